@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -26,6 +27,55 @@ const (
 	SREAccessRoleName = "RH-SRE-CCS-Access"
 	// AccountFinalizer is the string finalizer name
 	AccountFinalizer = "finalizer.aws.managed.openshift.io"
+	// AllowClaimedDeletionAnnotation can be set to "true" on an Account CR to let it be deleted
+	// while it is still claimed by an AccountClaim
+	AllowClaimedDeletionAnnotation = "aws.managed.openshift.io/allow-claimed-deletion"
+	// RegenerateConsoleURLAnnotation can be set on an Account or AWSFederatedAccountAccess CR to have its console URL regenerated.
+	// The operator removes it once the URL is regenerated.
+	RegenerateConsoleURLAnnotation = "aws.managed.openshift.io/regenerate-console-url"
+	// RequestedByAnnotation can be set on an Account CR to the user requesting SRE access to it. SRE
+	// access sessions carry it as their source identity.
+	RequestedByAnnotation = "aws.managed.openshift.io/requested-by"
+	// TicketAnnotation can be set on an Account CR to the ticket SRE access to it is requested for.
+	// SRE access sessions carry it as a session tag.
+	TicketAnnotation = "aws.managed.openshift.io/ticket"
+	// ConsoleURLExpiresAtAnnotation is set on the SRE console URL secret to the time the sign-in URL expires
+	ConsoleURLExpiresAtAnnotation = "aws.managed.openshift.io/url-expires-at"
+	// ConsoleSessionExpiresAtAnnotation is set on the SRE console URL secret to the time the console
+	// session opened with the URL expires
+	ConsoleSessionExpiresAtAnnotation = "aws.managed.openshift.io/session-expires-at"
+	// BudgetAlarmAnnotation is set on an Account CR to the last budget or billing alarm AWS notified for its
+	// account, and when it was received
+	BudgetAlarmAnnotation = "aws.managed.openshift.io/budget-alarm"
+	// ClusterDeploymentAnnotation is set on the secrets of a claim linked to a Hive ClusterDeployment to its
+	// namespace/name
+	ClusterDeploymentAnnotation = "aws.managed.openshift.io/cluster-deployment"
+	// ClusterNameAnnotation is set on the secrets of a claim linked to a Hive ClusterDeployment to the name of the cluster
+	ClusterNameAnnotation = "aws.managed.openshift.io/cluster-name"
+	// ClusterInfraIDAnnotation is set on the secrets of a claim linked to a Hive ClusterDeployment to the infra ID
+	// of the cluster
+	ClusterInfraIDAnnotation = "aws.managed.openshift.io/cluster-infra-id"
+	// CleanupReportAnnotation is set on an Account CR to the summary of the report of its last cleanup, with the
+	// name of the ConfigMap holding the report
+	CleanupReportAnnotation = "aws.managed.openshift.io/cleanup-report"
+	// OnDemandClaimAnnotation is set on an Account CR created on demand for a claim, when the pool had no account
+	// for it, to the namespace/name of the claim
+	OnDemandClaimAnnotation = "aws.managed.openshift.io/on-demand-claim"
+	// RepoolAnnotation can be set on an Account CR, e.g. with `aao accounts repool`, to put it back into the pool after
+	// a manual cleanup, to the reason it's put back. The operator removes it once it's handled.
+	RepoolAnnotation = "aws.managed.openshift.io/repool"
+	// CleanupAnnotation can be set on an unclaimed Account CR, e.g. with `aao accounts cleanup`, to run the reuse
+	// cleanup against it, to the region to clean up or empty for the default region. The operator removes it once
+	// the AccountCleanup is created.
+	CleanupAnnotation = "aws.managed.openshift.io/cleanup"
+	// RebuildIAMAnnotation can be set on a Ready or Failed Account CR, e.g. with `aao accounts rebuild-iam`, to run its
+	// IAM setup again after it was tampered with or only partly done, to the reason it's rebuilt. The operator removes
+	// it once it's handled.
+	RebuildIAMAnnotation = "aws.managed.openshift.io/rebuild-iam"
+	// ReassignLegalEntityAnnotation can be set on an unclaimed Account CR, e.g. with `aao accounts reassign-legal-entity`,
+	// to move it to another legal entity, to `<legal entity ID>` or `<legal entity ID>/<legal entity name>`. The
+	// operator removes it once it's handled.
+	ReassignLegalEntityAnnotation = "aws.managed.openshift.io/reassign-legal-entity"
 )
 
 // AccountSpec defines the desired state of Account
@@ -37,7 +87,14 @@ type AccountSpec struct {
 	// +optional
 	ClaimLink string `json:"claimLink"`
 	// +optional
-	ClaimLinkNamespace    string                `json:"claimLinkNamespace,omitempty"`
+	ClaimLinkNamespace string `json:"claimLinkNamespace,omitempty"`
+	// ClaimLinkUID is the UID of the AccountClaim the account is claimed by
+	// +optional
+	ClaimLinkUID string `json:"claimLinkUID,omitempty"`
+	// ClusterID is the ID of the cluster the account is claimed for, taken from the AccountClaim's
+	// api.openshift.com/id label
+	// +optional
+	ClusterID             string                `json:"clusterID,omitempty"`
 	LegalEntity           LegalEntity           `json:"legalEntity,omitempty"`
 	ManualSTSMode         bool                  `json:"manualSTSMode,omitempty"`
 	AccountPool           string                `json:"accountPool,omitempty"`
@@ -73,6 +130,7 @@ const (
 	EC2VPCElasticIPsQuotaCode SupportedServiceQuotas = "L-0263D0A3" // EC2-VPC Elastic IPs
 	VPCNetworkAclQuotaCode    SupportedServiceQuotas = "L-2AEEBF1A" // VPC-Network ACL
 	GeneralPurposeSSD         SupportedServiceQuotas = "L-7A658B76" // General Purpose SSD (gp3) volumes
+	VPCsPerRegionQuotaCode    SupportedServiceQuotas = "L-F678F1CE" // VPCs per Region
 )
 
 type SupportedServiceQuotaServices string
@@ -113,6 +171,152 @@ type AccountStatus struct {
 	Reused                   bool                  `json:"reused,omitempty"`
 	RegionalServiceQuotas    RegionalServiceQuotas `json:"regionalServiceQuotas,omitempty"`
 	OptInRegions             OptInRegions          `json:"optInRegions,omitempty"`
+	// ObservedGeneration is the most recent generation of the Account that was reconciled without error
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ReconcileFailures counts the consecutive failed reconciles of the Account
+	// +optional
+	ReconcileFailures int `json:"reconcileFailures,omitempty"`
+	// StateTransition records when the Account entered its current state
+	// +optional
+	StateTransition *StateTransition `json:"stateTransition,omitempty"`
+	// AccessKeyCreationTime is when the access key in the IAM user secret was created
+	// +optional
+	AccessKeyCreationTime *metav1.Time `json:"accessKeyCreationTime,omitempty"`
+	// OperatorResources are the resources the operator created for a CCS account
+	// +optional
+	OperatorResources *OperatorResources `json:"operatorResources,omitempty"`
+	// IAMUserName is the name of the IAM user created for the cluster
+	// +optional
+	IAMUserName string `json:"iamUserName,omitempty"`
+	// Phase is the account creation phase the Account is in
+	// +optional
+	Phase *PhaseStatus `json:"phase,omitempty"`
+	// RegionInitialization is the outcome of the initialization of each region of the account
+	// +optional
+	RegionInitialization []RegionInitializationStatus `json:"regionInitialization,omitempty"`
+	// SupportCase tracks the support case enrolling the account into Enterprise Support
+	// +optional
+	SupportCase *SupportCaseStatus `json:"supportCase,omitempty"`
+	// ClusterDeployment is the Hive ClusterDeployment of the cluster running in the claimed account
+	// +optional
+	ClusterDeployment *ClusterDeploymentStatus `json:"clusterDeployment,omitempty"`
+	// ServiceControlPolicies are the IDs of the service control policies attached directly to the AWS account when
+	// the operator last attached or detached the parking SCP
+	// +optional
+	ServiceControlPolicies []string `json:"serviceControlPolicies,omitempty"`
+}
+
+// ClusterDeploymentStatus is the metadata of the Hive ClusterDeployment linked to the claim of an account
+type ClusterDeploymentStatus struct {
+	// Name is the name of the ClusterDeployment
+	Name string `json:"name"`
+	// Namespace is the namespace of the ClusterDeployment
+	Namespace string `json:"namespace"`
+	// ClusterName is the name of the cluster
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+	// ClusterID is the ID of the installed cluster
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
+	// InfraID is the prefix of the names and tags of the AWS resources of the cluster
+	// +optional
+	InfraID string `json:"infraID,omitempty"`
+	// Region is the AWS region the cluster runs in
+	// +optional
+	Region string `json:"region,omitempty"`
+	// APIURL is the URL of the API server of the cluster
+	// +optional
+	APIURL string `json:"apiURL,omitempty"`
+	// DeletionTimestamp is when the ClusterDeployment was seen deleted
+	// +optional
+	DeletionTimestamp *metav1.Time `json:"deletionTimestamp,omitempty"`
+}
+
+// SupportCaseStatus is the state of the support case filed to attach the support plan of the payer account
+// to a new member account
+type SupportCaseStatus struct {
+	// ID is the ID of the support case
+	ID string `json:"id"`
+	// Status is the status of the case reported by the AWS Support API, e.g. opened or resolved
+	// +optional
+	Status string `json:"status,omitempty"`
+	// CreationTime is when the case was filed
+	// +optional
+	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+	// ResolutionTime is when the case was first seen resolved
+	// +optional
+	ResolutionTime *metav1.Time `json:"resolutionTime,omitempty"`
+}
+
+// RegionInitializationStatus is the outcome of the initialization of a region of an account
+type RegionInitializationStatus struct {
+	// Region is the name of the region
+	Region string `json:"region"`
+	// Initialized is true if an instance was created and terminated in the region
+	Initialized bool `json:"initialized"`
+	// Message describes the outcome of the initialization
+	// +optional
+	Message string `json:"message,omitempty"`
+	// DurationSeconds is how long the initialization of the region took
+	// +optional
+	DurationSeconds int64 `json:"durationSeconds,omitempty"`
+}
+
+// AccountPhase is a step of the account creation. Unlike the state, the phase is only advanced once the
+// previous step completed, so a restarted operator resumes the creation where it stopped.
+type AccountPhase string
+
+const (
+	// AccountPhaseCreating is the creation of the AWS account through Organizations
+	AccountPhaseCreating AccountPhase = "Creating"
+	// AccountPhasePendingVerification is the wait for the limits increase and Enterprise Support case
+	AccountPhasePendingVerification AccountPhase = "PendingVerification"
+	// AccountPhaseIAMSetup is the creation of the admin access role and of the IAM user for the cluster
+	AccountPhaseIAMSetup AccountPhase = "IAMSetup"
+	// AccountPhaseRegionInit is the initialization of the regions of the account
+	AccountPhaseRegionInit AccountPhase = "RegionInit"
+	// AccountPhaseReady means the account creation is complete
+	AccountPhaseReady AccountPhase = "Ready"
+)
+
+// PhaseStatus records the account creation phase an Account is in and the failed attempts at completing it
+type PhaseStatus struct {
+	// Phase is the account creation phase the Account is in
+	Phase AccountPhase `json:"phase"`
+	// Since is when the Account entered the phase
+	Since metav1.Time `json:"since"`
+	// Attempts counts the consecutive failed attempts at completing the phase
+	// +optional
+	Attempts int `json:"attempts,omitempty"`
+	// LastError is the error of the last failed attempt
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorCategory is the category of the AWS error of the last failed attempt
+	// +optional
+	LastErrorCategory AWSErrorCategory `json:"lastErrorCategory,omitempty"`
+	// NextAttemptTime is when the phase is attempted again after a failure
+	// +optional
+	NextAttemptTime *metav1.Time `json:"nextAttemptTime,omitempty"`
+}
+
+// OperatorResources lists the resources the operator created for a CCS account. They're the only
+// resources removed when the account is cleaned up, the rest of the account belongs to the customer.
+type OperatorResources struct {
+	// IAMUsers are the names of the IAM users created in the account
+	// +optional
+	IAMUsers []string `json:"iamUsers,omitempty"`
+	// IAMRoles are the names of the IAM roles created in the account
+	// +optional
+	IAMRoles []string `json:"iamRoles,omitempty"`
+	// Secrets are the namespace/name of the secrets created for the account
+	// +optional
+	Secrets []string `json:"secrets,omitempty"`
+}
+
+// IsEmpty returns true if no resource is recorded
+func (o *OperatorResources) IsEmpty() bool {
+	return o == nil || len(o.IAMUsers)+len(o.IAMRoles)+len(o.Secrets) == 0
 }
 
 // AccountCondition contains details for the current condition of a AWS account
@@ -175,6 +379,49 @@ const (
 	AccountOptingInRegions AccountConditionType = "OptingInRegions"
 	// AccountOptInRegionEnabled indicates that supported Opt-In regions have been enabled
 	AccountOptInRegionEnabled AccountConditionType = "OptInRegionsEnabled"
+	// AccountStuck indicates the Account has been in its current state for longer than expected
+	AccountStuck AccountConditionType = "Stuck"
+	// AccountDegraded indicates the IAM principals of the Account have drifted from what the operator
+	// expects and couldn't be repaired automatically, or that AWS Health reports the AWS account as
+	// suspended, under abuse review or affected by an open operational issue
+	AccountDegraded AccountConditionType = "Degraded"
+	// AccountPasswordPolicyConfigured indicates the IAM password policy of the Account has been configured
+	AccountPasswordPolicyConfigured AccountConditionType = "PasswordPolicyConfigured"
+	// AccountRootAccessKeysPresent indicates the root user of the Account has access keys
+	AccountRootAccessKeysPresent AccountConditionType = "RootAccessKeysPresent"
+	// AccountS3PublicAccessBlocked indicates the account level S3 Public Access Block is enabled
+	AccountS3PublicAccessBlocked AccountConditionType = "S3PublicAccessBlocked"
+	// AccountEBSEncryptionByDefault indicates EBS encryption by default is enabled in every initialized region
+	AccountEBSEncryptionByDefault AccountConditionType = "EBSEncryptionByDefault"
+	// AccountDefaultVPCDeleted indicates the default VPC has been deleted in every initialized region
+	AccountDefaultVPCDeleted AccountConditionType = "DefaultVPCDeleted"
+	// AccountRetired is set when the AccountClaim of a CCS account is deleted and the Account CR is kept as a
+	// record of the cleanup
+	AccountRetired AccountConditionType = "Retired"
+	// AccountNotInOrganization indicates the AWS account of the Account isn't an active member of the organization
+	AccountNotInOrganization AccountConditionType = "NotInOrganization"
+	// AccountHibernated indicates the billable resources left in the Ready unclaimed Account have been removed
+	// to reduce the cost of the idle pool
+	AccountHibernated AccountConditionType = "Hibernated"
+	// AccountCostAnomaly indicates AWS notified a budget threshold of the account was exceeded
+	AccountCostAnomaly AccountConditionType = "CostAnomaly"
+	// AccountIdleCost indicates Cost Explorer reports a Ready unclaimed account incurring costs, which usually means
+	// its cleanup left resources behind
+	AccountIdleCost AccountConditionType = "IdleCost"
+	// AccountPreflightFailed indicates the account failed the preflight checks run before it joins the pool, and
+	// lists the failures
+	AccountPreflightFailed AccountConditionType = "PreflightFailed"
+	// AccountCloudTrailBroken indicates the organization trail doesn't cover the account or isn't delivering its logs
+	AccountCloudTrailBroken AccountConditionType = "CloudTrailBroken"
+	// AccountGuardDutyEnrolled indicates the account is a GuardDuty member of the delegated administrator account of
+	// the organization for its claim
+	AccountGuardDutyEnrolled AccountConditionType = "GuardDutyEnrolled"
+	// AccountIAMUserMissing indicates the osdManagedAdmin IAM user of the account is gone, so its access key can't be
+	// rotated
+	AccountIAMUserMissing AccountConditionType = "IAMUserMissing"
+	// AccountManualActionRequired indicates the cleanup of the account found resources it isn't allowed to delete,
+	// like S3 buckets with object lock, and lists them. They have to be removed by hand before the cleanup is rerun.
+	AccountManualActionRequired AccountConditionType = "ManualActionRequired"
 )
 
 // +genclient
@@ -365,11 +612,82 @@ func (a *Account) IsPendingDeletion() bool {
 	return a.DeletionTimestamp != nil
 }
 
+// AllowsClaimedDeletion returns true if the account carries the override annotation permitting
+// deletion while it is still claimed
+func (a *Account) AllowsClaimedDeletion() bool {
+	return a.GetAnnotations()[AllowClaimedDeletionAnnotation] == "true"
+}
+
 // IsBYOC returns true if account is a BYOC account
 func (a *Account) IsBYOC() bool {
 	return a.Spec.BYOC
 }
 
+// IsRetired returns true if the account is a retired CCS account
+func (a *Account) IsRetired() bool {
+	return a.Status.State == string(AccountRetired)
+}
+
+// AccountAWSHealthIssueReason is the reason of the Degraded condition of accounts AWS Health reports
+// problems for
+const AccountAWSHealthIssueReason = "AWSHealthIssue"
+
+// AccountBudgetExceededReason is the reason of the CostAnomaly condition of accounts whose budget alarmed
+const AccountBudgetExceededReason = "BudgetThresholdExceeded"
+
+// HasAWSHealthIssues returns true if AWS Health reports the account as suspended, under abuse review or
+// affected by an open operational issue
+func (a *Account) HasAWSHealthIssues() bool {
+	cond := a.GetCondition(AccountDegraded)
+	return cond != nil && cond.Status == corev1.ConditionTrue && cond.Reason == AccountAWSHealthIssueReason
+}
+
+// AccountHibernatingReason is the reason of the Hibernated condition of accounts whose billable resources are being
+// removed
+const AccountHibernatingReason = "Hibernating"
+
+// IsHibernating returns true while the billable resources of the account are being removed. The account isn't handed
+// out to claims meanwhile.
+func (a *Account) IsHibernating() bool {
+	cond := a.GetCondition(AccountHibernated)
+	return cond != nil && cond.Status == corev1.ConditionFalse && cond.Reason == AccountHibernatingReason
+}
+
+// RecordOperatorResources adds the resources to the ones the operator created for the account.
+// Returns true if any of them wasn't recorded yet.
+func (a *Account) RecordOperatorResources(resources OperatorResources) bool {
+	if a.Status.OperatorResources == nil {
+		a.Status.OperatorResources = &OperatorResources{}
+	}
+	recorded := a.Status.OperatorResources
+	changed := false
+	for _, list := range []struct {
+		recorded *[]string
+		names    []string
+	}{
+		{&recorded.IAMUsers, resources.IAMUsers},
+		{&recorded.IAMRoles, resources.IAMRoles},
+		{&recorded.Secrets, resources.Secrets},
+	} {
+		for _, name := range list.names {
+			if !containsString(*list.recorded, name) {
+				*list.recorded = append(*list.recorded, name)
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 // HasAwsAccountID returns true if awsAccountID is set
 func (a *Account) HasAwsAccountID() bool {
 	return a.Spec.AwsAccountID != ""
@@ -477,6 +795,11 @@ func (a *Account) HasNeverBeenClaimed() bool {
 	return !a.Status.Claimed && a.Spec.LegalEntity.ID == ""
 }
 
+// IsCreatedOnDemand returns true if the account was created on demand for a claim rather than to fill its pool
+func (a *Account) IsCreatedOnDemand() bool {
+	return a.Annotations[OnDemandClaimAnnotation] != ""
+}
+
 // IsOwnedByAccountPool returns true if the account has an ownerreference type that is the accountpool or if the accountpool is defined in the account spec
 func (a *Account) IsOwnedByAccountPool() bool {
 	if a.ObjectMeta.OwnerReferences == nil {
@@ -502,6 +825,84 @@ func (a *Account) GetAssumeRole() string {
 	return AccountOperatorIAMRole
 }
 
+// GetPhase returns the account creation phase the Account is in, or an empty phase if none was recorded
+func (a *Account) GetPhase() AccountPhase {
+	if a.Status.Phase == nil {
+		return ""
+	}
+	return a.Status.Phase.Phase
+}
+
+// EnterPhase moves the Account to the given account creation phase, forgetting the failed attempts of
+// the previous one. Returns true if the phase changed.
+func (a *Account) EnterPhase(phase AccountPhase) bool {
+	if a.GetPhase() == phase {
+		return false
+	}
+	a.Status.Phase = &PhaseStatus{Phase: phase, Since: metav1.Now()}
+	return true
+}
+
+// RecordPhaseFailure records a failed attempt at completing the current phase, to be retried after
+// backoff
+func (a *Account) RecordPhaseFailure(err error, backoff time.Duration) {
+	if a.Status.Phase == nil {
+		return
+	}
+	next := metav1.NewTime(time.Now().Add(backoff))
+	a.Status.Phase.Attempts++
+	a.Status.Phase.LastError = err.Error()
+	a.Status.Phase.LastErrorCategory = GetAWSErrorCategory(err)
+	a.Status.Phase.NextAttemptTime = &next
+}
+
+// PhaseRetryDelay returns how long until the current phase may be attempted again after a failure
+func (a *Account) PhaseRetryDelay() time.Duration {
+	if a.Status.Phase == nil || a.Status.Phase.NextAttemptTime == nil {
+		return 0
+	}
+	delay := time.Until(a.Status.Phase.NextAttemptTime.Time)
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// GetIAMUserName returns the name of the IAM user created for the cluster. Accounts whose IAM user was
+// created before the name was recorded use the name derived from their IAMUserIDLabel.
+func (a *Account) GetIAMUserName() string {
+	if a.Status.IAMUserName != "" {
+		return a.Status.IAMUserName
+	}
+	return fmt.Sprintf("%s-%s", IAMUserNamePrefix, a.Labels[IAMUserIDLabel])
+}
+
+// GetReconcileFailures returns the number of consecutive failed reconciles of the Account
+func (a *Account) GetReconcileFailures() int {
+	return a.Status.ReconcileFailures
+}
+
+// RecordReconcileOutcome bumps the consecutive failure counter for a failed reconcile, or resets it and
+// records the observed generation for a successful one. It also records when the Account entered its
+// current state. Returns true if the status changed.
+func (a *Account) RecordReconcileOutcome(failed bool) bool {
+	changed := false
+	if a.Status.State != "" && !a.Status.StateTransition.IsFor(a.Status.State) {
+		a.Status.StateTransition = NewStateTransition(a.Status.State)
+		changed = true
+	}
+	if failed {
+		a.Status.ReconcileFailures++
+		return true
+	}
+	if a.Status.ReconcileFailures == 0 && a.Status.ObservedGeneration == a.Generation {
+		return changed
+	}
+	a.Status.ReconcileFailures = 0
+	a.Status.ObservedGeneration = a.Generation
+	return true
+}
+
 // GetCondition finds the condition that has the
 // specified condition type in the given list. If none exists, then returns nil.
 func (a *Account) GetCondition(conditionType AccountConditionType) *AccountCondition {
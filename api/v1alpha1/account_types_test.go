@@ -180,3 +180,106 @@ func TestAccount_AllRegionsExistInOptInRegions(t *testing.T) {
 		})
 	}
 }
+
+func TestAccount_RecordReconcileOutcome(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         AccountStatus
+		failed         bool
+		want           bool
+		wantFailures   int
+		wantGeneration int64
+	}{
+		{
+			name:           "Failure increments the counter",
+			status:         AccountStatus{ObservedGeneration: 1, ReconcileFailures: 2},
+			failed:         true,
+			want:           true,
+			wantFailures:   3,
+			wantGeneration: 1,
+		},
+		{
+			name:           "Success resets the counter and observes the generation",
+			status:         AccountStatus{ObservedGeneration: 1, ReconcileFailures: 2},
+			failed:         false,
+			want:           true,
+			wantFailures:   0,
+			wantGeneration: 2,
+		},
+		{
+			name:           "Success on an already observed generation changes nothing",
+			status:         AccountStatus{ObservedGeneration: 2},
+			failed:         false,
+			want:           false,
+			wantFailures:   0,
+			wantGeneration: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Account{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status:     tt.status,
+			}
+			if got := a.RecordReconcileOutcome(tt.failed); got != tt.want {
+				t.Errorf("RecordReconcileOutcome() = %v, want %v", got, tt.want)
+			}
+			if a.Status.ReconcileFailures != tt.wantFailures {
+				t.Errorf("ReconcileFailures = %v, want %v", a.Status.ReconcileFailures, tt.wantFailures)
+			}
+			if a.Status.ObservedGeneration != tt.wantGeneration {
+				t.Errorf("ObservedGeneration = %v, want %v", a.Status.ObservedGeneration, tt.wantGeneration)
+			}
+		})
+	}
+}
+
+func TestAccount_RecordOperatorResources(t *testing.T) {
+	a := &Account{}
+	if !a.Status.OperatorResources.IsEmpty() {
+		t.Errorf("IsEmpty() = false before anything was recorded")
+	}
+	if got := a.RecordOperatorResources(OperatorResources{IAMUsers: []string{"osdManagedAdmin-abcdef"}, Secrets: []string{"ns/secret"}}); !got {
+		t.Errorf("RecordOperatorResources() = %v, want true", got)
+	}
+	if got := a.RecordOperatorResources(OperatorResources{IAMUsers: []string{"osdManagedAdmin-abcdef"}}); got {
+		t.Errorf("RecordOperatorResources() of an already recorded user = %v, want false", got)
+	}
+	if got := a.RecordOperatorResources(OperatorResources{IAMRoles: []string{"ManagedOpenShift-Support-abcdef"}}); !got {
+		t.Errorf("RecordOperatorResources() = %v, want true", got)
+	}
+	recorded := a.Status.OperatorResources
+	if len(recorded.IAMUsers) != 1 || len(recorded.IAMRoles) != 1 || len(recorded.Secrets) != 1 {
+		t.Errorf("OperatorResources = %+v, want one user, role and secret", recorded)
+	}
+}
+
+func TestAccount_GetIAMUserName(t *testing.T) {
+	tests := []struct {
+		name   string
+		status AccountStatus
+		want   string
+	}{
+		{
+			name:   "Derived from the IAM user ID label when not recorded",
+			status: AccountStatus{},
+			want:   "osdManagedAdmin-abcdef",
+		},
+		{
+			name:   "Recorded name is used",
+			status: AccountStatus{IAMUserName: "osdManagedAdmin-123456"},
+			want:   "osdManagedAdmin-123456",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Account{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{IAMUserIDLabel: "abcdef"}},
+				Status:     tt.status,
+			}
+			if got := a.GetIAMUserName(); got != tt.want {
+				t.Errorf("GetIAMUserName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package v1alpha1
 
 import (
+	"reflect"
 	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestValidate(t *testing.T) {
@@ -80,6 +83,55 @@ func TestValidate(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "Testing STS Valid Roles",
+			accountClaim: &AccountClaim{
+				Spec: AccountClaimSpec{
+					ManualSTSMode: true,
+					STSRoleARN:    "arn:aws:whatever:something:role/whomever",
+					STSRoles: []STSRole{
+						{Name: "installer", TrustedARNs: []string{"arn:aws:iam::123456789012:role/ocm"}},
+					},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Testing STS Role Without Trusted ARNs",
+			accountClaim: &AccountClaim{
+				Spec: AccountClaimSpec{
+					ManualSTSMode: true,
+					STSRoleARN:    "arn:aws:whatever:something:role/whomever",
+					STSRoles:      []STSRole{{Name: "installer"}},
+				},
+			},
+			expectedErr: ErrSTSRoleInvalid,
+		},
+		{
+			name: "Testing STS Duplicate Roles",
+			accountClaim: &AccountClaim{
+				Spec: AccountClaimSpec{
+					ManualSTSMode: true,
+					STSRoleARN:    "arn:aws:whatever:something:role/whomever",
+					STSRoles: []STSRole{
+						{Name: "installer", TrustedARNs: []string{"arn:aws:iam::123456789012:role/ocm"}},
+						{Name: "installer", TrustedARNs: []string{"arn:aws:iam::123456789012:role/other"}},
+					},
+				},
+			},
+			expectedErr: ErrSTSRoleInvalid,
+		},
+		{
+			name: "Testing STS Roles Without STS Mode",
+			accountClaim: &AccountClaim{
+				Spec: AccountClaimSpec{
+					STSRoles: []STSRole{
+						{Name: "installer", TrustedARNs: []string{"arn:aws:iam::123456789012:role/ocm"}},
+					},
+				},
+			},
+			expectedErr: ErrSTSRolesWithoutSTSMode,
+		},
 		{
 			name: "Testing non-ccs Valid",
 			accountClaim: &AccountClaim{
@@ -87,6 +139,34 @@ func TestValidate(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "Testing Valid Regions",
+			accountClaim: &AccountClaim{
+				Spec: AccountClaimSpec{
+					Aws: Aws{Regions: []AwsRegions{{Name: "us-east-1"}, {Name: "eu-west-1"}, {Name: "us-gov-west-1"}}},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Testing Unsupported Region",
+			accountClaim: &AccountClaim{
+				Spec: AccountClaimSpec{
+					Aws: Aws{Regions: []AwsRegions{{Name: "us-east-1"}, {Name: "moon-south-1"}}},
+				},
+			},
+			expectedErr: ErrRegionUnsupported,
+		},
+		{
+			name: "Testing Duplicate Region",
+			accountClaim: &AccountClaim{
+				Spec: AccountClaimSpec{
+					BYOC: true,
+					Aws:  Aws{Regions: []AwsRegions{{Name: "us-east-1"}, {Name: "us-east-1"}}},
+				},
+			},
+			expectedErr: ErrRegionDuplicate,
+		},
 	}
 
 	for _, test := range tests {
@@ -99,3 +179,29 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCleanupExclusions(t *testing.T) {
+	accountClaim := &AccountClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				CleanupExclusionsAnnotation: "route53, arn:aws:ec2:us-east-1:123456789012:volume/vol-0123456789abcdef0,",
+			},
+		},
+		Spec: AccountClaimSpec{
+			CleanupExclusions: &CleanupExclusions{
+				ResourceARNs: []string{"arn:aws:s3:::forensics"},
+			},
+		},
+	}
+
+	expected := CleanupExclusions{
+		Steps:        []string{"route53"},
+		ResourceARNs: []string{"arn:aws:s3:::forensics", "arn:aws:ec2:us-east-1:123456789012:volume/vol-0123456789abcdef0"},
+	}
+	if exclusions := accountClaim.GetCleanupExclusions(); !reflect.DeepEqual(exclusions, expected) {
+		t.Errorf("got %v, wanted %v", exclusions, expected)
+	}
+	if (&AccountClaim{}).GetCleanupExclusions().IsEmpty() != true {
+		t.Error("expected no exclusions without spec.cleanupExclusions or annotation")
+	}
+}
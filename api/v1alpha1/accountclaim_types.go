@@ -2,11 +2,17 @@ package v1alpha1
 
 import (
 	"errors"
+	"strings"
 
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// TransferredFromAnnotation is set on an AccountClaim created by the transfer of another one to its
+// namespace/name
+const TransferredFromAnnotation = "aws.managed.openshift.io/transferred-from"
+
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
@@ -24,11 +30,86 @@ type AccountClaimSpec struct {
 	ManualSTSMode       bool               `json:"manualSTSMode,omitempty"`
 	STSRoleARN          string             `json:"stsRoleARN,omitempty"`
 	STSExternalID       string             `json:"stsExternalID,omitempty"`
+	STSRoles            []STSRole          `json:"stsRoles,omitempty"`
 	SupportRoleARN      string             `json:"supportRoleARN,omitempty"`
 	CustomTags          string             `json:"customTags,omitempty"`
 	KmsKeyId            string             `json:"kmsKeyId,omitempty"`
 	AccountPool         string             `json:"accountPool,omitempty"`
 	FleetManagerConfig  FleetManagerConfig `json:"fleetManagerConfig,omitempty"` // FleetmanagerConfig is exclusively designed for use by the fleet manager
+	// ClusterDeploymentRef is the Hive ClusterDeployment of the cluster the claim is for. Its metadata is recorded
+	// on the claimed account and on the secrets of the claim, and its deletion is recorded on the account.
+	// +optional
+	ClusterDeploymentRef *ClusterDeploymentRef `json:"clusterDeploymentRef,omitempty"`
+	// CleanupExclusions are the cleanup steps and resources left in place when the account is cleaned up after the
+	// claim is deleted, e.g. to keep evidence for an investigation
+	// +optional
+	CleanupExclusions *CleanupExclusions `json:"cleanupExclusions,omitempty"`
+	// ClaimDeadline is how long after its creation the claim may wait for an account from the pool before it's
+	// set TimedOut, e.g. 30m. Defaults to the accountclaim-deadline of the operator configmap, if any.
+	// +optional
+	ClaimDeadline *metav1.Duration `json:"claimDeadline,omitempty"`
+	// TTL is how long after its creation the claim is deleted by the operator, and its account cleaned up and
+	// returned to the pool like for any deleted claim, e.g. 12h for the claims of CI clusters
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+	// Fake claims go through the claim lifecycle, with their secret, states and finalizer, without being bound to
+	// an account or making any AWS call, for e2e tests and staging environments
+	// +optional
+	Fake bool `json:"fake,omitempty"`
+	// Route53Delegation is the delegation of the subdomain of the cluster from a shared parent hosted zone in
+	// another account. Its records are removed from the parent zone when the account is cleaned up.
+	// +optional
+	Route53Delegation *Route53Delegation `json:"route53Delegation,omitempty"`
+	// TransferTo moves the claim, with its account and secrets, to another namespace or name, e.g. when the
+	// namespace of the cluster is recreated by Hive. The claim is recreated there and this one is deleted without its
+	// account being cleaned up.
+	// +optional
+	TransferTo *ClaimTransfer `json:"transferTo,omitempty"`
+}
+
+// ClaimTransfer is where an AccountClaim is moved to
+type ClaimTransfer struct {
+	// Namespace is the namespace the claim is moved to, it has to exist
+	Namespace string `json:"namespace"`
+	// Name is the name of the claim in its new namespace, the current one if empty
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// Route53Delegation is a subdomain delegated from a parent hosted zone in another account, the DNS account
+type Route53Delegation struct {
+	// ParentHostedZoneID is the ID of the parent hosted zone holding the NS records of the delegation
+	ParentHostedZoneID string `json:"parentHostedZoneID"`
+	// Subdomain is the delegated subdomain, e.g. mycluster.example.com
+	Subdomain string `json:"subdomain"`
+	// RoleARN is the IAM role of the DNS account assumed to remove the delegation
+	RoleARN string `json:"roleARN"`
+	// ExternalID is the external ID required to assume RoleARN, if any
+	// +optional
+	ExternalID string `json:"externalID,omitempty"`
+}
+
+// CleanupExclusions are cleanup steps and AWS resources excluded from the cleanup of an account
+type CleanupExclusions struct {
+	// Steps are the cleanup steps not to run: snapshots, ebs_volumes, s3, vpc_endpoint_service_configurations,
+	// route53, route53_delegation or iam_users
+	// +optional
+	Steps []string `json:"steps,omitempty"`
+	// ResourceARNs are the ARNs of the EBS snapshots and volumes, S3 buckets, VPC endpoint services, Route53 hosted
+	// zones and IAM users to keep
+	// +optional
+	ResourceARNs []string `json:"resourceARNs,omitempty"`
+}
+
+// IsEmpty returns true if nothing is excluded from the cleanup
+func (e CleanupExclusions) IsEmpty() bool {
+	return len(e.Steps) == 0 && len(e.ResourceARNs) == 0
+}
+
+// ClusterDeploymentRef references a Hive ClusterDeployment
+type ClusterDeploymentRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
 }
 
 // AccountClaimStatus defines the observed state of AccountClaim
@@ -39,6 +120,85 @@ type AccountClaimStatus struct {
 	Conditions []AccountClaimCondition `json:"conditions"`
 
 	State ClaimStatus `json:"state"`
+
+	// ObservedGeneration is the most recent generation of the AccountClaim that was reconciled without error
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ReconcileFailures counts the consecutive failed reconciles of the AccountClaim
+	// +optional
+	ReconcileFailures int `json:"reconcileFailures,omitempty"`
+	// StateTransition records when the AccountClaim entered its current state
+	// +optional
+	StateTransition *StateTransition `json:"stateTransition,omitempty"`
+	// STSRoles are the roles provisioned for the spec.stsRoles of the AccountClaim
+	// +optional
+	STSRoles []ProvisionedSTSRole `json:"stsRoles,omitempty"`
+	// IAMUserName is the name of the IAM user created for the cluster in the claimed account
+	// +optional
+	IAMUserName string `json:"iamUserName,omitempty"`
+	// IdentityCenterAssignments are the IAM Identity Center permission sets assigned in the claimed account
+	// +optional
+	IdentityCenterAssignments []IdentityCenterAssignment `json:"identityCenterAssignments,omitempty"`
+}
+
+// STSRole describes an IAM role to provision in the account of a manualSTSMode AccountClaim
+type STSRole struct {
+	// Name is the name of the IAM role
+	Name string `json:"name"`
+	// TrustedARNs are the principals allowed to assume the role
+	TrustedARNs []string `json:"trustedARNs"`
+	// PolicyARNs are the managed policies attached to the role
+	// +optional
+	PolicyARNs []string `json:"policyARNs,omitempty"`
+}
+
+// ProvisionedSTSRole is an IAM role provisioned for an AccountClaim
+type ProvisionedSTSRole struct {
+	// Name is the name of the IAM role
+	Name string `json:"name"`
+	// ARN is the ARN of the IAM role
+	ARN string `json:"arn"`
+}
+
+// IdentityCenterAssignment is an IAM Identity Center permission set assigned to a group or user in an account
+type IdentityCenterAssignment struct {
+	// PermissionSetARN is the ARN of the permission set
+	PermissionSetARN string `json:"permissionSetARN"`
+	// PrincipalType is the type of the principal, GROUP or USER
+	PrincipalType string `json:"principalType"`
+	// PrincipalID is the identity store ID of the group or user
+	PrincipalID string `json:"principalID"`
+}
+
+// GetCleanupExclusions returns the exclusions of spec.cleanupExclusions and of the cleanup exclusions annotation
+// of the claim
+func (a *AccountClaim) GetCleanupExclusions() CleanupExclusions {
+	exclusions := CleanupExclusions{}
+	if a.Spec.CleanupExclusions != nil {
+		exclusions.Steps = append(exclusions.Steps, a.Spec.CleanupExclusions.Steps...)
+		exclusions.ResourceARNs = append(exclusions.ResourceARNs, a.Spec.CleanupExclusions.ResourceARNs...)
+	}
+	for _, exclusion := range strings.Split(a.Annotations[CleanupExclusionsAnnotation], ",") {
+		exclusion = strings.TrimSpace(exclusion)
+		switch {
+		case exclusion == "":
+		case strings.HasPrefix(exclusion, "arn:"):
+			exclusions.ResourceARNs = append(exclusions.ResourceARNs, exclusion)
+		default:
+			exclusions.Steps = append(exclusions.Steps, exclusion)
+		}
+	}
+	return exclusions
+}
+
+// ProvisionedSTSRoleARN returns the ARN of the provisioned role with the given name, or an empty string
+func (s *AccountClaimStatus) ProvisionedSTSRoleARN(name string) string {
+	for _, role := range s.STSRoles {
+		if role.Name == name {
+			return role.ARN
+		}
+	}
+	return ""
 }
 
 // AccountClaimCondition contains details for the current condition of a AWS account claim
@@ -81,6 +241,12 @@ const (
 	InvalidAccountClaim AccountClaimConditionType = "InvalidAccountClaim"
 	// InternalError is set when a serious internal issue arrises
 	InternalError AccountClaimConditionType = "InternalError"
+	// ValidationFailed is set when the customer account of a CCS AccountClaim doesn't pass validation
+	ValidationFailed AccountClaimConditionType = "ValidationFailed"
+	// AccountClaimStuck indicates the AccountClaim has been in its current state for longer than expected
+	AccountClaimStuck AccountClaimConditionType = "Stuck"
+	// AccountClaimTimedOut is set when no account was bound to the AccountClaim within its deadline
+	AccountClaimTimedOut AccountClaimConditionType = "ClaimTimedOut"
 )
 
 // ClaimStatus is a valid value from AccountClaim.Status
@@ -166,8 +332,57 @@ var ErrBYOCSecretRefMissing = errors.New("BYOCSecretRefMissing")
 // ErrSTSRoleARNMissing is an error for missing STS Role ARN definition in the AccountClaim
 var ErrSTSRoleARNMissing = errors.New("STSRoleARNMissing")
 
+// ErrSTSRolesWithoutSTSMode is an error for STS roles defined in an AccountClaim not in manualSTSMode
+var ErrSTSRolesWithoutSTSMode = errors.New("STSRolesWithoutSTSMode")
+
+// ErrSTSRoleInvalid is an error for an STS role without a name or trusted ARNs, or defined twice
+var ErrSTSRoleInvalid = errors.New("STSRoleInvalid")
+
+// ErrRegionUnsupported is an error for an AccountClaim region that isn't an AWS region
+var ErrRegionUnsupported = errors.New("RegionUnsupported")
+
+// ErrRegionDuplicate is an error for a region listed twice in an AccountClaim
+var ErrRegionDuplicate = errors.New("RegionDuplicate")
+
+// ErrRegionNotEnabled is an error for AccountClaim regions that aren't enabled in the AWS account of the claim
+var ErrRegionNotEnabled = errors.New("RegionNotEnabled")
+
+// ErrBYOCAccountCleanUp is an error for an attempt to wipe the data of a customer owned account
+var ErrBYOCAccountCleanUp = errors.New("BYOCAccountCleanUp")
+
+// GetReconcileFailures returns the number of consecutive failed reconciles of the AccountClaim
+func (a *AccountClaim) GetReconcileFailures() int {
+	return a.Status.ReconcileFailures
+}
+
+// RecordReconcileOutcome bumps the consecutive failure counter for a failed reconcile, or resets it and
+// records the observed generation for a successful one. It also records when the AccountClaim entered
+// its current state. Returns true if the status changed.
+func (a *AccountClaim) RecordReconcileOutcome(failed bool) bool {
+	changed := false
+	if a.Status.State != "" && !a.Status.StateTransition.IsFor(string(a.Status.State)) {
+		a.Status.StateTransition = NewStateTransition(string(a.Status.State))
+		changed = true
+	}
+	if failed {
+		a.Status.ReconcileFailures++
+		return true
+	}
+	if a.Status.ReconcileFailures == 0 && a.Status.ObservedGeneration == a.Generation {
+		return changed
+	}
+	a.Status.ReconcileFailures = 0
+	a.Status.ObservedGeneration = a.Generation
+	return true
+}
+
 // Validates an AccountClaim object
 func (a *AccountClaim) Validate() error {
+	err := a.ValidateRegions()
+	if err != nil {
+		return err
+	}
+
 	// Validate STS mode first since we only require the
 	// .Spec.STSRoleARN field to be set
 	// By design STS doesn't have long lived credentials so they wont
@@ -176,12 +391,37 @@ func (a *AccountClaim) Validate() error {
 		return a.validateSTS()
 	}
 
+	if len(a.Spec.STSRoles) > 0 {
+		return ErrSTSRolesWithoutSTSMode
+	}
+
 	if a.Spec.BYOC {
 		return a.validateBYOC()
 	}
 
-	// we don't do any validation for non-ccs accounts currently, so
-	// let's keep that behavior
+	// the regions are the only validation for non-ccs accounts
+	return nil
+}
+
+// ValidateRegions checks that the regions of the AccountClaim are AWS regions, each listed once. Whether they're
+// enabled in the account is checked once the account is known.
+func (a *AccountClaim) ValidateRegions() error {
+	supported := map[string]bool{}
+	for _, partition := range endpoints.DefaultPartitions() {
+		for region := range partition.Regions() {
+			supported[region] = true
+		}
+	}
+	seen := map[string]bool{}
+	for _, region := range a.Spec.Aws.Regions {
+		if !supported[region.Name] {
+			return ErrRegionUnsupported
+		}
+		if seen[region.Name] {
+			return ErrRegionDuplicate
+		}
+		seen[region.Name] = true
+	}
 	return nil
 }
 
@@ -189,6 +429,13 @@ func (a *AccountClaim) validateSTS() error {
 	if a.Spec.STSRoleARN == "" {
 		return ErrSTSRoleARNMissing
 	}
+	names := map[string]bool{}
+	for _, role := range a.Spec.STSRoles {
+		if role.Name == "" || len(role.TrustedARNs) == 0 || names[role.Name] {
+			return ErrSTSRoleInvalid
+		}
+		names[role.Name] = true
+	}
 	return nil
 }
 
@@ -0,0 +1,145 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RerunCleanupAnnotation is the annotation requesting a finished AccountCleanup to be run again from the start
+const RerunCleanupAnnotation = "aws.managed.openshift.io/rerun-cleanup"
+
+// CleanupExclusionsAnnotation can be set on an AccountClaim to a comma-separated list of cleanup steps and resource
+// ARNs to leave in place when its account is cleaned up, in addition to spec.cleanupExclusions
+const CleanupExclusionsAnnotation = "aws.managed.openshift.io/cleanup-exclusions"
+
+// AccountCleanupSpec defines the cleanup of an account released by a deleted AccountClaim
+// +k8s:openapi-gen=true
+type AccountCleanupSpec struct {
+	// AccountLink is the name of the Account CR to clean up and return to the pool
+	AccountLink string `json:"accountLink"`
+	// AwsAccountID is the ID of the AWS account to clean up
+	// +optional
+	AwsAccountID string `json:"awsAccountID,omitempty"`
+	// ClaimName is the name of the deleted AccountClaim
+	ClaimName string `json:"claimName"`
+	// ClaimNamespace is the namespace of the deleted AccountClaim
+	ClaimNamespace string `json:"claimNamespace"`
+	// ClaimUID is the UID of the deleted AccountClaim
+	ClaimUID string `json:"claimUID"`
+	// Region is the region the cluster of the claim ran in, the S3 buckets are cleaned up from it
+	Region string `json:"region"`
+	// Regions are all the regions of the claim, Region first. The EC2 resources of the claim are cleaned up from
+	// each of them.
+	// +optional
+	Regions []string `json:"regions,omitempty"`
+	// LegalEntity of the claim, carried over to the accounts claimed before account reuse was introduced
+	// +optional
+	LegalEntity LegalEntity `json:"legalEntity,omitempty"`
+	// Steps are the cleanup steps to run, all of them when empty
+	// +optional
+	Steps []string `json:"steps,omitempty"`
+	// Exclusions are the cleanup steps and resources of the claim to leave in place. An account with excluded
+	// resources isn't returned to the pool, it's set to Failed once cleaned up.
+	// +optional
+	Exclusions CleanupExclusions `json:"exclusions,omitempty"`
+	// Route53Delegation is the delegation of the subdomain of the cluster of the claim, removed from the parent
+	// hosted zone by the route53_delegation step
+	// +optional
+	Route53Delegation *Route53Delegation `json:"route53Delegation,omitempty"`
+}
+
+// GetRegions returns the regions the EC2 resources of the claim are cleaned up from, Region first. The cleanups
+// queued before the claims recorded all their regions only have Region.
+func (s *AccountCleanupSpec) GetRegions() []string {
+	regions := []string{s.Region}
+	for _, region := range s.Regions {
+		if region != "" && region != s.Region && !containsString(regions, region) {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// AccountCleanupState is the state of an AccountCleanup
+type AccountCleanupState string
+
+const (
+	// AccountCleanupPending is the state of a cleanup that didn't start or is retried
+	AccountCleanupPending AccountCleanupState = "Pending"
+	// AccountCleanupInProgress is the state of a cleanup being run
+	AccountCleanupInProgress AccountCleanupState = "InProgress"
+	// AccountCleanupCompleted is the state of a cleanup whose account was returned to the pool
+	AccountCleanupCompleted AccountCleanupState = "Completed"
+	// AccountCleanupFailed is the state of a cleanup that failed too many times, its account is Failed
+	AccountCleanupFailed AccountCleanupState = "Failed"
+)
+
+// AccountCleanupStatus defines the observed state of AccountCleanup
+// +k8s:openapi-gen=true
+type AccountCleanupStatus struct {
+	// +optional
+	State AccountCleanupState `json:"state,omitempty"`
+	// CompletedSteps are the cleanup steps that completed, they aren't run again when the cleanup is retried
+	// +optional
+	CompletedSteps []string `json:"completedSteps,omitempty"`
+	// Attempts counts the runs of the cleanup that failed
+	// +optional
+	Attempts int `json:"attempts,omitempty"`
+	// LastError is the error of the last failed run
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorCategory is the category of the AWS error of the last failed run. The runs failing with the
+	// AccessDenied or NotFound categories aren't fixed by being retried. A run failing with ManualActionRequired
+	// isn't retried, the cleanup fails until it's rerun once the resources are removed by hand.
+	// +optional
+	LastErrorCategory AWSErrorCategory `json:"lastErrorCategory,omitempty"`
+	// Interrupted is true if the operator was shut down before the cleanup completed
+	// +optional
+	Interrupted bool `json:"interrupted,omitempty"`
+	// PreservedResources are the ARNs of the excluded resources found in the account and left in place
+	// +optional
+	PreservedResources []string `json:"preservedResources,omitempty"`
+	// StartTime is when the cleanup first ran
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is when the cleanup completed or failed for good
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+
+// AccountCleanup is the Schema for the accountcleanups API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Account",type="string",JSONPath=".spec.accountLink",description="Account cleaned up"
+// +kubebuilder:printcolumn:name="Claim",type="string",JSONPath=".spec.claimName",description="Deleted claim"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="State of the cleanup"
+// +kubebuilder:printcolumn:name="Attempts",type="integer",JSONPath=".status.attempts",description="Failed runs"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:path=accountcleanups,scope=Namespaced
+type AccountCleanup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AccountCleanupSpec   `json:"spec,omitempty"`
+	Status AccountCleanupStatus `json:"status,omitempty"`
+}
+
+// IsFinished returns true if the cleanup completed or failed for good
+func (c *AccountCleanup) IsFinished() bool {
+	return c.Status.State == AccountCleanupCompleted || c.Status.State == AccountCleanupFailed
+}
+
+// +kubebuilder:object:root=true
+
+// AccountCleanupList contains a list of AccountCleanup
+type AccountCleanupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AccountCleanup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AccountCleanup{}, &AccountCleanupList{})
+}
@@ -0,0 +1,79 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AccountOperatorStatusName is the name of the singleton AccountOperatorStatus CR, in the operator namespace
+const AccountOperatorStatusName = "fleet"
+
+// StuckAccount is an Account that has been in its state for longer than expected
+type StuckAccount struct {
+	// Name is the name of the Account CR
+	Name string `json:"name"`
+	// State is the state the Account is stuck in
+	State string `json:"state"`
+	// Since is when the Account entered the state
+	Since metav1.Time `json:"since"`
+}
+
+// AccountOperatorStatusStatus summarizes the Accounts and AccountClaims of the fleet
+// +k8s:openapi-gen=true
+type AccountOperatorStatusStatus struct {
+	// TotalAccounts is the number of Account CRs
+	// +optional
+	TotalAccounts int `json:"totalAccounts,omitempty"`
+	// AccountsByState is the number of Account CRs in each state, accounts without a state are counted as Pending
+	// +optional
+	AccountsByState map[string]int `json:"accountsByState,omitempty"`
+	// PendingClaims is the number of AccountClaims waiting for an account
+	// +optional
+	PendingClaims int `json:"pendingClaims,omitempty"`
+	// QuarantinedAccounts are the names of the Account CRs in a failed state
+	// +optional
+	QuarantinedAccounts []string `json:"quarantinedAccounts,omitempty"`
+	// OldestStuckAccount is the Account that has been stuck the longest
+	// +optional
+	OldestStuckAccount *StuckAccount `json:"oldestStuckAccount,omitempty"`
+	// LastSuccessfulCreation is when the last AWS account created by the operator became ready
+	// +optional
+	LastSuccessfulCreation *metav1.Time `json:"lastSuccessfulCreation,omitempty"`
+	// LastCreatedAccount is the name of the Account CR of the last AWS account created by the operator
+	// +optional
+	LastCreatedAccount string `json:"lastCreatedAccount,omitempty"`
+	// LastUpdated is when the summary was computed
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+
+// AccountOperatorStatus is the Schema for the accountoperatorstatuses API. The operator keeps a single one, named
+// fleet, summarizing the accounts it manages.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Accounts",type="integer",JSONPath=".status.totalAccounts",description="Number of Account CRs"
+// +kubebuilder:printcolumn:name="Pending Claims",type="integer",JSONPath=".status.pendingClaims",description="Number of AccountClaims waiting for an account"
+// +kubebuilder:printcolumn:name="Last Creation",type="date",JSONPath=".status.lastSuccessfulCreation",description="When the last created account became ready"
+// +kubebuilder:printcolumn:name="Updated",type="date",JSONPath=".status.lastUpdated"
+// +kubebuilder:resource:path=accountoperatorstatuses,scope=Namespaced
+type AccountOperatorStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status AccountOperatorStatusStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AccountOperatorStatusList contains a list of AccountOperatorStatus
+type AccountOperatorStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AccountOperatorStatus `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AccountOperatorStatus{}, &AccountOperatorStatusList{})
+}
@@ -16,6 +16,7 @@ type AccountPoolSpec struct {
 // AccountPoolStatus defines the observed state of AccountPool
 // +k8s:openapi-gen=true
 type AccountPoolStatus struct {
+	// PoolSize is the pool size currently targeted, spec.poolSize unless an AccountPoolPolicy window is active
 	PoolSize int `json:"poolSize"`
 
 	// UnclaimedAccounts is an approximate value representing the amount of non-failed accounts
@@ -32,6 +33,10 @@ type AccountPoolStatus struct {
 
 	// AWSLimitDelta shows the approximate difference between the number of AWS accounts currently created and the limit. This should be the same across all hive shards in an environment
 	AWSLimitDelta int `json:"awsLimitDelta"`
+
+	// ActivePolicy is the AccountPoolPolicy whose time window sets the pool size, the pool size is spec.poolSize when empty
+	// +optional
+	ActivePolicy string `json:"activePolicy,omitempty"`
 }
 
 // +genclient
@@ -0,0 +1,60 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PoolSizeWindow is a recurring time window during which an AccountPool targets another pool size
+// +k8s:openapi-gen=true
+type PoolSizeWindow struct {
+	// Days of the week the window starts on, e.g. Mon, every day when empty
+	// +optional
+	Days []string `json:"days,omitempty"`
+	// Start is the UTC time of day the window starts at, e.g. 08:00
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+	// End is the UTC time of day the window ends at, e.g. 20:00. A window ending before its start ends the next
+	// day, one ending at its start lasts the whole day.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+	// PoolSize is the pool size targeted during the window
+	// +kubebuilder:validation:Minimum=0
+	PoolSize int `json:"poolSize"`
+}
+
+// AccountPoolPolicySpec defines the scheduled pool sizes of an AccountPool
+// +k8s:openapi-gen=true
+type AccountPoolPolicySpec struct {
+	// AccountPool is the name of the AccountPool the policy sizes
+	AccountPool string `json:"accountPool"`
+	// Windows are the time windows overriding the poolSize of the AccountPool
+	Windows []PoolSizeWindow `json:"windows"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+
+// AccountPoolPolicy is the Schema for the accountpoolpolicies API
+// +k8s:openapi-gen=true
+// +kubebuilder:printcolumn:name="Account Pool",type="string",JSONPath=".spec.accountPool",description="AccountPool sized by the policy"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:path=accountpoolpolicies,scope=Namespaced
+type AccountPoolPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AccountPoolPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AccountPoolPolicyList contains a list of AccountPoolPolicy
+type AccountPoolPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AccountPoolPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AccountPoolPolicy{}, &AccountPoolPolicyList{})
+}
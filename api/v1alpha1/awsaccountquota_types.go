@@ -0,0 +1,84 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AWSAccountQuotaSpec defines the Account whose service quotas are tracked
+// +k8s:openapi-gen=true
+type AWSAccountQuotaSpec struct {
+	// AccountLink is the name of the Account CR whose service quotas are tracked
+	AccountLink string `json:"accountLink"`
+	// Regions are the regions the regional service quotas are tracked in, the default region when empty
+	// +optional
+	Regions []string `json:"regions,omitempty"`
+}
+
+// TrackedServiceQuota is a service quota of an account and its current usage
+// +k8s:openapi-gen=true
+type TrackedServiceQuota struct {
+	// Name of the quota, e.g. vcpus
+	Name string `json:"name"`
+	// ServiceCode is the AWS code of the service, e.g. ec2
+	ServiceCode string `json:"serviceCode"`
+	// QuotaCode is the AWS code of the quota, e.g. L-1216C47A
+	QuotaCode string `json:"quotaCode"`
+	// Region the quota applies to, global for the quotas of global services
+	Region string `json:"region"`
+	// Limit is the value of the quota
+	Limit int `json:"limit"`
+	// Usage is how much of the quota is in use
+	Usage int `json:"usage"`
+	// UsagePercent is the usage as a percentage of the limit
+	UsagePercent int `json:"usagePercent"`
+}
+
+// AWSAccountQuotaStatus defines the observed service quotas of the Account
+// +k8s:openapi-gen=true
+type AWSAccountQuotaStatus struct {
+	// Quotas are the tracked service quotas as of the last snapshot
+	// +optional
+	Quotas []TrackedServiceQuota `json:"quotas,omitempty"`
+	// NearlyExhausted is true if the usage of a quota reached the exhaustion threshold, such accounts are handed
+	// out to claims last
+	// +optional
+	NearlyExhausted bool `json:"nearlyExhausted,omitempty"`
+	// LastSnapshotTime is when the quotas were last snapshotted
+	// +optional
+	LastSnapshotTime *metav1.Time `json:"lastSnapshotTime,omitempty"`
+	// LastError is the error of the last snapshot, if it failed
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+
+// AWSAccountQuota is the Schema for the awsaccountquotas API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Account",type="string",JSONPath=".spec.accountLink",description="Account tracked"
+// +kubebuilder:printcolumn:name="Nearly Exhausted",type="boolean",JSONPath=".status.nearlyExhausted",description="A quota is nearly exhausted"
+// +kubebuilder:printcolumn:name="Last Snapshot",type="date",JSONPath=".status.lastSnapshotTime",description="When the quotas were last snapshotted"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:path=awsaccountquotas,scope=Namespaced
+type AWSAccountQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AWSAccountQuotaSpec   `json:"spec,omitempty"`
+	Status AWSAccountQuotaStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AWSAccountQuotaList contains a list of AWSAccountQuota
+type AWSAccountQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AWSAccountQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AWSAccountQuota{}, &AWSAccountQuotaList{})
+}
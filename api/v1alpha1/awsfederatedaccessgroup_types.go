@@ -0,0 +1,64 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AWSFederatedAccessGroupSpec defines the desired state of AWSFederatedAccessGroup
+// +k8s:openapi-gen=true
+type AWSFederatedAccessGroupSpec struct {
+	// GroupName is the name of the OpenShift Group, synced from SSO or LDAP, whose members are granted access
+	GroupName string `json:"groupName"`
+	// IAMARNTemplate is a Go template of the external AWS IAM ARN of a group member, e.g.
+	// arn:aws:iam::123456789012:user/{{.User}}
+	IAMARNTemplate string `json:"iamARNTemplate"`
+	// AWSFederatedRole is the federated role granted to the members of the group
+	AWSFederatedRole AWSFederatedRoleRef `json:"awsFederatedRole"`
+	// AccountSelector selects the Account CRs the members of the group are granted access to
+	AccountSelector metav1.LabelSelector `json:"accountSelector"`
+	// Expiration is set on the AWSFederatedAccountAccesses of the group
+	// +optional
+	Expiration *metav1.Duration `json:"expiration,omitempty"`
+}
+
+// AWSFederatedAccessGroupStatus defines the observed state of AWSFederatedAccessGroup
+// +k8s:openapi-gen=true
+type AWSFederatedAccessGroupStatus struct {
+	// Members are the users of the group that were granted access
+	Members []string `json:"members,omitempty"`
+	// Accounts are the Account CRs the members were granted access to
+	Accounts []string `json:"accounts,omitempty"`
+	// AccountAccesses is the number of AWSFederatedAccountAccesses of the group
+	AccountAccesses int `json:"accountAccesses"`
+}
+
+// +kubebuilder:object:root=true
+
+// AWSFederatedAccessGroup is the Schema for the awsfederatedaccessgroups API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Group",type="string",JSONPath=".spec.groupName",description="OpenShift Group granted access"
+// +kubebuilder:printcolumn:name="Role",type="string",JSONPath=".spec.awsFederatedRole.name",description="Federated role granted to the group"
+// +kubebuilder:printcolumn:name="Accesses",type="integer",JSONPath=".status.accountAccesses",description="Number of account accesses of the group"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age since the access group was created"
+// +kubebuilder:resource:path=awsfederatedaccessgroups,scope=Namespaced
+type AWSFederatedAccessGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AWSFederatedAccessGroupSpec   `json:"spec,omitempty"`
+	Status AWSFederatedAccessGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AWSFederatedAccessGroupList contains a list of AWSFederatedAccessGroup
+type AWSFederatedAccessGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AWSFederatedAccessGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AWSFederatedAccessGroup{}, &AWSFederatedAccessGroupList{})
+}
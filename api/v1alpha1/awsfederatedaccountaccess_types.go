@@ -1,6 +1,8 @@
 package v1alpha1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -18,6 +20,8 @@ const (
 	AWSFederatedAccountStateReady AWSFederatedAccountAccessState = "Ready"
 	// AWSFederatedAccountStateFailed cont for Failed status state
 	AWSFederatedAccountStateFailed AWSFederatedAccountAccessState = "Failed"
+	// AWSFederatedAccountStateExpired const for Expired status state
+	AWSFederatedAccountStateExpired AWSFederatedAccountAccessState = "Expired"
 )
 
 // AWSFederatedAccountAccessSpec defines the desired state of AWSFederatedAccountAccess
@@ -29,6 +33,13 @@ type AWSFederatedAccountAccessSpec struct {
 	AWSCustomerCredentialSecret AWSSecretReference `json:"awsCustomerCredentialSecret"`
 	// FederatedRoleName must be the name of a federatedrole cr that currently exists
 	AWSFederatedRole AWSFederatedRoleRef `json:"awsFederatedRole"`
+	// Expiration is how long after its creation the access is revoked, e.g. 8h. Access never expires when unset.
+	// +optional
+	Expiration *metav1.Duration `json:"expiration,omitempty"`
+	// SigninSessionDuration is how long the console sessions opened with the sign-in URL of the access last,
+	// between 15m and 36h. Defaults to 1h.
+	// +optional
+	SigninSessionDuration *metav1.Duration `json:"signinSessionDuration,omitempty"`
 }
 
 // AWSFederatedAccountAccessStatus defines the observed state of AWSFederatedAccountAccess
@@ -39,6 +50,13 @@ type AWSFederatedAccountAccessStatus struct {
 	Conditions []AWSFederatedAccountAccessCondition `json:"conditions"`
 	State      AWSFederatedAccountAccessState       `json:"state"`
 	ConsoleURL string                               `json:"consoleURL,omitempty"`
+	// CustomPolicyArns are the ARNs of the parts of the custom policy created for the role, deleted with it
+	// +optional
+	CustomPolicyArns []string `json:"customPolicyArns,omitempty"`
+	// SigninURLSecret is the name of the secret, in the namespace of the account access, holding a console
+	// sign-in URL scoped to the policies of the role
+	// +optional
+	SigninURLSecret string `json:"signinURLSecret,omitempty"`
 }
 
 // AWSFederatedAccountAccessCondition defines a current condition state of the account
@@ -71,6 +89,8 @@ const (
 	AWSFederatedAccountReady AWSFederatedAccountAccessConditionType = "Ready"
 	// AWSFederatedAccountFailed is set when account access has failed to apply
 	AWSFederatedAccountFailed AWSFederatedAccountAccessConditionType = "Failed"
+	// AWSFederatedAccountExpired is set when account access has been revoked because it expired
+	AWSFederatedAccountExpired AWSFederatedAccountAccessConditionType = "Expired"
 )
 
 // AWSSecretReference holds the name and namespace of an secret containing credentials to cluster account
@@ -101,6 +121,14 @@ type AWSFederatedAccountAccess struct {
 	Status AWSFederatedAccountAccessStatus `json:"status,omitempty"`
 }
 
+// ExpirationTime returns the time the access expires at, and false if it doesn't expire
+func (a *AWSFederatedAccountAccess) ExpirationTime() (time.Time, bool) {
+	if a.Spec.Expiration == nil {
+		return time.Time{}, false
+	}
+	return a.CreationTimestamp.Add(a.Spec.Expiration.Duration), true
+}
+
 // +kubebuilder:object:root=true
 
 // AWSFederatedAccountAccessList contains a list of AWSFederatedAccountAccess
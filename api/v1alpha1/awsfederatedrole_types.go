@@ -101,6 +101,8 @@ const (
 	AWSFederatedRoleValid AWSFederatedRoleConditionType = "Valid"
 	// AWSFederatedRoleInvalid is set when an awsfederated role is invalid
 	AWSFederatedRoleInvalid AWSFederatedRoleConditionType = "Invalid"
+	// AWSFederatedRolePolicyWarnings is set when IAM Access Analyzer reports warnings for the custom policy
+	AWSFederatedRolePolicyWarnings AWSFederatedRoleConditionType = "PolicyWarnings"
 )
 
 // +kubebuilder:object:root=true
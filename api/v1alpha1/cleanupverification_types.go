@@ -0,0 +1,102 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CleanupVerificationSpec defines an end-to-end check of the account cleanup: representative resources are seeded
+// in the AWS account of an unclaimed Account, an AccountCleanup is run against it, and the verification passes if
+// none of the seeded resources is left
+// +k8s:openapi-gen=true
+type CleanupVerificationSpec struct {
+	// AccountLink is the name of the Ready, unclaimed, non-CCS Account CR to verify the cleanup against. Its AWS
+	// account has to be disposable, as the cleanup deletes everything it finds in it.
+	AccountLink string `json:"accountLink"`
+	// Region the EBS volume and snapshot are seeded in and the cleanup is run in, the default region when empty
+	// +optional
+	Region string `json:"region,omitempty"`
+}
+
+// CleanupVerificationState is the state of a CleanupVerification
+type CleanupVerificationState string
+
+const (
+	// CleanupVerificationSeeding is the state of a verification seeding the account
+	CleanupVerificationSeeding CleanupVerificationState = "Seeding"
+	// CleanupVerificationCleaningUp is the state of a verification waiting for its AccountCleanup to finish
+	CleanupVerificationCleaningUp CleanupVerificationState = "CleaningUp"
+	// CleanupVerificationPassed is the state of a verification whose cleanup removed all the seeded resources
+	CleanupVerificationPassed CleanupVerificationState = "Passed"
+	// CleanupVerificationFailed is the state of a verification whose cleanup failed or left seeded resources behind,
+	// or that couldn't be run
+	CleanupVerificationFailed CleanupVerificationState = "Failed"
+)
+
+// SeededResource is a resource seeded in the account by a CleanupVerification
+type SeededResource struct {
+	// Kind of the resource: S3Bucket, HostedZone, EBSVolume, EBSSnapshot or IAMUser
+	Kind string `json:"kind"`
+	// ID is the name or the ID of the resource
+	ID string `json:"id"`
+}
+
+// CleanupVerificationStatus defines the observed state of CleanupVerification
+// +k8s:openapi-gen=true
+type CleanupVerificationStatus struct {
+	// +optional
+	State CleanupVerificationState `json:"state,omitempty"`
+	// SeededResources are the resources seeded in the account
+	// +optional
+	SeededResources []SeededResource `json:"seededResources,omitempty"`
+	// RemainingResources are the seeded resources still in the account after the cleanup
+	// +optional
+	RemainingResources []SeededResource `json:"remainingResources,omitempty"`
+	// AccountCleanup is the name of the AccountCleanup run against the account
+	// +optional
+	AccountCleanup string `json:"accountCleanup,omitempty"`
+	// Message explains why the verification failed
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartTime is when the seeding started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is when the verification passed or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+
+// CleanupVerification is the Schema for the cleanupverifications API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Account",type="string",JSONPath=".spec.accountLink",description="Account the cleanup is verified against"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="State of the verification"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:path=cleanupverifications,scope=Namespaced
+type CleanupVerification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CleanupVerificationSpec   `json:"spec,omitempty"`
+	Status CleanupVerificationStatus `json:"status,omitempty"`
+}
+
+// IsFinished returns true if the verification passed or failed
+func (v *CleanupVerification) IsFinished() bool {
+	return v.Status.State == CleanupVerificationPassed || v.Status.State == CleanupVerificationFailed
+}
+
+// +kubebuilder:object:root=true
+
+// CleanupVerificationList contains a list of CleanupVerification
+type CleanupVerificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CleanupVerification `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CleanupVerification{}, &CleanupVerificationList{})
+}
@@ -0,0 +1,124 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IAMPrincipalType is the kind of IAM principal requested
+type IAMPrincipalType string
+
+const (
+	// IAMPrincipalUser is an IAM user, its access key is stored in the credentials secret
+	IAMPrincipalUser IAMPrincipalType = "User"
+	// IAMPrincipalRole is an IAM role assumed by the trusted ARNs, its ARN is stored in the credentials secret
+	IAMPrincipalRole IAMPrincipalType = "Role"
+)
+
+// IAMUserRequestSpec defines the IAM principal to create in the account of an AccountClaim
+// +k8s:openapi-gen=true
+type IAMUserRequestSpec struct {
+	// AccountClaim is the name of the AccountClaim, in the namespace of the request, whose account the principal
+	// is created in
+	AccountClaim string `json:"accountClaim"`
+	// PrincipalType is the kind of IAM principal to create
+	// +kubebuilder:validation:Enum=User;Role
+	PrincipalType IAMPrincipalType `json:"principalType"`
+	// IAMName is the name of the IAM user or role, the name of the request when empty
+	// +optional
+	IAMName string `json:"iamName,omitempty"`
+	// TrustedARNs are the ARNs allowed to assume the role, required for roles
+	// +optional
+	TrustedARNs []string `json:"trustedARNs,omitempty"`
+	// ManagedPolicyARNs are the ARNs of the managed policies attached to the principal
+	// +optional
+	ManagedPolicyARNs []string `json:"managedPolicyARNs,omitempty"`
+	// InlinePolicy is put on the principal as an inline policy
+	// +optional
+	InlinePolicy *AWSCustomPolicy `json:"inlinePolicy,omitempty"`
+	// SecretName is the name of the secret the credentials are stored in, <name>-iam-credentials when empty
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// IAMUserRequestState is the state of an IAMUserRequest
+type IAMUserRequestState string
+
+const (
+	// IAMUserRequestPending is the state of a request waiting for its AccountClaim to be Ready
+	IAMUserRequestPending IAMUserRequestState = "Pending"
+	// IAMUserRequestReady is the state of a request whose principal and credentials secret exist
+	IAMUserRequestReady IAMUserRequestState = "Ready"
+	// IAMUserRequestFailed is the state of a request whose principal couldn't be created, it's retried unless the
+	// request is invalid
+	IAMUserRequestFailed IAMUserRequestState = "Failed"
+)
+
+// IAMUserRequestStatus defines the observed state of IAMUserRequest
+// +k8s:openapi-gen=true
+type IAMUserRequestStatus struct {
+	// +optional
+	State IAMUserRequestState `json:"state,omitempty"`
+	// AccountLink is the name of the Account CR the principal was created for, it's torn down from it on deletion
+	// +optional
+	AccountLink string `json:"accountLink,omitempty"`
+	// AwsAccountID is the ID of the AWS account the principal was created in
+	// +optional
+	AwsAccountID string `json:"awsAccountID,omitempty"`
+	// PrincipalARN is the ARN of the IAM user or role
+	// +optional
+	PrincipalARN string `json:"principalARN,omitempty"`
+	// SecretRef is the secret the credentials are stored in
+	// +optional
+	SecretRef *SecretRef `json:"secretRef,omitempty"`
+	// Message explains the state of the request
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+
+// IAMUserRequest is the Schema for the iamuserrequests API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Claim",type="string",JSONPath=".spec.accountClaim",description="AccountClaim of the account"
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.principalType",description="Kind of IAM principal"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="State of the request"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:path=iamuserrequests,scope=Namespaced
+type IAMUserRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IAMUserRequestSpec   `json:"spec,omitempty"`
+	Status IAMUserRequestStatus `json:"status,omitempty"`
+}
+
+// GetIAMName returns the name of the IAM user or role of the request
+func (r *IAMUserRequest) GetIAMName() string {
+	if r.Spec.IAMName != "" {
+		return r.Spec.IAMName
+	}
+	return r.Name
+}
+
+// GetSecretName returns the name of the secret the credentials are stored in
+func (r *IAMUserRequest) GetSecretName() string {
+	if r.Spec.SecretName != "" {
+		return r.Spec.SecretName
+	}
+	return r.Name + "-iam-credentials"
+}
+
+// +kubebuilder:object:root=true
+
+// IAMUserRequestList contains a list of IAMUserRequest
+type IAMUserRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IAMUserRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IAMUserRequest{}, &IAMUserRequestList{})
+}
@@ -2,6 +2,13 @@ package v1alpha1
 
 import (
 	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type AmiSpec struct {
@@ -9,6 +16,35 @@ type AmiSpec struct {
 	InstanceType string
 }
 
+// StateTransition records when a CR entered its current state
+type StateTransition struct {
+	// State is the state the CR transitioned to
+	State string `json:"state"`
+	// LastTransitionTime is when the CR entered State
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// NewStateTransition returns a StateTransition to the given state that happened now
+func NewStateTransition(state string) *StateTransition {
+	return &StateTransition{
+		State:              state,
+		LastTransitionTime: metav1.Now(),
+	}
+}
+
+// IsFor returns true if the transition was recorded for the given state
+func (t *StateTransition) IsFor(state string) bool {
+	return t != nil && t.State == state
+}
+
+// TimeInState returns how long the CR has been in the state of the transition
+func (t *StateTransition) TimeInState() time.Duration {
+	if t == nil {
+		return 0
+	}
+	return time.Since(t.LastTransitionTime.Time)
+}
+
 // Custom errors
 
 // ErrAwsAccountLimitExceeded indicates the orgnization account limit has been reached.
@@ -23,6 +59,9 @@ var ErrAwsInternalFailure = errors.New("InternalFailure")
 // ErrAwsFailedCreateAccount indicates that an account creation failed
 var ErrAwsFailedCreateAccount = errors.New("FailedCreateAccount")
 
+// ErrAccountEmailInUse indicates that no email unused by the accounts of the organization could be generated for a new account
+var ErrAccountEmailInUse = errors.New("AccountEmailInUse")
+
 // ErrAwsConcurrentModification indicates that a resource is currently being modified and the request should be retried
 var ErrAwsConcurrentModification = errors.New("ConcurrentModificationOfOU")
 
@@ -89,6 +128,85 @@ var ErrFailedToCreateSubnet = errors.New("FailedToCreateSubnet")
 // ErrFailedToDeleteSubnet indicates that there was a failure while trying to delete subnet
 var ErrFailedToDeleteSubnet = errors.New("FailedToDeleteSubnet")
 
+// ErrManualActionRequired indicates resources that the operator isn't allowed to delete, like S3 buckets with
+// object lock, and that someone has to remove by hand
+var ErrManualActionRequired = errors.New("ManualActionRequired")
+
+// ErrAccountClaimed indicates that an account was claimed while the operator was reserving or changing it
+var ErrAccountClaimed = errors.New("AccountClaimed")
+
+// AWSErrorCategory is the category of the AWS error behind a failure recorded in the status of a CR, so automation
+// can tell the failures that go away when retried from the ones that need someone to fix something
+type AWSErrorCategory string
+
+const (
+	// AWSErrorThrottled is a request rejected by the rate limits of the AWS API
+	AWSErrorThrottled AWSErrorCategory = "Throttled"
+	// AWSErrorAccessDenied is a request the operator isn't allowed to make, by IAM, an SCP or its credentials
+	AWSErrorAccessDenied AWSErrorCategory = "AccessDenied"
+	// AWSErrorDependencyViolation is a resource that can't be changed or deleted while others depend on it
+	AWSErrorDependencyViolation AWSErrorCategory = "DependencyViolation"
+	// AWSErrorNotFound is a resource that doesn't exist
+	AWSErrorNotFound AWSErrorCategory = "NotFound"
+	// AWSErrorManualActionRequired is a resource the operator can't delete by design, which has to be removed by hand
+	AWSErrorManualActionRequired AWSErrorCategory = "ManualActionRequired"
+	// AWSErrorOther is any other failure, including the ones that don't come from AWS
+	AWSErrorOther AWSErrorCategory = "Other"
+)
+
+// awsAccessDeniedCodes are the AWS error codes of the requests the operator isn't allowed to make
+var awsAccessDeniedCodes = map[string]bool{
+	"AccessDenied":          true,
+	"AccessDeniedException": true,
+	"AuthFailure":           true,
+	"AuthorizationError":    true,
+	"InvalidClientTokenId":  true,
+	"UnauthorizedAccess":    true,
+	"UnauthorizedOperation": true,
+}
+
+// awsDependencyViolationCodes are the AWS error codes of the resources held by other resources, besides the ones
+// ending in "InUse" or "NotEmpty", like VolumeInUse or BucketNotEmpty
+var awsDependencyViolationCodes = map[string]bool{
+	"DependencyViolation":    true,
+	"DeleteConflict":         true,
+	"ResourceInUseException": true,
+}
+
+// GetAWSErrorCategory returns the category of the first AWS error wrapped in err, AWSErrorOther if there's none
+func GetAWSErrorCategory(err error) AWSErrorCategory {
+	if errors.Is(err, ErrManualActionRequired) {
+		return AWSErrorManualActionRequired
+	}
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) {
+		return AWSErrorOther
+	}
+	code := aerr.Code()
+	statusCode := 0
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		statusCode = reqErr.StatusCode()
+	}
+	switch {
+	case request.IsErrorThrottle(aerr) || statusCode == http.StatusTooManyRequests:
+		return AWSErrorThrottled
+	case awsAccessDeniedCodes[code] || request.IsErrorExpiredCreds(aerr) || statusCode == http.StatusForbidden:
+		return AWSErrorAccessDenied
+	case awsDependencyViolationCodes[code] || strings.HasSuffix(code, "InUse") || strings.HasSuffix(code, "NotEmpty"):
+		return AWSErrorDependencyViolation
+	case strings.HasSuffix(code, "NotFound") || strings.HasSuffix(code, "NotFoundException") || strings.HasPrefix(code, "NoSuch") || statusCode == http.StatusNotFound:
+		return AWSErrorNotFound
+	}
+	return AWSErrorOther
+}
+
+// Retryable returns false for the categories of the failures retrying doesn't fix, the operator being denied
+// access, missing resources and the resources to remove by hand
+func (c AWSErrorCategory) Retryable() bool {
+	return c != AWSErrorAccessDenied && c != AWSErrorNotFound && c != AWSErrorManualActionRequired
+}
+
 // Shared variables
 
 // UIDLabel is the string for the uid label on AWS Federated Account Access CRs
@@ -98,9 +216,22 @@ var FederatedRoleNameLabel = "awsFederatedRoleName"
 
 var LastRoleUpdateAnnotation = "lastRoleUpdate"
 
-// AccountIDLabel is the string for the AWS Account ID label on AWS Federated Account Access CRs
+// AccountIDLabel is the string for the AWS Account ID label on AWS Federated Account Access CRs, and on the
+// AccountClaims bound to an account, so the claim of an AWS account can be looked up with a label selector
 var AccountIDLabel = "awsAccountID"
 
+// FederatedAccessGroupLabel is the label on the AWS Federated Account Access CRs created for an
+// AWSFederatedAccessGroup, holding the name of the group CR
+var FederatedAccessGroupLabel = "awsFederatedAccessGroup"
+
+// FederatedAccessGroupUserAnnotation is the annotation on the AWS Federated Account Access CRs created for an
+// AWSFederatedAccessGroup, holding the group member the access is granted to
+var FederatedAccessGroupUserAnnotation = "aws.managed.openshift.io/federated-access-group-user"
+
+// FederatedAccessGroupAccountAnnotation is the annotation on the AWS Federated Account Access CRs created for an
+// AWSFederatedAccessGroup, holding the Account CR the access is granted in
+var FederatedAccessGroupAccountAnnotation = "aws.managed.openshift.io/federated-access-group-account"
+
 // ClusterAccountNameTagKey is the AWS key name for cluster account name
 var ClusterAccountNameTagKey = "clusterAccountName"
 
@@ -113,6 +244,24 @@ var ClusterClaimLinkTagKey = "clusterClaimLink"
 // ClusterClaimLinkNamespaceTagKey is the AWS key name for cluster claim namespace
 var ClusterClaimLinkNamespaceTagKey = "clusterClaimLinkNamespace"
 
+// ClusterIDTagKey is the AWS key name for the ID of the cluster the account is claimed for
+var ClusterIDTagKey = "clusterID"
+
+// LegalEntityIDTagKey is the AWS key name for the ID of the legal entity owning the account
+var LegalEntityIDTagKey = "legalEntityID"
+
+// ClaimUIDTagKey is the AWS key name for the UID of the AccountClaim the account is claimed by
+var ClaimUIDTagKey = "claimUID"
+
+// CreatedByTagKey is the AWS key name tagging the AWS accounts created by the operator, with CreatedByTagValue
+var CreatedByTagKey = "createdBy"
+
+// CreatedByTagValue is the value of the CreatedByTagKey tag of the AWS accounts created by the operator
+var CreatedByTagValue = "aws-account-operator"
+
+// ClusterIDLabel is the AccountClaim label holding the ID of the cluster the claim is for
+var ClusterIDLabel = "api.openshift.com/id"
+
 // Used to name the EC2 instance we spin up when initializing an AWS region
 var EC2InstanceNameTagKey = "Name"
 var EC2InstanceNameTagValue = "red-hat-region-init"
@@ -120,6 +269,30 @@ var EC2InstanceNameTagValue = "red-hat-region-init"
 // IAMUserIDLabel label key for IAM user suffix
 var IAMUserIDLabel = "iamUserId"
 
+// AdoptedAccountLabel is the label marking the Account CRs created for AWS accounts found in the pool OU without
+// an Account CR
+var AdoptedAccountLabel = "aws.managed.openshift.io/adopted"
+
+// ManagedByLabel is the label key marking the secrets created by the operator
+var ManagedByLabel = "app.kubernetes.io/managed-by"
+
+// ManagedByOperator is the ManagedByLabel value of the secrets created by the operator
+var ManagedByOperator = "aws-account-operator"
+
+// SecretOwnerKindLabel is the label key for the kind of the CR an operator secret was created for
+var SecretOwnerKindLabel = "aws.managed.openshift.io/owner-kind"
+
+// SecretOwnerNameAnnotation is the annotation key for the name of the CR an operator secret was created for.
+// Names can be longer than label values allow.
+var SecretOwnerNameAnnotation = "aws.managed.openshift.io/owner-name"
+
+// SecretOwnerNamespaceAnnotation is the annotation key for the namespace of the CR an operator secret was created for
+var SecretOwnerNamespaceAnnotation = "aws.managed.openshift.io/owner-namespace"
+
+// ShardLabel is the label key for the shard of the operator that reconciles an Account or AccountClaim when the
+// operator is sharded
+var ShardLabel = "aws.managed.openshift.io/shard"
+
 // EmailID is the ID used for prefixing Account CR names
 var EmailID = "osd-creds-mgmt"
 
@@ -155,9 +328,219 @@ var ManagedOpenShiftSupportRole = "ManagedOpenShift-Support"
 
 var ManagedOpenShiftSupportRoleARN = "arn:aws:iam::%s:role/ManagedOpenShift-Support-%s"
 
+// IAMUserNamePrefix is the name prefix of the IAM users created for clusters, the IAMUserIDLabel of the
+// account is appended to it
+var IAMUserNamePrefix = "osdManagedAdmin"
+
 // fedramp arn
 var FedrampManagedOpenShiftSupportRoleARN = "arn:aws-us-gov:iam::%s:role/ManagedOpenShift-Support-%s"
 
 var CCSAccessARN = "CCS-Access-Arn"
 
 var SupportJumpRole = "support-jump-role"
+
+// ManagedOpenShiftSREAccessRole is the role SREs assume from the SRE account to access non-CCS accounts
+var ManagedOpenShiftSREAccessRole = "ManagedOpenShift-SRE-Access"
+
+// SREAccessARN is the configmap key holding the principal allowed to assume the SRE access role
+var SREAccessARN = "sre-access-arn"
+
+// SREAccessExternalID is the configmap key holding the external ID required to assume the SRE access role
+var SREAccessExternalID = "sre-access-external-id"
+
+// SREAccessMaxSessionDuration is the configmap key holding the maximum session duration of the SRE access role
+var SREAccessMaxSessionDuration = "sre-access-max-session-duration"
+
+// PermissionsBoundaryConfigMapKey is the configmap key holding the permissions boundary policy ARN applied to every
+// IAM user and role the operator creates
+var PermissionsBoundaryConfigMapKey = "iam-permissions-boundary-arn"
+
+// IAMDriftCheckIntervalConfigMapKey is the configmap key for how often the IAM principals of Ready
+// accounts are audited for drift
+var IAMDriftCheckIntervalConfigMapKey = "iam-drift-check-interval"
+
+// AccessKeyMaxAgeConfigMapKey is the configmap key for the age after which the osdManagedAdmin access key
+// is rotated
+var AccessKeyMaxAgeConfigMapKey = "access-key-max-age"
+
+// RecreateMissingIAMUsersConfigMapKey is the configmap key enabling the recreation of the osdManagedAdmin IAM users
+// the credential rotator finds missing. They're flagged with the IAMUserMissing condition otherwise.
+var RecreateMissingIAMUsersConfigMapKey = "recreate-missing-iam-users"
+
+// SecretBackendConfigMapKey is the configmap key selecting where the AWS credentials handed to AccountClaims
+// are stored, either "kubernetes" (the default) or "vault"
+var SecretBackendConfigMapKey = "secret-backend"
+
+// VaultAddressConfigMapKey is the configmap key holding the address of the Vault server, e.g. https://vault.example.com:8200
+var VaultAddressConfigMapKey = "vault-address"
+
+// VaultRoleConfigMapKey is the configmap key holding the Vault role the operator logs in with using its service account token
+var VaultRoleConfigMapKey = "vault-role"
+
+// VaultAuthMountConfigMapKey is the configmap key holding the mount path of the Vault Kubernetes auth method
+var VaultAuthMountConfigMapKey = "vault-auth-mount"
+
+// VaultKVMountConfigMapKey is the configmap key holding the mount path of the Vault KV version 2 secrets engine
+var VaultKVMountConfigMapKey = "vault-kv-mount"
+
+// VaultPathPrefixConfigMapKey is the configmap key holding the path the secrets are written under in the KV secrets engine
+var VaultPathPrefixConfigMapKey = "vault-path-prefix"
+
+// SRECLICredentialsConfigMapKey is the configmap key enabling the short-lived STS session credentials published
+// in the <account>-sre-cli-credentials secret of Ready non-CCS accounts for SRE break-glass access
+var SRECLICredentialsConfigMapKey = "sre-cli-credentials"
+
+// SREConsoleURLConfigMapKey is the configmap key enabling the federated sign-in URL published in the
+// <account>-sre-console-url secret of Ready non-CCS accounts
+var SREConsoleURLConfigMapKey = "sre-console-url"
+
+// SREConsoleSessionDurationConfigMapKey is the configmap key for the duration of the console sessions opened
+// with the SRE console URL, e.g. 1h
+var SREConsoleSessionDurationConfigMapKey = "sre-console-session-duration"
+
+// CCSValidationConfigMapKey is the configmap key enabling the validation of the customer account before a CCS
+// AccountClaim is accepted
+var CCSValidationConfigMapKey = "ccs-validation"
+
+// CCSValidationMinimumQuotaConfigMapKeyPrefix prefixes the configmap keys overriding the minimum value of a
+// service quota of the customer account, e.g. ccs-validation.minimum-quota.L-1216C47A
+var CCSValidationMinimumQuotaConfigMapKeyPrefix = "ccs-validation.minimum-quota."
+
+// CCSAccountRetentionConfigMapKey is the configmap key for how long the Account CR of a CCS account is kept, as
+// Retired, after its AccountClaim is deleted, e.g. 720h. CCS Account CRs are deleted right away when it's unset.
+var CCSAccountRetentionConfigMapKey = "ccs-account-retention"
+
+// RegionInitConcurrencyConfigMapKey is the configmap key for how many regions of a new account are initialized
+// at the same time
+var RegionInitConcurrencyConfigMapKey = "region-init-concurrency"
+
+// RequiredServiceQuotasConfigMapKey is the configmap key for the service quotas requested in every region of
+// every new non-CCS account, whether or not its AccountPool configures servicequotas. The servicequotas of the
+// pool take precedence over it.
+var RequiredServiceQuotasConfigMapKey = "required-service-quotas"
+
+// AccountEmailTemplateConfigMapKey is the configmap key for the Go template of the email of new accounts, e.g.
+// {{.Prefix}}+{{.ID}}@redhat.com
+var AccountEmailTemplateConfigMapKey = "account-email-template"
+
+// OrphanedAccountCheckIntervalConfigMapKey is the configmap key for how often the AWS accounts of the
+// organization are compared with the Account CRs, e.g. 6h. An interval of 0s disables the check.
+var OrphanedAccountCheckIntervalConfigMapKey = "orphaned-account-check-interval"
+
+// OrphanedAccountPoolOUConfigMapKey is the configmap key holding the ID of the OU the pool accounts are created in,
+// where the AWS accounts without an Account CR are looked for. Orphaned AWS accounts aren't looked for without it.
+var OrphanedAccountPoolOUConfigMapKey = "orphaned-account-pool-ou"
+
+// OrphanedAccountAdoptionConfigMapKey is the configmap key enabling the creation of Account CRs for the AWS
+// accounts of the pool OU created by the operator without one, so the operator sets them up and adds them to the
+// default AccountPool
+var OrphanedAccountAdoptionConfigMapKey = "orphaned-account-adoption"
+
+// IdleCostCheckIntervalConfigMapKey is the configmap key for how often Cost Explorer is asked about the cost of the
+// Ready unclaimed accounts, e.g. 24h. An interval of 0s disables the check.
+var IdleCostCheckIntervalConfigMapKey = "idle-cost-check-interval"
+
+// IdleCostThresholdConfigMapKey is the configmap key for the cost, in USD, from which an unclaimed account gets the
+// IdleCost condition, over the days it has been idle within the last week. "1" by default.
+var IdleCostThresholdConfigMapKey = "idle-cost-threshold"
+
+// AWSNotificationsQueueURLConfigMapKey is the configmap key holding the URL of the SQS queue the AWS notifications
+// about the accounts are consumed from, e.g. https://sqs.us-east-1.amazonaws.com/123456789012/aao-notifications.
+// The notifications aren't consumed when it's not set.
+var AWSNotificationsQueueURLConfigMapKey = "aws-notifications-queue-url"
+
+// AccountHealthCheckIntervalConfigMapKey is the configmap key for how often AWS Health is checked for the
+// problems of the accounts of the organization, e.g. 15m. An interval of 0s disables the check.
+var AccountHealthCheckIntervalConfigMapKey = "account-health-check-interval"
+
+// AccountQuotaSnapshotIntervalConfigMapKey is the configmap key for how often the service quotas of Ready non-CCS
+// accounts and their usage are snapshotted in AWSAccountQuotas, e.g. 6h. An interval of 0s disables the snapshots.
+var AccountQuotaSnapshotIntervalConfigMapKey = "account-quota-snapshot-interval"
+
+// AccountQuotaExhaustionThresholdConfigMapKey is the configmap key for the usage, as a percentage of a service
+// quota, from which an account is nearly exhausted, e.g. 80
+var AccountQuotaExhaustionThresholdConfigMapKey = "account-quota-exhaustion-threshold"
+
+// FederatedRoleResyncIntervalConfigMapKey is the configmap key for how often the roles of Ready
+// AWSFederatedAccountAccesses are compared with their AWSFederatedRole and repaired, e.g. 1h. An interval of 0s
+// only checks them when the CRs change.
+var FederatedRoleResyncIntervalConfigMapKey = "federated-role-resync-interval"
+
+// IdentityCenterInstanceARNConfigMapKey is the configmap key holding the ARN of the IAM Identity Center instance
+// of the organization. When it's set, the permission sets of IdentityCenterAssignmentsConfigMapKey are assigned
+// in the accounts of non-CCS AccountClaims and removed when the accounts are reused.
+var IdentityCenterInstanceARNConfigMapKey = "identity-center-instance-arn"
+
+// IdentityCenterRegionConfigMapKey is the configmap key for the region of the IAM Identity Center instance,
+// defaulting to the default region of the operator
+var IdentityCenterRegionConfigMapKey = "identity-center-region"
+
+// IdentityCenterAssignmentsConfigMapKey is the configmap key for the IAM Identity Center permission sets assigned
+// in claimed accounts and the groups or users they are assigned to, e.g.
+//
+//	identity-center-assignments: |
+//	  - permissionSetArn: arn:aws:sso:::permissionSet/ssoins-1234567890abcdef/ps-1234567890abcdef
+//	    groupId: 1234567890-12345678-1234-1234-1234-123456789012
+var IdentityCenterAssignmentsConfigMapKey = "identity-center-assignments"
+
+// BudgetLimitConfigMapKey is the configmap key for the monthly cost, in USD, of the AWS Budget created in the payer
+// account for the account of each non-CCS AccountClaim, e.g. "500". No budget is created when it's not set.
+var BudgetLimitConfigMapKey = "budget-monthly-limit"
+
+// BudgetThresholdsConfigMapKey is the configmap key for the percentages of the budget limit AWS notifies about,
+// comma separated, "80,100" by default
+var BudgetThresholdsConfigMapKey = "budget-thresholds"
+
+// BudgetSNSTopicARNConfigMapKey is the configmap key for the ARN of the SNS topic the budget notifications are
+// sent to. It must be subscribed by the queue of AWSNotificationsQueueURLConfigMapKey, so the notifications set the
+// CostAnomaly condition of the accounts.
+var BudgetSNSTopicARNConfigMapKey = "budget-sns-topic-arn"
+
+// BudgetNamePrefix is the prefix of the names of the AWS Budgets of the claimed accounts, followed by the ID of
+// the AWS account. The payer account sends the notifications, the account they're about is found from the name.
+var BudgetNamePrefix = "aws-account-operator-"
+
+// CostAllocationTagsConfigMapKey is the configmap key enabling the cost allocation tags of the claimed accounts. Set
+// to "true", the clusterID and legalEntityID tag keys are activated in the payer account and the IAM user of the
+// account is tagged with them when it's claimed.
+var CostAllocationTagsConfigMapKey = "cost-allocation-tags"
+
+// GuardDutyAdminAccountIDConfigMapKey is the configmap key for the ID of the GuardDuty delegated administrator account
+// of the organization. When it's set, the accounts of non-CCS claims are enrolled as GuardDuty members of it in the
+// regions of the cluster, and removed when they're reused.
+var GuardDutyAdminAccountIDConfigMapKey = "guardduty-admin-account-id"
+
+// PreflightChecksConfigMapKey is the configmap key for the comma separated Trusted Advisor checks a new or reused
+// account has to pass before it's Ready, among service-limits, exposed-access-keys and s3-public-buckets. No check is
+// run by default.
+var PreflightChecksConfigMapKey = "preflight-checks"
+
+// CloudTrailOrganizationTrailConfigMapKey is the configmap key for the ARN of the organization trail every non-CCS
+// account is expected to be covered by, e.g. arn:aws:cloudtrail:us-east-1:123456789012:trail/org-trail. The trail
+// isn't verified when it's not set.
+var CloudTrailOrganizationTrailConfigMapKey = "cloudtrail-organization-trail-arn"
+
+// CloudTrailCheckIntervalConfigMapKey is the configmap key for how often the organization trail is verified in the
+// Ready non-CCS accounts, e.g. 6h. An interval of 0s disables the verification.
+var CloudTrailCheckIntervalConfigMapKey = "cloudtrail-check-interval"
+
+// CloudTrailRemediationConfigMapKey is the configmap key enabling the remediation of the organization trail. Set to
+// "true", logging is restarted from the payer account when the trail has been stopped.
+var CloudTrailRemediationConfigMapKey = "cloudtrail-remediation"
+
+// ParkingSCPConfigMapKey is the configmap key for the ID of a deny-all service control policy of the organization,
+// e.g. p-abcd1234. The policy is attached to the non-CCS accounts quarantined out of the pool and detached when
+// they're put back into service.
+var ParkingSCPConfigMapKey = "parking-scp-id"
+
+// MaintenanceModeConfigMapKey is the configmap key pausing the mutating AWS operations of the operator, e.g. during
+// AWS incidents. The CRs are still reconciled and their status kept up to date.
+var MaintenanceModeConfigMapKey = "maintenance-mode"
+
+// AWSAccountRateLimitConfigMapKey is the configmap key for how many AWS calls per second the operator makes to a
+// member account, across all controllers
+var AWSAccountRateLimitConfigMapKey = "aws-account-rate-limit"
+
+// AWSAccountRateBurstConfigMapKey is the configmap key for how many AWS calls the operator can make to a member
+// account in a burst, above its rate limit
+var AWSAccountRateBurstConfigMapKey = "aws-account-rate-burst"
@@ -0,0 +1,91 @@
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func TestGetAWSErrorCategory(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		want      AWSErrorCategory
+		retryable bool
+	}{
+		{
+			name:      "Throttling",
+			err:       awserr.New("Throttling", "Rate exceeded", nil),
+			want:      AWSErrorThrottled,
+			retryable: true,
+		},
+		{
+			name:      "Too many requests status",
+			err:       awserr.NewRequestFailure(awserr.New("SlowDownPlease", "", nil), 429, "request-id"),
+			want:      AWSErrorThrottled,
+			retryable: true,
+		},
+		{
+			name: "Wrapped access denied",
+			err:  fmt.Errorf("failed listing S3 buckets: %w", awserr.New("AccessDenied", "Access Denied", nil)),
+			want: AWSErrorAccessDenied,
+		},
+		{
+			name: "Expired token",
+			err:  awserr.New("ExpiredToken", "", nil),
+			want: AWSErrorAccessDenied,
+		},
+		{
+			name:      "Volume in use",
+			err:       awserr.New("VolumeInUse", "", nil),
+			want:      AWSErrorDependencyViolation,
+			retryable: true,
+		},
+		{
+			name:      "Bucket not empty",
+			err:       awserr.New("BucketNotEmpty", "", nil),
+			want:      AWSErrorDependencyViolation,
+			retryable: true,
+		},
+		{
+			name: "EC2 resource not found",
+			err:  awserr.New("InvalidSnapshot.NotFound", "", nil),
+			want: AWSErrorNotFound,
+		},
+		{
+			name: "IAM entity not found",
+			err:  awserr.New("NoSuchEntity", "", nil),
+			want: AWSErrorNotFound,
+		},
+		{
+			name:      "Joined errors",
+			err:       errors.Join(errors.New("snapshots: failed"), fmt.Errorf("s3: %w", awserr.New("DependencyViolation", "", nil))),
+			want:      AWSErrorDependencyViolation,
+			retryable: true,
+		},
+		{
+			name: "Manual action required",
+			err:  fmt.Errorf("s3: %w: bucket-1", ErrManualActionRequired),
+			want: AWSErrorManualActionRequired,
+		},
+		{
+			name:      "Not an AWS error",
+			err:       errors.New("failed"),
+			want:      AWSErrorOther,
+			retryable: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetAWSErrorCategory(tt.err)
+			if got != tt.want {
+				t.Errorf("GetAWSErrorCategory() = %v, want %v", got, tt.want)
+			}
+			if got.Retryable() != tt.retryable {
+				t.Errorf("Retryable() = %v, want %v", got.Retryable(), tt.retryable)
+			}
+		})
+	}
+}
@@ -5,9 +5,113 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSAccountQuota) DeepCopyInto(out *AWSAccountQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSAccountQuota.
+func (in *AWSAccountQuota) DeepCopy() *AWSAccountQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSAccountQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AWSAccountQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSAccountQuotaList) DeepCopyInto(out *AWSAccountQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AWSAccountQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSAccountQuotaList.
+func (in *AWSAccountQuotaList) DeepCopy() *AWSAccountQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSAccountQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AWSAccountQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSAccountQuotaSpec) DeepCopyInto(out *AWSAccountQuotaSpec) {
+	*out = *in
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSAccountQuotaSpec.
+func (in *AWSAccountQuotaSpec) DeepCopy() *AWSAccountQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSAccountQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSAccountQuotaStatus) DeepCopyInto(out *AWSAccountQuotaStatus) {
+	*out = *in
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = make([]TrackedServiceQuota, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSnapshotTime != nil {
+		in, out := &in.LastSnapshotTime, &out.LastSnapshotTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSAccountQuotaStatus.
+func (in *AWSAccountQuotaStatus) DeepCopy() *AWSAccountQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSAccountQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSCustomPolicy) DeepCopyInto(out *AWSCustomPolicy) {
 	*out = *in
@@ -30,12 +134,118 @@ func (in *AWSCustomPolicy) DeepCopy() *AWSCustomPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSFederatedAccessGroup) DeepCopyInto(out *AWSFederatedAccessGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSFederatedAccessGroup.
+func (in *AWSFederatedAccessGroup) DeepCopy() *AWSFederatedAccessGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSFederatedAccessGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AWSFederatedAccessGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSFederatedAccessGroupList) DeepCopyInto(out *AWSFederatedAccessGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AWSFederatedAccessGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSFederatedAccessGroupList.
+func (in *AWSFederatedAccessGroupList) DeepCopy() *AWSFederatedAccessGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSFederatedAccessGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AWSFederatedAccessGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSFederatedAccessGroupSpec) DeepCopyInto(out *AWSFederatedAccessGroupSpec) {
+	*out = *in
+	out.AWSFederatedRole = in.AWSFederatedRole
+	in.AccountSelector.DeepCopyInto(&out.AccountSelector)
+	if in.Expiration != nil {
+		in, out := &in.Expiration, &out.Expiration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSFederatedAccessGroupSpec.
+func (in *AWSFederatedAccessGroupSpec) DeepCopy() *AWSFederatedAccessGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSFederatedAccessGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSFederatedAccessGroupStatus) DeepCopyInto(out *AWSFederatedAccessGroupStatus) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Accounts != nil {
+		in, out := &in.Accounts, &out.Accounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSFederatedAccessGroupStatus.
+func (in *AWSFederatedAccessGroupStatus) DeepCopy() *AWSFederatedAccessGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSFederatedAccessGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSFederatedAccountAccess) DeepCopyInto(out *AWSFederatedAccountAccess) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -111,6 +321,16 @@ func (in *AWSFederatedAccountAccessSpec) DeepCopyInto(out *AWSFederatedAccountAc
 	*out = *in
 	out.AWSCustomerCredentialSecret = in.AWSCustomerCredentialSecret
 	out.AWSFederatedRole = in.AWSFederatedRole
+	if in.Expiration != nil {
+		in, out := &in.Expiration, &out.Expiration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SigninSessionDuration != nil {
+		in, out := &in.SigninSessionDuration, &out.SigninSessionDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSFederatedAccountAccessSpec.
@@ -133,6 +353,11 @@ func (in *AWSFederatedAccountAccessStatus) DeepCopyInto(out *AWSFederatedAccount
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CustomPolicyArns != nil {
+		in, out := &in.CustomPolicyArns, &out.CustomPolicyArns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSFederatedAccountAccessStatus.
@@ -404,7 +629,44 @@ func (in *AccountClaimSpec) DeepCopyInto(out *AccountClaimSpec) {
 	out.AwsCredentialSecret = in.AwsCredentialSecret
 	in.Aws.DeepCopyInto(&out.Aws)
 	out.BYOCSecretRef = in.BYOCSecretRef
+	if in.STSRoles != nil {
+		in, out := &in.STSRoles, &out.STSRoles
+		*out = make([]STSRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.FleetManagerConfig = in.FleetManagerConfig
+	if in.ClusterDeploymentRef != nil {
+		in, out := &in.ClusterDeploymentRef, &out.ClusterDeploymentRef
+		*out = new(ClusterDeploymentRef)
+		**out = **in
+	}
+	if in.CleanupExclusions != nil {
+		in, out := &in.CleanupExclusions, &out.CleanupExclusions
+		*out = new(CleanupExclusions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClaimDeadline != nil {
+		in, out := &in.ClaimDeadline, &out.ClaimDeadline
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Route53Delegation != nil {
+		in, out := &in.Route53Delegation, &out.Route53Delegation
+		*out = new(Route53Delegation)
+		**out = **in
+	}
+	if in.TransferTo != nil {
+		in, out := &in.TransferTo, &out.TransferTo
+		*out = new(ClaimTransfer)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountClaimSpec.
@@ -427,6 +689,21 @@ func (in *AccountClaimStatus) DeepCopyInto(out *AccountClaimStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.StateTransition != nil {
+		in, out := &in.StateTransition, &out.StateTransition
+		*out = new(StateTransition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.STSRoles != nil {
+		in, out := &in.STSRoles, &out.STSRoles
+		*out = make([]ProvisionedSTSRole, len(*in))
+		copy(*out, *in)
+	}
+	if in.IdentityCenterAssignments != nil {
+		in, out := &in.IdentityCenterAssignments, &out.IdentityCenterAssignments
+		*out = make([]IdentityCenterAssignment, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountClaimStatus.
@@ -440,48 +717,58 @@ func (in *AccountClaimStatus) DeepCopy() *AccountClaimStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AccountCondition) DeepCopyInto(out *AccountCondition) {
+func (in *AccountCleanup) DeepCopyInto(out *AccountCleanup) {
 	*out = *in
-	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountCondition.
-func (in *AccountCondition) DeepCopy() *AccountCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountCleanup.
+func (in *AccountCleanup) DeepCopy() *AccountCleanup {
 	if in == nil {
 		return nil
 	}
-	out := new(AccountCondition)
+	out := new(AccountCleanup)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccountCleanup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AccountList) DeepCopyInto(out *AccountList) {
+func (in *AccountCleanupList) DeepCopyInto(out *AccountCleanupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Account, len(*in))
+		*out = make([]AccountCleanup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountList.
-func (in *AccountList) DeepCopy() *AccountList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountCleanupList.
+func (in *AccountCleanupList) DeepCopy() *AccountCleanupList {
 	if in == nil {
 		return nil
 	}
-	out := new(AccountList)
+	out := new(AccountCleanupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AccountList) DeepCopyObject() runtime.Object {
+func (in *AccountCleanupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -489,66 +776,358 @@ func (in *AccountList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AccountPool) DeepCopyInto(out *AccountPool) {
+func (in *AccountCleanupSpec) DeepCopyInto(out *AccountCleanupSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.LegalEntity = in.LegalEntity
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Exclusions.DeepCopyInto(&out.Exclusions)
+	if in.Route53Delegation != nil {
+		in, out := &in.Route53Delegation, &out.Route53Delegation
+		*out = new(Route53Delegation)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountPool.
-func (in *AccountPool) DeepCopy() *AccountPool {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountCleanupSpec.
+func (in *AccountCleanupSpec) DeepCopy() *AccountCleanupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(AccountPool)
+	out := new(AccountCleanupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AccountPool) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AccountPoolList) DeepCopyInto(out *AccountPoolList) {
+func (in *AccountCleanupStatus) DeepCopyInto(out *AccountCleanupStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]AccountPool, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.CompletedSteps != nil {
+		in, out := &in.CompletedSteps, &out.CompletedSteps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreservedResources != nil {
+		in, out := &in.PreservedResources, &out.PreservedResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountPoolList.
-func (in *AccountPoolList) DeepCopy() *AccountPoolList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountCleanupStatus.
+func (in *AccountCleanupStatus) DeepCopy() *AccountCleanupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(AccountPoolList)
+	out := new(AccountCleanupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AccountPoolList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AccountPoolSpec) DeepCopyInto(out *AccountPoolSpec) {
+func (in *AccountCondition) DeepCopyInto(out *AccountCondition) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountCondition.
+func (in *AccountCondition) DeepCopy() *AccountCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountList) DeepCopyInto(out *AccountList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Account, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountList.
+func (in *AccountList) DeepCopy() *AccountList {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountOperatorStatus) DeepCopyInto(out *AccountOperatorStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountOperatorStatus.
+func (in *AccountOperatorStatus) DeepCopy() *AccountOperatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountOperatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccountOperatorStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountOperatorStatusList) DeepCopyInto(out *AccountOperatorStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AccountOperatorStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountOperatorStatusList.
+func (in *AccountOperatorStatusList) DeepCopy() *AccountOperatorStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountOperatorStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccountOperatorStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountOperatorStatusStatus) DeepCopyInto(out *AccountOperatorStatusStatus) {
+	*out = *in
+	if in.AccountsByState != nil {
+		in, out := &in.AccountsByState, &out.AccountsByState
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.QuarantinedAccounts != nil {
+		in, out := &in.QuarantinedAccounts, &out.QuarantinedAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OldestStuckAccount != nil {
+		in, out := &in.OldestStuckAccount, &out.OldestStuckAccount
+		*out = new(StuckAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastSuccessfulCreation != nil {
+		in, out := &in.LastSuccessfulCreation, &out.LastSuccessfulCreation
+		*out = (*in).DeepCopy()
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountOperatorStatusStatus.
+func (in *AccountOperatorStatusStatus) DeepCopy() *AccountOperatorStatusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountOperatorStatusStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountPool) DeepCopyInto(out *AccountPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountPool.
+func (in *AccountPool) DeepCopy() *AccountPool {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccountPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountPoolList) DeepCopyInto(out *AccountPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AccountPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountPoolList.
+func (in *AccountPoolList) DeepCopy() *AccountPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccountPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountPoolPolicy) DeepCopyInto(out *AccountPoolPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountPoolPolicy.
+func (in *AccountPoolPolicy) DeepCopy() *AccountPoolPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountPoolPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccountPoolPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountPoolPolicyList) DeepCopyInto(out *AccountPoolPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AccountPoolPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountPoolPolicyList.
+func (in *AccountPoolPolicyList) DeepCopy() *AccountPoolPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountPoolPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccountPoolPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountPoolPolicySpec) DeepCopyInto(out *AccountPoolPolicySpec) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]PoolSizeWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountPoolPolicySpec.
+func (in *AccountPoolPolicySpec) DeepCopy() *AccountPoolPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountPoolPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountPoolSpec) DeepCopyInto(out *AccountPoolSpec) {
 	*out = *in
 }
 
@@ -691,113 +1270,457 @@ func (in *AccountStatus) DeepCopyInto(out *AccountStatus) {
 		in, out := &in.OptInRegions, &out.OptInRegions
 		*out = make(OptInRegions, len(*in))
 		for key, val := range *in {
-			var outVal *OptInRegionStatus
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				inVal := (*in)[key]
-				in, out := &inVal, &outVal
-				*out = new(OptInRegionStatus)
-				**out = **in
-			}
-			(*out)[key] = outVal
+			var outVal *OptInRegionStatus
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = new(OptInRegionStatus)
+				**out = **in
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.StateTransition != nil {
+		in, out := &in.StateTransition, &out.StateTransition
+		*out = new(StateTransition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AccessKeyCreationTime != nil {
+		in, out := &in.AccessKeyCreationTime, &out.AccessKeyCreationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.OperatorResources != nil {
+		in, out := &in.OperatorResources, &out.OperatorResources
+		*out = new(OperatorResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Phase != nil {
+		in, out := &in.Phase, &out.Phase
+		*out = new(PhaseStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RegionInitialization != nil {
+		in, out := &in.RegionInitialization, &out.RegionInitialization
+		*out = make([]RegionInitializationStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.SupportCase != nil {
+		in, out := &in.SupportCase, &out.SupportCase
+		*out = new(SupportCaseStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterDeployment != nil {
+		in, out := &in.ClusterDeployment, &out.ClusterDeployment
+		*out = new(ClusterDeploymentStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceControlPolicies != nil {
+		in, out := &in.ServiceControlPolicies, &out.ServiceControlPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountStatus.
+func (in *AccountStatus) DeepCopy() *AccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AmiSpec) DeepCopyInto(out *AmiSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmiSpec.
+func (in *AmiSpec) DeepCopy() *AmiSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AmiSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Aws) DeepCopyInto(out *Aws) {
+	*out = *in
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]AwsRegions, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Aws.
+func (in *Aws) DeepCopy() *Aws {
+	if in == nil {
+		return nil
+	}
+	out := new(Aws)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AwsRegions) DeepCopyInto(out *AwsRegions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AwsRegions.
+func (in *AwsRegions) DeepCopy() *AwsRegions {
+	if in == nil {
+		return nil
+	}
+	out := new(AwsRegions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClaimTransfer) DeepCopyInto(out *ClaimTransfer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimTransfer.
+func (in *ClaimTransfer) DeepCopy() *ClaimTransfer {
+	if in == nil {
+		return nil
+	}
+	out := new(ClaimTransfer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupExclusions) DeepCopyInto(out *CleanupExclusions) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceARNs != nil {
+		in, out := &in.ResourceARNs, &out.ResourceARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupExclusions.
+func (in *CleanupExclusions) DeepCopy() *CleanupExclusions {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupExclusions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupVerification) DeepCopyInto(out *CleanupVerification) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupVerification.
+func (in *CleanupVerification) DeepCopy() *CleanupVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CleanupVerification) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupVerificationList) DeepCopyInto(out *CleanupVerificationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CleanupVerification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupVerificationList.
+func (in *CleanupVerificationList) DeepCopy() *CleanupVerificationList {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupVerificationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CleanupVerificationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupVerificationSpec) DeepCopyInto(out *CleanupVerificationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupVerificationSpec.
+func (in *CleanupVerificationSpec) DeepCopy() *CleanupVerificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupVerificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupVerificationStatus) DeepCopyInto(out *CleanupVerificationStatus) {
+	*out = *in
+	if in.SeededResources != nil {
+		in, out := &in.SeededResources, &out.SeededResources
+		*out = make([]SeededResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemainingResources != nil {
+		in, out := &in.RemainingResources, &out.RemainingResources
+		*out = make([]SeededResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupVerificationStatus.
+func (in *CleanupVerificationStatus) DeepCopy() *CleanupVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDeploymentRef) DeepCopyInto(out *ClusterDeploymentRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentRef.
+func (in *ClusterDeploymentRef) DeepCopy() *ClusterDeploymentRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDeploymentRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDeploymentStatus) DeepCopyInto(out *ClusterDeploymentStatus) {
+	*out = *in
+	if in.DeletionTimestamp != nil {
+		in, out := &in.DeletionTimestamp, &out.DeletionTimestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentStatus.
+func (in *ClusterDeploymentStatus) DeepCopy() *ClusterDeploymentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDeploymentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	if in.StringEquals != nil {
+		in, out := &in.StringEquals, &out.StringEquals
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountStatus.
-func (in *AccountStatus) DeepCopy() *AccountStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
 	if in == nil {
 		return nil
 	}
-	out := new(AccountStatus)
+	out := new(Condition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AmiSpec) DeepCopyInto(out *AmiSpec) {
+func (in *FleetManagerConfig) DeepCopyInto(out *FleetManagerConfig) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmiSpec.
-func (in *AmiSpec) DeepCopy() *AmiSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetManagerConfig.
+func (in *FleetManagerConfig) DeepCopy() *FleetManagerConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(AmiSpec)
+	out := new(FleetManagerConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Aws) DeepCopyInto(out *Aws) {
+func (in *IAMUserRequest) DeepCopyInto(out *IAMUserRequest) {
 	*out = *in
-	if in.Regions != nil {
-		in, out := &in.Regions, &out.Regions
-		*out = make([]AwsRegions, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMUserRequest.
+func (in *IAMUserRequest) DeepCopy() *IAMUserRequest {
+	if in == nil {
+		return nil
 	}
+	out := new(IAMUserRequest)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Aws.
-func (in *Aws) DeepCopy() *Aws {
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IAMUserRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IAMUserRequestList) DeepCopyInto(out *IAMUserRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IAMUserRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMUserRequestList.
+func (in *IAMUserRequestList) DeepCopy() *IAMUserRequestList {
 	if in == nil {
 		return nil
 	}
-	out := new(Aws)
+	out := new(IAMUserRequestList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IAMUserRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AwsRegions) DeepCopyInto(out *AwsRegions) {
+func (in *IAMUserRequestSpec) DeepCopyInto(out *IAMUserRequestSpec) {
 	*out = *in
+	if in.TrustedARNs != nil {
+		in, out := &in.TrustedARNs, &out.TrustedARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedPolicyARNs != nil {
+		in, out := &in.ManagedPolicyARNs, &out.ManagedPolicyARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InlinePolicy != nil {
+		in, out := &in.InlinePolicy, &out.InlinePolicy
+		*out = new(AWSCustomPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AwsRegions.
-func (in *AwsRegions) DeepCopy() *AwsRegions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMUserRequestSpec.
+func (in *IAMUserRequestSpec) DeepCopy() *IAMUserRequestSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(AwsRegions)
+	out := new(IAMUserRequestSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Condition) DeepCopyInto(out *Condition) {
+func (in *IAMUserRequestStatus) DeepCopyInto(out *IAMUserRequestStatus) {
 	*out = *in
-	if in.StringEquals != nil {
-		in, out := &in.StringEquals, &out.StringEquals
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SecretRef)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
-func (in *Condition) DeepCopy() *Condition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMUserRequestStatus.
+func (in *IAMUserRequestStatus) DeepCopy() *IAMUserRequestStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Condition)
+	out := new(IAMUserRequestStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FleetManagerConfig) DeepCopyInto(out *FleetManagerConfig) {
+func (in *IdentityCenterAssignment) DeepCopyInto(out *IdentityCenterAssignment) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetManagerConfig.
-func (in *FleetManagerConfig) DeepCopy() *FleetManagerConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityCenterAssignment.
+func (in *IdentityCenterAssignment) DeepCopy() *IdentityCenterAssignment {
 	if in == nil {
 		return nil
 	}
-	out := new(FleetManagerConfig)
+	out := new(IdentityCenterAssignment)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -817,6 +1740,36 @@ func (in *LegalEntity) DeepCopy() *LegalEntity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorResources) DeepCopyInto(out *OperatorResources) {
+	*out = *in
+	if in.IAMUsers != nil {
+		in, out := &in.IAMUsers, &out.IAMUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IAMRoles != nil {
+		in, out := &in.IAMRoles, &out.IAMRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorResources.
+func (in *OperatorResources) DeepCopy() *OperatorResources {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OptInRegionStatus) DeepCopyInto(out *OptInRegionStatus) {
 	*out = *in
@@ -862,6 +1815,46 @@ func (in OptInRegions) DeepCopy() OptInRegions {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhaseStatus) DeepCopyInto(out *PhaseStatus) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+	if in.NextAttemptTime != nil {
+		in, out := &in.NextAttemptTime, &out.NextAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhaseStatus.
+func (in *PhaseStatus) DeepCopy() *PhaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PhaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolSizeWindow) DeepCopyInto(out *PoolSizeWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolSizeWindow.
+func (in *PoolSizeWindow) DeepCopy() *PoolSizeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolSizeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Principal) DeepCopyInto(out *Principal) {
 	*out = *in
@@ -882,6 +1875,36 @@ func (in *Principal) DeepCopy() *Principal {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisionedSTSRole) DeepCopyInto(out *ProvisionedSTSRole) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisionedSTSRole.
+func (in *ProvisionedSTSRole) DeepCopy() *ProvisionedSTSRole {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisionedSTSRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegionInitializationStatus) DeepCopyInto(out *RegionInitializationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegionInitializationStatus.
+func (in *RegionInitializationStatus) DeepCopy() *RegionInitializationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RegionInitializationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in RegionalServiceQuotas) DeepCopyInto(out *RegionalServiceQuotas) {
 	{
@@ -923,6 +1946,46 @@ func (in RegionalServiceQuotas) DeepCopy() RegionalServiceQuotas {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Route53Delegation) DeepCopyInto(out *Route53Delegation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Route53Delegation.
+func (in *Route53Delegation) DeepCopy() *Route53Delegation {
+	if in == nil {
+		return nil
+	}
+	out := new(Route53Delegation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *STSRole) DeepCopyInto(out *STSRole) {
+	*out = *in
+	if in.TrustedARNs != nil {
+		in, out := &in.TrustedARNs, &out.TrustedARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PolicyARNs != nil {
+		in, out := &in.PolicyARNs, &out.PolicyARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new STSRole.
+func (in *STSRole) DeepCopy() *STSRole {
+	if in == nil {
+		return nil
+	}
+	out := new(STSRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretRef) DeepCopyInto(out *SecretRef) {
 	*out = *in
@@ -938,6 +2001,21 @@ func (in *SecretRef) DeepCopy() *SecretRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeededResource) DeepCopyInto(out *SeededResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeededResource.
+func (in *SeededResource) DeepCopy() *SeededResource {
+	if in == nil {
+		return nil
+	}
+	out := new(SeededResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceQuotaStatus) DeepCopyInto(out *ServiceQuotaStatus) {
 	*out = *in
@@ -953,6 +2031,22 @@ func (in *ServiceQuotaStatus) DeepCopy() *ServiceQuotaStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateTransition) DeepCopyInto(out *StateTransition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateTransition.
+func (in *StateTransition) DeepCopy() *StateTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(StateTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatementEntry) DeepCopyInto(out *StatementEntry) {
 	*out = *in
@@ -987,3 +2081,57 @@ func (in *StatementEntry) DeepCopy() *StatementEntry {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StuckAccount) DeepCopyInto(out *StuckAccount) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StuckAccount.
+func (in *StuckAccount) DeepCopy() *StuckAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(StuckAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportCaseStatus) DeepCopyInto(out *SupportCaseStatus) {
+	*out = *in
+	if in.CreationTime != nil {
+		in, out := &in.CreationTime, &out.CreationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ResolutionTime != nil {
+		in, out := &in.ResolutionTime, &out.ResolutionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportCaseStatus.
+func (in *SupportCaseStatus) DeepCopy() *SupportCaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SupportCaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrackedServiceQuota) DeepCopyInto(out *TrackedServiceQuota) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrackedServiceQuota.
+func (in *TrackedServiceQuota) DeepCopy() *TrackedServiceQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(TrackedServiceQuota)
+	in.DeepCopyInto(out)
+	return out
+}
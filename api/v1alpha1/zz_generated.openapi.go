@@ -14,6 +14,9 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
+		"github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedAccessGroup":         schema_openshift_aws_account_operator_api_v1alpha1_AWSFederatedAccessGroup(ref),
+		"github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedAccessGroupSpec":     schema_openshift_aws_account_operator_api_v1alpha1_AWSFederatedAccessGroupSpec(ref),
+		"github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedAccessGroupStatus":   schema_openshift_aws_account_operator_api_v1alpha1_AWSFederatedAccessGroupStatus(ref),
 		"github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedAccountAccess":       schema_openshift_aws_account_operator_api_v1alpha1_AWSFederatedAccountAccess(ref),
 		"github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedAccountAccessSpec":   schema_openshift_aws_account_operator_api_v1alpha1_AWSFederatedAccountAccessSpec(ref),
 		"github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedAccountAccessStatus": schema_openshift_aws_account_operator_api_v1alpha1_AWSFederatedAccountAccessStatus(ref),
@@ -33,6 +36,157 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 	}
 }
 
+func schema_openshift_aws_account_operator_api_v1alpha1_AWSFederatedAccessGroup(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AWSFederatedAccessGroup is the Schema for the awsfederatedaccessgroups API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedAccessGroupSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedAccessGroupStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedAccessGroupSpec", "github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedAccessGroupStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_openshift_aws_account_operator_api_v1alpha1_AWSFederatedAccessGroupSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AWSFederatedAccessGroupSpec defines the desired state of AWSFederatedAccessGroup",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"groupName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GroupName is the name of the OpenShift Group, synced from SSO or LDAP, whose members are granted access",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"iamARNTemplate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IAMARNTemplate is a Go template of the external AWS IAM ARN of a group member, e.g. arn:aws:iam::123456789012:user/{{.User}}",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"awsFederatedRole": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AWSFederatedRole is the federated role granted to the members of the group",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedRoleRef"),
+						},
+					},
+					"accountSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AccountSelector selects the Account CRs the members of the group are granted access to",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"expiration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Expiration is set on the AWSFederatedAccountAccesses of the group",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"groupName", "iamARNTemplate", "awsFederatedRole", "accountSelector"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedRoleRef", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
+func schema_openshift_aws_account_operator_api_v1alpha1_AWSFederatedAccessGroupStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AWSFederatedAccessGroupStatus defines the observed state of AWSFederatedAccessGroup",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"members": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Members are the users of the group that were granted access",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"accounts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Accounts are the Account CRs the members were granted access to",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"accountAccesses": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AccountAccesses is the number of AWSFederatedAccountAccesses of the group",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"accountAccesses"},
+			},
+		},
+	}
+}
+
 func schema_openshift_aws_account_operator_api_v1alpha1_AWSFederatedAccountAccess(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -109,12 +263,24 @@ func schema_openshift_aws_account_operator_api_v1alpha1_AWSFederatedAccountAcces
 							Ref:         ref("github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedRoleRef"),
 						},
 					},
+					"expiration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Expiration is how long after its creation the access is revoked, e.g. 8h. Access never expires when unset.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"signinSessionDuration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SigninSessionDuration is how long the console sessions opened with the sign-in URL of the access last, between 15m and 36h. Defaults to 1h.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
 				},
 				Required: []string{"externalCustomerAWSIAMARN", "awsCustomerCredentialSecret", "awsFederatedRole"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedRoleRef", "github.com/openshift/aws-account-operator/api/v1alpha1.AWSSecretReference"},
+			"github.com/openshift/aws-account-operator/api/v1alpha1.AWSFederatedRoleRef", "github.com/openshift/aws-account-operator/api/v1alpha1.AWSSecretReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
@@ -159,6 +325,28 @@ func schema_openshift_aws_account_operator_api_v1alpha1_AWSFederatedAccountAcces
 							Format: "",
 						},
 					},
+					"customPolicyArns": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CustomPolicyArns are the ARNs of the parts of the custom policy created for the role, deleted with it",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"signinURLSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SigninURLSecret is the name of the secret, in the namespace of the account access, holding a console sign-in URL scoped to the policies of the role",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"conditions", "state"},
 			},
@@ -560,12 +748,32 @@ func schema_openshift_aws_account_operator_api_v1alpha1_AccountClaimStatus(ref c
 							Format:  "",
 						},
 					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObservedGeneration is the most recent generation of the AccountClaim that was reconciled without error",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"reconcileFailures": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReconcileFailures counts the consecutive failed reconciles of the AccountClaim",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"stateTransition": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StateTransition records when the AccountClaim entered its current state",
+							Ref:         ref("github.com/openshift/aws-account-operator/api/v1alpha1.StateTransition"),
+						},
+					},
 				},
 				Required: []string{"conditions", "state"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/openshift/aws-account-operator/api/v1alpha1.AccountClaimCondition"},
+			"github.com/openshift/aws-account-operator/api/v1alpha1.AccountClaimCondition", "github.com/openshift/aws-account-operator/api/v1alpha1.StateTransition"},
 	}
 }
 
@@ -793,6 +1001,20 @@ func schema_openshift_aws_account_operator_api_v1alpha1_AccountSpec(ref common.R
 							Format: "",
 						},
 					},
+					"claimLinkUID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClaimLinkUID is the UID of the AccountClaim the account is claimed by",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClusterID is the ID of the cluster the account is claimed for, taken from the AccountClaim's api.openshift.com/id label",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"legalEntity": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
@@ -931,10 +1153,36 @@ func schema_openshift_aws_account_operator_api_v1alpha1_AccountStatus(ref common
 							},
 						},
 					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObservedGeneration is the most recent generation of the Account that was reconciled without error",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"reconcileFailures": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReconcileFailures counts the consecutive failed reconciles of the Account",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"stateTransition": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StateTransition records when the Account entered its current state",
+							Ref:         ref("github.com/openshift/aws-account-operator/api/v1alpha1.StateTransition"),
+						},
+					},
+					"accessKeyCreationTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AccessKeyCreationTime is when the access key in the IAM user secret was created",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/openshift/aws-account-operator/api/v1alpha1.AccountCondition", "github.com/openshift/aws-account-operator/api/v1alpha1.OptInRegionStatus", "github.com/openshift/aws-account-operator/api/v1alpha1.ServiceQuotaStatus"},
+			"github.com/openshift/aws-account-operator/api/v1alpha1.AccountCondition", "github.com/openshift/aws-account-operator/api/v1alpha1.OptInRegionStatus", "github.com/openshift/aws-account-operator/api/v1alpha1.ServiceQuotaStatus", "github.com/openshift/aws-account-operator/api/v1alpha1.StateTransition", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
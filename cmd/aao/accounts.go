@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+)
+
+// cleanupReportKey is the key of the report in the ConfigMap of a cleanup report
+const cleanupReportKey = "report.json"
+
+// accountSummary is what's listed of an account
+type accountSummary struct {
+	Name         string `json:"name"`
+	AwsAccountID string `json:"awsAccountID"`
+	State        string `json:"state"`
+	// Claim is the namespace/name of the claim of the account, empty when it's unclaimed
+	Claim string `json:"claim,omitempty"`
+	// Reuses counts the completed cleanups of the account, each returned it to the pool
+	Reuses int `json:"reuses"`
+	// Conditions are the types of the true conditions of the account
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// listAccounts returns the summaries of the accounts in the state, of all of them when it's empty, sorted by name
+func listAccounts(ctx context.Context, kubeClient client.Client, state string) ([]accountSummary, error) {
+	accounts := &awsv1alpha1.AccountList{}
+	if err := kubeClient.List(ctx, accounts, client.InNamespace(awsv1alpha1.AccountCrNamespace)); err != nil {
+		return nil, fmt.Errorf("failed to list the accounts: %w", err)
+	}
+	cleanups := &awsv1alpha1.AccountCleanupList{}
+	if err := kubeClient.List(ctx, cleanups, client.InNamespace(awsv1alpha1.AccountCrNamespace)); err != nil {
+		return nil, fmt.Errorf("failed to list the account cleanups: %w", err)
+	}
+	reuses := map[string]int{}
+	for _, cleanup := range cleanups.Items {
+		if cleanup.Status.State == awsv1alpha1.AccountCleanupCompleted {
+			reuses[cleanup.Spec.AccountLink]++
+		}
+	}
+
+	summaries := []accountSummary{}
+	for _, account := range accounts.Items {
+		if state != "" && !strings.EqualFold(account.Status.State, state) {
+			continue
+		}
+		summary := accountSummary{
+			Name:         account.Name,
+			AwsAccountID: account.Spec.AwsAccountID,
+			State:        account.Status.State,
+			Reuses:       reuses[account.Name],
+		}
+		if account.Spec.ClaimLink != "" {
+			summary.Claim = account.Spec.ClaimLinkNamespace + "/" + account.Spec.ClaimLink
+		}
+		for _, condition := range account.Status.Conditions {
+			if condition.Status == corev1.ConditionTrue {
+				summary.Conditions = append(summary.Conditions, string(condition.Type))
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries, nil
+}
+
+// printTable prints the summaries as a table, like oc get
+func printTable(out io.Writer, summaries []accountSummary) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tAWS ACCOUNT\tSTATE\tCLAIM\tREUSES\tCONDITIONS")
+	for _, summary := range summaries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", summary.Name, summary.AwsAccountID, orNone(summary.State),
+			orNone(summary.Claim), summary.Reuses, orNone(strings.Join(summary.Conditions, ",")))
+	}
+	return w.Flush()
+}
+
+// printJSON prints the summaries, or owners, as JSON, for jq
+func printJSON(out io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
+
+// cleanupReport returns the report of the last cleanup of the account, found through the cleanup report annotation
+func cleanupReport(ctx context.Context, kubeClient client.Client, accountName string) (string, error) {
+	account := &awsv1alpha1.Account{}
+	err := kubeClient.Get(ctx, client.ObjectKey{Name: accountName, Namespace: awsv1alpha1.AccountCrNamespace}, account)
+	if err != nil {
+		return "", fmt.Errorf("failed to get account %s: %w", accountName, err)
+	}
+	annotation, ok := account.Annotations[awsv1alpha1.CleanupReportAnnotation]
+	if !ok {
+		return "", fmt.Errorf("account %s has no cleanup report", accountName)
+	}
+	summary := struct {
+		ConfigMap string `json:"configMap"`
+	}{}
+	if err = json.Unmarshal([]byte(annotation), &summary); err != nil {
+		return "", fmt.Errorf("invalid cleanup report annotation on account %s: %w", accountName, err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err = kubeClient.Get(ctx, client.ObjectKey{Name: summary.ConfigMap, Namespace: awsv1alpha1.AccountCrNamespace}, configMap)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return "", fmt.Errorf("the cleanup report %s of account %s was deleted with its AccountCleanup", summary.ConfigMap, accountName)
+		}
+		return "", fmt.Errorf("failed to get the cleanup report %s: %w", summary.ConfigMap, err)
+	}
+	return configMap.Data[cleanupReportKey], nil
+}
+
+// annotateAccount sets an annotation of the account requesting an operation, the operator refuses the request with
+// an event if it isn't safe
+func annotateAccount(ctx context.Context, kubeClient client.Client, accountName string, annotation string, value string) error {
+	account := &awsv1alpha1.Account{}
+	err := kubeClient.Get(ctx, client.ObjectKey{Name: accountName, Namespace: awsv1alpha1.AccountCrNamespace}, account)
+	if err != nil {
+		return fmt.Errorf("failed to get account %s: %w", accountName, err)
+	}
+	patch := client.MergeFrom(account.DeepCopy())
+	if account.Annotations == nil {
+		account.Annotations = map[string]string{}
+	}
+	account.Annotations[annotation] = value
+	if err = kubeClient.Patch(ctx, account, patch); err != nil {
+		return fmt.Errorf("failed to annotate account %s: %w", accountName, err)
+	}
+	return nil
+}
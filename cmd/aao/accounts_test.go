@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+)
+
+func newTestClient(t *testing.T, objs ...runtime.Object) client.Client {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatalf("failed adding apis to scheme: %v", err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
+}
+
+func newAccount(name, state string) *awsv1alpha1.Account {
+	return &awsv1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: awsv1alpha1.AccountCrNamespace},
+		Spec:       awsv1alpha1.AccountSpec{AwsAccountID: "123456789012"},
+		Status:     awsv1alpha1.AccountStatus{State: state},
+	}
+}
+
+func newCleanup(name, account string, state awsv1alpha1.AccountCleanupState) *awsv1alpha1.AccountCleanup {
+	return &awsv1alpha1.AccountCleanup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: awsv1alpha1.AccountCrNamespace},
+		Spec:       awsv1alpha1.AccountCleanupSpec{AccountLink: account},
+		Status:     awsv1alpha1.AccountCleanupStatus{State: state},
+	}
+}
+
+func TestListAccounts(t *testing.T) {
+	claimed := newAccount("osd-creds-mgmt-bbb", string(awsv1alpha1.AccountReady))
+	claimed.Spec.ClaimLink = "mycluster"
+	claimed.Spec.ClaimLinkNamespace = "uhc-production-1234"
+	claimed.Status.Conditions = []awsv1alpha1.AccountCondition{
+		{Type: awsv1alpha1.AccountReady, Status: corev1.ConditionTrue},
+		{Type: awsv1alpha1.AccountReused, Status: corev1.ConditionTrue},
+		{Type: awsv1alpha1.AccountFailed, Status: corev1.ConditionFalse},
+	}
+	kubeClient := newTestClient(t,
+		claimed,
+		newAccount("osd-creds-mgmt-aaa", string(awsv1alpha1.AccountFailed)),
+		newCleanup("bbb-1", "osd-creds-mgmt-bbb", awsv1alpha1.AccountCleanupCompleted),
+		newCleanup("bbb-2", "osd-creds-mgmt-bbb", awsv1alpha1.AccountCleanupCompleted),
+		newCleanup("aaa-1", "osd-creds-mgmt-aaa", awsv1alpha1.AccountCleanupFailed),
+	)
+
+	summaries, err := listAccounts(context.TODO(), kubeClient, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []accountSummary{
+		{Name: "osd-creds-mgmt-aaa", AwsAccountID: "123456789012", State: "Failed"},
+		{Name: "osd-creds-mgmt-bbb", AwsAccountID: "123456789012", State: "Ready", Claim: "uhc-production-1234/mycluster", Reuses: 2, Conditions: []string{"Ready", "Reused"}},
+	}, summaries)
+
+	summaries, err = listAccounts(context.TODO(), kubeClient, "failed")
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 1)
+	assert.Equal(t, "osd-creds-mgmt-aaa", summaries[0].Name)
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printTable(out, summaries))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, []string{"osd-creds-mgmt-aaa", "123456789012", "Failed", "<none>", "0", "<none>"}, strings.Fields(lines[1]))
+}
+
+func TestCleanupReport(t *testing.T) {
+	account := newAccount("osd-creds-mgmt-aaa", string(awsv1alpha1.AccountReady))
+	account.Annotations = map[string]string{awsv1alpha1.CleanupReportAnnotation: `{"configMap":"aaa-1-report","deleted":3}`}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "aaa-1-report", Namespace: awsv1alpha1.AccountCrNamespace},
+		Data:       map[string]string{cleanupReportKey: `{"account":"osd-creds-mgmt-aaa"}`},
+	}
+	kubeClient := newTestClient(t, account, configMap, newAccount("osd-creds-mgmt-bbb", string(awsv1alpha1.AccountReady)))
+
+	report, err := cleanupReport(context.TODO(), kubeClient, "osd-creds-mgmt-aaa")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"account":"osd-creds-mgmt-aaa"}`, report)
+
+	_, err = cleanupReport(context.TODO(), kubeClient, "osd-creds-mgmt-bbb")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no cleanup report")
+}
+
+func TestAnnotateAccount(t *testing.T) {
+	kubeClient := newTestClient(t, newAccount("osd-creds-mgmt-aaa", string(awsv1alpha1.AccountFailed)))
+
+	assert.NoError(t, annotateAccount(context.TODO(), kubeClient, "osd-creds-mgmt-aaa", awsv1alpha1.RepoolAnnotation, "OHSS-1234"))
+	assert.NoError(t, annotateAccount(context.TODO(), kubeClient, "osd-creds-mgmt-aaa", awsv1alpha1.CleanupAnnotation, ""))
+	assert.NoError(t, annotateAccount(context.TODO(), kubeClient, "osd-creds-mgmt-aaa", awsv1alpha1.RebuildIAMAnnotation, "OHSS-5678"))
+	assert.NoError(t, annotateAccount(context.TODO(), kubeClient, "osd-creds-mgmt-aaa", awsv1alpha1.ReassignLegalEntityAnnotation, "2b3c4d/Right Corp"))
+	account := &awsv1alpha1.Account{}
+	assert.NoError(t, kubeClient.Get(context.TODO(), client.ObjectKey{Name: "osd-creds-mgmt-aaa", Namespace: awsv1alpha1.AccountCrNamespace}, account))
+	assert.Equal(t, "OHSS-1234", account.Annotations[awsv1alpha1.RepoolAnnotation])
+	assert.Contains(t, account.Annotations, awsv1alpha1.CleanupAnnotation)
+	assert.Equal(t, "OHSS-5678", account.Annotations[awsv1alpha1.RebuildIAMAnnotation])
+	assert.Equal(t, "2b3c4d/Right Corp", account.Annotations[awsv1alpha1.ReassignLegalEntityAnnotation])
+
+	assert.Error(t, annotateAccount(context.TODO(), kubeClient, "osd-creds-mgmt-bbb", awsv1alpha1.RepoolAnnotation, "OHSS-1234"))
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/controllers/account"
+	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+const (
+	// federationTimeout is how long the federation endpoint is given to return a sign-in token
+	federationTimeout = 10 * time.Second
+	// minRoleSessionNameLength is the minimum length of a role session name AWS accepts
+	minRoleSessionNameLength = 2
+	maxRoleSessionNameLength = 64
+)
+
+var invalidRoleSessionNameChars = regexp.MustCompile(`[^\w+=,.@-]`)
+
+// roleAssumer assumes IAM roles, it's implemented by the STS client
+type roleAssumer interface {
+	AssumeRole(*sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error)
+}
+
+// sreAccessRole is the SRE access role of an account, as the operator manages it
+type sreAccessRole struct {
+	account    *awsv1alpha1.Account
+	roleARN    string
+	externalID string
+}
+
+// findAccount returns the Account of the name, or the Account claimed for the cluster when the cluster ID is set
+func findAccount(ctx context.Context, kubeClient client.Client, name string, clusterID string) (*awsv1alpha1.Account, error) {
+	if clusterID == "" {
+		found := &awsv1alpha1.Account{}
+		err := kubeClient.Get(ctx, client.ObjectKey{Name: name, Namespace: awsv1alpha1.AccountCrNamespace}, found)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account %s: %w", name, err)
+		}
+		return found, nil
+	}
+
+	accounts := &awsv1alpha1.AccountList{}
+	if err := kubeClient.List(ctx, accounts, client.InNamespace(awsv1alpha1.AccountCrNamespace)); err != nil {
+		return nil, fmt.Errorf("failed to list the accounts: %w", err)
+	}
+	for i := range accounts.Items {
+		found := &accounts.Items[i]
+		if found.Spec.ClusterID == clusterID || (found.Status.ClusterDeployment != nil && found.Status.ClusterDeployment.ClusterID == clusterID) {
+			return found, nil
+		}
+	}
+	return nil, fmt.Errorf("no account is claimed for cluster %s", clusterID)
+}
+
+// getSREAccessRole returns the SRE access role of the account, with the external ID the operator configmap requires
+// to assume it
+func getSREAccessRole(kubeClient client.Client, sreAccount *awsv1alpha1.Account) (*sreAccessRole, error) {
+	if sreAccount.IsBYOC() || sreAccount.IsSTS() {
+		return nil, fmt.Errorf("account %s is a CCS or STS account, the operator doesn't manage its SRE access role", sreAccount.Name)
+	}
+	if sreAccount.Spec.AwsAccountID == "" {
+		return nil, fmt.Errorf("account %s has no AWS account yet", sreAccount.Name)
+	}
+
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the operator configmap: %w", err)
+	}
+	// The ARNs are in the partition of the operator
+	if err = config.SetIsFedramp(configMap); err != nil {
+		return nil, err
+	}
+	if configMap.Data[awsv1alpha1.SREAccessARN] == "" {
+		return nil, fmt.Errorf("no SRE access principal is configured in the operator configmap, the operator doesn't manage SRE access roles")
+	}
+	return &sreAccessRole{
+		account:    sreAccount,
+		roleARN:    config.GetIAMArn(sreAccount.Spec.AwsAccountID, config.AwsResourceTypeRole, account.GetSREAccessRoleName(sreAccount)),
+		externalID: configMap.Data[awsv1alpha1.SREAccessExternalID],
+	}, nil
+}
+
+// assume assumes the SRE access role with the credentials of the SRE principal. The session is named after the
+// requester, so CloudTrail attributes the actions of the session to them.
+func (r *sreAccessRole) assume(stsClient roleAssumer, requester string, duration time.Duration) (*sts.Credentials, error) {
+	output, err := stsClient.AssumeRole(&sts.AssumeRoleInput{
+		RoleArn:         aws.String(r.roleARN),
+		RoleSessionName: aws.String(roleSessionName(requester)),
+		ExternalId:      aws.String(r.externalID),
+		DurationSeconds: aws.Int64(int64(duration.Seconds())),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to assume %s, are your AWS credentials those of the SRE access principal? %w", r.roleARN, err)
+	}
+	return output.Credentials, nil
+}
+
+// roleSessionName returns the requester with the characters AWS doesn't accept in a role session name replaced
+func roleSessionName(requester string) string {
+	name := invalidRoleSessionNameChars.ReplaceAllString(requester, "-")
+	if len(name) > maxRoleSessionNameLength {
+		name = name[:maxRoleSessionNameLength]
+	}
+	if len(name) < minRoleSessionNameLength {
+		name = "aao-sre"
+	}
+	return name
+}
+
+// consoleURL returns a console sign-in URL opening a session with the credentials
+func consoleURL(creds *sts.Credentials) (string, error) {
+	signinToken, err := stsclient.GetSigninToken(&http.Client{Timeout: federationTimeout}, stsclient.FederationEndpoint(), creds)
+	if err != nil {
+		return "", fmt.Errorf("failed to get a console sign-in token: %w", err)
+	}
+	return stsclient.LoginURL(stsclient.FederationEndpoint(), signinToken), nil
+}
+
+// printCredentials prints the credentials as shell exports, to be eval'd
+func printCredentials(out io.Writer, creds *sts.Credentials, region string) {
+	fmt.Fprintf(out, "export AWS_ACCESS_KEY_ID=%s\n", aws.StringValue(creds.AccessKeyId))
+	fmt.Fprintf(out, "export AWS_SECRET_ACCESS_KEY=%s\n", aws.StringValue(creds.SecretAccessKey))
+	fmt.Fprintf(out, "export AWS_SESSION_TOKEN=%s\n", aws.StringValue(creds.SessionToken))
+	fmt.Fprintf(out, "export AWS_DEFAULT_REGION=%s\n", region)
+	fmt.Fprintf(out, "# Expires at %s\n", aws.TimeValue(creds.Expiration).UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+)
+
+type fakeRoleAssumer struct {
+	input *sts.AssumeRoleInput
+	err   error
+}
+
+func (f *fakeRoleAssumer) AssumeRole(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+	f.input = input
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &sts.AssumeRoleOutput{Credentials: &sts.Credentials{AccessKeyId: aws.String("ACCESS_KEY")}}, nil
+}
+
+func newOperatorConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+		Data:       data,
+	}
+}
+
+func TestFindAccountByClusterID(t *testing.T) {
+	claimed := newAccount("osd-creds-mgmt-bbb", string(awsv1alpha1.AccountReady))
+	claimed.Spec.ClusterID = "1a2b3c"
+	kubeClient := newTestClient(t, newAccount("osd-creds-mgmt-aaa", string(awsv1alpha1.AccountReady)), claimed)
+
+	found, err := findAccount(context.TODO(), kubeClient, "", "1a2b3c")
+	assert.NoError(t, err)
+	assert.Equal(t, "osd-creds-mgmt-bbb", found.Name)
+
+	found, err = findAccount(context.TODO(), kubeClient, "osd-creds-mgmt-aaa", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "osd-creds-mgmt-aaa", found.Name)
+
+	_, err = findAccount(context.TODO(), kubeClient, "", "unknown")
+	assert.Error(t, err)
+}
+
+func TestSREAccessRole(t *testing.T) {
+	sreAccount := newAccount("osd-creds-mgmt-aaa", string(awsv1alpha1.AccountReady))
+	sreAccount.Labels = map[string]string{awsv1alpha1.IAMUserIDLabel: "abc123"}
+	kubeClient := newTestClient(t, newOperatorConfigMap(map[string]string{
+		awsv1alpha1.SREAccessARN:        "arn:aws:iam::111111111111:role/SRE",
+		awsv1alpha1.SREAccessExternalID: "external-id",
+	}))
+
+	role, err := getSREAccessRole(kubeClient, sreAccount)
+	assert.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/ManagedOpenShift-SRE-Access-abc123", role.roleARN)
+
+	assumer := &fakeRoleAssumer{}
+	creds, err := role.assume(assumer, "jane.doe@example.com (SRE)", 2*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, "ACCESS_KEY", aws.StringValue(creds.AccessKeyId))
+	assert.Equal(t, "external-id", aws.StringValue(assumer.input.ExternalId))
+	assert.Equal(t, "jane.doe@example.com--SRE-", aws.StringValue(assumer.input.RoleSessionName))
+	assert.Equal(t, int64(7200), aws.Int64Value(assumer.input.DurationSeconds))
+
+	_, err = role.assume(&fakeRoleAssumer{err: errors.New("AccessDenied")}, "jane", time.Hour)
+	assert.Error(t, err)
+}
+
+func TestSREAccessRoleRefusals(t *testing.T) {
+	ccsAccount := newAccount("osd-creds-mgmt-aaa", string(awsv1alpha1.AccountReady))
+	ccsAccount.Spec.BYOC = true
+	_, err := getSREAccessRole(newTestClient(t, newOperatorConfigMap(nil)), ccsAccount)
+	assert.Error(t, err)
+
+	// The operator doesn't manage the role without an SRE access principal
+	_, err = getSREAccessRole(newTestClient(t, newOperatorConfigMap(nil)), newAccount("osd-creds-mgmt-bbb", string(awsv1alpha1.AccountReady)))
+	assert.Error(t, err)
+}
+
+func TestRoleSessionName(t *testing.T) {
+	assert.Equal(t, "jdoe", roleSessionName("jdoe"))
+	assert.Equal(t, "aao-sre", roleSessionName(""))
+	assert.Len(t, roleSessionName(strings.Repeat("a", 100)), maxRoleSessionNameLength)
+}
+
+func TestPrintCredentials(t *testing.T) {
+	out := &strings.Builder{}
+	printCredentials(out, &sts.Credentials{
+		AccessKeyId:     aws.String("ACCESS_KEY"),
+		SecretAccessKey: aws.String("SECRET_KEY"),
+		SessionToken:    aws.String("TOKEN"),
+		Expiration:      aws.Time(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+	}, "us-east-1")
+	assert.Equal(t, "export AWS_ACCESS_KEY_ID=ACCESS_KEY\n"+
+		"export AWS_SECRET_ACCESS_KEY=SECRET_KEY\n"+
+		"export AWS_SESSION_TOKEN=TOKEN\n"+
+		"export AWS_DEFAULT_REGION=us-east-1\n"+
+		"# Expires at 2024-01-01T12:00:00Z\n", out.String())
+}
@@ -0,0 +1,285 @@
+// aao inspects the Accounts of the AWS Account Operator through the cluster API, e.g. during incidents. Installed
+// as kubectl-aao, it's also a kubectl plugin.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+)
+
+const usage = `Usage: aao [--kubeconfig <path>] <command>, or kubectl aao <command> when installed as kubectl-aao
+
+Commands:
+  accounts list [--state <state>] [-o table|json]
+        List the accounts with their state, claim, reuse count and conditions
+  accounts report <account>
+        Print the report of the last cleanup of an account
+  accounts repool <account> --reason <reason>
+        Put an account cleaned up by hand back into the pool, once it's verified again
+  accounts cleanup <account> [--region <region>]
+        Run the reuse cleanup against an unclaimed account, in the default region of the operator by default
+  accounts rebuild-iam <account> --reason <reason>
+        Run the IAM setup of a Ready or Failed account again, with a new access key for its IAM user
+  accounts reassign-legal-entity <account> --legal-entity-id <id> [--legal-entity-name <name>]
+        Move an unclaimed account set to the wrong legal entity to another one
+  accounts owner <aws-account-id> [-o table|json]
+        Print the claim and cluster of an AWS account, or its Account when it's unclaimed
+  accounts credentials <account> | --cluster-id <id> [--console] [--duration <duration>] [--region <region>]
+        Assume the SRE access role of a non-CCS account with your AWS credentials, those of the SRE access
+        principal, and print temporary CLI credentials as shell exports, and a console sign-in URL with --console
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	args := flag.Args()
+	if len(args) < 2 || args[0] != "accounts" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	kubeClient, err := newKubeClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to the cluster: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	switch args[1] {
+	case "list":
+		err = runList(ctx, kubeClient, args[2:])
+	case "report":
+		err = runReport(ctx, kubeClient, args[2:])
+	case "repool":
+		err = runRepool(ctx, kubeClient, args[2:])
+	case "cleanup":
+		err = runCleanup(ctx, kubeClient, args[2:])
+	case "rebuild-iam":
+		err = runRebuildIAM(ctx, kubeClient, args[2:])
+	case "reassign-legal-entity":
+		err = runReassignLegalEntity(ctx, kubeClient, args[2:])
+	case "owner":
+		err = runOwner(ctx, kubeClient, args[2:])
+	case "credentials":
+		err = runCredentials(ctx, kubeClient, args[2:])
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// newKubeClient returns a client of the cluster of the kubeconfig, the in-cluster config when there's none
+func newKubeClient() (client.Client, error) {
+	cfg, err := ctrlconfig.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err = apis.AddToScheme(scheme.Scheme); err != nil {
+		return nil, err
+	}
+	return client.New(cfg, client.Options{Scheme: scheme.Scheme})
+}
+
+func runList(ctx context.Context, kubeClient client.Client, args []string) error {
+	flags := flag.NewFlagSet("accounts list", flag.ExitOnError)
+	state := flags.String("state", "", "Only list the accounts in this state")
+	output := flags.String("o", "table", "Output format, table or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	summaries, err := listAccounts(ctx, kubeClient, *state)
+	if err != nil {
+		return err
+	}
+	switch *output {
+	case "table":
+		return printTable(os.Stdout, summaries)
+	case "json":
+		return printJSON(os.Stdout, summaries)
+	}
+	return fmt.Errorf("unknown output format %q", *output)
+}
+
+func runReport(ctx context.Context, kubeClient client.Client, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("accounts report takes the name of an account")
+	}
+	report, err := cleanupReport(ctx, kubeClient, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(report)
+	return nil
+}
+
+func runRepool(ctx context.Context, kubeClient client.Client, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("accounts repool takes the name of an account")
+	}
+	flags := flag.NewFlagSet("accounts repool", flag.ExitOnError)
+	reason := flags.String("reason", "", "Why the account is put back, e.g. the ticket of the manual cleanup")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *reason == "" {
+		return fmt.Errorf("--reason is required")
+	}
+	if err := annotateAccount(ctx, kubeClient, args[0], awsv1alpha1.RepoolAnnotation, *reason); err != nil {
+		return err
+	}
+	fmt.Printf("Requested account %s to be put back into the pool, its events tell whether it was\n", args[0])
+	return nil
+}
+
+func runCleanup(ctx context.Context, kubeClient client.Client, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("accounts cleanup takes the name of an account")
+	}
+	flags := flag.NewFlagSet("accounts cleanup", flag.ExitOnError)
+	region := flags.String("region", "", "The region to clean up, the default region of the operator when empty")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if err := annotateAccount(ctx, kubeClient, args[0], awsv1alpha1.CleanupAnnotation, *region); err != nil {
+		return err
+	}
+	fmt.Printf("Requested the cleanup of account %s, its events tell whether it was queued\n", args[0])
+	return nil
+}
+
+func runRebuildIAM(ctx context.Context, kubeClient client.Client, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("accounts rebuild-iam takes the name of an account")
+	}
+	flags := flag.NewFlagSet("accounts rebuild-iam", flag.ExitOnError)
+	reason := flags.String("reason", "", "Why the IAM setup is rebuilt, e.g. the ticket of the incident")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *reason == "" {
+		return fmt.Errorf("--reason is required")
+	}
+	if err := annotateAccount(ctx, kubeClient, args[0], awsv1alpha1.RebuildIAMAnnotation, *reason); err != nil {
+		return err
+	}
+	fmt.Printf("Requested the rebuild of the IAM setup of account %s, its events tell whether it was\n", args[0])
+	return nil
+}
+
+func runReassignLegalEntity(ctx context.Context, kubeClient client.Client, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("accounts reassign-legal-entity takes the name of an account")
+	}
+	flags := flag.NewFlagSet("accounts reassign-legal-entity", flag.ExitOnError)
+	id := flags.String("legal-entity-id", "", "ID of the legal entity the account is moved to")
+	name := flags.String("legal-entity-name", "", "Name of the legal entity the account is moved to")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *id == "" {
+		return fmt.Errorf("--legal-entity-id is required")
+	}
+	value := *id
+	if *name != "" {
+		value += "/" + *name
+	}
+	if err := annotateAccount(ctx, kubeClient, args[0], awsv1alpha1.ReassignLegalEntityAnnotation, value); err != nil {
+		return err
+	}
+	fmt.Printf("Requested the reassignment of account %s to legal entity %s, its events tell whether it was\n", args[0], *id)
+	return nil
+}
+
+func runOwner(ctx context.Context, kubeClient client.Client, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("accounts owner takes the ID of an AWS account")
+	}
+	flags := flag.NewFlagSet("accounts owner", flag.ExitOnError)
+	output := flags.String("o", "table", "Output format, table or json")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	owners, err := findOwners(ctx, kubeClient, args[0])
+	if err != nil {
+		return err
+	}
+	switch *output {
+	case "table":
+		return printOwners(os.Stdout, owners)
+	case "json":
+		return printJSON(os.Stdout, owners)
+	}
+	return fmt.Errorf("unknown output format %q", *output)
+}
+
+func runCredentials(ctx context.Context, kubeClient client.Client, args []string) error {
+	name := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	flags := flag.NewFlagSet("accounts credentials", flag.ExitOnError)
+	clusterID := flags.String("cluster-id", "", "The ID of the cluster whose account to access, instead of the account name")
+	console := flags.Bool("console", false, "Also print a console sign-in URL")
+	duration := flags.Duration("duration", time.Hour, "How long the credentials are valid, up to the maximum session duration of the role")
+	region := flags.String("region", "", "The region set in AWS_DEFAULT_REGION, the default region of the operator when empty")
+	requester := flags.String("requested-by", os.Getenv("USER"), "Who the session is for, it's named after them in CloudTrail")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if (name == "") == (*clusterID == "") {
+		return fmt.Errorf("accounts credentials takes either the name of an account or --cluster-id")
+	}
+
+	sreAccount, err := findAccount(ctx, kubeClient, name, *clusterID)
+	if err != nil {
+		return err
+	}
+	role, err := getSREAccessRole(kubeClient, sreAccount)
+	if err != nil {
+		return err
+	}
+	if *region == "" {
+		*region = config.GetDefaultRegion()
+	}
+
+	// The role is assumed with the AWS credentials of the user, e.g. from their profile
+	sess, err := session.NewSessionWithOptions(session.Options{SharedConfigState: session.SharedConfigEnable})
+	if err != nil {
+		return fmt.Errorf("failed to load your AWS credentials: %w", err)
+	}
+	creds, err := role.assume(sts.New(sess), *requester, *duration)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Assumed %s of account %s (%s)\n", role.roleARN, sreAccount.Name, sreAccount.Spec.AwsAccountID)
+	printCredentials(os.Stdout, creds, *region)
+	if *console {
+		loginURL, err := consoleURL(creds)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("# Console: %s\n", loginURL)
+	}
+	return nil
+}
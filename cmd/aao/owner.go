@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+)
+
+// accountOwner is a claim of an AWS account, or the Account of an unclaimed AWS account
+type accountOwner struct {
+	AwsAccountID string `json:"awsAccountID"`
+	Account      string `json:"account"`
+	// Claim is the namespace/name of the claim, empty when the account is unclaimed
+	Claim     string `json:"claim,omitempty"`
+	ClusterID string `json:"clusterID,omitempty"`
+	State     string `json:"state"`
+}
+
+// findOwners returns the claims of the AWS account, found with the AWS account ID label the operator sets on the
+// claims. Without a labelled claim, e.g. while the operator hasn't reconciled the claims since it started labelling
+// them, the Accounts of the AWS account are returned, with their claim links.
+func findOwners(ctx context.Context, kubeClient client.Client, awsAccountID string) ([]accountOwner, error) {
+	claims := &awsv1alpha1.AccountClaimList{}
+	err := kubeClient.List(ctx, claims, client.MatchingLabels{awsv1alpha1.AccountIDLabel: awsAccountID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the claims of AWS account %s: %w", awsAccountID, err)
+	}
+	owners := []accountOwner{}
+	for _, claim := range claims.Items {
+		owners = append(owners, accountOwner{
+			AwsAccountID: awsAccountID,
+			Account:      claim.Spec.AccountLink,
+			Claim:        claim.Namespace + "/" + claim.Name,
+			ClusterID:    claim.Labels[awsv1alpha1.ClusterIDLabel],
+			State:        string(claim.Status.State),
+		})
+	}
+	if len(owners) > 0 {
+		sort.Slice(owners, func(i, j int) bool { return owners[i].Claim < owners[j].Claim })
+		return owners, nil
+	}
+
+	accounts := &awsv1alpha1.AccountList{}
+	if err = kubeClient.List(ctx, accounts, client.InNamespace(awsv1alpha1.AccountCrNamespace)); err != nil {
+		return nil, fmt.Errorf("failed to list the accounts: %w", err)
+	}
+	for _, account := range accounts.Items {
+		if account.Spec.AwsAccountID != awsAccountID {
+			continue
+		}
+		owner := accountOwner{
+			AwsAccountID: awsAccountID,
+			Account:      account.Name,
+			ClusterID:    account.Spec.ClusterID,
+			State:        account.Status.State,
+		}
+		if account.Spec.ClaimLink != "" {
+			owner.Claim = account.Spec.ClaimLinkNamespace + "/" + account.Spec.ClaimLink
+		}
+		owners = append(owners, owner)
+	}
+	if len(owners) == 0 {
+		return nil, fmt.Errorf("AWS account %s isn't managed by the operator", awsAccountID)
+	}
+	sort.Slice(owners, func(i, j int) bool { return owners[i].Account < owners[j].Account })
+	return owners, nil
+}
+
+// printOwners prints the owners as a table, like oc get
+func printOwners(out io.Writer, owners []accountOwner) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "AWS ACCOUNT\tACCOUNT\tCLAIM\tCLUSTER ID\tSTATE")
+	for _, owner := range owners {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", owner.AwsAccountID, orNone(owner.Account), orNone(owner.Claim),
+			orNone(owner.ClusterID), orNone(owner.State))
+	}
+	return w.Flush()
+}
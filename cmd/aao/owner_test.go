@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+)
+
+func TestFindOwners(t *testing.T) {
+	claim := &awsv1alpha1.AccountClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mycluster",
+			Namespace: "uhc-production-1234",
+			Labels: map[string]string{
+				awsv1alpha1.AccountIDLabel: "123456789012",
+				awsv1alpha1.ClusterIDLabel: "1a2b3c",
+			},
+		},
+		Spec:   awsv1alpha1.AccountClaimSpec{AccountLink: "osd-creds-mgmt-aaa"},
+		Status: awsv1alpha1.AccountClaimStatus{State: awsv1alpha1.ClaimStatusReady},
+	}
+	unclaimed := newAccount("osd-creds-mgmt-bbb", string(awsv1alpha1.AccountReady))
+	unclaimed.Spec.AwsAccountID = "210987654321"
+	kubeClient := newTestClient(t, claim, unclaimed)
+
+	owners, err := findOwners(context.TODO(), kubeClient, "123456789012")
+	assert.NoError(t, err)
+	assert.Equal(t, []accountOwner{{
+		AwsAccountID: "123456789012",
+		Account:      "osd-creds-mgmt-aaa",
+		Claim:        "uhc-production-1234/mycluster",
+		ClusterID:    "1a2b3c",
+		State:        "Ready",
+	}}, owners)
+
+	// Unclaimed accounts are found through their Account
+	owners, err = findOwners(context.TODO(), kubeClient, "210987654321")
+	assert.NoError(t, err)
+	assert.Len(t, owners, 1)
+	assert.Equal(t, "osd-creds-mgmt-bbb", owners[0].Account)
+	assert.Empty(t, owners[0].Claim)
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printOwners(out, owners))
+	assert.Contains(t, out.String(), "osd-creds-mgmt-bbb")
+
+	_, err = findOwners(context.TODO(), kubeClient, "999999999999")
+	assert.Error(t, err)
+}
@@ -20,17 +20,25 @@ import (
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
 	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
 	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/audit"
 	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/cloudevents"
+	"github.com/openshift/aws-account-operator/pkg/notifier"
 	"github.com/openshift/aws-account-operator/pkg/totalaccountwatcher"
+	"github.com/openshift/aws-account-operator/pkg/tracing"
 	"github.com/openshift/aws-account-operator/pkg/utils"
 )
 
@@ -48,6 +56,9 @@ const (
 	// awsAccountInitRequeueDuration is the duration we want to wait for the next
 	// reconcile loop after hitting an OptInRequired-error during region initialization.
 	awsAccountInitRequeueDuration = 1 * time.Minute
+	// claimedDeletionRequeueDuration is how long we wait before checking again whether a claimed
+	// Account CR that is pending deletion has been released by its AccountClaim.
+	claimedDeletionRequeueDuration = 5 * time.Minute
 
 	// AccountPending indicates an account is pending
 	AccountPending = "Pending"
@@ -68,7 +79,6 @@ const (
 	AccountOptInRegionEnabled    = "OptInRegionsEnabled"
 	standardAdminAccessArnPrefix = "arn:aws:iam"
 	adminAccessArnSuffix         = "::aws:policy/AdministratorAccess"
-	iamUserNameUHC               = "osdManagedAdmin"
 
 	controllerName = "account"
 
@@ -87,6 +97,7 @@ type AccountReconciler struct {
 	Scheme           *runtime.Scheme
 	awsClientBuilder awsclient.IBuilder
 	shardName        string
+	recorder         record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=accounts,verbs=get;list;watch;create;update;patch;delete
@@ -100,6 +111,26 @@ type AccountReconciler struct {
 func (r *AccountReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
 	reqLogger := log.WithValues("Controller", controllerName, "Request.Namespace", request.Namespace, "Request.Name", request.Name)
 
+	// The accounts of other shards, e.g. enqueued for their IAM user secret, are reconciled by other replicas
+	if !utils.InShard(request.Name) {
+		return ctrl.Result{}, nil
+	}
+
+	ctx = audit.WithFields(ctx, audit.Fields{Account: request.Name})
+	ctx, span := tracing.Start(ctx, "Reconcile Account", tracing.String("account", request.Name))
+	ctx, plan := awsclient.WithPlan(ctx)
+	result, err := r.reconcileAccount(ctx, request, reqLogger)
+	span.End(err)
+	account := &awsv1alpha1.Account{}
+	result, err = utils.UpdateReconcileOutcome(r.Client, reqLogger, request.NamespacedName, account, result, err)
+	if account.Name != "" {
+		awsclient.RecordPlannedActions(r.recorder, account, plan)
+	}
+	return result, err
+}
+
+func (r *AccountReconciler) reconcileAccount(ctx context.Context, request ctrl.Request, reqLogger logr.Logger) (ctrl.Result, error) {
+
 	// Fetch the Account instance
 	currentAcctInstance := &awsv1alpha1.Account{}
 	err := r.Client.Get(context.TODO(), request.NamespacedName, currentAcctInstance)
@@ -111,6 +142,25 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		return reconcile.Result{}, err
 	}
 
+	ctx = audit.WithFields(ctx, audit.Fields{AWSAccountID: currentAcctInstance.Spec.AwsAccountID})
+
+	// Label the account with its shard, the update triggers another reconcile
+	if utils.SetShardLabel(currentAcctInstance) {
+		return reconcile.Result{}, utils.UpdateWithRetry(r.Client, currentAcctInstance, func() error {
+			utils.SetShardLabel(currentAcctInstance)
+			return nil
+		})
+	}
+
+	// A retired CCS account was cleaned up when it was retired, the CR is only kept as a record
+	if currentAcctInstance.IsRetired() {
+		if currentAcctInstance.IsPendingDeletion() && currentAcctInstance.HasAwsv1alpha1Finalizer() {
+			reqLogger.Info("removing finalizer of retired account")
+			return reconcile.Result{}, r.removeFinalizer(currentAcctInstance, awsv1alpha1.AccountFinalizer)
+		}
+		return reconcile.Result{}, nil
+	}
+
 	configMap, err := utils.GetOperatorConfigMap(r.Client)
 	if err != nil {
 		log.Error(err, "Failed retrieving configmap")
@@ -135,6 +185,7 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		SecretName: utils.AwsSecretName,
 		NameSpace:  awsv1alpha1.AccountCrNamespace,
 		AwsRegion:  awsRegion,
+		Context:    ctx,
 	})
 	if err != nil {
 		reqLogger.Error(err, "failed building operator AWS client")
@@ -149,7 +200,23 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		}
 	}
 
+	// Park the quarantined accounts, and release them before they're put back into service or cleaned up
+	err = r.reconcileParkingSCP(reqLogger, awsSetupClient, currentAcctInstance, configMap.Data[awsv1alpha1.ParkingSCPConfigMapKey])
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	if currentAcctInstance.IsPendingDeletion() {
+		blocked, err := r.isClaimedDeletionBlocked(reqLogger, currentAcctInstance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if blocked {
+			reqLogger.Info(fmt.Sprintf("Account %s is claimed by %s/%s, refusing to finalize until the claim is deleted or the %s annotation is set",
+				currentAcctInstance.Name, currentAcctInstance.Spec.ClaimLinkNamespace, currentAcctInstance.Spec.ClaimLink, awsv1alpha1.AllowClaimedDeletionAnnotation))
+			return reconcile.Result{RequeueAfter: claimedDeletionRequeueDuration}, nil
+		}
+
 		if currentAcctInstance.Spec.ManualSTSMode {
 			// if the account is STS, we don't need to do any additional cleanup aside from
 			// removing the finalizer and exiting.
@@ -160,10 +227,16 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 			return reconcile.Result{}, err
 		}
 
+		// The account would lose its finalizer without having been cleaned up
+		if utils.IsMaintenanceMode(r.Client) {
+			reqLogger.Info("Maintenance mode is enabled, postponing the finalization of the account")
+			return reconcile.Result{RequeueAfter: utils.MaintenanceModeRequeueDelay}, nil
+		}
+
 		var awsClient awsclient.Client
 		if currentAcctInstance.IsBYOC() {
 			roleToAssume := currentAcctInstance.GetAssumeRole()
-			awsClient, _, err = stsclient.HandleRoleAssumption(reqLogger, r.awsClientBuilder, currentAcctInstance, r.Client, awsSetupClient, "", roleToAssume, "")
+			awsClient, _, err = stsclient.HandleRoleAssumption(reqLogger, awsclient.WithContext(ctx, r.awsClientBuilder), currentAcctInstance, r.Client, awsSetupClient, "", roleToAssume, "")
 			if err != nil {
 				reqLogger.Error(err, "failed building BYOC client from assume_role")
 				_, err = r.handleAWSClientError(reqLogger, currentAcctInstance, err)
@@ -187,7 +260,7 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 				return reconcile.Result{}, err
 			}
 		} else {
-			awsClient, _, err = stsclient.HandleRoleAssumption(reqLogger, r.awsClientBuilder, currentAcctInstance, r.Client, awsSetupClient, "", awsv1alpha1.AccountOperatorIAMRole, "")
+			awsClient, _, err = stsclient.HandleRoleAssumption(reqLogger, awsclient.WithContext(ctx, r.awsClientBuilder), currentAcctInstance, r.Client, awsSetupClient, "", awsv1alpha1.AccountOperatorIAMRole, "")
 			if err != nil {
 				reqLogger.Error(err, "failed building AWS client from assume_role")
 				return r.handleAWSClientError(reqLogger, currentAcctInstance, err)
@@ -196,6 +269,11 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		r.finalizeAccount(reqLogger, awsClient, currentAcctInstance)
 		//return reconcile.Result{}, nil
 
+		// The cleanup was only planned, the account keeps its finalizer
+		if utils.IsObserveOnly() {
+			return reconcile.Result{RequeueAfter: utils.MaintenanceModeRequeueDelay}, nil
+		}
+
 		// Remove finalizer if account CR is non STS. For CCS accounts, the accountclaim controller will delete the account CR
 		// when the accountClaim CR is deleted as its set as the owner reference.
 		if currentAcctInstance.IsNonSTSPendingDeletionWithFinalizer() {
@@ -209,6 +287,27 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		return reconcile.Result{}, nil
 	}
 
+	// An account cleaned up by hand is put back into the pool on request, Failed accounts included
+	repooled, err := r.handleRepoolRequest(reqLogger, currentAcctInstance)
+	if repooled || err != nil {
+		return reconcile.Result{}, err
+	}
+	// Accounts messed with by hand are scrubbed with the reuse cleanup on request
+	cleanupRequested, err := r.handleAdHocCleanupRequest(reqLogger, currentAcctInstance)
+	if cleanupRequested || err != nil {
+		return reconcile.Result{}, err
+	}
+	// The IAM setup of accounts tampered with or left half-done is rebuilt on request, Failed accounts included
+	rebuilt, err := r.handleRebuildIAMRequest(reqLogger, currentAcctInstance, awsSetupClient, request.Namespace)
+	if rebuilt || err != nil {
+		return reconcile.Result{}, err
+	}
+	// Unclaimed accounts given the wrong legal entity are moved to another one on request
+	reassigned, err := r.handleReassignLegalEntityRequest(reqLogger, currentAcctInstance, awsSetupClient)
+	if reassigned || err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Handles IAM user and secret recreation for accounts that are reused, non-BYOC, and in a ready state
 	// This function is essential because a Fleet Manager AWS account should not possess any long-lived IAM credentials; instead, it should only require STS IAM access.
 	// However, once a Fleet Manager account claim is deleted, the AWS account no longer has long-lived IAM credentials and cannot be claimed by non-Fleet Manager account claims.
@@ -226,6 +325,26 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		return reconcile.Result{}, nil
 	}
 
+	// The IAM user secret of a Ready account may have been deleted or edited out-of-band, the secret watch
+	// triggers a reconcile when it happens
+	if err = r.ensureIAMUserSecret(reqLogger, currentAcctInstance, awsSetupClient, request.Namespace); err != nil {
+		reqLogger.Error(err, "Failed to re-mint the IAM user credentials")
+		return reconcile.Result{}, err
+	}
+
+	// New accounts are neither created nor initialized in maintenance or observe-only mode, so they aren't failed on
+	// timeouts either
+	if !currentAcctInstance.IsReady() && utils.IsAWSMutationPaused(r.Client) {
+		reqLogger.Info("AWS changes are paused, postponing the creation of the account")
+		return reconcile.Result{RequeueAfter: utils.MaintenanceModeRequeueDelay}, nil
+	}
+
+	// Resume the account creation in the phase the previous reconciles reached
+	err = r.syncPhaseWithState(reqLogger, currentAcctInstance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Detect accounts for which we kicked off asynchronous region initialization
 	if currentAcctInstance.IsInitializingRegions() {
 		return r.handleAccountInitializingRegions(reqLogger, currentAcctInstance)
@@ -239,12 +358,14 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		result, initErr = r.initializeNewCCSAccount(reqLogger, currentAcctInstance)
 		if initErr != nil {
 			// TODO: If we have recoverable results from above, how do we allow them to requeue if state is failed
+			// The reason is the category of the AWS error, so automation can tell a CCS account that can be retried
+			// from one the customer has to fix
 			_, stateErr := r.setAccountFailed(
 				reqLogger,
 				currentAcctInstance,
 				awsv1alpha1.AccountCreationFailed,
-				initErr.Error(),
-				"Failed to initialize new CCS account",
+				string(awsv1alpha1.GetAWSErrorCategory(initErr)),
+				fmt.Sprintf("Failed to initialize new CCS account: %v", initErr),
 				AccountFailed,
 			)
 			if stateErr != nil {
@@ -338,6 +459,12 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 
 	// Account init for both BYOC and Non-BYOC
 	if currentAcctInstance.ReadyForInitialization() {
+		// A failed attempt at the current phase is only retried once its backoff has elapsed
+		if delay := currentAcctInstance.PhaseRetryDelay(); delay > 0 {
+			reqLogger.Info("Waiting before retrying account phase", "phase", currentAcctInstance.GetPhase(), "retryAfter", delay)
+			return reconcile.Result{RequeueAfter: delay}, nil
+		}
+
 		reqLogger.Info("initializing account", "awsAccountID", currentAcctInstance.Spec.AwsAccountID)
 
 		var creds *sts.AssumeRoleOutput
@@ -347,16 +474,20 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 			accountClaim, acctClaimErr := r.getAccountClaim(currentAcctInstance)
 			if acctClaimErr != nil {
 				reqLogger.Error(acctClaimErr, "unable to get accountclaim for sts account")
-				utils.SetAccountClaimStatus(
-					accountClaim,
-					"Failed to get AccountClaim for CSS account",
-					"FailedRetrievingAccountClaim",
-					awsv1alpha1.ClientError,
-					awsv1alpha1.ClaimStatusError,
-				)
-				err := r.Client.Status().Update(context.TODO(), accountClaim)
-				if err != nil {
-					reqLogger.Error(err, "failed to update accountclaim status")
+				if accountClaim != nil {
+					err := utils.UpdateStatusWithRetry(r.Client, accountClaim, func() error {
+						utils.SetAccountClaimStatus(
+							accountClaim,
+							"Failed to get AccountClaim for CSS account",
+							"FailedRetrievingAccountClaim",
+							awsv1alpha1.ClientError,
+							awsv1alpha1.ClaimStatusError,
+						)
+						return nil
+					})
+					if err != nil {
+						reqLogger.Error(err, "failed to update accountclaim status")
+					}
 				}
 				return reconcile.Result{}, acctClaimErr
 			}
@@ -370,25 +501,47 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		} else {
 			// Set IAMUserIDLabel if not there, and requeue
 			if !utils.AccountCRHasIAMUserIDLabel(currentAcctInstance) {
-				utils.AddLabels(
-					currentAcctInstance,
-					utils.GenerateLabel(
-						awsv1alpha1.IAMUserIDLabel,
-						utils.GenerateShortUID(),
-					),
-				)
-				return reconcile.Result{Requeue: true}, r.Client.Update(context.TODO(), currentAcctInstance)
+				return reconcile.Result{Requeue: true}, utils.UpdateWithRetry(r.Client, currentAcctInstance, func() error {
+					if !utils.AccountCRHasIAMUserIDLabel(currentAcctInstance) {
+						utils.AddLabels(
+							currentAcctInstance,
+							utils.GenerateLabel(
+								awsv1alpha1.IAMUserIDLabel,
+								utils.GenerateShortUID(),
+							),
+						)
+					}
+					return nil
+				})
 			}
 
-			_, newCredentials, err := r.handleIAMUserCreation(reqLogger, currentAcctInstance, awsSetupClient, request.Namespace)
-			if err != nil {
-				reqLogger.Error(err, "Error during IAM user creation")
-				return reconcile.Result{}, err
+			// An account whose IAM setup is complete resumes with the initialization of its regions
+			if currentAcctInstance.GetPhase() == awsv1alpha1.AccountPhaseRegionInit {
+				_, creds, err = AssumeRoleAndCreateClient(reqLogger, r.awsClientBuilder, currentAcctInstance, r.Client, awsSetupClient, "", currentAcctInstance.GetAssumeRole(), "")
+				if err != nil {
+					return r.phaseFailed(reqLogger, currentAcctInstance, err)
+				}
+			} else {
+				err = r.enterPhase(reqLogger, currentAcctInstance, awsv1alpha1.AccountPhaseIAMSetup)
+				if err != nil {
+					return reconcile.Result{}, err
+				}
+				_, newCredentials, err := r.handleIAMUserCreation(reqLogger, currentAcctInstance, awsSetupClient, request.Namespace)
+				if err != nil {
+					reqLogger.Error(err, "Error during IAM user creation")
+					if currentAcctInstance.IsFailed() {
+						return reconcile.Result{}, err
+					}
+					return r.phaseFailed(reqLogger, currentAcctInstance, err)
+				}
+				creds = newCredentials
 			}
-			creds = newCredentials
-
 		}
 
+		err = r.enterPhase(reqLogger, currentAcctInstance, awsv1alpha1.AccountPhaseRegionInit)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 		err = r.initializeRegions(reqLogger, currentAcctInstance, creds, amiOwner)
 
 		if isAwsOptInError(err) {
@@ -397,6 +550,9 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 				RequeueAfter: awsAccountInitRequeueDuration,
 			}, nil
 		}
+		if err != nil && !currentAcctInstance.IsFailed() {
+			return r.phaseFailed(reqLogger, currentAcctInstance, err)
+		}
 
 		return reconcile.Result{}, err
 	}
@@ -483,8 +639,17 @@ func (r *AccountReconciler) handleIAMUserCreation(reqLogger logr.Logger, current
 		return reconcile.Result{}, nil, err
 	}
 
-	// Use the same ID applied to the account name for IAM usernames
-	iamUserUHC := fmt.Sprintf("%s-%s", iamUserNameUHC, currentAcctInstance.Labels[awsv1alpha1.IAMUserIDLabel])
+	// The password policy of a CCS account belongs to the customer
+	if !currentAcctInstance.IsBYOC() {
+		err = r.applySecurityBaseline(reqLogger, awsAssumedRoleClient, currentAcctInstance)
+		if err != nil {
+			reqLogger.Error(err, "Failed to apply account security baseline")
+			return reconcile.Result{}, nil, err
+		}
+	}
+
+	// Use the same ID applied to the account name for IAM usernames, unless a name was already recorded
+	iamUserUHC := currentAcctInstance.GetIAMUserName()
 	secretName, err := r.BuildIAMUser(reqLogger, awsAssumedRoleClient, currentAcctInstance, iamUserUHC, namespace)
 	if err != nil {
 		reason, errType := getBuildIAMUserErrorReason(err)
@@ -503,12 +668,27 @@ func (r *AccountReconciler) handleIAMUserCreation(reqLogger logr.Logger, current
 		return reconcile.Result{}, nil, err
 	}
 
-	currentAcctInstance.Spec.IAMUserSecret = *secretName
-	err = r.accountSpecUpdate(reqLogger, currentAcctInstance)
+	err = r.accountSpecUpdate(reqLogger, currentAcctInstance, func() error {
+		currentAcctInstance.Spec.IAMUserSecret = *secretName
+		return nil
+	})
 	if err != nil {
 		reqLogger.Error(err, "Error updating Secret Ref in Account CR")
 		return reconcile.Result{}, nil, err
 	}
+
+	err = r.recordIAMUserName(reqLogger, currentAcctInstance, iamUserUHC)
+	if err != nil {
+		return reconcile.Result{}, nil, err
+	}
+
+	err = r.recordOperatorResources(reqLogger, currentAcctInstance, awsv1alpha1.OperatorResources{
+		IAMUsers: []string{iamUserUHC},
+		Secrets:  []string{fmt.Sprintf("%s/%s", namespace, *secretName)},
+	})
+	if err != nil {
+		return reconcile.Result{}, nil, err
+	}
 	reqLogger.Info("IAM User created and saved", "user", iamUserUHC)
 	return reconcile.Result{}, creds, nil
 }
@@ -621,7 +801,7 @@ func (r *AccountReconciler) HandleNonCCSPendingVerification(reqLogger logr.Logge
 			reqLogger.Info("case created", "CaseID", caseID)
 
 			// Update supportCaseId in CR
-			currentAcctInstance.Status.SupportCaseID = caseID
+			recordCaseCreation(currentAcctInstance, caseID)
 			utils.SetAccountStatus(currentAcctInstance, "Account pending verification in AWS", awsv1alpha1.AccountPendingVerification, AccountPendingVerification)
 			err = SetCurrentAccountServiceQuotas(reqLogger, r.awsClientBuilder, awsSetupClient, currentAcctInstance, r.Client)
 			if err != nil {
@@ -655,12 +835,27 @@ func (r *AccountReconciler) HandleNonCCSPendingVerification(reqLogger logr.Logge
 	var supportCaseResolved bool
 	switch utils.DetectDevMode {
 	case utils.DevModeProduction:
-		resolvedScoped, err := checkCaseResolution(reqLogger, currentAcctInstance.Status.SupportCaseID, awsSetupClient)
+		previousCaseStatus := supportCaseStatus(currentAcctInstance)
+		resolvedScoped, err := checkCaseResolution(reqLogger, currentAcctInstance, awsSetupClient)
+		if errors.Is(err, awsv1alpha1.ErrAwsSupportCaseIDNotFound) {
+			// File a new case rather than waiting for one AWS doesn't know about
+			reqLogger.Info("support case not found, filing a new one", "caseID", currentAcctInstance.Status.SupportCaseID)
+			currentAcctInstance.Status.SupportCaseID = ""
+			currentAcctInstance.Status.SupportCase = nil
+			return reconcile.Result{Requeue: true}, r.statusUpdate(currentAcctInstance)
+		}
 		if err != nil {
 			reqLogger.Error(err, "Error checking for Case Resolution")
 			return reconcile.Result{}, err
 		}
 		supportCaseResolved = resolvedScoped
+		if !supportCaseResolved && supportCaseStatus(currentAcctInstance) != previousCaseStatus {
+			err = r.statusUpdate(currentAcctInstance)
+			if err != nil {
+				reqLogger.Error(err, "failed to record support case status", "caseID", currentAcctInstance.Status.SupportCaseID)
+				return reconcile.Result{}, err
+			}
+		}
 	default:
 		log.Info("Running in development mode, Skipping case resolution check")
 		supportCaseResolved = true
@@ -677,8 +872,21 @@ func (r *AccountReconciler) HandleNonCCSPendingVerification(reqLogger logr.Logge
 	// Case Resolved and quota increases are all done: account is Ready
 	if supportCaseResolved && openCaseCount == 0 {
 		reqLogger.Info("case and quota increases resolved", "caseID", currentAcctInstance.Status.SupportCaseID)
+		// Keep the accounts failing the preflight checks out of the pool until they pass
+		passed, pending, err := r.preflightAccount(reqLogger, awsSetupClient, currentAcctInstance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if pending {
+			return reconcile.Result{RequeueAfter: PreflightRefreshInterval}, nil
+		}
+		if !passed {
+			return reconcile.Result{RequeueAfter: PreflightRecheckInterval}, nil
+		}
 		utils.SetAccountStatus(currentAcctInstance, "Account ready to be claimed", awsv1alpha1.AccountReady, AccountReady)
-		_ = r.statusUpdate(currentAcctInstance)
+		if err := r.statusUpdate(currentAcctInstance); err == nil {
+			cloudevents.Publish(reqLogger, cloudevents.TypeAccountCreated, currentAcctInstance, "Account ready to be claimed")
+		}
 		return reconcile.Result{}, nil
 	}
 
@@ -758,7 +966,13 @@ func SetCurrentAccountServiceQuotas(reqLogger logr.Logger, awsClientBuilder awsc
 func (r *AccountReconciler) finalizeAccount(reqLogger logr.Logger, awsClient awsclient.Client, account *awsv1alpha1.Account) {
 	reqLogger.Info("Finalizing Account CR")
 	if !account.Spec.ManualSTSMode && utils.AccountCRHasIAMUserIDLabel(account) {
-		err := CleanUpIAM(reqLogger, awsClient, account)
+		var err error
+		if account.IsBYOC() {
+			// Only what the operator created is removed from a CCS account, the rest belongs to the customer
+			err = CleanUpCCSAccount(reqLogger, r.Client, awsClient, account)
+		} else {
+			err = CleanUpIAM(reqLogger, awsClient, account)
+		}
 		if err != nil {
 			reqLogger.Error(err, "Failed to delete IAM user during finalizer cleanup")
 		} else {
@@ -767,8 +981,9 @@ func (r *AccountReconciler) finalizeAccount(reqLogger logr.Logger, awsClient aws
 	}
 }
 
-func (r *AccountReconciler) accountSpecUpdate(reqLogger logr.Logger, account *awsv1alpha1.Account) error {
-	err := r.Client.Update(context.TODO(), account)
+// accountSpecUpdate applies mutate to the account and updates it, retrying on conflicts
+func (r *AccountReconciler) accountSpecUpdate(reqLogger logr.Logger, account *awsv1alpha1.Account, mutate func() error) error {
+	err := utils.UpdateWithRetry(r.Client, account, mutate)
 	if err != nil {
 		reqLogger.Error(err, fmt.Sprintf("Account spec update for %s failed", account.Name))
 	}
@@ -779,8 +994,9 @@ func (r *AccountReconciler) nonCCSAssignAccountID(reqLogger logr.Logger, current
 	// Build Aws Account
 	var awsAccountID string
 
-	switch utils.DetectDevMode {
-	case utils.DevModeProduction:
+	switch {
+	// In LocalStack mode the account is created in the simulated organization, even in development mode
+	case utils.DetectDevMode == utils.DevModeProduction, utils.IsLocalStack():
 		var err error
 		awsAccountID, err = r.BuildAccount(reqLogger, awsSetupClient, currentAcctInstance)
 		if err != nil {
@@ -804,16 +1020,17 @@ func (r *AccountReconciler) nonCCSAssignAccountID(reqLogger logr.Logger, current
 		currentAcctInstance.Status.SupportCaseID = "11111111"
 	}
 
-	// update account cr with awsAccountID from aws
-	currentAcctInstance.Spec.AwsAccountID = awsAccountID
-
 	// tag account with hive shard name
 	err = TagAccount(awsSetupClient, awsAccountID, r.shardName)
 	if err != nil {
 		reqLogger.Info("Unable to tag aws account.", "account", currentAcctInstance.Name, "AWSAccountID", awsAccountID, "Error", error.Error(err))
 	}
 
-	return r.accountSpecUpdate(reqLogger, currentAcctInstance)
+	// update account cr with awsAccountID from aws
+	return r.accountSpecUpdate(reqLogger, currentAcctInstance, func() error {
+		currentAcctInstance.Spec.AwsAccountID = awsAccountID
+		return nil
+	})
 }
 
 func TagAccount(awsSetupClient awsclient.Client, awsAccountID string, shardName string) error {
@@ -843,6 +1060,7 @@ func (r *AccountReconciler) initializeRegions(reqLogger logr.Logger, currentAcct
 		AwsCredsSecretAccessKey: *creds.Credentials.SecretAccessKey,
 		AwsToken:                *creds.Credentials.SessionToken,
 		AwsRegion:               awsRegion,
+		AwsAccountID:            currentAcctInstance.Spec.AwsAccountID,
 	})
 	if err != nil {
 		connErr := fmt.Sprintf("unable to connect to default region %s", awsRegion)
@@ -885,6 +1103,7 @@ func (r *AccountReconciler) initializeRegions(reqLogger logr.Logger, currentAcct
 		reqLogger.Info("Accountclaim not found")
 		return acctClaimErr
 	}
+	var missingRegions []string
 	for _, wantedRegion := range accountClaim.Spec.Aws.Regions {
 		found := false
 		for _, enabledRegion := range regionsEnabledInAccount.Regions {
@@ -893,21 +1112,24 @@ func (r *AccountReconciler) initializeRegions(reqLogger logr.Logger, currentAcct
 			}
 		}
 		if !found {
-			utils.SetAccountStatus(
-				currentAcctInstance,
-				fmt.Sprintf("AWS region %s is not supported for AWS account %s", wantedRegion, currentAcctInstance.Name),
-				awsv1alpha1.AccountInitializingRegions, AccountInitializingRegions)
-			if err := r.statusUpdate(currentAcctInstance); err != nil {
-				// statusUpdate logs
-				return err
-			}
+			missingRegions = append(missingRegions, wantedRegion.Name)
+		}
+	}
+	if len(missingRegions) > 0 {
+		utils.SetAccountStatus(
+			currentAcctInstance,
+			fmt.Sprintf("AWS regions %s are not enabled in AWS account %s", strings.Join(missingRegions, ", "), currentAcctInstance.Name),
+			awsv1alpha1.AccountInitializingRegions, AccountInitializingRegions)
+		if err := r.statusUpdate(currentAcctInstance); err != nil {
+			// statusUpdate logs
 			return err
 		}
+		return fmt.Errorf("%w: %s", awsv1alpha1.ErrRegionNotEnabled, strings.Join(missingRegions, ", "))
 	}
 
 	// This initializes supported regions, and updates Account state when that's done. There is
 	// no error checking at this level.
-	// Only initiate the one requested region
+	// Only initiate the requested regions
 	go r.asyncRegionInit(reqLogger, currentAcctInstance, creds, amiOwner, accountClaim.Spec.Aws.Regions)
 
 	return nil
@@ -926,6 +1148,14 @@ func (r *AccountReconciler) asyncRegionInit(reqLogger logr.Logger, currentAcctIn
 	// Initialize all supported regions by creating and terminating an instance in each
 	r.InitializeSupportedRegions(reqLogger, currentAcctInstance, regionsEnabledInAccount, creds, amiOwner)
 
+	// Hardening failures are reported in the account conditions, they don't fail the account
+	if !currentAcctInstance.IsBYOC() {
+		deleteDefaultVPC := !utils.GetKeepDefaultVPCFromAccountPool(reqLogger, currentAcctInstance.Spec.AccountPool, r.Client)
+		if err := HardenAccount(reqLogger, r.Client, r.awsClientBuilder, currentAcctInstance, creds, regionsEnabledInAccount, deleteDefaultVPC); err != nil {
+			reqLogger.Error(err, "Failed to harden account")
+		}
+	}
+
 	if currentAcctInstance.IsBYOC() {
 		utils.SetAccountStatus(currentAcctInstance, "BYOC Account Ready", awsv1alpha1.AccountReady, AccountReady)
 
@@ -952,11 +1182,22 @@ func (r *AccountReconciler) asyncRegionInit(reqLogger logr.Logger, currentAcctIn
 func (r *AccountReconciler) BuildAccount(reqLogger logr.Logger, awsClient awsclient.Client, account *awsv1alpha1.Account) (string, error) {
 	reqLogger.Info("Creating Account")
 
-	email := formatAccountEmail(account.Name)
-	orgOutput, orgErr := CreateAccount(reqLogger, awsClient, account.Name, email)
+	tmpl, err := r.getAccountEmailTemplate(reqLogger)
+	if err != nil {
+		return "", err
+	}
+
+	orgOutput, orgErr := createAccountWithUnusedEmail(reqLogger, awsClient, account, tmpl)
 	// If it was an api or a limit issue don't modify account and exit if anything else set to failed
 	if orgErr != nil {
 		switch orgErr {
+		case awsv1alpha1.ErrAccountEmailInUse:
+			utils.SetAccountStatus(account, "Failed to create AWS Account: no unused account email", awsv1alpha1.AccountCreationFailed, AccountFailed)
+			if err := r.statusUpdate(account); err != nil {
+				return "", err
+			}
+			return "", orgErr
+
 		case awsv1alpha1.ErrAwsFailedCreateAccount:
 			utils.SetAccountStatus(account, "Failed to create AWS Account", awsv1alpha1.AccountCreationFailed, AccountFailed)
 			err := r.statusUpdate(account)
@@ -978,7 +1219,7 @@ func (r *AccountReconciler) BuildAccount(reqLogger logr.Logger, awsClient awscli
 	}
 
 	accountObjectKey := client.ObjectKeyFromObject(account)
-	err := r.Client.Get(context.TODO(), accountObjectKey, account)
+	err = r.Client.Get(context.TODO(), accountObjectKey, account)
 	if err != nil {
 		reqLogger.Error(err, "Unable to get updated Account object after status update")
 	}
@@ -994,6 +1235,11 @@ func CreateAccount(reqLogger logr.Logger, client awsclient.Client, accountName,
 	createInput := organizations.CreateAccountInput{
 		AccountName: aws.String(accountName),
 		Email:       aws.String(accountEmail),
+		// Tells the AWS accounts created by the operator apart, e.g. before adopting an orphaned one
+		Tags: []*organizations.Tag{{
+			Key:   aws.String(awsv1alpha1.CreatedByTagKey),
+			Value: aws.String(awsv1alpha1.CreatedByTagValue),
+		}},
 	}
 
 	createOutput, err := client.CreateAccount(&createInput)
@@ -1040,6 +1286,8 @@ func CreateAccount(reqLogger logr.Logger, client awsclient.Client, accountName,
 				returnErr = awsv1alpha1.ErrAwsAccountLimitExceeded
 			case "INTERNAL_FAILURE":
 				returnErr = awsv1alpha1.ErrAwsInternalFailure
+			case organizations.CreateAccountFailureReasonEmailAlreadyExists:
+				returnErr = awsv1alpha1.ErrAccountEmailInUse
 			default:
 				returnErr = awsv1alpha1.ErrAwsFailedCreateAccount
 			}
@@ -1074,22 +1322,43 @@ func ClaimAccount(r *AccountReconciler, currentAcctInstance *awsv1alpha1.Account
 	return r.statusUpdate(currentAcctInstance)
 }
 
-func formatAccountEmail(name string) string {
-	// osd-creds-mgmt
-	// libra-ops
-	splitString := strings.Split(name, "-")
-	prefix := splitString[0]
-	for i := 1; i < (len(splitString) - 1); i++ {
-		prefix = prefix + "-" + splitString[i]
-	}
+func (r *AccountReconciler) statusUpdate(account *awsv1alpha1.Account) error {
+	err := utils.UpdateStatus(r.Client, account)
+	return err
+}
 
-	email := prefix + "+" + splitString[len(splitString)-1] + "@redhat.com"
-	return email
+// recordOperatorResources records resources created in a CCS account, so that the cleanup removes them and
+// nothing else
+func (r *AccountReconciler) recordOperatorResources(reqLogger logr.Logger, account *awsv1alpha1.Account, resources awsv1alpha1.OperatorResources) error {
+	if !account.IsBYOC() || !account.DeepCopy().RecordOperatorResources(resources) {
+		return nil
+	}
+	err := utils.UpdateStatusWithRetry(r.Client, account, func() error {
+		account.RecordOperatorResources(resources)
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to record operator resources in Account status")
+		return err
+	}
+	return nil
 }
 
-func (r *AccountReconciler) statusUpdate(account *awsv1alpha1.Account) error {
-	err := r.Client.Status().Update(context.TODO(), account)
-	return err
+// recordIAMUserName records the name of the IAM user created for the cluster in the Account status, so
+// credential rotation and cleanup don't have to derive it
+func (r *AccountReconciler) recordIAMUserName(reqLogger logr.Logger, account *awsv1alpha1.Account, userName string) error {
+	if account.Status.IAMUserName == userName {
+		return nil
+	}
+	err := utils.UpdateStatusWithRetry(r.Client, account, func() error {
+		account.Status.IAMUserName = userName
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to record IAM user name in Account status")
+		return err
+	}
+	return nil
 }
 
 func (r *AccountReconciler) setAccountFailed(reqLogger logr.Logger, account *awsv1alpha1.Account, ctype awsv1alpha1.AccountConditionType, reason string, message string, state string) (reconcile.Result, error) {
@@ -1105,6 +1374,9 @@ func (r *AccountReconciler) setAccountFailed(reqLogger logr.Logger, account *aws
 		account.Spec.BYOC,
 	)
 	account.Status.State = state
+	utils.RecordEvent(r.recorder, account, corev1.EventTypeWarning, utils.EventReasonAccountQuarantined, "Account set to %s: %s", state, message)
+	cloudevents.Publish(reqLogger, cloudevents.TypeAccountQuarantined, account, message)
+	notifier.Notify(reqLogger, notifier.ForAccount(notifier.KindAccountFailed, account, fmt.Sprintf("Account %s set to %s", account.Name, state), fmt.Sprintf("%s: %s", reason, message)))
 
 	// Set the failure in the accountClaim as well
 	err := r.accountClaimError(reqLogger, account, reason, message)
@@ -1143,20 +1415,21 @@ func (r *AccountReconciler) accountClaimError(reqLogger logr.Logger, account *aw
 		return err
 	}
 
-	accountClaim = r.failAllAccountClaimStatus(accountClaim)
-	accountClaim.Status.Conditions = utils.SetAccountClaimCondition(
-		accountClaim.Status.Conditions,
-		awsv1alpha1.InternalError,
-		corev1.ConditionTrue,
-		reason,
-		message,
-		utils.UpdateConditionIfReasonOrMessageChange,
-		accountClaim.Spec.BYOCAWSAccountID != "",
-	)
-	accountClaim.Status.State = awsv1alpha1.ClaimStatusError
-
 	// Update the *accountClaim* status (not the account status)
-	err = r.Client.Status().Update(context.TODO(), accountClaim)
+	err = utils.UpdateStatusWithRetry(r.Client, accountClaim, func() error {
+		accountClaim = r.failAllAccountClaimStatus(accountClaim)
+		accountClaim.Status.Conditions = utils.SetAccountClaimCondition(
+			accountClaim.Status.Conditions,
+			awsv1alpha1.InternalError,
+			corev1.ConditionTrue,
+			reason,
+			message,
+			utils.UpdateConditionIfReasonOrMessageChange,
+			accountClaim.Spec.BYOCAWSAccountID != "",
+		)
+		accountClaim.Status.State = awsv1alpha1.ClaimStatusError
+		return nil
+	})
 	if err != nil {
 		reqLogger.Error(err, "failed to update accountclaim status", "accountclaim", accountClaim.Name)
 	}
@@ -1196,20 +1469,20 @@ func (r *AccountReconciler) setAccountClaimError(reqLogger logr.Logger, currentA
 		reason = string(awsv1alpha1.AccountClaimFailed)
 	}
 
-	accountClaim.Status.Conditions = utils.SetAccountClaimCondition(
-		accountClaim.Status.Conditions,
-		conditionType,
-		corev1.ConditionTrue,
-		reason,
-		message,
-		utils.UpdateConditionIfReasonOrMessageChange,
-		accountClaim.Spec.BYOCAWSAccountID != "",
-	)
-
-	accountClaim.Status.State = awsv1alpha1.ClaimStatusError
-
 	// Update the *accountClaim* status (not the account status)
-	err = r.Client.Status().Update(context.TODO(), accountClaim)
+	err = utils.UpdateStatusWithRetry(r.Client, accountClaim, func() error {
+		accountClaim.Status.Conditions = utils.SetAccountClaimCondition(
+			accountClaim.Status.Conditions,
+			conditionType,
+			corev1.ConditionTrue,
+			reason,
+			message,
+			utils.UpdateConditionIfReasonOrMessageChange,
+			accountClaim.Spec.BYOCAWSAccountID != "",
+		)
+		accountClaim.Status.State = awsv1alpha1.ClaimStatusError
+		return nil
+	})
 	if err != nil {
 		reqLogger.Error(err, "failed to update accountclaim status", "accountclaim", accountClaim.Name)
 	}
@@ -1347,14 +1620,16 @@ func (r *AccountReconciler) handleCreateAdminAccessRole(
 		accountClaim, acctClaimErr := r.getAccountClaim(currentAcctInstance)
 		if acctClaimErr != nil {
 			if accountClaim != nil {
-				utils.SetAccountClaimStatus(
-					accountClaim,
-					"Failed to get AccountClaim for Account",
-					"FailedRetrievingAccountClaim",
-					awsv1alpha1.ClientError,
-					awsv1alpha1.ClaimStatusError,
-				)
-				err := r.Client.Status().Update(context.TODO(), accountClaim)
+				err := utils.UpdateStatusWithRetry(r.Client, accountClaim, func() error {
+					utils.SetAccountClaimStatus(
+						accountClaim,
+						"Failed to get AccountClaim for Account",
+						"FailedRetrievingAccountClaim",
+						awsv1alpha1.ClientError,
+						awsv1alpha1.ClaimStatusError,
+					)
+					return nil
+				})
 				if err != nil {
 					reqLogger.Error(err, "failed to update accountclaim status")
 				}
@@ -1383,6 +1658,13 @@ func (r *AccountReconciler) handleCreateAdminAccessRole(
 			return nil, nil, err
 		}
 
+		err = r.recordOperatorResources(reqLogger, currentAcctInstance, awsv1alpha1.OperatorResources{
+			IAMRoles: []string{fmt.Sprintf("%s-%s", awsv1alpha1.ManagedOpenShiftSupportRole, currentAccInstanceID)},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
 		awsAssumedRoleClient, creds, err = AssumeRoleAndCreateClient(reqLogger, r.awsClientBuilder, currentAcctInstance, r.Client, awsSetupClient, "", roleToAssume, roleID)
 		if err != nil {
 			return nil, nil, err
@@ -1409,6 +1691,12 @@ func (r *AccountReconciler) handleCreateAdminAccessRole(
 			reqLogger.Error(err, "Encountered error while creating ManagedOpenShiftSupportRole for non-CCS Account", "roleID", roleID)
 			return nil, nil, err
 		}
+
+		err = EnsureSREAccessRole(reqLogger, r.Client, awsAssumedRoleClient, currentAcctInstance, tags)
+		if err != nil {
+			reqLogger.Error(err, "Encountered error while ensuring SRE access role for non-CCS Account")
+			return nil, nil, err
+		}
 	}
 
 	return awsAssumedRoleClient, creds, err
@@ -1418,6 +1706,7 @@ func (r *AccountReconciler) handleCreateAdminAccessRole(
 func (r *AccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	r.awsClientBuilder = &awsclient.Builder{}
+	r.recorder = mgr.GetEventRecorderFor(controllerName)
 
 	maxReconciles, err := utils.GetControllerMaxReconciles(controllerName)
 	if err != nil {
@@ -1439,7 +1728,8 @@ func (r *AccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	rwm := utils.NewReconcilerWithMetrics(r, controllerName)
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&awsv1alpha1.Account{}).
+		For(&awsv1alpha1.Account{}, builder.WithPredicates(utils.InShardFilter, utils.IgnoreReconcileOutcomeUpdates)).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(accountForSecret), builder.WithPredicates(iamUserSecretChanged)).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxReconciles,
 		}).Complete(rwm)
@@ -144,6 +144,12 @@ func (t *testAccountBuilder) WithLabels(labels map[string]string) *testAccountBu
 	return t
 }
 
+// Add annotations
+func (t *testAccountBuilder) WithAnnotations(annotations map[string]string) *testAccountBuilder {
+	t.acct.ObjectMeta.Annotations = annotations
+	return t
+}
+
 // Add a state string
 func (t *testAccountBuilder) WithState(state awsv1alpha1.AccountConditionType) *testAccountBuilder {
 	t.acct.Status.State = string(state)
@@ -1269,6 +1275,88 @@ func TestFinalizeAccount_LabelledBYOCAccount(t *testing.T) {
 	r.finalizeAccount(nullLogger, mockAWSClient, &account)
 }
 
+func TestIsClaimedDeletionBlocked(t *testing.T) {
+	err := apis.AddToScheme(scheme.Scheme)
+	if err != nil {
+		fmt.Printf("failed adding to scheme in account_controller_test.go")
+	}
+	nullLogger := testutils.NewTestLogger().Logger()
+
+	claimName := "testclaim"
+	liveClaim := &awsv1alpha1.AccountClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claimName,
+			Namespace: TestAccountNamespace,
+		},
+	}
+	deletingClaim := liveClaim.DeepCopy()
+	deletingClaim.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	deletingClaim.Finalizers = []string{awsv1alpha1.AccountFinalizer}
+
+	tests := []struct {
+		name     string
+		acct     *testAccountBuilder
+		claim    *awsv1alpha1.AccountClaim
+		expected bool
+	}{
+		{
+			name:     "Unclaimed account",
+			acct:     newTestAccountBuilder(),
+			expected: false,
+		},
+		{
+			name:     "Claimed account with live claim",
+			acct:     newTestAccountBuilder().Claimed(true).WithClaimLink(claimName).WithClaimLinkNamespace(TestAccountNamespace),
+			claim:    liveClaim,
+			expected: true,
+		},
+		{
+			name:     "Claimed account with claim being deleted",
+			acct:     newTestAccountBuilder().Claimed(true).WithClaimLink(claimName).WithClaimLinkNamespace(TestAccountNamespace),
+			claim:    deletingClaim,
+			expected: false,
+		},
+		{
+			name:     "Claimed account with missing claim",
+			acct:     newTestAccountBuilder().Claimed(true).WithClaimLink(claimName).WithClaimLinkNamespace(TestAccountNamespace),
+			expected: false,
+		},
+		{
+			name: "Claimed account with override annotation",
+			acct: newTestAccountBuilder().Claimed(true).WithClaimLink(claimName).WithClaimLinkNamespace(TestAccountNamespace).
+				WithAnnotations(map[string]string{awsv1alpha1.AllowClaimedDeletionAnnotation: "true"}),
+			claim:    liveClaim,
+			expected: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			localObjects := []runtime.Object{&test.acct.acct}
+			if test.claim != nil {
+				localObjects = append(localObjects, test.claim)
+			}
+			mocks := setupDefaultMocks(t, localObjects)
+			defer mocks.mockCtrl.Finish()
+
+			r := AccountReconciler{
+				Client: mocks.fakeKubeClient,
+				Scheme: scheme.Scheme,
+			}
+			result, err := r.isClaimedDeletionBlocked(nullLogger, &test.acct.acct)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if result != test.expected {
+				t.Error(
+					"for account:", test.acct,
+					"expected", test.expected,
+					"got", result,
+				)
+			}
+		})
+	}
+}
+
 var _ = Describe("Account Controller", func() {
 	var (
 		nullTestLogger testutils.TestLogger
@@ -1445,6 +1533,7 @@ var _ = Describe("Account Controller", func() {
 		It("Should not modify the AccountCR when encountering a known error during Account Creation", func() {
 			account = &newTestAccountBuilder().WithoutState().acct
 			account.Name = accountName
+			r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{account, configMap}...).Build()
 			for errCode, knownErr := range knownErrors {
 				mockAWSClient.EXPECT().CreateAccount(gomock.Any()).Return(nil, awserr.New(errCode, "Error String", nil))
 				acctId, actualErr := r.BuildAccount(nullLogger, mockAWSClient, account)
@@ -1468,6 +1557,67 @@ var _ = Describe("Account Controller", func() {
 			Expect(nullTestLogger.Messages()).Should(ContainElement(ContainSubstring(organizations.ErrCodeAccessDeniedException)))
 			Expect(account.Status.State).To(BeEquivalentTo(awsv1alpha1.AccountFailed))
 		})
+
+		It("Should create the account with the email of the configured template", func() {
+			emailAccount := &newTestAccountBuilder().WithoutState().acct
+			emailAccount.Name = "osd-creds-mgmt-abc123"
+			configMap.Data[awsv1alpha1.AccountEmailTemplateConfigMapKey] = "aws-{{.ID}}@example.com"
+			r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{emailAccount, configMap}...).Build()
+			mockAWSClient.EXPECT().CreateAccount(&organizations.CreateAccountInput{
+				AccountName: aws.String("osd-creds-mgmt-abc123"),
+				Email:       aws.String("aws-abc123@example.com"),
+				Tags:        []*organizations.Tag{{Key: aws.String(awsv1alpha1.CreatedByTagKey), Value: aws.String(awsv1alpha1.CreatedByTagValue)}},
+			}).Return(nil, awserr.New(organizations.ErrCodeServiceException, "Error String", nil))
+			_, actualErr := r.BuildAccount(nullLogger, mockAWSClient, emailAccount)
+			Expect(actualErr).To(MatchError(awsv1alpha1.ErrAwsInternalFailure))
+		})
+
+		It("Should set the Account to failed when its email is already used", func() {
+			emailAccount := &newTestAccountBuilder().WithoutState().acct
+			emailAccount.Name = "osd-creds-mgmt-abc123"
+			r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{emailAccount, configMap}...).Build()
+			mockAWSClient.EXPECT().CreateAccount(gomock.Any()).Return(&organizations.CreateAccountOutput{
+				CreateAccountStatus: &organizations.CreateAccountStatus{Id: aws.String("ID")},
+			}, nil)
+			mockAWSClient.EXPECT().DescribeCreateAccountStatus(gomock.Any()).Return(&organizations.DescribeCreateAccountStatusOutput{
+				CreateAccountStatus: &organizations.CreateAccountStatus{
+					State:         aws.String("FAILED"),
+					FailureReason: aws.String(organizations.CreateAccountFailureReasonEmailAlreadyExists),
+				},
+			}, nil)
+			mockAWSClient.EXPECT().ListAccounts(gomock.Any()).Times(0)
+			acctId, actualErr := r.BuildAccount(nullLogger, mockAWSClient, emailAccount)
+			Expect(actualErr).To(MatchError(awsv1alpha1.ErrAccountEmailInUse))
+			Expect(acctId).To(BeEmpty())
+			Expect(emailAccount.Status.State).To(BeEquivalentTo(awsv1alpha1.AccountFailed))
+		})
+
+		It("Should render a random email again when it's already used", func() {
+			emailAccount := &newTestAccountBuilder().WithoutState().acct
+			emailAccount.Name = "osd-creds-mgmt-abc123"
+			configMap.Data[awsv1alpha1.AccountEmailTemplateConfigMapKey] = "aws-{{random 8}}@example.com"
+			r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{emailAccount, configMap}...).Build()
+			mockAWSClient.EXPECT().CreateAccount(gomock.Any()).Return(&organizations.CreateAccountOutput{
+				CreateAccountStatus: &organizations.CreateAccountStatus{Id: aws.String("ID")},
+			}, nil).Times(2)
+			gomock.InOrder(
+				mockAWSClient.EXPECT().DescribeCreateAccountStatus(gomock.Any()).Return(&organizations.DescribeCreateAccountStatusOutput{
+					CreateAccountStatus: &organizations.CreateAccountStatus{
+						State:         aws.String("FAILED"),
+						FailureReason: aws.String(organizations.CreateAccountFailureReasonEmailAlreadyExists),
+					},
+				}, nil),
+				mockAWSClient.EXPECT().DescribeCreateAccountStatus(gomock.Any()).Return(&organizations.DescribeCreateAccountStatusOutput{
+					CreateAccountStatus: &organizations.CreateAccountStatus{
+						State:     aws.String("SUCCEEDED"),
+						AccountId: aws.String("123456789012"),
+					},
+				}, nil),
+			)
+			acctId, actualErr := r.BuildAccount(nullLogger, mockAWSClient, emailAccount)
+			Expect(actualErr).NotTo(HaveOccurred())
+			Expect(acctId).To(Equal("123456789012"))
+		})
 	})
 
 	Context("Testing Reconciliation", func() {
@@ -1585,6 +1735,11 @@ var _ = Describe("Account Controller", func() {
 				}},
 			}, nil)
 
+			mockAWSClient.EXPECT().UpdateAccountPasswordPolicy(gomock.Any()).Return(&iam.UpdateAccountPasswordPolicyOutput{}, nil)
+			mockAWSClient.EXPECT().GetAccountSummary(gomock.Any()).Return(&iam.GetAccountSummaryOutput{
+				SummaryMap: map[string]*int64{"AccountAccessKeysPresent": aws.Int64(0)},
+			}, nil)
+
 			userName := "osdManagedAdmin-abcdef"
 			mockAWSClient.EXPECT().GetUser(&iam.GetUserInput{
 				UserName: &userName,
@@ -1809,6 +1964,11 @@ var _ = Describe("Account Controller", func() {
 				}},
 			}, nil)
 
+			mockAWSClient.EXPECT().UpdateAccountPasswordPolicy(gomock.Any()).Return(&iam.UpdateAccountPasswordPolicyOutput{}, nil)
+			mockAWSClient.EXPECT().GetAccountSummary(gomock.Any()).Return(&iam.GetAccountSummaryOutput{
+				SummaryMap: map[string]*int64{"AccountAccessKeysPresent": aws.Int64(0)},
+			}, nil)
+
 			userName := "osdManagedAdmin-abcdef"
 			mockAWSClient.EXPECT().GetUser(&iam.GetUserInput{
 				UserName: &userName,
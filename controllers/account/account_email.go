@@ -0,0 +1,122 @@
+package account
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// defaultAccountEmailTemplate builds the email of an Account named osd-creds-mgmt-abc123 as
+// osd-creds-mgmt+abc123@redhat.com
+const defaultAccountEmailTemplate = "{{.Prefix}}+{{.ID}}@redhat.com"
+
+// accountEmailAttempts is how many emails are rendered for an account before giving up on finding one no
+// account of the organization uses. Only templates with a random component render different emails.
+const accountEmailAttempts = 5
+
+// accountEmailData is what the account email template is rendered with
+type accountEmailData struct {
+	// Name is the name of the Account CR
+	Name string
+	// Prefix is the name of the Account CR without its ID, e.g. osd-creds-mgmt
+	Prefix string
+	// ID is the last part of the name of the Account CR, e.g. abc123
+	ID string
+}
+
+// parseAccountEmailTemplate parses the email template of new accounts. Besides the fields of accountEmailData,
+// the template can call random with a length to add random lowercase letters and digits, e.g. {{random 4}}.
+func parseAccountEmailTemplate(text string) (*template.Template, error) {
+	return template.New("account-email").
+		Funcs(template.FuncMap{"random": rand.String}).
+		Option("missingkey=error").
+		Parse(text)
+}
+
+// renderAccountEmail renders the email template for the Account CR with the given name
+func renderAccountEmail(tmpl *template.Template, name string) (string, error) {
+	data := accountEmailData{Name: name, Prefix: name}
+	if i := strings.LastIndex(name, "-"); i >= 0 {
+		data.Prefix = name[:i]
+		data.ID = name[i+1:]
+	}
+
+	var email bytes.Buffer
+	if err := tmpl.Execute(&email, data); err != nil {
+		return "", err
+	}
+	if !strings.Contains(email.String(), "@") {
+		return "", fmt.Errorf("account email template rendered %q which isn't an email", email.String())
+	}
+	return email.String(), nil
+}
+
+// getAccountEmailTemplate returns the email template of new accounts from the operator configmap, or the
+// default template when the configmap doesn't set one
+func (r *AccountReconciler) getAccountEmailTemplate(reqLogger logr.Logger) (*template.Template, error) {
+	text := defaultAccountEmailTemplate
+	configMap, err := utils.GetOperatorConfigMap(r.Client)
+	if err != nil {
+		reqLogger.Info("Could not retrieve the operator configmap, using the default account email template")
+	} else if v, ok := configMap.Data[awsv1alpha1.AccountEmailTemplateConfigMapKey]; ok && strings.TrimSpace(v) != "" {
+		text = strings.TrimSpace(v)
+	}
+
+	tmpl, err := parseAccountEmailTemplate(text)
+	if err != nil {
+		reqLogger.Error(err, "Invalid account email template", "template", text)
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// listOrganizationAccounts returns all the accounts of the organization
+func listOrganizationAccounts(awsClient awsclient.Client) ([]*organizations.Account, error) {
+	var accounts []*organizations.Account
+	var nextToken *string
+	for {
+		output, err := awsClient.ListAccounts(&organizations.ListAccountsInput{NextToken: nextToken})
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, output.Accounts...)
+		if output.NextToken == nil {
+			return accounts, nil
+		}
+		nextToken = output.NextToken
+	}
+}
+
+// createAccountWithUnusedEmail creates the AWS account with an email rendered from the template. AWS fails the
+// creation when an account of the organization uses the email already, which is checked rather than listing the
+// whole organization for every new account. A template with a random component is rendered again on a collision,
+// otherwise ErrAccountEmailInUse is returned.
+func createAccountWithUnusedEmail(reqLogger logr.Logger, awsClient awsclient.Client, account *awsv1alpha1.Account, tmpl *template.Template) (*organizations.DescribeCreateAccountStatusOutput, error) {
+	usedEmails := map[string]bool{}
+	for attempt := 0; attempt < accountEmailAttempts; attempt++ {
+		email, err := renderAccountEmail(tmpl, account.Name)
+		if err != nil {
+			reqLogger.Error(err, "Failed to render the account email")
+			return &organizations.DescribeCreateAccountStatusOutput{}, err
+		}
+		if usedEmails[strings.ToLower(email)] {
+			continue
+		}
+
+		output, err := CreateAccount(reqLogger, awsClient, account.Name, email)
+		if err != awsv1alpha1.ErrAccountEmailInUse {
+			return output, err
+		}
+		reqLogger.Info("Account email already used in the organization", "email", email)
+		usedEmails[strings.ToLower(email)] = true
+	}
+	return &organizations.DescribeCreateAccountStatusOutput{}, awsv1alpha1.ErrAccountEmailInUse
+}
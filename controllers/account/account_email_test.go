@@ -0,0 +1,52 @@
+package account
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderAccountEmail(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		expected  *regexp.Regexp
+		expectErr bool
+	}{
+		{
+			name:     "Default template",
+			template: defaultAccountEmailTemplate,
+			expected: regexp.MustCompile(`^osd-creds-mgmt\+abc123@redhat\.com$`),
+		},
+		{
+			name:     "Template with a random component",
+			template: "aws+{{.Name}}-{{random 4}}@example.com",
+			expected: regexp.MustCompile(`^aws\+osd-creds-mgmt-abc123-[a-z0-9]{4}@example\.com$`),
+		},
+		{
+			name:      "Template not rendering an email",
+			template:  "{{.ID}}",
+			expectErr: true,
+		},
+		{
+			name:      "Template with an unknown field",
+			template:  "{{.Unknown}}@example.com",
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := parseAccountEmailTemplate(tt.template)
+			assert.NoError(t, err)
+
+			email, err := renderAccountEmail(tmpl, "osd-creds-mgmt-abc123")
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Regexp(t, tt.expected, email)
+		})
+	}
+}
@@ -5,17 +5,24 @@ import (
 
 	"github.com/go-logr/logr"
 	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/cloudevents"
 	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func (r *AccountReconciler) addFinalizer(reqLogger logr.Logger, account *awsv1alpha1.Account) error {
 
 	if !controllerutils.Contains(account.GetFinalizers(), awsv1alpha1.AccountFinalizer) {
 		reqLogger.Info("Adding Finalizer for the Account")
-		account.SetFinalizers(append(account.GetFinalizers(), awsv1alpha1.AccountFinalizer))
 
 		// Update CR
-		err := r.Client.Update(context.TODO(), account)
+		err := controllerutils.UpdateWithRetry(r.Client, account, func() error {
+			if !controllerutils.Contains(account.GetFinalizers(), awsv1alpha1.AccountFinalizer) {
+				account.SetFinalizers(append(account.GetFinalizers(), awsv1alpha1.AccountFinalizer))
+			}
+			return nil
+		})
 		if err != nil {
 			reqLogger.Error(err, "Failed to update Account with finalizer")
 			return err
@@ -26,10 +33,37 @@ func (r *AccountReconciler) addFinalizer(reqLogger logr.Logger, account *awsv1al
 
 // Function to remove finalizer
 func (r *AccountReconciler) removeFinalizer(account *awsv1alpha1.Account, finalizerName string) error {
-	account.SetFinalizers(controllerutils.Remove(account.GetFinalizers(), finalizerName))
-	err := r.Client.Update(context.TODO(), account)
+	err := controllerutils.UpdateWithRetry(r.Client, account, func() error {
+		account.SetFinalizers(controllerutils.Remove(account.GetFinalizers(), finalizerName))
+		return nil
+	})
 	if err != nil {
 		return err
 	}
+	if account.IsPendingDeletion() {
+		cloudevents.Publish(log, cloudevents.TypeAccountClosed, account, "Account CR finalized and deleted")
+	}
 	return nil
 }
+
+// isClaimedDeletionBlocked returns true if the account is still claimed by an AccountClaim that is not
+// itself being deleted. Deleting such an account would orphan the credentials of a live cluster, so the
+// finalizer is kept in place until the claim goes away or the override annotation is set.
+func (r *AccountReconciler) isClaimedDeletionBlocked(reqLogger logr.Logger, account *awsv1alpha1.Account) (bool, error) {
+	if !account.IsClaimed() || !account.HasClaimLink() || account.AllowsClaimedDeletion() {
+		return false, nil
+	}
+
+	accountClaim := &awsv1alpha1.AccountClaim{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: account.Spec.ClaimLink, Namespace: account.Spec.ClaimLinkNamespace}, accountClaim)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return false, nil
+		}
+		reqLogger.Error(err, "Failed to get AccountClaim for claimed account pending deletion")
+		return true, err
+	}
+
+	// The AccountClaim finalizer deletes BYOC and STS accounts as part of its own cleanup
+	return accountClaim.DeletionTimestamp == nil, nil
+}
@@ -0,0 +1,263 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/health"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultAccountHealthCheckInterval is how often AWS Health is checked unless the configmap says
+	// otherwise. An interval of 0 disables the check.
+	defaultAccountHealthCheckInterval = 15 * time.Minute
+
+	// awsHealthAbuseService is the service of the AWS Health events about abuse reports
+	awsHealthAbuseService = "ABUSE"
+	// awsHealthAbuseEventTypePrefix prefixes the type codes of the AWS Health events about abuse reports
+	awsHealthAbuseEventTypePrefix = "AWS_ABUSE_"
+
+	awsHealthOKReason    = "AWSHealthOK"
+	accountHealthWatcher = "account-health-watcher"
+)
+
+// AccountHealthWatcher periodically asks AWS Health and AWS Organizations about the accounts of the
+// organization. The non-CCS accounts that are suspended, under abuse review or affected by an open
+// operational issue are flagged with the Degraded condition, which keeps them from being claimed.
+type AccountHealthWatcher struct {
+	client           client.Client
+	awsClientBuilder awsclient.IBuilder
+	recorder         record.EventRecorder
+}
+
+// NewAccountHealthWatcher returns a new AccountHealthWatcher
+func NewAccountHealthWatcher(client client.Client, awsClientBuilder awsclient.IBuilder, recorder record.EventRecorder) *AccountHealthWatcher {
+	return &AccountHealthWatcher{
+		client:           client,
+		awsClientBuilder: awsClientBuilder,
+		recorder:         recorder,
+	}
+}
+
+// Start checks the accounts every interval configured in the configmap, until the operator is stopped
+func (w *AccountHealthWatcher) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the account health watcher")
+	for {
+		interval := getAccountHealthCheckInterval(log, w.client)
+		if interval == 0 {
+			// Check again later whether the check has been enabled
+			interval = defaultAccountHealthCheckInterval
+		} else if err := w.CheckAccounts(log); err != nil {
+			log.Error(err, "Failed to check the health of the accounts")
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-stopCh.Done():
+			log.Info("Stopping the account health watcher")
+			return
+		}
+	}
+}
+
+// getAccountHealthCheckInterval reads the health check interval from the configmap, falling back to the
+// default when it's missing or invalid
+func getAccountHealthCheckInterval(log logr.Logger, kubeClient client.Client) time.Duration {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		log.Error(err, "Unable to get the account health check interval from the configmap, using the default")
+		return defaultAccountHealthCheckInterval
+	}
+
+	value, ok := configMap.Data[awsv1alpha1.AccountHealthCheckIntervalConfigMapKey]
+	if !ok || value == "" {
+		return defaultAccountHealthCheckInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Error(err, "Invalid account health check interval in configmap, using the default", "value", value)
+		return defaultAccountHealthCheckInterval
+	}
+	return interval
+}
+
+// CheckAccounts flags the non-CCS accounts AWS reports problems for with the Degraded condition, and
+// clears the condition of the accounts whose problems are gone
+func (w *AccountHealthWatcher) CheckAccounts(log logr.Logger) error {
+	accountList := &awsv1alpha1.AccountList{}
+	err := w.client.List(context.TODO(), accountList, client.InNamespace(awsv1alpha1.AccountCrNamespace))
+	if err != nil {
+		return err
+	}
+
+	awsSetupClient, err := w.awsClientBuilder.GetClient(accountHealthWatcher, w.client, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		return err
+	}
+
+	problems, err := getAccountHealthProblems(awsSetupClient)
+	if err != nil {
+		return err
+	}
+
+	for i := range accountList.Items {
+		account := &accountList.Items[i]
+		// CCS accounts belong to the customer's organization
+		if !account.HasAwsAccountID() || account.IsBYOC() || account.IsPendingDeletion() {
+			continue
+		}
+		reqLogger := log.WithValues("account", account.Name, "awsAccountID", account.Spec.AwsAccountID)
+		if !w.setHealthCondition(reqLogger, account, problems[account.Spec.AwsAccountID]) {
+			continue
+		}
+		err = utils.UpdateAccountConditionsWithRetry(w.client, account, awsv1alpha1.AccountDegraded)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update account status")
+		}
+	}
+	return nil
+}
+
+// getAccountHealthProblems returns the problems of the accounts of the organization by AWS account ID:
+// suspended accounts, accounts under abuse review and accounts affected by an open operational issue
+func getAccountHealthProblems(awsClient awsclient.Client) (map[string][]string, error) {
+	problems := map[string][]string{}
+
+	orgAccounts, err := listOrganizationAccounts(awsClient)
+	if err != nil {
+		return nil, err
+	}
+	for _, orgAccount := range orgAccounts {
+		if aws.StringValue(orgAccount.Status) == organizations.AccountStatusSuspended {
+			accountID := aws.StringValue(orgAccount.Id)
+			problems[accountID] = append(problems[accountID], "account is suspended")
+		}
+	}
+
+	events, err := listOpenHealthEvents(awsClient)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		var problem string
+		switch {
+		case isAbuseHealthEvent(event):
+			problem = fmt.Sprintf("under abuse review (%s)", aws.StringValue(event.EventTypeCode))
+		case aws.StringValue(event.EventTypeCategory) == health.EventTypeCategoryIssue:
+			problem = fmt.Sprintf("open operational issue %s", aws.StringValue(event.EventTypeCode))
+		default:
+			continue
+		}
+
+		accountIDs, err := listHealthEventAffectedAccounts(awsClient, event.Arn)
+		if err != nil {
+			return nil, err
+		}
+		for _, accountID := range accountIDs {
+			problems[accountID] = append(problems[accountID], problem)
+		}
+	}
+
+	for accountID := range problems {
+		sort.Strings(problems[accountID])
+	}
+	return problems, nil
+}
+
+// listOpenHealthEvents returns the open AWS Health issues and account notifications that affect specific
+// accounts of the organization. Public events affect every AWS customer of a region and aren't held
+// against any account.
+func listOpenHealthEvents(awsClient awsclient.Client) ([]*health.OrganizationEvent, error) {
+	var events []*health.OrganizationEvent
+	var nextToken *string
+	for {
+		output, err := awsClient.DescribeEventsForOrganization(&health.DescribeEventsForOrganizationInput{
+			Filter: &health.OrganizationEventFilter{
+				EventStatusCodes:    aws.StringSlice([]string{health.EventStatusCodeOpen}),
+				EventTypeCategories: aws.StringSlice([]string{health.EventTypeCategoryIssue, health.EventTypeCategoryAccountNotification}),
+			},
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range output.Events {
+			if aws.StringValue(event.EventScopeCode) == health.EventScopeCodeAccountSpecific {
+				events = append(events, event)
+			}
+		}
+		if output.NextToken == nil {
+			return events, nil
+		}
+		nextToken = output.NextToken
+	}
+}
+
+// listHealthEventAffectedAccounts returns the IDs of the accounts of the organization affected by the event
+func listHealthEventAffectedAccounts(awsClient awsclient.Client, eventArn *string) ([]string, error) {
+	var accountIDs []string
+	var nextToken *string
+	for {
+		output, err := awsClient.DescribeAffectedAccountsForOrganization(&health.DescribeAffectedAccountsForOrganizationInput{
+			EventArn:  eventArn,
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		accountIDs = append(accountIDs, aws.StringValueSlice(output.AffectedAccounts)...)
+		if output.NextToken == nil {
+			return accountIDs, nil
+		}
+		nextToken = output.NextToken
+	}
+}
+
+// isAbuseHealthEvent returns true for the AWS Health events reporting abuse from an account
+func isAbuseHealthEvent(event *health.OrganizationEvent) bool {
+	return aws.StringValue(event.Service) == awsHealthAbuseService ||
+		strings.HasPrefix(aws.StringValue(event.EventTypeCode), awsHealthAbuseEventTypePrefix)
+}
+
+// setHealthCondition sets the Degraded condition of the account when AWS reports problems for it, and
+// clears it once they're gone. A Degraded condition set for another reason, like IAM drift, is only
+// replaced when there are problems to report. Returns true if the condition changed.
+func (w *AccountHealthWatcher) setHealthCondition(reqLogger logr.Logger, account *awsv1alpha1.Account, problems []string) bool {
+	existing := account.GetCondition(awsv1alpha1.AccountDegraded)
+
+	if len(problems) == 0 {
+		if !account.HasAWSHealthIssues() {
+			return false
+		}
+		reqLogger.Info("AWS no longer reports problems for the account")
+		account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountDegraded, corev1.ConditionFalse, awsHealthOKReason, "AWS Health reports no problems for the account", utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+		utils.RecordEvent(w.recorder, account, corev1.EventTypeNormal, utils.EventReasonAWSHealthResolved, "AWS Health reports no problems for the account")
+		return true
+	}
+
+	message := strings.Join(problems, "; ")
+	if account.HasAWSHealthIssues() && existing.Message == message {
+		return false
+	}
+	reqLogger.Info("AWS reports problems for the account", "problems", message)
+	account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountDegraded, corev1.ConditionTrue, awsv1alpha1.AccountAWSHealthIssueReason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+	utils.RecordEvent(w.recorder, account, corev1.EventTypeWarning, utils.EventReasonAWSHealthIssue, "%s", message)
+	return true
+}
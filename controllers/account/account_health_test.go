@@ -0,0 +1,131 @@
+package account
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/health"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Account health watcher", func() {
+	var (
+		nullLogger    logr.Logger
+		ctrl          *gomock.Controller
+		builder       *mock.Builder
+		mockAWSClient *mock.MockClient
+		account       *awsv1alpha1.Account
+		kubeClient    client.Client
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		ctrl = gomock.NewController(GinkgoT())
+		nullLogger = testutils.NewTestLogger().Logger()
+		builder = &mock.Builder{MockController: ctrl}
+		awsClient, _ := builder.GetClient("", nil, awsclient.NewAwsClientInput{})
+		mockAWSClient = awsClient.(*mock.MockClient)
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osd-creds-mgmt-abcdef",
+				Namespace: awsv1alpha1.AccountCrNamespace,
+			},
+			Spec:   awsv1alpha1.AccountSpec{AwsAccountID: "111111111111"},
+			Status: awsv1alpha1.AccountStatus{State: string(awsv1alpha1.AccountReady)},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	checkAccounts := func(accountStatus string, events []*health.OrganizationEvent, affectedAccounts []string) *awsv1alpha1.Account {
+		kubeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(account).Build()
+		mockAWSClient.EXPECT().ListAccounts(gomock.Any()).Return(&organizations.ListAccountsOutput{
+			Accounts: []*organizations.Account{{Id: aws.String("111111111111"), Status: aws.String(accountStatus)}},
+		}, nil)
+		mockAWSClient.EXPECT().DescribeEventsForOrganization(gomock.Any()).Return(&health.DescribeEventsForOrganizationOutput{Events: events}, nil)
+		mockAWSClient.EXPECT().DescribeAffectedAccountsForOrganization(gomock.Any()).Return(
+			&health.DescribeAffectedAccountsForOrganizationOutput{AffectedAccounts: aws.StringSlice(affectedAccounts)}, nil).AnyTimes()
+
+		Expect(NewAccountHealthWatcher(kubeClient, builder, nil).CheckAccounts(nullLogger)).To(Succeed())
+
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		return updated
+	}
+
+	healthEvent := func(service, eventTypeCode, category, scope string) *health.OrganizationEvent {
+		return &health.OrganizationEvent{
+			Arn:               aws.String("arn:aws:health:us-east-1::event/" + eventTypeCode),
+			Service:           aws.String(service),
+			EventTypeCode:     aws.String(eventTypeCode),
+			EventTypeCategory: aws.String(category),
+			EventScopeCode:    aws.String(scope),
+		}
+	}
+
+	It("Leaves healthy accounts alone", func() {
+		updated := checkAccounts(organizations.AccountStatusActive, nil, nil)
+		Expect(updated.GetCondition(awsv1alpha1.AccountDegraded)).To(BeNil())
+		Expect(updated.HasAWSHealthIssues()).To(BeFalse())
+	})
+
+	It("Flags suspended accounts", func() {
+		updated := checkAccounts(organizations.AccountStatusSuspended, nil, nil)
+		Expect(updated.HasAWSHealthIssues()).To(BeTrue())
+		Expect(updated.GetCondition(awsv1alpha1.AccountDegraded).Message).To(ContainSubstring("suspended"))
+	})
+
+	It("Flags accounts under abuse review", func() {
+		events := []*health.OrganizationEvent{
+			healthEvent("ABUSE", "AWS_ABUSE_DOS_REPORT", health.EventTypeCategoryAccountNotification, health.EventScopeCodeAccountSpecific),
+		}
+		updated := checkAccounts(organizations.AccountStatusActive, events, []string{"111111111111"})
+		Expect(updated.HasAWSHealthIssues()).To(BeTrue())
+		Expect(updated.GetCondition(awsv1alpha1.AccountDegraded).Message).To(ContainSubstring("AWS_ABUSE_DOS_REPORT"))
+	})
+
+	It("Ignores public events and account notifications other than abuse reports", func() {
+		events := []*health.OrganizationEvent{
+			healthEvent("EC2", "AWS_EC2_OPERATIONAL_ISSUE", health.EventTypeCategoryIssue, health.EventScopeCodePublic),
+			healthEvent("IAM", "AWS_IAM_CREDENTIALS_NOTIFICATION", health.EventTypeCategoryAccountNotification, health.EventScopeCodeAccountSpecific),
+		}
+		updated := checkAccounts(organizations.AccountStatusActive, events, []string{"111111111111"})
+		Expect(updated.HasAWSHealthIssues()).To(BeFalse())
+	})
+
+	It("Clears the condition once the problems are gone", func() {
+		account.Status.Conditions = []awsv1alpha1.AccountCondition{{
+			Type:   awsv1alpha1.AccountDegraded,
+			Status: corev1.ConditionTrue,
+			Reason: awsv1alpha1.AccountAWSHealthIssueReason,
+		}}
+		updated := checkAccounts(organizations.AccountStatusActive, nil, nil)
+		Expect(updated.GetCondition(awsv1alpha1.AccountDegraded).Status).To(Equal(corev1.ConditionFalse))
+	})
+
+	It("Leaves the Degraded condition of IAM drift alone", func() {
+		account.Status.Conditions = []awsv1alpha1.AccountCondition{{
+			Type:   awsv1alpha1.AccountDegraded,
+			Status: corev1.ConditionTrue,
+			Reason: iamDriftReason,
+		}}
+		updated := checkAccounts(organizations.AccountStatusActive, nil, nil)
+		Expect(updated.GetCondition(awsv1alpha1.AccountDegraded).Reason).To(Equal(iamDriftReason))
+	})
+})
@@ -0,0 +1,101 @@
+package account
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// accountAccessKeysPresent is the IAM account summary entry counting the root user's access keys
+	accountAccessKeysPresent = "AccountAccessKeysPresent"
+
+	passwordPolicyAppliedReason = "PasswordPolicyApplied"
+	rootAccessKeysReason        = "RootAccessKeysFound"
+	noRootAccessKeysReason      = "NoRootAccessKeys"
+)
+
+// passwordPolicy is the IAM password policy configured in every account the operator creates
+var passwordPolicy = &iam.UpdateAccountPasswordPolicyInput{
+	MinimumPasswordLength:      aws.Int64(14),
+	RequireUppercaseCharacters: aws.Bool(true),
+	RequireLowercaseCharacters: aws.Bool(true),
+	RequireNumbers:             aws.Bool(true),
+	RequireSymbols:             aws.Bool(true),
+	AllowUsersToChangePassword: aws.Bool(true),
+	MaxPasswordAge:             aws.Int64(90),
+	PasswordReusePrevention:    aws.Int64(24),
+}
+
+// ConfigurePasswordPolicy applies the operator's IAM password policy to the account and records it in
+// the PasswordPolicyConfigured condition
+func ConfigurePasswordPolicy(reqLogger logr.Logger, awsClient awsclient.Client, account *awsv1alpha1.Account) error {
+	reqLogger.Info("Configuring IAM account password policy")
+	_, err := awsClient.UpdateAccountPasswordPolicy(passwordPolicy)
+	if err != nil {
+		reqLogger.Error(err, "Failed to configure IAM account password policy")
+		return err
+	}
+
+	account.Status.Conditions = utils.SetAccountCondition(
+		account.Status.Conditions,
+		awsv1alpha1.AccountPasswordPolicyConfigured,
+		corev1.ConditionTrue,
+		passwordPolicyAppliedReason,
+		"IAM account password policy configured",
+		utils.UpdateConditionIfReasonOrMessageChange,
+		account.Spec.BYOC,
+	)
+	return nil
+}
+
+// CheckRootAccessKeys records whether the root user of the account has access keys in the
+// RootAccessKeysPresent condition. Returns true if the condition changed.
+func CheckRootAccessKeys(reqLogger logr.Logger, awsClient awsclient.Client, account *awsv1alpha1.Account) (bool, error) {
+	summary, err := awsClient.GetAccountSummary(&iam.GetAccountSummaryInput{})
+	if err != nil {
+		reqLogger.Error(err, "Failed to get IAM account summary")
+		return false, err
+	}
+
+	rootAccessKeys := aws.Int64Value(summary.SummaryMap[accountAccessKeysPresent])
+	present := rootAccessKeys > 0
+
+	existing := account.GetCondition(awsv1alpha1.AccountRootAccessKeysPresent)
+	if present == (existing != nil && existing.Status == corev1.ConditionTrue) {
+		return false, nil
+	}
+
+	status, reason, message := corev1.ConditionFalse, noRootAccessKeysReason, "The root user has no access keys"
+	if present {
+		reqLogger.Info("Root user has access keys", "count", rootAccessKeys)
+		status, reason, message = corev1.ConditionTrue, rootAccessKeysReason, fmt.Sprintf("The root user has %d access keys", rootAccessKeys)
+	}
+	account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountRootAccessKeysPresent, status, reason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+	return true, nil
+}
+
+// applySecurityBaseline configures the password policy of a new account and checks its root user has
+// no access keys. Root access keys don't fail the account, they're only reported.
+func (r *AccountReconciler) applySecurityBaseline(reqLogger logr.Logger, awsClient awsclient.Client, account *awsv1alpha1.Account) error {
+	err := ConfigurePasswordPolicy(reqLogger, awsClient, account)
+	if err != nil {
+		return err
+	}
+
+	_, err = CheckRootAccessKeys(reqLogger, awsClient, account)
+	if err != nil {
+		return err
+	}
+	if cond := account.GetCondition(awsv1alpha1.AccountRootAccessKeysPresent); cond != nil && cond.Status == corev1.ConditionTrue {
+		utils.RecordEvent(r.recorder, account, corev1.EventTypeWarning, utils.EventReasonRootAccessKeys, "%s", cond.Message)
+	}
+
+	return r.statusUpdate(account)
+}
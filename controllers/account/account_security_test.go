@@ -0,0 +1,79 @@
+package account
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Account security baseline", func() {
+	var (
+		nullLogger    logr.Logger
+		mockAWSClient *mock.MockClient
+		ctrl          *gomock.Controller
+		account       *awsv1alpha1.Account
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		nullLogger = testutils.NewTestLogger().Logger()
+		mockAWSClient = mock.NewMockClient(ctrl)
+		account = &newTestAccountBuilder().acct
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectRootAccessKeys := func(count int64) {
+		mockAWSClient.EXPECT().GetAccountSummary(gomock.Any()).Return(&iam.GetAccountSummaryOutput{
+			SummaryMap: map[string]*int64{"AccountAccessKeysPresent": aws.Int64(count)},
+		}, nil)
+	}
+
+	It("Configures the password policy", func() {
+		mockAWSClient.EXPECT().UpdateAccountPasswordPolicy(gomock.Any()).DoAndReturn(func(input *iam.UpdateAccountPasswordPolicyInput) (*iam.UpdateAccountPasswordPolicyOutput, error) {
+			Expect(aws.Int64Value(input.MinimumPasswordLength)).To(BeNumerically(">=", 14))
+			return &iam.UpdateAccountPasswordPolicyOutput{}, nil
+		})
+		Expect(ConfigurePasswordPolicy(nullLogger, mockAWSClient, account)).To(Succeed())
+
+		condition := account.GetCondition(awsv1alpha1.AccountPasswordPolicyConfigured)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+	})
+
+	It("Doesn't add a condition when the root user has no access keys", func() {
+		expectRootAccessKeys(0)
+		changed, err := CheckRootAccessKeys(nullLogger, mockAWSClient, account)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(account.GetCondition(awsv1alpha1.AccountRootAccessKeysPresent)).To(BeNil())
+	})
+
+	It("Flags root access keys and clears the condition once they are removed", func() {
+		expectRootAccessKeys(1)
+		changed, err := CheckRootAccessKeys(nullLogger, mockAWSClient, account)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(account.GetCondition(awsv1alpha1.AccountRootAccessKeysPresent).Status).To(Equal(corev1.ConditionTrue))
+
+		expectRootAccessKeys(1)
+		changed, err = CheckRootAccessKeys(nullLogger, mockAWSClient, account)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		expectRootAccessKeys(0)
+		changed, err = CheckRootAccessKeys(nullLogger, mockAWSClient, account)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(account.GetCondition(awsv1alpha1.AccountRootAccessKeysPresent).Status).To(Equal(corev1.ConditionFalse))
+	})
+})
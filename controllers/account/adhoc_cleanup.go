@@ -0,0 +1,132 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// adHocCleanupInfix is in the names of the ad-hoc AccountCleanups, between the name of the account and a timestamp
+const adHocCleanupInfix = "-adhoc-"
+
+// handleAdHocCleanupRequest runs the reuse cleanup against an unclaimed Account annotated with CleanupAnnotation.
+// The account is first reserved for the cleanup, as if it were claimed by it, so it isn't handed out while it's
+// cleaned up; the AccountCleanup is then created and releases the account back to the pool when it completes. The
+// request is refused, with an event saying why, when the account can't be cleaned up. It returns true if there was
+// a request.
+func (r *AccountReconciler) handleAdHocCleanupRequest(reqLogger logr.Logger, account *awsv1alpha1.Account) (bool, error) {
+	region, requested := account.GetAnnotations()[awsv1alpha1.CleanupAnnotation]
+	if !requested {
+		return false, nil
+	}
+	if region == "" {
+		region = config.GetDefaultRegion()
+	}
+
+	// The account was reserved by a previous reconcile
+	if isReservedForAdHocCleanup(account) {
+		return true, r.createAdHocCleanup(reqLogger, account, region)
+	}
+
+	refusal, err := r.adHocCleanupRefusal(account)
+	if err != nil {
+		return true, err
+	}
+	if refusal != "" {
+		reqLogger.Info("Refusing to clean up the account", "reason", refusal)
+		utils.RecordEvent(r.recorder, account, corev1.EventTypeWarning, utils.EventReasonAdHocCleanupRefused, "Account not cleaned up: %s", refusal)
+		return true, utils.UpdateWithRetry(r.Client, account, func() error {
+			delete(account.Annotations, awsv1alpha1.CleanupAnnotation)
+			return nil
+		})
+	}
+
+	// The reservation triggers another reconcile, which creates the AccountCleanup
+	cleanupName := account.Name + adHocCleanupInfix + strconv.FormatInt(time.Now().Unix(), 10)
+	err = utils.UpdateWithRetry(r.Client, account, func() error {
+		// The account may have been claimed since it was read
+		if account.Spec.ClaimLink != "" || account.Status.Claimed {
+			return awsv1alpha1.ErrAccountClaimed
+		}
+		account.Spec.ClaimLink = cleanupName
+		account.Spec.ClaimLinkNamespace = awsv1alpha1.AccountCrNamespace
+		account.Spec.ClaimLinkUID = ""
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to reserve the account for its cleanup")
+		return true, err
+	}
+	reqLogger.Info("Reserved the account for an ad-hoc cleanup", "accountCleanup", cleanupName)
+	return true, nil
+}
+
+// isReservedForAdHocCleanup returns true if the claim link of the account is an ad-hoc AccountCleanup
+func isReservedForAdHocCleanup(account *awsv1alpha1.Account) bool {
+	return account.Spec.ClaimLinkNamespace == awsv1alpha1.AccountCrNamespace &&
+		strings.HasPrefix(account.Spec.ClaimLink, account.Name+adHocCleanupInfix)
+}
+
+// adHocCleanupRefusal returns why the account can't be cleaned up, empty if it can
+func (r *AccountReconciler) adHocCleanupRefusal(account *awsv1alpha1.Account) (string, error) {
+	switch {
+	case account.IsBYOC() || account.IsSTS():
+		return "CCS and STS accounts aren't cleaned up for reuse", nil
+	case !account.IsReady() && !account.IsFailed():
+		return fmt.Sprintf("the account is %s, only Ready and Failed accounts can be cleaned up", account.Status.State), nil
+	case account.HasClaimLink():
+		return fmt.Sprintf("the account is claimed by %s/%s", account.Spec.ClaimLinkNamespace, account.Spec.ClaimLink), nil
+	}
+	return r.cleanupRefusal(account)
+}
+
+// createAdHocCleanup creates the AccountCleanup the account is reserved for, with the reservation as its claim, and
+// removes the request
+func (r *AccountReconciler) createAdHocCleanup(reqLogger logr.Logger, account *awsv1alpha1.Account, region string) error {
+	cleanup := &awsv1alpha1.AccountCleanup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      account.Spec.ClaimLink,
+			Namespace: awsv1alpha1.AccountCrNamespace,
+		},
+		Spec: awsv1alpha1.AccountCleanupSpec{
+			AccountLink:    account.Name,
+			AwsAccountID:   account.Spec.AwsAccountID,
+			ClaimName:      account.Spec.ClaimLink,
+			ClaimNamespace: account.Spec.ClaimLinkNamespace,
+			Region:         region,
+			LegalEntity:    account.Spec.LegalEntity,
+		},
+	}
+	err := controllerutil.SetOwnerReference(account, cleanup, r.Scheme)
+	if err != nil {
+		return err
+	}
+	err = r.Client.Create(context.TODO(), cleanup)
+	if err != nil && !k8serr.IsAlreadyExists(err) {
+		reqLogger.Error(err, "Failed to create the ad-hoc account cleanup")
+		return err
+	}
+
+	err = utils.UpdateWithRetry(r.Client, account, func() error {
+		delete(account.Annotations, awsv1alpha1.CleanupAnnotation)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	reqLogger.Info("Queued an ad-hoc account cleanup", "accountCleanup", cleanup.Name, "region", region)
+	utils.RecordEvent(r.recorder, account, corev1.EventTypeNormal, utils.EventReasonAdHocCleanupQueued, "Queued the cleanup of the account in %s as AccountCleanup %s", region, cleanup.Name)
+	return nil
+}
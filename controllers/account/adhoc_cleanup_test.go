@@ -0,0 +1,98 @@
+package account
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Ad-hoc cleanup requests", func() {
+	var account *awsv1alpha1.Account
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "osd-creds-mgmt-aaabbb",
+				Namespace:   awsv1alpha1.AccountCrNamespace,
+				Annotations: map[string]string{awsv1alpha1.CleanupAnnotation: "us-west-2"},
+			},
+			Spec:   awsv1alpha1.AccountSpec{AwsAccountID: "123456789012"},
+			Status: awsv1alpha1.AccountStatus{State: AccountReady},
+		}
+	})
+
+	It("Reserves the account, then queues its cleanup", func() {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account).Build()
+		r := &AccountReconciler{Client: kubeClient, Scheme: scheme.Scheme}
+		logger := testutils.NewTestLogger().Logger()
+
+		handled, err := r.handleAdHocCleanupRequest(logger, account)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(handled).To(BeTrue())
+		Expect(isReservedForAdHocCleanup(account)).To(BeTrue())
+
+		handled, err = r.handleAdHocCleanupRequest(logger, account)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(handled).To(BeTrue())
+
+		cleanup := &awsv1alpha1.AccountCleanup{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKey{Name: account.Spec.ClaimLink, Namespace: awsv1alpha1.AccountCrNamespace}, cleanup)).To(Succeed())
+		Expect(cleanup.Spec.AccountLink).To(Equal(account.Name))
+		Expect(cleanup.Spec.ClaimName).To(Equal(account.Spec.ClaimLink))
+		Expect(cleanup.Spec.ClaimNamespace).To(Equal(awsv1alpha1.AccountCrNamespace))
+		Expect(cleanup.Spec.ClaimUID).To(BeEmpty())
+		Expect(cleanup.Spec.Region).To(Equal("us-west-2"))
+		Expect(cleanup.OwnerReferences).To(HaveLen(1))
+
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		Expect(updated.Annotations).NotTo(HaveKey(awsv1alpha1.CleanupAnnotation))
+	})
+
+	It("Refuses claimed accounts", func() {
+		account.Spec.ClaimLink = "mycluster"
+		account.Spec.ClaimLinkNamespace = "uhc-production-1234"
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account).Build()
+		r := &AccountReconciler{Client: kubeClient, Scheme: scheme.Scheme}
+
+		handled, err := r.handleAdHocCleanupRequest(testutils.NewTestLogger().Logger(), account)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(handled).To(BeTrue())
+
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		Expect(updated.Annotations).NotTo(HaveKey(awsv1alpha1.CleanupAnnotation))
+		Expect(updated.Spec.ClaimLink).To(Equal("mycluster"))
+
+		cleanups := &awsv1alpha1.AccountCleanupList{}
+		Expect(kubeClient.List(context.TODO(), cleanups)).To(Succeed())
+		Expect(cleanups.Items).To(BeEmpty())
+	})
+
+	It("Doesn't reserve an account claimed since it was read", func() {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account).Build()
+		r := &AccountReconciler{Client: kubeClient, Scheme: scheme.Scheme}
+		stale := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), stale)).To(Succeed())
+		account.Spec.ClaimLink = "mycluster"
+		account.Spec.ClaimLinkNamespace = "uhc-production-1234"
+		Expect(kubeClient.Update(context.TODO(), account)).To(Succeed())
+
+		handled, err := r.handleAdHocCleanupRequest(testutils.NewTestLogger().Logger(), stale)
+		Expect(err).To(MatchError(awsv1alpha1.ErrAccountClaimed))
+		Expect(handled).To(BeTrue())
+
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		Expect(updated.Spec.ClaimLink).To(Equal("mycluster"))
+	})
+})
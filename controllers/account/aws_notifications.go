@@ -0,0 +1,342 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// awsNotificationsDisabledInterval is how often the configmap is checked for a queue while none is set
+	awsNotificationsDisabledInterval = 5 * time.Minute
+	// awsNotificationsErrorInterval is how long to wait before polling the queue again after an error
+	awsNotificationsErrorInterval = 30 * time.Second
+	// awsNotificationsWaitSeconds is how long a poll waits for messages to arrive
+	awsNotificationsWaitSeconds = 20
+	awsNotificationsMaxMessages = 10
+)
+
+// awsNotificationKind is the kind of problem an AWS notification reports for an account
+type awsNotificationKind string
+
+const (
+	awsNotificationAccountClosed  awsNotificationKind = "AccountClosed"
+	awsNotificationAccountRemoved awsNotificationKind = "AccountRemoved"
+	awsNotificationAbuse          awsNotificationKind = "Abuse"
+	awsNotificationBudgetAlarm    awsNotificationKind = "BudgetAlarm"
+)
+
+// awsNotification is a notification about an AWS account the operator acts on
+type awsNotification struct {
+	kind         awsNotificationKind
+	awsAccountID string
+	description  string
+}
+
+// AWSNotificationListener consumes the AWS notifications routed to an SQS queue of the payer account, e.g.
+// through EventBridge rules or SNS subscriptions, and flags the Account CRs they are about. The periodic
+// watchers eventually find the same problems, the notifications make them visible right away.
+type AWSNotificationListener struct {
+	client           client.Client
+	awsClientBuilder awsclient.IBuilder
+	recorder         record.EventRecorder
+}
+
+// NewAWSNotificationListener returns a new AWSNotificationListener
+func NewAWSNotificationListener(client client.Client, awsClientBuilder awsclient.IBuilder, recorder record.EventRecorder) *AWSNotificationListener {
+	return &AWSNotificationListener{
+		client:           client,
+		awsClientBuilder: awsClientBuilder,
+		recorder:         recorder,
+	}
+}
+
+// Start polls the queue configured in the configmap for notifications, until the operator is stopped
+func (l *AWSNotificationListener) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the AWS notification listener")
+	for {
+		wait := time.Duration(0)
+		queueURL := getAWSNotificationsQueueURL(log, l.client)
+		if queueURL == "" {
+			// Check again later whether a queue has been configured
+			wait = awsNotificationsDisabledInterval
+		} else if err := l.Poll(log, queueURL); err != nil {
+			log.Error(err, "Failed to poll the AWS notifications queue", "queueURL", queueURL)
+			wait = awsNotificationsErrorInterval
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-stopCh.Done():
+			log.Info("Stopping the AWS notification listener")
+			return
+		}
+	}
+}
+
+// getAWSNotificationsQueueURL reads the URL of the notifications queue from the configmap
+func getAWSNotificationsQueueURL(log logr.Logger, kubeClient client.Client) string {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		log.Error(err, "Unable to get the AWS notifications queue from the configmap")
+		return ""
+	}
+	return configMap.Data[awsv1alpha1.AWSNotificationsQueueURLConfigMapKey]
+}
+
+// queueRegion returns the region of an SQS queue from its URL, e.g. https://sqs.us-east-1.amazonaws.com/..., or
+// the default region if the URL doesn't tell
+func queueRegion(queueURL string) string {
+	parsed, err := url.Parse(queueURL)
+	if err == nil {
+		parts := strings.Split(parsed.Hostname(), ".")
+		if len(parts) >= 3 && parts[0] == "sqs" {
+			return parts[1]
+		}
+	}
+	return config.GetDefaultRegion()
+}
+
+// Poll receives the waiting notifications and acts on them. Messages are deleted once handled, or if they
+// aren't notifications the operator acts on; messages that failed to be handled are received again later.
+func (l *AWSNotificationListener) Poll(log logr.Logger, queueURL string) error {
+	awsClient, err := l.awsClientBuilder.GetClient("", l.client, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  queueRegion(queueURL),
+	})
+	if err != nil {
+		return err
+	}
+
+	output, err := awsClient.ReceiveMessage(&sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(queueURL),
+		MaxNumberOfMessages: aws.Int64(awsNotificationsMaxMessages),
+		WaitTimeSeconds:     aws.Int64(awsNotificationsWaitSeconds),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, message := range output.Messages {
+		reqLogger := log.WithValues("messageID", aws.StringValue(message.MessageId))
+		notification, ok := parseAWSNotification(aws.StringValue(message.Body))
+		if !ok {
+			reqLogger.Info("Ignoring AWS notification the operator doesn't act on")
+		} else if err := l.applyNotification(reqLogger, notification); err != nil {
+			reqLogger.Error(err, "Failed to handle AWS notification, it will be received again", "kind", notification.kind, "awsAccountID", notification.awsAccountID)
+			continue
+		}
+
+		_, err = awsClient.DeleteMessage(&sqs.DeleteMessageInput{
+			QueueUrl:      aws.String(queueURL),
+			ReceiptHandle: message.ReceiptHandle,
+		})
+		if err != nil {
+			reqLogger.Error(err, "Failed to delete AWS notification")
+		}
+	}
+	return nil
+}
+
+// snsEnvelope is a notification delivered to SQS by an SNS subscription without raw message delivery
+type snsEnvelope struct {
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+}
+
+// eventBridgeEvent is an event delivered by an EventBridge rule
+type eventBridgeEvent struct {
+	Source     string          `json:"source"`
+	DetailType string          `json:"detail-type"`
+	Account    string          `json:"account"`
+	Detail     json.RawMessage `json:"detail"`
+}
+
+// cloudTrailDetail is the detail of the EventBridge events of the API calls recorded by CloudTrail
+type cloudTrailDetail struct {
+	EventName         string `json:"eventName"`
+	ErrorCode         string `json:"errorCode"`
+	RequestParameters struct {
+		AccountID string `json:"accountId"`
+	} `json:"requestParameters"`
+}
+
+// healthDetail is the detail of the EventBridge events of AWS Health
+type healthDetail struct {
+	Service         string `json:"service"`
+	EventTypeCode   string `json:"eventTypeCode"`
+	AffectedAccount string `json:"affectedAccount"`
+}
+
+// cloudWatchAlarm is the notification of a CloudWatch alarm changing state, e.g. a billing alarm
+type cloudWatchAlarm struct {
+	AlarmName     string `json:"AlarmName"`
+	NewStateValue string `json:"NewStateValue"`
+	AWSAccountID  string `json:"AWSAccountId"`
+}
+
+var (
+	budgetAccountRegexp = regexp.MustCompile(`AWS Account (\d{12})`)
+	budgetNameRegexp    = regexp.MustCompile(`Budget Name: ([^\r\n]+)`)
+)
+
+// parseAWSNotification returns the notification held by the body of an SQS message, and false if it isn't a
+// notification the operator acts on. Notifications may be wrapped in an SNS envelope.
+func parseAWSNotification(body string) (awsNotification, bool) {
+	envelope := snsEnvelope{}
+	if err := json.Unmarshal([]byte(body), &envelope); err == nil && envelope.Type == "Notification" {
+		body = envelope.Message
+	}
+
+	// AWS Budgets notifications are plain text
+	if matches := budgetAccountRegexp.FindStringSubmatch(body); matches != nil && strings.Contains(body, "AWS Budget") {
+		budget := "budget"
+		accountID := matches[1]
+		if name := budgetNameRegexp.FindStringSubmatch(body); name != nil {
+			budget = strings.TrimSpace(name[1])
+			// The budgets of the claimed accounts are in the payer account, which sends their notifications
+			if strings.HasPrefix(budget, awsv1alpha1.BudgetNamePrefix) {
+				accountID = strings.TrimPrefix(budget, awsv1alpha1.BudgetNamePrefix)
+			}
+		}
+		return awsNotification{kind: awsNotificationBudgetAlarm, awsAccountID: accountID, description: budget}, true
+	}
+
+	alarm := cloudWatchAlarm{}
+	if err := json.Unmarshal([]byte(body), &alarm); err == nil && alarm.AlarmName != "" {
+		if alarm.NewStateValue != "ALARM" || alarm.AWSAccountID == "" {
+			return awsNotification{}, false
+		}
+		return awsNotification{kind: awsNotificationBudgetAlarm, awsAccountID: alarm.AWSAccountID, description: alarm.AlarmName}, true
+	}
+
+	event := eventBridgeEvent{}
+	if err := json.Unmarshal([]byte(body), &event); err != nil {
+		return awsNotification{}, false
+	}
+	switch event.Source {
+	case "aws.organizations":
+		detail := cloudTrailDetail{}
+		if err := json.Unmarshal(event.Detail, &detail); err != nil || detail.ErrorCode != "" || detail.RequestParameters.AccountID == "" {
+			return awsNotification{}, false
+		}
+		switch detail.EventName {
+		case "CloseAccount":
+			return awsNotification{kind: awsNotificationAccountClosed, awsAccountID: detail.RequestParameters.AccountID, description: "account is closed"}, true
+		case "RemoveAccountFromOrganization":
+			return awsNotification{kind: awsNotificationAccountRemoved, awsAccountID: detail.RequestParameters.AccountID, description: "account was removed from the organization"}, true
+		}
+	case "aws.health":
+		detail := healthDetail{}
+		if err := json.Unmarshal(event.Detail, &detail); err != nil {
+			return awsNotification{}, false
+		}
+		if detail.Service != awsHealthAbuseService && !strings.HasPrefix(detail.EventTypeCode, awsHealthAbuseEventTypePrefix) {
+			return awsNotification{}, false
+		}
+		accountID := detail.AffectedAccount
+		if accountID == "" {
+			accountID = event.Account
+		}
+		// Same description as the account health watcher, so it keeps the condition as is
+		return awsNotification{kind: awsNotificationAbuse, awsAccountID: accountID, description: fmt.Sprintf("under abuse review (%s)", detail.EventTypeCode)}, true
+	}
+	return awsNotification{}, false
+}
+
+// applyNotification flags the Account of the AWS account the notification is about. Closed accounts and abuse
+// notices set the Degraded condition like the account health watcher, accounts removed from the organization
+// the NotInOrganization condition like the orphaned account watcher, and budget alarms set the CostAnomaly
+// condition and are recorded in an annotation. Notifications about AWS accounts without a non-CCS Account CR are
+// ignored.
+func (l *AWSNotificationListener) applyNotification(reqLogger logr.Logger, notification awsNotification) error {
+	accountList := &awsv1alpha1.AccountList{}
+	err := l.client.List(context.TODO(), accountList, client.InNamespace(awsv1alpha1.AccountCrNamespace))
+	if err != nil {
+		return err
+	}
+	var account *awsv1alpha1.Account
+	for i := range accountList.Items {
+		candidate := &accountList.Items[i]
+		if candidate.Spec.AwsAccountID == notification.awsAccountID && !candidate.IsBYOC() && !candidate.IsPendingDeletion() {
+			account = candidate
+			break
+		}
+	}
+	if account == nil {
+		reqLogger.Info("Ignoring AWS notification about an AWS account without an Account CR", "kind", notification.kind, "awsAccountID", notification.awsAccountID)
+		return nil
+	}
+	reqLogger = reqLogger.WithValues("account", account.Name, "awsAccountID", account.Spec.AwsAccountID)
+	reqLogger.Info("Received AWS notification", "kind", notification.kind, "description", notification.description)
+
+	switch notification.kind {
+	case awsNotificationAccountClosed, awsNotificationAbuse:
+		message := notification.description
+		if account.HasAWSHealthIssues() {
+			existing := account.GetCondition(awsv1alpha1.AccountDegraded).Message
+			if strings.Contains(existing, message) {
+				return nil
+			}
+			message = existing + "; " + message
+		}
+		account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountDegraded, corev1.ConditionTrue, awsv1alpha1.AccountAWSHealthIssueReason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+		err = utils.UpdateAccountConditionsWithRetry(l.client, account, awsv1alpha1.AccountDegraded)
+		if err != nil {
+			return err
+		}
+		utils.RecordEvent(l.recorder, account, corev1.EventTypeWarning, utils.EventReasonAWSHealthIssue, "%s", message)
+	case awsNotificationAccountRemoved:
+		existing := account.GetCondition(awsv1alpha1.AccountNotInOrganization)
+		if existing != nil && existing.Status == corev1.ConditionTrue {
+			return nil
+		}
+		message := "The AWS account was removed from the organization"
+		account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountNotInOrganization, corev1.ConditionTrue, notInOrganizationReason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+		err = utils.UpdateAccountConditionsWithRetry(l.client, account, awsv1alpha1.AccountNotInOrganization)
+		if err != nil {
+			return err
+		}
+		utils.RecordEvent(l.recorder, account, corev1.EventTypeWarning, utils.EventReasonNotInOrganization, "%s", message)
+	case awsNotificationBudgetAlarm:
+		value := fmt.Sprintf("%s at %s", notification.description, time.Now().UTC().Format(time.RFC3339))
+		err = utils.UpdateWithRetry(l.client, account, func() error {
+			annotations := account.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[awsv1alpha1.BudgetAlarmAnnotation] = value
+			account.SetAnnotations(annotations)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		message := fmt.Sprintf("Budget alarm %s", notification.description)
+		err = utils.UpdateStatusWithRetry(l.client, account, func() error {
+			account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountCostAnomaly, corev1.ConditionTrue, awsv1alpha1.AccountBudgetExceededReason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		utils.RecordEvent(l.recorder, account, corev1.EventTypeWarning, utils.EventReasonBudgetAlarm, "%s", message)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testQueueURL = "https://sqs.us-west-2.amazonaws.com/123456789012/aao-notifications"
+
+var _ = Describe("AWS notification listener", func() {
+	var (
+		nullLogger    logr.Logger
+		ctrl          *gomock.Controller
+		builder       *mock.Builder
+		mockAWSClient *mock.MockClient
+		account       *awsv1alpha1.Account
+		kubeClient    client.Client
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		ctrl = gomock.NewController(GinkgoT())
+		nullLogger = testutils.NewTestLogger().Logger()
+		builder = &mock.Builder{MockController: ctrl}
+		awsClient, _ := builder.GetClient("", nil, awsclient.NewAwsClientInput{})
+		mockAWSClient = awsClient.(*mock.MockClient)
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osd-creds-mgmt-abcdef",
+				Namespace: awsv1alpha1.AccountCrNamespace,
+			},
+			Spec:   awsv1alpha1.AccountSpec{AwsAccountID: "111111111111"},
+			Status: awsv1alpha1.AccountStatus{State: string(awsv1alpha1.AccountReady)},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	eventBridge := func(source string, detail interface{}) string {
+		detailJSON, err := json.Marshal(detail)
+		Expect(err).NotTo(HaveOccurred())
+		event, err := json.Marshal(map[string]interface{}{
+			"source":      source,
+			"detail-type": "test",
+			"account":     "999999999999",
+			"detail":      json.RawMessage(detailJSON),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return string(event)
+	}
+
+	// poll delivers the messages to the listener and returns the account and the receipt handles deleted
+	poll := func(bodies ...string) (*awsv1alpha1.Account, []string) {
+		kubeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(account).Build()
+		messages := []*sqs.Message{}
+		for i, body := range bodies {
+			messages = append(messages, &sqs.Message{
+				MessageId:     aws.String(string(rune('a' + i))),
+				ReceiptHandle: aws.String(string(rune('a' + i))),
+				Body:          aws.String(body),
+			})
+		}
+		mockAWSClient.EXPECT().ReceiveMessage(gomock.Any()).Return(&sqs.ReceiveMessageOutput{Messages: messages}, nil)
+		deleted := []string{}
+		mockAWSClient.EXPECT().DeleteMessage(gomock.Any()).DoAndReturn(func(input *sqs.DeleteMessageInput) (*sqs.DeleteMessageOutput, error) {
+			Expect(aws.StringValue(input.QueueUrl)).To(Equal(testQueueURL))
+			deleted = append(deleted, aws.StringValue(input.ReceiptHandle))
+			return &sqs.DeleteMessageOutput{}, nil
+		}).AnyTimes()
+
+		Expect(NewAWSNotificationListener(kubeClient, builder, nil).Poll(nullLogger, testQueueURL)).To(Succeed())
+
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		return updated, deleted
+	}
+
+	It("Flags closed accounts as degraded", func() {
+		updated, deleted := poll(eventBridge("aws.organizations", map[string]interface{}{
+			"eventName":         "CloseAccount",
+			"requestParameters": map[string]string{"accountId": "111111111111"},
+		}))
+		Expect(deleted).To(ConsistOf("a"))
+		Expect(updated.HasAWSHealthIssues()).To(BeTrue())
+		Expect(updated.GetCondition(awsv1alpha1.AccountDegraded).Message).To(Equal("account is closed"))
+	})
+
+	It("Flags accounts removed from the organization", func() {
+		updated, _ := poll(eventBridge("aws.organizations", map[string]interface{}{
+			"eventName":         "RemoveAccountFromOrganization",
+			"requestParameters": map[string]string{"accountId": "111111111111"},
+		}))
+		condition := updated.GetCondition(awsv1alpha1.AccountNotInOrganization)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+	})
+
+	It("Flags accounts under abuse review, unwrapping SNS notifications", func() {
+		abuse := eventBridge("aws.health", map[string]string{
+			"service":         "ABUSE",
+			"eventTypeCode":   "AWS_ABUSE_DOS_REPORT",
+			"affectedAccount": "111111111111",
+		})
+		envelope, err := json.Marshal(snsEnvelope{Type: "Notification", Message: abuse})
+		Expect(err).NotTo(HaveOccurred())
+
+		updated, _ := poll(string(envelope))
+		Expect(updated.HasAWSHealthIssues()).To(BeTrue())
+		Expect(updated.GetCondition(awsv1alpha1.AccountDegraded).Message).To(Equal("under abuse review (AWS_ABUSE_DOS_REPORT)"))
+	})
+
+	It("Records budget alarms in an annotation", func() {
+		budget := "AWS Budget Notification October 16, 2026\nAWS Account 111111111111\n\nBudget Name: osd-budget\nBudget Type: Cost\n"
+		updated, deleted := poll(budget)
+		Expect(deleted).To(ConsistOf("a"))
+		Expect(updated.Annotations[awsv1alpha1.BudgetAlarmAnnotation]).To(HavePrefix("osd-budget at "))
+		condition := updated.GetCondition(awsv1alpha1.AccountCostAnomaly)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(awsv1alpha1.AccountBudgetExceededReason))
+	})
+
+	It("Finds the account of the budgets of the payer account from their name", func() {
+		budget := "AWS Budget Notification October 16, 2026\nAWS Account 999999999999\n\nBudget Name: " + awsv1alpha1.BudgetNamePrefix + "111111111111\nBudget Type: Cost\n"
+		updated, _ := poll(budget)
+		Expect(updated.GetCondition(awsv1alpha1.AccountCostAnomaly)).NotTo(BeNil())
+	})
+
+	It("Deletes notifications it doesn't act on", func() {
+		okAlarm, err := json.Marshal(cloudWatchAlarm{AlarmName: "billing", NewStateValue: "OK", AWSAccountID: "111111111111"})
+		Expect(err).NotTo(HaveOccurred())
+		otherAccount := eventBridge("aws.organizations", map[string]interface{}{
+			"eventName":         "CloseAccount",
+			"requestParameters": map[string]string{"accountId": "222222222222"},
+		})
+
+		updated, deleted := poll("not json", string(okAlarm), otherAccount)
+		Expect(deleted).To(ConsistOf("a", "b", "c"))
+		Expect(updated.GetCondition(awsv1alpha1.AccountDegraded)).To(BeNil())
+		Expect(updated.Annotations).NotTo(HaveKey(awsv1alpha1.BudgetAlarmAnnotation))
+	})
+
+	It("Reads the region of the queue from its URL", func() {
+		Expect(queueRegion(testQueueURL)).To(Equal("us-west-2"))
+	})
+})
@@ -13,6 +13,7 @@ import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
@@ -49,14 +50,16 @@ func (r *AccountReconciler) initializeNewCCSAccount(reqLogger logr.Logger, accou
 		// TODO: Unrecoverable
 		// TODO: set helpful error message
 		if accountClaim != nil {
-			utils.SetAccountClaimStatus(
-				accountClaim,
-				"Failed to get AccountClaim for CSS account",
-				"FailedRetrievingAccountClaim",
-				awsv1alpha1.ClientError,
-				awsv1alpha1.ClaimStatusError,
-			)
-			err := r.Client.Status().Update(context.TODO(), accountClaim)
+			err := utils.UpdateStatusWithRetry(r.Client, accountClaim, func() error {
+				utils.SetAccountClaimStatus(
+					accountClaim,
+					"Failed to get AccountClaim for CSS account",
+					"FailedRetrievingAccountClaim",
+					awsv1alpha1.ClientError,
+					awsv1alpha1.ClaimStatusError,
+				)
+				return nil
+			})
 			if err != nil {
 				reqLogger.Error(err, "failed to update accountclaim status")
 			}
@@ -248,9 +251,15 @@ func DeleteRole(reqLogger logr.Logger, byocRole string, byocAWSClient awsclient.
 }
 
 func (r *AccountReconciler) getSTSClient(log logr.Logger, accountClaim *awsv1alpha1.AccountClaim, operatorAWSClient awsclient.Client) (awsclient.Client, *sts.AssumeRoleOutput, error) {
+	return GetSTSClient(log, r.Client, r.awsClientBuilder, accountClaim, operatorAWSClient)
+}
+
+// GetSTSClient returns a client of the customer account of a manualSTSMode AccountClaim, assuming the
+// claim's STS role through the sts-jump-role
+func GetSTSClient(log logr.Logger, kubeClient client.Client, awsClientBuilder awsclient.IBuilder, accountClaim *awsv1alpha1.AccountClaim, operatorAWSClient awsclient.Client) (awsclient.Client, *sts.AssumeRoleOutput, error) {
 	// Get SRE Access ARN from configmap
 	cm := &corev1.ConfigMap{}
-	cmErr := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: awsv1alpha1.AccountCrNamespace, Name: awsv1alpha1.DefaultConfigMap}, cm)
+	cmErr := kubeClient.Get(context.TODO(), types.NamespacedName{Namespace: awsv1alpha1.AccountCrNamespace, Name: awsv1alpha1.DefaultConfigMap}, cm)
 	if cmErr != nil {
 		log.Error(cmErr, "There was an error getting the ConfigMap to get the STS Jump Role")
 		return nil, nil, cmErr
@@ -269,7 +278,7 @@ func (r *AccountReconciler) getSTSClient(log logr.Logger, accountClaim *awsv1alp
 		return nil, nil, err
 	}
 
-	jumpRoleClient, err := r.awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
+	jumpRoleClient, err := awsClientBuilder.GetClient(controllerName, kubeClient, awsclient.NewAwsClientInput{
 		AwsCredsSecretIDKey:     *jumpRoleCreds.Credentials.AccessKeyId,
 		AwsCredsSecretAccessKey: *jumpRoleCreds.Credentials.SecretAccessKey,
 		AwsToken:                *jumpRoleCreds.Credentials.SessionToken,
@@ -285,11 +294,12 @@ func (r *AccountReconciler) getSTSClient(log logr.Logger, accountClaim *awsv1alp
 		return nil, nil, err
 	}
 
-	customerClient, err := r.awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
+	customerClient, err := awsClientBuilder.GetClient(controllerName, kubeClient, awsclient.NewAwsClientInput{
 		AwsCredsSecretIDKey:     *customerAccountCreds.Credentials.AccessKeyId,
 		AwsCredsSecretAccessKey: *customerAccountCreds.Credentials.SecretAccessKey,
 		AwsToken:                *customerAccountCreds.Credentials.SessionToken,
 		AwsRegion:               awsRegion,
+		AwsAccountID:            accountClaim.Spec.BYOCAWSAccountID,
 	})
 	if err != nil {
 		return nil, nil, err
@@ -7,6 +7,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/support"
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openshift/aws-account-operator/api/v1alpha1"
 	"github.com/openshift/aws-account-operator/pkg/awsclient"
@@ -19,6 +20,7 @@ const (
 	caseServiceCode               = "customer-account"
 	caseIssueType                 = "customer-service"
 	caseSeverity                  = "high"
+	caseStatusOpened              = "opened"
 	caseStatusResolved            = "resolved"
 	caseLanguage                  = "en"
 	intervalAfterCaseCreationSecs = 30
@@ -76,7 +78,23 @@ Thanks.
 	return *caseResult.CaseId, nil
 }
 
-func checkCaseResolution(reqLogger logr.Logger, caseID string, client awsclient.Client) (bool, error) {
+// recordCaseCreation records the support case filed for the account in its status
+func recordCaseCreation(account *v1alpha1.Account, caseID string) {
+	now := metav1.Now()
+	account.Status.SupportCaseID = caseID
+	account.Status.SupportCase = &v1alpha1.SupportCaseStatus{
+		ID:           caseID,
+		Status:       caseStatusOpened,
+		CreationTime: &now,
+	}
+}
+
+// checkCaseResolution looks up the support case of the account, records its status in the account status and
+// returns true once it is resolved. The returned bool is false along with ErrAwsSupportCaseIDNotFound when AWS
+// doesn't know the case.
+func checkCaseResolution(reqLogger logr.Logger, account *v1alpha1.Account, client awsclient.Client) (bool, error) {
+	caseID := account.Status.SupportCaseID
+
 	// Look for the case using the unique ID provided
 	describeCasesInput := support.DescribeCasesInput{
 		CaseIdList: []*string{
@@ -102,14 +120,40 @@ func checkCaseResolution(reqLogger logr.Logger, caseID string, client awsclient.
 
 		return false, returnErr
 	}
+	if len(caseResult.Cases) == 0 {
+		return false, v1alpha1.ErrAwsSupportCaseIDNotFound
+	}
 
 	// Since we are describing cases based on the unique ID, this list will have only 1 element
-	if *caseResult.Cases[0].Status == caseStatusResolved {
+	caseStatus := aws.StringValue(caseResult.Cases[0].Status)
+	recordCaseStatus(account, caseStatus)
+	if caseStatus == caseStatusResolved {
 		reqLogger.Info(fmt.Sprintf("Case Resolved: %s", caseID))
 		return true, nil
 	}
 
-	reqLogger.Info(fmt.Sprintf("Case [%s] not yet Resolved, waiting. Current Status: %s", caseID, *caseResult.Cases[0].Status))
+	reqLogger.Info(fmt.Sprintf("Case [%s] not yet Resolved, waiting. Current Status: %s", caseID, caseStatus))
 
 	return false, nil
 }
+
+// supportCaseStatus returns the recorded status of the support case of the account, empty if none is recorded
+func supportCaseStatus(account *v1alpha1.Account) string {
+	if account.Status.SupportCase == nil {
+		return ""
+	}
+	return account.Status.SupportCase.Status
+}
+
+// recordCaseStatus records the status of the support case of the account, and when it was resolved
+func recordCaseStatus(account *v1alpha1.Account, caseStatus string) {
+	if account.Status.SupportCase == nil || account.Status.SupportCase.ID != account.Status.SupportCaseID {
+		// The case was filed before the operator recorded its status
+		account.Status.SupportCase = &v1alpha1.SupportCaseStatus{ID: account.Status.SupportCaseID}
+	}
+	account.Status.SupportCase.Status = caseStatus
+	if caseStatus == caseStatusResolved && account.Status.SupportCase.ResolutionTime == nil {
+		now := metav1.Now()
+		account.Status.SupportCase.ResolutionTime = &now
+	}
+}
@@ -0,0 +1,83 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/support"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCheckCaseResolution(t *testing.T) {
+	tests := []struct {
+		name           string
+		caseStatus     string
+		describeErr    error
+		expectResolved bool
+		expectErr      error
+	}{
+		{
+			name:       "Open case is recorded but not resolved",
+			caseStatus: "work-in-progress",
+		},
+		{
+			name:           "Resolved case records its resolution time",
+			caseStatus:     caseStatusResolved,
+			expectResolved: true,
+		},
+		{
+			name:        "Unknown case is reported as not found",
+			describeErr: awserr.New(support.ErrCodeCaseIdNotFound, "case not found", nil),
+			expectErr:   awsv1alpha1.ErrAwsSupportCaseIDNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			account := &awsv1alpha1.Account{}
+			recordCaseCreation(account, "123456")
+
+			mockAWSClient := mock.NewMockClient(ctrl)
+			output := &support.DescribeCasesOutput{
+				Cases: []*support.CaseDetails{{CaseId: aws.String("123456"), Status: aws.String(tt.caseStatus)}},
+			}
+			mockAWSClient.EXPECT().DescribeCases(gomock.Any()).Return(output, tt.describeErr)
+
+			resolved, err := checkCaseResolution(testutils.NewTestLogger().Logger(), account, mockAWSClient)
+			assert.Equal(t, tt.expectErr, err)
+			assert.Equal(t, tt.expectResolved, resolved)
+			if tt.expectErr != nil {
+				assert.Equal(t, caseStatusOpened, account.Status.SupportCase.Status)
+				return
+			}
+			assert.Equal(t, tt.caseStatus, account.Status.SupportCase.Status)
+			assert.Equal(t, tt.expectResolved, account.Status.SupportCase.ResolutionTime != nil)
+		})
+	}
+}
+
+func TestRecordCaseStatusOfUntrackedCase(t *testing.T) {
+	account := &awsv1alpha1.Account{Status: awsv1alpha1.AccountStatus{SupportCaseID: "123456"}}
+
+	recordCaseStatus(account, caseStatusResolved)
+
+	assert.Equal(t, "123456", account.Status.SupportCase.ID)
+	assert.Equal(t, caseStatusResolved, account.Status.SupportCase.Status)
+	assert.NotNil(t, account.Status.SupportCase.ResolutionTime)
+	assert.Nil(t, account.Status.SupportCase.CreationTime)
+}
+
+func TestSupportCaseStatus(t *testing.T) {
+	account := &awsv1alpha1.Account{Status: awsv1alpha1.AccountStatus{SupportCaseID: "123456"}}
+	assert.Equal(t, "", supportCaseStatus(account))
+
+	recordCaseStatus(account, "opened")
+	assert.Equal(t, "opened", supportCaseStatus(account))
+}
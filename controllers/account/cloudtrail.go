@@ -0,0 +1,276 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultCloudTrailCheckInterval is how often the organization trail is verified unless the configmap says
+	// otherwise. An interval of 0 disables the verification.
+	defaultCloudTrailCheckInterval = 6 * time.Hour
+
+	// cloudTrailMaxDeliveryAge is how long the organization trail can go without delivering the logs of an account
+	// before it's considered broken. CloudTrail delivers every few minutes while there's activity in the account.
+	cloudTrailMaxDeliveryAge = 24 * time.Hour
+
+	cloudTrailBrokenReason = "OrganizationTrailBroken"
+	cloudTrailOKReason     = "OrganizationTrailDelivering"
+	cloudTrailWatcher      = "cloudtrail-watcher"
+)
+
+// CloudTrailWatcher periodically verifies that the organization trail covers the Ready non-CCS accounts and is
+// delivering their logs, flagging the accounts it doesn't with the CloudTrailBroken condition. The condition is the
+// evidence compliance asks for that every account of the pool is audited.
+type CloudTrailWatcher struct {
+	client           client.Client
+	awsClientBuilder awsclient.IBuilder
+	recorder         record.EventRecorder
+}
+
+// NewCloudTrailWatcher returns a new CloudTrailWatcher
+func NewCloudTrailWatcher(client client.Client, awsClientBuilder awsclient.IBuilder, recorder record.EventRecorder) *CloudTrailWatcher {
+	return &CloudTrailWatcher{
+		client:           client,
+		awsClientBuilder: awsClientBuilder,
+		recorder:         recorder,
+	}
+}
+
+// Start verifies the organization trail every interval configured in the configmap, until the operator is stopped
+func (w *CloudTrailWatcher) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the CloudTrail watcher")
+	for {
+		interval, trailARN, remediate := getCloudTrailConfig(log, w.client)
+		if interval == 0 || trailARN == "" {
+			// Check again later whether the verification has been enabled
+			interval = defaultCloudTrailCheckInterval
+		} else {
+			w.VerifyAccounts(log, trailARN, remediate)
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-stopCh.Done():
+			log.Info("Stopping the CloudTrail watcher")
+			return
+		}
+	}
+}
+
+// getCloudTrailConfig reads the verification interval, the ARN of the organization trail and whether the trail is
+// remediated from the configmap. The interval falls back to the default when it's missing or invalid.
+func getCloudTrailConfig(log logr.Logger, kubeClient client.Client) (time.Duration, string, bool) {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		log.Error(err, "Unable to get the CloudTrail configuration from the configmap, using the default")
+		return defaultCloudTrailCheckInterval, "", false
+	}
+
+	trailARN := configMap.Data[awsv1alpha1.CloudTrailOrganizationTrailConfigMapKey]
+	remediate := configMap.Data[awsv1alpha1.CloudTrailRemediationConfigMapKey] == "true"
+
+	value, ok := configMap.Data[awsv1alpha1.CloudTrailCheckIntervalConfigMapKey]
+	if !ok || value == "" {
+		return defaultCloudTrailCheckInterval, trailARN, remediate
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Error(err, "Invalid CloudTrail check interval in configmap, using the default", "value", value)
+		return defaultCloudTrailCheckInterval, trailARN, remediate
+	}
+	return interval, trailARN, remediate
+}
+
+// VerifyAccounts restarts the organization trail if it's stopped and remediation is enabled, then verifies it in
+// every Ready non-CCS account. Failing to verify one account doesn't stop the others from being verified.
+func (w *CloudTrailWatcher) VerifyAccounts(log logr.Logger, trailARN string, remediate bool) {
+	parsed, err := arn.Parse(trailARN)
+	if err != nil {
+		log.Error(err, "Invalid organization trail ARN", "trailARN", trailARN)
+		return
+	}
+	// The trail can only be managed from its home region, where its bucket is read from too
+	awsSetupClient, err := w.awsClientBuilder.GetClient(cloudTrailWatcher, w.client, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  parsed.Region,
+	})
+	if err != nil {
+		log.Error(err, "Failed building operator AWS client")
+		return
+	}
+
+	// Every shard verifies its accounts, but the trail is the whole organization's
+	if remediate && utils.IsPrimaryShard() {
+		err := remediateOrganizationTrail(log, w.client, awsSetupClient, trailARN)
+		if err != nil {
+			log.Error(err, "Failed to remediate the organization trail", "trailARN", trailARN)
+		}
+	}
+
+	organization, err := awsSetupClient.DescribeOrganization(&organizations.DescribeOrganizationInput{})
+	if err != nil {
+		log.Error(err, "Failed to get the organization")
+		return
+	}
+	organizationID := aws.StringValue(organization.Organization.Id)
+
+	forEachManagedAccount(log, w.client, w.awsClientBuilder, cloudTrailWatcher, func(reqLogger logr.Logger, awsClient awsclient.Client, account *awsv1alpha1.Account) {
+		problems, err := VerifyOrganizationTrail(awsClient, awsSetupClient, trailARN, organizationID, account.Spec.AwsAccountID, time.Now())
+		if err != nil {
+			reqLogger.Error(err, "Failed to verify the organization trail")
+			return
+		}
+		if !w.setCloudTrailCondition(reqLogger, account, problems) {
+			return
+		}
+		err = utils.UpdateAccountConditionsWithRetry(w.client, account, awsv1alpha1.AccountCloudTrailBroken)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update account status")
+		}
+	})
+}
+
+// remediateOrganizationTrail restarts the logging of the organization trail from the payer account, which owns it,
+// when it has been stopped. Delivery errors, like a broken bucket policy, are left to an SRE.
+func remediateOrganizationTrail(log logr.Logger, kubeClient client.Client, awsSetupClient awsclient.Client, trailARN string) error {
+	status, err := awsSetupClient.GetTrailStatus(&cloudtrail.GetTrailStatusInput{Name: aws.String(trailARN)})
+	if err != nil {
+		return err
+	}
+	if aws.BoolValue(status.IsLogging) {
+		return nil
+	}
+	if utils.IsMaintenanceMode(kubeClient) {
+		log.Info("Maintenance mode is enabled, not restarting the organization trail", "trailARN", trailARN)
+		return nil
+	}
+	log.Info("Restarting the logging of the organization trail", "trailARN", trailARN)
+	_, err = awsSetupClient.StartLogging(&cloudtrail.StartLoggingInput{Name: aws.String(trailARN)})
+	return err
+}
+
+// VerifyOrganizationTrail checks, from a member account, that the organization trail covers it and is logging, and,
+// from the payer account, that the logs of the member account are delivered to the bucket of the trail under
+// AWSLogs/<organization-id>/<account-id>/. Returns why the account isn't audited, nothing when it is.
+func VerifyOrganizationTrail(awsClient awsclient.Client, awsSetupClient awsclient.Client, trailARN string, organizationID string, awsAccountID string, now time.Time) ([]string, error) {
+	trails, err := awsClient.DescribeTrails(&cloudtrail.DescribeTrailsInput{
+		TrailNameList:       aws.StringSlice([]string{trailARN}),
+		IncludeShadowTrails: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var trail *cloudtrail.Trail
+	for _, candidate := range trails.TrailList {
+		if aws.StringValue(candidate.TrailARN) == trailARN && aws.BoolValue(candidate.IsOrganizationTrail) {
+			trail = candidate
+			break
+		}
+	}
+	if trail == nil {
+		return []string{fmt.Sprintf("organization trail %s doesn't cover the account", trailARN)}, nil
+	}
+
+	status, err := awsClient.GetTrailStatus(&cloudtrail.GetTrailStatusInput{Name: aws.String(trailARN)})
+	if err != nil {
+		return nil, err
+	}
+	var problems []string
+	if !aws.BoolValue(status.IsLogging) {
+		problems = append(problems, "organization trail isn't logging")
+	}
+	if deliveryError := aws.StringValue(status.LatestDeliveryError); deliveryError != "" {
+		problems = append(problems, fmt.Sprintf("log delivery failed: %s", deliveryError))
+	}
+
+	delivered, err := accountLogsDelivered(awsSetupClient, trail, organizationID, awsAccountID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !delivered {
+		problems = append(problems, fmt.Sprintf("no logs of the account delivered to s3://%s/%s since %s", aws.StringValue(trail.S3BucketName), accountLogsPrefix(trail, organizationID, awsAccountID), now.Add(-cloudTrailMaxDeliveryAge).UTC().Format(time.RFC3339)))
+	}
+	return problems, nil
+}
+
+// accountLogsPrefix returns the key prefix the organization trail delivers the logs of the member account under
+func accountLogsPrefix(trail *cloudtrail.Trail, organizationID string, awsAccountID string) string {
+	prefix := fmt.Sprintf("AWSLogs/%s/%s/", organizationID, awsAccountID)
+	if keyPrefix := aws.StringValue(trail.S3KeyPrefix); keyPrefix != "" {
+		prefix = strings.TrimSuffix(keyPrefix, "/") + "/" + prefix
+	}
+	return prefix
+}
+
+// accountLogsDelivered checks whether the organization trail delivered logs of the member account since
+// cloudTrailMaxDeliveryAge. The logs are looked for in the default region, where the verification itself is logged,
+// under the folders of the days the logged events are from.
+func accountLogsDelivered(awsSetupClient awsclient.Client, trail *cloudtrail.Trail, organizationID string, awsAccountID string, now time.Time) (bool, error) {
+	since := now.Add(-cloudTrailMaxDeliveryAge)
+	for _, day := range []time.Time{now.UTC(), since.UTC()} {
+		prefix := fmt.Sprintf("%sCloudTrail/%s/%s/", accountLogsPrefix(trail, organizationID, awsAccountID), config.GetDefaultRegion(), day.Format("2006/01/02"))
+		input := &s3.ListObjectsV2Input{
+			Bucket: trail.S3BucketName,
+			Prefix: aws.String(prefix),
+		}
+		for {
+			output, err := awsSetupClient.ListObjectsV2(input)
+			if err != nil {
+				return false, err
+			}
+			for _, object := range output.Contents {
+				if object.LastModified != nil && object.LastModified.After(since) {
+					return true, nil
+				}
+			}
+			if !aws.BoolValue(output.IsTruncated) {
+				break
+			}
+			input.ContinuationToken = output.NextContinuationToken
+		}
+	}
+	return false, nil
+}
+
+// setCloudTrailCondition sets the CloudTrailBroken condition of the account from the problems of the organization
+// trail, and clears it once they're gone. Returns true if the condition changed.
+func (w *CloudTrailWatcher) setCloudTrailCondition(reqLogger logr.Logger, account *awsv1alpha1.Account, problems []string) bool {
+	existing := account.GetCondition(awsv1alpha1.AccountCloudTrailBroken)
+
+	if len(problems) == 0 {
+		if existing == nil || existing.Status == corev1.ConditionFalse {
+			return false
+		}
+		reqLogger.Info("The organization trail is delivering the logs of the account again")
+		account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountCloudTrailBroken, corev1.ConditionFalse, cloudTrailOKReason, "The organization trail is delivering the logs of the account", utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+		utils.RecordEvent(w.recorder, account, corev1.EventTypeNormal, utils.EventReasonCloudTrailRestored, "The organization trail is delivering the logs of the account")
+		return true
+	}
+
+	message := strings.Join(problems, "; ")
+	if existing != nil && existing.Status == corev1.ConditionTrue && existing.Message == message {
+		return false
+	}
+	reqLogger.Info("The organization trail isn't delivering the logs of the account", "problems", message)
+	account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountCloudTrailBroken, corev1.ConditionTrue, cloudTrailBrokenReason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+	utils.RecordEvent(w.recorder, account, corev1.EventTypeWarning, utils.EventReasonCloudTrailBroken, "%s", message)
+	return true
+}
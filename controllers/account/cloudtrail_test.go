@@ -0,0 +1,133 @@
+package account
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/s3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("CloudTrail watcher", func() {
+	const (
+		trailARN       = "arn:aws:cloudtrail:us-east-1:111111111111:trail/org-trail"
+		organizationID = "o-abcdef"
+		awsAccountID   = "123456789012"
+	)
+
+	var (
+		ctrl          *gomock.Controller
+		mockAWSClient *mock.MockClient
+		now           time.Time
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+		now = time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectTrail := func(isOrganizationTrail bool) {
+		mockAWSClient.EXPECT().DescribeTrails(&cloudtrail.DescribeTrailsInput{
+			TrailNameList:       aws.StringSlice([]string{trailARN}),
+			IncludeShadowTrails: aws.Bool(true),
+		}).Return(&cloudtrail.DescribeTrailsOutput{TrailList: []*cloudtrail.Trail{{
+			TrailARN:            aws.String(trailARN),
+			IsOrganizationTrail: aws.Bool(isOrganizationTrail),
+			S3BucketName:        aws.String("org-trail-logs"),
+			S3KeyPrefix:         aws.String("audit"),
+		}}}, nil)
+	}
+
+	// expectDelivery lists the logs of the account for the day, returning one delivered at deliveredAt
+	expectDelivery := func(day string, deliveredAt time.Time) {
+		mockAWSClient.EXPECT().ListObjectsV2(&s3.ListObjectsV2Input{
+			Bucket: aws.String("org-trail-logs"),
+			Prefix: aws.String("audit/AWSLogs/o-abcdef/123456789012/CloudTrail/us-east-1/" + day + "/"),
+		}).Return(&s3.ListObjectsV2Output{Contents: []*s3.Object{{LastModified: aws.Time(deliveredAt)}}}, nil)
+	}
+
+	It("Passes an account the trail delivers the logs of", func() {
+		expectTrail(true)
+		mockAWSClient.EXPECT().GetTrailStatus(&cloudtrail.GetTrailStatusInput{Name: aws.String(trailARN)}).Return(&cloudtrail.GetTrailStatusOutput{
+			IsLogging:          aws.Bool(true),
+			LatestDeliveryTime: aws.Time(now.Add(-10 * time.Minute)),
+		}, nil)
+		expectDelivery("2024/03/10", now.Add(-10*time.Minute))
+
+		problems, err := VerifyOrganizationTrail(mockAWSClient, mockAWSClient, trailARN, organizationID, awsAccountID, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(BeEmpty())
+	})
+
+	It("Reports an account the trail doesn't cover", func() {
+		expectTrail(false)
+
+		problems, err := VerifyOrganizationTrail(mockAWSClient, mockAWSClient, trailARN, organizationID, awsAccountID, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(Equal([]string{"organization trail " + trailARN + " doesn't cover the account"}))
+	})
+
+	It("Reports a trail that stopped delivering", func() {
+		expectTrail(true)
+		mockAWSClient.EXPECT().GetTrailStatus(gomock.Any()).Return(&cloudtrail.GetTrailStatusOutput{
+			IsLogging:           aws.Bool(false),
+			LatestDeliveryError: aws.String("AccessDenied"),
+			LatestDeliveryTime:  aws.Time(now.Add(-48 * time.Hour)),
+		}, nil)
+		expectDelivery("2024/03/10", now.Add(-48*time.Hour))
+		expectDelivery("2024/03/09", now.Add(-30*time.Hour))
+
+		problems, err := VerifyOrganizationTrail(mockAWSClient, mockAWSClient, trailARN, organizationID, awsAccountID, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(Equal([]string{
+			"organization trail isn't logging",
+			"log delivery failed: AccessDenied",
+			"no logs of the account delivered to s3://org-trail-logs/audit/AWSLogs/o-abcdef/123456789012/ since 2024-03-09T12:00:00Z",
+		}))
+	})
+
+	It("Reports an account the trail doesn't deliver the logs of", func() {
+		expectTrail(true)
+		mockAWSClient.EXPECT().GetTrailStatus(gomock.Any()).Return(&cloudtrail.GetTrailStatusOutput{
+			IsLogging:          aws.Bool(true),
+			LatestDeliveryTime: aws.Time(now.Add(-10 * time.Minute)),
+		}, nil)
+		mockAWSClient.EXPECT().ListObjectsV2(gomock.Any()).Return(&s3.ListObjectsV2Output{}, nil).Times(2)
+
+		problems, err := VerifyOrganizationTrail(mockAWSClient, mockAWSClient, trailARN, organizationID, awsAccountID, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(Equal([]string{
+			"no logs of the account delivered to s3://org-trail-logs/audit/AWSLogs/o-abcdef/123456789012/ since 2024-03-09T12:00:00Z",
+		}))
+	})
+
+	It("Sets the CloudTrailBroken condition and clears it once the trail delivers again", func() {
+		w := &CloudTrailWatcher{}
+		account := &awsv1alpha1.Account{}
+		log := testutils.NewTestLogger().Logger()
+
+		Expect(w.setCloudTrailCondition(log, account, nil)).To(BeFalse())
+		Expect(account.GetCondition(awsv1alpha1.AccountCloudTrailBroken)).To(BeNil())
+
+		Expect(w.setCloudTrailCondition(log, account, []string{"organization trail isn't logging"})).To(BeTrue())
+		condition := account.GetCondition(awsv1alpha1.AccountCloudTrailBroken)
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition.Message).To(Equal("organization trail isn't logging"))
+		Expect(w.setCloudTrailCondition(log, account, []string{"organization trail isn't logging"})).To(BeFalse())
+
+		Expect(w.setCloudTrailCondition(log, account, nil)).To(BeTrue())
+		Expect(account.GetCondition(awsv1alpha1.AccountCloudTrailBroken).Status).To(Equal(corev1.ConditionFalse))
+	})
+})
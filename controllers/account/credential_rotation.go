@@ -0,0 +1,371 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/secretstore"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// credentialRotationInterval is how often access key ages are checked
+	credentialRotationInterval = time.Hour
+
+	// maxAccessKeysPerUser is the number of access keys AWS allows an IAM user to have
+	maxAccessKeysPerUser = 2
+
+	credentialRotator       = "credential-rotator"
+	secretUserName          = "aws_user_name"
+	secretSecretAccessKey   = "aws_secret_access_key" // #nosec G101 -- This is a false positive
+	accessKeyMaxAgeDisabled = time.Duration(0)
+
+	iamUserMissingReason = "IAMUserNotFound"
+	iamUserFoundReason   = "IAMUserFound"
+)
+
+// rotationResult is what the rotation did with the IAM user of an account
+type rotationResult string
+
+const (
+	rotationCurrent   rotationResult = "current"
+	rotationRotated   rotationResult = "rotated"
+	rotationMissing   rotationResult = "missing"
+	rotationRecreated rotationResult = "recreated"
+	rotationFailed    rotationResult = "failed"
+)
+
+// CredentialRotator periodically records the age of the osdManagedAdmin access key of Ready accounts
+// and rotates the keys older than the maximum age configured in the configmap
+type CredentialRotator struct {
+	client           client.Client
+	awsClientBuilder awsclient.IBuilder
+	recorder         record.EventRecorder
+}
+
+// NewCredentialRotator returns a new CredentialRotator
+func NewCredentialRotator(client client.Client, awsClientBuilder awsclient.IBuilder, recorder record.EventRecorder) *CredentialRotator {
+	return &CredentialRotator{
+		client:           client,
+		awsClientBuilder: awsClientBuilder,
+		recorder:         recorder,
+	}
+}
+
+// Start checks the access keys every credentialRotationInterval, until the operator is stopped
+func (c *CredentialRotator) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the credential rotator")
+	for {
+		c.RotateCredentials(log)
+
+		select {
+		case <-time.After(credentialRotationInterval):
+		case <-stopCh.Done():
+			log.Info("Stopping the credential rotator")
+			return
+		}
+	}
+}
+
+// getAccessKeyMaxAge reads the maximum access key age from the configmap. Keys aren't rotated when
+// it's not set.
+func getAccessKeyMaxAge(log logr.Logger, kubeClient client.Client) time.Duration {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		log.Error(err, "Unable to get the access key max age from the configmap, not rotating access keys")
+		return accessKeyMaxAgeDisabled
+	}
+
+	value := configMap.Data[awsv1alpha1.AccessKeyMaxAgeConfigMapKey]
+	if value == "" {
+		return accessKeyMaxAgeDisabled
+	}
+	maxAge, err := time.ParseDuration(value)
+	if err != nil || maxAge < 0 {
+		log.Error(err, "Invalid access key max age in configmap, not rotating access keys", "value", value)
+		return accessKeyMaxAgeDisabled
+	}
+	return maxAge
+}
+
+// RotateCredentials records the access key creation time of every Ready non-CCS account and rotates
+// the keys that are older than the configured maximum age. Nothing is done in maintenance or observe-only mode.
+func (c *CredentialRotator) RotateCredentials(log logr.Logger) {
+	if utils.IsAWSMutationPaused(c.client) {
+		log.Info("AWS changes are paused, not rotating credentials")
+		return
+	}
+	maxAge := getAccessKeyMaxAge(log, c.client)
+	recreateMissing := recreateMissingIAMUsers(log, c.client)
+
+	// A failed or missing user doesn't stop the users of the other accounts from being rotated
+	results := map[rotationResult]int{}
+	forEachManagedAccount(log, c.client, c.awsClientBuilder, credentialRotator, func(reqLogger logr.Logger, awsClient awsclient.Client, account *awsv1alpha1.Account) {
+		result, err := c.rotateAccountCredentials(reqLogger, awsClient, account, maxAge, recreateMissing)
+		if err != nil {
+			reqLogger.Error(err, "Failed to rotate account credentials")
+			result = rotationFailed
+		}
+		if result != "" {
+			results[result]++
+		}
+	})
+	log.Info("Credential rotation finished",
+		string(rotationCurrent), results[rotationCurrent],
+		string(rotationRotated), results[rotationRotated],
+		string(rotationMissing), results[rotationMissing],
+		string(rotationRecreated), results[rotationRecreated],
+		string(rotationFailed), results[rotationFailed])
+}
+
+// recreateMissingIAMUsers reads from the configmap whether the IAM users found missing are recreated. They're
+// only flagged otherwise.
+func recreateMissingIAMUsers(log logr.Logger, kubeClient client.Client) bool {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		log.Error(err, "Unable to get the configmap, not recreating missing IAM users")
+		return false
+	}
+	return configMap.Data[awsv1alpha1.RecreateMissingIAMUsersConfigMapKey] == "true"
+}
+
+// rotateAccountCredentials rotates the access key of the IAM user of the account if it's older than maxAge. A
+// missing user is recreated with a new access key when recreateMissing is set, otherwise the account is flagged
+// with the IAMUserMissing condition.
+func (c *CredentialRotator) rotateAccountCredentials(reqLogger logr.Logger, awsClient awsclient.Client, account *awsv1alpha1.Account, maxAge time.Duration, recreateMissing bool) (rotationResult, error) {
+	if account.Spec.IAMUserSecret == "" {
+		return "", nil
+	}
+
+	accountSecret := &corev1.Secret{}
+	err := c.client.Get(context.TODO(), types.NamespacedName{Name: account.Spec.IAMUserSecret, Namespace: account.Namespace}, accountSecret)
+	if err != nil {
+		return "", err
+	}
+
+	userName := aws.String(string(accountSecret.Data[secretUserName]))
+	if aws.StringValue(userName) == "" {
+		userName = aws.String(account.GetIAMUserName())
+	}
+	reqLogger = reqLogger.WithValues("iamUser", aws.StringValue(userName))
+
+	// listAccessKeys waits for a recently recreated user to propagate, a user still missing is gone
+	accessKeys, err := listAccessKeys(awsClient, &iam.User{UserName: userName})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+		if !recreateMissing {
+			return rotationMissing, c.flagMissingIAMUser(reqLogger, account, aws.StringValue(userName))
+		}
+		return rotationRecreated, c.recreateIAMUser(reqLogger, awsClient, account, accountSecret, aws.StringValue(userName))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// A previous rotation may have failed to update the claim's secret
+	_, err = syncClaimSecret(reqLogger, c.client, awsClient, account, accountSecret)
+	if err != nil {
+		return "", err
+	}
+
+	currentKeyID := string(accountSecret.Data[secretAccessKeyID])
+	var currentKey *iam.AccessKeyMetadata
+	for _, accessKey := range accessKeys.AccessKeyMetadata {
+		if aws.StringValue(accessKey.AccessKeyId) == currentKeyID {
+			currentKey = accessKey
+		}
+	}
+	if currentKey == nil || currentKey.CreateDate == nil {
+		return "", fmt.Errorf("access key %s in secret %s doesn't belong to IAM user %s", currentKeyID, accountSecret.Name, aws.StringValue(userName))
+	}
+
+	if maxAge == accessKeyMaxAgeDisabled || time.Since(*currentKey.CreateDate) < maxAge {
+		return rotationCurrent, c.setAccessKeyCreationTime(account, *currentKey.CreateDate)
+	}
+
+	// The new key has to be created before the current one is deleted so the secrets can be updated
+	// without the credentials ever being invalid
+	if len(accessKeys.AccessKeyMetadata) >= maxAccessKeysPerUser {
+		return "", fmt.Errorf("IAM user %s already has %d access keys, remove the unexpected ones to allow rotation", aws.StringValue(userName), len(accessKeys.AccessKeyMetadata))
+	}
+
+	reqLogger.Info("Rotating access key", "accessKeyID", currentKeyID, "age", time.Since(*currentKey.CreateDate).Round(time.Minute).String())
+	newKey, deletedKeyID, err := c.replaceAccessKey(reqLogger, awsClient, account, accountSecret, userName)
+	if err != nil {
+		return "", err
+	}
+
+	if deletedKeyID != currentKeyID {
+		_, err = deleteAccessKey(awsClient, aws.String(currentKeyID), userName)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	utils.RecordEvent(c.recorder, account, corev1.EventTypeNormal, utils.EventReasonCredentialsRotated, "Rotated access key of IAM user %s older than %s", aws.StringValue(userName), maxAge)
+	return rotationRotated, c.setAccessKeyCreationTime(account, aws.TimeValue(newKey.CreateDate))
+}
+
+// replaceAccessKey creates a new access key for the user and puts it in the secrets of the account and of its claim.
+// Returns the new key and the ID of the key the claim's secret held, which was deleted.
+func (c *CredentialRotator) replaceAccessKey(reqLogger logr.Logger, awsClient awsclient.Client, account *awsv1alpha1.Account, accountSecret *corev1.Secret, userName *string) (*iam.AccessKey, string, error) {
+	newKey, err := putNewAccessKey(reqLogger, c.client, awsClient, accountSecret, userName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The previous key is kept until the claim's secret has the new one. Updating the claim's secret
+	// deletes the key it held, which usually is the previous key.
+	deletedKeyID, err := syncClaimSecret(reqLogger, c.client, awsClient, account, accountSecret)
+	if err != nil {
+		return nil, "", err
+	}
+	return newKey, deletedKeyID, nil
+}
+
+// putNewAccessKey creates a new access key for the user and puts it in the secret of the account. The key is deleted
+// again if the secret can't be updated.
+func putNewAccessKey(reqLogger logr.Logger, kubeClient client.Client, awsClient awsclient.Client, accountSecret *corev1.Secret, userName *string) (*iam.AccessKey, error) {
+	accessKeyOutput, err := CreateUserAccessKey(awsClient, &iam.User{UserName: userName})
+	if err != nil {
+		return nil, err
+	}
+	newKey := accessKeyOutput.AccessKey
+
+	// All keys of the secret are replaced in a single update, so readers never see a mismatched pair.
+	// Conflicts are retried, the new key would otherwise be lost.
+	err = utils.UpdateWithRetry(kubeClient, accountSecret, func() error {
+		if accountSecret.Data == nil {
+			accountSecret.Data = map[string][]byte{}
+		}
+		accountSecret.Data[secretUserName] = []byte(aws.StringValue(newKey.UserName))
+		accountSecret.Data[secretAccessKeyID] = []byte(aws.StringValue(newKey.AccessKeyId))
+		accountSecret.Data[secretSecretAccessKey] = []byte(aws.StringValue(newKey.SecretAccessKey))
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to update secret with the new access key, deleting it")
+		if _, deleteErr := deleteAccessKey(awsClient, newKey.AccessKeyId, userName); deleteErr != nil {
+			reqLogger.Error(deleteErr, "Failed to delete the new access key")
+		}
+		return nil, err
+	}
+	return newKey, nil
+}
+
+// flagMissingIAMUser sets the IAMUserMissing condition of an account whose IAM user is gone
+func (c *CredentialRotator) flagMissingIAMUser(reqLogger logr.Logger, account *awsv1alpha1.Account, userName string) error {
+	if condition := account.GetCondition(awsv1alpha1.AccountIAMUserMissing); condition != nil && condition.Status == corev1.ConditionTrue {
+		return nil
+	}
+	message := fmt.Sprintf("IAM user %s doesn't exist, its access key can't be rotated", userName)
+	reqLogger.Info(message)
+	utils.RecordEvent(c.recorder, account, corev1.EventTypeWarning, utils.EventReasonIAMUserMissing, "%s", message)
+	return utils.UpdateStatusWithRetry(c.client, account, func() error {
+		account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountIAMUserMissing, corev1.ConditionTrue, iamUserMissingReason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+		return nil
+	})
+}
+
+// recreateIAMUser creates the missing IAM user of the account again and puts a new access key in the secrets of the
+// account and of its claim
+func (c *CredentialRotator) recreateIAMUser(reqLogger logr.Logger, awsClient awsclient.Client, account *awsv1alpha1.Account, accountSecret *corev1.Secret, userName string) error {
+	newKey, err := RecreateIAMUser(reqLogger, c.client, awsClient, c.recorder, account, accountSecret, userName)
+	if err != nil {
+		return err
+	}
+	_, err = syncClaimSecret(reqLogger, c.client, awsClient, account, accountSecret)
+	if err != nil {
+		return err
+	}
+	return c.setAccessKeyCreationTime(account, aws.TimeValue(newKey.CreateDate))
+}
+
+// syncClaimSecret copies the credentials of the account's secret to the secret of the AccountClaim the
+// account is claimed by, and deletes the access key the claim's secret held before. Returns the ID of
+// the deleted access key, if any.
+func syncClaimSecret(reqLogger logr.Logger, kubeClient client.Client, awsClient awsclient.Client, account *awsv1alpha1.Account, accountSecret *corev1.Secret) (string, error) {
+	if !account.IsClaimed() || account.Spec.ClaimLink == "" {
+		return "", nil
+	}
+
+	accountClaim := &awsv1alpha1.AccountClaim{}
+	err := kubeClient.Get(context.TODO(), types.NamespacedName{Name: account.Spec.ClaimLink, Namespace: account.Spec.ClaimLinkNamespace}, accountClaim)
+	if err != nil {
+		return "", err
+	}
+
+	// The claim's secret lives in the configured secret store, which may not be Kubernetes
+	store, err := secretstore.New(kubeClient)
+	if err != nil {
+		return "", err
+	}
+	claimSecretKey := types.NamespacedName{Name: accountClaim.Spec.AwsCredentialSecret.Name, Namespace: accountClaim.Spec.AwsCredentialSecret.Namespace}
+	claimSecretData, err := store.Get(context.TODO(), claimSecretKey)
+	if err != nil {
+		return "", err
+	}
+
+	previousKeyID := string(claimSecretData[secretAccessKeyID])
+	currentKeyID := string(accountSecret.Data[secretAccessKeyID])
+	if previousKeyID == currentKeyID {
+		return "", nil
+	}
+
+	reqLogger.Info("Updating AccountClaim secret with the rotated access key", "secret", claimSecretKey.Name, "namespace", claimSecretKey.Namespace, "backend", store.Backend())
+	if claimSecretData == nil {
+		claimSecretData = map[string][]byte{}
+	}
+	claimSecretData[secretAccessKeyID] = accountSecret.Data[secretAccessKeyID]
+	claimSecretData[secretSecretAccessKey] = accountSecret.Data[secretSecretAccessKey]
+	claimSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: claimSecretKey.Name, Namespace: claimSecretKey.Namespace},
+		Data:       claimSecretData,
+	}
+	err = store.Put(context.TODO(), claimSecret)
+	if err != nil {
+		return "", err
+	}
+
+	if previousKeyID == "" {
+		return "", nil
+	}
+	userName := account.GetIAMUserName()
+	_, err = deleteAccessKey(awsClient, aws.String(previousKeyID), aws.String(userName))
+	if err != nil {
+		// The previous key is already gone
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+			return previousKeyID, nil
+		}
+		return "", err
+	}
+	return previousKeyID, nil
+}
+
+// setAccessKeyCreationTime records the creation time of the current access key in the account status, and clears
+// the IAMUserMissing condition of an account whose IAM user exists again
+func (c *CredentialRotator) setAccessKeyCreationTime(account *awsv1alpha1.Account, createDate time.Time) error {
+	missing := account.GetCondition(awsv1alpha1.AccountIAMUserMissing)
+	userMissing := missing != nil && missing.Status == corev1.ConditionTrue
+	if !userMissing && account.Status.AccessKeyCreationTime != nil && account.Status.AccessKeyCreationTime.Time.Equal(createDate) {
+		return nil
+	}
+	return utils.UpdateStatusWithRetry(c.client, account, func() error {
+		account.Status.AccessKeyCreationTime = &metav1.Time{Time: createDate}
+		if userMissing {
+			account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountIAMUserMissing, corev1.ConditionFalse, iamUserFoundReason, "The IAM user of the account exists", utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+		}
+		return nil
+	})
+}
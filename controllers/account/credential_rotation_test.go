@@ -0,0 +1,226 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Credential rotation", func() {
+	var (
+		nullLogger    logr.Logger
+		mockAWSClient *mock.MockClient
+		ctrl          *gomock.Controller
+		account       *awsv1alpha1.Account
+		accountSecret *corev1.Secret
+		objects       []client.Object
+		userName      string
+		keyCreated    time.Time
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		ctrl = gomock.NewController(GinkgoT())
+		nullLogger = testutils.NewTestLogger().Logger()
+		mockAWSClient = mock.NewMockClient(ctrl)
+		userName = "osdManagedAdmin-abcdef"
+		keyCreated = time.Now().Add(-100 * 24 * time.Hour).UTC().Truncate(time.Second)
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osd-creds-mgmt-abcdef",
+				Namespace: awsv1alpha1.AccountCrNamespace,
+				Labels:    map[string]string{awsv1alpha1.IAMUserIDLabel: "abcdef"},
+			},
+			Spec: awsv1alpha1.AccountSpec{
+				AwsAccountID:  "123456789012",
+				IAMUserSecret: "osd-creds-mgmt-abcdef-secret",
+			},
+		}
+		accountSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osd-creds-mgmt-abcdef-secret",
+				Namespace: awsv1alpha1.AccountCrNamespace,
+			},
+			Data: map[string][]byte{
+				"aws_user_name":         []byte(userName),
+				"aws_access_key_id":     []byte("AKIAOLD"),
+				"aws_secret_access_key": []byte("old-secret"),
+			},
+		}
+		objects = nil
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	rotate := func(maxAge time.Duration) (client.Client, error) {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(objects, account, accountSecret)...).Build()
+		rotator := NewCredentialRotator(kubeClient, nil, nil)
+		_, err := rotator.rotateAccountCredentials(nullLogger, mockAWSClient, account, maxAge, false)
+		return kubeClient, err
+	}
+
+	expectAccessKeys := func(accessKeyIDs ...string) {
+		accessKeys := []*iam.AccessKeyMetadata{}
+		for _, accessKeyID := range accessKeyIDs {
+			accessKeys = append(accessKeys, &iam.AccessKeyMetadata{AccessKeyId: aws.String(accessKeyID), CreateDate: aws.Time(keyCreated)})
+		}
+		mockAWSClient.EXPECT().ListAccessKeys(&iam.ListAccessKeysInput{UserName: aws.String(userName)}).Return(&iam.ListAccessKeysOutput{
+			AccessKeyMetadata: accessKeys,
+		}, nil)
+	}
+
+	expectNewAccessKey := func() {
+		mockAWSClient.EXPECT().CreateAccessKey(&iam.CreateAccessKeyInput{UserName: aws.String(userName)}).Return(&iam.CreateAccessKeyOutput{
+			AccessKey: &iam.AccessKey{
+				UserName:        aws.String(userName),
+				AccessKeyId:     aws.String("AKIANEW"),
+				SecretAccessKey: aws.String("new-secret"),
+				CreateDate:      aws.Time(time.Now()),
+			},
+		}, nil)
+	}
+
+	getSecret := func(kubeClient client.Client, name string, namespace string) *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, secret)).To(Succeed())
+		return secret
+	}
+
+	It("Records the access key creation time without rotating when rotation is disabled", func() {
+		expectAccessKeys("AKIAOLD")
+		kubeClient, err := rotate(accessKeyMaxAgeDisabled)
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), types.NamespacedName{Name: account.Name, Namespace: account.Namespace}, updated)).To(Succeed())
+		Expect(updated.Status.AccessKeyCreationTime.Time.Equal(keyCreated)).To(BeTrue())
+	})
+
+	It("Doesn't rotate a key younger than the maximum age", func() {
+		expectAccessKeys("AKIAOLD")
+		kubeClient, err := rotate(365 * 24 * time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getSecret(kubeClient, accountSecret.Name, accountSecret.Namespace).Data["aws_access_key_id"]).To(Equal([]byte("AKIAOLD")))
+	})
+
+	It("Rotates a key older than the maximum age", func() {
+		expectAccessKeys("AKIAOLD")
+		expectNewAccessKey()
+		mockAWSClient.EXPECT().DeleteAccessKey(&iam.DeleteAccessKeyInput{
+			AccessKeyId: aws.String("AKIAOLD"),
+			UserName:    aws.String(userName),
+		}).Return(&iam.DeleteAccessKeyOutput{}, nil)
+
+		kubeClient, err := rotate(90 * 24 * time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+
+		secret := getSecret(kubeClient, accountSecret.Name, accountSecret.Namespace)
+		Expect(secret.Data["aws_access_key_id"]).To(Equal([]byte("AKIANEW")))
+		Expect(secret.Data["aws_secret_access_key"]).To(Equal([]byte("new-secret")))
+	})
+
+	It("Updates the secret of the claim before deleting the old key", func() {
+		account.Status.Claimed = true
+		account.Spec.ClaimLink = "claim"
+		account.Spec.ClaimLinkNamespace = "claim-namespace"
+		objects = []client.Object{
+			&awsv1alpha1.AccountClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "claim-namespace"},
+				Spec: awsv1alpha1.AccountClaimSpec{
+					AwsCredentialSecret: awsv1alpha1.SecretRef{Name: "aws", Namespace: "claim-namespace"},
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "aws", Namespace: "claim-namespace"},
+				Data: map[string][]byte{
+					"aws_access_key_id":     []byte("AKIAOLD"),
+					"aws_secret_access_key": []byte("old-secret"),
+				},
+			},
+		}
+
+		expectAccessKeys("AKIAOLD")
+		expectNewAccessKey()
+		mockAWSClient.EXPECT().DeleteAccessKey(&iam.DeleteAccessKeyInput{
+			AccessKeyId: aws.String("AKIAOLD"),
+			UserName:    aws.String(userName),
+		}).Return(&iam.DeleteAccessKeyOutput{}, nil)
+
+		kubeClient, err := rotate(90 * 24 * time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+
+		claimSecret := getSecret(kubeClient, "aws", "claim-namespace")
+		Expect(claimSecret.Data["aws_access_key_id"]).To(Equal([]byte("AKIANEW")))
+		Expect(claimSecret.Data["aws_secret_access_key"]).To(Equal([]byte("new-secret")))
+	})
+
+	It("Refuses to rotate when the user already has the maximum number of keys", func() {
+		expectAccessKeys("AKIAOLD", "AKIAEXTRA")
+		_, err := rotate(90 * 24 * time.Hour)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("When the IAM user is missing", func() {
+		BeforeEach(func() {
+			mockAWSClient.EXPECT().ListAccessKeys(&iam.ListAccessKeysInput{UserName: aws.String(userName)}).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil)).Times(5)
+		})
+
+		getAccount := func(kubeClient client.Client) *awsv1alpha1.Account {
+			updated := &awsv1alpha1.Account{}
+			Expect(kubeClient.Get(context.TODO(), types.NamespacedName{Name: account.Name, Namespace: account.Namespace}, updated)).To(Succeed())
+			return updated
+		}
+
+		It("Flags the account and moves on", func() {
+			kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account, accountSecret).Build()
+			result, err := NewCredentialRotator(kubeClient, nil, nil).rotateAccountCredentials(nullLogger, mockAWSClient, account, 90*24*time.Hour, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(rotationMissing))
+
+			condition := getAccount(kubeClient).GetCondition(awsv1alpha1.AccountIAMUserMissing)
+			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(condition.Message).To(Equal("IAM user osdManagedAdmin-abcdef doesn't exist, its access key can't be rotated"))
+		})
+
+		It("Recreates the user with a new access key when enabled", func() {
+			account.Status.Conditions = []awsv1alpha1.AccountCondition{{Type: awsv1alpha1.AccountIAMUserMissing, Status: corev1.ConditionTrue}}
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+				Data:       map[string]string{awsv1alpha1.RecreateMissingIAMUsersConfigMapKey: "true"},
+			}
+			kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account, accountSecret, configMap).Build()
+			Expect(recreateMissingIAMUsers(nullLogger, kubeClient)).To(BeTrue())
+
+			mockAWSClient.EXPECT().GetUser(&iam.GetUserInput{UserName: aws.String(userName)}).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil))
+			mockAWSClient.EXPECT().CreateUser(gomock.Any()).Return(&iam.CreateUserOutput{User: &iam.User{UserName: aws.String(userName)}}, nil)
+			mockAWSClient.EXPECT().AttachUserPolicy(gomock.Any()).Return(&iam.AttachUserPolicyOutput{}, nil)
+			expectNewAccessKey()
+
+			result, err := NewCredentialRotator(kubeClient, nil, nil).rotateAccountCredentials(nullLogger, mockAWSClient, account, 90*24*time.Hour, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(rotationRecreated))
+
+			Expect(getSecret(kubeClient, accountSecret.Name, accountSecret.Namespace).Data["aws_access_key_id"]).To(Equal([]byte("AKIANEW")))
+			Expect(getAccount(kubeClient).GetCondition(awsv1alpha1.AccountIAMUserMissing).Status).To(Equal(corev1.ConditionFalse))
+		})
+	})
+})
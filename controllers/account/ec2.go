@@ -3,7 +3,10 @@ package account
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -15,6 +18,7 @@ import (
 	"github.com/openshift/aws-account-operator/config"
 	"github.com/openshift/aws-account-operator/pkg/awsclient"
 	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type regionInitializationError struct {
@@ -30,21 +34,21 @@ const EXECUTABLEBY = "all"
 const T3INSTANCETYPE = "t3.micro"
 const T2INSTANCETYPE = "t2.micro"
 
+// defaultRegionInitConcurrency is how many regions are initialized at the same time unless configured
+const defaultRegionInitConcurrency = 8
+
+// regionInitMaxMessages bounds the notifications and errors InitializeRegion sends for a region
+const regionInitMaxMessages = 4
+
 var sampleCIDR = "10.0.0.0/16"
-var sampleVPCID = ""
 
-// InitializeSupportedRegions concurrently calls InitializeRegion to create instances in all supported regions
+// InitializeSupportedRegions calls InitializeRegion to create instances in all supported regions, with at
+// most region-init-concurrency regions initialized at the same time
 // This should ensure we don't see any AWS API "PendingVerification" errors when launching instances
 // NOTE: This function does not have any returns. In particular, error conditions from the
-// goroutines are logged, but do not result in a failure up the stack.
+// goroutines are logged and recorded per region in the account status, but do not result in a failure
+// up the stack.
 func (r *AccountReconciler) InitializeSupportedRegions(reqLogger logr.Logger, account *awsv1alpha1.Account, regions []awsv1alpha1.AwsRegions, creds *sts.AssumeRoleOutput, amiOwner string) {
-	// Create some channels to listen and error on when creating EC2 instances in all supported regions
-	ec2Notifications, ec2Errors := make(chan string), make(chan regionInitializationError)
-
-	// Make sure we close our channels when we're done
-	defer close(ec2Notifications)
-	defer close(ec2Errors)
-
 	// We should not bomb out just because we can't retrieve the vCPU value
 	// and we'll just continue with a "0"
 	// Errors are logged already in getDesiredVCPUValue
@@ -63,43 +67,130 @@ func (r *AccountReconciler) InitializeSupportedRegions(reqLogger logr.Logger, ac
 	managedTags := r.getManagedTags(reqLogger)
 	customerTags := r.getCustomTags(reqLogger, account)
 
-	// Create go routines to initialize regions in parallel
-	for _, region := range regions {
-		go r.InitializeRegion(reqLogger, account, region.Name, amiOwner, vCPUQuota, ec2Notifications, ec2Errors, creds, managedTags, customerTags, kmsKeyId) //nolint:errcheck // Unable to do anything with the returned error
+	concurrency := getRegionInitConcurrency(reqLogger, r.Client)
+	mode := controllerutils.GetRegionInitModeFromAccountPool(reqLogger, account.Spec.AccountPool, r.Client)
+	// Instances in fedramp regions need the VPC created by the instance mode
+	if config.IsFedramp() {
+		mode = controllerutils.RegionInitModeInstance
 	}
+	reqLogger.Info("Initializing regions", "regions", len(regions), "concurrency", concurrency, "mode", mode)
+
+	// Initialize the regions with a bounded pool of workers, each region records its own result
+	results := make([]awsv1alpha1.RegionInitializationStatus, len(regions))
+	workers := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func(i int, region string) {
+			defer wg.Done()
+			workers <- struct{}{}
+			defer func() { <-workers }()
+
+			results[i] = r.initializeRegionWithResult(reqLogger, account, region, mode, amiOwner, vCPUQuota, creds, managedTags, customerTags, kmsKeyId)
+		}(i, region.Name)
+	}
+	wg.Wait()
 
 	var regionInitFailedRegion []string
-	regionInitFailed := false
-	// Wait for all go routines to send a message or error to notify that the region initialization has finished
-	for i := 0; i < len(regions); i++ {
-		select {
-		case msg := <-ec2Notifications:
-			reqLogger.Info(msg)
-		case errMsg := <-ec2Errors:
-			regionInitFailed = true
-			// If we fail to initialize the desired region we want to fail the account
-			reqLogger.Error(errors.New(errMsg.ErrorMsg), errMsg.ErrorMsg)
-			regionInitFailedRegion = append(regionInitFailedRegion, errMsg.Region)
+	for _, result := range results {
+		if !result.Initialized {
+			regionInitFailedRegion = append(regionInitFailedRegion, result.Region)
 		}
 	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Region < results[j].Region })
+	account.Status.RegionInitialization = results
+
 	// If an account is BYOC or CCS and region initialization fails for the region expected, we want to fail the account else output success log
-	if regionInitFailed && len(regions) == 1 {
+	if len(regionInitFailedRegion) > 0 && len(regions) == 1 {
 		controllerutils.SetAccountStatus(
 			account,
 			fmt.Sprintf("Account %s failed to initialize expected region %v", account.Name, regionInitFailedRegion),
 			awsv1alpha1.AccountInitializingRegions,
 			AccountFailed,
 		)
+	} else if len(regionInitFailedRegion) > 0 {
+		reqLogger.Info("Completed initializing desired regions, some regions failed", "failedRegions", regionInitFailedRegion)
 	} else {
 		reqLogger.Info("Successfully completed initializing desired regions")
 	}
 }
 
-// InitializeRegion sets up a connection to the AWS `region` and then creates and terminates an EC2 instance if necessary
+// initializeRegionWithResult calls InitializeRegion for a single region and turns the notifications and
+// errors it sends into the result of the region
+func (r *AccountReconciler) initializeRegionWithResult(
+	reqLogger logr.Logger,
+	account *awsv1alpha1.Account,
+	region string,
+	mode string,
+	amiOwner string,
+	vCPUQuota float64,
+	creds *sts.AssumeRoleOutput,
+	managedTags []awsclient.AWSTag,
+	customerTags []awsclient.AWSTag,
+	kmsKeyId string,
+) awsv1alpha1.RegionInitializationStatus {
+	// InitializeRegion may report several errors before giving up on a region, the channels are
+	// buffered so it never blocks on them
+	ec2Notifications, ec2Errors := make(chan string, regionInitMaxMessages), make(chan regionInitializationError, regionInitMaxMessages)
+	start := time.Now()
+	err := r.InitializeRegion(reqLogger, account, region, mode, amiOwner, vCPUQuota, ec2Notifications, ec2Errors, creds, managedTags, customerTags, kmsKeyId)
+	close(ec2Notifications)
+	close(ec2Errors)
+
+	result := awsv1alpha1.RegionInitializationStatus{
+		Region:          region,
+		Initialized:     true,
+		DurationSeconds: int64(time.Since(start).Seconds()),
+	}
+	for errMsg := range ec2Errors {
+		reqLogger.Error(errors.New(errMsg.ErrorMsg), errMsg.ErrorMsg)
+		if result.Initialized {
+			result.Initialized = false
+			result.Message = errMsg.ErrorMsg
+		}
+	}
+	for msg := range ec2Notifications {
+		reqLogger.Info(msg)
+		if result.Initialized {
+			result.Message = msg
+		}
+	}
+	// Some failures are only returned
+	if err != nil && result.Initialized {
+		reqLogger.Error(err, "failed to initialize region", "region", region)
+		result.Initialized = false
+		result.Message = err.Error()
+	}
+	return result
+}
+
+// getRegionInitConcurrency reads how many regions are initialized at the same time from the configmap
+func getRegionInitConcurrency(reqLogger logr.Logger, kubeClient client.Client) int {
+	configMap, err := controllerutils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		reqLogger.Info("Unable to get the region init concurrency from the configmap, using the default", "default", defaultRegionInitConcurrency)
+		return defaultRegionInitConcurrency
+	}
+
+	value, ok := configMap.Data[awsv1alpha1.RegionInitConcurrencyConfigMapKey]
+	if !ok {
+		return defaultRegionInitConcurrency
+	}
+	concurrency, err := strconv.Atoi(value)
+	if err != nil || concurrency < 1 {
+		reqLogger.Error(err, "Invalid region init concurrency in configmap, using the default", "value", value, "default", defaultRegionInitConcurrency)
+		return defaultRegionInitConcurrency
+	}
+	return concurrency
+}
+
+// InitializeRegion sets up a connection to the AWS `region` and then creates and terminates an EC2 instance if necessary.
+// In the api-check mode the region is verified through the EC2 API instead of launching an instance.
 func (r *AccountReconciler) InitializeRegion(
 	reqLogger logr.Logger,
 	account *awsv1alpha1.Account,
 	region string,
+	mode string,
 	amiOwner string,
 	vCPUQuota float64,
 	ec2Notifications chan string,
@@ -114,6 +205,7 @@ func (r *AccountReconciler) InitializeRegion(
 		AwsCredsSecretAccessKey: *creds.Credentials.SecretAccessKey,
 		AwsToken:                *creds.Credentials.SessionToken,
 		AwsRegion:               region,
+		AwsAccountID:            account.Spec.AwsAccountID,
 	})
 
 	if err != nil {
@@ -141,7 +233,9 @@ func (r *AccountReconciler) InitializeRegion(
 		return nil
 	}
 
-	// If in fedramp, create vpc and set sampleVPCID value
+	// If in fedramp, create the vpc the instance is launched in. It's local to the region, which may be
+	// initialized at the same time as other regions and accounts.
+	var sampleVPCID string
 	if config.IsFedramp() {
 		// Attempt to clean the region from any hanging fedramp resources
 		fedrampCleaned, err := cleanFedrampInitializationResources(reqLogger, awsClient, account.Name, region)
@@ -194,7 +288,18 @@ func (r *AccountReconciler) InitializeRegion(
 		InstanceType: instanceType,
 	}
 
-	err = r.BuildAndDestroyEC2Instances(reqLogger, account, awsClient, instanceInfo, managedTags, customerTags, kmsKeyId)
+	if mode == controllerutils.RegionInitModeAPICheck {
+		err = checkRegionCapability(reqLogger, awsClient, region, instanceInfo)
+		if err != nil {
+			controllerutils.LogAwsError(reqLogger, fmt.Sprintf("Region capability check failed in region: %s", region), nil, err)
+			ec2Errors <- regionInitializationError{ErrorMsg: err.Error(), Region: region}
+			return err
+		}
+		ec2Notifications <- fmt.Sprintf("Region capabilities verified through the EC2 API in region: %s", region)
+		return nil
+	}
+
+	err = r.BuildAndDestroyEC2Instances(reqLogger, account, awsClient, instanceInfo, managedTags, customerTags, kmsKeyId, sampleVPCID)
 	if err != nil {
 		createErr := fmt.Sprintf("Unable to create instance in region: %s", region)
 		controllerutils.LogAwsError(reqLogger, createErr, nil, err)
@@ -512,8 +617,9 @@ func (r *AccountReconciler) BuildAndDestroyEC2Instances(
 	instanceInfo awsv1alpha1.AmiSpec,
 	managedTags []awsclient.AWSTag,
 	customerTags []awsclient.AWSTag,
-	kmsKeyId string) error {
-	instanceID, err := CreateEC2Instance(reqLogger, account, awsClient, instanceInfo, managedTags, customerTags, kmsKeyId)
+	kmsKeyId string,
+	vpcID string) error {
+	instanceID, err := CreateEC2Instance(reqLogger, account, awsClient, instanceInfo, managedTags, customerTags, kmsKeyId, vpcID)
 	if err != nil {
 		// Terminate instance id if it exists
 		if instanceID != "" {
@@ -576,8 +682,9 @@ func (r *AccountReconciler) BuildAndDestroyEC2Instances(
 	return nil
 }
 
-// CreateEC2Instance creates ec2 instance and returns its instance ID
-func CreateEC2Instance(reqLogger logr.Logger, account *awsv1alpha1.Account, client awsclient.Client, instanceInfo awsv1alpha1.AmiSpec, managedTags []awsclient.AWSTag, customerTags []awsclient.AWSTag, customerKmsKeyId string) (string, error) {
+// CreateEC2Instance creates ec2 instance and returns its instance ID. In fedramp the instance is launched in a new
+// subnet of vpcID.
+func CreateEC2Instance(reqLogger logr.Logger, account *awsv1alpha1.Account, client awsclient.Client, instanceInfo awsv1alpha1.AmiSpec, managedTags []awsclient.AWSTag, customerTags []awsclient.AWSTag, customerKmsKeyId string, vpcID string) (string, error) {
 
 	// Retain instance id
 	var timeoutInstanceID string
@@ -629,7 +736,7 @@ func CreateEC2Instance(reqLogger logr.Logger, account *awsv1alpha1.Account, clie
 
 		// If fedramp, create subnet and set value for RunInstancesInput
 		if config.IsFedramp() {
-			subnetID, err := createSubnet(reqLogger, client, account, managedTags, customerTags, sampleCIDR, sampleVPCID)
+			subnetID, err := createSubnet(reqLogger, client, account, managedTags, customerTags, sampleCIDR, vpcID)
 			if err != nil {
 				subnetErr := fmt.Sprintf("Error while trying to create subnet: %s", subnetID)
 				controllerutils.LogAwsError(reqLogger, subnetErr, nil, err)
@@ -17,6 +17,7 @@ import (
 	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
 	"github.com/openshift/aws-account-operator/pkg/testutils"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -317,7 +318,7 @@ func TestCreateEC2Instance(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockAWSClient.EXPECT().RunInstances(tt.args.instanceInput).MinTimes(1).MaxTimes(1).Return(tt.args.instanceOutput, tt.args.instanceOutputError)
-			got, err := CreateEC2Instance(tt.args.reqLogger, tt.args.account, tt.args.client, tt.args.instanceInfo, tt.args.managedTags, tt.args.customerTags, tt.args.customerKmsKeyId)
+			got, err := CreateEC2Instance(tt.args.reqLogger, tt.args.account, tt.args.client, tt.args.instanceInfo, tt.args.managedTags, tt.args.customerTags, tt.args.customerKmsKeyId, "")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateEC2Instance() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -437,6 +438,68 @@ func TestReconcileAccount_InitializeSupportedRegions(t *testing.T) {
 	}
 }
 
+func TestReconcileAccount_InitializeSupportedRegionsRecordsRegionResults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAWSBuilder := mock.NewMockIBuilder(ctrl)
+	mockAWSBuilder.EXPECT().GetClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("unreachable")).Times(3)
+
+	r := &AccountReconciler{
+		Client:           fake.NewClientBuilder().Build(),
+		Scheme:           scheme.Scheme,
+		awsClientBuilder: mockAWSBuilder,
+	}
+	account := &awsv1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TestAccountName,
+			Namespace: TestAccountNamespace,
+		},
+	}
+	creds := &sts.AssumeRoleOutput{
+		Credentials: &sts.Credentials{
+			AccessKeyId:     aws.String("123456"),
+			SecretAccessKey: aws.String("123456"),
+			SessionToken:    aws.String("123456"),
+		},
+	}
+	regions := []awsv1alpha1.AwsRegions{{Name: "us-west-2"}, {Name: "eu-west-1"}, {Name: "us-east-1"}}
+
+	r.InitializeSupportedRegions(testutils.NewTestLogger().Logger(), account, regions, creds, "")
+
+	results := account.Status.RegionInitialization
+	assert.Len(t, results, 3)
+	assert.Equal(t, []string{"eu-west-1", "us-east-1", "us-west-2"}, []string{results[0].Region, results[1].Region, results[2].Region})
+	for _, result := range results {
+		assert.False(t, result.Initialized)
+		assert.Contains(t, result.Message, "unable to connect to region")
+	}
+	// Accounts initializing several regions aren't failed by a region failing
+	assert.NotEqual(t, AccountFailed, account.Status.State)
+}
+
+func TestGetRegionInitConcurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string]string
+		expected int
+	}{
+		{"Default when unset", nil, defaultRegionInitConcurrency},
+		{"Configured value", map[string]string{awsv1alpha1.RegionInitConcurrencyConfigMapKey: "3"}, 3},
+		{"Default when invalid", map[string]string{awsv1alpha1.RegionInitConcurrencyConfigMapKey: "0"}, defaultRegionInitConcurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+				Data:       tt.data,
+			}
+			kubeClient := fake.NewClientBuilder().WithObjects(configMap).Build()
+			assert.Equal(t, tt.expected, getRegionInitConcurrency(testutils.NewTestLogger().Logger(), kubeClient))
+		})
+	}
+}
+
 func TestCreateVpc(t *testing.T) {
 	tests := []struct {
 		Name                   string
@@ -0,0 +1,194 @@
+package account
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/s3control"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	s3PublicAccessBlockedReason         = "PublicAccessBlockEnabled"
+	s3PublicAccessBlockFailedReason     = "PublicAccessBlockFailed"
+	ebsEncryptionEnabledReason          = "EncryptionByDefaultEnabled"
+	ebsEncryptionEnablementFailedReason = "EncryptionByDefaultFailed"
+	defaultVPCDeletedReason             = "DefaultVPCDeleted"
+	defaultVPCDeletionFailedReason      = "DefaultVPCDeletionFailed"
+)
+
+// HardenAccount enables the account level S3 Public Access Block, and EBS encryption by default in
+// each of the regions. With deleteDefaultVPC, the default VPC of each region is deleted too. The
+// outcome is recorded in the S3PublicAccessBlocked, EBSEncryptionByDefault and DefaultVPCDeleted
+// conditions; every step is attempted even if an earlier one fails.
+func HardenAccount(reqLogger logr.Logger, kubeClient client.Client, awsClientBuilder awsclient.IBuilder, account *awsv1alpha1.Account, creds *sts.AssumeRoleOutput, regions []awsv1alpha1.AwsRegions, deleteDefaultVPC bool) error {
+	getClient := func(region string) (awsclient.Client, error) {
+		return awsClientBuilder.GetClient(controllerName, kubeClient, awsclient.NewAwsClientInput{
+			AwsCredsSecretIDKey:     *creds.Credentials.AccessKeyId,
+			AwsCredsSecretAccessKey: *creds.Credentials.SecretAccessKey,
+			AwsToken:                *creds.Credentials.SessionToken,
+			AwsRegion:               region,
+			AwsAccountID:            account.Spec.AwsAccountID,
+		})
+	}
+
+	s3Err := blockS3PublicAccess(reqLogger, getClient, account)
+	ebsErr := enableEBSEncryptionByDefault(reqLogger, getClient, account, regions)
+	var vpcErr error
+	if deleteDefaultVPC {
+		vpcErr = deleteDefaultVPCs(reqLogger, getClient, account, regions)
+	}
+	if s3Err != nil {
+		return s3Err
+	}
+	if ebsErr != nil {
+		return ebsErr
+	}
+	return vpcErr
+}
+
+// blockS3PublicAccess enables all settings of the account level S3 Public Access Block
+func blockS3PublicAccess(reqLogger logr.Logger, getClient func(string) (awsclient.Client, error), account *awsv1alpha1.Account) error {
+	awsClient, err := getClient(config.GetDefaultRegion())
+	if err == nil {
+		_, err = awsClient.PutPublicAccessBlock(&s3control.PutPublicAccessBlockInput{
+			AccountId: aws.String(account.Spec.AwsAccountID),
+			PublicAccessBlockConfiguration: &s3control.PublicAccessBlockConfiguration{
+				BlockPublicAcls:       aws.Bool(true),
+				BlockPublicPolicy:     aws.Bool(true),
+				IgnorePublicAcls:      aws.Bool(true),
+				RestrictPublicBuckets: aws.Bool(true),
+			},
+		})
+	}
+
+	status, reason, message := corev1.ConditionTrue, s3PublicAccessBlockedReason, "S3 Public Access Block enabled for the account"
+	if err != nil {
+		reqLogger.Error(err, "Failed to enable S3 Public Access Block")
+		status, reason, message = corev1.ConditionFalse, s3PublicAccessBlockFailedReason, fmt.Sprintf("Failed to enable S3 Public Access Block: %v", err)
+	}
+	account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountS3PublicAccessBlocked, status, reason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+	return err
+}
+
+// enableEBSEncryptionByDefault enables EBS encryption by default in each of the regions
+func enableEBSEncryptionByDefault(reqLogger logr.Logger, getClient func(string) (awsclient.Client, error), account *awsv1alpha1.Account, regions []awsv1alpha1.AwsRegions) error {
+	var failedRegions []string
+	var lastErr error
+	for _, region := range regions {
+		awsClient, err := getClient(region.Name)
+		if err == nil {
+			_, err = awsClient.EnableEbsEncryptionByDefault(&ec2.EnableEbsEncryptionByDefaultInput{})
+		}
+		if err != nil {
+			reqLogger.Error(err, "Failed to enable EBS encryption by default", "region", region.Name)
+			failedRegions = append(failedRegions, region.Name)
+			lastErr = err
+		}
+	}
+
+	status, reason, message := corev1.ConditionTrue, ebsEncryptionEnabledReason, "EBS encryption by default enabled in all initialized regions"
+	if lastErr != nil {
+		status, reason, message = corev1.ConditionFalse, ebsEncryptionEnablementFailedReason, fmt.Sprintf("Failed to enable EBS encryption by default in regions: %s", strings.Join(failedRegions, ", "))
+	}
+	account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountEBSEncryptionByDefault, status, reason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+	if lastErr != nil {
+		return fmt.Errorf("failed to enable EBS encryption by default in regions %v: %w", failedRegions, lastErr)
+	}
+	return nil
+}
+
+// deleteDefaultVPCs deletes the default VPC of each of the regions, so the account exposes no network it doesn't
+// need and the VPCs the installer creates can't conflict with its CIDR. Regions without a default VPC are skipped,
+// and a default VPC recreated by a previous tenant is deleted again on reuse.
+func deleteDefaultVPCs(reqLogger logr.Logger, getClient func(string) (awsclient.Client, error), account *awsv1alpha1.Account, regions []awsv1alpha1.AwsRegions) error {
+	var failedRegions []string
+	var lastErr error
+	for _, region := range regions {
+		awsClient, err := getClient(region.Name)
+		if err == nil {
+			err = deleteDefaultVPC(reqLogger, awsClient, region.Name)
+		}
+		if err != nil {
+			reqLogger.Error(err, "Failed to delete the default VPC", "region", region.Name)
+			failedRegions = append(failedRegions, region.Name)
+			lastErr = err
+		}
+	}
+
+	status, reason, message := corev1.ConditionTrue, defaultVPCDeletedReason, "No default VPC left in any initialized region"
+	if lastErr != nil {
+		status, reason, message = corev1.ConditionFalse, defaultVPCDeletionFailedReason, fmt.Sprintf("Failed to delete the default VPC in regions: %s", strings.Join(failedRegions, ", "))
+	}
+	account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountDefaultVPCDeleted, status, reason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+	if lastErr != nil {
+		return fmt.Errorf("failed to delete the default VPC in regions %v: %w", failedRegions, lastErr)
+	}
+	return nil
+}
+
+// deleteDefaultVPC deletes the default VPC of the region with its network interfaces, security groups, internet
+// gateway and subnets. Its route table, network ACL and default security group go with it.
+func deleteDefaultVPC(reqLogger logr.Logger, awsClient awsclient.Client, region string) error {
+	vpcs, err := awsClient.DescribeVpcs(&ec2.DescribeVpcsInput{
+		Filters: []*ec2.Filter{{Name: aws.String("isDefault"), Values: aws.StringSlice([]string{"true"})}},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, vpc := range vpcs.Vpcs {
+		vpcID := vpc.VpcId
+		reqLogger.Info("Deleting the default VPC", "region", region, "vpcID", aws.StringValue(vpcID))
+
+		err = deleteVPCDependencies(reqLogger, awsClient, vpcID)
+		if err != nil {
+			return err
+		}
+
+		gateways, err := awsClient.DescribeInternetGateways(&ec2.DescribeInternetGatewaysInput{
+			Filters: []*ec2.Filter{{Name: aws.String("attachment.vpc-id"), Values: []*string{vpcID}}},
+		})
+		if err != nil {
+			return err
+		}
+		for _, gateway := range gateways.InternetGateways {
+			_, err = awsClient.DetachInternetGateway(&ec2.DetachInternetGatewayInput{InternetGatewayId: gateway.InternetGatewayId, VpcId: vpcID})
+			if err != nil {
+				return err
+			}
+			_, err = awsClient.DeleteInternetGateway(&ec2.DeleteInternetGatewayInput{InternetGatewayId: gateway.InternetGatewayId})
+			if err != nil {
+				return err
+			}
+		}
+
+		subnets, err := awsClient.DescribeSubnets(&ec2.DescribeSubnetsInput{
+			Filters: []*ec2.Filter{{Name: aws.String("vpc-id"), Values: []*string{vpcID}}},
+		})
+		if err != nil {
+			return err
+		}
+		for _, subnet := range subnets.Subnets {
+			_, err = awsClient.DeleteSubnet(&ec2.DeleteSubnetInput{SubnetId: subnet.SubnetId})
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = awsClient.DeleteVpc(&ec2.DeleteVpcInput{VpcId: vpcID})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
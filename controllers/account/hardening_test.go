@@ -0,0 +1,159 @@
+package account
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/s3control"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Account hardening", func() {
+	var (
+		nullLogger     logr.Logger
+		mockAWSClient  *mock.MockClient
+		mockAWSBuilder *mock.MockIBuilder
+		ctrl           *gomock.Controller
+		account        *awsv1alpha1.Account
+		creds          *sts.AssumeRoleOutput
+		regions        []awsv1alpha1.AwsRegions
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		nullLogger = testutils.NewTestLogger().Logger()
+		mockAWSClient = mock.NewMockClient(ctrl)
+		mockAWSBuilder = mock.NewMockIBuilder(ctrl)
+		account = &newTestAccountBuilder().acct
+		account.Spec.AwsAccountID = "123456789012"
+		creds = &sts.AssumeRoleOutput{
+			Credentials: &sts.Credentials{
+				AccessKeyId:     aws.String("ACCESS_KEY"),
+				SecretAccessKey: aws.String("SECRET_KEY"),
+				SessionToken:    aws.String("SESSION_TOKEN"),
+			},
+		}
+		regions = []awsv1alpha1.AwsRegions{{Name: "us-east-1"}, {Name: "eu-west-1"}}
+		mockAWSBuilder.EXPECT().GetClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(mockAWSClient, nil).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectPublicAccessBlock := func(err error) {
+		mockAWSClient.EXPECT().PutPublicAccessBlock(gomock.Any()).DoAndReturn(func(input *s3control.PutPublicAccessBlockInput) (*s3control.PutPublicAccessBlockOutput, error) {
+			Expect(aws.StringValue(input.AccountId)).To(Equal("123456789012"))
+			Expect(aws.BoolValue(input.PublicAccessBlockConfiguration.BlockPublicPolicy)).To(BeTrue())
+			return &s3control.PutPublicAccessBlockOutput{}, err
+		})
+	}
+
+	It("Enables the S3 Public Access Block and EBS encryption by default in every region", func() {
+		expectPublicAccessBlock(nil)
+		mockAWSClient.EXPECT().EnableEbsEncryptionByDefault(gomock.Any()).Return(&ec2.EnableEbsEncryptionByDefaultOutput{}, nil).Times(len(regions))
+
+		Expect(HardenAccount(nullLogger, nil, mockAWSBuilder, account, creds, regions, false)).To(Succeed())
+		Expect(account.GetCondition(awsv1alpha1.AccountS3PublicAccessBlocked).Status).To(Equal(corev1.ConditionTrue))
+		Expect(account.GetCondition(awsv1alpha1.AccountEBSEncryptionByDefault).Status).To(Equal(corev1.ConditionTrue))
+	})
+
+	It("Doesn't add the conditions when hardening fails", func() {
+		expectPublicAccessBlock(errors.New("AccessDenied"))
+		mockAWSClient.EXPECT().EnableEbsEncryptionByDefault(gomock.Any()).Return(nil, errors.New("UnauthorizedOperation")).Times(len(regions))
+
+		Expect(HardenAccount(nullLogger, nil, mockAWSBuilder, account, creds, regions, false)).NotTo(Succeed())
+		Expect(account.GetCondition(awsv1alpha1.AccountS3PublicAccessBlocked)).To(BeNil())
+		Expect(account.GetCondition(awsv1alpha1.AccountEBSEncryptionByDefault)).To(BeNil())
+	})
+
+	It("Reports the regions where EBS encryption by default couldn't be re-enabled", func() {
+		expectPublicAccessBlock(nil)
+		mockAWSClient.EXPECT().EnableEbsEncryptionByDefault(gomock.Any()).Return(&ec2.EnableEbsEncryptionByDefaultOutput{}, nil).Times(len(regions))
+		Expect(HardenAccount(nullLogger, nil, mockAWSBuilder, account, creds, regions, false)).To(Succeed())
+
+		expectPublicAccessBlock(errors.New("AccessDenied"))
+		gomock.InOrder(
+			mockAWSClient.EXPECT().EnableEbsEncryptionByDefault(gomock.Any()).Return(&ec2.EnableEbsEncryptionByDefaultOutput{}, nil),
+			mockAWSClient.EXPECT().EnableEbsEncryptionByDefault(gomock.Any()).Return(nil, errors.New("UnauthorizedOperation")),
+		)
+		Expect(HardenAccount(nullLogger, nil, mockAWSBuilder, account, creds, regions, false)).NotTo(Succeed())
+
+		Expect(account.GetCondition(awsv1alpha1.AccountS3PublicAccessBlocked).Status).To(Equal(corev1.ConditionFalse))
+		condition := account.GetCondition(awsv1alpha1.AccountEBSEncryptionByDefault)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("eu-west-1"))
+		Expect(condition.Message).NotTo(ContainSubstring("us-east-1"))
+	})
+
+	It("Builds a client for each region with the assumed role credentials", func() {
+		builder := mock.NewMockIBuilder(ctrl)
+		for _, region := range []string{"us-east-1", "us-east-1", "eu-west-1"} {
+			builder.EXPECT().GetClient(controllerName, gomock.Any(), awsclient.NewAwsClientInput{
+				AwsCredsSecretIDKey:     "ACCESS_KEY",
+				AwsCredsSecretAccessKey: "SECRET_KEY",
+				AwsToken:                "SESSION_TOKEN",
+				AwsRegion:               region,
+				AwsAccountID:            "123456789012",
+			}).Return(mockAWSClient, nil)
+		}
+		expectPublicAccessBlock(nil)
+		mockAWSClient.EXPECT().EnableEbsEncryptionByDefault(gomock.Any()).Return(&ec2.EnableEbsEncryptionByDefaultOutput{}, nil).Times(len(regions))
+
+		Expect(HardenAccount(nullLogger, nil, builder, account, creds, regions, false)).To(Succeed())
+	})
+
+	It("Deletes the default VPC of every region with its internet gateway and subnets", func() {
+		expectPublicAccessBlock(nil)
+		mockAWSClient.EXPECT().EnableEbsEncryptionByDefault(gomock.Any()).Return(&ec2.EnableEbsEncryptionByDefaultOutput{}, nil).Times(len(regions))
+		gomock.InOrder(
+			mockAWSClient.EXPECT().DescribeVpcs(gomock.Any()).Return(&ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-1")}}}, nil),
+			mockAWSClient.EXPECT().DescribeVpcs(gomock.Any()).Return(&ec2.DescribeVpcsOutput{}, nil),
+		)
+		mockAWSClient.EXPECT().DescribeNetworkInterfaces(gomock.Any()).Return(&ec2.DescribeNetworkInterfacesOutput{}, nil)
+		mockAWSClient.EXPECT().DescribeSecurityGroups(gomock.Any()).Return(&ec2.DescribeSecurityGroupsOutput{}, nil)
+		mockAWSClient.EXPECT().DescribeInternetGateways(gomock.Any()).Return(&ec2.DescribeInternetGatewaysOutput{
+			InternetGateways: []*ec2.InternetGateway{{InternetGatewayId: aws.String("igw-1")}},
+		}, nil)
+		mockAWSClient.EXPECT().DetachInternetGateway(&ec2.DetachInternetGatewayInput{InternetGatewayId: aws.String("igw-1"), VpcId: aws.String("vpc-1")}).Return(&ec2.DetachInternetGatewayOutput{}, nil)
+		mockAWSClient.EXPECT().DeleteInternetGateway(&ec2.DeleteInternetGatewayInput{InternetGatewayId: aws.String("igw-1")}).Return(&ec2.DeleteInternetGatewayOutput{}, nil)
+		mockAWSClient.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{{SubnetId: aws.String("subnet-1")}, {SubnetId: aws.String("subnet-2")}},
+		}, nil)
+		mockAWSClient.EXPECT().DeleteSubnet(gomock.Any()).Return(&ec2.DeleteSubnetOutput{}, nil).Times(2)
+		mockAWSClient.EXPECT().DeleteVpc(&ec2.DeleteVpcInput{VpcId: aws.String("vpc-1")}).Return(&ec2.DeleteVpcOutput{}, nil)
+
+		Expect(HardenAccount(nullLogger, nil, mockAWSBuilder, account, creds, regions, true)).To(Succeed())
+		Expect(account.GetCondition(awsv1alpha1.AccountDefaultVPCDeleted).Status).To(Equal(corev1.ConditionTrue))
+	})
+
+	It("Reports the regions where the default VPC couldn't be deleted again", func() {
+		account.Status.Conditions = []awsv1alpha1.AccountCondition{{Type: awsv1alpha1.AccountDefaultVPCDeleted, Status: corev1.ConditionTrue}}
+		expectPublicAccessBlock(nil)
+		mockAWSClient.EXPECT().EnableEbsEncryptionByDefault(gomock.Any()).Return(&ec2.EnableEbsEncryptionByDefaultOutput{}, nil).Times(len(regions))
+		gomock.InOrder(
+			mockAWSClient.EXPECT().DescribeVpcs(gomock.Any()).Return(&ec2.DescribeVpcsOutput{}, nil),
+			mockAWSClient.EXPECT().DescribeVpcs(gomock.Any()).Return(&ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-2")}}}, nil),
+		)
+		mockAWSClient.EXPECT().DescribeNetworkInterfaces(gomock.Any()).Return(&ec2.DescribeNetworkInterfacesOutput{}, nil)
+		mockAWSClient.EXPECT().DescribeSecurityGroups(gomock.Any()).Return(&ec2.DescribeSecurityGroupsOutput{}, nil)
+		mockAWSClient.EXPECT().DescribeInternetGateways(gomock.Any()).Return(&ec2.DescribeInternetGatewaysOutput{}, nil)
+		mockAWSClient.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{}, nil)
+		mockAWSClient.EXPECT().DeleteVpc(gomock.Any()).Return(nil, errors.New("DependencyViolation"))
+
+		Expect(HardenAccount(nullLogger, nil, mockAWSBuilder, account, creds, regions, true)).NotTo(Succeed())
+		condition := account.GetCondition(awsv1alpha1.AccountDefaultVPCDeleted)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("eu-west-1"))
+	})
+})
@@ -0,0 +1,289 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// hibernationCheckInterval is how often the accounts are checked for hibernation. Whether an account is
+	// hibernated, and when, is configured per AccountPool.
+	hibernationCheckInterval = time.Hour
+
+	hibernatedReason        = "Hibernated"
+	hibernationFailedReason = "HibernationFailed"
+	hibernationEndedReason  = "Claimed"
+	accountHibernator       = "account-hibernator"
+)
+
+// AccountHibernator periodically hibernates the accounts that stayed Ready and unclaimed for longer than
+// the hibernateAfter of their AccountPool. Hibernating an account confirms it runs no instance, deletes its
+// default VPC and releases its unassociated Elastic IPs, in each of its initialized regions, so the idle pool
+// costs less without closing the accounts.
+type AccountHibernator struct {
+	client           client.Client
+	awsClientBuilder awsclient.IBuilder
+	recorder         record.EventRecorder
+}
+
+// NewAccountHibernator returns a new AccountHibernator
+func NewAccountHibernator(client client.Client, awsClientBuilder awsclient.IBuilder, recorder record.EventRecorder) *AccountHibernator {
+	return &AccountHibernator{
+		client:           client,
+		awsClientBuilder: awsClientBuilder,
+		recorder:         recorder,
+	}
+}
+
+// Start hibernates idle accounts every hibernationCheckInterval, until the operator is stopped
+func (h *AccountHibernator) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the account hibernator")
+	for {
+		h.HibernateAccounts(log)
+
+		select {
+		case <-time.After(hibernationCheckInterval):
+		case <-stopCh.Done():
+			log.Info("Stopping the account hibernator")
+			return
+		}
+	}
+}
+
+// HibernateAccounts hibernates the idle accounts of the pools configuring hibernation, and ends the
+// hibernation of the accounts that have been claimed. Failing to hibernate one account doesn't stop the
+// others from being hibernated.
+func (h *AccountHibernator) HibernateAccounts(log logr.Logger) {
+	accountList := &awsv1alpha1.AccountList{}
+	err := h.client.List(context.TODO(), accountList, client.InNamespace(awsv1alpha1.AccountCrNamespace))
+	if err != nil {
+		log.Error(err, "Failed to list accounts")
+		return
+	}
+
+	hibernateAfter := map[string]time.Duration{}
+	var awsSetupClient awsclient.Client
+	for i := range accountList.Items {
+		account := &accountList.Items[i]
+		// The accounts of other shards are hibernated by other replicas
+		if !utils.InShard(account.Name) {
+			continue
+		}
+		reqLogger := log.WithValues("account", account.Name, "awsAccountID", account.Spec.AwsAccountID)
+
+		if account.IsClaimed() || account.HasClaimLink() {
+			if isHibernated(account) || account.IsHibernating() {
+				h.endHibernation(reqLogger, account)
+			}
+			continue
+		}
+
+		delay, ok := hibernateAfter[account.Spec.AccountPool]
+		if !ok {
+			delay = utils.GetHibernateAfterFromAccountPool(reqLogger, account.Spec.AccountPool, h.client)
+			hibernateAfter[account.Spec.AccountPool] = delay
+		}
+		if !ShouldHibernate(account, delay) {
+			continue
+		}
+		if utils.IsAWSMutationPaused(h.client) {
+			reqLogger.Info("AWS changes are paused, not hibernating the account")
+			continue
+		}
+
+		if awsSetupClient == nil {
+			awsSetupClient, err = h.awsClientBuilder.GetClient(accountHibernator, h.client, awsclient.NewAwsClientInput{
+				SecretName: utils.AwsSecretName,
+				NameSpace:  awsv1alpha1.AccountCrNamespace,
+				AwsRegion:  config.GetDefaultRegion(),
+			})
+			if err != nil {
+				log.Error(err, "Failed building operator AWS client")
+				return
+			}
+		}
+		h.hibernateAccount(reqLogger, awsSetupClient, account)
+	}
+}
+
+// ShouldHibernate returns true for the non-CCS accounts that have been Ready and unclaimed for at least
+// hibernateAfter and aren't hibernated yet. A hibernateAfter of 0 disables hibernation.
+func ShouldHibernate(account *awsv1alpha1.Account, hibernateAfter time.Duration) bool {
+	if hibernateAfter <= 0 {
+		return false
+	}
+	if !account.IsReady() ||
+		account.IsClaimed() ||
+		account.HasClaimLink() ||
+		!account.HasAwsAccountID() ||
+		account.IsBYOC() ||
+		account.IsPendingDeletion() ||
+		isHibernated(account) {
+		return false
+	}
+	transition := account.Status.StateTransition
+	return transition != nil && transition.State == string(awsv1alpha1.AccountReady) && transition.TimeInState() >= hibernateAfter
+}
+
+// isHibernated returns true if the account was hibernated since it last became Ready. Accounts going back
+// to the pool after being claimed are hibernated again.
+func isHibernated(account *awsv1alpha1.Account) bool {
+	cond := account.GetCondition(awsv1alpha1.AccountHibernated)
+	if cond == nil || cond.Status != corev1.ConditionTrue {
+		return false
+	}
+	transition := account.Status.StateTransition
+	return transition == nil || !cond.LastTransitionTime.Before(&transition.LastTransitionTime)
+}
+
+// hibernationRegions returns the regions whose resources are removed when the account is hibernated: the
+// default region and the regions that were initialized
+func hibernationRegions(account *awsv1alpha1.Account) []string {
+	regions := []string{config.GetDefaultRegion()}
+	for _, region := range account.Status.RegionInitialization {
+		if region.Initialized && region.Region != config.GetDefaultRegion() {
+			regions = append(regions, region.Region)
+		}
+	}
+	return regions
+}
+
+// hibernateAccount removes the billable resources of each region of the account and records the outcome in
+// the Hibernated condition. The account is marked Hibernating first, so it isn't handed out to claims meanwhile,
+// and the hibernation stops if it's claimed anyway.
+func (h *AccountHibernator) hibernateAccount(reqLogger logr.Logger, awsSetupClient awsclient.Client, account *awsv1alpha1.Account) {
+	err := utils.UpdateStatusWithRetry(h.client, account, func() error {
+		// The account may have been claimed since it was listed
+		if account.IsClaimed() || account.HasClaimLink() {
+			return awsv1alpha1.ErrAccountClaimed
+		}
+		account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountHibernated, corev1.ConditionFalse, awsv1alpha1.AccountHibernatingReason, "Removing the billable resources of the account", utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to mark the account as hibernating")
+		return
+	}
+
+	regions := hibernationRegions(account)
+	_, creds, err := stsclient.HandleRoleAssumption(reqLogger, h.awsClientBuilder, account, h.client, awsSetupClient, config.GetDefaultRegion(), awsv1alpha1.AccountOperatorIAMRole, "")
+	if err != nil {
+		err = fmt.Errorf("unable to assume role into account: %w", err)
+	}
+	for i := 0; err == nil && i < len(regions); i++ {
+		region := regions[i]
+		// Stop as soon as the account is claimed
+		err = h.client.Get(context.TODO(), client.ObjectKeyFromObject(account), account)
+		if err != nil {
+			break
+		}
+		if account.IsClaimed() || account.HasClaimLink() {
+			reqLogger.Info("Account claimed during its hibernation, stopping")
+			h.endHibernation(reqLogger, account)
+			return
+		}
+
+		var awsClient awsclient.Client
+		awsClient, err = h.awsClientBuilder.GetClient(accountHibernator, h.client, awsclient.NewAwsClientInput{
+			AwsCredsSecretIDKey:     *creds.Credentials.AccessKeyId,
+			AwsCredsSecretAccessKey: *creds.Credentials.SecretAccessKey,
+			AwsToken:                *creds.Credentials.SessionToken,
+			AwsRegion:               region,
+			AwsAccountID:            account.Spec.AwsAccountID,
+		})
+		if err != nil {
+			break
+		}
+		err = HibernateRegion(reqLogger.WithValues("region", region), awsClient, region)
+		if err != nil {
+			err = fmt.Errorf("failed to hibernate region %s: %w", region, err)
+		}
+	}
+
+	status, reason, message := corev1.ConditionTrue, hibernatedReason, fmt.Sprintf("No instance, default VPC or unassociated Elastic IP left in regions: %s", strings.Join(regions, ", "))
+	if err != nil {
+		reqLogger.Error(err, "Failed to hibernate account")
+		status, reason, message = corev1.ConditionFalse, hibernationFailedReason, err.Error()
+	}
+	err = utils.UpdateStatusWithRetry(h.client, account, func() error {
+		account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountHibernated, status, reason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to update account status")
+		return
+	}
+	if status == corev1.ConditionTrue {
+		reqLogger.Info("Hibernated account")
+		utils.RecordEvent(h.recorder, account, corev1.EventTypeNormal, utils.EventReasonAccountHibernated, "%s", message)
+	}
+}
+
+// endHibernation clears the Hibernated condition of an account that has been claimed
+func (h *AccountHibernator) endHibernation(reqLogger logr.Logger, account *awsv1alpha1.Account) {
+	err := utils.UpdateStatusWithRetry(h.client, account, func() error {
+		account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountHibernated, corev1.ConditionFalse, hibernationEndedReason, "The account has been claimed", utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to update account status")
+	}
+}
+
+// HibernateRegion confirms the region runs no instance, then deletes its default VPC and releases its unassociated
+// Elastic IPs. The instances are left alone: a region running any isn't hibernated.
+func HibernateRegion(reqLogger logr.Logger, awsClient awsclient.Client, region string) error {
+	instances, err := awsClient.DescribeInstances(&ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("instance-state-name"),
+			Values: aws.StringSlice([]string{"pending", "running", "shutting-down", "stopping", "stopped"}),
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	var instanceIDs []string
+	for _, reservation := range instances.Reservations {
+		for _, instance := range reservation.Instances {
+			instanceIDs = append(instanceIDs, aws.StringValue(instance.InstanceId))
+		}
+	}
+	if len(instanceIDs) > 0 {
+		return fmt.Errorf("instances found: %s", strings.Join(instanceIDs, ", "))
+	}
+
+	err = deleteDefaultVPC(reqLogger, awsClient, region)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := awsClient.DescribeAddresses(&ec2.DescribeAddressesInput{})
+	if err != nil {
+		return err
+	}
+	for _, address := range addresses.Addresses {
+		if address.AssociationId != nil {
+			continue
+		}
+		reqLogger.Info("Releasing Elastic IP", "publicIP", aws.StringValue(address.PublicIp))
+		_, err = awsClient.ReleaseAddress(&ec2.ReleaseAddressInput{AllocationId: address.AllocationId})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
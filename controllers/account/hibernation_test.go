@@ -0,0 +1,122 @@
+package account
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Account hibernation", func() {
+	var (
+		nullLogger    logr.Logger
+		mockAWSClient *mock.MockClient
+		ctrl          *gomock.Controller
+		account       *awsv1alpha1.Account
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		nullLogger = testutils.NewTestLogger().Logger()
+		mockAWSClient = mock.NewMockClient(ctrl)
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osd-creds-mgmt-abcdef",
+				Namespace: awsv1alpha1.AccountCrNamespace,
+			},
+			Spec: awsv1alpha1.AccountSpec{AwsAccountID: "111111111111"},
+			Status: awsv1alpha1.AccountStatus{
+				State: string(awsv1alpha1.AccountReady),
+				StateTransition: &awsv1alpha1.StateTransition{
+					State:              string(awsv1alpha1.AccountReady),
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-48 * time.Hour)),
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("ShouldHibernate", func() {
+		It("Hibernates accounts idle for longer than hibernateAfter", func() {
+			Expect(ShouldHibernate(account, 24*time.Hour)).To(BeTrue())
+		})
+
+		It("Doesn't hibernate accounts when hibernation is disabled or they're not idle long enough", func() {
+			Expect(ShouldHibernate(account, 0)).To(BeFalse())
+			Expect(ShouldHibernate(account, 72*time.Hour)).To(BeFalse())
+		})
+
+		It("Doesn't hibernate claimed or CCS accounts", func() {
+			account.Status.Claimed = true
+			Expect(ShouldHibernate(account, 24*time.Hour)).To(BeFalse())
+			account.Status.Claimed = false
+			account.Spec.BYOC = true
+			Expect(ShouldHibernate(account, 24*time.Hour)).To(BeFalse())
+		})
+
+		It("Hibernates accounts once per stay in the pool", func() {
+			account.Status.Conditions = []awsv1alpha1.AccountCondition{{
+				Type:               awsv1alpha1.AccountHibernated,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-24 * time.Hour)),
+			}}
+			Expect(ShouldHibernate(account, 24*time.Hour)).To(BeFalse())
+
+			// Hibernated before the account went back to the pool
+			account.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-72 * time.Hour))
+			Expect(ShouldHibernate(account, 24*time.Hour)).To(BeTrue())
+		})
+	})
+
+	Context("HibernateRegion", func() {
+		expectInstances := func(instances []*ec2.Instance) {
+			mockAWSClient.EXPECT().DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{
+				Reservations: []*ec2.Reservation{{Instances: instances}},
+			}, nil)
+		}
+
+		It("Deletes the default VPC and releases the unassociated Elastic IPs of a region without instances", func() {
+			expectInstances(nil)
+			mockAWSClient.EXPECT().DescribeVpcs(gomock.Any()).Return(&ec2.DescribeVpcsOutput{}, nil)
+			mockAWSClient.EXPECT().DescribeAddresses(gomock.Any()).Return(&ec2.DescribeAddressesOutput{Addresses: []*ec2.Address{
+				{AllocationId: aws.String("eipalloc-free"), PublicIp: aws.String("192.0.2.1")},
+				{AllocationId: aws.String("eipalloc-used"), PublicIp: aws.String("192.0.2.2"), AssociationId: aws.String("eipassoc-used")},
+			}}, nil)
+			mockAWSClient.EXPECT().ReleaseAddress(&ec2.ReleaseAddressInput{AllocationId: aws.String("eipalloc-free")}).Return(&ec2.ReleaseAddressOutput{}, nil)
+
+			Expect(HibernateRegion(nullLogger, mockAWSClient, "us-east-1")).To(Succeed())
+		})
+
+		It("Leaves a region running instances alone", func() {
+			expectInstances([]*ec2.Instance{{InstanceId: aws.String("i-running"), State: &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameRunning)}}})
+			mockAWSClient.EXPECT().TerminateInstances(gomock.Any()).Times(0)
+			mockAWSClient.EXPECT().DescribeVpcs(gomock.Any()).Times(0)
+
+			Expect(HibernateRegion(nullLogger, mockAWSClient, "us-east-1")).To(MatchError(ContainSubstring("i-running")))
+		})
+	})
+
+	Context("IsHibernating", func() {
+		It("Reports accounts whose resources are being removed", func() {
+			Expect(account.IsHibernating()).To(BeFalse())
+			account.Status.Conditions = []awsv1alpha1.AccountCondition{{
+				Type:   awsv1alpha1.AccountHibernated,
+				Status: corev1.ConditionFalse,
+				Reason: awsv1alpha1.AccountHibernatingReason,
+			}}
+			Expect(account.IsHibernating()).To(BeTrue())
+		})
+	})
+})
@@ -2,7 +2,9 @@ package account
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -15,10 +17,13 @@ import (
 	"github.com/openshift/aws-account-operator/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/openshift/aws-account-operator/pkg/awsclient"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 
 	retry "github.com/avast/retry-go"
 )
@@ -29,6 +34,7 @@ type awsStatement struct {
 	Action    []string               `json:"Action"`
 	Resource  []string               `json:"Resource,omitempty"`
 	Principal *awsv1alpha1.Principal `json:"Principal,omitempty"`
+	Condition *awsv1alpha1.Condition `json:"Condition,omitempty"`
 }
 
 // PolicyDocument represents JSON object of an AWS Policy Document
@@ -64,8 +70,10 @@ func (r *AccountReconciler) CreateSecret(reqLogger logr.Logger, account *awsv1al
 	createErr := r.Client.Create(context.TODO(), secret)
 	if createErr != nil {
 		failedToCreateUserSecretMsg := fmt.Sprintf("Failed to create secret %s", secret.Name)
-		utils.SetAccountStatus(account, failedToCreateUserSecretMsg, awsv1alpha1.AccountFailed, "Failed")
-		err := r.Client.Status().Update(context.TODO(), account)
+		err := utils.UpdateStatusWithRetry(r.Client, account, func() error {
+			utils.SetAccountStatus(account, failedToCreateUserSecretMsg, awsv1alpha1.AccountFailed, "Failed")
+			return nil
+		})
 		if err != nil {
 			return err
 		}
@@ -94,6 +102,15 @@ func retryIfAwsServiceFailureOrInvalidToken(err error) bool {
 	return false
 }
 
+// retryIfIAMUserNotPropagated retries the errors of retryIfAwsServiceFailureOrInvalidToken, and NoSuchEntity, which
+// IAM returns for a while after a user is created or recreated
+func retryIfIAMUserNotPropagated(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+		return true
+	}
+	return retryIfAwsServiceFailureOrInvalidToken(err)
+}
+
 func listAccessKeys(client awsclient.Client, iamUser *iam.User) (*iam.ListAccessKeysOutput, error) {
 	var result *iam.ListAccessKeysOutput
 	var err error
@@ -108,8 +125,12 @@ func listAccessKeys(client awsclient.Client, iamUser *iam.User) (*iam.ListAccess
 			return err
 		},
 
-		// Retry if we receive some specific errors: access denied, rate limit or server-side error
-		retry.RetryIf(retryIfAwsServiceFailureOrInvalidToken),
+		// Retry if we receive some specific errors: access denied, rate limit, server-side error or a user that
+		// hasn't propagated yet
+		retry.RetryIf(retryIfIAMUserNotPropagated),
+
+		// Return the last error rather than all of them, so callers can tell a user that's gone
+		retry.LastErrorOnly(true),
 	)
 
 	return result, err
@@ -216,15 +237,21 @@ func CreateIAMUser(reqLogger logr.Logger, client awsclient.Client, userName stri
 // AttachAdminUserPolicy attaches the AdministratorAccess policy to a target user
 // Takes a logger, an AWS client for the target account, and the target IAM user's username
 func AttachAdminUserPolicy(client awsclient.Client, iamUser *iam.User) (*iam.AttachUserPolicyOutput, error) {
+	return attachUserPolicy(client, iamUser, config.GetIAMArn("aws", config.AwsResourceTypePolicy, config.AwsResourceIDAdministratorAccessRole))
+}
+
+// attachUserPolicy attaches a managed policy to a target user, retrying while the new user propagates. The other
+// failures, e.g. a policy that doesn't exist, are returned right away.
+func attachUserPolicy(client awsclient.Client, iamUser *iam.User, policyArn string) (*iam.AttachUserPolicyOutput, error) {
 	attachPolicyOutput := &iam.AttachUserPolicyOutput{}
 	var err error
 	for i := 0; i < 100; i++ {
 		time.Sleep(defaultSleepDelay)
 		attachPolicyOutput, err = client.AttachUserPolicy(&iam.AttachUserPolicyInput{
 			UserName:  iamUser.UserName,
-			PolicyArn: aws.String(config.GetIAMArn("aws", config.AwsResourceTypePolicy, config.AwsResourceIDAdministratorAccessRole)),
+			PolicyArn: aws.String(policyArn),
 		})
-		if err == nil {
+		if err == nil || !attachUserPolicyRetryable(err, policyArn) {
 			break
 		}
 	}
@@ -235,6 +262,160 @@ func AttachAdminUserPolicy(client awsclient.Client, iamUser *iam.User) (*iam.Att
 	return attachPolicyOutput, nil
 }
 
+// attachUserPolicyRetryable returns true for the AttachUserPolicy failures that go away on their own: throttling, and
+// the new user or the credentials of a new account not being visible everywhere yet
+func attachUserPolicyRetryable(err error, policyArn string) bool {
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) {
+		return false
+	}
+	switch aerr.Code() {
+	case iam.ErrCodeNoSuchEntityException:
+		// The policy not existing doesn't go away
+		return !strings.Contains(aerr.Message(), policyArn)
+	case "AccessDenied", "InvalidClientTokenId":
+		return true
+	}
+	return awsv1alpha1.GetAWSErrorCategory(err) == awsv1alpha1.AWSErrorThrottled
+}
+
+// ReconcileIAMUserPolicies attaches the policies configured for the account's AccountPool to its IAM user and
+// removes any policies that aren't part of that set. Accounts whose pool doesn't configure a policy set keep the
+// AdministratorAccess policy.
+func ReconcileIAMUserPolicies(reqLogger logr.Logger, kubeClient client.Client, awsClient awsclient.Client, account *awsv1alpha1.Account, iamUser *iam.User) error {
+	userName := aws.StringValue(iamUser.UserName)
+
+	policies, err := utils.GetIAMUserPoliciesFromAccountPool(reqLogger, account.Spec.AccountPool, kubeClient)
+	if err != nil {
+		return err
+	}
+	if policies == nil {
+		reqLogger.Info(fmt.Sprintf("Attaching Admin Policy to IAM user %s", userName))
+		_, err = AttachAdminUserPolicy(awsClient, iamUser)
+		return err
+	}
+
+	desiredManagedPolicies := map[string]bool{}
+	for _, policyArn := range policies.ManagedPolicyARNs {
+		desiredManagedPolicies[policyArn] = true
+		reqLogger.Info(fmt.Sprintf("Attaching policy %s to IAM user %s", policyArn, userName))
+		_, err = attachUserPolicy(awsClient, iamUser, policyArn)
+		if err != nil {
+			return err
+		}
+	}
+
+	attachedPolicies, err := awsClient.ListAttachedUserPolicies(&iam.ListAttachedUserPoliciesInput{UserName: iamUser.UserName})
+	if err != nil {
+		return err
+	}
+	for _, attachedPolicy := range attachedPolicies.AttachedPolicies {
+		if desiredManagedPolicies[aws.StringValue(attachedPolicy.PolicyArn)] {
+			continue
+		}
+		reqLogger.Info(fmt.Sprintf("Detaching policy %s from IAM user %s", aws.StringValue(attachedPolicy.PolicyArn), userName))
+		_, err = awsClient.DetachUserPolicy(&iam.DetachUserPolicyInput{
+			UserName:  iamUser.UserName,
+			PolicyArn: attachedPolicy.PolicyArn,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	policyNames := make([]string, 0, len(policies.InlinePolicies))
+	for policyName := range policies.InlinePolicies {
+		policyNames = append(policyNames, policyName)
+	}
+	sort.Strings(policyNames)
+	for _, policyName := range policyNames {
+		reqLogger.Info(fmt.Sprintf("Putting inline policy %s on IAM user %s", policyName, userName))
+		_, err = awsClient.PutUserPolicy(&iam.PutUserPolicyInput{
+			UserName:       iamUser.UserName,
+			PolicyName:     aws.String(policyName),
+			PolicyDocument: aws.String(policies.InlinePolicies[policyName]),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	inlinePolicies, err := awsClient.ListUserPolicies(&iam.ListUserPoliciesInput{UserName: iamUser.UserName})
+	if err != nil {
+		return err
+	}
+	for _, policyName := range inlinePolicies.PolicyNames {
+		if _, ok := policies.InlinePolicies[aws.StringValue(policyName)]; ok {
+			continue
+		}
+		reqLogger.Info(fmt.Sprintf("Deleting inline policy %s from IAM user %s", aws.StringValue(policyName), userName))
+		_, err = awsClient.DeleteUserPolicy(&iam.DeleteUserPolicyInput{
+			UserName:   iamUser.UserName,
+			PolicyName: policyName,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EnsureIAMUserPolicies re-applies the AccountPool's policy set to the account's osdManagedAdmin user, if it exists
+func EnsureIAMUserPolicies(reqLogger logr.Logger, kubeClient client.Client, awsClient awsclient.Client, account *awsv1alpha1.Account) error {
+	iamUserName := account.GetIAMUserName()
+	iamUserExists, iamUserExistsOutput, err := awsclient.CheckIAMUserExists(reqLogger, awsClient, iamUserName)
+	if err != nil {
+		return err
+	}
+	if !iamUserExists {
+		reqLogger.Info(fmt.Sprintf("IAM user %s doesn't exist, not reconciling its policies", iamUserName))
+		return nil
+	}
+	return ReconcileIAMUserPolicies(reqLogger, kubeClient, awsClient, account, iamUserExistsOutput.User)
+}
+
+// EnsureIAMUser recreates the osdManagedAdmin user of an account when it was removed out-of-band, so the account
+// isn't handed out with the credentials of a user that's gone. Accounts without an IAM user secret never had a user.
+// Returns true if the user was recreated.
+func EnsureIAMUser(reqLogger logr.Logger, kubeClient client.Client, awsClient awsclient.Client, recorder record.EventRecorder, account *awsv1alpha1.Account) (bool, error) {
+	if account.Spec.IAMUserSecret == "" {
+		return false, nil
+	}
+	iamUserName := account.GetIAMUserName()
+	iamUserExists, _, err := awsclient.CheckIAMUserExists(reqLogger, awsClient, iamUserName)
+	if err != nil || iamUserExists {
+		return false, err
+	}
+
+	accountSecret := &corev1.Secret{}
+	err = kubeClient.Get(context.TODO(), types.NamespacedName{Name: account.Spec.IAMUserSecret, Namespace: account.Namespace}, accountSecret)
+	if err != nil {
+		return false, err
+	}
+	_, err = RecreateIAMUser(reqLogger, kubeClient, awsClient, recorder, account, accountSecret, iamUserName)
+	return err == nil, err
+}
+
+// RecreateIAMUser builds a missing IAM user of the account again, with the tags and policies of a new account, and
+// puts a new access key in the account's secret. The access keys of the previous user are gone with it.
+func RecreateIAMUser(reqLogger logr.Logger, kubeClient client.Client, awsClient awsclient.Client, recorder record.EventRecorder, account *awsv1alpha1.Account, accountSecret *corev1.Secret, iamUserName string) (*iam.AccessKey, error) {
+	reqLogger.Info(fmt.Sprintf("IAM user %s doesn't exist, recreating it", iamUserName))
+	r := &AccountReconciler{Client: kubeClient, Scheme: kubeClient.Scheme(), recorder: recorder}
+	// No access key is created as the secret of the account exists
+	_, err := r.BuildIAMUser(reqLogger, awsClient, account, iamUserName, accountSecret.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	newKey, err := putNewAccessKey(reqLogger, kubeClient, awsClient, accountSecret, aws.String(iamUserName))
+	if err != nil {
+		return nil, err
+	}
+	utils.RecordEvent(recorder, account, corev1.EventTypeNormal, utils.EventReasonIAMUserRecreated, "Recreated missing IAM user %s", iamUserName)
+	return newKey, nil
+}
+
 func attachAndEnsureRolePolicies(reqLogger logr.Logger, client awsclient.Client, roleName string, policyArn string) error {
 	reqLogger.Info(fmt.Sprintf("Attaching policy %s to role %s", policyArn, roleName))
 	// Attach the specified policy to the Role
@@ -288,8 +469,12 @@ func CreateUserAccessKey(client awsclient.Client, iamUser *iam.User) (*iam.Creat
 			return err
 		},
 
-		// Retry if we receive some specific errors: access denied, rate limit or server-side error
-		retry.RetryIf(retryIfAwsServiceFailureOrInvalidToken),
+		// Retry if we receive some specific errors: access denied, rate limit, server-side error or a user that
+		// hasn't propagated yet
+		retry.RetryIf(retryIfIAMUserNotPropagated),
+
+		// Return the last error rather than all of them, so callers can tell a user that's gone
+		retry.LastErrorOnly(true),
 	)
 
 	if err != nil {
@@ -319,6 +504,17 @@ func (r *AccountReconciler) BuildIAMUser(reqLogger logr.Logger, awsClient awscli
 	if iamUserExists {
 		// If user exists extract iam.User pointer
 		createdIAMUser = iamUserExistsOutput.User
+
+		// Users created before a permissions boundary was configured don't have it yet
+		permissionsBoundary, err := utils.GetPermissionsBoundaryARN(r.Client)
+		if err != nil {
+			return nil, err
+		}
+		err = awsclient.EnsureUserPermissionsBoundary(reqLogger, awsClient, createdIAMUser, permissionsBoundary)
+		if err != nil {
+			reqLogger.Error(err, fmt.Sprintf("Failed to set permissions boundary on IAM user %s", iamUserName))
+			return nil, err
+		}
 	} else {
 		CreateUserOutput, err := awsclient.CreateIAMUser(reqLogger, awsClient, account, iamUserName, managedTags, customTags)
 		// Err is handled within the function and returns a error message
@@ -332,12 +528,10 @@ func (r *AccountReconciler) BuildIAMUser(reqLogger logr.Logger, awsClient awscli
 
 	iamUserSecretName = createIAMUserSecretName(account.Name)
 
-	reqLogger.Info(fmt.Sprintf("Attaching Admin Policy to IAM user %s", aws.StringValue(createdIAMUser.UserName)))
-
-	// Setting IAM user policy
-	_, err = AttachAdminUserPolicy(awsClient, createdIAMUser)
+	// Setting IAM user policies
+	err = ReconcileIAMUserPolicies(reqLogger, r.Client, awsClient, account, createdIAMUser)
 	if err != nil {
-		errMsg := fmt.Sprintf("Failed to attach admin policy to IAM user %s", aws.StringValue(createdIAMUser.UserName))
+		errMsg := fmt.Sprintf("Failed to attach policies to IAM user %s", aws.StringValue(createdIAMUser.UserName))
 		reqLogger.Error(err, errMsg)
 		return nil, err
 	}
@@ -367,6 +561,7 @@ func (r *AccountReconciler) BuildIAMUser(reqLogger logr.Logger, awsClient awscli
 			reqLogger.Error(err, errMsg)
 			return nil, err
 		}
+		utils.RecordEvent(r.recorder, account, corev1.EventTypeNormal, utils.EventReasonCredentialsRotated, "Rotated access keys for IAM user %s into secret %s", aws.StringValue(createdIAMUser.UserName), secretNamespacedName.Name)
 	}
 
 	// Return secret name
@@ -388,6 +583,80 @@ func CleanUpIAM(reqLogger logr.Logger, awsClient awsclient.Client, accountCR *aw
 	return nil
 }
 
+// CleanUpCCSAccount removes exactly the IAM users, roles and secrets recorded in the OperatorResources of a CCS
+// account. Anything else in the account belongs to the customer and is never touched. Accounts created before the
+// resources were recorded fall back to the tag based IAM cleanup.
+func CleanUpCCSAccount(reqLogger logr.Logger, kubeClient client.Client, awsClient awsclient.Client, accountCR *awsv1alpha1.Account) error {
+	resources := accountCR.Status.OperatorResources
+	if resources.IsEmpty() {
+		reqLogger.Info("No operator resources recorded for CCS account, cleaning up tagged IAM resources")
+		return CleanUpIAM(reqLogger, awsClient, accountCR)
+	}
+
+	for _, userName := range resources.IAMUsers {
+		getUser, err := awsClient.GetUser(&iam.GetUserInput{UserName: aws.String(userName)})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+				reqLogger.Info(fmt.Sprintf("IAM user %s is already gone", userName))
+				continue
+			}
+			return fmt.Errorf("failed to get IAM user %s: %v", userName, err)
+		}
+		if err = deleteUserInlinePolicies(awsClient, getUser.User); err != nil {
+			return err
+		}
+		reqLogger.Info(fmt.Sprintf("Deleting IAM user: %s", userName))
+		if err = deleteIAMUser(reqLogger, awsClient, getUser.User); err != nil {
+			return err
+		}
+	}
+
+	for _, roleName := range resources.IAMRoles {
+		getRole, err := awsClient.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+				reqLogger.Info(fmt.Sprintf("IAM role %s is already gone", roleName))
+				continue
+			}
+			return fmt.Errorf("failed to get IAM role %s: %v", roleName, err)
+		}
+		if err = cleanIAMRole(reqLogger, awsClient, getRole.Role); err != nil {
+			return err
+		}
+	}
+
+	for _, secret := range resources.Secrets {
+		namespace, name, found := strings.Cut(secret, "/")
+		if !found {
+			reqLogger.Info(fmt.Sprintf("Ignoring malformed secret reference: %s", secret))
+			continue
+		}
+		reqLogger.Info(fmt.Sprintf("Deleting secret: %s", secret))
+		err := kubeClient.Delete(context.TODO(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}})
+		if err != nil && !k8serr.IsNotFound(err) {
+			return fmt.Errorf("failed to delete secret %s: %v", secret, err)
+		}
+	}
+
+	return nil
+}
+
+// deleteUserInlinePolicies deletes the inline policies of the user, which have to be gone before the user can be
+// deleted
+func deleteUserInlinePolicies(awsClient awsclient.Client, user *iam.User) error {
+	inlinePolicies, err := awsClient.ListUserPolicies(&iam.ListUserPoliciesInput{UserName: user.UserName})
+	if err != nil {
+		return fmt.Errorf("unable to list inline policies of IAM user %s: %v", *user.UserName, err)
+	}
+	for _, policyName := range inlinePolicies.PolicyNames {
+		_, err = awsClient.DeleteUserPolicy(&iam.DeleteUserPolicyInput{UserName: user.UserName, PolicyName: policyName})
+		if err != nil {
+			return fmt.Errorf("unable to delete inline policy %s of IAM user %s: %v", *policyName, *user.UserName, err)
+		}
+	}
+	return nil
+}
+
 func deleteIAMUser(reqLogger logr.Logger, awsClient awsclient.Client, user *iam.User) error {
 	var err error
 	// Detach User Policies
@@ -434,22 +703,12 @@ func DeleteIAMUsers(reqLogger logr.Logger, awsClient awsclient.Client, accountCR
 	}
 
 	for _, user := range users {
-		clusterNameTag := false
-		clusterNamespaceTag := false
 		getUser, err := awsClient.GetUser(&iam.GetUserInput{UserName: user.UserName})
 		if err != nil {
 			return fmt.Errorf("failed to get aws user: %v", err)
 		}
 		user = getUser.User
-		for _, tag := range user.Tags {
-			if *tag.Key == awsv1alpha1.ClusterAccountNameTagKey && *tag.Value == accountCR.Name {
-				clusterNameTag = true
-			}
-			if *tag.Key == awsv1alpha1.ClusterNamespaceTagKey && *tag.Value == accountCR.Namespace {
-				clusterNamespaceTag = true
-			}
-		}
-		if clusterNameTag && clusterNamespaceTag {
+		if iamResourceBelongsToAccount(user.Tags, accountCR) {
 			err = deleteIAMUser(reqLogger, awsClient, user)
 			if err != nil {
 				return err
@@ -461,6 +720,72 @@ func DeleteIAMUsers(reqLogger logr.Logger, awsClient awsclient.Client, accountCR
 	return nil
 }
 
+// DeleteClaimIAMUsers deletes the IAM users tagged with the UID of the claim, created for it while it held the
+// account. The osdManagedAdmin users of the account itself are left in place.
+func DeleteClaimIAMUsers(reqLogger logr.Logger, awsClient awsclient.Client, claimUID string) error {
+	if claimUID == "" {
+		return nil
+	}
+
+	users, err := listIAMUsers(reqLogger, awsClient)
+	if err != nil {
+		return fmt.Errorf("failed to list aws iam users: %v", err)
+	}
+
+	for _, user := range users {
+		if strings.HasPrefix(aws.StringValue(user.UserName), awsv1alpha1.IAMUserNamePrefix) {
+			continue
+		}
+		getUser, err := awsClient.GetUser(&iam.GetUserInput{UserName: user.UserName})
+		if err != nil {
+			return fmt.Errorf("failed to get IAM user %s: %v", aws.StringValue(user.UserName), err)
+		}
+		if !userTaggedWithClaim(getUser.User.Tags, claimUID) {
+			continue
+		}
+		if err = deleteUserInlinePolicies(awsClient, getUser.User); err != nil {
+			return err
+		}
+		reqLogger.Info(fmt.Sprintf("Deleting IAM user of the claim: %s", aws.StringValue(user.UserName)))
+		if err = deleteIAMUser(reqLogger, awsClient, getUser.User); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// userTaggedWithClaim returns true if the tags of an IAM user hold the UID of the claim
+func userTaggedWithClaim(tags []*iam.Tag, claimUID string) bool {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == awsv1alpha1.ClaimUIDTagKey && aws.StringValue(tag.Value) == claimUID {
+			return true
+		}
+	}
+	return false
+}
+
+// iamResourceBelongsToAccount checks the tags of an IAM user or role to tell whether it was created
+// for the account. The claim UID is the primary key when both the account and the resource have one,
+// so resources aren't orphaned when the claim's namespace is renamed. Otherwise the account name and
+// namespace tags have to match.
+func iamResourceBelongsToAccount(tags []*iam.Tag, accountCR *awsv1alpha1.Account) bool {
+	clusterNameTag := false
+	clusterNamespaceTag := false
+	for _, tag := range tags {
+		switch aws.StringValue(tag.Key) {
+		case awsv1alpha1.ClaimUIDTagKey:
+			if accountCR.Spec.ClaimLinkUID != "" {
+				return aws.StringValue(tag.Value) == accountCR.Spec.ClaimLinkUID
+			}
+		case awsv1alpha1.ClusterAccountNameTagKey:
+			clusterNameTag = aws.StringValue(tag.Value) == accountCR.Name
+		case awsv1alpha1.ClusterNamespaceTagKey:
+			clusterNamespaceTag = aws.StringValue(tag.Value) == accountCR.Namespace
+		}
+	}
+	return clusterNameTag && clusterNamespaceTag
+}
+
 func cleanIAMRole(reqLogger logr.Logger, awsClient awsclient.Client, role *iam.Role) error {
 	// remove attached policies from the role before deletion
 	if err := detachRolePolicies(awsClient, *role.RoleName); err != nil {
@@ -484,24 +809,13 @@ func cleanIAMRoles(reqLogger logr.Logger, awsClient awsclient.Client, accountCR
 	}
 
 	for _, role := range roles {
-		clusterNameTag := false
-		clusterNamespaceTag := false
 		getRole, err := awsClient.GetRole(&iam.GetRoleInput{RoleName: role.RoleName})
 		if err != nil {
 			return err
 		}
 		role = getRole.Role
 
-		for _, tag := range role.Tags {
-			if *tag.Key == awsv1alpha1.ClusterAccountNameTagKey && *tag.Value == accountCR.Name {
-				clusterNameTag = true
-			}
-			if *tag.Key == awsv1alpha1.ClusterNamespaceTagKey && *tag.Value == accountCR.Namespace {
-				clusterNamespaceTag = true
-			}
-		}
-
-		if clusterNameTag && clusterNamespaceTag {
+		if iamResourceBelongsToAccount(role.Tags, accountCR) {
 			err = cleanIAMRole(reqLogger, awsClient, role)
 			if err != nil {
 				return err
@@ -584,7 +898,7 @@ func (r *AccountReconciler) createIAMUserSecret(reqLogger logr.Logger, account *
 	iamUserSecret := CreateSecret(secretName.Name, secretName.Namespace, userSecretData)
 
 	// Set controller as owner of secret
-	if err := controllerutil.SetControllerReference(account, iamUserSecret, r.Scheme); err != nil {
+	if err := utils.SetSecretOwner(iamUserSecret, account, r.Scheme); err != nil {
 		return err
 	}
 
@@ -644,6 +958,14 @@ func (r *AccountReconciler) createManagedOpenShiftSupportRole(reqLogger logr.Log
 		reqLogger.Info(fmt.Sprintf("Found pre-existing role: %s", managedSupRoleWithID))
 		reqLogger.Info("Verifying role policies are correct")
 		roleID = *existingRole.Role.RoleId
+		permissionsBoundary, err := utils.GetPermissionsBoundaryARN(r.Client)
+		if err != nil {
+			return roleID, err
+		}
+		err = awsclient.EnsureRolePermissionsBoundary(reqLogger, client, existingRole.Role, permissionsBoundary)
+		if err != nil {
+			return roleID, err
+		}
 		// existingRole is not empty
 		policyList, err := GetAttachedPolicies(reqLogger, managedSupRoleWithID, client)
 		if err != nil {
@@ -0,0 +1,272 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultIAMDriftCheckInterval is how often Ready accounts are audited unless the configmap says
+	// otherwise. An interval of 0 disables the audit.
+	defaultIAMDriftCheckInterval = 6 * time.Hour
+
+	iamDriftReason    = "IAMDrift"
+	iamInSyncReason   = "IAMInSync"
+	iamDriftWatcher   = "iam-drift-watcher"
+	secretAccessKeyID = "aws_access_key_id" // #nosec G101 -- This is a false positive
+)
+
+// IAMDriftWatcher periodically audits the IAM principals of Ready accounts, claimed or pooled,
+// repairs the drift it can and flags the rest with the Degraded condition
+type IAMDriftWatcher struct {
+	client           client.Client
+	awsClientBuilder awsclient.IBuilder
+	recorder         record.EventRecorder
+}
+
+// NewIAMDriftWatcher returns a new IAMDriftWatcher
+func NewIAMDriftWatcher(client client.Client, awsClientBuilder awsclient.IBuilder, recorder record.EventRecorder) *IAMDriftWatcher {
+	return &IAMDriftWatcher{
+		client:           client,
+		awsClientBuilder: awsClientBuilder,
+		recorder:         recorder,
+	}
+}
+
+// Start audits accounts every interval configured in the configmap, until the operator is stopped
+func (w *IAMDriftWatcher) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the IAM drift watcher")
+	for {
+		interval := getIAMDriftCheckInterval(log, w.client)
+		if interval == 0 {
+			// Check again later whether the audit has been enabled
+			interval = defaultIAMDriftCheckInterval
+		} else {
+			w.AuditAccounts(log)
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-stopCh.Done():
+			log.Info("Stopping the IAM drift watcher")
+			return
+		}
+	}
+}
+
+// getIAMDriftCheckInterval reads the audit interval from the configmap, falling back to the default
+// when it's missing or invalid
+func getIAMDriftCheckInterval(log logr.Logger, kubeClient client.Client) time.Duration {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		log.Error(err, "Unable to get the IAM drift check interval from the configmap, using the default")
+		return defaultIAMDriftCheckInterval
+	}
+
+	value, ok := configMap.Data[awsv1alpha1.IAMDriftCheckIntervalConfigMapKey]
+	if !ok || value == "" {
+		return defaultIAMDriftCheckInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Error(err, "Invalid IAM drift check interval in configmap, using the default", "value", value)
+		return defaultIAMDriftCheckInterval
+	}
+	return interval
+}
+
+// AuditAccounts audits every Ready non-CCS account. Failing to audit one account doesn't stop the
+// others from being audited.
+func (w *IAMDriftWatcher) AuditAccounts(log logr.Logger) {
+	forEachManagedAccount(log, w.client, w.awsClientBuilder, iamDriftWatcher, func(reqLogger logr.Logger, awsClient awsclient.Client, account *awsv1alpha1.Account) {
+		problems, err := AuditAccountIAM(reqLogger, w.client, awsClient, account)
+		if err != nil {
+			reqLogger.Error(err, "Failed to audit account IAM")
+			return
+		}
+
+		// Root access keys can be created at any time by whoever holds the root credentials
+		rootAccessKeysChanged, err := CheckRootAccessKeys(reqLogger, awsClient, account)
+		if err != nil {
+			reqLogger.Error(err, "Failed to check root access keys")
+			return
+		}
+		if cond := account.GetCondition(awsv1alpha1.AccountRootAccessKeysPresent); rootAccessKeysChanged && cond.Status == corev1.ConditionTrue {
+			utils.RecordEvent(w.recorder, account, corev1.EventTypeWarning, utils.EventReasonRootAccessKeys, "%s", cond.Message)
+		}
+
+		degradedChanged := w.setDegradedCondition(reqLogger, account, problems)
+		if !rootAccessKeysChanged && !degradedChanged {
+			return
+		}
+		err = utils.UpdateAccountConditionsWithRetry(w.client, account, awsv1alpha1.AccountRootAccessKeysPresent, awsv1alpha1.AccountDegraded)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update account status")
+		}
+	})
+}
+
+// forEachManagedAccount calls fn with a client for each Ready account whose IAM principals the
+// operator manages, assuming the operator role into the account
+func forEachManagedAccount(log logr.Logger, kubeClient client.Client, awsClientBuilder awsclient.IBuilder, controllerName string, fn func(logr.Logger, awsclient.Client, *awsv1alpha1.Account)) {
+	accountList := &awsv1alpha1.AccountList{}
+	err := kubeClient.List(context.TODO(), accountList, client.InNamespace(awsv1alpha1.AccountCrNamespace))
+	if err != nil {
+		log.Error(err, "Failed to list accounts")
+		return
+	}
+
+	awsSetupClient, err := awsClientBuilder.GetClient(controllerName, kubeClient, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		log.Error(err, "Failed building operator AWS client")
+		return
+	}
+
+	for i := range accountList.Items {
+		account := &accountList.Items[i]
+		if !shouldAuditIAM(account) || !utils.InShard(account.Name) {
+			continue
+		}
+
+		reqLogger := log.WithValues("account", account.Name, "awsAccountID", account.Spec.AwsAccountID)
+		awsClient, _, err := stsclient.HandleRoleAssumption(reqLogger, awsClientBuilder, account, kubeClient, awsSetupClient, config.GetDefaultRegion(), awsv1alpha1.AccountOperatorIAMRole, "")
+		if err != nil {
+			reqLogger.Error(err, "Unable to assume role into account")
+			continue
+		}
+		fn(reqLogger, awsClient, account)
+	}
+}
+
+// shouldAuditIAM returns true for the accounts whose IAM principals the operator manages. CCS
+// accounts belong to the customer and STS accounts have no IAM user.
+func shouldAuditIAM(account *awsv1alpha1.Account) bool {
+	return account.IsReady() &&
+		account.HasAwsAccountID() &&
+		!account.IsBYOC() &&
+		!account.IsSTS() &&
+		!account.IsPendingDeletion()
+}
+
+// AuditAccountIAM verifies the osdManagedAdmin user and SRE access role of the account. Drift in
+// policies, permissions boundaries and the SRE access role is repaired. The problems that can't be
+// repaired without breaking whoever uses the account, a missing user or unexpected access keys, are
+// returned for the account to be flagged as Degraded.
+func AuditAccountIAM(reqLogger logr.Logger, kubeClient client.Client, awsClient awsclient.Client, account *awsv1alpha1.Account) ([]string, error) {
+	var problems []string
+
+	iamUserName := account.GetIAMUserName()
+	iamUserExists, iamUserExistsOutput, err := awsclient.CheckIAMUserExists(reqLogger, awsClient, iamUserName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !iamUserExists {
+		problems = append(problems, fmt.Sprintf("IAM user %s doesn't exist", iamUserName))
+	} else {
+		iamUser := iamUserExistsOutput.User
+
+		err = ReconcileIAMUserPolicies(reqLogger, kubeClient, awsClient, account, iamUser)
+		if err != nil {
+			return nil, err
+		}
+
+		permissionsBoundary, err := utils.GetPermissionsBoundaryARN(kubeClient)
+		if err != nil {
+			return nil, err
+		}
+		err = awsclient.EnsureUserPermissionsBoundary(reqLogger, awsClient, iamUser, permissionsBoundary)
+		if err != nil {
+			return nil, err
+		}
+
+		extraKeys, err := findUnexpectedAccessKeys(kubeClient, awsClient, account, iamUser.UserName)
+		if err != nil {
+			return nil, err
+		}
+		if len(extraKeys) > 0 {
+			problems = append(problems, fmt.Sprintf("IAM user %s has unexpected access keys %s", iamUserName, strings.Join(extraKeys, ", ")))
+		}
+	}
+
+	tags := awsclient.AWSTags.BuildTags(account, nil, nil).GetIAMTags()
+	err = EnsureSREAccessRole(reqLogger, kubeClient, awsClient, account, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+// findUnexpectedAccessKeys returns the IDs of the access keys of the IAM user other than the one
+// stored in the account's secret
+func findUnexpectedAccessKeys(kubeClient client.Client, awsClient awsclient.Client, account *awsv1alpha1.Account, userName *string) ([]string, error) {
+	secret := &corev1.Secret{}
+	err := kubeClient.Get(context.TODO(), types.NamespacedName{Name: createIAMUserSecretName(account.Name), Namespace: account.Namespace}, secret)
+	if err != nil {
+		return nil, err
+	}
+	expectedKeyID := string(secret.Data[secretAccessKeyID])
+
+	accessKeys, err := listAccessKeys(awsClient, &iam.User{UserName: userName})
+	if err != nil {
+		return nil, err
+	}
+
+	var extraKeys []string
+	for _, accessKey := range accessKeys.AccessKeyMetadata {
+		if aws.StringValue(accessKey.AccessKeyId) != expectedKeyID {
+			extraKeys = append(extraKeys, aws.StringValue(accessKey.AccessKeyId))
+		}
+	}
+	return extraKeys, nil
+}
+
+// setDegradedCondition sets the Degraded condition when drift was found that couldn't be repaired,
+// and clears it once the account is back in sync. The condition is left alone while AWS Health reports
+// problems for the account, the account health watcher owns it then. Returns true if the condition changed.
+func (w *IAMDriftWatcher) setDegradedCondition(reqLogger logr.Logger, account *awsv1alpha1.Account, problems []string) bool {
+	if account.HasAWSHealthIssues() {
+		return false
+	}
+	existing := account.GetCondition(awsv1alpha1.AccountDegraded)
+	degraded := existing != nil && existing.Status == corev1.ConditionTrue
+
+	if len(problems) == 0 {
+		if !degraded {
+			return false
+		}
+		reqLogger.Info("Account IAM is back in sync")
+		account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountDegraded, corev1.ConditionFalse, iamInSyncReason, "IAM principals match the expected configuration", utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+		utils.RecordEvent(w.recorder, account, corev1.EventTypeNormal, utils.EventReasonIAMDriftResolved, "IAM principals match the expected configuration")
+		return true
+	}
+
+	message := strings.Join(problems, "; ")
+	if degraded && existing.Message == message {
+		return false
+	}
+	reqLogger.Info("Account IAM has drifted", "problems", message)
+	account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountDegraded, corev1.ConditionTrue, iamDriftReason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+	utils.RecordEvent(w.recorder, account, corev1.EventTypeWarning, utils.EventReasonIAMDriftDetected, "%s", message)
+	return true
+}
@@ -0,0 +1,136 @@
+package account
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("IAM drift audit", func() {
+	var (
+		nullLogger    logr.Logger
+		mockAWSClient *mock.MockClient
+		ctrl          *gomock.Controller
+		configMap     *corev1.ConfigMap
+		secret        *corev1.Secret
+		account       *awsv1alpha1.Account
+		userName      string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		nullLogger = testutils.NewTestLogger().Logger()
+		mockAWSClient = mock.NewMockClient(ctrl)
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      awsv1alpha1.DefaultConfigMap,
+				Namespace: awsv1alpha1.AccountCrNamespace,
+			},
+			Data: map[string]string{},
+		}
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osd-creds-mgmt-abcdef",
+				Namespace: awsv1alpha1.AccountCrNamespace,
+				Labels:    map[string]string{awsv1alpha1.IAMUserIDLabel: "abcdef"},
+			},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osd-creds-mgmt-abcdef-secret",
+				Namespace: awsv1alpha1.AccountCrNamespace,
+			},
+			Data: map[string][]byte{
+				"aws_access_key_id": []byte("AKIAEXPECTED"),
+			},
+		}
+		userName = "osdManagedAdmin-abcdef"
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	audit := func() ([]string, error) {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(configMap, secret).Build()
+		return AuditAccountIAM(nullLogger, kubeClient, mockAWSClient, account)
+	}
+
+	expectUser := func(accessKeyIDs ...string) {
+		mockAWSClient.EXPECT().GetUser(&iam.GetUserInput{UserName: aws.String(userName)}).Return(&iam.GetUserOutput{
+			User: &iam.User{UserName: aws.String(userName)},
+		}, nil)
+		mockAWSClient.EXPECT().AttachUserPolicy(gomock.Any()).Return(&iam.AttachUserPolicyOutput{}, nil)
+
+		accessKeys := []*iam.AccessKeyMetadata{}
+		for _, accessKeyID := range accessKeyIDs {
+			accessKeys = append(accessKeys, &iam.AccessKeyMetadata{AccessKeyId: aws.String(accessKeyID)})
+		}
+		mockAWSClient.EXPECT().ListAccessKeys(&iam.ListAccessKeysInput{UserName: aws.String(userName)}).Return(&iam.ListAccessKeysOutput{
+			AccessKeyMetadata: accessKeys,
+		}, nil)
+	}
+
+	It("Finds no problems when the IAM user matches the expected configuration", func() {
+		expectUser("AKIAEXPECTED")
+		Expect(audit()).To(BeEmpty())
+	})
+
+	It("Reports access keys that aren't stored in the account secret", func() {
+		expectUser("AKIAEXPECTED", "AKIAUNEXPECTED")
+		problems, err := audit()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(ConsistOf(ContainSubstring("AKIAUNEXPECTED")))
+	})
+
+	It("Reports a missing IAM user", func() {
+		mockAWSClient.EXPECT().GetUser(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "User does not exist", nil))
+		problems, err := audit()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(ConsistOf(ContainSubstring(userName)))
+	})
+
+	It("Repairs a missing permissions boundary", func() {
+		configMap.Data[awsv1alpha1.PermissionsBoundaryConfigMapKey] = "arn:aws:iam::aws:policy/PowerUserAccess"
+		mockAWSClient.EXPECT().PutUserPermissionsBoundary(&iam.PutUserPermissionsBoundaryInput{
+			UserName:            aws.String(userName),
+			PermissionsBoundary: aws.String("arn:aws:iam::aws:policy/PowerUserAccess"),
+		}).Return(&iam.PutUserPermissionsBoundaryOutput{}, nil)
+		expectUser("AKIAEXPECTED")
+		Expect(audit()).To(BeEmpty())
+	})
+
+	Context("Check interval", func() {
+		interval := func() time.Duration {
+			kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(configMap).Build()
+			return getIAMDriftCheckInterval(nullLogger, kubeClient)
+		}
+
+		It("Defaults when not configured", func() {
+			Expect(interval()).To(Equal(defaultIAMDriftCheckInterval))
+		})
+
+		It("Uses the configured interval", func() {
+			configMap.Data[awsv1alpha1.IAMDriftCheckIntervalConfigMapKey] = "30m"
+			Expect(interval()).To(Equal(30 * time.Minute))
+		})
+
+		It("Defaults when the configured interval is invalid", func() {
+			configMap.Data[awsv1alpha1.IAMDriftCheckIntervalConfigMapKey] = "often"
+			Expect(interval()).To(Equal(defaultIAMDriftCheckInterval))
+		})
+	})
+})
@@ -18,6 +18,8 @@ import (
 	"github.com/openshift/aws-account-operator/pkg/testutils"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -144,6 +146,17 @@ func TestListAccessKeys(t *testing.T) {
 	returnValue, err = listAccessKeys(mockAWSClient, &user)
 	assert.Nil(t, returnValue)
 	assert.Error(t, err, returnErr)
+
+	// A recreated user that hasn't propagated yet is waited for
+	mockAWSClient = mock.NewMockClient(mocks.mockCtrl)
+	gomock.InOrder(
+		mockAWSClient.EXPECT().ListAccessKeys(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil)).Times(2),
+		mockAWSClient.EXPECT().ListAccessKeys(gomock.Any()).Return(&iam.ListAccessKeysOutput{}, nil),
+	)
+
+	returnValue, err = listAccessKeys(mockAWSClient, &user)
+	assert.Nil(t, err)
+	assert.Empty(t, returnValue.AccessKeyMetadata)
 }
 
 func TestDeleteAccessKey(t *testing.T) {
@@ -338,6 +351,25 @@ func TestAttachAdminUserPolicy(t *testing.T) {
 	attachAdminUserPolicy, err = AttachAdminUserPolicy(mockAWSClient, &user)
 	assert.Equal(t, attachAdminUserPolicy, &iam.AttachUserPolicyOutput{})
 	assert.Equal(t, err, expectedError)
+
+	// Testing a failure retrying doesn't fix, returns the error right away.
+	policyArn := "arn:aws:iam::aws:policy/AdministratorAccess"
+	expectedError = awserr.New(iam.ErrCodeNoSuchEntityException, "Policy "+policyArn+" does not exist or is not attachable.", nil)
+	mockAWSClient.EXPECT().AttachUserPolicy(gomock.Any()).Return(
+		&iam.AttachUserPolicyOutput{},
+		expectedError,
+	).Times(1)
+
+	_, err = attachUserPolicy(mockAWSClient, &user, policyArn)
+	assert.Equal(t, err, expectedError)
+
+	// Testing throttling, retries until the policy is attached.
+	gomock.InOrder(
+		mockAWSClient.EXPECT().AttachUserPolicy(gomock.Any()).Return(nil, awserr.New("Throttling", "Rate exceeded", nil)).Times(2),
+		mockAWSClient.EXPECT().AttachUserPolicy(gomock.Any()).Return(&iam.AttachUserPolicyOutput{}, nil),
+	)
+	_, err = attachUserPolicy(mockAWSClient, &user, policyArn)
+	assert.Nil(t, err)
 }
 
 func TestAttachAndEnsureRolePolicies(t *testing.T) {
@@ -425,6 +457,12 @@ func TestBuildIAMUser(t *testing.T) {
 				"two": []byte("world"),
 			},
 		),
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      v1alpha1.DefaultConfigMap,
+				Namespace: v1alpha1.AccountCrNamespace,
+			},
+		},
 	}
 	mocks := setupDefaultMocks(t, localObjects)
 
@@ -455,6 +493,64 @@ func TestBuildIAMUser(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestReconcileIAMUserPolicies(t *testing.T) {
+	username := "osdManagedAdmin-abcdef"
+	powerUserArn := "arn:aws:iam::aws:policy/PowerUserAccess"
+	adminArn := strings.Join([]string{standardAdminAccessArnPrefix, adminAccessArnSuffix}, "")
+
+	mocks := setupDefaultMocks(t, []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      v1alpha1.DefaultConfigMap,
+				Namespace: v1alpha1.AccountCrNamespace,
+			},
+			Data: map[string]string{
+				"accountpool": `fm-accountpool:
+  iamUserPolicies:
+    managedPolicyArns:
+    - ` + powerUserArn + `
+    inlinePolicies:
+      osd-iam: '{"Version":"2012-10-17"}'
+`,
+			},
+		},
+	})
+
+	mockAWSClient := mock.NewMockClient(mocks.mockCtrl)
+	mockAWSClient.EXPECT().AttachUserPolicy(&iam.AttachUserPolicyInput{
+		UserName:  &username,
+		PolicyArn: aws.String(powerUserArn),
+	}).Return(&iam.AttachUserPolicyOutput{}, nil)
+	mockAWSClient.EXPECT().ListAttachedUserPolicies(gomock.Any()).Return(&iam.ListAttachedUserPoliciesOutput{
+		AttachedPolicies: []*iam.AttachedPolicy{
+			{PolicyArn: aws.String(powerUserArn)},
+			{PolicyArn: aws.String(adminArn)},
+		},
+	}, nil)
+	mockAWSClient.EXPECT().DetachUserPolicy(&iam.DetachUserPolicyInput{
+		UserName:  &username,
+		PolicyArn: aws.String(adminArn),
+	}).Return(&iam.DetachUserPolicyOutput{}, nil)
+	mockAWSClient.EXPECT().PutUserPolicy(&iam.PutUserPolicyInput{
+		UserName:       &username,
+		PolicyName:     aws.String("osd-iam"),
+		PolicyDocument: aws.String(`{"Version":"2012-10-17"}`),
+	}).Return(&iam.PutUserPolicyOutput{}, nil)
+	mockAWSClient.EXPECT().ListUserPolicies(gomock.Any()).Return(&iam.ListUserPoliciesOutput{
+		PolicyNames: []*string{aws.String("osd-iam"), aws.String("stale")},
+	}, nil)
+	mockAWSClient.EXPECT().DeleteUserPolicy(&iam.DeleteUserPolicyInput{
+		UserName:   &username,
+		PolicyName: aws.String("stale"),
+	}).Return(&iam.DeleteUserPolicyOutput{}, nil)
+
+	nullLogger := testutils.NewTestLogger().Logger()
+	account := newTestAccountBuilder().acct
+	account.Spec.AccountPool = "fm-accountpool"
+	err := ReconcileIAMUserPolicies(nullLogger, mocks.fakeKubeClient, mockAWSClient, &account, &iam.User{UserName: &username})
+	assert.Nil(t, err)
+}
+
 func TestDeleteIAMUser(t *testing.T) {
 	nullLogger := testutils.NewTestLogger().Logger()
 	mocks := setupDefaultMocks(t, []runtime.Object{})
@@ -534,6 +630,45 @@ func TestDeleteIAMUsers(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestDeleteClaimIAMUsers(t *testing.T) {
+	nullLogger := testutils.NewTestLogger().Logger()
+	mocks := setupDefaultMocks(t, []runtime.Object{})
+	defer mocks.mockCtrl.Finish()
+
+	mockAWSClient := mock.NewMockClient(mocks.mockCtrl)
+	claimUser := aws.String("claim-user")
+	otherUser := aws.String("other-user")
+	adminUser := aws.String(v1alpha1.IAMUserNamePrefix + "-abcdef")
+	claimUIDTag := func(uid string) []*iam.Tag {
+		return []*iam.Tag{{Key: aws.String(v1alpha1.ClaimUIDTagKey), Value: aws.String(uid)}}
+	}
+
+	// The osdManagedAdmin user of the account isn't even looked at
+	mockAWSClient.EXPECT().GetUser(&iam.GetUserInput{UserName: claimUser}).Return(
+		&iam.GetUserOutput{User: &iam.User{UserName: claimUser, Tags: claimUIDTag("claim-uid")}}, nil,
+	)
+	mockAWSClient.EXPECT().GetUser(&iam.GetUserInput{UserName: otherUser}).Return(
+		&iam.GetUserOutput{User: &iam.User{UserName: otherUser, Tags: claimUIDTag("other-claim-uid")}}, nil,
+	)
+	mockAWSClient.EXPECT().ListUserPolicies(gomock.Any()).Return(&iam.ListUserPoliciesOutput{}, nil)
+	mockAWSClient.EXPECT().ListAttachedUserPolicies(gomock.Any()).Return(&iam.ListAttachedUserPoliciesOutput{}, nil)
+	mockAWSClient.EXPECT().ListAccessKeys(gomock.Any()).Return(&iam.ListAccessKeysOutput{}, nil)
+	mockAWSClient.EXPECT().DeleteUser(&iam.DeleteUserInput{UserName: claimUser}).Return(nil, nil)
+
+	old := listIAMUsers
+	listIAMUsers = func(reqLogger logr.Logger, client awsclient.Client) ([]*iam.User, error) {
+		return []*iam.User{{UserName: adminUser}, {UserName: claimUser}, {UserName: otherUser}}, nil
+	}
+	defer func() { listIAMUsers = old }()
+
+	err := DeleteClaimIAMUsers(nullLogger, mockAWSClient, "claim-uid")
+	assert.Nil(t, err)
+
+	// Nothing is deleted without a claim
+	err = DeleteClaimIAMUsers(nullLogger, mockAWSClient, "")
+	assert.Nil(t, err)
+}
+
 func getValidTags(account *v1alpha1.Account) []*iam.Tag {
 	return []*iam.Tag{
 		// These tags are required to enter the deletion block
@@ -548,6 +683,76 @@ func getValidTags(account *v1alpha1.Account) []*iam.Tag {
 	}
 }
 
+func TestIAMResourceBelongsToAccount(t *testing.T) {
+	account := newTestAccountBuilder().acct
+	claimedAccount := newTestAccountBuilder().acct
+	claimedAccount.Spec.ClaimLinkUID = "claim-uid"
+	claimUIDTag := func(uid string) *iam.Tag {
+		return &iam.Tag{Key: aws.String(v1alpha1.ClaimUIDTagKey), Value: aws.String(uid)}
+	}
+
+	tests := []struct {
+		name          string
+		account       *v1alpha1.Account
+		tags          []*iam.Tag
+		expectedValue bool
+	}{
+		{
+			name:          "TestMatchingNameAndNamespace",
+			account:       &account,
+			tags:          getValidTags(&account),
+			expectedValue: true,
+		},
+		{
+			name:          "TestNoTags",
+			account:       &account,
+			tags:          []*iam.Tag{},
+			expectedValue: false,
+		},
+		{
+			name:    "TestOtherNamespace",
+			account: &account,
+			tags: []*iam.Tag{
+				{Key: aws.String(v1alpha1.ClusterAccountNameTagKey), Value: aws.String(account.Name)},
+				{Key: aws.String(v1alpha1.ClusterNamespaceTagKey), Value: aws.String("renamed")},
+			},
+			expectedValue: false,
+		},
+		{
+			name:    "TestMatchingClaimUIDInOtherNamespace",
+			account: &claimedAccount,
+			tags: []*iam.Tag{
+				{Key: aws.String(v1alpha1.ClusterAccountNameTagKey), Value: aws.String(account.Name)},
+				{Key: aws.String(v1alpha1.ClusterNamespaceTagKey), Value: aws.String("renamed")},
+				claimUIDTag("claim-uid"),
+			},
+			expectedValue: true,
+		},
+		{
+			name:          "TestOtherClaimUID",
+			account:       &claimedAccount,
+			tags:          append(getValidTags(&claimedAccount), claimUIDTag("other-claim-uid")),
+			expectedValue: false,
+		},
+		{
+			name:          "TestClaimUIDOnUnclaimedAccount",
+			account:       &account,
+			tags:          append(getValidTags(&account), claimUIDTag("claim-uid")),
+			expectedValue: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			value := iamResourceBelongsToAccount(tt.tags, tt.account)
+			if value != tt.expectedValue {
+				t.Errorf("[TestIAMResourceBelongsToAccount()] Got %v, wanted %v", value, tt.expectedValue)
+			}
+		})
+	}
+}
+
 func TestCleanIAMRoles(t *testing.T) {
 	mocks := setupDefaultMocks(t, []runtime.Object{})
 
@@ -619,6 +824,50 @@ func TestCleanIAMRoles(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestCleanUpCCSAccount(t *testing.T) {
+	err := apis.AddToScheme(scheme.Scheme)
+	if err != nil {
+		fmt.Printf("failed adding to scheme in iam_test.go")
+	}
+
+	account := newTestAccountBuilder().BYOC(true).acct
+	account.Status.OperatorResources = &v1alpha1.OperatorResources{
+		IAMUsers: []string{"osdManagedAdmin-abcdef"},
+		IAMRoles: []string{"ManagedOpenShift-Support-abcdef"},
+		Secrets:  []string{TestAccountNamespace + "/iam-user-secret"},
+	}
+	secret := CreateSecret("iam-user-secret", TestAccountNamespace, map[string][]byte{})
+	mocks := setupDefaultMocks(t, []runtime.Object{secret})
+	mockAWSClient := mock.NewMockClient(mocks.mockCtrl)
+	defer mocks.mockCtrl.Finish()
+
+	// Only the recorded resources are looked up, nothing is listed
+	userName := aws.String("osdManagedAdmin-abcdef")
+	mockAWSClient.EXPECT().GetUser(&iam.GetUserInput{UserName: userName}).Return(
+		&iam.GetUserOutput{User: &iam.User{UserName: userName}}, nil,
+	)
+	mockAWSClient.EXPECT().ListUserPolicies(gomock.Any()).Return(
+		&iam.ListUserPoliciesOutput{PolicyNames: []*string{aws.String("inline")}}, nil,
+	)
+	mockAWSClient.EXPECT().DeleteUserPolicy(&iam.DeleteUserPolicyInput{UserName: userName, PolicyName: aws.String("inline")}).Return(nil, nil)
+	mockAWSClient.EXPECT().ListAttachedUserPolicies(gomock.Any()).Return(&iam.ListAttachedUserPoliciesOutput{}, nil)
+	mockAWSClient.EXPECT().ListAccessKeys(gomock.Any()).Return(&iam.ListAccessKeysOutput{}, nil)
+	mockAWSClient.EXPECT().DeleteUser(&iam.DeleteUserInput{UserName: userName}).Return(nil, nil)
+
+	// The role is already gone
+	mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "role not found", nil))
+
+	nullLogger := testutils.NewTestLogger().Logger()
+
+	err = CleanUpCCSAccount(nullLogger, mocks.fakeKubeClient, mockAWSClient, &account)
+	assert.Nil(t, err)
+
+	r := AccountReconciler{Client: mocks.fakeKubeClient, Scheme: scheme.Scheme}
+	exists, err := r.DoesSecretExist(types.NamespacedName{Name: "iam-user-secret", Namespace: TestAccountNamespace})
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
 func TestRotateIAMAccessKeys(t *testing.T) {
 	mocks := setupDefaultMocks(t, []runtime.Object{})
 	mockAWSClient := mock.NewMockClient(mocks.mockCtrl)
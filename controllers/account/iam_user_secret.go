@@ -0,0 +1,142 @@
+package account
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// iamUserSecretChanged lets through the deletions and the data changes of the secrets created by the operator,
+// which are mapped to their Account by accountForSecret
+var iamUserSecretChanged = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldSecret, ok := e.ObjectOld.(*corev1.Secret)
+		if !ok {
+			return false
+		}
+		newSecret, ok := e.ObjectNew.(*corev1.Secret)
+		if !ok {
+			return false
+		}
+		return !reflect.DeepEqual(oldSecret.Data, newSecret.Data)
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+// accountForSecret returns the Account a secret was created for, found with its controller reference or the
+// owner labels and annotations set by utils.SetSecretOwner
+func accountForSecret(object client.Object) []reconcile.Request {
+	for _, ref := range object.GetOwnerReferences() {
+		if ref.Kind == "Account" && ref.Controller != nil && *ref.Controller {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: ref.Name, Namespace: object.GetNamespace()}}}
+		}
+	}
+	if object.GetLabels()[awsv1alpha1.SecretOwnerKindLabel] != "Account" {
+		return nil
+	}
+	annotations := object.GetAnnotations()
+	name, namespace := annotations[awsv1alpha1.SecretOwnerNameAnnotation], annotations[awsv1alpha1.SecretOwnerNamespaceAnnotation]
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}}
+}
+
+// validIAMUserSecret checks the secret holds a complete set of IAM user credentials
+func validIAMUserSecret(secret *corev1.Secret) bool {
+	for _, key := range []string{secretAccessKeyID, secretSecretAccessKey} {
+		if len(secret.Data[key]) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ensureIAMUserSecret re-mints the credentials of a Ready account whose IAM user secret was deleted or emptied
+// out-of-band, rather than letting the next claim of the account fail on them. The secret of the AccountClaim of a
+// claimed account gets the new credentials too.
+func (r *AccountReconciler) ensureIAMUserSecret(reqLogger logr.Logger, account *awsv1alpha1.Account, awsSetupClient awsclient.Client, namespace string) error {
+	if !account.IsReady() || account.IsBYOC() || account.IsSTS() || account.Spec.IAMUserSecret == "" {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: account.Spec.IAMUserSecret, Namespace: account.Namespace}, secret)
+	switch {
+	case k8serr.IsNotFound(err):
+		reqLogger.Info("IAM user secret is missing, re-minting the credentials", "secret", account.Spec.IAMUserSecret)
+	case err != nil:
+		return err
+	case validIAMUserSecret(secret):
+		return nil
+	default:
+		reqLogger.Info("IAM user secret is incomplete, re-minting the credentials", "secret", account.Spec.IAMUserSecret)
+	}
+
+	if utils.IsAWSMutationPaused(r.Client) {
+		reqLogger.Info("AWS changes are paused, not re-minting the IAM user credentials")
+		return nil
+	}
+
+	if err != nil {
+		secret = nil
+	}
+	return r.remintIAMUserCredentials(reqLogger, account, awsSetupClient, namespace, secret)
+}
+
+// remintIAMUserCredentials deletes the IAM user secret of the account, if given, and runs the IAM setup of the
+// account, which creates the secret again with a new access key. The secret of the AccountClaim of a claimed account
+// gets the new credentials too.
+func (r *AccountReconciler) remintIAMUserCredentials(reqLogger logr.Logger, account *awsv1alpha1.Account, awsSetupClient awsclient.Client, namespace string, secret *corev1.Secret) error {
+	// The secret is only created again if it doesn't exist
+	if secret != nil {
+		err := r.Client.Delete(context.TODO(), secret)
+		if err != nil && !k8serr.IsNotFound(err) {
+			return err
+		}
+	}
+
+	_, creds, err := r.handleIAMUserCreation(reqLogger, account, awsSetupClient, namespace)
+	if err != nil {
+		return err
+	}
+	if !account.IsClaimed() {
+		return nil
+	}
+
+	// The previous access keys were deleted with the re-minting, the claim's secret has to be updated
+	accountSecret := &corev1.Secret{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: account.Spec.IAMUserSecret, Namespace: account.Namespace}, accountSecret)
+	if err != nil {
+		return err
+	}
+	awsClient, err := r.awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
+		AwsCredsSecretIDKey:     *creds.Credentials.AccessKeyId,
+		AwsCredsSecretAccessKey: *creds.Credentials.SecretAccessKey,
+		AwsToken:                *creds.Credentials.SessionToken,
+		AwsRegion:               config.GetDefaultRegion(),
+		AwsAccountID:            account.Spec.AwsAccountID,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = syncClaimSecret(reqLogger, r.Client, awsClient, account, accountSecret)
+	return err
+}
@@ -0,0 +1,75 @@
+package account
+
+import (
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IAM user secret watch", func() {
+	It("Maps a secret to the Account it's controlled by", func() {
+		controller := true
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "osd-creds-mgmt-abcdef-secret",
+			Namespace: awsv1alpha1.AccountCrNamespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "Account", Name: "osd-creds-mgmt-abcdef", Controller: &controller},
+			},
+		}}
+		Expect(accountForSecret(secret)).To(ConsistOf(reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: "osd-creds-mgmt-abcdef", Namespace: awsv1alpha1.AccountCrNamespace},
+		}))
+	})
+
+	It("Maps a secret to the Account in its owner annotations", func() {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "osd-creds-mgmt-abcdef-secret",
+			Namespace: "other-namespace",
+			Labels:    map[string]string{awsv1alpha1.SecretOwnerKindLabel: "Account"},
+			Annotations: map[string]string{
+				awsv1alpha1.SecretOwnerNameAnnotation:      "osd-creds-mgmt-abcdef",
+				awsv1alpha1.SecretOwnerNamespaceAnnotation: awsv1alpha1.AccountCrNamespace,
+			},
+		}}
+		Expect(accountForSecret(secret)).To(ConsistOf(reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: "osd-creds-mgmt-abcdef", Namespace: awsv1alpha1.AccountCrNamespace},
+		}))
+	})
+
+	It("Ignores the secrets of other owners", func() {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "claim-secret",
+			Namespace: "claim-namespace",
+			Labels:    map[string]string{awsv1alpha1.SecretOwnerKindLabel: "AccountClaim"},
+		}}
+		Expect(accountForSecret(secret)).To(BeEmpty())
+	})
+
+	It("Only lets through the data changes", func() {
+		secret := &corev1.Secret{Data: map[string][]byte{secretAccessKeyID: []byte("AKIAOLD")}}
+		relabeled := secret.DeepCopy()
+		relabeled.Labels = map[string]string{"label": "value"}
+		Expect(iamUserSecretChanged.Update(event.UpdateEvent{ObjectOld: secret, ObjectNew: relabeled})).To(BeFalse())
+
+		edited := secret.DeepCopy()
+		edited.Data[secretAccessKeyID] = []byte("AKIANEW")
+		Expect(iamUserSecretChanged.Update(event.UpdateEvent{ObjectOld: secret, ObjectNew: edited})).To(BeTrue())
+		Expect(iamUserSecretChanged.Delete(event.DeleteEvent{Object: secret})).To(BeTrue())
+	})
+
+	It("Checks the secret holds both access keys", func() {
+		Expect(validIAMUserSecret(&corev1.Secret{Data: map[string][]byte{
+			secretAccessKeyID:     []byte("AKIA"),
+			secretSecretAccessKey: []byte("secret"),
+		}})).To(BeTrue())
+		Expect(validIAMUserSecret(&corev1.Secret{Data: map[string][]byte{
+			secretAccessKeyID: []byte("AKIA"),
+		}})).To(BeFalse())
+	})
+})
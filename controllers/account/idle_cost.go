@@ -0,0 +1,272 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultIdleCostCheckInterval is how often the cost of the unclaimed accounts is checked unless the configmap
+	// says otherwise. Cost Explorer refreshes its data a few times a day at most. An interval of 0 disables the check.
+	defaultIdleCostCheckInterval = 24 * time.Hour
+
+	// defaultIdleCostThreshold is the cost, in USD, from which an idle account is flagged unless the configmap says
+	// otherwise
+	defaultIdleCostThreshold = 1.0
+
+	// idleCostLookback is how far back the cost of the unclaimed accounts is looked at
+	idleCostLookback = 7 * 24 * time.Hour
+
+	// costExplorerDateFormat is the format of the dates of the Cost Explorer time periods
+	costExplorerDateFormat = "2006-01-02"
+
+	idleSpendReason   = "UnclaimedAccountSpend"
+	noIdleSpendReason = "NoIdleSpend"
+	idleCostWatcher   = "idle-cost-watcher"
+)
+
+// IdleCostWatcher periodically asks Cost Explorer about the cost of the Ready unclaimed accounts of the pool. An
+// idle account shouldn't cost anything, so spend over the configured threshold usually means the cleanup left
+// resources behind. The cost of each account is exported as a metric and the accounts over the threshold are
+// flagged with the IdleCost condition.
+type IdleCostWatcher struct {
+	client           client.Client
+	awsClientBuilder awsclient.IBuilder
+	recorder         record.EventRecorder
+}
+
+// NewIdleCostWatcher returns a new IdleCostWatcher
+func NewIdleCostWatcher(client client.Client, awsClientBuilder awsclient.IBuilder, recorder record.EventRecorder) *IdleCostWatcher {
+	return &IdleCostWatcher{
+		client:           client,
+		awsClientBuilder: awsClientBuilder,
+		recorder:         recorder,
+	}
+}
+
+// Start checks the cost of the unclaimed accounts every interval configured in the configmap, until the operator is
+// stopped
+func (w *IdleCostWatcher) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the idle cost watcher")
+	for {
+		interval, threshold := getIdleCostConfig(log, w.client)
+		if interval == 0 {
+			// Check again later whether the check has been enabled
+			interval = defaultIdleCostCheckInterval
+		} else if err := w.CheckIdleCosts(log, threshold, time.Now()); err != nil {
+			log.Error(err, "Failed to check the cost of the unclaimed accounts")
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-stopCh.Done():
+			log.Info("Stopping the idle cost watcher")
+			return
+		}
+	}
+}
+
+// getIdleCostConfig reads the idle cost check interval and threshold from the configmap, falling back to the
+// defaults when they're missing or invalid
+func getIdleCostConfig(log logr.Logger, kubeClient client.Client) (time.Duration, float64) {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		log.Error(err, "Unable to get the idle cost configuration from the configmap, using the defaults")
+		return defaultIdleCostCheckInterval, defaultIdleCostThreshold
+	}
+
+	interval := defaultIdleCostCheckInterval
+	if value := configMap.Data[awsv1alpha1.IdleCostCheckIntervalConfigMapKey]; value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			log.Error(err, "Invalid idle cost check interval in configmap, using the default", "value", value)
+		} else {
+			interval = parsed
+		}
+	}
+
+	threshold := defaultIdleCostThreshold
+	if value := strings.TrimSpace(configMap.Data[awsv1alpha1.IdleCostThresholdConfigMapKey]); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 {
+			log.Error(err, "Invalid idle cost threshold in configmap, using the default", "value", value)
+		} else {
+			threshold = parsed
+		}
+	}
+	return interval, threshold
+}
+
+// isIdle returns true if the account is a Ready non-CCS account of the pool that isn't claimed
+func isIdle(account *awsv1alpha1.Account) bool {
+	return account.IsReady() &&
+		!account.IsClaimed() &&
+		!account.HasClaimLink() &&
+		account.HasAwsAccountID() &&
+		!account.IsBYOC() &&
+		!account.IsPendingDeletion() &&
+		account.Status.StateTransition.IsFor(string(awsv1alpha1.AccountReady))
+}
+
+// idleSince returns the first whole day the account was idle, as the day it became Ready includes the cost of its
+// previous claim or of its setup
+func idleSince(account *awsv1alpha1.Account) time.Time {
+	readyTime := account.Status.StateTransition.LastTransitionTime.UTC()
+	return readyTime.Truncate(24 * time.Hour).Add(24 * time.Hour)
+}
+
+// CheckIdleCosts asks Cost Explorer about the daily cost of the unclaimed accounts over the last week, sums the cost
+// of the days each account was idle, exports it and sets the IdleCost condition of the accounts over the threshold
+func (w *IdleCostWatcher) CheckIdleCosts(log logr.Logger, threshold float64, now time.Time) error {
+	accountList := &awsv1alpha1.AccountList{}
+	err := w.client.List(context.TODO(), accountList, client.InNamespace(awsv1alpha1.AccountCrNamespace))
+	if err != nil {
+		return err
+	}
+
+	idleAccounts := map[string]*awsv1alpha1.Account{}
+	accountIDs := []*string{}
+	for i := range accountList.Items {
+		account := &accountList.Items[i]
+		if !isIdle(account) {
+			// Claimed accounts are budgeted instead, their idle spend is history
+			w.flagIdleCost(log.WithValues("account", account.Name), account, 0, threshold)
+			continue
+		}
+		idleAccounts[account.Spec.AwsAccountID] = account
+		accountIDs = append(accountIDs, aws.String(account.Spec.AwsAccountID))
+	}
+	localmetrics.Collector.ResetIdleAccountCosts()
+	if len(idleAccounts) == 0 {
+		return nil
+	}
+
+	awsSetupClient, err := w.awsClientBuilder.GetClient(idleCostWatcher, w.client, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		return err
+	}
+
+	end := now.UTC().Truncate(24 * time.Hour)
+	costs, err := getDailyCosts(awsSetupClient, accountIDs, end.Add(-idleCostLookback), end)
+	if err != nil {
+		return err
+	}
+
+	for accountID, account := range idleAccounts {
+		since := idleSince(account)
+		cost := 0.0
+		for day, dayCost := range costs[accountID] {
+			if !day.Before(since) {
+				cost += dayCost
+			}
+		}
+		localmetrics.Collector.SetIdleAccountCost(account.Name, accountID, cost)
+		w.flagIdleCost(log.WithValues("account", account.Name, "awsAccountID", accountID), account, cost, threshold)
+	}
+	log.Info("Checked the cost of the unclaimed accounts", "accounts", len(idleAccounts))
+	return nil
+}
+
+// getDailyCosts returns the unblended cost of each day of the period, keyed by AWS account ID then day
+func getDailyCosts(awsClient awsclient.Client, accountIDs []*string, start time.Time, end time.Time) (map[string]map[time.Time]float64, error) {
+	costs := map[string]map[time.Time]float64{}
+	var nextToken *string
+	for {
+		output, err := awsClient.GetCostAndUsage(&costexplorer.GetCostAndUsageInput{
+			TimePeriod: &costexplorer.DateInterval{
+				Start: aws.String(start.Format(costExplorerDateFormat)),
+				End:   aws.String(end.Format(costExplorerDateFormat)),
+			},
+			Granularity: aws.String(costexplorer.GranularityDaily),
+			Metrics:     aws.StringSlice([]string{costexplorer.MetricUnblendedCost}),
+			Filter: &costexplorer.Expression{
+				Dimensions: &costexplorer.DimensionValues{
+					Key:    aws.String(costexplorer.DimensionLinkedAccount),
+					Values: accountIDs,
+				},
+			},
+			GroupBy: []*costexplorer.GroupDefinition{{
+				Type: aws.String(costexplorer.GroupDefinitionTypeDimension),
+				Key:  aws.String(costexplorer.DimensionLinkedAccount),
+			}},
+			NextPageToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range output.ResultsByTime {
+			day, err := time.Parse(costExplorerDateFormat, aws.StringValue(result.TimePeriod.Start))
+			if err != nil {
+				return nil, fmt.Errorf("unexpected Cost Explorer time period %q: %w", aws.StringValue(result.TimePeriod.Start), err)
+			}
+			for _, group := range result.Groups {
+				if len(group.Keys) == 0 || group.Metrics[costexplorer.MetricUnblendedCost] == nil {
+					continue
+				}
+				amount, err := strconv.ParseFloat(aws.StringValue(group.Metrics[costexplorer.MetricUnblendedCost].Amount), 64)
+				if err != nil {
+					return nil, fmt.Errorf("unexpected Cost Explorer amount: %w", err)
+				}
+				accountID := aws.StringValue(group.Keys[0])
+				if costs[accountID] == nil {
+					costs[accountID] = map[time.Time]float64{}
+				}
+				costs[accountID][day] += amount
+			}
+		}
+
+		if output.NextPageToken == nil {
+			return costs, nil
+		}
+		nextToken = output.NextPageToken
+	}
+}
+
+// flagIdleCost sets the IdleCost condition of the account when its idle cost is over the threshold, and clears it
+// once the cost is back under it
+func (w *IdleCostWatcher) flagIdleCost(reqLogger logr.Logger, account *awsv1alpha1.Account, cost float64, threshold float64) {
+	overThreshold := cost > threshold
+	existing := account.GetCondition(awsv1alpha1.AccountIdleCost)
+	if !overThreshold && (existing == nil || existing.Status == corev1.ConditionFalse) {
+		return
+	}
+
+	status, reason, message := corev1.ConditionFalse, noIdleSpendReason, "The account has no idle spend over the threshold"
+	if overThreshold {
+		status, reason = corev1.ConditionTrue, idleSpendReason
+		message = fmt.Sprintf("The unclaimed account cost %.2f USD since %s, over the threshold of %.2f USD", cost, idleSince(account).Format(costExplorerDateFormat), threshold)
+	}
+	if existing != nil && existing.Status == status && existing.Message == message {
+		return
+	}
+	account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountIdleCost, status, reason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+	err := utils.UpdateAccountConditionsWithRetry(w.client, account, awsv1alpha1.AccountIdleCost)
+	if err != nil {
+		reqLogger.Error(err, "Failed to update account status")
+		return
+	}
+	if overThreshold && (existing == nil || existing.Status != corev1.ConditionTrue) {
+		reqLogger.Info("Unclaimed account incurs costs", "cost", cost)
+		utils.RecordEvent(w.recorder, account, corev1.EventTypeWarning, utils.EventReasonIdleCost, "%s", message)
+	}
+}
@@ -0,0 +1,136 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Idle cost watcher", func() {
+	var (
+		nullLogger    logr.Logger
+		ctrl          *gomock.Controller
+		builder       *mock.Builder
+		mockAWSClient *mock.MockClient
+		account       *awsv1alpha1.Account
+		kubeClient    client.Client
+		now           time.Time
+	)
+
+	// dailyCosts returns the Cost Explorer results of the week before now, the account costing amount every day
+	dailyCosts := func(amount string) *costexplorer.GetCostAndUsageOutput {
+		output := &costexplorer.GetCostAndUsageOutput{}
+		end := now.Truncate(24 * time.Hour)
+		for day := end.Add(-idleCostLookback); day.Before(end); day = day.Add(24 * time.Hour) {
+			output.ResultsByTime = append(output.ResultsByTime, &costexplorer.ResultByTime{
+				TimePeriod: &costexplorer.DateInterval{
+					Start: aws.String(day.Format(costExplorerDateFormat)),
+					End:   aws.String(day.Add(24 * time.Hour).Format(costExplorerDateFormat)),
+				},
+				Groups: []*costexplorer.Group{{
+					Keys:    aws.StringSlice([]string{"111111111111"}),
+					Metrics: map[string]*costexplorer.MetricValue{costexplorer.MetricUnblendedCost: {Amount: aws.String(amount), Unit: aws.String("USD")}},
+				}},
+			})
+		}
+		return output
+	}
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		localmetrics.Collector = localmetrics.NewMetricsCollector(nil)
+		ctrl = gomock.NewController(GinkgoT())
+		nullLogger = testutils.NewTestLogger().Logger()
+		builder = &mock.Builder{MockController: ctrl}
+		awsClient, _ := builder.GetClient("", nil, awsclient.NewAwsClientInput{})
+		mockAWSClient = awsClient.(*mock.MockClient)
+		now = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osd-creds-mgmt-abcdef",
+				Namespace: awsv1alpha1.AccountCrNamespace,
+			},
+			Spec: awsv1alpha1.AccountSpec{AwsAccountID: "111111111111"},
+			Status: awsv1alpha1.AccountStatus{
+				State: string(awsv1alpha1.AccountReady),
+				StateTransition: &awsv1alpha1.StateTransition{
+					State:              string(awsv1alpha1.AccountReady),
+					LastTransitionTime: metav1.NewTime(time.Date(2026, 10, 12, 10, 0, 0, 0, time.UTC)),
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	checkIdleCosts := func() {
+		kubeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account).Build()
+		watcher := NewIdleCostWatcher(kubeClient, builder, nil)
+		Expect(watcher.CheckIdleCosts(nullLogger, defaultIdleCostThreshold, now)).To(Succeed())
+	}
+
+	getAccount := func() *awsv1alpha1.Account {
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		return updated
+	}
+
+	It("Only counts the whole days the account was idle", func() {
+		Expect(idleSince(account)).To(Equal(time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("Flags an unclaimed account incurring costs", func() {
+		mockAWSClient.EXPECT().GetCostAndUsage(gomock.Any()).DoAndReturn(func(input *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error) {
+			Expect(aws.StringValue(input.TimePeriod.Start)).To(Equal("2026-10-09"))
+			Expect(aws.StringValue(input.TimePeriod.End)).To(Equal("2026-10-16"))
+			Expect(aws.StringValueSlice(input.Filter.Dimensions.Values)).To(Equal([]string{"111111111111"}))
+			return dailyCosts("0.5"), nil
+		})
+		checkIdleCosts()
+
+		condition := getAccount().GetCondition(awsv1alpha1.AccountIdleCost)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(idleSpendReason))
+		Expect(condition.Message).To(ContainSubstring("1.50 USD since 2026-10-13"))
+	})
+
+	It("Leaves an unclaimed account without costs alone", func() {
+		mockAWSClient.EXPECT().GetCostAndUsage(gomock.Any()).Return(dailyCosts("0.01"), nil)
+		checkIdleCosts()
+
+		Expect(getAccount().GetCondition(awsv1alpha1.AccountIdleCost)).To(BeNil())
+	})
+
+	It("Clears the condition of an account that was claimed", func() {
+		account.Spec.ClaimLink = "claim"
+		account.Status.Claimed = true
+		account.Status.Conditions = []awsv1alpha1.AccountCondition{{
+			Type:   awsv1alpha1.AccountIdleCost,
+			Status: corev1.ConditionTrue,
+			Reason: idleSpendReason,
+		}}
+		checkIdleCosts()
+
+		Expect(getAccount().GetCondition(awsv1alpha1.AccountIdleCost).Status).To(Equal(corev1.ConditionFalse))
+	})
+})
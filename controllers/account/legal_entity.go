@@ -0,0 +1,135 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/audit"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// legalEntityIDPattern matches the legal entity IDs, which also name the OUs of the legal entities
+var legalEntityIDPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]{0,127}$`)
+
+// parseLegalEntityAnnotation returns the legal entity of a ReassignLegalEntityAnnotation, `<id>` or `<id>/<name>`
+func parseLegalEntityAnnotation(value string) awsv1alpha1.LegalEntity {
+	id, name, _ := strings.Cut(strings.TrimSpace(value), "/")
+	return awsv1alpha1.LegalEntity{ID: id, Name: name}
+}
+
+// handleReassignLegalEntityRequest moves an unclaimed Account annotated with ReassignLegalEntityAnnotation to
+// another legal entity, e.g. one set by mistake: the legalEntityID tag of the AWS account is set to the new one, and
+// so is spec.legalEntity, so the account is only reused by the claims of the new legal entity and the account
+// validation moves it to its OU. The reassignment is recorded with an event and in the audit trail. The request is
+// refused, with an event saying why, when the account is claimed or its legal entity can't be changed. The
+// annotation is removed either way, unless the AWS account can't be tagged and the request is retried or AWS changes
+// are paused. Returns true if the request was handled.
+func (r *AccountReconciler) handleReassignLegalEntityRequest(reqLogger logr.Logger, account *awsv1alpha1.Account, awsSetupClient awsclient.Client) (bool, error) {
+	value, requested := account.GetAnnotations()[awsv1alpha1.ReassignLegalEntityAnnotation]
+	if !requested {
+		return false, nil
+	}
+	legalEntity := parseLegalEntityAnnotation(value)
+	previous := account.Spec.LegalEntity
+
+	if refusal := reassignLegalEntityRefusal(account, legalEntity); refusal != "" {
+		reqLogger.Info("Refusing to reassign the legal entity of the account", "reason", refusal)
+		utils.RecordEvent(r.recorder, account, corev1.EventTypeWarning, utils.EventReasonReassignRefused, "Legal entity not reassigned to %q: %s", legalEntity.ID, refusal)
+		return true, r.removeReassignLegalEntityAnnotation(account)
+	}
+	if utils.IsAWSMutationPaused(r.Client) {
+		reqLogger.Info("AWS changes are paused, postponing the reassignment of the legal entity")
+		return false, nil
+	}
+
+	err := tagLegalEntity(awsSetupClient, account.Spec.AwsAccountID, legalEntity.ID)
+	if err != nil {
+		reqLogger.Error(err, "Failed to tag the AWS account with its new legal entity")
+		return true, err
+	}
+
+	err = utils.UpdateWithRetry(r.Client, account, func() error {
+		// The account may have been claimed since it was read
+		if account.Status.Claimed || account.HasClaimLink() {
+			return awsv1alpha1.ErrAccountClaimed
+		}
+		account.Spec.LegalEntity = legalEntity
+		delete(account.Annotations, awsv1alpha1.ReassignLegalEntityAnnotation)
+		return nil
+	})
+	if errors.Is(err, awsv1alpha1.ErrAccountClaimed) {
+		reqLogger.Info("Account claimed during the reassignment, restoring the legal entity tag of the AWS account")
+		err = tagLegalEntity(awsSetupClient, account.Spec.AwsAccountID, previous.ID)
+		if err != nil {
+			return true, err
+		}
+		refusal := reassignLegalEntityRefusal(account, legalEntity)
+		utils.RecordEvent(r.recorder, account, corev1.EventTypeWarning, utils.EventReasonReassignRefused, "Legal entity not reassigned to %q: %s", legalEntity.ID, refusal)
+		return true, r.removeReassignLegalEntityAnnotation(account)
+	}
+	if err != nil {
+		reqLogger.Error(err, "Failed to set the new legal entity of the account")
+		return true, err
+	}
+	audit.Record(context.TODO(), audit.Entry{
+		Controller:   controllerName,
+		Operation:    "ReassignLegalEntity",
+		Target:       fmt.Sprintf("LegalEntityID=%s->%s", previous.ID, legalEntity.ID),
+		Account:      account.Name,
+		AWSAccountID: account.Spec.AwsAccountID,
+		Result:       audit.ResultSucceeded,
+	})
+
+	reqLogger.Info("Legal entity of the account reassigned", "previousLegalEntityID", previous.ID, "legalEntityID", legalEntity.ID)
+	utils.RecordEvent(r.recorder, account, corev1.EventTypeNormal, utils.EventReasonEntityReassigned, "Legal entity reassigned from %q to %q", previous.ID, legalEntity.ID)
+	return true, nil
+}
+
+// tagLegalEntity sets the legalEntityID tag of the AWS account
+func tagLegalEntity(awsSetupClient awsclient.Client, awsAccountID string, legalEntityID string) error {
+	_, err := awsSetupClient.TagResource(&organizations.TagResourceInput{
+		ResourceId: aws.String(awsAccountID),
+		Tags: []*organizations.Tag{{
+			Key:   aws.String(awsv1alpha1.LegalEntityIDTagKey),
+			Value: aws.String(legalEntityID),
+		}},
+	})
+	return err
+}
+
+// reassignLegalEntityRefusal returns why the account can't be moved to legalEntity, empty if it can
+func reassignLegalEntityRefusal(account *awsv1alpha1.Account, legalEntity awsv1alpha1.LegalEntity) string {
+	switch {
+	case !legalEntityIDPattern.MatchString(legalEntity.ID):
+		return "the legal entity ID is empty or invalid"
+	case legalEntity.ID == account.Spec.LegalEntity.ID:
+		return "the account already belongs to this legal entity"
+	case account.IsBYOC() || account.IsSTS():
+		return "CCS and STS accounts belong to the legal entity of their claim"
+	case account.Status.Claimed || account.HasClaimLink():
+		return "only unclaimed accounts can be reassigned, the claim would be left with an account of another legal entity"
+	case !account.HasAwsAccountID():
+		return "the AWS account isn't created yet"
+	case !account.IsReady() && !account.IsFailed():
+		return fmt.Sprintf("the account is %s, only Ready and Failed accounts can be reassigned", account.Status.State)
+	}
+	return ""
+}
+
+// removeReassignLegalEntityAnnotation removes the reassign-legal-entity annotation once the request is handled
+func (r *AccountReconciler) removeReassignLegalEntityAnnotation(account *awsv1alpha1.Account) error {
+	return utils.UpdateWithRetry(r.Client, account, func() error {
+		delete(account.Annotations, awsv1alpha1.ReassignLegalEntityAnnotation)
+		return nil
+	})
+}
@@ -0,0 +1,136 @@
+package account
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Legal entity reassignment requests", func() {
+	var (
+		ctrl          *gomock.Controller
+		mockAWSClient *mock.MockClient
+		account       *awsv1alpha1.Account
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "osd-creds-mgmt-aaabbb",
+				Namespace:   awsv1alpha1.AccountCrNamespace,
+				Annotations: map[string]string{awsv1alpha1.ReassignLegalEntityAnnotation: "2b3c4d/Right Corp"},
+			},
+			Spec: awsv1alpha1.AccountSpec{
+				AwsAccountID: "123456789012",
+				LegalEntity:  awsv1alpha1.LegalEntity{ID: "1a2b3c", Name: "Wrong Corp"},
+			},
+			Status: awsv1alpha1.AccountStatus{State: AccountReady, Reused: true},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	// reassign handles the request and returns the updated account and the event
+	reassign := func() (*awsv1alpha1.Account, string) {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account).Build()
+		recorder := record.NewFakeRecorder(1)
+		r := &AccountReconciler{Client: kubeClient, Scheme: scheme.Scheme, recorder: recorder}
+		handled, err := r.handleReassignLegalEntityRequest(testutils.NewTestLogger().Logger(), account, mockAWSClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(handled).To(BeTrue())
+
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		Expect(updated.Annotations).NotTo(HaveKey(awsv1alpha1.ReassignLegalEntityAnnotation))
+		Expect(recorder.Events).To(HaveLen(1))
+		return updated, <-recorder.Events
+	}
+
+	It("Tags the AWS account and sets the new legal entity", func() {
+		mockAWSClient.EXPECT().TagResource(&organizations.TagResourceInput{
+			ResourceId: aws.String("123456789012"),
+			Tags:       []*organizations.Tag{{Key: aws.String(awsv1alpha1.LegalEntityIDTagKey), Value: aws.String("2b3c4d")}},
+		}).Return(&organizations.TagResourceOutput{}, nil)
+
+		updated, event := reassign()
+		Expect(updated.Spec.LegalEntity).To(Equal(awsv1alpha1.LegalEntity{ID: "2b3c4d", Name: "Right Corp"}))
+		Expect(event).To(ContainSubstring(`reassigned from "1a2b3c" to "2b3c4d"`))
+	})
+
+	It("Refuses claimed accounts", func() {
+		account.Status.Claimed = true
+		account.Spec.ClaimLink = "claim"
+		updated, event := reassign()
+		Expect(updated.Spec.LegalEntity.ID).To(Equal("1a2b3c"))
+		Expect(event).To(ContainSubstring("only unclaimed accounts"))
+	})
+
+	It("Restores the tag of an account claimed during the reassignment", func() {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account).Build()
+		stale := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), stale)).To(Succeed())
+		account.Spec.ClaimLink = "claim"
+		Expect(kubeClient.Update(context.TODO(), account)).To(Succeed())
+
+		gomock.InOrder(
+			mockAWSClient.EXPECT().TagResource(&organizations.TagResourceInput{
+				ResourceId: aws.String("123456789012"),
+				Tags:       []*organizations.Tag{{Key: aws.String(awsv1alpha1.LegalEntityIDTagKey), Value: aws.String("2b3c4d")}},
+			}).Return(&organizations.TagResourceOutput{}, nil),
+			mockAWSClient.EXPECT().TagResource(&organizations.TagResourceInput{
+				ResourceId: aws.String("123456789012"),
+				Tags:       []*organizations.Tag{{Key: aws.String(awsv1alpha1.LegalEntityIDTagKey), Value: aws.String("1a2b3c")}},
+			}).Return(&organizations.TagResourceOutput{}, nil),
+		)
+		recorder := record.NewFakeRecorder(1)
+		r := &AccountReconciler{Client: kubeClient, Scheme: scheme.Scheme, recorder: recorder}
+		handled, err := r.handleReassignLegalEntityRequest(testutils.NewTestLogger().Logger(), stale, mockAWSClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(handled).To(BeTrue())
+
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		Expect(updated.Spec.LegalEntity.ID).To(Equal("1a2b3c"))
+		Expect(updated.Annotations).NotTo(HaveKey(awsv1alpha1.ReassignLegalEntityAnnotation))
+		Expect(<-recorder.Events).To(ContainSubstring("only unclaimed accounts"))
+	})
+
+	It("Refuses invalid legal entity IDs", func() {
+		account.Annotations[awsv1alpha1.ReassignLegalEntityAnnotation] = ""
+		updated, event := reassign()
+		Expect(updated.Spec.LegalEntity.ID).To(Equal("1a2b3c"))
+		Expect(event).To(ContainSubstring("empty or invalid"))
+	})
+
+	It("Refuses CCS accounts", func() {
+		account.Spec.BYOC = true
+		_, event := reassign()
+		Expect(event).To(ContainSubstring("CCS and STS accounts"))
+	})
+
+	It("Ignores accounts without the annotation", func() {
+		account.Annotations = nil
+		r := &AccountReconciler{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account).Build()}
+		handled, err := r.handleReassignLegalEntityRequest(testutils.NewTestLogger().Logger(), account, mockAWSClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(handled).To(BeFalse())
+	})
+})
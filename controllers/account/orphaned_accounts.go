@@ -0,0 +1,303 @@
+package account
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultOrphanedAccountCheckInterval is how often the inventory is checked unless the configmap says
+	// otherwise. An interval of 0 disables the check.
+	defaultOrphanedAccountCheckInterval = 6 * time.Hour
+
+	// orphanedAccountGracePeriod leaves alone the AWS accounts that joined the organization recently, as the
+	// Account CR of an account being created only learns its ID once the creation completes
+	orphanedAccountGracePeriod = time.Hour
+
+	notInOrganizationReason = "NotInOrganization"
+	inOrganizationReason    = "InOrganization"
+	orphanedAccountWatcher  = "orphaned-account-watcher"
+)
+
+// InventoryDrift is the difference between the AWS accounts of the organization and the Account CRs
+type InventoryDrift struct {
+	// OrphanedAWSAccountIDs are the IDs of the active AWS accounts of the pool OU without an Account CR
+	OrphanedAWSAccountIDs []string
+	// AccountsNotInOrganization are the names of the non-CCS Account CRs whose AWS account isn't an active
+	// member of the organization
+	AccountsNotInOrganization []string
+}
+
+// OrphanedAccountWatcher periodically compares the AWS accounts of the organization with the Account CRs. The
+// Account CRs whose AWS account is gone are flagged with the NotInOrganization condition. The AWS accounts of
+// the pool OU without an Account CR are reported, and adopted into the default AccountPool if the configmap
+// says so.
+type OrphanedAccountWatcher struct {
+	client           client.Client
+	awsClientBuilder awsclient.IBuilder
+	recorder         record.EventRecorder
+}
+
+// NewOrphanedAccountWatcher returns a new OrphanedAccountWatcher
+func NewOrphanedAccountWatcher(client client.Client, awsClientBuilder awsclient.IBuilder, recorder record.EventRecorder) *OrphanedAccountWatcher {
+	return &OrphanedAccountWatcher{
+		client:           client,
+		awsClientBuilder: awsClientBuilder,
+		recorder:         recorder,
+	}
+}
+
+// Start checks the inventory every interval configured in the configmap, until the operator is stopped
+func (w *OrphanedAccountWatcher) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the orphaned account watcher")
+	for {
+		interval := getOrphanedAccountCheckInterval(log, w.client)
+		if interval == 0 {
+			// Check again later whether the check has been enabled
+			interval = defaultOrphanedAccountCheckInterval
+		} else if _, err := w.CheckInventory(log); err != nil {
+			log.Error(err, "Failed to check the account inventory")
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-stopCh.Done():
+			log.Info("Stopping the orphaned account watcher")
+			return
+		}
+	}
+}
+
+// getOrphanedAccountCheckInterval reads the inventory check interval from the configmap, falling back to the
+// default when it's missing or invalid
+func getOrphanedAccountCheckInterval(log logr.Logger, kubeClient client.Client) time.Duration {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		log.Error(err, "Unable to get the orphaned account check interval from the configmap, using the default")
+		return defaultOrphanedAccountCheckInterval
+	}
+
+	value, ok := configMap.Data[awsv1alpha1.OrphanedAccountCheckIntervalConfigMapKey]
+	if !ok || value == "" {
+		return defaultOrphanedAccountCheckInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Error(err, "Invalid orphaned account check interval in configmap, using the default", "value", value)
+		return defaultOrphanedAccountCheckInterval
+	}
+	return interval
+}
+
+// CheckInventory compares the AWS accounts of the organization with the Account CRs and returns the drift
+// between them
+func (w *OrphanedAccountWatcher) CheckInventory(log logr.Logger) (*InventoryDrift, error) {
+	configMap, err := utils.GetOperatorConfigMap(w.client)
+	if err != nil {
+		return nil, err
+	}
+
+	accountList := &awsv1alpha1.AccountList{}
+	err = w.client.List(context.TODO(), accountList, client.InNamespace(awsv1alpha1.AccountCrNamespace))
+	if err != nil {
+		return nil, err
+	}
+
+	awsSetupClient, err := w.awsClientBuilder.GetClient(orphanedAccountWatcher, w.client, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	orgAccounts, err := listOrganizationAccounts(awsSetupClient)
+	if err != nil {
+		return nil, err
+	}
+	activeAccounts := map[string]bool{}
+	for _, orgAccount := range orgAccounts {
+		if aws.StringValue(orgAccount.Status) == organizations.AccountStatusActive {
+			activeAccounts[aws.StringValue(orgAccount.Id)] = true
+		}
+	}
+
+	drift := &InventoryDrift{}
+	knownAccounts := map[string]bool{}
+	for i := range accountList.Items {
+		account := &accountList.Items[i]
+		if !account.HasAwsAccountID() {
+			continue
+		}
+		knownAccounts[account.Spec.AwsAccountID] = true
+		// CCS accounts belong to the customer's organization
+		if account.IsBYOC() || account.IsPendingDeletion() {
+			continue
+		}
+
+		inOrganization := activeAccounts[account.Spec.AwsAccountID]
+		if !inOrganization {
+			drift.AccountsNotInOrganization = append(drift.AccountsNotInOrganization, account.Name)
+		}
+		w.flagAccountNotInOrganization(log.WithValues("account", account.Name, "awsAccountID", account.Spec.AwsAccountID), account, inOrganization)
+	}
+
+	poolOU := configMap.Data[awsv1alpha1.OrphanedAccountPoolOUConfigMapKey]
+	if poolOU == "" {
+		log.Info("No pool OU in the configmap, not looking for orphaned AWS accounts")
+	} else {
+		organization, err := awsSetupClient.DescribeOrganization(&organizations.DescribeOrganizationInput{})
+		if err != nil {
+			return nil, err
+		}
+		// The management account never gets an Account CR
+		managementAccountID := aws.StringValue(organization.Organization.MasterAccountId)
+
+		poolAccounts, err := listAccountsForParent(awsSetupClient, poolOU)
+		if err != nil {
+			return nil, err
+		}
+		for _, poolAccount := range poolAccounts {
+			accountID := aws.StringValue(poolAccount.Id)
+			if knownAccounts[accountID] || accountID == managementAccountID || aws.StringValue(poolAccount.Status) != organizations.AccountStatusActive {
+				continue
+			}
+			if time.Since(aws.TimeValue(poolAccount.JoinedTimestamp)) < orphanedAccountGracePeriod {
+				continue
+			}
+			drift.OrphanedAWSAccountIDs = append(drift.OrphanedAWSAccountIDs, accountID)
+		}
+	}
+	sort.Strings(drift.OrphanedAWSAccountIDs)
+	sort.Strings(drift.AccountsNotInOrganization)
+
+	log.Info("Checked the account inventory", "orphanedAWSAccounts", drift.OrphanedAWSAccountIDs, "accountsNotInOrganization", drift.AccountsNotInOrganization)
+	localmetrics.Collector.SetInventoryDrift(len(drift.OrphanedAWSAccountIDs), len(drift.AccountsNotInOrganization))
+
+	if configMap.Data[awsv1alpha1.OrphanedAccountAdoptionConfigMapKey] == "true" {
+		for _, accountID := range drift.OrphanedAWSAccountIDs {
+			err = w.adoptAccount(log.WithValues("awsAccountID", accountID), awsSetupClient, accountID)
+			if err != nil {
+				log.Error(err, "Failed to adopt orphaned AWS account", "awsAccountID", accountID)
+			}
+		}
+	}
+	return drift, nil
+}
+
+// listAccountsForParent returns the accounts directly in the OU
+func listAccountsForParent(awsClient awsclient.Client, parentID string) ([]*organizations.Account, error) {
+	var accounts []*organizations.Account
+	var nextToken *string
+	for {
+		output, err := awsClient.ListAccountsForParent(&organizations.ListAccountsForParentInput{
+			ParentId:  aws.String(parentID),
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, output.Accounts...)
+		if output.NextToken == nil {
+			return accounts, nil
+		}
+		nextToken = output.NextToken
+	}
+}
+
+// flagAccountNotInOrganization sets the NotInOrganization condition of the Account when its AWS account left
+// the organization, and clears it when the account is back
+func (w *OrphanedAccountWatcher) flagAccountNotInOrganization(reqLogger logr.Logger, account *awsv1alpha1.Account, inOrganization bool) {
+	existing := account.GetCondition(awsv1alpha1.AccountNotInOrganization)
+	if inOrganization && (existing == nil || existing.Status == corev1.ConditionFalse) {
+		return
+	}
+	if !inOrganization && existing != nil && existing.Status == corev1.ConditionTrue {
+		return
+	}
+
+	status, reason, message := corev1.ConditionFalse, inOrganizationReason, "The AWS account is an active member of the organization"
+	if !inOrganization {
+		status, reason, message = corev1.ConditionTrue, notInOrganizationReason, "The AWS account isn't an active member of the organization"
+	}
+	account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountNotInOrganization, status, reason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+	err := utils.UpdateAccountConditionsWithRetry(w.client, account, awsv1alpha1.AccountNotInOrganization)
+	if err != nil {
+		reqLogger.Error(err, "Failed to update account status")
+		return
+	}
+	if !inOrganization {
+		reqLogger.Info("AWS account isn't an active member of the organization")
+		utils.RecordEvent(w.recorder, account, corev1.EventTypeWarning, utils.EventReasonNotInOrganization, "%s", message)
+	}
+}
+
+// adoptAccount creates an Account CR in the default AccountPool for an orphaned AWS account created by the
+// operator, as told by its CreatedByTagKey tag. The account controller sets up accounts created with an AWS account
+// ID like the ones it created itself.
+func (w *OrphanedAccountWatcher) adoptAccount(reqLogger logr.Logger, awsSetupClient awsclient.Client, awsAccountID string) error {
+	createdByOperator, err := isCreatedByOperator(awsSetupClient, awsAccountID)
+	if err != nil {
+		return err
+	}
+	if !createdByOperator {
+		reqLogger.Info("Not adopting orphaned AWS account, it wasn't created by the operator")
+		return nil
+	}
+
+	poolName, err := config.GetDefaultAccountPoolName(reqLogger, w.client)
+	if err != nil {
+		return err
+	}
+
+	account := GenerateAccountCR(awsv1alpha1.AccountCrNamespace)
+	account.Spec.AwsAccountID = awsAccountID
+	account.Spec.AccountPool = poolName
+	account.Labels[awsv1alpha1.AdoptedAccountLabel] = "true"
+	utils.AddFinalizer(account, awsv1alpha1.AccountFinalizer)
+
+	err = w.client.Create(context.TODO(), account)
+	if err != nil {
+		return err
+	}
+	reqLogger.Info("Adopted orphaned AWS account", "account", account.Name, "accountPool", poolName)
+	utils.RecordEvent(w.recorder, account, corev1.EventTypeNormal, utils.EventReasonAccountAdopted, "Created for the orphaned AWS account %s", awsAccountID)
+	return nil
+}
+
+// isCreatedByOperator returns whether the AWS account has the CreatedByTagKey tag of the accounts the operator created
+func isCreatedByOperator(awsClient awsclient.Client, awsAccountID string) (bool, error) {
+	var nextToken *string
+	for {
+		output, err := awsClient.ListTagsForResource(&organizations.ListTagsForResourceInput{
+			ResourceId: aws.String(awsAccountID),
+			NextToken:  nextToken,
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, tag := range output.Tags {
+			if aws.StringValue(tag.Key) == awsv1alpha1.CreatedByTagKey && aws.StringValue(tag.Value) == awsv1alpha1.CreatedByTagValue {
+				return true, nil
+			}
+		}
+		if output.NextToken == nil {
+			return false, nil
+		}
+		nextToken = output.NextToken
+	}
+}
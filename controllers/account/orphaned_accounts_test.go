@@ -0,0 +1,159 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Orphaned account watcher", func() {
+	var (
+		nullLogger    logr.Logger
+		ctrl          *gomock.Controller
+		builder       *mock.Builder
+		mockAWSClient *mock.MockClient
+		configMap     *corev1.ConfigMap
+		account       *awsv1alpha1.Account
+		kubeClient    client.Client
+		watcher       *OrphanedAccountWatcher
+	)
+
+	orgAccount := func(id string, status string, joined time.Time) *organizations.Account {
+		return &organizations.Account{Id: aws.String(id), Status: aws.String(status), JoinedTimestamp: aws.Time(joined)}
+	}
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		localmetrics.Collector = localmetrics.NewMetricsCollector(nil)
+		ctrl = gomock.NewController(GinkgoT())
+		nullLogger = testutils.NewTestLogger().Logger()
+		builder = &mock.Builder{MockController: ctrl}
+		awsClient, _ := builder.GetClient("", nil, awsclient.NewAwsClientInput{})
+		mockAWSClient = awsClient.(*mock.MockClient)
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      awsv1alpha1.DefaultConfigMap,
+				Namespace: awsv1alpha1.AccountCrNamespace,
+			},
+			Data: map[string]string{
+				awsv1alpha1.OrphanedAccountPoolOUConfigMapKey: "ou-pool",
+				"accountpool": "hivei01ue1:\n  default: true\n",
+			},
+		}
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osd-creds-mgmt-abcdef",
+				Namespace: awsv1alpha1.AccountCrNamespace,
+			},
+			Spec: awsv1alpha1.AccountSpec{AwsAccountID: "111111111111"},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	checkInventory := func(orgAccounts []*organizations.Account, poolAccounts []*organizations.Account) *InventoryDrift {
+		kubeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(configMap, account).Build()
+		watcher = NewOrphanedAccountWatcher(kubeClient, builder, nil)
+		mockAWSClient.EXPECT().ListAccounts(gomock.Any()).Return(&organizations.ListAccountsOutput{Accounts: orgAccounts}, nil)
+		mockAWSClient.EXPECT().DescribeOrganization(gomock.Any()).Return(&organizations.DescribeOrganizationOutput{
+			Organization: &organizations.Organization{MasterAccountId: aws.String("999999999999")},
+		}, nil)
+		mockAWSClient.EXPECT().ListAccountsForParent(&organizations.ListAccountsForParentInput{ParentId: aws.String("ou-pool")}).Return(
+			&organizations.ListAccountsForParentOutput{Accounts: poolAccounts}, nil)
+		drift, err := watcher.CheckInventory(nullLogger)
+		Expect(err).NotTo(HaveOccurred())
+		return drift
+	}
+
+	getAccount := func() *awsv1alpha1.Account {
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		return updated
+	}
+
+	It("Finds no drift when every AWS account has an Account CR", func() {
+		accounts := []*organizations.Account{orgAccount("111111111111", organizations.AccountStatusActive, time.Now().Add(-24*time.Hour))}
+		drift := checkInventory(accounts, accounts)
+		Expect(drift.OrphanedAWSAccountIDs).To(BeEmpty())
+		Expect(drift.AccountsNotInOrganization).To(BeEmpty())
+		Expect(getAccount().GetCondition(awsv1alpha1.AccountNotInOrganization)).To(BeNil())
+	})
+
+	It("Reports the AWS accounts of the pool OU without an Account CR", func() {
+		accounts := []*organizations.Account{
+			orgAccount("111111111111", organizations.AccountStatusActive, time.Now().Add(-24*time.Hour)),
+			orgAccount("222222222222", organizations.AccountStatusActive, time.Now().Add(-24*time.Hour)),
+			orgAccount("333333333333", organizations.AccountStatusActive, time.Now()),
+			orgAccount("444444444444", organizations.AccountStatusSuspended, time.Now().Add(-24*time.Hour)),
+		}
+		drift := checkInventory(accounts, accounts)
+		Expect(drift.OrphanedAWSAccountIDs).To(Equal([]string{"222222222222"}))
+
+		accountList := &awsv1alpha1.AccountList{}
+		Expect(kubeClient.List(context.TODO(), accountList)).To(Succeed())
+		Expect(accountList.Items).To(HaveLen(1))
+	})
+
+	It("Never reports the management account", func() {
+		accounts := []*organizations.Account{
+			orgAccount("111111111111", organizations.AccountStatusActive, time.Now().Add(-24*time.Hour)),
+			orgAccount("999999999999", organizations.AccountStatusActive, time.Now().Add(-24*time.Hour)),
+		}
+		drift := checkInventory(accounts, accounts)
+		Expect(drift.OrphanedAWSAccountIDs).To(BeEmpty())
+	})
+
+	It("Adopts orphaned AWS accounts into the default AccountPool when enabled", func() {
+		configMap.Data[awsv1alpha1.OrphanedAccountAdoptionConfigMapKey] = "true"
+		accounts := []*organizations.Account{
+			orgAccount("111111111111", organizations.AccountStatusActive, time.Now().Add(-24*time.Hour)),
+			orgAccount("222222222222", organizations.AccountStatusActive, time.Now().Add(-24*time.Hour)),
+			orgAccount("333333333333", organizations.AccountStatusActive, time.Now().Add(-24*time.Hour)),
+		}
+		mockAWSClient.EXPECT().ListTagsForResource(&organizations.ListTagsForResourceInput{ResourceId: aws.String("222222222222")}).Return(
+			&organizations.ListTagsForResourceOutput{Tags: []*organizations.Tag{{Key: aws.String(awsv1alpha1.CreatedByTagKey), Value: aws.String(awsv1alpha1.CreatedByTagValue)}}}, nil)
+		// Not created by the operator
+		mockAWSClient.EXPECT().ListTagsForResource(&organizations.ListTagsForResourceInput{ResourceId: aws.String("333333333333")}).Return(
+			&organizations.ListTagsForResourceOutput{}, nil)
+		checkInventory(accounts, accounts)
+
+		accountList := &awsv1alpha1.AccountList{}
+		Expect(kubeClient.List(context.TODO(), accountList, client.MatchingLabels{awsv1alpha1.AdoptedAccountLabel: "true"})).To(Succeed())
+		Expect(accountList.Items).To(HaveLen(1))
+		Expect(accountList.Items[0].Spec.AwsAccountID).To(Equal("222222222222"))
+		Expect(accountList.Items[0].Spec.AccountPool).To(Equal("hivei01ue1"))
+		Expect(accountList.Items[0].IsOwnedByAccountPool()).To(BeTrue())
+	})
+
+	It("Flags Account CRs whose AWS account left the organization", func() {
+		drift := checkInventory(nil, nil)
+		Expect(drift.AccountsNotInOrganization).To(Equal([]string{account.Name}))
+		Expect(getAccount().GetCondition(awsv1alpha1.AccountNotInOrganization).Status).To(Equal(corev1.ConditionTrue))
+	})
+
+	It("Leaves CCS Account CRs alone", func() {
+		account.Spec.BYOC = true
+		drift := checkInventory(nil, nil)
+		Expect(drift.AccountsNotInOrganization).To(BeEmpty())
+		Expect(getAccount().GetCondition(awsv1alpha1.AccountNotInOrganization)).To(BeNil())
+	})
+})
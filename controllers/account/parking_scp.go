@@ -0,0 +1,138 @@
+package account
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// shouldPark returns true if the account is a non-CCS account quarantined out of the pool. Accounts that are
+// claimed, being deleted, or put back into service by hand through the repool, cleanup or rebuild-iam annotations
+// aren't parked, the operator has to work in them.
+func shouldPark(account *awsv1alpha1.Account) bool {
+	if !account.IsFailed() ||
+		!account.HasAwsAccountID() ||
+		account.IsBYOC() ||
+		account.IsClaimed() ||
+		account.HasClaimLink() ||
+		account.IsPendingDeletion() {
+		return false
+	}
+	_, repool := account.Annotations[awsv1alpha1.RepoolAnnotation]
+	_, cleanup := account.Annotations[awsv1alpha1.CleanupAnnotation]
+	_, rebuildIAM := account.Annotations[awsv1alpha1.RebuildIAMAnnotation]
+	return !repool && !cleanup && !rebuildIAM
+}
+
+// isParked returns true if the parking SCP was attached to the account when its policies were last recorded
+func isParked(account *awsv1alpha1.Account, policyID string) bool {
+	for _, id := range account.Status.ServiceControlPolicies {
+		if id == policyID {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileParkingSCP attaches the parking SCP to the accounts quarantined out of the pool, so nothing runs in them
+// while they wait for an SRE, and detaches it once they're put back into service or deleted. The policies attached
+// to the account are recorded in its status whenever the parking SCP is attached or detached.
+func (r *AccountReconciler) reconcileParkingSCP(reqLogger logr.Logger, awsSetupClient awsclient.Client, account *awsv1alpha1.Account, policyID string) error {
+	if policyID == "" {
+		return nil
+	}
+	park := shouldPark(account)
+	if park == isParked(account, policyID) {
+		return nil
+	}
+	if utils.IsMaintenanceMode(r.Client) {
+		reqLogger.Info("Maintenance mode is enabled, postponing the parking SCP change", "park", park)
+		return nil
+	}
+
+	target := aws.String(account.Spec.AwsAccountID)
+	var aerr awserr.Error
+	if park {
+		reqLogger.Info("Attaching the parking SCP to the quarantined account", "policyID", policyID)
+		_, err := awsSetupClient.AttachPolicy(&organizations.AttachPolicyInput{PolicyId: aws.String(policyID), TargetId: target})
+		if err != nil && (!errors.As(err, &aerr) || aerr.Code() != organizations.ErrCodeDuplicatePolicyAttachmentException) {
+			reqLogger.Error(err, "Failed to attach the parking SCP", "policyID", policyID)
+			return err
+		}
+	} else {
+		reqLogger.Info("Detaching the parking SCP from the account", "policyID", policyID)
+		_, err := awsSetupClient.DetachPolicy(&organizations.DetachPolicyInput{PolicyId: aws.String(policyID), TargetId: target})
+		if err != nil && (!errors.As(err, &aerr) || aerr.Code() != organizations.ErrCodePolicyNotAttachedException) {
+			reqLogger.Error(err, "Failed to detach the parking SCP", "policyID", policyID)
+			return err
+		}
+	}
+
+	policies, err := listServiceControlPolicies(awsSetupClient, account.Spec.AwsAccountID)
+	if err != nil {
+		reqLogger.Error(err, "Failed to list the service control policies of the account")
+		return err
+	}
+	// AWS Organizations may not list the change yet
+	policies = withParkingSCP(policies, policyID, park)
+	err = utils.UpdateStatusWithRetry(r.Client, account, func() error {
+		account.Status.ServiceControlPolicies = policies
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if park {
+		utils.RecordEvent(r.recorder, account, corev1.EventTypeWarning, utils.EventReasonAccountParked, "Parking SCP %s attached while the account is %s", policyID, account.Status.State)
+	} else {
+		utils.RecordEvent(r.recorder, account, corev1.EventTypeNormal, utils.EventReasonAccountUnparked, "Parking SCP %s detached", policyID)
+	}
+	return nil
+}
+
+// listServiceControlPolicies returns the IDs of the service control policies attached directly to the AWS
+// account, leaving out the ones it inherits from its OUs
+func listServiceControlPolicies(awsClient awsclient.Client, awsAccountID string) ([]string, error) {
+	policies := []string{}
+	var nextToken *string
+	for {
+		output, err := awsClient.ListPoliciesForTarget(&organizations.ListPoliciesForTargetInput{
+			TargetId:  aws.String(awsAccountID),
+			Filter:    aws.String(organizations.PolicyTypeServiceControlPolicy),
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range output.Policies {
+			policies = append(policies, aws.StringValue(policy.Id))
+		}
+		if output.NextToken == nil {
+			return policies, nil
+		}
+		nextToken = output.NextToken
+	}
+}
+
+// withParkingSCP returns the sorted policies with or without the parking SCP
+func withParkingSCP(policies []string, policyID string, park bool) []string {
+	result := []string{}
+	for _, id := range policies {
+		if id != policyID {
+			result = append(result, id)
+		}
+	}
+	if park {
+		result = append(result, policyID)
+	}
+	sort.Strings(result)
+	return result
+}
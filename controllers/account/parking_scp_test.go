@@ -0,0 +1,110 @@
+package account
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Parking SCP", func() {
+	const policyID = "p-parking"
+
+	var (
+		ctrl          *gomock.Controller
+		mockAWSClient *mock.MockClient
+		account       *awsv1alpha1.Account
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "osd-creds-mgmt-aaabbb", Namespace: awsv1alpha1.AccountCrNamespace},
+			Spec:       awsv1alpha1.AccountSpec{AwsAccountID: "123456789012"},
+			Status:     awsv1alpha1.AccountStatus{State: AccountFailed},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	reconcileParkingSCP := func() *awsv1alpha1.Account {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account).Build()
+		r := &AccountReconciler{Client: kubeClient, Scheme: scheme.Scheme}
+		Expect(r.reconcileParkingSCP(testutils.NewTestLogger().Logger(), mockAWSClient, account, policyID)).To(Succeed())
+
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		return updated
+	}
+
+	expectPolicies := func(ids ...string) {
+		output := &organizations.ListPoliciesForTargetOutput{}
+		for _, id := range ids {
+			output.Policies = append(output.Policies, &organizations.PolicySummary{Id: aws.String(id)})
+		}
+		mockAWSClient.EXPECT().ListPoliciesForTarget(gomock.Any()).Return(output, nil)
+	}
+
+	It("Parks the quarantined accounts", func() {
+		mockAWSClient.EXPECT().AttachPolicy(&organizations.AttachPolicyInput{
+			PolicyId: aws.String(policyID),
+			TargetId: aws.String("123456789012"),
+		}).Return(&organizations.AttachPolicyOutput{}, nil)
+		expectPolicies("p-FullAWSAccess")
+
+		updated := reconcileParkingSCP()
+		Expect(updated.Status.ServiceControlPolicies).To(Equal([]string{"p-FullAWSAccess", policyID}))
+	})
+
+	It("Leaves a parked account alone", func() {
+		account.Status.ServiceControlPolicies = []string{policyID}
+		reconcileParkingSCP()
+	})
+
+	It("Releases the account once it's put back into service", func() {
+		account.Status.State = AccountReady
+		account.Status.ServiceControlPolicies = []string{"p-FullAWSAccess", policyID}
+		mockAWSClient.EXPECT().DetachPolicy(&organizations.DetachPolicyInput{
+			PolicyId: aws.String(policyID),
+			TargetId: aws.String("123456789012"),
+		}).Return(&organizations.DetachPolicyOutput{}, nil)
+		expectPolicies("p-FullAWSAccess", policyID)
+
+		updated := reconcileParkingSCP()
+		Expect(updated.Status.ServiceControlPolicies).To(Equal([]string{"p-FullAWSAccess"}))
+	})
+
+	It("Doesn't park the accounts the operator has to work in", func() {
+		account.Annotations = map[string]string{awsv1alpha1.RepoolAnnotation: "cleaned up in OHSS-1234"}
+		Expect(shouldPark(account)).To(BeFalse())
+
+		account.Annotations = map[string]string{awsv1alpha1.RebuildIAMAnnotation: "OHSS-1234"}
+		Expect(shouldPark(account)).To(BeFalse())
+
+		account.Annotations = nil
+		account.Spec.ClaimLink = "mycluster"
+		Expect(shouldPark(account)).To(BeFalse())
+
+		account.Spec.ClaimLink = ""
+		account.Spec.BYOC = true
+		Expect(shouldPark(account)).To(BeFalse())
+
+		account.Spec.BYOC = false
+		Expect(shouldPark(account)).To(BeTrue())
+	})
+})
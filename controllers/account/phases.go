@@ -0,0 +1,123 @@
+package account
+
+import (
+	"math"
+	"time"
+
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// phaseBackoff is the delay before retrying a failed attempt at an account creation phase, doubled with
+// each failed attempt up to max
+type phaseBackoff struct {
+	base time.Duration
+	max  time.Duration
+}
+
+// phaseBackoffs are the retry delays of the account creation phases. Region initialization starts EC2
+// instances in every region so it is retried less eagerly than the IAM setup.
+var phaseBackoffs = map[awsv1alpha1.AccountPhase]phaseBackoff{
+	awsv1alpha1.AccountPhaseIAMSetup:   {base: 10 * time.Second, max: 10 * time.Minute},
+	awsv1alpha1.AccountPhaseRegionInit: {base: 30 * time.Second, max: 30 * time.Minute},
+}
+
+// defaultPhaseBackoff is used for the phases without their own backoff
+var defaultPhaseBackoff = phaseBackoff{base: 30 * time.Second, max: 15 * time.Minute}
+
+// phaseOrder is the order the account creation phases are completed in
+var phaseOrder = map[awsv1alpha1.AccountPhase]int{
+	awsv1alpha1.AccountPhaseCreating:            0,
+	awsv1alpha1.AccountPhaseIAMSetup:            1,
+	awsv1alpha1.AccountPhaseRegionInit:          2,
+	awsv1alpha1.AccountPhasePendingVerification: 3,
+	awsv1alpha1.AccountPhaseReady:               4,
+}
+
+// getPhaseBackoff returns how long to wait before attempting the phase again after the given number of
+// consecutive failed attempts
+func getPhaseBackoff(phase awsv1alpha1.AccountPhase, attempts int) time.Duration {
+	backoff, ok := phaseBackoffs[phase]
+	if !ok {
+		backoff = defaultPhaseBackoff
+	}
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := float64(backoff.base) * math.Pow(2, float64(attempts-1))
+	if delay > float64(backoff.max) {
+		return backoff.max
+	}
+	return time.Duration(delay)
+}
+
+// phaseForState returns the account creation phase matching the state of the Account. The IAMSetup and
+// RegionInit phases happen while the Account is Creating, so Creating only maps to a phase for Accounts
+// that haven't recorded one yet.
+func phaseForState(account *awsv1alpha1.Account) (awsv1alpha1.AccountPhase, bool) {
+	switch account.Status.State {
+	case string(awsv1alpha1.AccountCreating), string(awsv1alpha1.AccountOptingInRegions), string(awsv1alpha1.AccountOptInRegionEnabled):
+		return awsv1alpha1.AccountPhaseCreating, account.GetPhase() == ""
+	case awsv1alpha1.AccountInitializingRegions:
+		return awsv1alpha1.AccountPhaseRegionInit, true
+	case string(awsv1alpha1.AccountPendingVerification):
+		return awsv1alpha1.AccountPhasePendingVerification, true
+	case string(awsv1alpha1.AccountReady):
+		return awsv1alpha1.AccountPhaseReady, true
+	}
+	return "", false
+}
+
+// syncPhaseWithState records the account creation phase reached by the state changes of the previous
+// reconciles, so Accounts created before phases were recorded resume in the right one
+func (r *AccountReconciler) syncPhaseWithState(reqLogger logr.Logger, account *awsv1alpha1.Account) error {
+	phase, ok := phaseForState(account)
+	if !ok || account.GetPhase() == phase {
+		return nil
+	}
+	return r.enterPhase(reqLogger, account, phase)
+}
+
+// enterPhase persists the account creation phase the Account moved to. An Account already past the phase
+// stays in its own, so the failed attempts at it are kept and its backoff keeps growing.
+func (r *AccountReconciler) enterPhase(reqLogger logr.Logger, account *awsv1alpha1.Account, phase awsv1alpha1.AccountPhase) error {
+	if account.GetPhase() == phase || phaseOrder[phase] < phaseOrder[account.GetPhase()] {
+		return nil
+	}
+	previous := account.GetPhase()
+	err := utils.UpdateStatusWithRetry(r.Client, account, func() error {
+		account.EnterPhase(phase)
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to record account phase", "phase", phase)
+		return err
+	}
+	reqLogger.Info("Account entered phase", "phase", phase, "previousPhase", previous)
+	return nil
+}
+
+// phaseFailed records a failed attempt at completing the current account creation phase and requeues the
+// Account once the backoff of the phase has elapsed. The attempts are kept in the status so the backoff
+// survives operator restarts, and an Account failing the same phase over and over can be spotted.
+func (r *AccountReconciler) phaseFailed(reqLogger logr.Logger, account *awsv1alpha1.Account, phaseErr error) (reconcile.Result, error) {
+	phase := account.GetPhase()
+	attempts := 1
+	if account.Status.Phase != nil {
+		attempts = account.Status.Phase.Attempts + 1
+	}
+	backoff := getPhaseBackoff(phase, attempts)
+	reqLogger.Error(phaseErr, "Account phase failed, retrying", "phase", phase, "attempts", attempts, "retryAfter", backoff)
+
+	err := utils.UpdateStatusWithRetry(r.Client, account, func() error {
+		account.RecordPhaseFailure(phaseErr, backoff)
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to record account phase failure", "phase", phase)
+		return reconcile.Result{}, phaseErr
+	}
+	return reconcile.Result{RequeueAfter: backoff}, nil
+}
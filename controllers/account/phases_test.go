@@ -0,0 +1,99 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Account creation phases", func() {
+	var (
+		r       *AccountReconciler
+		account *awsv1alpha1.Account
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "account", Namespace: awsv1alpha1.AccountCrNamespace},
+			Status:     awsv1alpha1.AccountStatus{State: string(awsv1alpha1.AccountCreating)},
+		}
+		r = &AccountReconciler{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account).Build()}
+	})
+
+	getAccount := func() *awsv1alpha1.Account {
+		updated := &awsv1alpha1.Account{}
+		Expect(r.Client.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		return updated
+	}
+
+	It("Doubles the backoff of a phase up to its maximum", func() {
+		Expect(getPhaseBackoff(awsv1alpha1.AccountPhaseIAMSetup, 1)).To(Equal(10 * time.Second))
+		Expect(getPhaseBackoff(awsv1alpha1.AccountPhaseIAMSetup, 3)).To(Equal(40 * time.Second))
+		Expect(getPhaseBackoff(awsv1alpha1.AccountPhaseIAMSetup, 20)).To(Equal(10 * time.Minute))
+		Expect(getPhaseBackoff(awsv1alpha1.AccountPhasePendingVerification, 1)).To(Equal(defaultPhaseBackoff.base))
+	})
+
+	It("Resumes accounts without a recorded phase in the phase of their state", func() {
+		account.Status.State = awsv1alpha1.AccountInitializingRegions
+		Expect(r.Client.Status().Update(context.TODO(), account)).To(Succeed())
+
+		Expect(r.syncPhaseWithState(testutils.NewTestLogger().Logger(), account)).To(Succeed())
+		Expect(getAccount().GetPhase()).To(Equal(awsv1alpha1.AccountPhaseRegionInit))
+	})
+
+	It("Doesn't move a Creating account back from IAMSetup", func() {
+		Expect(r.enterPhase(testutils.NewTestLogger().Logger(), account, awsv1alpha1.AccountPhaseIAMSetup)).To(Succeed())
+
+		Expect(r.syncPhaseWithState(testutils.NewTestLogger().Logger(), account)).To(Succeed())
+		Expect(getAccount().GetPhase()).To(Equal(awsv1alpha1.AccountPhaseIAMSetup))
+	})
+
+	It("Records failed attempts and requeues after the backoff", func() {
+		log := testutils.NewTestLogger().Logger()
+		Expect(r.enterPhase(log, account, awsv1alpha1.AccountPhaseIAMSetup)).To(Succeed())
+
+		result, err := r.phaseFailed(log, account, errors.New("throttled"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+		result, err = r.phaseFailed(log, account, errors.New("throttled"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(20 * time.Second))
+
+		updated := getAccount()
+		Expect(updated.Status.Phase.Attempts).To(Equal(2))
+		Expect(updated.Status.Phase.LastError).To(Equal("throttled"))
+		Expect(updated.PhaseRetryDelay()).To(BeNumerically(">", 0))
+
+		Expect(r.enterPhase(log, updated, awsv1alpha1.AccountPhaseRegionInit)).To(Succeed())
+		updated = getAccount()
+		Expect(updated.Status.Phase.Attempts).To(BeZero())
+		Expect(updated.PhaseRetryDelay()).To(BeZero())
+	})
+
+	It("Keeps the attempts at a phase when an earlier one is entered again", func() {
+		log := testutils.NewTestLogger().Logger()
+		Expect(r.enterPhase(log, account, awsv1alpha1.AccountPhaseRegionInit)).To(Succeed())
+		_, err := r.phaseFailed(log, account, errors.New("throttled"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.enterPhase(log, account, awsv1alpha1.AccountPhaseIAMSetup)).To(Succeed())
+		updated := getAccount()
+		Expect(updated.GetPhase()).To(Equal(awsv1alpha1.AccountPhaseRegionInit))
+		Expect(updated.Status.Phase.Attempts).To(Equal(1))
+
+		result, err := r.phaseFailed(log, updated, errors.New("throttled"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+	})
+})
@@ -0,0 +1,165 @@
+package account
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/support"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PreflightRecheckInterval is how often an account failing the preflight checks is checked again
+	PreflightRecheckInterval = time.Hour
+	// PreflightRefreshInterval is how often an account is checked again while Trusted Advisor refreshes its checks
+	PreflightRefreshInterval = time.Minute
+
+	preflightFailedReason = "PreflightChecksFailed"
+	preflightPassedReason = "PreflightChecksPassed"
+
+	// trustedAdvisorStatusError is the status of a Trusted Advisor check recommending action
+	trustedAdvisorStatusError = "error"
+	// trustedAdvisorRefreshSuccess is the status of a Trusted Advisor check refresh once its results are up to date
+	trustedAdvisorRefreshSuccess = "success"
+)
+
+// preflightChecks are the IDs of the Trusted Advisor checks the preflight checks are made of, by name. They're core
+// checks, available without a Business or Enterprise Support plan.
+var preflightChecks = map[string]string{
+	"service-limits":      "eW7HH0l7J9",
+	"exposed-access-keys": "12Fnkpl8Y5",
+	"s3-public-buckets":   "Pfx0RwqBli",
+}
+
+// GetPreflightChecks returns the names of the preflight checks configured in the operator configmap, none when the
+// configmap doesn't list any
+func GetPreflightChecks(kubeClient client.Client) ([]string, error) {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	checks := []string{}
+	for _, field := range strings.Split(configMap.Data[awsv1alpha1.PreflightChecksConfigMapKey], ",") {
+		name := strings.TrimSpace(field)
+		if name == "" {
+			continue
+		}
+		if _, ok := preflightChecks[name]; !ok {
+			return nil, fmt.Errorf("unknown preflight check %q in %s", name, awsv1alpha1.PreflightChecksConfigMapKey)
+		}
+		checks = append(checks, name)
+	}
+	return checks, nil
+}
+
+// RunPreflightChecks refreshes the Trusted Advisor checks in the account and returns why it failed them, nothing when
+// it passed. The results of a check are only read once its refresh succeeded, true is returned while some of the
+// checks are still refreshing: their results are unknown yet and they're neither failed nor passed.
+func RunPreflightChecks(reqLogger logr.Logger, awsClient awsclient.Client, checks []string) ([]string, bool, error) {
+	failures := []string{}
+	pending := false
+	for _, name := range checks {
+		checkID := aws.String(preflightChecks[name])
+		refresh, err := awsClient.RefreshTrustedAdvisorCheck(&support.RefreshTrustedAdvisorCheckInput{CheckId: checkID})
+		if err != nil {
+			reqLogger.Error(err, "Failed to refresh the Trusted Advisor check", "check", name)
+			return nil, false, err
+		}
+		status := ""
+		if refresh.Status != nil {
+			status = aws.StringValue(refresh.Status.Status)
+		}
+		if status != trustedAdvisorRefreshSuccess {
+			reqLogger.Info("Trusted Advisor check still refreshing", "check", name, "status", status)
+			pending = true
+			continue
+		}
+
+		output, err := awsClient.DescribeTrustedAdvisorCheckResult(&support.DescribeTrustedAdvisorCheckResultInput{
+			CheckId:  checkID,
+			Language: aws.String("en"),
+		})
+		if err != nil {
+			reqLogger.Error(err, "Failed to get the Trusted Advisor check result", "check", name)
+			return nil, false, err
+		}
+		if output.Result == nil || aws.StringValue(output.Result.Status) != trustedAdvisorStatusError {
+			continue
+		}
+		flagged := int64(0)
+		if output.Result.ResourcesSummary != nil {
+			flagged = aws.Int64Value(output.Result.ResourcesSummary.ResourcesFlagged)
+		}
+		failures = append(failures, fmt.Sprintf("%s: %d resources flagged", name, flagged))
+	}
+	sort.Strings(failures)
+	return failures, pending, nil
+}
+
+// SetPreflightCondition sets the PreflightFailed condition of the account from the failures of its preflight checks,
+// and clears it once they pass. Returns true if the conditions changed.
+func SetPreflightCondition(account *awsv1alpha1.Account, failures []string) bool {
+	existing := account.GetCondition(awsv1alpha1.AccountPreflightFailed)
+	if len(failures) == 0 && (existing == nil || existing.Status == corev1.ConditionFalse) {
+		return false
+	}
+
+	status, reason, message := corev1.ConditionFalse, preflightPassedReason, "The account passed the preflight checks"
+	if len(failures) > 0 {
+		status, reason, message = corev1.ConditionTrue, preflightFailedReason, strings.Join(failures, "; ")
+	}
+	if existing != nil && existing.Status == status && existing.Message == message {
+		return false
+	}
+	account.Status.Conditions = utils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountPreflightFailed, status, reason, message, utils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+	return true
+}
+
+// preflightAccount runs the configured preflight checks in a non-CCS account before it's Ready and records the outcome
+// in its PreflightFailed condition. Returns true if the account passed, and true if some of the checks are still
+// refreshing, the outcome is then left as it was.
+func (r *AccountReconciler) preflightAccount(reqLogger logr.Logger, awsSetupClient awsclient.Client, account *awsv1alpha1.Account) (bool, bool, error) {
+	checks, err := GetPreflightChecks(r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Failed to read the preflight checks")
+		return false, false, err
+	}
+	if len(checks) == 0 {
+		return true, false, nil
+	}
+
+	awsClient, _, err := stsclient.HandleRoleAssumption(reqLogger, r.awsClientBuilder, account, r.Client, awsSetupClient, "", account.GetAssumeRole(), "")
+	if err != nil {
+		reqLogger.Error(err, "failed building AWS client from assume_role")
+		return false, false, err
+	}
+	failures, pending, err := RunPreflightChecks(reqLogger, awsClient, checks)
+	if err != nil || pending {
+		return false, pending, err
+	}
+	if SetPreflightCondition(account, failures) {
+		err = r.statusUpdate(account)
+		if err != nil {
+			return false, false, err
+		}
+		if len(failures) > 0 {
+			reqLogger.Info("Account failed the preflight checks", "failures", failures)
+			utils.RecordEvent(r.recorder, account, corev1.EventTypeWarning, utils.EventReasonPreflightFailed, "Account kept out of the pool: %s", strings.Join(failures, "; "))
+		}
+	}
+	return len(failures) == 0, false, nil
+}
@@ -0,0 +1,118 @@
+package account
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/support"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Preflight checks", func() {
+	var (
+		ctrl          *gomock.Controller
+		mockAWSClient *mock.MockClient
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	getChecks := func(value string) ([]string, error) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+			Data:       map[string]string{awsv1alpha1.PreflightChecksConfigMapKey: value},
+		}
+		return GetPreflightChecks(fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(configMap).Build())
+	}
+
+	checkResult := func(status string, flagged int64) *support.DescribeTrustedAdvisorCheckResultOutput {
+		return &support.DescribeTrustedAdvisorCheckResultOutput{Result: &support.TrustedAdvisorCheckResult{
+			Status:           aws.String(status),
+			ResourcesSummary: &support.TrustedAdvisorResourcesSummary{ResourcesFlagged: aws.Int64(flagged)},
+		}}
+	}
+
+	expectRefresh := func(checkID string, status string) {
+		mockAWSClient.EXPECT().RefreshTrustedAdvisorCheck(&support.RefreshTrustedAdvisorCheckInput{
+			CheckId: aws.String(checkID),
+		}).Return(&support.RefreshTrustedAdvisorCheckOutput{Status: &support.TrustedAdvisorCheckRefreshStatus{
+			CheckId: aws.String(checkID),
+			Status:  aws.String(status),
+		}}, nil)
+	}
+
+	It("Reads the configured checks", func() {
+		checks, err := getChecks("service-limits, exposed-access-keys")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checks).To(Equal([]string{"service-limits", "exposed-access-keys"}))
+
+		checks, err = getChecks("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checks).To(BeEmpty())
+
+		_, err = getChecks("service-limits,lucky-dip")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Fails the checks Trusted Advisor recommends action for", func() {
+		expectRefresh("eW7HH0l7J9", "success")
+		mockAWSClient.EXPECT().DescribeTrustedAdvisorCheckResult(&support.DescribeTrustedAdvisorCheckResultInput{
+			CheckId:  aws.String("eW7HH0l7J9"),
+			Language: aws.String("en"),
+		}).Return(checkResult("error", 2), nil)
+		expectRefresh("Pfx0RwqBli", "success")
+		mockAWSClient.EXPECT().DescribeTrustedAdvisorCheckResult(&support.DescribeTrustedAdvisorCheckResultInput{
+			CheckId:  aws.String("Pfx0RwqBli"),
+			Language: aws.String("en"),
+		}).Return(checkResult("warning", 1), nil)
+
+		failures, pending, err := RunPreflightChecks(testutils.NewTestLogger().Logger(), mockAWSClient, []string{"service-limits", "s3-public-buckets"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(BeFalse())
+		Expect(failures).To(Equal([]string{"service-limits: 2 resources flagged"}))
+	})
+
+	It("Waits for the checks to refresh before reading their results", func() {
+		expectRefresh("eW7HH0l7J9", "processing")
+		expectRefresh("12Fnkpl8Y5", "success")
+		mockAWSClient.EXPECT().DescribeTrustedAdvisorCheckResult(&support.DescribeTrustedAdvisorCheckResultInput{
+			CheckId:  aws.String("12Fnkpl8Y5"),
+			Language: aws.String("en"),
+		}).Return(checkResult("error", 1), nil)
+
+		failures, pending, err := RunPreflightChecks(testutils.NewTestLogger().Logger(), mockAWSClient, []string{"service-limits", "exposed-access-keys"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(BeTrue())
+		Expect(failures).To(Equal([]string{"exposed-access-keys: 1 resources flagged"}))
+	})
+
+	It("Sets the PreflightFailed condition and clears it once the checks pass", func() {
+		account := &awsv1alpha1.Account{}
+		Expect(SetPreflightCondition(account, nil)).To(BeFalse())
+		Expect(account.GetCondition(awsv1alpha1.AccountPreflightFailed)).To(BeNil())
+
+		Expect(SetPreflightCondition(account, []string{"service-limits: 2 resources flagged"})).To(BeTrue())
+		condition := account.GetCondition(awsv1alpha1.AccountPreflightFailed)
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition.Message).To(Equal("service-limits: 2 resources flagged"))
+		Expect(SetPreflightCondition(account, []string{"service-limits: 2 resources flagged"})).To(BeFalse())
+
+		Expect(SetPreflightCondition(account, nil)).To(BeTrue())
+		Expect(account.GetCondition(awsv1alpha1.AccountPreflightFailed).Status).To(Equal(corev1.ConditionFalse))
+	})
+})
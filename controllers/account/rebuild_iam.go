@@ -0,0 +1,84 @@
+package account
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// handleRebuildIAMRequest runs the IAM setup of an Account annotated with RebuildIAMAnnotation again: the
+// ManagedOpenShift-Support and SRE access roles, the security baseline, and the IAM user with its policies and a new
+// access key, in place of the ones tampered with or left behind by a failed setup. The state of the account is left
+// alone, a Failed account is put back into the pool with the repool annotation once its IAM setup is rebuilt. The
+// request is refused, with an event saying why, when the account has no IAM setup the operator can rebuild. The
+// annotation is removed either way, unless the rebuild fails and is retried or AWS changes are paused. Returns true
+// if the request was handled.
+func (r *AccountReconciler) handleRebuildIAMRequest(reqLogger logr.Logger, account *awsv1alpha1.Account, awsSetupClient awsclient.Client, namespace string) (bool, error) {
+	reason, requested := account.GetAnnotations()[awsv1alpha1.RebuildIAMAnnotation]
+	if !requested {
+		return false, nil
+	}
+
+	if refusal := rebuildIAMRefusal(account); refusal != "" {
+		reqLogger.Info("Refusing to rebuild the IAM setup of the account", "reason", refusal)
+		utils.RecordEvent(r.recorder, account, corev1.EventTypeWarning, utils.EventReasonIAMRebuildRefused, "IAM setup not rebuilt: %s", refusal)
+		return true, r.removeRebuildIAMAnnotation(account)
+	}
+	if utils.IsAWSMutationPaused(r.Client) {
+		reqLogger.Info("AWS changes are paused, postponing the rebuild of the IAM setup")
+		return false, nil
+	}
+
+	// The secret is deleted so the IAM user gets a new access key, the ones it had may have been tampered with too
+	var secret *corev1.Secret
+	if account.Spec.IAMUserSecret != "" {
+		secret = &corev1.Secret{}
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: account.Spec.IAMUserSecret, Namespace: account.Namespace}, secret)
+		if k8serr.IsNotFound(err) {
+			secret = nil
+		} else if err != nil {
+			return true, err
+		}
+	}
+
+	reqLogger.Info("Rebuilding the IAM setup of the account", "reason", reason)
+	err := r.remintIAMUserCredentials(reqLogger, account, awsSetupClient, namespace, secret)
+	if err != nil {
+		reqLogger.Error(err, "Failed to rebuild the IAM setup of the account")
+		return true, err
+	}
+
+	utils.RecordEvent(r.recorder, account, corev1.EventTypeNormal, utils.EventReasonIAMRebuilt, "IAM setup of IAM user %s rebuilt with a new access key: %s", account.GetIAMUserName(), reason)
+	return true, r.removeRebuildIAMAnnotation(account)
+}
+
+// rebuildIAMRefusal returns why the IAM setup of the account can't be rebuilt, empty if it can
+func rebuildIAMRefusal(account *awsv1alpha1.Account) string {
+	switch {
+	case account.IsBYOC() || account.IsSTS():
+		return "the IAM setup of CCS and STS accounts belongs to the customer"
+	case !account.HasAwsAccountID():
+		return "the AWS account isn't created yet"
+	case !account.IsReady() && !account.IsFailed():
+		return fmt.Sprintf("the account is %s, only the IAM setup of Ready and Failed accounts can be rebuilt", account.Status.State)
+	case !utils.AccountCRHasIAMUserIDLabel(account):
+		return "the account never got to its IAM setup"
+	}
+	return ""
+}
+
+// removeRebuildIAMAnnotation removes the rebuild-iam annotation once the request is handled
+func (r *AccountReconciler) removeRebuildIAMAnnotation(account *awsv1alpha1.Account) error {
+	return utils.UpdateWithRetry(r.Client, account, func() error {
+		delete(account.Annotations, awsv1alpha1.RebuildIAMAnnotation)
+		return nil
+	})
+}
@@ -0,0 +1,78 @@
+package account
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("IAM rebuild requests", func() {
+	var account *awsv1alpha1.Account
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "osd-creds-mgmt-aaabbb",
+				Namespace:   awsv1alpha1.AccountCrNamespace,
+				Labels:      map[string]string{awsv1alpha1.IAMUserIDLabel: "abcdef"},
+				Annotations: map[string]string{awsv1alpha1.RebuildIAMAnnotation: "OHSS-1234"},
+			},
+			Spec: awsv1alpha1.AccountSpec{
+				AwsAccountID:  "123456789012",
+				IAMUserSecret: "osd-creds-mgmt-aaabbb-secret",
+			},
+			Status: awsv1alpha1.AccountStatus{State: AccountReady},
+		}
+	})
+
+	// refuse handles the request, which has to be refused before any AWS call is made, and returns the event
+	refuse := func() string {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account).Build()
+		recorder := record.NewFakeRecorder(1)
+		r := &AccountReconciler{Client: kubeClient, Scheme: scheme.Scheme, recorder: recorder}
+		handled, err := r.handleRebuildIAMRequest(testutils.NewTestLogger().Logger(), account, nil, awsv1alpha1.AccountCrNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(handled).To(BeTrue())
+
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		Expect(updated.Annotations).NotTo(HaveKey(awsv1alpha1.RebuildIAMAnnotation))
+		Expect(updated.Status.State).To(Equal(account.Status.State))
+		Expect(recorder.Events).To(HaveLen(1))
+		return <-recorder.Events
+	}
+
+	It("Refuses STS accounts", func() {
+		account.Spec.ManualSTSMode = true
+		Expect(refuse()).To(ContainSubstring("CCS and STS accounts"))
+	})
+
+	It("Refuses accounts still being created", func() {
+		account.Status.State = string(awsv1alpha1.AccountCreating)
+		Expect(refuse()).To(ContainSubstring("only the IAM setup of Ready and Failed accounts"))
+	})
+
+	It("Refuses accounts that never got to their IAM setup", func() {
+		account.Status.State = AccountFailed
+		account.Labels = nil
+		Expect(refuse()).To(ContainSubstring("never got to its IAM setup"))
+	})
+
+	It("Ignores accounts without the annotation", func() {
+		account.Annotations = nil
+		r := &AccountReconciler{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account).Build()}
+		handled, err := r.handleRebuildIAMRequest(testutils.NewTestLogger().Logger(), account, nil, awsv1alpha1.AccountCrNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(handled).To(BeFalse())
+	})
+})
@@ -0,0 +1,54 @@
+package account
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+)
+
+// dryRunSucceededCode is the error code EC2 returns when a dry run request would have succeeded
+const dryRunSucceededCode = "DryRunOperation"
+
+// checkRegionCapability verifies through the EC2 API that a region can run instances, without launching
+// any: the region must have an available availability zone, and a dry run of launching the instance
+// region initialization would use must be authorized. The service quotas of the region are requested by
+// the caller, like for the regions initialized with an instance.
+func checkRegionCapability(reqLogger logr.Logger, awsClient awsclient.Client, region string, instanceInfo awsv1alpha1.AmiSpec) error {
+	zones, err := awsClient.DescribeAvailabilityZones(&ec2.DescribeAvailabilityZonesInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("state"),
+			Values: []*string{aws.String(ec2.AvailabilityZoneStateAvailable)},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to describe the availability zones of region %s: %w", region, err)
+	}
+	if len(zones.AvailabilityZones) == 0 {
+		return fmt.Errorf("region %s has no available availability zone", region)
+	}
+
+	if instanceInfo.Ami == "" || instanceInfo.InstanceType == "" {
+		return fmt.Errorf("no instance type or AMI available in region %s", region)
+	}
+	_, err = awsClient.RunInstances(&ec2.RunInstancesInput{
+		DryRun:       aws.Bool(true),
+		ImageId:      aws.String(instanceInfo.Ami),
+		InstanceType: aws.String(instanceInfo.InstanceType),
+		MinCount:     aws.Int64(1),
+		MaxCount:     aws.Int64(1),
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dryRunSucceededCode {
+		reqLogger.Info("Region capabilities verified", "region", region, "availabilityZones", len(zones.AvailabilityZones))
+		return nil
+	}
+	if err == nil {
+		// A dry run never launches an instance, EC2 always answers with an error
+		return fmt.Errorf("unexpected answer to the dry run of an instance in region %s", region)
+	}
+	return fmt.Errorf("dry run of an instance failed in region %s: %w", region, err)
+}
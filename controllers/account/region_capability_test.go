@@ -0,0 +1,67 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCheckRegionCapability(t *testing.T) {
+	instanceInfo := awsv1alpha1.AmiSpec{Ami: "ami-075ed2fafb0c1aa68", InstanceType: T3INSTANCETYPE}
+	availableZones := &ec2.DescribeAvailabilityZonesOutput{
+		AvailabilityZones: []*ec2.AvailabilityZone{{ZoneName: aws.String("us-east-1a")}},
+	}
+
+	tests := []struct {
+		name      string
+		zones     *ec2.DescribeAvailabilityZonesOutput
+		dryRunErr error
+		expectErr bool
+	}{
+		{
+			name:      "Region passes when the dry run would have succeeded",
+			zones:     availableZones,
+			dryRunErr: awserr.New(dryRunSucceededCode, "Request would have succeeded", nil),
+		},
+		{
+			name:      "Region fails when the dry run is refused",
+			zones:     availableZones,
+			dryRunErr: awserr.New("UnauthorizedOperation", "You are not authorized", nil),
+			expectErr: true,
+		},
+		{
+			name:      "Region fails without an available availability zone",
+			zones:     &ec2.DescribeAvailabilityZonesOutput{},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAWSClient := mock.NewMockClient(ctrl)
+			mockAWSClient.EXPECT().DescribeAvailabilityZones(gomock.Any()).Return(tt.zones, nil)
+			if len(tt.zones.AvailabilityZones) > 0 {
+				mockAWSClient.EXPECT().RunInstances(gomock.Any()).DoAndReturn(func(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
+					assert.True(t, aws.BoolValue(input.DryRun))
+					return nil, tt.dryRunErr
+				})
+			}
+
+			err := checkRegionCapability(testutils.NewTestLogger().Logger(), mockAWSClient, "us-east-1", instanceInfo)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
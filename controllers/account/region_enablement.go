@@ -11,6 +11,7 @@ import (
 	"github.com/go-logr/logr"
 	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
 	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -113,7 +114,12 @@ func GetOptInRegionStatus(reqLogger logr.Logger, awsClientBuilder awsclient.IBui
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	err = client.Status().Update(context.TODO(), currentAcctInstance)
+	// The requests were updated from AWS, only their statuses are written again after a conflict
+	optInRegions := currentAcctInstance.Status.OptInRegions
+	err = utils.UpdateStatusWithRetry(client, currentAcctInstance, func() error {
+		currentAcctInstance.Status.OptInRegions = optInRegions
+		return nil
+	})
 	if err != nil {
 		return reconcile.Result{}, err
 	}
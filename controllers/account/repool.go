@@ -0,0 +1,136 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// handleRepoolRequest puts an Account annotated with RepoolAnnotation back into the pool, after it was cleaned up
+// by hand. It's released from its claim like a reused account, and goes through the verification again before it's
+// Ready. The request is refused, with an event saying why, when the account can't safely be handed out again. The
+// annotation is removed either way, it returns true if there was one.
+func (r *AccountReconciler) handleRepoolRequest(reqLogger logr.Logger, account *awsv1alpha1.Account) (bool, error) {
+	reason, requested := account.GetAnnotations()[awsv1alpha1.RepoolAnnotation]
+	if !requested {
+		return false, nil
+	}
+
+	refusal, err := r.repoolRefusal(account)
+	if err != nil {
+		return true, err
+	}
+	if refusal != "" {
+		reqLogger.Info("Refusing to put the account back into the pool", "reason", refusal)
+		utils.RecordEvent(r.recorder, account, corev1.EventTypeWarning, utils.EventReasonRepoolRefused, "Account not put back into the pool: %s", refusal)
+		return true, r.removeRepoolAnnotation(account)
+	}
+
+	previousClaim := ""
+	if account.HasClaimLink() {
+		previousClaim = account.Spec.ClaimLinkNamespace + "/" + account.Spec.ClaimLink
+	}
+	err = utils.UpdateWithRetry(r.Client, account, func() error {
+		account.Spec.ClaimLink = ""
+		account.Spec.ClaimLinkNamespace = ""
+		account.Spec.ClaimLinkUID = ""
+		account.Spec.ClusterID = ""
+		delete(account.Annotations, awsv1alpha1.RepoolAnnotation)
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to release the account from its claim")
+		return true, err
+	}
+
+	// The verification marks the account Ready again, the credentials handed out with the claim are rotated
+	err = utils.UpdateStatusWithRetry(r.Client, account, func() error {
+		account.Status.RotateConsoleCredentials = true
+		account.Status.RotateCredentials = true
+		account.Status.Claimed = false
+		account.Status.Reused = true
+		account.Status.ClusterDeployment = nil
+		utils.SetAccountStatus(account, "Account put back into the pool, pending verification", awsv1alpha1.AccountPendingVerification, AccountPendingVerification)
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to set the account pending verification")
+		return true, err
+	}
+
+	reqLogger.Info("Account put back into the pool", "previousClaim", previousClaim, "reason", reason)
+	utils.RecordEvent(r.recorder, account, corev1.EventTypeNormal, utils.EventReasonAccountRepooled, "Account put back into the pool, pending verification: %s", reason)
+	return true, nil
+}
+
+// repoolRefusal returns why the account can't be put back into the pool, empty if it can
+func (r *AccountReconciler) repoolRefusal(account *awsv1alpha1.Account) (string, error) {
+	switch {
+	case account.IsBYOC() || account.IsSTS():
+		return "CCS and STS accounts aren't reused", nil
+	case !account.IsReady() && !account.IsFailed():
+		return fmt.Sprintf("the account is %s, only Ready and Failed accounts can be put back", account.Status.State), nil
+	case !account.HasClaimLink():
+		return r.cleanupRefusal(account)
+	}
+
+	claim := &awsv1alpha1.AccountClaim{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: account.Spec.ClaimLink, Namespace: account.Spec.ClaimLinkNamespace}, claim)
+	if err == nil {
+		return fmt.Sprintf("its AccountClaim %s/%s still exists", claim.Namespace, claim.Name), nil
+	}
+	if !k8serr.IsNotFound(err) {
+		return "", err
+	}
+
+	// The secrets created for the claim hold credentials of the account
+	secrets := &corev1.SecretList{}
+	err = r.Client.List(context.TODO(), secrets, client.MatchingLabels{awsv1alpha1.SecretOwnerKindLabel: "AccountClaim"})
+	if err != nil {
+		return "", err
+	}
+	remaining := []string{}
+	for _, secret := range secrets.Items {
+		if secret.Annotations[awsv1alpha1.SecretOwnerNameAnnotation] == account.Spec.ClaimLink &&
+			secret.Annotations[awsv1alpha1.SecretOwnerNamespaceAnnotation] == account.Spec.ClaimLinkNamespace {
+			remaining = append(remaining, secret.Namespace+"/"+secret.Name)
+		}
+	}
+	if len(remaining) > 0 {
+		return fmt.Sprintf("the secrets %s of its claim remain", strings.Join(remaining, ", ")), nil
+	}
+	return r.cleanupRefusal(account)
+}
+
+// cleanupRefusal refuses to put back an account whose AccountCleanup is still running, it would release the account
+// itself
+func (r *AccountReconciler) cleanupRefusal(account *awsv1alpha1.Account) (string, error) {
+	cleanups := &awsv1alpha1.AccountCleanupList{}
+	err := r.Client.List(context.TODO(), cleanups, client.InNamespace(awsv1alpha1.AccountCrNamespace))
+	if err != nil {
+		return "", err
+	}
+	for _, cleanup := range cleanups.Items {
+		if cleanup.Spec.AccountLink == account.Name && !cleanup.IsFinished() {
+			return fmt.Sprintf("its AccountCleanup %s is still running", cleanup.Name), nil
+		}
+	}
+	return "", nil
+}
+
+// removeRepoolAnnotation removes the repool annotation of a refused request
+func (r *AccountReconciler) removeRepoolAnnotation(account *awsv1alpha1.Account) error {
+	return utils.UpdateWithRetry(r.Client, account, func() error {
+		delete(account.Annotations, awsv1alpha1.RepoolAnnotation)
+		return nil
+	})
+}
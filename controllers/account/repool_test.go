@@ -0,0 +1,97 @@
+package account
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Repool requests", func() {
+	var account *awsv1alpha1.Account
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "osd-creds-mgmt-aaabbb",
+				Namespace:   awsv1alpha1.AccountCrNamespace,
+				Annotations: map[string]string{awsv1alpha1.RepoolAnnotation: "cleaned up in OHSS-1234"},
+			},
+			Spec: awsv1alpha1.AccountSpec{
+				AwsAccountID:       "123456789012",
+				ClaimLink:          "mycluster",
+				ClaimLinkNamespace: "uhc-production-1234",
+				ClaimLinkUID:       "1234",
+			},
+			Status: awsv1alpha1.AccountStatus{State: AccountFailed, Claimed: true},
+		}
+	})
+
+	handle := func(objs ...client.Object) (*awsv1alpha1.Account, bool) {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(objs, account)...).Build()
+		r := &AccountReconciler{Client: kubeClient, Scheme: scheme.Scheme}
+		handled, err := r.handleRepoolRequest(testutils.NewTestLogger().Logger(), account)
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		Expect(updated.Annotations).NotTo(HaveKey(awsv1alpha1.RepoolAnnotation))
+		return updated, handled
+	}
+
+	It("Releases the account and sets it pending verification", func() {
+		updated, handled := handle()
+		Expect(handled).To(BeTrue())
+		Expect(updated.Spec.ClaimLink).To(BeEmpty())
+		Expect(updated.Spec.ClaimLinkNamespace).To(BeEmpty())
+		Expect(updated.Spec.ClaimLinkUID).To(BeEmpty())
+		Expect(updated.Status.State).To(Equal(AccountPendingVerification))
+		Expect(updated.Status.Claimed).To(BeFalse())
+		Expect(updated.Status.Reused).To(BeTrue())
+		Expect(updated.Status.RotateCredentials).To(BeTrue())
+	})
+
+	It("Refuses while the claim exists", func() {
+		claim := &awsv1alpha1.AccountClaim{ObjectMeta: metav1.ObjectMeta{Name: "mycluster", Namespace: "uhc-production-1234"}}
+		updated, handled := handle(claim)
+		Expect(handled).To(BeTrue())
+		Expect(updated.Spec.ClaimLink).To(Equal("mycluster"))
+		Expect(updated.Status.State).To(Equal(AccountFailed))
+	})
+
+	It("Refuses while secrets of the claim remain", func() {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "mycluster-secret",
+			Namespace: "uhc-production-1234",
+			Labels:    map[string]string{awsv1alpha1.SecretOwnerKindLabel: "AccountClaim"},
+			Annotations: map[string]string{
+				awsv1alpha1.SecretOwnerNameAnnotation:      "mycluster",
+				awsv1alpha1.SecretOwnerNamespaceAnnotation: "uhc-production-1234",
+			},
+		}}
+		updated, _ := handle(secret)
+		Expect(updated.Spec.ClaimLink).To(Equal("mycluster"))
+		Expect(updated.Status.State).To(Equal(AccountFailed))
+	})
+
+	It("Refuses CCS accounts", func() {
+		account.Spec.BYOC = true
+		updated, _ := handle()
+		Expect(updated.Spec.ClaimLink).To(Equal("mycluster"))
+	})
+
+	It("Ignores accounts without the annotation", func() {
+		account.Annotations = nil
+		_, handled := handle()
+		Expect(handled).To(BeFalse())
+	})
+})
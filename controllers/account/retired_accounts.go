@@ -0,0 +1,106 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// retiredAccountCollectionInterval is how often retired CCS accounts past their retention are looked for
+	retiredAccountCollectionInterval = time.Hour
+
+	// ccsAccountRetentionDisabled means CCS Account CRs are deleted with their AccountClaim
+	ccsAccountRetentionDisabled = time.Duration(0)
+)
+
+// RetiredAccountCollector periodically deletes the retired CCS Account CRs whose retention has expired
+type RetiredAccountCollector struct {
+	client client.Client
+}
+
+// NewRetiredAccountCollector returns a new RetiredAccountCollector
+func NewRetiredAccountCollector(client client.Client) *RetiredAccountCollector {
+	return &RetiredAccountCollector{client: client}
+}
+
+// Start looks for expired retired accounts every retiredAccountCollectionInterval, until the operator is stopped
+func (c *RetiredAccountCollector) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the retired account collector")
+	for {
+		_, err := c.CollectRetiredAccounts(log)
+		if err != nil {
+			log.Error(err, "Failed to collect retired accounts")
+		}
+
+		select {
+		case <-time.After(retiredAccountCollectionInterval):
+		case <-stopCh.Done():
+			log.Info("Stopping the retired account collector")
+			return
+		}
+	}
+}
+
+// GetCCSAccountRetention reads how long retired CCS Account CRs are kept from the configmap. CCS Account
+// CRs aren't retired when it's not set.
+func GetCCSAccountRetention(log logr.Logger, kubeClient client.Client) time.Duration {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		log.Error(err, "Unable to get the CCS account retention from the configmap, not retaining CCS accounts")
+		return ccsAccountRetentionDisabled
+	}
+
+	value := configMap.Data[awsv1alpha1.CCSAccountRetentionConfigMapKey]
+	if value == "" {
+		return ccsAccountRetentionDisabled
+	}
+	retention, err := time.ParseDuration(value)
+	if err != nil || retention < 0 {
+		log.Error(err, "Invalid CCS account retention in configmap, not retaining CCS accounts", "value", value)
+		return ccsAccountRetentionDisabled
+	}
+	return retention
+}
+
+// CollectRetiredAccounts deletes the retired CCS accounts that have been retired for longer than the
+// configured retention and returns their names. Retired accounts are kept while the retention isn't set, in
+// case it was only unset by mistake.
+func (c *RetiredAccountCollector) CollectRetiredAccounts(log logr.Logger) ([]string, error) {
+	retention := GetCCSAccountRetention(log, c.client)
+	if retention == ccsAccountRetentionDisabled {
+		return nil, nil
+	}
+
+	accountList := &awsv1alpha1.AccountList{}
+	err := c.client.List(context.TODO(), accountList, client.InNamespace(awsv1alpha1.AccountCrNamespace))
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := []string{}
+	for i := range accountList.Items {
+		account := &accountList.Items[i]
+		if !account.IsBYOC() || !account.IsRetired() || account.IsPendingDeletion() {
+			continue
+		}
+		condition := account.GetCondition(awsv1alpha1.AccountRetired)
+		if condition == nil || time.Since(condition.LastTransitionTime.Time) < retention {
+			continue
+		}
+
+		log.Info("Deleting retired CCS account", "account", account.Name, "retiredAt", condition.LastTransitionTime)
+		err = c.client.Delete(context.TODO(), account)
+		if err != nil && !k8serr.IsNotFound(err) {
+			log.Error(err, "Failed to delete retired CCS account", "account", account.Name)
+			continue
+		}
+		deleted = append(deleted, account.Name)
+	}
+	return deleted, nil
+}
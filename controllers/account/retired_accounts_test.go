@@ -0,0 +1,76 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("RetiredAccountCollector", func() {
+	var configMap *corev1.ConfigMap
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+			Data:       map[string]string{awsv1alpha1.CCSAccountRetentionConfigMapKey: "720h"},
+		}
+	})
+
+	retiredAccount := func(name string, retiredFor time.Duration) *awsv1alpha1.Account {
+		return &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: awsv1alpha1.AccountCrNamespace},
+			Spec:       awsv1alpha1.AccountSpec{BYOC: true},
+			Status: awsv1alpha1.AccountStatus{
+				State: string(awsv1alpha1.AccountRetired),
+				Conditions: []awsv1alpha1.AccountCondition{{
+					Type:               awsv1alpha1.AccountRetired,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-retiredFor)),
+				}},
+			},
+		}
+	}
+
+	It("Deletes retired CCS accounts past their retention", func() {
+		expired := retiredAccount("expired", 31*24*time.Hour)
+		retained := retiredAccount("retained", 24*time.Hour)
+		ready := &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: awsv1alpha1.AccountCrNamespace},
+			Spec:       awsv1alpha1.AccountSpec{BYOC: true},
+			Status:     awsv1alpha1.AccountStatus{State: string(awsv1alpha1.AccountReady)},
+		}
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(configMap, expired, retained, ready).Build()
+
+		deleted, err := NewRetiredAccountCollector(kubeClient).CollectRetiredAccounts(testutils.NewTestLogger().Logger())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(ConsistOf("expired"))
+
+		err = kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(expired), &awsv1alpha1.Account{})
+		Expect(k8serr.IsNotFound(err)).To(BeTrue())
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(retained), &awsv1alpha1.Account{})).To(Succeed())
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(ready), &awsv1alpha1.Account{})).To(Succeed())
+	})
+
+	It("Keeps retired accounts while the retention isn't set", func() {
+		configMap.Data = nil
+		expired := retiredAccount("expired", 31*24*time.Hour)
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(configMap, expired).Build()
+
+		deleted, err := NewRetiredAccountCollector(kubeClient).CollectRetiredAccounts(testutils.NewTestLogger().Logger())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(BeEmpty())
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(expired), &awsv1alpha1.Account{})).To(Succeed())
+	})
+})
@@ -1,7 +1,6 @@
 package account
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -23,7 +22,7 @@ import (
 func HandleServiceQuotaRequests(reqLogger logr.Logger, awsClient awsclient.Client, quotaCode awsv1alpha1.SupportedServiceQuotas, serviceQuotaStatus *awsv1alpha1.ServiceQuotaStatus) error {
 
 	reqLogger.Info("Handling ServiceQuota Requests")
-	serviceCode, found := getServiceCode(quotaCode)
+	serviceCode, found := GetServiceCode(quotaCode)
 	if !found {
 		reqLogger.Error(fixtures.NotFound, "cannot find corresponding ServiceCode for QuotaCode", "QuotaCode", string(quotaCode))
 		return fixtures.NotFound
@@ -98,7 +97,8 @@ func HandleServiceQuotaRequests(reqLogger logr.Logger, awsClient awsclient.Clien
 	return nil
 }
 
-func getServiceCode(quotaCode awsv1alpha1.SupportedServiceQuotas) (string, bool) {
+// GetServiceCode returns the code of the AWS service the quota belongs to
+func GetServiceCode(quotaCode awsv1alpha1.SupportedServiceQuotas) (string, bool) {
 
 	servicesMap := map[awsv1alpha1.SupportedServiceQuotas]string{
 		awsv1alpha1.RunningStandardInstances:  string(awsv1alpha1.EC2ServiceQuota),
@@ -107,6 +107,7 @@ func getServiceCode(quotaCode awsv1alpha1.SupportedServiceQuotas) (string, bool)
 		awsv1alpha1.RulesPerSecurityGroup:     string(awsv1alpha1.VPCServiceQuota),
 		awsv1alpha1.VPCNetworkAclQuotaCode:    string(awsv1alpha1.VPCServiceQuota),
 		awsv1alpha1.GeneralPurposeSSD:         string(awsv1alpha1.EBSServiceQuota),
+		awsv1alpha1.VPCsPerRegionQuotaCode:    string(awsv1alpha1.VPCServiceQuota),
 	}
 
 	v, found := servicesMap[quotaCode]
@@ -392,7 +393,13 @@ func GetServiceQuotaRequest(reqLogger logr.Logger, awsClientBuilder awsclient.IB
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	err = client.Status().Update(context.TODO(), currentAcctInstance)
+	// The requests were updated from AWS, only their statuses are written again after a conflict
+	serviceQuotas := currentAcctInstance.Status.RegionalServiceQuotas
+	err = controllerutils.UpdateStatusWithRetry(client, currentAcctInstance, func() error {
+		currentAcctInstance.Status.RegionalServiceQuotas = serviceQuotas
+		failDeniedServiceQuotaRequests(currentAcctInstance)
+		return nil
+	})
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -419,11 +426,16 @@ func UpdateServiceQuotaRequests(reqLogger logr.Logger, awsClientBuilder awsclien
 		}
 	}
 
+	failDeniedServiceQuotaRequests(currentAcctInstance)
+
+	return nil
+}
+
+// failDeniedServiceQuotaRequests fails the account when one of its service quota increases was denied
+func failDeniedServiceQuotaRequests(currentAcctInstance *awsv1alpha1.Account) {
 	deniedCount, _ := currentAcctInstance.GetQuotaRequestsByStatus(awsv1alpha1.ServiceRequestDenied)
 
 	if deniedCount > 0 {
 		controllerutils.SetAccountStatus(currentAcctInstance, "ServiceQuota increase got denied", awsv1alpha1.AccountFailed, AccountFailed)
 	}
-
-	return nil
 }
@@ -0,0 +1,269 @@
+package account
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AWS only accepts a maximum session duration between one and twelve hours
+	minSREAccessSessionDuration     = time.Hour
+	maxSREAccessSessionDuration     = 12 * time.Hour
+	defaultSREAccessSessionDuration = time.Hour
+	// AWS caps the sessions of a role assumed with the credentials of another assumed role at one hour
+	maxChainedSessionDuration = time.Hour
+)
+
+// sreAccessConfig holds the settings of the role SREs assume to access non-CCS accounts
+type sreAccessConfig struct {
+	principalARN       string
+	externalID         string
+	maxSessionDuration time.Duration
+}
+
+// getSREAccessConfig reads the SRE access role settings from the operator configmap. It returns nil
+// when no SRE access principal is configured, in which case the operator doesn't manage the role.
+func getSREAccessConfig(kubeClient client.Client) (*sreAccessConfig, error) {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		return nil, err
+	}
+
+	principalARN := configMap.Data[awsv1alpha1.SREAccessARN]
+	if principalARN == "" {
+		return nil, nil
+	}
+
+	externalID := configMap.Data[awsv1alpha1.SREAccessExternalID]
+	if externalID == "" {
+		return nil, fmt.Errorf("%w: %s is required when %s is set", awsv1alpha1.ErrInvalidConfigMap, awsv1alpha1.SREAccessExternalID, awsv1alpha1.SREAccessARN)
+	}
+
+	maxSessionDuration := defaultSREAccessSessionDuration
+	if value := configMap.Data[awsv1alpha1.SREAccessMaxSessionDuration]; value != "" {
+		maxSessionDuration, err = time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid %s: %v", awsv1alpha1.ErrInvalidConfigMap, awsv1alpha1.SREAccessMaxSessionDuration, err)
+		}
+		if maxSessionDuration < minSREAccessSessionDuration || maxSessionDuration > maxSREAccessSessionDuration {
+			return nil, fmt.Errorf("%w: %s must be between %s and %s", awsv1alpha1.ErrInvalidConfigMap, awsv1alpha1.SREAccessMaxSessionDuration, minSREAccessSessionDuration, maxSREAccessSessionDuration)
+		}
+	}
+
+	return &sreAccessConfig{
+		principalARN:       principalARN,
+		externalID:         externalID,
+		maxSessionDuration: maxSessionDuration,
+	}, nil
+}
+
+// sreAccessTrustPolicy returns the assume role policy allowing the SRE principal to assume the role
+// only when it presents the external ID, and the operator to assume it through the
+// OrganizationAccountAccessRole of the account. Both may set a source identity and session tags so
+// the sessions are attributed to the SRE who requested them.
+func sreAccessTrustPolicy(sreAccess *sreAccessConfig, account *awsv1alpha1.Account) (string, error) {
+	actions := []string{"sts:AssumeRole", "sts:SetSourceIdentity", "sts:TagSession"}
+	assumeRolePolicyDoc := struct {
+		Version   string
+		Statement []awsStatement
+	}{
+		Version: "2012-10-17",
+		Statement: []awsStatement{
+			{
+				Effect: "Allow",
+				Action: actions,
+				Principal: &awsv1alpha1.Principal{
+					AWS: []string{sreAccess.principalARN},
+				},
+				Condition: &awsv1alpha1.Condition{
+					StringEquals: map[string]string{"sts:ExternalId": sreAccess.externalID},
+				},
+			},
+			{
+				Effect: "Allow",
+				Action: actions,
+				Principal: &awsv1alpha1.Principal{
+					AWS: []string{config.GetIAMArn(account.Spec.AwsAccountID, config.AwsResourceTypeRole, awsv1alpha1.AccountOperatorIAMRole)},
+				},
+			},
+		},
+	}
+
+	jsonAssumeRolePolicyDoc, err := json.Marshal(&assumeRolePolicyDoc)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonAssumeRolePolicyDoc), nil
+}
+
+// GetSREAccessRoleName returns the name of the SRE access role for the given account
+func GetSREAccessRoleName(account *awsv1alpha1.Account) string {
+	return fmt.Sprintf("%s-%s", awsv1alpha1.ManagedOpenShiftSREAccessRole, account.Labels[awsv1alpha1.IAMUserIDLabel])
+}
+
+// getSREAccessRoleCredentials returns session credentials of the SRE access role of the account,
+// attributed to whoever requested them. The operator assumes the role through the
+// OrganizationAccountAccessRole of the account, so the session lasts at most an hour.
+func getSREAccessRoleCredentials(reqLogger logr.Logger, awsClientBuilder awsclient.IBuilder, kubeClient client.Client, awsSetupClient awsclient.Client, account *awsv1alpha1.Account, roleSessionName string, sessionDuration time.Duration) (*sts.AssumeRoleOutput, error) {
+	if sessionDuration > maxChainedSessionDuration {
+		sessionDuration = maxChainedSessionDuration
+	}
+
+	awsAssumedRoleClient, _, err := stsclient.HandleRoleAssumption(reqLogger, awsClientBuilder, account, kubeClient, awsSetupClient, "", awsv1alpha1.AccountOperatorIAMRole, "")
+	if err != nil {
+		return nil, err
+	}
+
+	roleArn := config.GetIAMArn(account.Spec.AwsAccountID, config.AwsResourceTypeRole, GetSREAccessRoleName(account))
+	return stsclient.GetSREAccessCredentials(reqLogger, awsAssumedRoleClient, roleArn, roleSessionName, int64(sessionDuration.Seconds()), stsclient.SREAccessSessionIdentity(account))
+}
+
+// EnsureSREAccessRole creates the role SREs assume to access a non-CCS account. If the role already
+// exists, its trust policy, maximum session duration and policy are brought back in line with the
+// configmap, the role is only written to when they drifted. SREs get short-lived credentials from
+// this role instead of a long-lived IAM user.
+func EnsureSREAccessRole(reqLogger logr.Logger, kubeClient client.Client, awsClient awsclient.Client, account *awsv1alpha1.Account, tags []*iam.Tag) error {
+	sreAccess, err := getSREAccessConfig(kubeClient)
+	if err != nil {
+		reqLogger.Error(err, "Unable to get the SRE access role settings from the configmap")
+		return err
+	}
+	if sreAccess == nil {
+		reqLogger.Info("No SRE access principal configured, not managing the SRE access role")
+		return nil
+	}
+
+	roleName := GetSREAccessRoleName(account)
+	reqLogger = reqLogger.WithValues("role", roleName)
+
+	trustPolicy, err := sreAccessTrustPolicy(sreAccess, account)
+	if err != nil {
+		return err
+	}
+	maxSessionDuration := int64(sreAccess.maxSessionDuration.Seconds())
+
+	existingRole, err := GetExistingRole(reqLogger, roleName, awsClient)
+	if err != nil {
+		return err
+	}
+
+	if (*existingRole == iam.GetRoleOutput{}) {
+		reqLogger.Info("Creating SRE access role")
+		_, err = awsClient.CreateRole(&iam.CreateRoleInput{
+			Tags:                     tags,
+			RoleName:                 aws.String(roleName),
+			Description:              aws.String("SRE access to the account"),
+			AssumeRolePolicyDocument: aws.String(trustPolicy),
+			MaxSessionDuration:       aws.Int64(maxSessionDuration),
+		})
+		if err != nil {
+			reqLogger.Error(err, "Failed to create SRE access role")
+			return err
+		}
+	} else {
+		reqLogger.Info("Verifying SRE access role")
+		equal, err := PolicyDocumentsEqual(aws.StringValue(existingRole.Role.AssumeRolePolicyDocument), trustPolicy)
+		if err != nil || !equal {
+			reqLogger.Info("Updating SRE access role trust policy")
+			_, err = awsClient.UpdateAssumeRolePolicy(&iam.UpdateAssumeRolePolicyInput{
+				RoleName:       aws.String(roleName),
+				PolicyDocument: aws.String(trustPolicy),
+			})
+			if err != nil {
+				reqLogger.Error(err, "Failed to update SRE access role trust policy")
+				return err
+			}
+		}
+
+		permissionsBoundary, err := utils.GetPermissionsBoundaryARN(kubeClient)
+		if err != nil {
+			return err
+		}
+		err = awsclient.EnsureRolePermissionsBoundary(reqLogger, awsClient, existingRole.Role, permissionsBoundary)
+		if err != nil {
+			reqLogger.Error(err, "Failed to set SRE access role permissions boundary")
+			return err
+		}
+
+		if aws.Int64Value(existingRole.Role.MaxSessionDuration) != maxSessionDuration {
+			reqLogger.Info("Updating SRE access role maximum session duration", "maxSessionDuration", sreAccess.maxSessionDuration.String())
+			_, err = awsClient.UpdateRole(&iam.UpdateRoleInput{
+				RoleName:           aws.String(roleName),
+				MaxSessionDuration: aws.Int64(maxSessionDuration),
+			})
+			if err != nil {
+				reqLogger.Error(err, "Failed to update SRE access role maximum session duration")
+				return err
+			}
+		}
+	}
+
+	adminAccessArn := config.GetIAMArn("aws", config.AwsResourceTypePolicy, config.AwsResourceIDAdministratorAccessRole)
+	// The policy is only attached again when it's missing, so an audit of a role in sync doesn't write to it
+	if (*existingRole != iam.GetRoleOutput{}) {
+		policyList, err := GetAttachedPolicies(reqLogger, roleName, awsClient)
+		if err != nil {
+			return err
+		}
+		for _, policy := range policyList.AttachedPolicies {
+			if aws.StringValue(policy.PolicyArn) == adminAccessArn {
+				return nil
+			}
+		}
+	}
+	return attachAndEnsureRolePolicies(reqLogger, awsClient, roleName, adminAccessArn)
+}
+
+// PolicyDocumentsEqual compares a policy document returned by IAM, which is URL encoded and may
+// have single element lists collapsed to their only element, with the expected document
+func PolicyDocumentsEqual(current string, expected string) (bool, error) {
+	decoded, err := url.QueryUnescape(current)
+	if err != nil {
+		return false, err
+	}
+
+	var currentDoc, expectedDoc interface{}
+	if err := json.Unmarshal([]byte(decoded), &currentDoc); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal([]byte(expected), &expectedDoc); err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(normalizePolicyDocument(currentDoc), normalizePolicyDocument(expectedDoc)), nil
+}
+
+func normalizePolicyDocument(doc interface{}) interface{} {
+	switch value := doc.(type) {
+	case []interface{}:
+		if len(value) == 1 {
+			return normalizePolicyDocument(value[0])
+		}
+		normalized := make([]interface{}, 0, len(value))
+		for _, element := range value {
+			normalized = append(normalized, normalizePolicyDocument(element))
+		}
+		return normalized
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(value))
+		for key, element := range value {
+			normalized[key] = normalizePolicyDocument(element)
+		}
+		return normalized
+	default:
+		return value
+	}
+}
@@ -0,0 +1,188 @@
+package account
+
+import (
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("SRE access role", func() {
+	var (
+		nullLogger     logr.Logger
+		mockAWSClient  *mock.MockClient
+		ctrl           *gomock.Controller
+		configMap      *corev1.ConfigMap
+		account        *awsv1alpha1.Account
+		roleName       string
+		adminAccessArn string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		nullLogger = testutils.NewTestLogger().Logger()
+		mockAWSClient = mock.NewMockClient(ctrl)
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      awsv1alpha1.DefaultConfigMap,
+				Namespace: awsv1alpha1.AccountCrNamespace,
+			},
+			Data: map[string]string{
+				awsv1alpha1.SREAccessARN:                "arn:aws:iam::123456789012:role/sre-jump",
+				awsv1alpha1.SREAccessExternalID:         "external-id",
+				awsv1alpha1.SREAccessMaxSessionDuration: "2h",
+			},
+		}
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osd-creds-mgmt-abcdef",
+				Namespace: awsv1alpha1.AccountCrNamespace,
+				Labels:    map[string]string{awsv1alpha1.IAMUserIDLabel: "abcdef"},
+			},
+			Spec: awsv1alpha1.AccountSpec{AwsAccountID: "111111111111"},
+		}
+		roleName = "ManagedOpenShift-SRE-Access-abcdef"
+		adminAccessArn = config.GetIAMArn("aws", config.AwsResourceTypePolicy, config.AwsResourceIDAdministratorAccessRole)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	ensure := func() error {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(configMap).Build()
+		return EnsureSREAccessRole(nullLogger, kubeClient, mockAWSClient, account, nil)
+	}
+
+	expectAdminPolicy := func() {
+		mockAWSClient.EXPECT().AttachRolePolicy(&iam.AttachRolePolicyInput{
+			RoleName:  aws.String(roleName),
+			PolicyArn: aws.String(adminAccessArn),
+		}).Return(&iam.AttachRolePolicyOutput{}, nil)
+		mockAWSClient.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{
+			AttachedPolicies: []*iam.AttachedPolicy{{PolicyArn: aws.String(adminAccessArn)}},
+		}, nil)
+	}
+
+	expectAdminPolicyAttached := func() {
+		mockAWSClient.EXPECT().ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{RoleName: aws.String(roleName)}).Return(&iam.ListAttachedRolePoliciesOutput{
+			AttachedPolicies: []*iam.AttachedPolicy{{PolicyArn: aws.String(adminAccessArn)}},
+		}, nil)
+	}
+
+	It("Does nothing when no SRE access principal is configured", func() {
+		delete(configMap.Data, awsv1alpha1.SREAccessARN)
+		Expect(ensure()).To(Succeed())
+	})
+
+	It("Requires an external ID", func() {
+		delete(configMap.Data, awsv1alpha1.SREAccessExternalID)
+		Expect(ensure()).To(MatchError(awsv1alpha1.ErrInvalidConfigMap))
+	})
+
+	It("Rejects a session duration AWS doesn't allow", func() {
+		configMap.Data[awsv1alpha1.SREAccessMaxSessionDuration] = "13h"
+		Expect(ensure()).To(MatchError(awsv1alpha1.ErrInvalidConfigMap))
+	})
+
+	It("Creates the role with the external ID and session duration", func() {
+		mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "Role does not exist", nil))
+		mockAWSClient.EXPECT().CreateRole(gomock.Any()).DoAndReturn(func(input *iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+			Expect(*input.RoleName).To(Equal(roleName))
+			Expect(*input.MaxSessionDuration).To(Equal(int64(7200)))
+			Expect(*input.AssumeRolePolicyDocument).To(ContainSubstring(`"sts:ExternalId":"external-id"`))
+			Expect(*input.AssumeRolePolicyDocument).To(ContainSubstring("arn:aws:iam::123456789012:role/sre-jump"))
+			Expect(*input.AssumeRolePolicyDocument).To(ContainSubstring("arn:aws:iam::111111111111:role/OrganizationAccountAccessRole"))
+			Expect(*input.AssumeRolePolicyDocument).To(ContainSubstring("sts:SetSourceIdentity"))
+			return &iam.CreateRoleOutput{Role: &iam.Role{RoleName: input.RoleName}}, nil
+		})
+		expectAdminPolicy()
+		Expect(ensure()).To(Succeed())
+	})
+
+	It("Leaves a role matching the configmap unchanged", func() {
+		// IAM returns the document URL encoded, with single element lists collapsed
+		document := `{"Version":"2012-10-17","Statement":[` +
+			`{"Effect":"Allow","Action":["sts:AssumeRole","sts:SetSourceIdentity","sts:TagSession"],"Principal":{"AWS":"arn:aws:iam::123456789012:role/sre-jump"},"Condition":{"StringEquals":{"sts:ExternalId":"external-id"}}},` +
+			`{"Effect":"Allow","Action":["sts:AssumeRole","sts:SetSourceIdentity","sts:TagSession"],"Principal":{"AWS":"arn:aws:iam::111111111111:role/OrganizationAccountAccessRole"}}]}`
+		mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{Role: &iam.Role{
+			RoleName:                 aws.String(roleName),
+			AssumeRolePolicyDocument: aws.String(url.QueryEscape(document)),
+			MaxSessionDuration:       aws.Int64(7200),
+		}}, nil)
+		expectAdminPolicyAttached()
+		Expect(ensure()).To(Succeed())
+	})
+
+	It("Sets the configured permissions boundary on an existing role", func() {
+		configMap.Data[awsv1alpha1.PermissionsBoundaryConfigMapKey] = "arn:aws:iam::aws:policy/PowerUserAccess"
+		document := `{"Version":"2012-10-17","Statement":[` +
+			`{"Effect":"Allow","Action":["sts:AssumeRole","sts:SetSourceIdentity","sts:TagSession"],"Principal":{"AWS":["arn:aws:iam::123456789012:role/sre-jump"]},"Condition":{"StringEquals":{"sts:ExternalId":"external-id"}}},` +
+			`{"Effect":"Allow","Action":["sts:AssumeRole","sts:SetSourceIdentity","sts:TagSession"],"Principal":{"AWS":["arn:aws:iam::111111111111:role/OrganizationAccountAccessRole"]}}]}`
+		mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{Role: &iam.Role{
+			RoleName:                 aws.String(roleName),
+			AssumeRolePolicyDocument: aws.String(url.QueryEscape(document)),
+			MaxSessionDuration:       aws.Int64(7200),
+		}}, nil)
+		mockAWSClient.EXPECT().PutRolePermissionsBoundary(&iam.PutRolePermissionsBoundaryInput{
+			RoleName:            aws.String(roleName),
+			PermissionsBoundary: aws.String("arn:aws:iam::aws:policy/PowerUserAccess"),
+		}).Return(&iam.PutRolePermissionsBoundaryOutput{}, nil)
+		expectAdminPolicyAttached()
+		Expect(ensure()).To(Succeed())
+	})
+
+	It("Restores a modified trust policy and session duration", func() {
+		document := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":"*"}}]}`
+		mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{Role: &iam.Role{
+			RoleName:                 aws.String(roleName),
+			AssumeRolePolicyDocument: aws.String(url.QueryEscape(document)),
+			MaxSessionDuration:       aws.Int64(43200),
+		}}, nil)
+		mockAWSClient.EXPECT().UpdateAssumeRolePolicy(gomock.Any()).DoAndReturn(func(input *iam.UpdateAssumeRolePolicyInput) (*iam.UpdateAssumeRolePolicyOutput, error) {
+			Expect(*input.PolicyDocument).To(ContainSubstring(`"sts:ExternalId":"external-id"`))
+			return &iam.UpdateAssumeRolePolicyOutput{}, nil
+		})
+		mockAWSClient.EXPECT().UpdateRole(&iam.UpdateRoleInput{
+			RoleName:           aws.String(roleName),
+			MaxSessionDuration: aws.Int64(7200),
+		}).Return(&iam.UpdateRoleOutput{}, nil)
+		expectAdminPolicyAttached()
+		Expect(ensure()).To(Succeed())
+	})
+
+	It("Attaches the policy detached from the role", func() {
+		document := `{"Version":"2012-10-17","Statement":[` +
+			`{"Effect":"Allow","Action":["sts:AssumeRole","sts:SetSourceIdentity","sts:TagSession"],"Principal":{"AWS":"arn:aws:iam::123456789012:role/sre-jump"},"Condition":{"StringEquals":{"sts:ExternalId":"external-id"}}},` +
+			`{"Effect":"Allow","Action":["sts:AssumeRole","sts:SetSourceIdentity","sts:TagSession"],"Principal":{"AWS":"arn:aws:iam::111111111111:role/OrganizationAccountAccessRole"}}]}`
+		mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{Role: &iam.Role{
+			RoleName:                 aws.String(roleName),
+			AssumeRolePolicyDocument: aws.String(url.QueryEscape(document)),
+			MaxSessionDuration:       aws.Int64(7200),
+		}}, nil)
+		gomock.InOrder(
+			mockAWSClient.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{}, nil),
+			mockAWSClient.EXPECT().AttachRolePolicy(&iam.AttachRolePolicyInput{
+				RoleName:  aws.String(roleName),
+				PolicyArn: aws.String(adminAccessArn),
+			}).Return(&iam.AttachRolePolicyOutput{}, nil),
+			mockAWSClient.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{
+				AttachedPolicies: []*iam.AttachedPolicy{{PolicyArn: aws.String(adminAccessArn)}},
+			}, nil),
+		)
+		Expect(ensure()).To(Succeed())
+	})
+})
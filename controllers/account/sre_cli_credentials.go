@@ -0,0 +1,215 @@
+package account
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// sreCLICredentialsCheckInterval is how often the expiry of the SRE CLI credentials is checked
+	sreCLICredentialsCheckInterval = 5 * time.Minute
+	// sreCLICredentialsRefreshMargin is how long before they expire the SRE CLI credentials are renewed
+	sreCLICredentialsRefreshMargin = 15 * time.Minute
+	// sreCLICredentialsSessionDuration is how long the SRE CLI credentials are valid for
+	sreCLICredentialsSessionDuration = time.Hour
+
+	sreCLICredentialsRefresher   = "sre-cli-credentials-refresher"
+	sreCLICredentialsSuffix      = "-sre-cli-credentials" // #nosec G101 -- This is a false positive
+	sreCLICredentialsSessionName = "SRE-CLI"
+	secretSessionToken           = "aws_session_token" // #nosec G101 -- This is a false positive
+	secretExpiration             = "expiration"
+)
+
+// SRECLICredentialsRefresher publishes short-lived STS session credentials of the SRE access role of
+// Ready non-CCS accounts in their <account>-sre-cli-credentials secret for SRE break-glass access, and
+// renews them before they expire. It only runs when enabled in the configmap and the SRE access role
+// is configured, the secrets are deleted otherwise.
+type SRECLICredentialsRefresher struct {
+	client           client.Client
+	awsClientBuilder awsclient.IBuilder
+}
+
+// NewSRECLICredentialsRefresher returns a new SRECLICredentialsRefresher
+func NewSRECLICredentialsRefresher(client client.Client, awsClientBuilder awsclient.IBuilder) *SRECLICredentialsRefresher {
+	return &SRECLICredentialsRefresher{
+		client:           client,
+		awsClientBuilder: awsClientBuilder,
+	}
+}
+
+// Start checks the SRE CLI credentials every sreCLICredentialsCheckInterval, until the operator is stopped
+func (c *SRECLICredentialsRefresher) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the SRE CLI credentials refresher")
+	for {
+		c.RefreshCredentials(log)
+
+		select {
+		case <-time.After(sreCLICredentialsCheckInterval):
+		case <-stopCh.Done():
+			log.Info("Stopping the SRE CLI credentials refresher")
+			return
+		}
+	}
+}
+
+// sreCLICredentialsEnabled reads from the configmap whether SRE CLI credentials are published
+func sreCLICredentialsEnabled(log logr.Logger, kubeClient client.Client) bool {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		log.Error(err, "Unable to get the configmap, not publishing SRE CLI credentials")
+		return false
+	}
+	value := configMap.Data[awsv1alpha1.SRECLICredentialsConfigMapKey]
+	if value == "" {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Error(err, "Invalid SRE CLI credentials setting in configmap, not publishing SRE CLI credentials", "value", value)
+		return false
+	}
+	// The credentials are those of the SRE access role, which only exists when its principal is configured
+	if enabled && configMap.Data[awsv1alpha1.SREAccessARN] == "" {
+		log.Info("No SRE access principal configured, not publishing SRE CLI credentials")
+		return false
+	}
+	return enabled
+}
+
+// SRECLICredentialsSecretName returns the name of the secret holding the SRE CLI credentials of an account
+func SRECLICredentialsSecretName(accountName string) string {
+	return accountName + sreCLICredentialsSuffix
+}
+
+// RefreshCredentials renews the SRE CLI credentials that are missing or about to expire. When they're
+// disabled, the existing secrets are deleted.
+func (c *SRECLICredentialsRefresher) RefreshCredentials(log logr.Logger) {
+	accountList := &awsv1alpha1.AccountList{}
+	err := c.client.List(context.TODO(), accountList, client.InNamespace(awsv1alpha1.AccountCrNamespace))
+	if err != nil {
+		log.Error(err, "Failed to list accounts")
+		return
+	}
+
+	// The secrets are listed once rather than fetched for every account
+	secretList := &corev1.SecretList{}
+	err = c.client.List(context.TODO(), secretList, client.InNamespace(awsv1alpha1.AccountCrNamespace), client.MatchingLabels{awsv1alpha1.ManagedByLabel: awsv1alpha1.ManagedByOperator})
+	if err != nil {
+		log.Error(err, "Failed to list secrets")
+		return
+	}
+	secrets := map[string]*corev1.Secret{}
+	for i := range secretList.Items {
+		if strings.HasSuffix(secretList.Items[i].Name, sreCLICredentialsSuffix) {
+			secrets[secretList.Items[i].Name] = &secretList.Items[i]
+		}
+	}
+
+	enabled := sreCLICredentialsEnabled(log, c.client)
+	// The operator client is only built when some credentials have to be renewed
+	var awsSetupClient awsclient.Client
+	for i := range accountList.Items {
+		account := &accountList.Items[i]
+		// The accounts of other shards are refreshed by other replicas
+		if !utils.InShard(account.Name) {
+			continue
+		}
+		reqLogger := log.WithValues("account", account.Name, "awsAccountID", account.Spec.AwsAccountID)
+		secret, exists := secrets[SRECLICredentialsSecretName(account.Name)]
+
+		if !enabled || !shouldAuditIAM(account) {
+			if exists {
+				reqLogger.Info("Deleting SRE CLI credentials")
+				err = c.client.Delete(context.TODO(), secret)
+				if err != nil && !k8serr.IsNotFound(err) {
+					reqLogger.Error(err, "Failed to delete SRE CLI credentials")
+				}
+			}
+			continue
+		}
+		if exists && !sreCLICredentialsExpiring(secret) {
+			continue
+		}
+
+		if awsSetupClient == nil {
+			awsSetupClient, err = c.awsClientBuilder.GetClient(sreCLICredentialsRefresher, c.client, awsclient.NewAwsClientInput{
+				SecretName: utils.AwsSecretName,
+				NameSpace:  awsv1alpha1.AccountCrNamespace,
+				AwsRegion:  config.GetDefaultRegion(),
+			})
+			if err != nil {
+				log.Error(err, "Failed building operator AWS client")
+				return
+			}
+		}
+		err = c.refreshAccountCredentials(reqLogger, awsSetupClient, account)
+		if err != nil {
+			reqLogger.Error(err, "Failed to refresh SRE CLI credentials")
+		}
+	}
+}
+
+// sreCLICredentialsExpiring returns true when the credentials in the secret expire within
+// sreCLICredentialsRefreshMargin, or their expiry is unknown
+func sreCLICredentialsExpiring(secret *corev1.Secret) bool {
+	expiration, err := time.Parse(time.RFC3339, string(secret.Data[secretExpiration]))
+	if err != nil {
+		return true
+	}
+	return time.Until(expiration) < sreCLICredentialsRefreshMargin
+}
+
+// refreshAccountCredentials gets session credentials of the SRE access role of the account and
+// stores them in the account's SRE CLI credentials secret
+func (c *SRECLICredentialsRefresher) refreshAccountCredentials(reqLogger logr.Logger, awsSetupClient awsclient.Client, account *awsv1alpha1.Account) error {
+	creds, err := getSREAccessRoleCredentials(reqLogger, c.awsClientBuilder, c.client, awsSetupClient, account, sreCLICredentialsSessionName, sreCLICredentialsSessionDuration)
+	if err != nil {
+		return err
+	}
+
+	data := map[string][]byte{
+		secretAccessKeyID:     []byte(aws.StringValue(creds.Credentials.AccessKeyId)),
+		secretSecretAccessKey: []byte(aws.StringValue(creds.Credentials.SecretAccessKey)),
+		secretSessionToken:    []byte(aws.StringValue(creds.Credentials.SessionToken)),
+		secretExpiration:      []byte(aws.TimeValue(creds.Credentials.Expiration).UTC().Format(time.RFC3339)),
+	}
+
+	secret := &corev1.Secret{}
+	err = c.client.Get(context.TODO(), types.NamespacedName{Name: SRECLICredentialsSecretName(account.Name), Namespace: account.Namespace}, secret)
+	if k8serr.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: SRECLICredentialsSecretName(account.Name), Namespace: account.Namespace},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       data,
+		}
+		err = utils.SetSecretOwner(secret, account, c.client.Scheme())
+		if err != nil {
+			return err
+		}
+		reqLogger.Info("Publishing SRE CLI credentials", "expiration", string(data[secretExpiration]))
+		return c.client.Create(context.TODO(), secret)
+	}
+	if err != nil {
+		return err
+	}
+
+	reqLogger.Info("Renewing SRE CLI credentials", "expiration", string(data[secretExpiration]))
+	return utils.UpdateWithRetry(c.client, secret, func() error {
+		secret.Data = data
+		return nil
+	})
+}
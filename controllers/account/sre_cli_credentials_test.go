@@ -0,0 +1,170 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("SRE CLI credentials", func() {
+	var (
+		nullLogger     logr.Logger
+		mockAWSClient  *mock.MockClient
+		mockMemberAWS  *mock.MockClient
+		mockAWSBuilder *mock.MockIBuilder
+		ctrl           *gomock.Controller
+		account        *awsv1alpha1.Account
+		configMap      *corev1.ConfigMap
+		secretKey      types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		ctrl = gomock.NewController(GinkgoT())
+		nullLogger = testutils.NewTestLogger().Logger()
+		mockAWSClient = mock.NewMockClient(ctrl)
+		mockMemberAWS = mock.NewMockClient(ctrl)
+		mockAWSBuilder = mock.NewMockIBuilder(ctrl)
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osd-creds-mgmt-abcdef",
+				Namespace: awsv1alpha1.AccountCrNamespace,
+				Labels:    map[string]string{awsv1alpha1.IAMUserIDLabel: "abcdef"},
+			},
+			Spec:   awsv1alpha1.AccountSpec{AwsAccountID: "123456789012"},
+			Status: awsv1alpha1.AccountStatus{State: string(awsv1alpha1.AccountReady)},
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+			Data: map[string]string{
+				awsv1alpha1.SRECLICredentialsConfigMapKey: "true",
+				awsv1alpha1.SREAccessARN:                  "arn:aws:iam::210987654321:role/sre-jump",
+			},
+		}
+		secretKey = types.NamespacedName{Name: "osd-creds-mgmt-abcdef-sre-cli-credentials", Namespace: awsv1alpha1.AccountCrNamespace}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	credentialsSecret := func(expiration time.Time) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace},
+			Data: map[string][]byte{
+				"aws_access_key_id": []byte("ASIAOLD"),
+				"expiration":        []byte(expiration.UTC().Format(time.RFC3339)),
+			},
+		}
+		Expect(utils.SetSecretOwner(secret, account, scheme.Scheme)).To(Succeed())
+		return secret
+	}
+
+	refresh := func(objects ...client.Object) client.Client {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(objects, account, configMap)...).Build()
+		NewSRECLICredentialsRefresher(kubeClient, mockAWSBuilder).RefreshCredentials(nullLogger)
+		return kubeClient
+	}
+
+	expectAssumeRole := func(expiration time.Time) {
+		gomock.InOrder(
+			mockAWSBuilder.EXPECT().GetClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(mockAWSClient, nil),
+			mockAWSClient.EXPECT().AssumeRole(gomock.Any()).DoAndReturn(func(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+				Expect(aws.StringValue(input.RoleArn)).To(Equal("arn:aws:iam::123456789012:role/OrganizationAccountAccessRole"))
+				return &sts.AssumeRoleOutput{
+					AssumedRoleUser: &sts.AssumedRoleUser{AssumedRoleId: aws.String("AROAOPERATOR:awsAccountOperator")},
+					Credentials: &sts.Credentials{
+						AccessKeyId:     aws.String("ASIAOPERATOR"),
+						SecretAccessKey: aws.String("operator-secret"),
+						SessionToken:    aws.String("operator-token"),
+					},
+				}, nil
+			}),
+			mockAWSBuilder.EXPECT().GetClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(mockMemberAWS, nil),
+			mockMemberAWS.EXPECT().AssumeRole(gomock.Any()).DoAndReturn(func(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+				Expect(aws.StringValue(input.RoleArn)).To(Equal("arn:aws:iam::123456789012:role/ManagedOpenShift-SRE-Access-abcdef"))
+				Expect(aws.StringValue(input.RoleSessionName)).To(Equal("SRE-CLI"))
+				Expect(aws.Int64Value(input.DurationSeconds)).To(Equal(int64(3600)))
+				return &sts.AssumeRoleOutput{Credentials: &sts.Credentials{
+					AccessKeyId:     aws.String("ASIANEW"),
+					SecretAccessKey: aws.String("new-secret"),
+					SessionToken:    aws.String("new-token"),
+					Expiration:      aws.Time(expiration),
+				}}, nil
+			}),
+		)
+	}
+
+	It("Publishes the session credentials of Ready accounts", func() {
+		expiration := time.Now().Add(time.Hour)
+		expectAssumeRole(expiration)
+		kubeClient := refresh()
+
+		secret := &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), secretKey, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("aws_access_key_id", []byte("ASIANEW")))
+		Expect(secret.Data).To(HaveKeyWithValue("aws_secret_access_key", []byte("new-secret")))
+		Expect(secret.Data).To(HaveKeyWithValue("aws_session_token", []byte("new-token")))
+		Expect(secret.Data).To(HaveKeyWithValue("expiration", []byte(expiration.UTC().Format(time.RFC3339))))
+		Expect(secret.Labels).To(HaveKeyWithValue(awsv1alpha1.SecretOwnerKindLabel, "Account"))
+	})
+
+	It("Keeps credentials that don't expire soon", func() {
+		kubeClient := refresh(credentialsSecret(time.Now().Add(time.Hour)))
+
+		secret := &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), secretKey, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("aws_access_key_id", []byte("ASIAOLD")))
+	})
+
+	It("Renews credentials about to expire", func() {
+		expectAssumeRole(time.Now().Add(time.Hour))
+		kubeClient := refresh(credentialsSecret(time.Now().Add(5 * time.Minute)))
+
+		secret := &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), secretKey, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("aws_access_key_id", []byte("ASIANEW")))
+	})
+
+	It("Deletes the credentials when disabled", func() {
+		configMap.Data = nil
+		kubeClient := refresh(credentialsSecret(time.Now().Add(time.Hour)))
+
+		err := kubeClient.Get(context.TODO(), secretKey, &corev1.Secret{})
+		Expect(k8serr.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Deletes the credentials when no SRE access role is configured", func() {
+		delete(configMap.Data, awsv1alpha1.SREAccessARN)
+		kubeClient := refresh(credentialsSecret(time.Now().Add(time.Hour)))
+
+		err := kubeClient.Get(context.TODO(), secretKey, &corev1.Secret{})
+		Expect(k8serr.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Doesn't publish credentials of CCS accounts", func() {
+		account.Spec.BYOC = true
+		kubeClient := refresh()
+
+		err := kubeClient.Get(context.TODO(), secretKey, &corev1.Secret{})
+		Expect(k8serr.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,252 @@
+package account
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// sreConsoleURLCheckInterval is how often the console URLs are checked for expiry or a
+	// regeneration request
+	sreConsoleURLCheckInterval = time.Minute
+	// sreConsoleURLRefreshMargin is how long before the sign-in URL expires it is regenerated, more than
+	// sreConsoleURLCheckInterval so the published URL can always be used
+	sreConsoleURLRefreshMargin = 2 * time.Minute
+
+	defaultSREConsoleSessionDuration = time.Hour
+	minSREConsoleSessionDuration     = 15 * time.Minute
+	// The SRE access role is assumed through the OrganizationAccountAccessRole, which caps the session
+	maxSREConsoleSessionDuration = maxChainedSessionDuration
+
+	sreConsoleURLRefresher  = "sre-console-url-refresher"
+	sreConsoleURLSuffix     = "-sre-console-url"
+	sreConsoleSessionName   = "SRE-Console"
+	secretConsoleURL        = "aws_console_login_url"
+	federationClientTimeout = 10 * time.Second
+)
+
+// federationEndpoint returns the AWS sign-in federation endpoint of the partition
+var federationEndpoint = stsclient.FederationEndpoint
+
+// SREConsoleURLRefresher publishes a federated AWS console sign-in URL of the SRE access role of Ready
+// non-CCS accounts in their <account>-sre-console-url secret. AWS only accepts a sign-in URL for 15
+// minutes, it is regenerated before then, and on demand when the Account is annotated with
+// RegenerateConsoleURLAnnotation. It only runs when enabled in the configmap and the SRE access role is
+// configured, the secrets are deleted otherwise.
+type SREConsoleURLRefresher struct {
+	client           client.Client
+	awsClientBuilder awsclient.IBuilder
+	httpClient       *http.Client
+}
+
+// NewSREConsoleURLRefresher returns a new SREConsoleURLRefresher
+func NewSREConsoleURLRefresher(client client.Client, awsClientBuilder awsclient.IBuilder) *SREConsoleURLRefresher {
+	return &SREConsoleURLRefresher{
+		client:           client,
+		awsClientBuilder: awsClientBuilder,
+		httpClient:       &http.Client{Timeout: federationClientTimeout},
+	}
+}
+
+// Start checks the console URLs every sreConsoleURLCheckInterval, until the operator is stopped
+func (c *SREConsoleURLRefresher) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the SRE console URL refresher")
+	for {
+		c.RefreshConsoleURLs(log)
+
+		select {
+		case <-time.After(sreConsoleURLCheckInterval):
+		case <-stopCh.Done():
+			log.Info("Stopping the SRE console URL refresher")
+			return
+		}
+	}
+}
+
+// getSREConsoleURLSettings reads from the configmap whether console URLs are published and the
+// duration of their console sessions
+func getSREConsoleURLSettings(log logr.Logger, kubeClient client.Client) (bool, time.Duration) {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		log.Error(err, "Unable to get the configmap, not publishing SRE console URLs")
+		return false, 0
+	}
+
+	enabled, err := strconv.ParseBool(configMap.Data[awsv1alpha1.SREConsoleURLConfigMapKey])
+	if err != nil || !enabled {
+		return false, 0
+	}
+	// The console sessions are those of the SRE access role, which only exists when its principal is configured
+	if configMap.Data[awsv1alpha1.SREAccessARN] == "" {
+		log.Info("No SRE access principal configured, not publishing SRE console URLs")
+		return false, 0
+	}
+
+	duration := defaultSREConsoleSessionDuration
+	if value := configMap.Data[awsv1alpha1.SREConsoleSessionDurationConfigMapKey]; value != "" {
+		duration, err = time.ParseDuration(value)
+		if err != nil || duration < minSREConsoleSessionDuration || duration > maxSREConsoleSessionDuration {
+			log.Error(err, "Invalid SRE console session duration in configmap, using the default", "value", value, "default", defaultSREConsoleSessionDuration)
+			duration = defaultSREConsoleSessionDuration
+		}
+	}
+	return true, duration
+}
+
+// SREConsoleURLSecretName returns the name of the secret holding the SRE console URL of an account
+func SREConsoleURLSecretName(accountName string) string {
+	return accountName + sreConsoleURLSuffix
+}
+
+// RefreshConsoleURLs regenerates the console URLs that are missing, about to expire or requested with
+// the annotation. When they're disabled, the existing secrets are deleted.
+func (c *SREConsoleURLRefresher) RefreshConsoleURLs(log logr.Logger) {
+	accountList := &awsv1alpha1.AccountList{}
+	err := c.client.List(context.TODO(), accountList, client.InNamespace(awsv1alpha1.AccountCrNamespace))
+	if err != nil {
+		log.Error(err, "Failed to list accounts")
+		return
+	}
+
+	secretList := &corev1.SecretList{}
+	err = c.client.List(context.TODO(), secretList, client.InNamespace(awsv1alpha1.AccountCrNamespace), client.MatchingLabels{awsv1alpha1.ManagedByLabel: awsv1alpha1.ManagedByOperator})
+	if err != nil {
+		log.Error(err, "Failed to list secrets")
+		return
+	}
+	secrets := map[string]*corev1.Secret{}
+	for i := range secretList.Items {
+		if strings.HasSuffix(secretList.Items[i].Name, sreConsoleURLSuffix) {
+			secrets[secretList.Items[i].Name] = &secretList.Items[i]
+		}
+	}
+
+	enabled, sessionDuration := getSREConsoleURLSettings(log, c.client)
+	var awsSetupClient awsclient.Client
+	for i := range accountList.Items {
+		account := &accountList.Items[i]
+		// The accounts of other shards are refreshed by other replicas
+		if !utils.InShard(account.Name) {
+			continue
+		}
+		reqLogger := log.WithValues("account", account.Name, "awsAccountID", account.Spec.AwsAccountID)
+		secret, exists := secrets[SREConsoleURLSecretName(account.Name)]
+
+		if !enabled || !shouldAuditIAM(account) {
+			if exists {
+				reqLogger.Info("Deleting SRE console URL")
+				err = c.client.Delete(context.TODO(), secret)
+				if err != nil && !k8serr.IsNotFound(err) {
+					reqLogger.Error(err, "Failed to delete SRE console URL")
+				}
+			}
+			continue
+		}
+		_, requested := account.GetAnnotations()[awsv1alpha1.RegenerateConsoleURLAnnotation]
+		if exists && !requested && !consoleURLExpiring(secret) {
+			continue
+		}
+
+		if awsSetupClient == nil {
+			awsSetupClient, err = c.awsClientBuilder.GetClient(sreConsoleURLRefresher, c.client, awsclient.NewAwsClientInput{
+				SecretName: utils.AwsSecretName,
+				NameSpace:  awsv1alpha1.AccountCrNamespace,
+				AwsRegion:  config.GetDefaultRegion(),
+			})
+			if err != nil {
+				log.Error(err, "Failed building operator AWS client")
+				return
+			}
+		}
+		err = c.refreshConsoleURL(reqLogger, awsSetupClient, account, secret, sessionDuration)
+		if err != nil {
+			reqLogger.Error(err, "Failed to refresh SRE console URL")
+			continue
+		}
+
+		if requested {
+			err = utils.UpdateWithRetry(c.client, account, func() error {
+				annotations := account.GetAnnotations()
+				delete(annotations, awsv1alpha1.RegenerateConsoleURLAnnotation)
+				account.SetAnnotations(annotations)
+				return nil
+			})
+			if err != nil {
+				reqLogger.Error(err, "Failed to remove the console URL regeneration annotation")
+			}
+		}
+	}
+}
+
+// consoleURLExpiring returns true when the sign-in URL in the secret expires within
+// sreConsoleURLRefreshMargin, or its expiry is unknown
+func consoleURLExpiring(secret *corev1.Secret) bool {
+	expiration, err := time.Parse(time.RFC3339, secret.GetAnnotations()[awsv1alpha1.ConsoleURLExpiresAtAnnotation])
+	if err != nil {
+		return true
+	}
+	return time.Until(expiration) < sreConsoleURLRefreshMargin
+}
+
+// refreshConsoleURL assumes the SRE access role of the account for the session duration, exchanges the
+// session credentials for a sign-in token and stores the resulting URL in the secret
+func (c *SREConsoleURLRefresher) refreshConsoleURL(reqLogger logr.Logger, awsSetupClient awsclient.Client, account *awsv1alpha1.Account, secret *corev1.Secret, sessionDuration time.Duration) error {
+	// The console session lasts as long as the credentials
+	creds, err := getSREAccessRoleCredentials(reqLogger, c.awsClientBuilder, c.client, awsSetupClient, account, sreConsoleSessionName, sessionDuration)
+	if err != nil {
+		return err
+	}
+
+	issued := time.Now()
+	signinToken, err := stsclient.GetSigninToken(c.httpClient, federationEndpoint(), creds.Credentials)
+	if err != nil {
+		return err
+	}
+	loginURL := stsclient.LoginURL(federationEndpoint(), signinToken)
+
+	annotations := map[string]string{
+		awsv1alpha1.ConsoleURLExpiresAtAnnotation:     issued.Add(stsclient.SigninTokenValidity).UTC().Format(time.RFC3339),
+		awsv1alpha1.ConsoleSessionExpiresAtAnnotation: aws.TimeValue(creds.Credentials.Expiration).UTC().Format(time.RFC3339),
+	}
+	reqLogger.Info("Regenerated SRE console URL", "urlExpiresAt", annotations[awsv1alpha1.ConsoleURLExpiresAtAnnotation], "sessionExpiresAt", annotations[awsv1alpha1.ConsoleSessionExpiresAtAnnotation])
+
+	if secret == nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: SREConsoleURLSecretName(account.Name), Namespace: account.Namespace, Annotations: annotations},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{secretConsoleURL: []byte(loginURL)},
+		}
+		err = utils.SetSecretOwner(secret, account, c.client.Scheme())
+		if err != nil {
+			return err
+		}
+		return c.client.Create(context.TODO(), secret)
+	}
+	return utils.UpdateWithRetry(c.client, secret, func() error {
+		secret.Data = map[string][]byte{secretConsoleURL: []byte(loginURL)}
+		current := secret.GetAnnotations()
+		if current == nil {
+			current = map[string]string{}
+		}
+		for k, v := range annotations {
+			current[k] = v
+		}
+		secret.SetAnnotations(current)
+		return nil
+	})
+}
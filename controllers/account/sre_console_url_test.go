@@ -0,0 +1,209 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("SRE console URL", func() {
+	var (
+		nullLogger         logr.Logger
+		mockAWSClient      *mock.MockClient
+		mockMemberAWS      *mock.MockClient
+		mockAWSBuilder     *mock.MockIBuilder
+		ctrl               *gomock.Controller
+		account            *awsv1alpha1.Account
+		configMap          *corev1.ConfigMap
+		secretKey          types.NamespacedName
+		federation         *httptest.Server
+		previousEndpoint   func() string
+		federationSessions []map[string]string
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		ctrl = gomock.NewController(GinkgoT())
+		nullLogger = testutils.NewTestLogger().Logger()
+		mockAWSClient = mock.NewMockClient(ctrl)
+		mockMemberAWS = mock.NewMockClient(ctrl)
+		mockAWSBuilder = mock.NewMockIBuilder(ctrl)
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "osd-creds-mgmt-abcdef",
+				Namespace: awsv1alpha1.AccountCrNamespace,
+				Labels:    map[string]string{awsv1alpha1.IAMUserIDLabel: "abcdef"},
+			},
+			Spec:   awsv1alpha1.AccountSpec{AwsAccountID: "123456789012"},
+			Status: awsv1alpha1.AccountStatus{State: string(awsv1alpha1.AccountReady)},
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+			Data: map[string]string{
+				awsv1alpha1.SREConsoleURLConfigMapKey:             "true",
+				awsv1alpha1.SREConsoleSessionDurationConfigMapKey: "30m",
+				awsv1alpha1.SREAccessARN:                          "arn:aws:iam::210987654321:role/sre-jump",
+			},
+		}
+		secretKey = types.NamespacedName{Name: "osd-creds-mgmt-abcdef-sre-console-url", Namespace: awsv1alpha1.AccountCrNamespace}
+
+		federationSessions = nil
+		federation = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Query().Get("Action")).To(Equal("getSigninToken"))
+			session := map[string]string{}
+			Expect(json.Unmarshal([]byte(r.URL.Query().Get("Session")), &session)).To(Succeed())
+			federationSessions = append(federationSessions, session)
+			_ = json.NewEncoder(w).Encode(map[string]string{"SigninToken": "signin-token"})
+		}))
+		previousEndpoint = federationEndpoint
+		federationEndpoint = func() string { return federation.URL + "/federation" }
+	})
+
+	AfterEach(func() {
+		federationEndpoint = previousEndpoint
+		federation.Close()
+		ctrl.Finish()
+	})
+
+	consoleURLSecret := func(urlExpiration time.Time) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretKey.Name,
+				Namespace: secretKey.Namespace,
+				Annotations: map[string]string{
+					awsv1alpha1.ConsoleURLExpiresAtAnnotation:     urlExpiration.UTC().Format(time.RFC3339),
+					awsv1alpha1.ConsoleSessionExpiresAtAnnotation: time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+				},
+			},
+			Data: map[string][]byte{"aws_console_login_url": []byte("https://old")},
+		}
+		Expect(utils.SetSecretOwner(secret, account, scheme.Scheme)).To(Succeed())
+		return secret
+	}
+
+	refresh := func(objects ...client.Object) client.Client {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(objects, account, configMap)...).Build()
+		NewSREConsoleURLRefresher(kubeClient, mockAWSBuilder).RefreshConsoleURLs(nullLogger)
+		return kubeClient
+	}
+
+	expectAssumeRole := func(durationSeconds int64, expiration time.Time) {
+		gomock.InOrder(
+			mockAWSBuilder.EXPECT().GetClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(mockAWSClient, nil),
+			mockAWSClient.EXPECT().AssumeRole(gomock.Any()).DoAndReturn(func(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+				Expect(aws.StringValue(input.RoleArn)).To(Equal("arn:aws:iam::123456789012:role/OrganizationAccountAccessRole"))
+				return &sts.AssumeRoleOutput{
+					AssumedRoleUser: &sts.AssumedRoleUser{AssumedRoleId: aws.String("AROAOPERATOR:awsAccountOperator")},
+					Credentials: &sts.Credentials{
+						AccessKeyId:     aws.String("ASIAOPERATOR"),
+						SecretAccessKey: aws.String("operator-secret"),
+						SessionToken:    aws.String("operator-token"),
+					},
+				}, nil
+			}),
+			mockAWSBuilder.EXPECT().GetClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(mockMemberAWS, nil),
+			mockMemberAWS.EXPECT().AssumeRole(gomock.Any()).DoAndReturn(func(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+				Expect(aws.StringValue(input.RoleArn)).To(Equal("arn:aws:iam::123456789012:role/ManagedOpenShift-SRE-Access-abcdef"))
+				Expect(aws.Int64Value(input.DurationSeconds)).To(Equal(durationSeconds))
+				Expect(input.SourceIdentity).To(BeNil())
+				return &sts.AssumeRoleOutput{Credentials: &sts.Credentials{
+					AccessKeyId:     aws.String("ASIANEW"),
+					SecretAccessKey: aws.String("new-secret"),
+					SessionToken:    aws.String("new-token"),
+					Expiration:      aws.Time(expiration),
+				}}, nil
+			}),
+		)
+	}
+
+	It("Publishes a console URL with its expiry", func() {
+		expiration := time.Now().Add(30 * time.Minute)
+		expectAssumeRole(1800, expiration)
+		kubeClient := refresh()
+
+		secret := &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), secretKey, secret)).To(Succeed())
+		loginURL, err := url.Parse(string(secret.Data["aws_console_login_url"]))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loginURL.Query().Get("Action")).To(Equal("login"))
+		Expect(loginURL.Query().Get("SigninToken")).To(Equal("signin-token"))
+		Expect(secret.Annotations).To(HaveKeyWithValue(awsv1alpha1.ConsoleSessionExpiresAtAnnotation, expiration.UTC().Format(time.RFC3339)))
+		Expect(secret.Annotations).To(HaveKey(awsv1alpha1.ConsoleURLExpiresAtAnnotation))
+		Expect(federationSessions).To(ConsistOf(map[string]string{"sessionId": "ASIANEW", "sessionKey": "new-secret", "sessionToken": "new-token"}))
+	})
+
+	It("Keeps a console URL that can still be used", func() {
+		kubeClient := refresh(consoleURLSecret(time.Now().Add(10 * time.Minute)))
+
+		secret := &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), secretKey, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("aws_console_login_url", []byte("https://old")))
+	})
+
+	It("Regenerates a console URL about to expire, even when its session doesn't", func() {
+		expectAssumeRole(1800, time.Now().Add(30*time.Minute))
+		kubeClient := refresh(consoleURLSecret(time.Now().Add(time.Minute)))
+
+		secret := &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), secretKey, secret)).To(Succeed())
+		Expect(string(secret.Data["aws_console_login_url"])).To(ContainSubstring("SigninToken=signin-token"))
+	})
+
+	It("Caps the console session at an hour", func() {
+		configMap.Data[awsv1alpha1.SREConsoleSessionDurationConfigMapKey] = "12h"
+		expectAssumeRole(3600, time.Now().Add(time.Hour))
+		refresh()
+	})
+
+	It("Regenerates the console URL on request and removes the annotation", func() {
+		account.Annotations = map[string]string{awsv1alpha1.RegenerateConsoleURLAnnotation: "true"}
+		expectAssumeRole(1800, time.Now().Add(30*time.Minute))
+		kubeClient := refresh(consoleURLSecret(time.Now().Add(10 * time.Minute)))
+
+		secret := &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), secretKey, secret)).To(Succeed())
+		Expect(string(secret.Data["aws_console_login_url"])).To(ContainSubstring("SigninToken=signin-token"))
+
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		Expect(updated.Annotations).NotTo(HaveKey(awsv1alpha1.RegenerateConsoleURLAnnotation))
+	})
+
+	It("Deletes the console URL when disabled", func() {
+		configMap.Data = nil
+		kubeClient := refresh(consoleURLSecret(time.Now().Add(10 * time.Minute)))
+
+		err := kubeClient.Get(context.TODO(), secretKey, &corev1.Secret{})
+		Expect(k8serr.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Deletes the console URL when no SRE access role is configured", func() {
+		delete(configMap.Data, awsv1alpha1.SREAccessARN)
+		kubeClient := refresh(consoleURLSecret(time.Now().Add(10 * time.Minute)))
+
+		err := kubeClient.Get(context.TODO(), secretKey, &corev1.Secret{})
+		Expect(k8serr.IsNotFound(err)).To(BeTrue())
+	})
+})
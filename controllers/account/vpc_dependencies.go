@@ -0,0 +1,129 @@
+package account
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/go-logr/logr"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+)
+
+// networkInterfaceDetachAttempts is how many times a detached network interface is checked before it's given up on
+const networkInterfaceDetachAttempts = 10
+
+// networkInterfaceDetachDelay is how long to wait between the checks of a network interface being detached. Tests set
+// it to 0.
+var networkInterfaceDetachDelay = 3 * time.Second
+
+// deleteVPCDependencies deletes what keeps a VPC from being deleted with a DependencyViolation, in the order their own
+// dependencies allow: the network interfaces left behind, detached first, then the rules of the security groups,
+// which may reference each other, then the security groups. The default security group goes with the VPC.
+func deleteVPCDependencies(reqLogger logr.Logger, awsClient awsclient.Client, vpcID *string) error {
+	err := deleteVPCNetworkInterfaces(reqLogger, awsClient, vpcID)
+	if err != nil {
+		return err
+	}
+	return deleteVPCSecurityGroups(reqLogger, awsClient, vpcID)
+}
+
+// deleteVPCNetworkInterfaces detaches and deletes the network interfaces of the VPC. The primary interface of an
+// instance, and the interfaces managed by an AWS service like a load balancer, can't be detached: they go away with
+// their instance or the resource of the service, so they're reported instead.
+func deleteVPCNetworkInterfaces(reqLogger logr.Logger, awsClient awsclient.Client, vpcID *string) error {
+	output, err := awsClient.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{
+		Filters: []*ec2.Filter{{Name: aws.String("vpc-id"), Values: []*string{vpcID}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, networkInterface := range output.NetworkInterfaces {
+		interfaceID := aws.StringValue(networkInterface.NetworkInterfaceId)
+		attachment := networkInterface.Attachment
+		if attachment != nil && aws.StringValue(networkInterface.Status) == ec2.NetworkInterfaceStatusInUse {
+			if aws.BoolValue(networkInterface.RequesterManaged) {
+				return fmt.Errorf("network interface %s is managed by AWS for %q, its resource has to be deleted first", interfaceID, aws.StringValue(networkInterface.Description))
+			}
+			if aws.Int64Value(attachment.DeviceIndex) == 0 {
+				return fmt.Errorf("network interface %s is the primary interface of instance %s, the instance has to be terminated first", interfaceID, aws.StringValue(attachment.InstanceId))
+			}
+			reqLogger.Info("Detaching network interface", "vpcID", aws.StringValue(vpcID), "networkInterfaceID", interfaceID, "instanceID", aws.StringValue(attachment.InstanceId))
+			_, err = awsClient.DetachNetworkInterface(&ec2.DetachNetworkInterfaceInput{
+				AttachmentId: attachment.AttachmentId,
+				Force:        aws.Bool(true),
+			})
+			if err != nil {
+				return err
+			}
+			err = waitForNetworkInterfaceDetached(reqLogger, awsClient, networkInterface.NetworkInterfaceId)
+			if err != nil {
+				return err
+			}
+		}
+
+		reqLogger.Info("Deleting network interface", "vpcID", aws.StringValue(vpcID), "networkInterfaceID", interfaceID)
+		_, err = awsClient.DeleteNetworkInterface(&ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: networkInterface.NetworkInterfaceId})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForNetworkInterfaceDetached waits for a detached network interface to be available, up to
+// networkInterfaceDetachAttempts checks
+func waitForNetworkInterfaceDetached(reqLogger logr.Logger, awsClient awsclient.Client, interfaceID *string) error {
+	for attempt := 1; attempt <= networkInterfaceDetachAttempts; attempt++ {
+		time.Sleep(networkInterfaceDetachDelay)
+		output, err := awsClient.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{NetworkInterfaceIds: []*string{interfaceID}})
+		if err != nil {
+			return err
+		}
+		if len(output.NetworkInterfaces) > 0 && aws.StringValue(output.NetworkInterfaces[0].Status) == ec2.NetworkInterfaceStatusAvailable {
+			return nil
+		}
+		reqLogger.Info("Waiting for the network interface to be detached", "networkInterfaceID", aws.StringValue(interfaceID), "attempt", attempt)
+	}
+	return fmt.Errorf("network interface %s is still attached after %d checks", aws.StringValue(interfaceID), networkInterfaceDetachAttempts)
+}
+
+// deleteVPCSecurityGroups revokes the rules of all the security groups of the VPC, then deletes the groups other
+// than the default one. A group can't be deleted while a rule of another group references it, and the groups may
+// reference each other, so no group is deleted before all the rules are gone.
+func deleteVPCSecurityGroups(reqLogger logr.Logger, awsClient awsclient.Client, vpcID *string) error {
+	output, err := awsClient.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{{Name: aws.String("vpc-id"), Values: []*string{vpcID}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, group := range output.SecurityGroups {
+		if len(group.IpPermissions) > 0 {
+			_, err = awsClient.RevokeSecurityGroupIngress(&ec2.RevokeSecurityGroupIngressInput{GroupId: group.GroupId, IpPermissions: group.IpPermissions})
+			if err != nil {
+				return err
+			}
+		}
+		if len(group.IpPermissionsEgress) > 0 {
+			_, err = awsClient.RevokeSecurityGroupEgress(&ec2.RevokeSecurityGroupEgressInput{GroupId: group.GroupId, IpPermissions: group.IpPermissionsEgress})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, group := range output.SecurityGroups {
+		if aws.StringValue(group.GroupName) == "default" {
+			continue
+		}
+		reqLogger.Info("Deleting security group", "vpcID", aws.StringValue(vpcID), "groupID", aws.StringValue(group.GroupId))
+		_, err = awsClient.DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{GroupId: group.GroupId})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
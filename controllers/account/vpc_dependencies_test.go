@@ -0,0 +1,73 @@
+package account
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("VPC dependencies", func() {
+	var (
+		ctrl          *gomock.Controller
+		mockAWSClient *mock.MockClient
+		vpcID         = aws.String("vpc-1")
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+		networkInterfaceDetachDelay = 0
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("Deletes the network interfaces, then the rules of the security groups, then the groups", func() {
+		// The groups reference each other, neither can be deleted before the rules of the other are revoked
+		toB := []*ec2.IpPermission{{IpProtocol: aws.String("-1"), UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-b")}}}}
+		toA := []*ec2.IpPermission{{IpProtocol: aws.String("-1"), UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-a")}}}}
+		gomock.InOrder(
+			mockAWSClient.EXPECT().DescribeNetworkInterfaces(gomock.Any()).Return(&ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: []*ec2.NetworkInterface{
+				{NetworkInterfaceId: aws.String("eni-available"), Status: aws.String(ec2.NetworkInterfaceStatusAvailable)},
+				{
+					NetworkInterfaceId: aws.String("eni-attached"),
+					Status:             aws.String(ec2.NetworkInterfaceStatusInUse),
+					Attachment:         &ec2.NetworkInterfaceAttachment{AttachmentId: aws.String("eni-attach-1"), DeviceIndex: aws.Int64(1), InstanceId: aws.String("i-1")},
+				},
+			}}, nil),
+			mockAWSClient.EXPECT().DeleteNetworkInterface(&ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: aws.String("eni-available")}).Return(&ec2.DeleteNetworkInterfaceOutput{}, nil),
+			mockAWSClient.EXPECT().DetachNetworkInterface(&ec2.DetachNetworkInterfaceInput{AttachmentId: aws.String("eni-attach-1"), Force: aws.Bool(true)}).Return(&ec2.DetachNetworkInterfaceOutput{}, nil),
+			mockAWSClient.EXPECT().DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{NetworkInterfaceIds: aws.StringSlice([]string{"eni-attached"})}).Return(&ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: []*ec2.NetworkInterface{
+				{Status: aws.String(ec2.NetworkInterfaceStatusAvailable)},
+			}}, nil),
+			mockAWSClient.EXPECT().DeleteNetworkInterface(&ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: aws.String("eni-attached")}).Return(&ec2.DeleteNetworkInterfaceOutput{}, nil),
+			mockAWSClient.EXPECT().DescribeSecurityGroups(gomock.Any()).Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []*ec2.SecurityGroup{
+				{GroupId: aws.String("sg-default"), GroupName: aws.String("default")},
+				{GroupId: aws.String("sg-a"), GroupName: aws.String("a"), IpPermissions: toB},
+				{GroupId: aws.String("sg-b"), GroupName: aws.String("b"), IpPermissionsEgress: toA},
+			}}, nil),
+			mockAWSClient.EXPECT().RevokeSecurityGroupIngress(&ec2.RevokeSecurityGroupIngressInput{GroupId: aws.String("sg-a"), IpPermissions: toB}).Return(&ec2.RevokeSecurityGroupIngressOutput{}, nil),
+			mockAWSClient.EXPECT().RevokeSecurityGroupEgress(&ec2.RevokeSecurityGroupEgressInput{GroupId: aws.String("sg-b"), IpPermissions: toA}).Return(&ec2.RevokeSecurityGroupEgressOutput{}, nil),
+			mockAWSClient.EXPECT().DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-a")}).Return(&ec2.DeleteSecurityGroupOutput{}, nil),
+			mockAWSClient.EXPECT().DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-b")}).Return(&ec2.DeleteSecurityGroupOutput{}, nil),
+		)
+
+		Expect(deleteVPCDependencies(testutils.NewTestLogger().Logger(), mockAWSClient, vpcID)).To(Succeed())
+	})
+
+	It("Reports the primary network interface of an instance instead of detaching it", func() {
+		mockAWSClient.EXPECT().DescribeNetworkInterfaces(gomock.Any()).Return(&ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: []*ec2.NetworkInterface{{
+			NetworkInterfaceId: aws.String("eni-primary"),
+			Status:             aws.String(ec2.NetworkInterfaceStatusInUse),
+			Attachment:         &ec2.NetworkInterfaceAttachment{AttachmentId: aws.String("eni-attach-0"), DeviceIndex: aws.Int64(0), InstanceId: aws.String("i-1")},
+		}}}, nil)
+
+		err := deleteVPCDependencies(testutils.NewTestLogger().Logger(), mockAWSClient, vpcID)
+		Expect(err).To(MatchError(ContainSubstring("primary interface of instance i-1")))
+	})
+})
@@ -0,0 +1,44 @@
+package accountclaim
+
+import (
+	"github.com/go-logr/logr"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// setAccountIDLabel labels the claim with the ID of the AWS account it's bound to, so the owner of an AWS account
+// is found from the hive cluster with a label selector, e.g.
+// `oc get accountclaims -A -l awsAccountID=123456789012`. The label follows the account link when it changes.
+func (r *AccountClaimReconciler) setAccountIDLabel(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) error {
+	accountID := accountClaim.Spec.BYOCAWSAccountID
+	if accountID == "" {
+		claimedAccount, err := r.getClaimedAccount(accountClaim.Spec.AccountLink, awsv1alpha1.AccountCrNamespace)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		accountID = claimedAccount.Spec.AwsAccountID
+	}
+	// The account may not be created yet
+	if accountID == "" || accountClaim.Labels[awsv1alpha1.AccountIDLabel] == accountID {
+		return nil
+	}
+
+	err := controllerutils.UpdateWithRetry(r.Client, accountClaim, func() error {
+		if accountClaim.Labels == nil {
+			accountClaim.Labels = map[string]string{}
+		}
+		accountClaim.Labels[awsv1alpha1.AccountIDLabel] = accountID
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to label the claim with its AWS account ID")
+		return err
+	}
+	reqLogger.Info("Labelled the claim with its AWS account ID", "awsAccountID", accountID)
+	return nil
+}
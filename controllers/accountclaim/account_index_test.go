@@ -0,0 +1,64 @@
+package accountclaim
+
+import (
+	"context"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AWS account ID label", func() {
+	var (
+		claim   *awsv1alpha1.AccountClaim
+		account *awsv1alpha1.Account
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		claim = &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "claim-namespace"},
+			Spec:       awsv1alpha1.AccountClaimSpec{AccountLink: "osd-creds-mgmt-aaabbb"},
+		}
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "osd-creds-mgmt-aaabbb", Namespace: awsv1alpha1.AccountCrNamespace},
+			Spec:       awsv1alpha1.AccountSpec{AwsAccountID: "123456789012"},
+		}
+	})
+
+	label := func(objs ...client.Object) map[string]string {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(objs, claim)...).Build()
+		r := &AccountClaimReconciler{Client: kubeClient, Scheme: scheme.Scheme}
+		Expect(r.setAccountIDLabel(testutils.NewTestLogger().Logger(), claim)).To(Succeed())
+
+		updated := &awsv1alpha1.AccountClaim{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(claim), updated)).To(Succeed())
+		return updated.Labels
+	}
+
+	It("Labels the claim with the AWS account ID of its account", func() {
+		Expect(label(account)).To(HaveKeyWithValue(awsv1alpha1.AccountIDLabel, "123456789012"))
+	})
+
+	It("Follows the account link", func() {
+		claim.Labels = map[string]string{awsv1alpha1.AccountIDLabel: "210987654321"}
+		Expect(label(account)).To(HaveKeyWithValue(awsv1alpha1.AccountIDLabel, "123456789012"))
+	})
+
+	It("Labels CCS claims with their AWS account ID", func() {
+		claim.Spec.BYOCAWSAccountID = "111111111111"
+		Expect(label()).To(HaveKeyWithValue(awsv1alpha1.AccountIDLabel, "111111111111"))
+	})
+
+	It("Doesn't label claims whose account has no AWS account yet", func() {
+		account.Spec.AwsAccountID = ""
+		Expect(label(account)).NotTo(HaveKey(awsv1alpha1.AccountIDLabel))
+	})
+})
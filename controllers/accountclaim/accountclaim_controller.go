@@ -3,6 +3,7 @@ package accountclaim
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -15,20 +16,29 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/openshift/aws-account-operator/config"
 	"github.com/openshift/aws-account-operator/controllers/account"
+	"github.com/openshift/aws-account-operator/pkg/audit"
 	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/cloudevents"
 	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/secretstore"
+	"github.com/openshift/aws-account-operator/pkg/totalaccountwatcher"
+	"github.com/openshift/aws-account-operator/pkg/tracing"
 	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
 )
@@ -51,6 +61,9 @@ const (
 	stsPolicyName           = "AAO-CustomPolicy"
 )
 
+// secretSuffixes are appended to the claim name to name the secrets created for an AccountClaim
+var secretSuffixes = []string{"secret", awsSTSSecret}
+
 var fleetManagerClaimEnabled = false
 
 type Policy struct {
@@ -148,11 +161,14 @@ type AccountClaimReconciler struct {
 	client.Client
 	Scheme           *runtime.Scheme
 	awsClientBuilder awsclient.IBuilder
+	recorder         record.EventRecorder
+	accountWatcher   totalaccountwatcher.AccountWatcherIface
 }
 
 //+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=accountclaims,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=accountclaims/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=accountclaims/finalizers,verbs=update
+//+kubebuilder:rbac:groups=hive.openshift.io,resources=clusterdeployments,verbs=get;list;watch
 
 // NewReconcileAccountClaim initializes ReconcileAccountClaim
 //
@@ -172,6 +188,29 @@ func NewAccountClaimReconciler(client client.Client, scheme *runtime.Scheme, aws
 func (r *AccountClaimReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
 	reqLogger := log.WithValues("Controller", controllerName, "Request.Namespace", request.Namespace, "Request.Name", request.Name)
 
+	// The claims of other shards, e.g. enqueued for their account, are reconciled by other replicas
+	if !controllerutils.InShard(request.Name) {
+		return ctrl.Result{}, nil
+	}
+
+	ctx = audit.WithFields(ctx, audit.Fields{AccountClaim: request.String()})
+	ctx, span := tracing.Start(ctx, "Reconcile AccountClaim", tracing.String("accountclaim", request.Name), tracing.String("accountclaim.namespace", request.Namespace))
+	ctx, plan := awsclient.WithPlan(ctx)
+	result, err := r.reconcileAccountClaim(ctx, request, reqLogger)
+	span.End(err)
+	accountClaim := &awsv1alpha1.AccountClaim{}
+	result, err = controllerutils.UpdateReconcileOutcome(r.Client, reqLogger, request.NamespacedName, accountClaim, result, err)
+	if accountClaim.Name != "" {
+		awsclient.RecordPlannedActions(r.recorder, accountClaim, plan)
+		if err == nil {
+			result = requeueBeforeExpiry(accountClaim, result)
+		}
+	}
+	return result, err
+}
+
+func (r *AccountClaimReconciler) reconcileAccountClaim(ctx context.Context, request ctrl.Request, reqLogger logr.Logger) (ctrl.Result, error) {
+
 	// Watch AccountClaim
 	accountClaim := &awsv1alpha1.AccountClaim{}
 	err := r.Client.Get(context.TODO(), request.NamespacedName, accountClaim)
@@ -185,11 +224,29 @@ func (r *AccountClaimReconciler) Reconcile(ctx context.Context, request ctrl.Req
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
+	if accountClaim.Spec.AccountLink != "" {
+		ctx = audit.WithFields(ctx, audit.Fields{Account: accountClaim.Spec.AccountLink})
+		tracing.SpanFromContext(ctx).SetAttributes(tracing.String("account", accountClaim.Spec.AccountLink))
+	}
+
+	// Label the claim with its shard, the update triggers another reconcile
+	if controllerutils.SetShardLabel(accountClaim) {
+		return reconcile.Result{}, controllerutils.UpdateWithRetry(r.Client, accountClaim, func() error {
+			controllerutils.SetShardLabel(accountClaim)
+			return nil
+		})
+	}
+
+	// Claims past their TTL are deleted, the deletion triggers their finalization
+	expired, err := r.expireClaim(reqLogger, accountClaim)
+	if expired || err != nil {
+		return reconcile.Result{}, err
+	}
 
 	// Fake Account Claim Process for Hive Testing ..
-	// Fake account claims are account claims which have the label `managed.openshift.com/fake: true`
-	// These fake claims are used for testing within hive
-	if accountClaim.Annotations[fakeAnnotation] == "true" {
+	// Fake account claims are account claims with spec.fake, or the annotation `managed.openshift.com/fake: true`
+	// These fake claims are used for testing within hive and in staging environments
+	if isFakeClaim(accountClaim) {
 		requeue, err := r.processFake(reqLogger, accountClaim)
 		if err != nil {
 			return reconcile.Result{}, err
@@ -207,13 +264,24 @@ func (r *AccountClaimReconciler) Reconcile(ctx context.Context, request ctrl.Req
 		return reconcile.Result{}, nil
 	}
 
+	// A claim moved to another namespace/name is recreated there and deleted without its account being cleaned up
+	handled, err := r.handleClaimTransfer(reqLogger, accountClaim)
+	if handled || err != nil {
+		return reconcile.Result{}, err
+	}
+
 	if accountClaim.DeletionTimestamp != nil {
-		if accountClaim.Spec.FleetManagerConfig.TrustedARN != "" {
-			if r.checkIAMSecretExists(accountClaim.Spec.AwsCredentialSecret.Name, accountClaim.Spec.AwsCredentialSecret.Namespace) {
-				err = r.deleteIAMSecret(reqLogger, accountClaim.Spec.AwsCredentialSecret.Name, accountClaim.Spec.AwsCredentialSecret.Namespace)
-				if err != nil {
-					return reconcile.Result{}, err
-				}
+		// The account would be reused or released without having been cleaned up
+		if controllerutils.IsAWSMutationPaused(r.Client) {
+			reqLogger.Info("AWS changes are paused, postponing the cleanup of the claimed account")
+			return reconcile.Result{RequeueAfter: controllerutils.MaintenanceModeRequeueDelay}, nil
+		}
+
+		// The secret and role of a transferred claim belong to the claim it was transferred to
+		if accountClaim.Spec.FleetManagerConfig.TrustedARN != "" && !claimTransferCompleted(accountClaim) {
+			err = r.deleteClaimSecret(reqLogger, accountClaim)
+			if err != nil {
+				return reconcile.Result{}, err
 			}
 
 			currentAcctInstance, accountErr := r.getClaimedAccount(accountClaim.Spec.AccountLink, awsv1alpha1.AccountCrNamespace)
@@ -243,7 +311,7 @@ func (r *AccountClaimReconciler) Reconcile(ctx context.Context, request ctrl.Req
 				}
 			}
 		}
-		return reconcile.Result{}, r.handleAccountClaimDeletion(reqLogger, accountClaim)
+		return reconcile.Result{}, r.handleAccountClaimDeletion(ctx, reqLogger, accountClaim)
 	}
 
 	isCCS := accountClaim.Spec.BYOCAWSAccountID != ""
@@ -254,13 +322,29 @@ func (r *AccountClaimReconciler) Reconcile(ctx context.Context, request ctrl.Req
 		localmetrics.Collector.SetAccountClaimPendingDuration(isCCS, pendingDuration.Seconds())
 	}
 
+	// Record the cluster of the claim, and its deletion, on the claimed account
+	if accountClaim.Spec.ClusterDeploymentRef != nil && accountClaim.Spec.AccountLink != "" {
+		err = r.linkClusterDeployment(reqLogger, accountClaim)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Index the claim by the ID of its AWS account
+	if accountClaim.Spec.AccountLink != "" {
+		err = r.setAccountIDLabel(reqLogger, accountClaim)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
 	if accountClaim.Spec.BYOC {
 		return r.handleBYOCAccountClaim(reqLogger, accountClaim)
 	}
 
 	// Return if this claim has been satisfied
 	if claimIsSatisfied(accountClaim) {
-		reqLogger.Info(fmt.Sprintf("Claim %s has been satisfied ignoring", accountClaim.ObjectMeta.Name))
+		reqLogger.Info("Claim has been satisfied, ignoring", "claim", accountClaim.ObjectMeta.Name)
 		return reconcile.Result{}, nil
 	}
 
@@ -287,7 +371,19 @@ func (r *AccountClaimReconciler) Reconcile(ctx context.Context, request ctrl.Req
 
 	// Get an unclaimed account from the pool
 	if accountClaim.Spec.AccountLink == "" {
+		// An account isn't handed out to a claim for regions it can't be used in
+		err = accountClaim.ValidateRegions()
+		if err != nil {
+			return reconcile.Result{}, r.setInvalidAccountClaim(reqLogger, accountClaim, err)
+		}
+		err = r.checkClaimDeadline(reqLogger, accountClaim)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 		unclaimedAccount, err = r.getUnclaimedAccount(reqLogger, accountClaim)
+		if errors.Is(err, errNoUnclaimedAccount) && r.onDemandCreationEnabled(reqLogger, accountClaim) {
+			return r.createAccountOnDemand(reqLogger, accountClaim)
+		}
 		if err != nil {
 			reqLogger.Error(err, "Unable to select an unclaimed account from the pool")
 			return reconcile.Result{}, err
@@ -302,8 +398,14 @@ func (r *AccountClaimReconciler) Reconcile(ctx context.Context, request ctrl.Req
 	// Set Account.Spec.ClaimLink
 	// This will trigger the reconcile loop for the account which will mark the account as claimed in its status
 	if unclaimedAccount.Spec.ClaimLink == "" {
-		updateClaimedAccountFields(reqLogger, unclaimedAccount, accountClaim)
-		err := r.accountSpecUpdate(reqLogger, unclaimedAccount)
+		err := r.accountSpecUpdate(reqLogger, unclaimedAccount, func() error {
+			// The account may have been claimed since it was read
+			if unclaimedAccount.Spec.ClaimLink != "" && (unclaimedAccount.Spec.ClaimLink != accountClaim.Name || unclaimedAccount.Spec.ClaimLinkNamespace != accountClaim.Namespace) {
+				return awsv1alpha1.ErrAccountClaimed
+			}
+			updateClaimedAccountFields(reqLogger, unclaimedAccount, accountClaim)
+			return nil
+		})
 		if err != nil {
 			return reconcile.Result{}, err
 		}
@@ -311,8 +413,16 @@ func (r *AccountClaimReconciler) Reconcile(ctx context.Context, request ctrl.Req
 
 	// Set awsAccountClaim.Spec.AccountLink
 	if accountClaim.Spec.AccountLink == "" {
-		setAccountLinkOnAccountClaim(reqLogger, unclaimedAccount, accountClaim)
-		return reconcile.Result{}, r.specUpdate(reqLogger, accountClaim)
+		return reconcile.Result{}, r.specUpdate(reqLogger, accountClaim, func() error {
+			setAccountLinkOnAccountClaim(reqLogger, unclaimedAccount, accountClaim)
+			return nil
+		})
+	}
+
+	// An account created on demand for the claim goes to the pool when the claim got another one
+	err = r.releaseOnDemandAccounts(reqLogger, accountClaim)
+	if err != nil {
+		return reconcile.Result{}, err
 	}
 
 	if !accountClaim.Spec.ManualSTSMode {
@@ -405,19 +515,26 @@ func (r *AccountClaimReconciler) Reconcile(ctx context.Context, request ctrl.Req
 				}
 			}
 			// Remove IAM user Secret from Account Spec
-			unclaimedAccount.Spec.IAMUserSecret = ""
-			err = r.accountSpecUpdate(reqLogger, unclaimedAccount)
+			err = r.accountSpecUpdate(reqLogger, unclaimedAccount, func() error {
+				unclaimedAccount.Spec.IAMUserSecret = ""
+				return nil
+			})
 			if err != nil {
 				return reconcile.Result{}, err
 			}
 
 			// Creates IAM role secret
-			if !r.checkIAMSecretExists(accountClaim.Spec.AwsCredentialSecret.Name, accountClaim.Spec.AwsCredentialSecret.Namespace) {
+			exists, err := r.checkClaimSecretExists(accountClaim)
+			if err != nil {
+				reqLogger.Error(err, "Unable to check the claim secret")
+				return reconcile.Result{}, err
+			}
+			if !exists {
 				if err := r.createIAMRoleSecret(reqLogger, accountClaim, roleARN); err != nil {
 					return reconcile.Result{}, err
 				}
 			} else {
-				err = r.deleteIAMSecret(reqLogger, accountClaim.Spec.AwsCredentialSecret.Name, accountClaim.Spec.AwsCredentialSecret.Namespace)
+				err = r.deleteClaimSecret(reqLogger, accountClaim)
 				if err != nil {
 					return reconcile.Result{}, err
 				}
@@ -432,7 +549,12 @@ func (r *AccountClaimReconciler) Reconcile(ctx context.Context, request ctrl.Req
 	} else {
 
 		// Create secret for OCM to consume
-		if !r.checkIAMSecretExists(accountClaim.Spec.AwsCredentialSecret.Name, accountClaim.Spec.AwsCredentialSecret.Namespace) {
+		exists, err := r.checkClaimSecretExists(accountClaim)
+		if err != nil {
+			reqLogger.Error(err, "Unable to check the claim secret")
+			return reconcile.Result{}, err
+		}
+		if !exists {
 			err = r.createIAMSecret(reqLogger, accountClaim, unclaimedAccount)
 			if err != nil {
 				return reconcile.Result{}, nil
@@ -440,9 +562,35 @@ func (r *AccountClaimReconciler) Reconcile(ctx context.Context, request ctrl.Req
 		}
 	}
 
+	// Grant the configured IAM Identity Center access, budget the account, tag it for cost allocation and enroll it in
+	// GuardDuty before the claim is handed out
+	if !unclaimedAccount.IsBYOC() {
+		err = r.assignIdentityCenterPermissionSets(reqLogger, accountClaim, unclaimedAccount)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		err = r.ensureBudget(reqLogger, unclaimedAccount)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		err = r.ensureCostAllocationTags(reqLogger, unclaimedAccount)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		err = r.ensureGuardDutyMembership(reqLogger, accountClaim, unclaimedAccount)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
 	if accountClaim.Status.State != awsv1alpha1.ClaimStatusReady && accountClaim.Spec.AccountLink != "" {
 		// Set AccountClaim.Status.Conditions and AccountClaim.Status.State to Ready
 		setAccountClaimStatus(reqLogger, unclaimedAccount, accountClaim)
+		controllerutils.RecordEvent(r.recorder, accountClaim, corev1.EventTypeNormal, controllerutils.EventReasonClaimBound, "Account claim bound to account %s", unclaimedAccount.Name)
+		cloudevents.Publish(reqLogger, cloudevents.TypeAccountClaimed, unclaimedAccount, fmt.Sprintf("Account claimed by %s/%s", accountClaim.Namespace, accountClaim.Name))
 		return reconcile.Result{}, r.statusUpdate(reqLogger, accountClaim)
 	}
 
@@ -549,19 +697,21 @@ func (r *AccountClaimReconciler) createIAMRoleSecret(reqLogger logr.Logger, acco
 	}
 
 	OCMSecret := newStsSecretforCR(OCMSecretName, OCMSecretNamespace, []byte(roleARN))
-
-	err := r.Client.Create(context.TODO(), OCMSecret)
+	err := controllerutils.SetSecretOwner(OCMSecret, accountClaim, r.Scheme)
 	if err != nil {
-		reqLogger.Error(err, "Unable to create secret for OCM")
 		return err
 	}
-	reqLogger.Info(fmt.Sprintf("Secret %s created for claim %s", OCMSecret.Name, accountClaim.Name))
 
-	accountClaim.Spec.AwsCredentialSecret.Name = OCMSecretName
-	err = r.Client.Update(context.TODO(), accountClaim)
+	err = r.putClaimSecret(reqLogger, OCMSecret)
 	if err != nil {
-		reqLogger.Error(err, fmt.Sprintf("AccountClaim spec update for %s failed", accountClaim.Name))
+		return err
 	}
+	reqLogger.Info("Secret created for claim", "secret", OCMSecret.Name, "claim", accountClaim.Name)
+
+	_ = r.specUpdate(reqLogger, accountClaim, func() error {
+		accountClaim.Spec.AwsCredentialSecret.Name = OCMSecretName
+		return nil
+	})
 	return nil
 }
 
@@ -593,7 +743,7 @@ func (r *AccountClaimReconciler) createIAMRoleWithPermissions(reqLogger logr.Log
 		return "", err
 	}
 
-	reqLogger.Info(fmt.Sprintf("Creating role: %s", roleName))
+	reqLogger.Info("Creating role", "role", roleName)
 	createRoleOutput, err := awsClient.CreateRole(&iam.CreateRoleInput{
 		RoleName:                 aws.String(roleName),
 		Description:              aws.String("Managed by AAO"),
@@ -602,7 +752,7 @@ func (r *AccountClaimReconciler) createIAMRoleWithPermissions(reqLogger logr.Log
 	if err != nil {
 		return "", err
 	}
-	reqLogger.Info(fmt.Sprintf("Role %s created", createRoleOutput))
+	reqLogger.Info("Role created", "role", roleName)
 
 	arnComponents := strings.Split(*createRoleOutput.Role.Arn, ":")
 	accountId := arnComponents[4]
@@ -635,13 +785,15 @@ func (r *AccountClaimReconciler) createIAMRoleWithPermissions(reqLogger logr.Log
 func (r *AccountClaimReconciler) setSupportRoleARNManagedOpenshift(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim, account *awsv1alpha1.Account) error {
 	if accountClaim.Spec.STSRoleARN == "" {
 		instanceID := account.Labels[awsv1alpha1.IAMUserIDLabel]
-		accountClaim.Spec.SupportRoleARN = config.GetIAMArn(account.Spec.AwsAccountID, config.AwsResourceTypeRole, fmt.Sprintf("ManagedOpenShift-Support-%s", instanceID))
-		return r.specUpdate(reqLogger, accountClaim)
+		return r.specUpdate(reqLogger, accountClaim, func() error {
+			accountClaim.Spec.SupportRoleARN = config.GetIAMArn(account.Spec.AwsAccountID, config.AwsResourceTypeRole, fmt.Sprintf("ManagedOpenShift-Support-%s", instanceID))
+			return nil
+		})
 	}
 	return nil
 }
 
-func (r *AccountClaimReconciler) handleAccountClaimDeletion(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) error {
+func (r *AccountClaimReconciler) handleAccountClaimDeletion(ctx context.Context, reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) error {
 
 	if !controllerutils.Contains(accountClaim.GetFinalizers(), accountClaimFinalizer) {
 		return nil
@@ -657,7 +809,7 @@ func (r *AccountClaimReconciler) handleAccountClaimDeletion(reqLogger logr.Logge
 	// Only do AWS cleanup and account reset if accountLink is not empty
 	// We will not attempt AWS cleanup if the account is BYOC since we're not going to reuse these accounts
 	if accountClaim.Spec.AccountLink != "" {
-		err := r.finalizeAccountClaim(reqLogger, accountClaim)
+		err := r.finalizeAccountClaim(ctx, reqLogger, accountClaim)
 		if err != nil {
 			// If the finalize/cleanup process fails for an account we don't want to return
 			// we will flag the account with the Failed Reuse condition, and with state = Failed
@@ -668,6 +820,12 @@ func (r *AccountClaimReconciler) handleAccountClaimDeletion(reqLogger logr.Logge
 				return fmt.Errorf("account CR modified during reset: %w", err)
 			}
 
+			// A cleanup interrupted by an operator shutdown resumes after the restart
+			if controllerutils.IsShuttingDownError(err) {
+				reqLogger.Info("AccountClaim finalization interrupted by the operator shutdown")
+				return err
+			}
+
 			// Get account claimed by deleted accountclaim
 			failedReusedAccount, accountErr := r.getClaimedAccount(accountClaim.Spec.AccountLink, awsv1alpha1.AccountCrNamespace)
 			if accountErr != nil {
@@ -675,7 +833,7 @@ func (r *AccountClaimReconciler) handleAccountClaimDeletion(reqLogger logr.Logge
 				return fmt.Errorf("failed to get claimed account: %w", err)
 			}
 			// Update account status and add "Reuse Failed" condition
-			accountErr = r.resetAccountSpecStatus(reqLogger, failedReusedAccount, accountClaim, awsv1alpha1.AccountFailed, "Failed")
+			accountErr = resetAccountSpecStatus(reqLogger, r.Client, failedReusedAccount, accountClaim.Spec.LegalEntity, awsv1alpha1.AccountFailed, "Failed")
 			if accountErr != nil {
 				reqLogger.Error(accountErr, "Failed updating account status for failed reuse")
 				return fmt.Errorf("failed updating account status for failed reuse: %w", err)
@@ -685,6 +843,12 @@ func (r *AccountClaimReconciler) handleAccountClaimDeletion(reqLogger logr.Logge
 		}
 	}
 
+	// An account created on demand for the claim and not bound to it goes to the pool
+	err := r.releaseOnDemandAccounts(reqLogger, accountClaim)
+	if err != nil {
+		return err
+	}
+
 	// Remove finalizer to unlock deletion of the accountClaim
 	return r.removeFinalizer(reqLogger, accountClaim, accountClaimFinalizer)
 }
@@ -708,27 +872,35 @@ func (r *AccountClaimReconciler) handleBYOCAccountClaim(reqLogger logr.Logger, a
 	if accountClaim.Spec.AccountLink == "" {
 		validateErr := accountClaim.Validate()
 		if validateErr != nil {
-			// Figure the reason for our failure
-			errReason := validateErr.Error()
-			// Update AccountClaim status
-			controllerutils.SetAccountClaimStatus(
-				accountClaim,
-				"Invalid AccountClaim",
-				errReason,
-				awsv1alpha1.InvalidAccountClaim,
-				awsv1alpha1.ClaimStatusError,
-			)
-			err := r.Client.Status().Update(context.TODO(), accountClaim)
-			if err != nil {
-				reqLogger.Error(err, "Failed to Update AccountClaim Status")
-			}
-
 			// TODO: Recoverable?
-			return reconcile.Result{}, validateErr
+			return reconcile.Result{}, r.setInvalidAccountClaim(reqLogger, accountClaim, validateErr)
+		}
+
+		// Validate the customer account before accepting the claim
+		validationEnabled, cm, err := r.ccsValidationEnabled()
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if validationEnabled {
+			validationErr := r.validateCCSAccount(reqLogger, accountClaim, cm)
+			var failure *ccsValidationFailure
+			if validationErr != nil && !errors.As(validationErr, &failure) {
+				return reconcile.Result{}, validationErr
+			}
+			if setCCSValidationStatus(accountClaim, validationErr) {
+				err = r.statusUpdate(reqLogger, accountClaim)
+				if err != nil {
+					return reconcile.Result{}, err
+				}
+			}
+			if failure != nil {
+				reqLogger.Info("CCS account failed validation", "reason", failure.reason, "message", failure.message)
+				return reconcile.Result{RequeueAfter: time.Second * ccsValidationRequeuePeriod}, nil
+			}
 		}
 
 		// Create a new account with BYOC flag
-		err := r.createAccountForBYOCClaim(accountClaim)
+		err = r.createAccountForBYOCClaim(accountClaim)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
@@ -765,8 +937,19 @@ func (r *AccountClaimReconciler) handleBYOCAccountClaim(reqLogger logr.Logger, a
 		return reconcile.Result{RequeueAfter: time.Second * waitPeriod}, nil
 	}
 
+	// STS claims get the roles they describe instead of IAM users
+	if accountClaim.Spec.ManualSTSMode {
+		err = r.provisionSTSRoles(reqLogger, accountClaim)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
 	if byocAccount.IsReady() && accountClaim.Status.State != awsv1alpha1.ClaimStatusReady {
 		accountClaim.Status.State = awsv1alpha1.ClaimStatusReady
+		// Each cluster installed in the CCS account gets its own IAM user
+		accountClaim.Status.IAMUserName = byocAccount.Status.IAMUserName
+		setAccountClaimFulfillmentDuration(accountClaim)
 		message := "BYOC account ready"
 		accountClaim.Status.Conditions = controllerutils.SetAccountClaimCondition(
 			accountClaim.Status.Conditions,
@@ -788,7 +971,12 @@ func (r *AccountClaimReconciler) handleBYOCAccountClaim(reqLogger logr.Logger, a
 		}
 
 		// Create secret for OCM to consume
-		if !r.checkIAMSecretExists(accountClaim.Spec.AwsCredentialSecret.Name, accountClaim.Spec.AwsCredentialSecret.Namespace) {
+		exists, err := r.checkClaimSecretExists(accountClaim)
+		if err != nil {
+			reqLogger.Error(err, "Unable to check the claim secret")
+			return reconcile.Result{}, err
+		}
+		if !exists {
 			err = r.createIAMSecret(reqLogger, accountClaim, byocAccount)
 			if err != nil {
 				return reconcile.Result{}, nil
@@ -813,9 +1001,10 @@ func (r *AccountClaimReconciler) createAccountForBYOCClaim(accountClaim *awsv1al
 	}
 
 	// Set the accountLink of the AccountClaim to the new account if create is successful
-	accountClaim.Spec.AccountLink = newAccount.Name
-	err = r.Client.Update(context.TODO(), accountClaim)
-	return err
+	return controllerutils.UpdateWithRetry(r.Client, accountClaim, func() error {
+		accountClaim.Spec.AccountLink = newAccount.Name
+		return nil
+	})
 }
 
 func (r *AccountClaimReconciler) getClaimedAccount(accountLink string, namespace string) (*awsv1alpha1.Account, error) {
@@ -853,10 +1042,11 @@ func (r *AccountClaimReconciler) getUnclaimedAccount(reqLogger logr.Logger, acco
 		reqLogger.Error(err, "Default AccountPool name is empty")
 		return nil, err
 	} else {
-		reqLogger.Info(fmt.Sprintf("defaultAccountPoolName: %s", defaultAccountPoolName))
+		reqLogger.Info("Using default AccountPool", "accountPool", defaultAccountPoolName)
 	}
 
-	var unusedAccount *awsv1alpha1.Account
+	exhaustedAccounts := r.getNearlyExhaustedAccounts(reqLogger)
+	var unusedAccount, exhaustedAccount *awsv1alpha1.Account
 
 	for _, loopAccount := range accountList.Items {
 		// assign to new variable to prevent issues with using a pointer to the loop var later
@@ -869,8 +1059,16 @@ func (r *AccountClaimReconciler) getUnclaimedAccount(reqLogger logr.Logger, acco
 			continue
 		}
 
+		// Accounts whose service quotas are nearly exhausted are only handed out when no other account is left
+		if exhaustedAccounts[account.Name] {
+			if exhaustedAccount == nil {
+				exhaustedAccount = &account
+			}
+			continue
+		}
+
 		if account.Status.Reused {
-			reqLogger.Info(fmt.Sprintf("Reusing account: %s", account.ObjectMeta.Name))
+			reqLogger.Info("Reusing account", "account", account.ObjectMeta.Name, "awsAccountID", account.Spec.AwsAccountID)
 			return &account, nil
 		} else {
 			unusedAccount = &account
@@ -878,10 +1076,32 @@ func (r *AccountClaimReconciler) getUnclaimedAccount(reqLogger logr.Logger, acco
 	}
 
 	if unusedAccount != nil {
-		reqLogger.Info(fmt.Sprintf("Claiming account: %s", unusedAccount.ObjectMeta.Name))
+		reqLogger.Info("Claiming account", "account", unusedAccount.ObjectMeta.Name, "awsAccountID", unusedAccount.Spec.AwsAccountID)
 		return unusedAccount, nil
 	}
-	return nil, fmt.Errorf("can't find a suitable account to claim")
+	if exhaustedAccount != nil {
+		reqLogger.Info("Claiming account with nearly exhausted service quotas", "account", exhaustedAccount.ObjectMeta.Name, "awsAccountID", exhaustedAccount.Spec.AwsAccountID)
+		return exhaustedAccount, nil
+	}
+	return nil, errNoUnclaimedAccount
+}
+
+// getNearlyExhaustedAccounts returns the names of the accounts whose AWSAccountQuota reports nearly exhausted
+// service quotas. Claims aren't blocked on it, no account is reported when the AWSAccountQuotas can't be listed.
+func (r *AccountClaimReconciler) getNearlyExhaustedAccounts(reqLogger logr.Logger) map[string]bool {
+	quotaList := &awsv1alpha1.AWSAccountQuotaList{}
+	if err := r.Client.List(context.TODO(), quotaList, client.InNamespace(awsv1alpha1.AccountCrNamespace)); err != nil {
+		reqLogger.Error(err, "Unable to list AWSAccountQuotas, ignoring the service quotas of the accounts")
+		return nil
+	}
+
+	exhausted := map[string]bool{}
+	for _, quota := range quotaList.Items {
+		if quota.Status.NearlyExhausted {
+			exhausted[quota.Spec.AccountLink] = true
+		}
+	}
+	return exhausted
 }
 
 // IsSameAccountPoolNames is used to determine if two accountpool names
@@ -939,6 +1159,21 @@ func CanAccountBeClaimedByAccountClaim(account *awsv1alpha1.Account, accountclai
 		return false
 	}
 
+	// Accounts AWS reports problems for can't be claimed
+	if account.HasAWSHealthIssues() {
+		return false
+	}
+
+	// Accounts being hibernated can't be claimed until their resources are removed
+	if account.IsHibernating() {
+		return false
+	}
+
+	// Accounts created on demand for a claim are only handed out to it, until they're reused
+	if account.IsCreatedOnDemand() && !account.Status.Reused && account.Annotations[awsv1alpha1.OnDemandClaimAnnotation] != accountclaim.Namespace+"/"+accountclaim.Name {
+		return false
+	}
+
 	// Unused accounts always match
 	if !account.Status.Reused {
 		return true
@@ -964,21 +1199,76 @@ func (r *AccountClaimReconciler) createIAMSecret(reqLogger logr.Logger, accountC
 	awsSecretAccessKey := accountIAMUserSecret.Data[awsCredsSecretAccessKey]
 
 	if string(awsAccessKeyID) == "" || string(awsSecretAccessKey) == "" {
-		reqLogger.Error(err, fmt.Sprintf("Cannot get AWS Credentials from secret %s referenced from Account", unclaimedAccount.Spec.IAMUserSecret))
+		reqLogger.Error(err, "Cannot get AWS Credentials from secret referenced from Account", "secret", unclaimedAccount.Spec.IAMUserSecret, "account", unclaimedAccount.Name)
 	}
 
 	OCMSecret := newSecretforCR(OCMSecretName, OCMSecretNamespace, awsAccessKeyID, awsSecretAccessKey)
+	err = controllerutils.SetSecretOwner(OCMSecret, accountClaim, r.Scheme)
+	if err != nil {
+		return err
+	}
 
-	err = r.Client.Create(context.TODO(), OCMSecret)
+	err = r.putClaimSecret(reqLogger, OCMSecret)
 	if err != nil {
-		reqLogger.Error(err, "Unable to create secret for OCM")
 		return err
 	}
 
-	reqLogger.Info(fmt.Sprintf("Secret %s created for claim %s", OCMSecret.Name, accountClaim.Name))
+	reqLogger.Info("Secret created for claim", "secret", OCMSecret.Name, "claim", accountClaim.Name)
 	return nil
 }
 
+// checkClaimSecretExists checks whether the credentials secret of the claim exists in the configured secret store.
+// Only a secret the store doesn't have is missing, the other errors of the store are returned.
+func (r *AccountClaimReconciler) checkClaimSecretExists(accountClaim *awsv1alpha1.AccountClaim) (bool, error) {
+	store, err := secretstore.New(r.Client)
+	if err != nil {
+		return false, err
+	}
+	_, err = store.Get(context.TODO(), claimSecretKey(accountClaim))
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// putClaimSecret writes a credentials secret for a claim to the configured secret store
+func (r *AccountClaimReconciler) putClaimSecret(reqLogger logr.Logger, secret *corev1.Secret) error {
+	store, err := secretstore.New(r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Unable to get secret store")
+		return err
+	}
+	err = store.Put(context.TODO(), secret)
+	if err != nil {
+		reqLogger.Error(err, "Unable to create secret for OCM", "backend", store.Backend())
+		return err
+	}
+	return nil
+}
+
+// deleteClaimSecret deletes the credentials secret of the claim from the configured secret store
+func (r *AccountClaimReconciler) deleteClaimSecret(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) error {
+	store, err := secretstore.New(r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Unable to get secret store")
+		return err
+	}
+	err = store.Delete(context.TODO(), claimSecretKey(accountClaim))
+	if err != nil {
+		reqLogger.Error(err, "Unable to delete claim secret", "backend", store.Backend())
+		return err
+	}
+	reqLogger.Info("Claim secret deleted", "secret", accountClaim.Spec.AwsCredentialSecret.Name)
+	return nil
+}
+
+func claimSecretKey(accountClaim *awsv1alpha1.AccountClaim) types.NamespacedName {
+	return types.NamespacedName{Name: accountClaim.Spec.AwsCredentialSecret.Name, Namespace: accountClaim.Spec.AwsCredentialSecret.Namespace}
+}
+
 func (r *AccountClaimReconciler) checkIAMSecretExists(name string, namespace string) bool {
 	// Need to check if the secret exists AND that it matches what we're expecting
 	secret := corev1.Secret{}
@@ -991,25 +1281,27 @@ func (r *AccountClaimReconciler) checkIAMSecretExists(name string, namespace str
 }
 
 func (r *AccountClaimReconciler) statusUpdate(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) error {
-	err := r.Client.Status().Update(context.TODO(), accountClaim)
+	err := controllerutils.UpdateStatus(r.Client, accountClaim)
 	if err != nil {
-		reqLogger.Error(err, fmt.Sprintf("Status update for %s failed", accountClaim.Name))
+		reqLogger.Error(err, "AccountClaim status update failed", "claim", accountClaim.Name)
 	}
 	return err
 }
 
-func (r *AccountClaimReconciler) specUpdate(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) error {
-	err := r.Client.Update(context.TODO(), accountClaim)
+// specUpdate applies mutate to the claim and updates it, retrying on conflicts
+func (r *AccountClaimReconciler) specUpdate(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim, mutate func() error) error {
+	err := controllerutils.UpdateWithRetry(r.Client, accountClaim, mutate)
 	if err != nil {
-		reqLogger.Error(err, fmt.Sprintf("Spec update for %s failed", accountClaim.Name))
+		reqLogger.Error(err, "AccountClaim spec update failed", "claim", accountClaim.Name)
 	}
 	return err
 }
 
-func (r *AccountClaimReconciler) accountSpecUpdate(reqLogger logr.Logger, account *awsv1alpha1.Account) error {
-	err := r.Client.Update(context.TODO(), account)
+// accountSpecUpdate applies mutate to the account and updates it, retrying on conflicts
+func (r *AccountClaimReconciler) accountSpecUpdate(reqLogger logr.Logger, account *awsv1alpha1.Account, mutate func() error) error {
+	err := controllerutils.UpdateWithRetry(r.Client, account, mutate)
 	if err != nil {
-		reqLogger.Error(err, fmt.Sprintf("Account spec update for %s failed", account.Name))
+		reqLogger.Error(err, "Account spec update failed", "account", account.Name)
 	}
 	return err
 }
@@ -1019,12 +1311,14 @@ func updateClaimedAccountFields(reqLogger logr.Logger, awsAccount *awsv1alpha1.A
 	// Set link on Account
 	awsAccount.Spec.ClaimLink = awsAccountClaim.ObjectMeta.Name
 	awsAccount.Spec.ClaimLinkNamespace = awsAccountClaim.ObjectMeta.Namespace
+	awsAccount.Spec.ClaimLinkUID = string(awsAccountClaim.UID)
+	awsAccount.Spec.ClusterID = awsAccountClaim.Labels[awsv1alpha1.ClusterIDLabel]
 
 	// Carry over LegalEntity data from the claim to the account
 	awsAccount.Spec.LegalEntity.ID = awsAccountClaim.Spec.LegalEntity.ID
 	awsAccount.Spec.LegalEntity.Name = awsAccountClaim.Spec.LegalEntity.Name
 
-	reqLogger.Info(fmt.Sprintf("Account %s ClaimLink set to AccountClaim %s and carried over LegalEntity ID %s", awsAccount.Name, awsAccountClaim.Name, awsAccount.Spec.LegalEntity.ID))
+	reqLogger.Info("Account ClaimLink set to AccountClaim and carried over LegalEntity", "account", awsAccount.Name, "awsAccountID", awsAccount.Spec.AwsAccountID, "claim", awsAccountClaim.Name, "legalEntityID", awsAccount.Spec.LegalEntity.ID)
 }
 
 func setAccountClaimStatus(reqLogger logr.Logger, awsAccount *awsv1alpha1.Account, awsAccountClaim *awsv1alpha1.AccountClaim) {
@@ -1039,7 +1333,15 @@ func setAccountClaimStatus(reqLogger logr.Logger, awsAccount *awsv1alpha1.Accoun
 		awsAccountClaim.Spec.BYOCAWSAccountID != "",
 	)
 	awsAccountClaim.Status.State = awsv1alpha1.ClaimStatusReady
-	reqLogger.Info(fmt.Sprintf("Account %s condition status updated", awsAccountClaim.Name))
+	awsAccountClaim.Status.IAMUserName = awsAccount.Status.IAMUserName
+	setAccountClaimFulfillmentDuration(awsAccountClaim)
+	reqLogger.Info("AccountClaim condition status updated", "claim", awsAccountClaim.Name)
+}
+
+// setAccountClaimFulfillmentDuration records how long the claim took to become Ready since it was created
+func setAccountClaimFulfillmentDuration(accountClaim *awsv1alpha1.AccountClaim) {
+	fulfillmentDuration := time.Since(accountClaim.GetCreationTimestamp().Time)
+	localmetrics.Collector.SetAccountClaimFulfillmentDuration(accountClaim.Spec.BYOCAWSAccountID != "", fulfillmentDuration.Seconds())
 }
 
 // setAccountLink sets AccountClaim.Spec.AccountLink to Account.ObjectMetadata.Name
@@ -1050,7 +1352,43 @@ func setAccountLinkOnAccountClaim(reqLogger logr.Logger, awsAccount *awsv1alpha1
 	}
 	// Set link on AccountClaim
 	awsAccountClaim.Spec.AccountLink = awsAccount.ObjectMeta.Name
-	reqLogger.Info(fmt.Sprintf("Linked claim %s to account %s", awsAccountClaim.Name, awsAccount.Name))
+	reqLogger.Info("Linked claim to account", "claim", awsAccountClaim.Name, "account", awsAccount.Name, "awsAccountID", awsAccount.Spec.AwsAccountID)
+}
+
+// setInvalidAccountClaim sets the claim in error for failing its validation, and returns the validation error
+func (r *AccountClaimReconciler) setInvalidAccountClaim(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim, validateErr error) error {
+	// Figure the reason for our failure
+	errReason := validateErr.Error()
+	// Update AccountClaim status
+	err := controllerutils.UpdateStatusWithRetry(r.Client, accountClaim, func() error {
+		controllerutils.SetAccountClaimStatus(
+			accountClaim,
+			"Invalid AccountClaim",
+			errReason,
+			awsv1alpha1.InvalidAccountClaim,
+			awsv1alpha1.ClaimStatusError,
+		)
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to Update AccountClaim Status")
+	}
+	return validateErr
+}
+
+// claimRegions returns the names of the regions of the claim, the default region for a claim without any. The
+// first one is the region of the cluster.
+func claimRegions(accountClaim *awsv1alpha1.AccountClaim) []string {
+	regions := []string{}
+	for _, region := range accountClaim.Spec.Aws.Regions {
+		if region.Name != "" {
+			regions = append(regions, region.Name)
+		}
+	}
+	if len(regions) == 0 {
+		regions = append(regions, config.GetDefaultRegion())
+	}
+	return regions
 }
 
 func claimIsSatisfied(accountClaim *awsv1alpha1.AccountClaim) bool {
@@ -1082,6 +1420,8 @@ func populateBYOCSpec(account *awsv1alpha1.Account, accountClaim *awsv1alpha1.Ac
 	account.Spec.AwsAccountID = accountClaim.Spec.BYOCAWSAccountID
 	account.Spec.ClaimLink = accountClaim.ObjectMeta.Name
 	account.Spec.ClaimLinkNamespace = accountClaim.ObjectMeta.Namespace
+	account.Spec.ClaimLinkUID = string(accountClaim.UID)
+	account.Spec.ClusterID = accountClaim.Labels[awsv1alpha1.ClusterIDLabel]
 	account.Spec.LegalEntity = accountClaim.Spec.LegalEntity
 	account.Spec.ManualSTSMode = accountClaim.Spec.ManualSTSMode
 }
@@ -1089,15 +1429,24 @@ func populateBYOCSpec(account *awsv1alpha1.Account, accountClaim *awsv1alpha1.Ac
 // SetupWithManager sets up the controller with the Manager.
 func (r *AccountClaimReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.awsClientBuilder = &awsclient.Builder{}
+	r.recorder = mgr.GetEventRecorderFor(controllerName)
+	r.accountWatcher = totalaccountwatcher.TotalAccountWatcher
 	maxReconciles, err := controllerutils.GetControllerMaxReconciles(controllerName)
 	if err != nil {
 		log.Error(err, "missing max reconciles for controller", "controller", controllerName)
 	}
 
 	rwm := controllerutils.NewReconcilerWithMetrics(r, controllerName)
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&awsv1alpha1.AccountClaim{}).
-		Owns(&awsv1alpha1.Account{}).
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		For(&awsv1alpha1.AccountClaim{}, builder.WithPredicates(controllerutils.InShardFilter, controllerutils.IgnoreReconcileOutcomeUpdates)).
+		Owns(&awsv1alpha1.Account{}, builder.WithPredicates(controllerutils.IgnoreReconcileOutcomeUpdates))
+	// Watching a kind that isn't installed would keep the controller from starting
+	if hiveInstalled(mgr.GetRESTMapper()) {
+		controllerBuilder = controllerBuilder.Watches(&source.Kind{Type: newClusterDeployment()}, handler.EnqueueRequestsFromMapFunc(r.accountClaimsForClusterDeployment))
+	} else {
+		log.Info("Hive isn't installed, not watching ClusterDeployments")
+	}
+	return controllerBuilder.
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxReconciles,
 		}).Complete(rwm)
@@ -5,10 +5,7 @@ import (
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/iam"
-	"github.com/aws/aws-sdk-go/service/route53"
-	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"go.uber.org/mock/gomock"
 	apis "github.com/openshift/aws-account-operator/api"
@@ -16,6 +13,7 @@ import (
 	"github.com/openshift/aws-account-operator/config"
 	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
 	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
 	"github.com/openshift/aws-account-operator/test/fixtures"
 	v1 "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
@@ -110,17 +108,14 @@ var _ = Describe("AccountClaim", func() {
 		Context("AccountClaim is marked for Deletion", func() {
 
 			var (
-				objs              []runtime.Object
-				orgAccessRoleName string
-				roleSessionName   string
-				orgAccessArn      string
+				objs            []runtime.Object
+				roleSessionName string
 			)
 
 			BeforeEach(func() {
-				orgAccessRoleName = "OrganizationAccountAccessRole"
 				roleSessionName = "awsAccountOperator"
-				orgAccessArn = "arn:aws:iam:::role/OrganizationAccountAccessRole"
 				accountClaim.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				accountClaim.UID = "claim-uid"
 				accountClaim.SetFinalizers(append(accountClaim.GetFinalizers(), accountClaimFinalizer))
 
 				account := &awsv1alpha1.Account{
@@ -136,171 +131,140 @@ var _ = Describe("AccountClaim", func() {
 					},
 				}
 
-				objs = []runtime.Object{accountClaim, account}
-			})
-
-			It("should delete AccountClaim", func() {
-				r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
-
-				mockAWSClient := mock.GetMockClient(r.awsClientBuilder)
-				// Create empty empy aws responses.
-				lhzo := &route53.ListHostedZonesOutput{
-					HostedZones: []*route53.HostedZone{},
-					IsTruncated: aws.Bool(false),
-				}
-				lbo := &s3.ListBucketsOutput{
-					Buckets: []*s3.Bucket{},
-				}
-				dvpcesco := &ec2.DescribeVpcEndpointServiceConfigurationsOutput{
-					ServiceConfigurations: []*ec2.ServiceConfiguration{},
-				}
-				dso := &ec2.DescribeSnapshotsOutput{
-					Snapshots: []*ec2.Snapshot{},
-				}
-				dvo := &ec2.DescribeVolumesOutput{
-					Volumes: []*ec2.Volume{},
+				configMap := &v1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      awsv1alpha1.DefaultConfigMap,
+						Namespace: awsv1alpha1.AccountCrNamespace,
+					},
 				}
 
-				mockAWSClient.EXPECT().AssumeRole(&sts.AssumeRoleInput{
-					DurationSeconds: aws.Int64(3600),
-					RoleArn:         &orgAccessArn,
-					RoleSessionName: &roleSessionName,
-				}).Return(&sts.AssumeRoleOutput{
-					AssumedRoleUser: &sts.AssumedRoleUser{
-						Arn:           aws.String(fmt.Sprintf("aws:::%s/%s", orgAccessRoleName, roleSessionName)),
-						AssumedRoleId: aws.String(fmt.Sprintf("%s/%s", orgAccessRoleName, roleSessionName)),
-					},
-					Credentials: &sts.Credentials{
-						AccessKeyId:     aws.String("ACCESS_KEY"),
-						SecretAccessKey: aws.String("SECRET_KEY"),
-						SessionToken:    aws.String("SESSION_TOKEN"),
-					},
-					PackedPolicySize: aws.Int64(40),
-				}, nil)
-				mockAWSClient.EXPECT().ListHostedZones(gomock.Any()).Return(lhzo, nil)
-				mockAWSClient.EXPECT().ListBuckets(gomock.Any()).Return(lbo, nil)
-				mockAWSClient.EXPECT().DescribeVpcEndpointServiceConfigurations(gomock.Any()).Return(dvpcesco, nil)
-				mockAWSClient.EXPECT().DescribeSnapshots(gomock.Any()).Return(dso, nil)
-				mockAWSClient.EXPECT().DescribeVolumes(gomock.Any()).Return(dvo, nil)
+				objs = []runtime.Object{accountClaim, account, configMap}
+			})
 
-				// Confirm that the accountclaim exists from the client's perspective
-				ac := awsv1alpha1.AccountClaim{}
-				err = r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, &ac)
-				Expect(err).NotTo(HaveOccurred())
+			It("should queue the account cleanup and delete AccountClaim", func() {
+				r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
 
 				_, err := r.Reconcile(context.TODO(), req)
 				Expect(err).ToNot(HaveOccurred())
 
-				// With the finalizer removed, the AccountClaim should have been deleted
+				// With the finalizer removed, the AccountClaim should have been deleted without waiting for the cleanup
+				ac := awsv1alpha1.AccountClaim{}
 				err = r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, &ac)
 				Expect(k8serr.IsNotFound(err)).To(BeTrue())
 
-				// Ensure the non-ccs account has been reset as expected.
-				acc := awsv1alpha1.Account{}
-				err = r.Client.Get(context.TODO(), types.NamespacedName{Name: ac.Spec.AccountLink, Namespace: awsv1alpha1.AccountCrNamespace}, &acc)
+				cleanup := awsv1alpha1.AccountCleanup{}
+				err = r.Client.Get(context.TODO(), types.NamespacedName{Name: "osd-creds-mgmt-aaabbb-claim-uid", Namespace: awsv1alpha1.AccountCrNamespace}, &cleanup)
 				Expect(err).NotTo(HaveOccurred())
-				Expect(acc.Spec.ClaimLink).To(BeEmpty())
-				Expect(acc.Spec.ClaimLinkNamespace).To(BeEmpty())
-				Expect(acc.Status.State).To(Equal(string(awsv1alpha1.AccountReady)))
-				Expect(acc.Status.Reused).To(BeTrue())
+				Expect(cleanup.Spec.AccountLink).To(Equal("osd-creds-mgmt-aaabbb"))
+				Expect(cleanup.Spec.ClaimName).To(Equal(name))
+				Expect(cleanup.Spec.ClaimNamespace).To(Equal(namespace))
+				Expect(cleanup.Spec.Region).To(Equal("us-east-1"))
+				Expect(cleanup.OwnerReferences).To(HaveLen(1))
+				Expect(cleanup.OwnerReferences[0].Name).To(Equal("osd-creds-mgmt-aaabbb"))
 			})
 
-			It("should retry on a conflict error", func() {
-				r.Client = &possiblyErroringFakeCtrlRuntimeClient{
-					fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build(),
-					true,
-				}
+			It("should queue the cleanup of all the regions of the claim", func() {
+				accountClaim.Spec.Aws.Regions = append(accountClaim.Spec.Aws.Regions, awsv1alpha1.AwsRegions{Name: "eu-west-1"})
+				r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
 
-				mockAWSClient := mock.GetMockClient(r.awsClientBuilder)
-				// Create empty empy aws responses.
-				lhzo := &route53.ListHostedZonesOutput{
-					HostedZones: []*route53.HostedZone{},
-					IsTruncated: aws.Bool(false),
-				}
-				lbo := &s3.ListBucketsOutput{
-					Buckets: []*s3.Bucket{},
-				}
-				dvpcesco := &ec2.DescribeVpcEndpointServiceConfigurationsOutput{
-					ServiceConfigurations: []*ec2.ServiceConfiguration{},
-				}
-				dso := &ec2.DescribeSnapshotsOutput{
-					Snapshots: []*ec2.Snapshot{},
-				}
-				dvo := &ec2.DescribeVolumesOutput{
-					Volumes: []*ec2.Volume{},
-				}
+				_, err := r.Reconcile(context.TODO(), req)
+				Expect(err).ToNot(HaveOccurred())
 
-				mockAWSClient.EXPECT().AssumeRole(&sts.AssumeRoleInput{
-					DurationSeconds: aws.Int64(3600),
-					RoleArn:         &orgAccessArn,
-					RoleSessionName: &roleSessionName,
-				}).Return(&sts.AssumeRoleOutput{
-					AssumedRoleUser: &sts.AssumedRoleUser{
-						Arn:           aws.String(fmt.Sprintf("aws:::%s/%s", orgAccessRoleName, roleSessionName)),
-						AssumedRoleId: aws.String(fmt.Sprintf("%s/%s", orgAccessRoleName, roleSessionName)),
-					},
-					Credentials: &sts.Credentials{
-						AccessKeyId:     aws.String("ACCESS_KEY"),
-						SecretAccessKey: aws.String("SECRET_KEY"),
-						SessionToken:    aws.String("SESSION_TOKEN"),
-					},
-					PackedPolicySize: aws.Int64(40),
-				}, nil)
-				mockAWSClient.EXPECT().ListHostedZones(gomock.Any()).Return(lhzo, nil)
-				mockAWSClient.EXPECT().ListBuckets(gomock.Any()).Return(lbo, nil)
-				mockAWSClient.EXPECT().DescribeVpcEndpointServiceConfigurations(gomock.Any()).Return(dvpcesco, nil)
-				mockAWSClient.EXPECT().DescribeSnapshots(gomock.Any()).Return(dso, nil)
-				mockAWSClient.EXPECT().DescribeVolumes(gomock.Any()).Return(dvo, nil)
+				cleanup := awsv1alpha1.AccountCleanup{}
+				err = r.Client.Get(context.TODO(), types.NamespacedName{Name: "osd-creds-mgmt-aaabbb-claim-uid", Namespace: awsv1alpha1.AccountCrNamespace}, &cleanup)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cleanup.Spec.Region).To(Equal("us-east-1"))
+				Expect(cleanup.Spec.GetRegions()).To(Equal([]string{"us-east-1", "eu-west-1"}))
+			})
 
-				_, err := r.Reconcile(context.TODO(), req)
+			It("should not clean up the account in maintenance mode", func() {
+				objs[2].(*v1.ConfigMap).Data = map[string]string{awsv1alpha1.MaintenanceModeConfigMapKey: "true"}
+				r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
 
-				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(Equal("account CR modified during reset: Conflict"))
+				result, err := r.Reconcile(context.TODO(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
 
-				// Ensure we haven't removed the finalizer.
+				// The AccountClaim keeps its finalizer until the cleanup can run
 				ac := awsv1alpha1.AccountClaim{}
 				err = r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, &ac)
 				Expect(err).NotTo(HaveOccurred())
-				Expect(ac.Finalizers).To(Equal(accountClaim.GetFinalizers()))
+				Expect(ac.GetFinalizers()).To(ContainElement(accountClaimFinalizer))
 			})
 
-			It("should handle aws cleanup errors", func() {
-				r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
+			Context("When the claim is a CCS claim", func() {
+				var account *awsv1alpha1.Account
 
-				mockAWSClient := mock.GetMockClient(r.awsClientBuilder)
-				// Use a bogus error, just so we can fail AWS calls.
-				theErr := awserr.NewBatchError("foo", "bar", []error{})
-				mockAWSClient.EXPECT().AssumeRole(&sts.AssumeRoleInput{
-					DurationSeconds: aws.Int64(3600),
-					RoleArn:         &orgAccessArn,
-					RoleSessionName: &roleSessionName,
-				}).Return(&sts.AssumeRoleOutput{
-					AssumedRoleUser: &sts.AssumedRoleUser{
-						Arn:           aws.String(fmt.Sprintf("aws:::%s/%s", orgAccessRoleName, roleSessionName)),
-						AssumedRoleId: aws.String(fmt.Sprintf("%s/%s", orgAccessRoleName, roleSessionName)),
-					},
-					Credentials: &sts.Credentials{
-						AccessKeyId:     aws.String("ACCESS_KEY"),
-						SecretAccessKey: aws.String("SECRET_KEY"),
-						SessionToken:    aws.String("SESSION_TOKEN"),
-					},
-					PackedPolicySize: aws.Int64(40),
-				}, nil)
-				mockAWSClient.EXPECT().ListHostedZones(gomock.Any()).Return(nil, theErr)
-				mockAWSClient.EXPECT().ListBuckets(gomock.Any()).Return(nil, theErr)
-				mockAWSClient.EXPECT().DescribeVpcEndpointServiceConfigurations(gomock.Any()).Return(nil, theErr)
-				mockAWSClient.EXPECT().DescribeSnapshots(gomock.Any()).Return(nil, theErr)
-				mockAWSClient.EXPECT().DescribeVolumes(gomock.Any()).Return(nil, theErr)
+				BeforeEach(func() {
+					accountClaim.Spec.BYOC = true
+					accountClaim.Spec.BYOCAWSAccountID = "123456789012"
+					// The BYOC secret is gone, the claim must be deletable without it
+					accountClaim.Spec.BYOCSecretRef = awsv1alpha1.SecretRef{Name: "byoc", Namespace: namespace}
+					account = objs[1].(*awsv1alpha1.Account)
+					account.Labels = map[string]string{awsv1alpha1.IAMUserIDLabel: "abcdef"}
+					account.Spec.BYOC = true
+					account.Spec.AwsAccountID = "123456789012"
+				})
 
-				_, err := r.Reconcile(context.TODO(), req)
+				It("should leave the access to the account to the Account finalizer", func() {
+					r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
 
-				Expect(err).To(HaveOccurred())
+					// The mock fails on any AWS call, no role is assumed
+					_, err := r.Reconcile(context.TODO(), req)
+					Expect(err).NotTo(HaveOccurred())
 
-				// Ensure we haven't removed the finalizer.
-				ac := awsv1alpha1.AccountClaim{}
-				err = r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, &ac)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(ac.Finalizers).To(Equal(accountClaim.GetFinalizers()))
+					// The CCS account is deleted rather than reset for reuse
+					err = r.Client.Get(context.TODO(), types.NamespacedName{Name: account.Name, Namespace: account.Namespace}, &awsv1alpha1.Account{})
+					Expect(k8serr.IsNotFound(err)).To(BeTrue())
+				})
+
+				It("should access the account to retire through the support role rather than the BYOC secret", func() {
+					objs[2].(*v1.ConfigMap).Data = map[string]string{awsv1alpha1.CCSAccountRetentionConfigMapKey: "720h"}
+					account.Status.OperatorResources = &awsv1alpha1.OperatorResources{IAMUsers: []string{"osdManagedAdmin-abcdef"}}
+					r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
+
+					supportRoleArn := "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-abcdef"
+					mockAWSClient := mock.GetMockClient(r.awsClientBuilder)
+					mockAWSClient.EXPECT().AssumeRole(&sts.AssumeRoleInput{
+						DurationSeconds: aws.Int64(3600),
+						RoleArn:         &supportRoleArn,
+						RoleSessionName: &roleSessionName,
+					}).Return(&sts.AssumeRoleOutput{
+						AssumedRoleUser: &sts.AssumedRoleUser{
+							Arn:           aws.String(fmt.Sprintf("aws:::ManagedOpenShift-Support-abcdef/%s", roleSessionName)),
+							AssumedRoleId: aws.String(fmt.Sprintf("ManagedOpenShift-Support-abcdef/%s", roleSessionName)),
+						},
+						Credentials: &sts.Credentials{
+							AccessKeyId:     aws.String("ACCESS_KEY"),
+							SecretAccessKey: aws.String("SECRET_KEY"),
+							SessionToken:    aws.String("SESSION_TOKEN"),
+						},
+					}, nil)
+					mockAWSClient.EXPECT().GetUser(&iam.GetUserInput{UserName: aws.String("osdManagedAdmin-abcdef")}).
+						Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "gone", nil))
+
+					_, err := r.Reconcile(context.TODO(), req)
+					Expect(err).NotTo(HaveOccurred())
+
+					retired := &awsv1alpha1.Account{}
+					err = r.Client.Get(context.TODO(), types.NamespacedName{Name: account.Name, Namespace: account.Namespace}, retired)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(retired.IsRetired()).To(BeTrue())
+				})
+
+				It("should not access an account already retired", func() {
+					objs[2].(*v1.ConfigMap).Data = map[string]string{awsv1alpha1.CCSAccountRetentionConfigMapKey: "720h"}
+					account.Status.State = string(awsv1alpha1.AccountRetired)
+					r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
+
+					// The mock fails on any AWS call, no role is assumed
+					_, err := r.Reconcile(context.TODO(), req)
+					Expect(err).NotTo(HaveOccurred())
+
+					ac := awsv1alpha1.AccountClaim{}
+					err = r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, &ac)
+					Expect(k8serr.IsNotFound(err)).To(BeTrue())
+				})
 			})
 
 			It("should do nothing when there are additional finalizers present", func() {
@@ -455,13 +419,16 @@ var _ = Describe("AccountClaim", func() {
 
 				r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(accountClaim).Build()
 
-				_, err := r.Reconcile(context.TODO(), req)
+				result, err := r.Reconcile(context.TODO(), req)
 
-				Expect(err).To(HaveOccurred())
+				// The failure is recorded and retried with a backoff
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
 				ac := awsv1alpha1.AccountClaim{}
 				err = r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, &ac)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(ac.Status.State).To(Equal(awsv1alpha1.ClaimStatusError))
+				Expect(ac.Status.ReconcileFailures).To(Equal(1))
 			})
 
 			It("Should create a BYOC Account", func() {
@@ -782,11 +749,11 @@ var _ = Describe("Mutiple AccountPools Claim", func() {
 					}
 
 					for i := 0; i < reconcileCount; i++ {
-						_, err := r.Reconcile(context.TODO(), req)
-						if i == 0 {
-							Expect(err).NotTo(HaveOccurred())
-						} else {
-							Expect(err).To(HaveOccurred())
+						result, err := r.Reconcile(context.TODO(), req)
+						Expect(err).NotTo(HaveOccurred())
+						if i > 0 {
+							// The failure is retried with a backoff
+							Expect(result.RequeueAfter).To(BeNumerically(">", 0))
 						}
 					}
 
@@ -1007,3 +974,126 @@ var _ = Describe("Mutiple AccountPools Claim", func() {
 		})
 	})
 })
+
+var _ = Describe("CanAccountBeClaimedByAccountClaim", func() {
+	var (
+		account      *awsv1alpha1.Account
+		accountClaim *awsv1alpha1.AccountClaim
+	)
+
+	BeforeEach(func() {
+		account = &awsv1alpha1.Account{Status: awsv1alpha1.AccountStatus{State: AccountReady}}
+		accountClaim = &awsv1alpha1.AccountClaim{}
+	})
+
+	It("should match a Ready unclaimed account", func() {
+		Expect(CanAccountBeClaimedByAccountClaim(account, accountClaim)).To(BeTrue())
+	})
+
+	It("should not match an account AWS Health reports problems for", func() {
+		account.Status.Conditions = []awsv1alpha1.AccountCondition{{
+			Type:   awsv1alpha1.AccountDegraded,
+			Status: v1.ConditionTrue,
+			Reason: awsv1alpha1.AccountAWSHealthIssueReason,
+		}}
+		Expect(CanAccountBeClaimedByAccountClaim(account, accountClaim)).To(BeFalse())
+	})
+
+	It("should not match an account being hibernated", func() {
+		account.Status.Conditions = []awsv1alpha1.AccountCondition{{
+			Type:   awsv1alpha1.AccountHibernated,
+			Status: v1.ConditionFalse,
+			Reason: awsv1alpha1.AccountHibernatingReason,
+		}}
+		Expect(CanAccountBeClaimedByAccountClaim(account, accountClaim)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Claiming accounts with nearly exhausted service quotas", func() {
+	var (
+		r        *AccountClaimReconciler
+		claim    *awsv1alpha1.AccountClaim
+		accounts []runtime.Object
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		configMap := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      awsv1alpha1.DefaultConfigMap,
+				Namespace: awsv1alpha1.AccountCrNamespace,
+			},
+			Data: map[string]string{
+				"accountpool": "my-default-accountpool:\n  default: true",
+			},
+		}
+		accounts = []runtime.Object{configMap}
+		for _, name := range []string{"exhausted-account", "spare-account"} {
+			accounts = append(accounts, &awsv1alpha1.Account{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: awsv1alpha1.AccountCrNamespace},
+				Status:     awsv1alpha1.AccountStatus{State: AccountReady},
+			})
+		}
+		accounts = append(accounts, &awsv1alpha1.AWSAccountQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "exhausted-account", Namespace: awsv1alpha1.AccountCrNamespace},
+			Spec:       awsv1alpha1.AWSAccountQuotaSpec{AccountLink: "exhausted-account"},
+			Status:     awsv1alpha1.AWSAccountQuotaStatus{NearlyExhausted: true},
+		})
+		claim = &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "claim-namespace"},
+		}
+		r = &AccountClaimReconciler{Scheme: scheme.Scheme}
+	})
+
+	It("should prefer the accounts whose service quotas aren't nearly exhausted", func() {
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(accounts...).Build()
+
+		account, err := r.getUnclaimedAccount(testutils.NewTestLogger().Logger(), claim)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(account.Name).To(Equal("spare-account"))
+	})
+
+	It("should hand out an account with nearly exhausted service quotas when no other is left", func() {
+		// Drop the spare account
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(accounts[0], accounts[1], accounts[3]).Build()
+
+		account, err := r.getUnclaimedAccount(testutils.NewTestLogger().Logger(), claim)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(account.Name).To(Equal("exhausted-account"))
+	})
+})
+
+var _ = Describe("Claim secret", func() {
+	var claim *awsv1alpha1.AccountClaim
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		claim = &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "claim-namespace"},
+			Spec:       awsv1alpha1.AccountClaimSpec{AwsCredentialSecret: awsv1alpha1.SecretRef{Name: "aws", Namespace: "claim-namespace"}},
+		}
+	})
+
+	It("Tells whether the secret exists", func() {
+		secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "aws", Namespace: "claim-namespace"}}
+		r := &AccountClaimReconciler{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()}
+		exists, err := r.checkClaimSecretExists(claim)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeTrue())
+
+		r = &AccountClaimReconciler{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()}
+		exists, err = r.checkClaimSecretExists(claim)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("Returns the errors of the secret store", func() {
+		configMap := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+			Data:       map[string]string{awsv1alpha1.SecretBackendConfigMapKey: "filing-cabinet"},
+		}
+		r := &AccountClaimReconciler{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(configMap).Build()}
+		_, err := r.checkClaimSecretExists(claim)
+		Expect(err).To(HaveOccurred())
+	})
+})
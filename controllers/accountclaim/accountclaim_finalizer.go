@@ -2,11 +2,13 @@ package accountclaim
 
 import (
 	"context"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/types"
 
 	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/secretstore"
 	"github.com/openshift/aws-account-operator/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
@@ -14,10 +16,14 @@ import (
 
 func (r *AccountClaimReconciler) addFinalizer(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) error {
 	reqLogger.Info("Adding Finalizer for the AccountClaim")
-	accountClaim.SetFinalizers(append(accountClaim.GetFinalizers(), accountClaimFinalizer))
 
 	// Update CR
-	err := r.Client.Update(context.TODO(), accountClaim)
+	err := utils.UpdateWithRetry(r.Client, accountClaim, func() error {
+		if !utils.Contains(accountClaim.GetFinalizers(), accountClaimFinalizer) {
+			accountClaim.SetFinalizers(append(accountClaim.GetFinalizers(), accountClaimFinalizer))
+		}
+		return nil
+	})
 	if err != nil {
 		reqLogger.Error(err, "Failed to update AccountClaim with finalizer")
 		return err
@@ -27,10 +33,12 @@ func (r *AccountClaimReconciler) addFinalizer(reqLogger logr.Logger, accountClai
 
 func (r *AccountClaimReconciler) removeFinalizer(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim, finalizerName string) error {
 	reqLogger.Info("Removing Finalizer for the AccountClaim")
-	accountClaim.SetFinalizers(utils.Remove(accountClaim.GetFinalizers(), finalizerName))
 
 	// Update CR
-	err := r.Client.Update(context.TODO(), accountClaim)
+	err := utils.UpdateWithRetry(r.Client, accountClaim, func() error {
+		accountClaim.SetFinalizers(utils.Remove(accountClaim.GetFinalizers(), finalizerName))
+		return nil
+	})
 	if err != nil {
 		reqLogger.Error(err, "Failed to remove AccountClaim finalizer")
 		return err
@@ -51,8 +59,10 @@ func (r *AccountClaimReconciler) addBYOCSecretFinalizer(accountClaim *awsv1alpha
 	}
 
 	if !utils.Contains(byocSecret.GetFinalizers(), byocSecretFinalizer) {
-		utils.AddFinalizer(byocSecret, byocSecretFinalizer)
-		err = r.Client.Update(context.TODO(), byocSecret)
+		err = utils.UpdateWithRetry(r.Client, byocSecret, func() error {
+			utils.AddFinalizer(byocSecret, byocSecretFinalizer)
+			return nil
+		})
 		if err != nil {
 			return err
 		}
@@ -77,11 +87,85 @@ func (r *AccountClaimReconciler) removeBYOCSecretFinalizer(accountClaim *awsv1al
 		return err
 	}
 
-	byocSecret.Finalizers = utils.Remove(byocSecret.Finalizers, byocSecretFinalizer)
-	err = r.Client.Update(context.TODO(), byocSecret)
+	err = utils.UpdateWithRetry(r.Client, byocSecret, func() error {
+		byocSecret.Finalizers = utils.Remove(byocSecret.Finalizers, byocSecretFinalizer)
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// cleanUpClaimSecrets deletes the secrets derived from the claim name with one of secretSuffixes, in
+// the claim namespace and in the namespace of the Account CRs, along with the claim's credentials
+// secret, also from the configured secret store. Secrets that are already gone are skipped. The
+// customer's BYOC secret and the secrets of Accounts are never deleted. The names of the deleted
+// secrets are returned.
+func (r *AccountClaimReconciler) cleanUpClaimSecrets(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) ([]string, error) {
+	candidates := []types.NamespacedName{}
+	for _, namespace := range []string{accountClaim.Namespace, awsv1alpha1.AccountCrNamespace} {
+		for _, suffix := range secretSuffixes {
+			candidates = append(candidates, types.NamespacedName{Name: accountClaim.Name + "-" + suffix, Namespace: namespace})
+		}
+	}
+	if accountClaim.Spec.AwsCredentialSecret.Name != "" {
+		candidates = append(candidates, types.NamespacedName{Name: accountClaim.Spec.AwsCredentialSecret.Name, Namespace: accountClaim.Spec.AwsCredentialSecret.Namespace})
+	}
+	byocSecret := types.NamespacedName{Name: accountClaim.Spec.BYOCSecretRef.Name, Namespace: accountClaim.Spec.BYOCSecretRef.Namespace}
+
+	deleted := []string{}
+	seen := map[types.NamespacedName]bool{}
+	for _, candidate := range candidates {
+		if seen[candidate] || candidate == byocSecret {
+			continue
+		}
+		seen[candidate] = true
+
+		secret := &corev1.Secret{}
+		err := r.Client.Get(context.TODO(), candidate, secret)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				continue
+			}
+			return deleted, err
+		}
+		if secret.Labels[awsv1alpha1.SecretOwnerKindLabel] == "Account" {
+			continue
+		}
+
+		err = r.Client.Delete(context.TODO(), secret)
+		if err != nil && !k8serr.IsNotFound(err) {
+			return deleted, err
+		}
+		deleted = append(deleted, candidate.String())
+	}
+
+	// The credentials may be kept outside of Kubernetes
+	if accountClaim.Spec.AwsCredentialSecret.Name != "" {
+		store, err := secretstore.New(r.Client)
+		if err != nil {
+			return deleted, err
+		}
+		if store.Backend() != secretstore.BackendKubernetes {
+			key := claimSecretKey(accountClaim)
+			_, err = store.Get(context.TODO(), key)
+			if err == nil {
+				err = store.Delete(context.TODO(), key)
+				if err != nil {
+					return deleted, err
+				}
+				deleted = append(deleted, store.Backend()+":"+key.String())
+			} else if !k8serr.IsNotFound(err) {
+				return deleted, err
+			}
+		}
+	}
+
+	if len(deleted) > 0 {
+		reqLogger.Info("Deleted AccountClaim secrets", "secrets", deleted)
+		utils.RecordEvent(r.recorder, accountClaim, corev1.EventTypeNormal, utils.EventReasonSecretsDeleted, "Deleted secrets %s", strings.Join(deleted, ", "))
+	}
+	return deleted, nil
+}
@@ -164,6 +164,51 @@ var _ = Describe("AccountClaim", func() {
 			})
 		})
 
+		When("Cleaning up the claim secrets", func() {
+			newSecret := func(name, namespace string) *corev1.Secret {
+				return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+			}
+
+			It("deletes the secrets of every suffix in both namespaces", func() {
+				accountClaim.Spec.AwsCredentialSecret = v1alpha1.SecretRef{Name: "aws", Namespace: namespace}
+				objs := []runtime.Object{
+					accountClaim,
+					newSecret(name+"-secret", namespace),
+					newSecret(name+"-sts-secret", awsv1alpha1.AccountCrNamespace),
+					newSecret("aws", namespace),
+				}
+				r.Client = fake.NewClientBuilder().WithRuntimeObjects(objs...).Build()
+
+				deleted, err := r.cleanUpClaimSecrets(nullLogger, accountClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deleted).To(ConsistOf(
+					namespace+"/"+name+"-secret",
+					awsv1alpha1.AccountCrNamespace+"/"+name+"-sts-secret",
+					namespace+"/aws",
+				))
+
+				secrets := &corev1.SecretList{}
+				Expect(r.Client.List(context.TODO(), secrets)).To(Succeed())
+				Expect(secrets.Items).To(BeEmpty())
+			})
+
+			It("keeps the BYOC secret and the secrets of Accounts", func() {
+				accountClaim.Spec.BYOCSecretRef = v1alpha1.SecretRef{Name: name + "-secret", Namespace: namespace}
+				accountSecret := newSecret(name+"-secret", awsv1alpha1.AccountCrNamespace)
+				accountSecret.Labels = map[string]string{awsv1alpha1.SecretOwnerKindLabel: "Account"}
+				objs := []runtime.Object{accountClaim, newSecret(name+"-secret", namespace), accountSecret}
+				r.Client = fake.NewClientBuilder().WithRuntimeObjects(objs...).Build()
+
+				deleted, err := r.cleanUpClaimSecrets(nullLogger, accountClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deleted).To(BeEmpty())
+
+				secrets := &corev1.SecretList{}
+				Expect(r.Client.List(context.TODO(), secrets)).To(Succeed())
+				Expect(secrets.Items).To(HaveLen(2))
+			})
+		})
+
 		When("Updating the byoc secret finalizers", func() {
 
 			BeforeEach(func() {
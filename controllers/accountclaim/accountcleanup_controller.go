@@ -0,0 +1,490 @@
+package accountclaim
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/controllers/account"
+	"github.com/openshift/aws-account-operator/pkg/audit"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
+	"github.com/openshift/aws-account-operator/pkg/cloudevents"
+	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/notifier"
+	"github.com/openshift/aws-account-operator/pkg/tracing"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+const (
+	cleanupControllerName = "accountcleanup"
+	// accountCleanupMaxAttempts is how many times a cleanup fails before it's given up and its account set to Failed
+	accountCleanupMaxAttempts = 5
+)
+
+var cleanupLog = logf.Log.WithName("controller_accountcleanup")
+
+// AccountCleanupReconciler cleans up the accounts released by deleted AccountClaims and returns them to the pool
+type AccountCleanupReconciler struct {
+	client.Client
+	Scheme           *runtime.Scheme
+	awsClientBuilder awsclient.IBuilder
+	recorder         record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=accountcleanups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=accountcleanups/status,verbs=get;update;patch
+
+// NewAccountCleanupReconciler initializes AccountCleanupReconciler
+func NewAccountCleanupReconciler(client client.Client, scheme *runtime.Scheme, awsClientBuilder awsclient.IBuilder) *AccountCleanupReconciler {
+	return &AccountCleanupReconciler{
+		Client:           client,
+		Scheme:           scheme,
+		awsClientBuilder: awsClientBuilder,
+	}
+}
+
+// accountCleanupName returns the name of the AccountCleanup of an account after a claim. It's derived from the UID
+// of the claim, so a finalizer retried after the cleanup was queued finds it.
+func accountCleanupName(accountName string, accountClaim *awsv1alpha1.AccountClaim) string {
+	return accountName + "-" + string(accountClaim.UID)
+}
+
+// queueAccountCleanup creates the AccountCleanup of the account released by the deleted claim. It's owned by the
+// Account, so it's deleted with it.
+func (r *AccountClaimReconciler) queueAccountCleanup(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim, reusedAccount *awsv1alpha1.Account) error {
+	regions := claimRegions(accountClaim)
+	cleanup := &awsv1alpha1.AccountCleanup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      accountCleanupName(reusedAccount.Name, accountClaim),
+			Namespace: awsv1alpha1.AccountCrNamespace,
+		},
+		Spec: awsv1alpha1.AccountCleanupSpec{
+			AccountLink:       reusedAccount.Name,
+			AwsAccountID:      reusedAccount.Spec.AwsAccountID,
+			ClaimName:         accountClaim.Name,
+			ClaimNamespace:    accountClaim.Namespace,
+			ClaimUID:          string(accountClaim.UID),
+			Region:            regions[0],
+			Regions:           regions,
+			LegalEntity:       accountClaim.Spec.LegalEntity,
+			Exclusions:        accountClaim.GetCleanupExclusions(),
+			Route53Delegation: accountClaim.Spec.Route53Delegation,
+		},
+	}
+	err := controllerutil.SetOwnerReference(reusedAccount, cleanup, r.Scheme)
+	if err != nil {
+		return err
+	}
+
+	err = r.Client.Create(context.TODO(), cleanup)
+	if err != nil {
+		if k8serr.IsAlreadyExists(err) {
+			return nil
+		}
+		reqLogger.Error(err, "Failed to queue the account cleanup")
+		return err
+	}
+	reqLogger.Info("Queued the account cleanup", "accountCleanup", cleanup.Name)
+	utils.RecordEvent(r.recorder, accountClaim, corev1.EventTypeNormal, utils.EventReasonCleanupQueued, "Queued the cleanup of account %s as AccountCleanup %s", reusedAccount.Name, cleanup.Name)
+	return nil
+}
+
+// Reconcile runs the cleanup of an AccountCleanup, until the account is returned to the pool or the cleanup
+// failed accountCleanupMaxAttempts times
+func (r *AccountCleanupReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := cleanupLog.WithValues("Controller", cleanupControllerName, "Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	cleanup := &awsv1alpha1.AccountCleanup{}
+	err := r.Client.Get(context.TODO(), request.NamespacedName, cleanup)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	reqLogger = reqLogger.WithValues("account", cleanup.Spec.AccountLink, "awsAccountID", cleanup.Spec.AwsAccountID)
+
+	// The cleanups of the accounts of other shards are run by other replicas
+	if !utils.InShard(cleanup.Spec.AccountLink) {
+		return reconcile.Result{}, nil
+	}
+
+	if cleanup.Annotations[awsv1alpha1.RerunCleanupAnnotation] == "true" {
+		return reconcile.Result{}, r.rerun(reqLogger, cleanup)
+	}
+
+	if cleanup.IsFinished() {
+		return reconcile.Result{}, nil
+	}
+
+	// The account would be returned to the pool without having been cleaned up
+	if utils.IsAWSMutationPaused(r.Client) {
+		reqLogger.Info("AWS changes are paused, postponing the account cleanup")
+		return reconcile.Result{RequeueAfter: utils.MaintenanceModeRequeueDelay}, nil
+	}
+
+	// Don't start a cleanup the operator won't have time to finish, it's run after the restart
+	if utils.IsShuttingDown(ctx) {
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	reusedAccount := &awsv1alpha1.Account{}
+	err = r.Client.Get(context.TODO(), client.ObjectKey{Name: cleanup.Spec.AccountLink, Namespace: awsv1alpha1.AccountCrNamespace}, reusedAccount)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			// Nothing is left to return to the pool
+			return reconcile.Result{}, r.finish(cleanup, awsv1alpha1.AccountCleanupFailed, "the account no longer exists")
+		}
+		return reconcile.Result{}, err
+	}
+	// Never clean up an account handed out to another claim, e.g. when a finished cleanup is rerun
+	if reusedAccount.Spec.ClaimLink != "" && (reusedAccount.Spec.ClaimLink != cleanup.Spec.ClaimName || reusedAccount.Spec.ClaimLinkNamespace != cleanup.Spec.ClaimNamespace) {
+		reqLogger.Info("Account claimed again, skipping the cleanup", "claim", reusedAccount.Spec.ClaimLink)
+		return reconcile.Result{}, r.finish(cleanup, awsv1alpha1.AccountCleanupFailed, "the account was claimed again")
+	}
+
+	err = utils.UpdateStatusWithRetry(r.Client, cleanup, func() error {
+		cleanup.Status.State = awsv1alpha1.AccountCleanupInProgress
+		cleanup.Status.Interrupted = false
+		if cleanup.Status.StartTime == nil {
+			now := metav1.Now()
+			cleanup.Status.StartTime = &now
+		}
+		return nil
+	})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	ctx = audit.WithFields(ctx, audit.Fields{Account: reusedAccount.Name, AWSAccountID: reusedAccount.Spec.AwsAccountID})
+	ctx, span := tracing.Start(ctx, "Reconcile AccountCleanup", tracing.String("account", reusedAccount.Name))
+	err = r.cleanUpAccount(ctx, reqLogger, cleanup, reusedAccount)
+	span.End(err)
+	switch {
+	case err == nil && !cleanup.Spec.Exclusions.IsEmpty():
+		utils.RecordEvent(r.recorder, cleanup, corev1.EventTypeWarning, utils.EventReasonAccountQuarantined, "Account %s cleaned up except for the exclusions of the claim, it's set to Failed rather than returned to the pool", reusedAccount.Name)
+		utils.RecordEvent(r.recorder, reusedAccount, corev1.EventTypeWarning, utils.EventReasonAccountQuarantined, "Resources of claim %s/%s excluded from the cleanup, the account is set to Failed rather than returned to the pool", cleanup.Spec.ClaimNamespace, cleanup.Spec.ClaimName)
+		notifier.Notify(reqLogger, notifier.ForAccount(notifier.KindCleanupQuarantined, reusedAccount,
+			fmt.Sprintf("Account %s quarantined after its cleanup", reusedAccount.Name),
+			fmt.Sprintf("Resources of claim %s/%s were excluded from the cleanup, the account is set to Failed rather than returned to the pool", cleanup.Spec.ClaimNamespace, cleanup.Spec.ClaimName)))
+		reqLogger.Info("Account cleanup completed, the account is kept out of the pool for its exclusions")
+		return reconcile.Result{}, r.finish(cleanup, awsv1alpha1.AccountCleanupCompleted, "")
+	case err == nil:
+		utils.RecordEvent(r.recorder, cleanup, corev1.EventTypeNormal, utils.EventReasonReuseCompleted, "Account %s cleaned up and returned to the pool", reusedAccount.Name)
+		utils.RecordEvent(r.recorder, reusedAccount, corev1.EventTypeNormal, utils.EventReasonReuseCompleted, "Account cleaned up after claim %s/%s and returned to the pool", cleanup.Spec.ClaimNamespace, cleanup.Spec.ClaimName)
+		cloudevents.Publish(reqLogger, cloudevents.TypeAccountReused, reusedAccount, fmt.Sprintf("Account cleaned up after claim %s/%s and returned to the pool", cleanup.Spec.ClaimNamespace, cleanup.Spec.ClaimName))
+		reqLogger.Info("Account cleanup completed")
+		return reconcile.Result{}, r.finish(cleanup, awsv1alpha1.AccountCleanupCompleted, "")
+	case utils.IsShuttingDownError(err):
+		reqLogger.Info("Account cleanup interrupted by the operator shutdown")
+		return reconcile.Result{Requeue: true}, nil
+	case k8serr.IsConflict(err):
+		// The account was modified during its reset, e.g. by the credentials rotator, this isn't a cleanup failure
+		reqLogger.Info("Account CR modified during the account reset")
+		return reconcile.Result{}, fmt.Errorf("account CR modified during reset: %w", err)
+	}
+	return r.recordFailure(reqLogger, cleanup, reusedAccount, err)
+}
+
+// cleanUpAccount removes the resources of the previous cluster from the account, resets its IAM principals and
+// hardening and returns it to the pool
+func (r *AccountCleanupReconciler) cleanUpAccount(ctx context.Context, reqLogger logr.Logger, cleanup *awsv1alpha1.AccountCleanup, reusedAccount *awsv1alpha1.Account) error {
+	// Clients built here trace their AWS calls under the cleanup span
+	awsClientBuilder := awsclient.WithContext(ctx, r.awsClientBuilder)
+	regions := cleanup.Spec.GetRegions()
+	reqLogger = reqLogger.WithValues("regions", regions)
+
+	// We expect this secret to exist in the same namespace Account CR's are created
+	awsSetupClient, err := awsClientBuilder.GetClient(cleanupControllerName, r.Client, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		reqLogger.Error(err, "failed building operator AWS client")
+		return err
+	}
+
+	// This can not be the default region us-east-1 when cleaning up S3 buckets that live in other regions (if the cluster is not in us-east-1):
+	// e.g. https://github.com/parallelworks/interactive_session/pull/65
+	awsClient, creds, err := stsclient.HandleRoleAssumption(reqLogger, awsClientBuilder, reusedAccount, r.Client, awsSetupClient, cleanup.Spec.Region, reusedAccount.GetAssumeRole(), "")
+	if err != nil {
+		reqLogger.Error(err, "Unable to create aws client")
+		return err
+	}
+	// The EC2 resources of the claim are cleaned up from all its regions
+	awsClients := map[string]awsclient.Client{cleanup.Spec.Region: awsClient}
+	for _, region := range regions[1:] {
+		awsClients[region], err = awsClientBuilder.GetClient(cleanupControllerName, r.Client, awsclient.NewAwsClientInput{
+			AwsCredsSecretIDKey:     *creds.Credentials.AccessKeyId,
+			AwsCredsSecretAccessKey: *creds.Credentials.SecretAccessKey,
+			AwsToken:                *creds.Credentials.SessionToken,
+			AwsRegion:               region,
+			AwsAccountID:            reusedAccount.Spec.AwsAccountID,
+		})
+		if err != nil {
+			reqLogger.Error(err, "Unable to create aws client", "region", region)
+			return err
+		}
+	}
+
+	before := time.Now()
+	// Perform account clean up in AWS
+	utils.RecordEvent(r.recorder, cleanup, corev1.EventTypeNormal, utils.EventReasonCleanupStarted, "Cleaning up account %s for reuse", reusedAccount.Name)
+	err = r.cleanUpAwsAccount(ctx, reqLogger, awsClients, cleanup, reusedAccount)
+	if utils.IsShuttingDownError(err) {
+		return err
+	}
+	if err != nil {
+		localmetrics.Collector.AddAccountReuseCleanupFailure()
+		localmetrics.Collector.AddAccountReuse(false)
+		reqLogger.Error(err, "Failed to clean up AWS account")
+		return err
+	}
+	localmetrics.Collector.SetAccountReusedCleanupDuration(time.Since(before).Seconds())
+
+	// The resources left to remove by hand by a previous run of the cleanup are gone
+	if condition := reusedAccount.GetCondition(awsv1alpha1.AccountManualActionRequired); condition != nil && condition.Status == corev1.ConditionTrue {
+		err = utils.UpdateStatusWithRetry(r.Client, reusedAccount, func() error {
+			reusedAccount.Status.Conditions = utils.SetAccountCondition(reusedAccount.Status.Conditions, awsv1alpha1.AccountManualActionRequired, corev1.ConditionFalse, "ManualActionCompleted", "The cleanup deleted the account resources", utils.UpdateConditionIfReasonOrMessageChange, reusedAccount.Spec.BYOC)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// The previous cluster may have modified the SRE access role, make sure it matches the configmap
+	// before the account is handed out again
+	tags := awsclient.AWSTags.BuildTags(reusedAccount, nil, nil).GetIAMTags()
+	err = account.EnsureSREAccessRole(reqLogger, r.Client, awsClient, reusedAccount, tags)
+	if err != nil {
+		localmetrics.Collector.AddAccountReuse(false)
+		reqLogger.Error(err, "Failed to verify SRE access role")
+		return err
+	}
+
+	// The osdManagedAdmin user may have been deleted out-of-band while the account was claimed, it's rebuilt with a
+	// new access key rather than handing out the credentials of a user that's gone
+	recreated, err := account.EnsureIAMUser(reqLogger, r.Client, awsClient, r.recorder, reusedAccount)
+	if err != nil {
+		localmetrics.Collector.AddAccountReuse(false)
+		reqLogger.Error(err, "Failed to recreate the IAM user")
+		return err
+	}
+
+	// The AccountPool's IAM user policy set may have changed while the account was claimed, a recreated user already
+	// has it
+	if !recreated {
+		err = account.EnsureIAMUserPolicies(reqLogger, r.Client, awsClient, reusedAccount)
+		if err != nil {
+			localmetrics.Collector.AddAccountReuse(false)
+			reqLogger.Error(err, "Failed to reconcile IAM user policies")
+			return err
+		}
+	}
+
+	// The previous tenant may have disabled the S3 Public Access Block or EBS encryption by default, or recreated a
+	// default VPC
+	err = r.hardenReusedAccount(reqLogger, awsClientBuilder, awsClient, reusedAccount, creds)
+	if err != nil {
+		localmetrics.Collector.AddAccountReuse(false)
+		reqLogger.Error(err, "Failed to harden account")
+		return err
+	}
+
+	// An account still holding resources excluded by the claim isn't handed out again
+	if !cleanup.Spec.Exclusions.IsEmpty() {
+		err = resetAccountSpecStatus(reqLogger, r.Client, reusedAccount, cleanup.Spec.LegalEntity, awsv1alpha1.AccountFailed, AccountFailed)
+		if err != nil {
+			reqLogger.Error(err, "Failed to reset account entity")
+		}
+		return err
+	}
+
+	// An account failing the preflight checks, or whose checks are still refreshing, waits in PendingVerification,
+	// where the account controller checks it again before it's Ready
+	failures, pending, err := r.preflightReusedAccount(reqLogger, awsClientBuilder, awsSetupClient, reusedAccount)
+	if err != nil {
+		localmetrics.Collector.AddAccountReuse(false)
+		return err
+	}
+	if len(failures) > 0 || pending {
+		err = resetAccountSpecStatus(reqLogger, r.Client, reusedAccount, cleanup.Spec.LegalEntity, awsv1alpha1.AccountPendingVerification, AccountPendingVerification)
+		if err != nil {
+			reqLogger.Error(err, "Failed to reset account entity")
+			return err
+		}
+		if pending {
+			reqLogger.Info("Reused account waits for its preflight checks to refresh")
+			localmetrics.Collector.AddAccountReuse(true)
+			return nil
+		}
+		err = utils.UpdateStatusWithRetry(r.Client, reusedAccount, func() error {
+			account.SetPreflightCondition(reusedAccount, failures)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		reqLogger.Info("Reused account failed the preflight checks", "failures", failures)
+		utils.RecordEvent(r.recorder, reusedAccount, corev1.EventTypeWarning, utils.EventReasonPreflightFailed, "Account kept out of the pool: %s", strings.Join(failures, "; "))
+		localmetrics.Collector.AddAccountReuse(true)
+		return nil
+	}
+
+	err = resetAccountSpecStatus(reqLogger, r.Client, reusedAccount, cleanup.Spec.LegalEntity, awsv1alpha1.AccountReused, AccountReady)
+	if err != nil {
+		localmetrics.Collector.AddAccountReuse(false)
+		reqLogger.Error(err, "Failed to reset account entity")
+		return err
+	}
+	localmetrics.Collector.AddAccountReuse(true)
+	return nil
+}
+
+// preflightReusedAccount runs the configured preflight checks in the cleaned up account and returns why it failed
+// them, and true while some of the checks are still refreshing. Trusted Advisor is only reachable in the default
+// region, not necessarily the region of the cleanup.
+func (r *AccountCleanupReconciler) preflightReusedAccount(reqLogger logr.Logger, awsClientBuilder awsclient.IBuilder, awsSetupClient awsclient.Client, reusedAccount *awsv1alpha1.Account) ([]string, bool, error) {
+	checks, err := account.GetPreflightChecks(r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Failed to read the preflight checks")
+		return nil, false, err
+	}
+	if len(checks) == 0 {
+		return nil, false, nil
+	}
+	awsClient, _, err := stsclient.HandleRoleAssumption(reqLogger, awsClientBuilder, reusedAccount, r.Client, awsSetupClient, "", reusedAccount.GetAssumeRole(), "")
+	if err != nil {
+		reqLogger.Error(err, "Unable to create aws client")
+		return nil, false, err
+	}
+	return account.RunPreflightChecks(reqLogger, awsClient, checks)
+}
+
+// recordFailure counts a failed run of the cleanup and retries it with a backoff. After accountCleanupMaxAttempts
+// failures, the cleanup is given up and the account set to Failed. A cleanup that found resources to remove by hand
+// is given up on the first failure.
+func (r *AccountCleanupReconciler) recordFailure(reqLogger logr.Logger, cleanup *awsv1alpha1.AccountCleanup, reusedAccount *awsv1alpha1.Account, cleanupErr error) (reconcile.Result, error) {
+	err := utils.UpdateStatusWithRetry(r.Client, cleanup, func() error {
+		cleanup.Status.Attempts++
+		cleanup.Status.LastError = cleanupErr.Error()
+		cleanup.Status.LastErrorCategory = awsv1alpha1.GetAWSErrorCategory(cleanupErr)
+		cleanup.Status.State = awsv1alpha1.AccountCleanupPending
+		return nil
+	})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cleanup.Status.LastErrorCategory == awsv1alpha1.AWSErrorManualActionRequired {
+		return reconcile.Result{}, r.requireManualAction(reqLogger, cleanup, reusedAccount, cleanupErr)
+	}
+	if cleanup.Status.Attempts < accountCleanupMaxAttempts {
+		reqLogger.Info("Account cleanup failed, retrying", "attempts", cleanup.Status.Attempts, "error", cleanupErr.Error(), "errorCategory", cleanup.Status.LastErrorCategory)
+		return utils.RequeueWithBackoff(cleanup.Status.Attempts)
+	}
+
+	reqLogger.Error(cleanupErr, "Account cleanup failed, giving up", "attempts", cleanup.Status.Attempts, "errorCategory", cleanup.Status.LastErrorCategory)
+	utils.RecordEvent(r.recorder, cleanup, corev1.EventTypeWarning, utils.EventReasonCleanupFailed, "Cleanup of account %s failed %d times, the account is set to Failed: %v", reusedAccount.Name, cleanup.Status.Attempts, cleanupErr)
+	notifier.Notify(reqLogger, notifier.ForAccount(notifier.KindCleanupQuarantined, reusedAccount,
+		fmt.Sprintf("Cleanup of account %s given up", reusedAccount.Name),
+		fmt.Sprintf("The cleanup failed %d times, the account is set to Failed: %v", cleanup.Status.Attempts, cleanupErr)))
+	// Update account status and add "Reuse Failed" condition
+	err = resetAccountSpecStatus(reqLogger, r.Client, reusedAccount, cleanup.Spec.LegalEntity, awsv1alpha1.AccountFailed, AccountFailed)
+	if err != nil {
+		reqLogger.Error(err, "Failed updating account status for failed reuse")
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, r.finish(cleanup, awsv1alpha1.AccountCleanupFailed, cleanupErr.Error())
+}
+
+// requireManualAction sets the account to Failed with the ManualActionRequired condition listing the resources the
+// cleanup isn't allowed to delete, and fails the cleanup. Retrying wouldn't delete them, the cleanup is rerun with
+// the rerun annotation once they're removed by hand.
+func (r *AccountCleanupReconciler) requireManualAction(reqLogger logr.Logger, cleanup *awsv1alpha1.AccountCleanup, reusedAccount *awsv1alpha1.Account, cleanupErr error) error {
+	reqLogger.Error(cleanupErr, "Account cleanup requires manual action, giving up")
+	utils.RecordEvent(r.recorder, cleanup, corev1.EventTypeWarning, utils.EventReasonManualActionRequired, "Cleanup of account %s requires manual action, the account is set to Failed: %v", reusedAccount.Name, cleanupErr)
+	notifier.Notify(reqLogger, notifier.ForAccount(notifier.KindCleanupQuarantined, reusedAccount,
+		fmt.Sprintf("Cleanup of account %s requires manual action", reusedAccount.Name),
+		fmt.Sprintf("The cleanup found resources it isn't allowed to delete, the account is set to Failed until they're removed and the cleanup is rerun: %v", cleanupErr)))
+	err := resetAccountSpecStatus(reqLogger, r.Client, reusedAccount, cleanup.Spec.LegalEntity, awsv1alpha1.AccountFailed, AccountFailed)
+	if err != nil {
+		reqLogger.Error(err, "Failed updating account status for failed reuse")
+		return err
+	}
+	err = utils.UpdateStatusWithRetry(r.Client, reusedAccount, func() error {
+		reusedAccount.Status.Conditions = utils.SetAccountCondition(reusedAccount.Status.Conditions, awsv1alpha1.AccountManualActionRequired, corev1.ConditionTrue, string(awsv1alpha1.AccountManualActionRequired), cleanupErr.Error(), utils.UpdateConditionIfReasonOrMessageChange, reusedAccount.Spec.BYOC)
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed setting the ManualActionRequired condition of the account")
+		return err
+	}
+	return r.finish(cleanup, awsv1alpha1.AccountCleanupFailed, cleanupErr.Error())
+}
+
+// finish records that the cleanup completed or failed for good
+func (r *AccountCleanupReconciler) finish(cleanup *awsv1alpha1.AccountCleanup, state awsv1alpha1.AccountCleanupState, lastError string) error {
+	return utils.UpdateStatusWithRetry(r.Client, cleanup, func() error {
+		now := metav1.Now()
+		cleanup.Status.State = state
+		cleanup.Status.CompletionTime = &now
+		if lastError != "" {
+			cleanup.Status.LastError = lastError
+		}
+		return nil
+	})
+}
+
+// rerun resets the status of the cleanup, so all its steps are run again, and removes the annotation requesting it
+func (r *AccountCleanupReconciler) rerun(reqLogger logr.Logger, cleanup *awsv1alpha1.AccountCleanup) error {
+	reqLogger.Info("Running the account cleanup again on request")
+	err := utils.UpdateStatusWithRetry(r.Client, cleanup, func() error {
+		cleanup.Status = awsv1alpha1.AccountCleanupStatus{State: awsv1alpha1.AccountCleanupPending}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// The update of the annotation triggers the run
+	return utils.UpdateWithRetry(r.Client, cleanup, func() error {
+		delete(cleanup.Annotations, awsv1alpha1.RerunCleanupAnnotation)
+		return nil
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AccountCleanupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.awsClientBuilder = &awsclient.Builder{}
+	r.recorder = mgr.GetEventRecorderFor(cleanupControllerName)
+	maxReconciles, err := utils.GetControllerMaxReconciles(cleanupControllerName)
+	if err != nil {
+		cleanupLog.Error(err, "missing max reconciles for controller", "controller", cleanupControllerName)
+	}
+
+	rwm := utils.NewReconcilerWithMetrics(r, cleanupControllerName)
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&awsv1alpha1.AccountCleanup{}, builder.WithPredicates(utils.SpecOrMetadataChanged)).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: maxReconciles,
+		}).Complete(rwm)
+}
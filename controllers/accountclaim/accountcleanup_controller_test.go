@@ -0,0 +1,646 @@
+package accountclaim
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3control"
+	"github.com/aws/aws-sdk-go/service/sts"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AccountCleanup", func() {
+	var (
+		orgAccessRoleName = "OrganizationAccountAccessRole"
+		roleSessionName   = "awsAccountOperator"
+		orgAccessArn      = "arn:aws:iam:::role/OrganizationAccountAccessRole"
+		cleanupName       = types.NamespacedName{Name: "osd-creds-mgmt-aaabbb-claim-uid", Namespace: awsv1alpha1.AccountCrNamespace}
+		accountName       = types.NamespacedName{Name: "osd-creds-mgmt-aaabbb", Namespace: awsv1alpha1.AccountCrNamespace}
+		r                 *AccountCleanupReconciler
+		ctrl              *gomock.Controller
+		req               reconcile.Request
+		cleanup           *awsv1alpha1.AccountCleanup
+		objs              []runtime.Object
+	)
+
+	err := apis.AddToScheme(scheme.Scheme)
+	if err != nil {
+		fmt.Printf("failed adding apis to scheme in accountcleanup controller tests")
+	}
+	localmetrics.Collector = localmetrics.NewMetricsCollector(nil)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		r = &AccountCleanupReconciler{
+			Scheme:           scheme.Scheme,
+			awsClientBuilder: &mock.Builder{MockController: ctrl},
+		}
+		req = reconcile.Request{NamespacedName: cleanupName}
+
+		cleanup = &awsv1alpha1.AccountCleanup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cleanupName.Name,
+				Namespace: cleanupName.Namespace,
+			},
+			Spec: awsv1alpha1.AccountCleanupSpec{
+				AccountLink:    accountName.Name,
+				ClaimName:      "testAccountClaim",
+				ClaimNamespace: "myAccountClaimNamespace",
+				ClaimUID:       "claim-uid",
+				Region:         "us-east-1",
+				LegalEntity: awsv1alpha1.LegalEntity{
+					Name: "LegalCorp. Inc.",
+					ID:   "abcdefg123456",
+				},
+			},
+		}
+		account := &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      accountName.Name,
+				Namespace: accountName.Namespace,
+			},
+			Spec: awsv1alpha1.AccountSpec{
+				ClaimLink:          "testAccountClaim",
+				ClaimLinkNamespace: "myAccountClaimNamespace",
+				LegalEntity: awsv1alpha1.LegalEntity{
+					Name: "LegalCorp. Inc.",
+					ID:   "abcdefg123456",
+				},
+			},
+			Status: awsv1alpha1.AccountStatus{
+				State:   AccountReady,
+				Claimed: true,
+			},
+		}
+		configMap := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      awsv1alpha1.DefaultConfigMap,
+				Namespace: awsv1alpha1.AccountCrNamespace,
+			},
+		}
+		objs = []runtime.Object{cleanup, account, configMap}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectAssumeRole := func(mockAWSClient *mock.MockClient) {
+		mockAWSClient.EXPECT().AssumeRole(&sts.AssumeRoleInput{
+			DurationSeconds: aws.Int64(3600),
+			RoleArn:         &orgAccessArn,
+			RoleSessionName: &roleSessionName,
+		}).Return(&sts.AssumeRoleOutput{
+			AssumedRoleUser: &sts.AssumedRoleUser{
+				Arn:           aws.String(fmt.Sprintf("aws:::%s/%s", orgAccessRoleName, roleSessionName)),
+				AssumedRoleId: aws.String(fmt.Sprintf("%s/%s", orgAccessRoleName, roleSessionName)),
+			},
+			Credentials: &sts.Credentials{
+				AccessKeyId:     aws.String("ACCESS_KEY"),
+				SecretAccessKey: aws.String("SECRET_KEY"),
+				SessionToken:    aws.String("SESSION_TOKEN"),
+			},
+			PackedPolicySize: aws.Int64(40),
+		}, nil)
+	}
+
+	expectEmptyAccount := func(mockAWSClient *mock.MockClient) {
+		expectAssumeRole(mockAWSClient)
+		// Create empty aws responses.
+		mockAWSClient.EXPECT().ListHostedZones(gomock.Any()).Return(&route53.ListHostedZonesOutput{
+			HostedZones: []*route53.HostedZone{},
+			IsTruncated: aws.Bool(false),
+		}, nil)
+		mockAWSClient.EXPECT().ListBuckets(gomock.Any()).Return(&s3.ListBucketsOutput{Buckets: []*s3.Bucket{}}, nil)
+		mockAWSClient.EXPECT().DescribeVpcEndpointServiceConfigurations(gomock.Any()).Return(&ec2.DescribeVpcEndpointServiceConfigurationsOutput{
+			ServiceConfigurations: []*ec2.ServiceConfiguration{},
+		}, nil)
+		mockAWSClient.EXPECT().DescribeSnapshots(gomock.Any()).Return(&ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{}}, nil)
+		mockAWSClient.EXPECT().DescribeVolumes(gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{}}, nil)
+		mockAWSClient.EXPECT().ListUsersPages(gomock.Any(), gomock.Any()).Return(nil)
+		// The reused account has no osdManagedAdmin user whose policies need reconciling
+		mockAWSClient.EXPECT().GetUser(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil))
+		mockAWSClient.EXPECT().DescribeRegions(gomock.Any()).Return(&ec2.DescribeRegionsOutput{}, nil)
+		mockAWSClient.EXPECT().PutPublicAccessBlock(gomock.Any()).Return(&s3control.PutPublicAccessBlockOutput{}, nil)
+	}
+
+	expectFailingCleanup := func(mockAWSClient *mock.MockClient) {
+		expectAssumeRole(mockAWSClient)
+		// Use a bogus error, just so we can fail AWS calls.
+		theErr := awserr.NewBatchError("foo", "bar", []error{})
+		mockAWSClient.EXPECT().ListHostedZones(gomock.Any()).Return(nil, theErr)
+		mockAWSClient.EXPECT().ListBuckets(gomock.Any()).Return(nil, theErr)
+		mockAWSClient.EXPECT().DescribeVpcEndpointServiceConfigurations(gomock.Any()).Return(nil, theErr)
+		mockAWSClient.EXPECT().DescribeSnapshots(gomock.Any()).Return(nil, theErr)
+		mockAWSClient.EXPECT().DescribeVolumes(gomock.Any()).Return(nil, theErr)
+		mockAWSClient.EXPECT().ListUsersPages(gomock.Any(), gomock.Any()).Return(theErr)
+	}
+
+	It("should clean up the account and return it to the pool", func() {
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
+		expectEmptyAccount(mock.GetMockClient(r.awsClientBuilder))
+
+		_, err := r.Reconcile(context.TODO(), req)
+		Expect(err).NotTo(HaveOccurred())
+
+		acc := awsv1alpha1.Account{}
+		Expect(r.Client.Get(context.TODO(), accountName, &acc)).To(Succeed())
+		Expect(acc.Spec.ClaimLink).To(BeEmpty())
+		Expect(acc.Spec.ClaimLinkNamespace).To(BeEmpty())
+		Expect(acc.Status.State).To(Equal(string(awsv1alpha1.AccountReady)))
+		Expect(acc.Status.Reused).To(BeTrue())
+
+		stored := awsv1alpha1.AccountCleanup{}
+		Expect(r.Client.Get(context.TODO(), cleanupName, &stored)).To(Succeed())
+		Expect(stored.Status.State).To(Equal(awsv1alpha1.AccountCleanupCompleted))
+		Expect(stored.Status.CompletionTime).NotTo(BeNil())
+
+		report, err := getCleanupReport(r.Client, &stored)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report).NotTo(BeNil())
+		Expect(report.Error).To(BeEmpty())
+		Expect(report.Steps).To(HaveLen(len(cleanupSteps)))
+		Expect(acc.Annotations[awsv1alpha1.CleanupReportAnnotation]).To(ContainSubstring(cleanupReportName(&stored)))
+	})
+
+	It("should recreate the IAM user of the account removed out-of-band", func() {
+		userName := "osdManagedAdmin-aaabbb"
+		account := objs[1].(*awsv1alpha1.Account)
+		account.Status.IAMUserName = userName
+		account.Spec.IAMUserSecret = "osd-creds-mgmt-aaabbb-secret"
+		accountSecret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: account.Spec.IAMUserSecret, Namespace: awsv1alpha1.AccountCrNamespace},
+			Data: map[string][]byte{
+				"aws_user_name":         []byte(userName),
+				"aws_access_key_id":     []byte("AKIAGONE"),
+				"aws_secret_access_key": []byte("gone-secret"),
+			},
+		}
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(append(objs, accountSecret)...).Build()
+		mockAWSClient := mock.GetMockClient(r.awsClientBuilder)
+		expectEmptyAccount(mockAWSClient)
+		mockAWSClient.EXPECT().GetUser(&iam.GetUserInput{UserName: aws.String(userName)}).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil))
+		mockAWSClient.EXPECT().CreateUser(gomock.Any()).Return(&iam.CreateUserOutput{User: &iam.User{UserName: aws.String(userName)}}, nil)
+		mockAWSClient.EXPECT().AttachUserPolicy(gomock.Any()).Return(&iam.AttachUserPolicyOutput{}, nil)
+		mockAWSClient.EXPECT().CreateAccessKey(&iam.CreateAccessKeyInput{UserName: aws.String(userName)}).Return(&iam.CreateAccessKeyOutput{
+			AccessKey: &iam.AccessKey{
+				UserName:        aws.String(userName),
+				AccessKeyId:     aws.String("AKIANEW"),
+				SecretAccessKey: aws.String("new-secret"),
+			},
+		}, nil)
+
+		_, err := r.Reconcile(context.TODO(), req)
+		Expect(err).NotTo(HaveOccurred())
+
+		acc := awsv1alpha1.Account{}
+		Expect(r.Client.Get(context.TODO(), accountName, &acc)).To(Succeed())
+		Expect(acc.Status.State).To(Equal(string(awsv1alpha1.AccountReady)))
+		secret := &v1.Secret{}
+		Expect(r.Client.Get(context.TODO(), types.NamespacedName{Name: accountSecret.Name, Namespace: accountSecret.Namespace}, secret)).To(Succeed())
+		Expect(secret.Data["aws_access_key_id"]).To(Equal([]byte("AKIANEW")))
+	})
+
+	It("should return the conflict when the account is modified during its reset", func() {
+		r.Client = &possiblyErroringFakeCtrlRuntimeClient{
+			fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build(),
+			true,
+		}
+		expectEmptyAccount(mock.GetMockClient(r.awsClientBuilder))
+
+		_, err := r.Reconcile(context.TODO(), req)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("account CR modified during reset: Conflict"))
+
+		// A conflict isn't counted as a failed run
+		stored := awsv1alpha1.AccountCleanup{}
+		Expect(r.Client.Get(context.TODO(), cleanupName, &stored)).To(Succeed())
+		Expect(stored.Status.Attempts).To(BeZero())
+	})
+
+	It("should retry the cleanup with a backoff when AWS calls fail", func() {
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
+		expectFailingCleanup(mock.GetMockClient(r.awsClientBuilder))
+
+		result, err := r.Reconcile(context.TODO(), req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+		stored := awsv1alpha1.AccountCleanup{}
+		Expect(r.Client.Get(context.TODO(), cleanupName, &stored)).To(Succeed())
+		Expect(stored.Status.State).To(Equal(awsv1alpha1.AccountCleanupPending))
+		Expect(stored.Status.Attempts).To(Equal(1))
+		Expect(stored.Status.LastError).NotTo(BeEmpty())
+
+		// The account stays linked to the deleted claim, so it isn't handed out before it's cleaned up
+		acc := awsv1alpha1.Account{}
+		Expect(r.Client.Get(context.TODO(), accountName, &acc)).To(Succeed())
+		Expect(acc.Spec.ClaimLink).To(Equal("testAccountClaim"))
+	})
+
+	It("should set the account to Failed after too many failed runs", func() {
+		cleanup.Status.Attempts = accountCleanupMaxAttempts - 1
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
+		expectFailingCleanup(mock.GetMockClient(r.awsClientBuilder))
+
+		result, err := r.Reconcile(context.TODO(), req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		stored := awsv1alpha1.AccountCleanup{}
+		Expect(r.Client.Get(context.TODO(), cleanupName, &stored)).To(Succeed())
+		Expect(stored.Status.State).To(Equal(awsv1alpha1.AccountCleanupFailed))
+		Expect(stored.Status.Attempts).To(Equal(accountCleanupMaxAttempts))
+
+		acc := awsv1alpha1.Account{}
+		Expect(r.Client.Get(context.TODO(), accountName, &acc)).To(Succeed())
+		Expect(acc.Status.State).To(Equal(string(awsv1alpha1.AccountFailed)))
+	})
+
+	It("should set the account to Failed right away when the cleanup requires manual action", func() {
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
+		mockAWSClient := mock.GetMockClient(r.awsClientBuilder)
+		expectAssumeRole(mockAWSClient)
+		theErr := awserr.NewBatchError("foo", "bar", []error{})
+		mockAWSClient.EXPECT().ListHostedZones(gomock.Any()).Return(nil, theErr)
+		mockAWSClient.EXPECT().DescribeVpcEndpointServiceConfigurations(gomock.Any()).Return(nil, theErr)
+		mockAWSClient.EXPECT().DescribeSnapshots(gomock.Any()).Return(nil, theErr)
+		mockAWSClient.EXPECT().DescribeVolumes(gomock.Any()).Return(nil, theErr)
+		mockAWSClient.EXPECT().ListUsersPages(gomock.Any(), gomock.Any()).Return(theErr)
+		mockAWSClient.EXPECT().ListBuckets(gomock.Any()).Return(&s3.ListBucketsOutput{Buckets: []*s3.Bucket{{Name: aws.String("bucket-locked")}}}, nil)
+		mockAWSClient.EXPECT().GetObjectLockConfiguration(gomock.Any()).Return(&s3.GetObjectLockConfigurationOutput{
+			ObjectLockConfiguration: &s3.ObjectLockConfiguration{ObjectLockEnabled: aws.String(s3.ObjectLockEnabledEnabled)},
+		}, nil)
+
+		result, err := r.Reconcile(context.TODO(), req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		stored := awsv1alpha1.AccountCleanup{}
+		Expect(r.Client.Get(context.TODO(), cleanupName, &stored)).To(Succeed())
+		Expect(stored.Status.State).To(Equal(awsv1alpha1.AccountCleanupFailed))
+		Expect(stored.Status.Attempts).To(Equal(1))
+		Expect(stored.Status.LastErrorCategory).To(Equal(awsv1alpha1.AWSErrorManualActionRequired))
+
+		acc := awsv1alpha1.Account{}
+		Expect(r.Client.Get(context.TODO(), accountName, &acc)).To(Succeed())
+		Expect(acc.Status.State).To(Equal(string(awsv1alpha1.AccountFailed)))
+		condition := acc.GetCondition(awsv1alpha1.AccountManualActionRequired)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(v1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("bucket-locked"))
+	})
+
+	It("should not clean up an account claimed again", func() {
+		objs[1].(*awsv1alpha1.Account).Spec.ClaimLink = "anotherAccountClaim"
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
+
+		_, err := r.Reconcile(context.TODO(), req)
+		Expect(err).NotTo(HaveOccurred())
+
+		stored := awsv1alpha1.AccountCleanup{}
+		Expect(r.Client.Get(context.TODO(), cleanupName, &stored)).To(Succeed())
+		Expect(stored.Status.State).To(Equal(awsv1alpha1.AccountCleanupFailed))
+	})
+
+	It("should reset a finished cleanup when a rerun is requested", func() {
+		cleanup.Annotations = map[string]string{awsv1alpha1.RerunCleanupAnnotation: "true"}
+		cleanup.Status = awsv1alpha1.AccountCleanupStatus{
+			State:          awsv1alpha1.AccountCleanupFailed,
+			Attempts:       accountCleanupMaxAttempts,
+			CompletedSteps: []string{"s3"},
+		}
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
+
+		_, err := r.Reconcile(context.TODO(), req)
+		Expect(err).NotTo(HaveOccurred())
+
+		stored := awsv1alpha1.AccountCleanup{}
+		Expect(r.Client.Get(context.TODO(), cleanupName, &stored)).To(Succeed())
+		Expect(stored.Status.State).To(Equal(awsv1alpha1.AccountCleanupPending))
+		Expect(stored.Status.Attempts).To(BeZero())
+		Expect(stored.Status.CompletedSteps).To(BeEmpty())
+		Expect(stored.Annotations).NotTo(HaveKey(awsv1alpha1.RerunCleanupAnnotation))
+	})
+})
+
+var _ = Describe("S3 bucket deletion", func() {
+	var (
+		ctrl          *gomock.Controller
+		mockAWSClient *mock.MockClient
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+		bucketNotEmptyDelay = 0
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("Deletes the content again while the deleted objects are still listed", func() {
+		versions := &s3.ListObjectVersionsOutput{Versions: []*s3.ObjectVersion{{Key: aws.String("object")}}}
+		gomock.InOrder(
+			mockAWSClient.EXPECT().ListObjectVersions(gomock.Any()).Return(versions, nil),
+			mockAWSClient.EXPECT().BatchDeleteBucketObjectVersions(aws.String("bucket")).Return(nil),
+			mockAWSClient.EXPECT().DeleteBucket(gomock.Any()).Return(nil, awserr.New("BucketNotEmpty", "", nil)),
+			mockAWSClient.EXPECT().ListObjectVersions(gomock.Any()).Return(&s3.ListObjectVersionsOutput{}, nil),
+			mockAWSClient.EXPECT().DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String("bucket")}).Return(&s3.DeleteBucketOutput{}, nil),
+		)
+
+		Expect(deleteBucket(testutils.NewTestLogger().Logger(), mockAWSClient, "bucket")).To(Succeed())
+	})
+
+	It("Gives up on a bucket that never empties", func() {
+		mockAWSClient.EXPECT().ListObjectVersions(gomock.Any()).Return(&s3.ListObjectVersionsOutput{}, nil).Times(bucketNotEmptyAttempts)
+		mockAWSClient.EXPECT().DeleteBucket(gomock.Any()).Return(nil, awserr.New("BucketNotEmpty", "", nil)).Times(bucketNotEmptyAttempts)
+
+		err := deleteBucket(testutils.NewTestLogger().Logger(), mockAWSClient, "bucket")
+		Expect(awsv1alpha1.GetAWSErrorCategory(err)).To(Equal(awsv1alpha1.AWSErrorDependencyViolation))
+	})
+})
+
+var _ = Describe("S3 bucket cleanup", func() {
+	var (
+		ctrl          *gomock.Controller
+		mockAWSClient *mock.MockClient
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("Removes the policies denying access, and reports the buckets it can't delete", func() {
+		denyPolicy := `{"Version":"2012-10-17","Statement":{"Effect":"Deny","Principal":"*","Action":"s3:DeleteBucket","Resource":"*"}}`
+		allowPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"*"}]}`
+		bucketInput := func(name string) *s3.GetBucketPolicyInput { return &s3.GetBucketPolicyInput{Bucket: aws.String(name)} }
+		mockAWSClient.EXPECT().ListBuckets(gomock.Any()).Return(&s3.ListBucketsOutput{Buckets: []*s3.Bucket{
+			{Name: aws.String("bucket-locked")},
+			{Name: aws.String("bucket-denied")},
+			{Name: aws.String("bucket-protected")},
+			{Name: aws.String("bucket-allowed")},
+		}}, nil)
+		mockAWSClient.EXPECT().GetObjectLockConfiguration(&s3.GetObjectLockConfigurationInput{Bucket: aws.String("bucket-locked")}).Return(&s3.GetObjectLockConfigurationOutput{
+			ObjectLockConfiguration: &s3.ObjectLockConfiguration{ObjectLockEnabled: aws.String(s3.ObjectLockEnabledEnabled)},
+		}, nil)
+		mockAWSClient.EXPECT().GetObjectLockConfiguration(gomock.Any()).Return(nil, awserr.New("ObjectLockConfigurationNotFoundError", "", nil)).Times(3)
+		mockAWSClient.EXPECT().GetBucketPolicy(bucketInput("bucket-denied")).Return(&s3.GetBucketPolicyOutput{Policy: aws.String(denyPolicy)}, nil)
+		mockAWSClient.EXPECT().GetBucketPolicy(bucketInput("bucket-protected")).Return(&s3.GetBucketPolicyOutput{Policy: aws.String(denyPolicy)}, nil)
+		mockAWSClient.EXPECT().GetBucketPolicy(bucketInput("bucket-allowed")).Return(&s3.GetBucketPolicyOutput{Policy: aws.String(allowPolicy)}, nil)
+		mockAWSClient.EXPECT().DeleteBucketPolicy(&s3.DeleteBucketPolicyInput{Bucket: aws.String("bucket-denied")}).Return(&s3.DeleteBucketPolicyOutput{}, nil)
+		mockAWSClient.EXPECT().DeleteBucketPolicy(&s3.DeleteBucketPolicyInput{Bucket: aws.String("bucket-protected")}).Return(nil, awserr.New("AccessDenied", "Access Denied", nil))
+		mockAWSClient.EXPECT().ListObjectVersions(gomock.Any()).Return(&s3.ListObjectVersionsOutput{}, nil).Times(2)
+		mockAWSClient.EXPECT().DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String("bucket-denied")}).Return(&s3.DeleteBucketOutput{}, nil)
+		mockAWSClient.EXPECT().DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String("bucket-allowed")}).Return(&s3.DeleteBucketOutput{}, nil)
+
+		recorder := newCleanupRecorder(mockAWSClient, "us-east-1")
+		exclusions := newCleanupExclusions(testutils.NewTestLogger().Logger(), recorder, nil)
+		r := &AccountCleanupReconciler{}
+		awsNotifications, awsErrors := make(chan string, 1), make(chan string, 1)
+		err := r.cleanUpAwsAccountS3(testutils.NewTestLogger().Logger(), exclusions, awsNotifications, awsErrors)
+		Expect(err).To(MatchError(awsv1alpha1.ErrManualActionRequired))
+		Expect(err).To(MatchError(ContainSubstring("bucket-locked, bucket-protected")))
+		Expect(awsv1alpha1.GetAWSErrorCategory(err)).To(Equal(awsv1alpha1.AWSErrorManualActionRequired))
+
+		reports := recorder.stepReports(nil)
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Found).To(Equal(4))
+		Expect(reports[0].Deleted).To(Equal([]string{"bucket-denied", "bucket-allowed"}))
+		Expect(reports[0].Kept).To(Equal([]string{
+			"bucket-locked: object lock enabled",
+			"bucket-protected: bucket policy denying access can't be removed by the operator",
+		}))
+	})
+})
+
+var _ = Describe("EBS snapshot cleanup", func() {
+	var (
+		ctrl          *gomock.Controller
+		mockAWSClient *mock.MockClient
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("Unshares and deletes the snapshots, and keeps the ones it can't delete", func() {
+		snapshot := func(id string, tags ...*ec2.Tag) *ec2.Snapshot {
+			return &ec2.Snapshot{SnapshotId: aws.String(id), Tags: tags}
+		}
+		mockAWSClient.EXPECT().DescribeSnapshots(gomock.Any()).Return(&ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{
+			snapshot("snap-shared"),
+			snapshot("snap-backup", &ec2.Tag{Key: aws.String("aws:backup:source-resource"), Value: aws.String("vol-1")}),
+			snapshot("snap-locked"),
+			snapshot("snap-expired"),
+			snapshot("snap-ami"),
+		}}, nil)
+		mockAWSClient.EXPECT().DescribeLockedSnapshots(gomock.Any()).Return(&ec2.DescribeLockedSnapshotsOutput{Snapshots: []*ec2.LockedSnapshotsInfo{
+			{SnapshotId: aws.String("snap-locked"), LockState: aws.String(ec2.LockStateCompliance)},
+			{SnapshotId: aws.String("snap-expired"), LockState: aws.String(ec2.LockStateExpired)},
+		}}, nil)
+		for _, id := range []string{"snap-shared", "snap-expired", "snap-ami"} {
+			mockAWSClient.EXPECT().ResetSnapshotAttribute(&ec2.ResetSnapshotAttributeInput{
+				SnapshotId: aws.String(id),
+				Attribute:  aws.String(ec2.SnapshotAttributeNameCreateVolumePermission),
+			}).Return(&ec2.ResetSnapshotAttributeOutput{}, nil)
+		}
+		mockAWSClient.EXPECT().DeleteSnapshot(&ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-shared")}).Return(&ec2.DeleteSnapshotOutput{}, nil)
+		mockAWSClient.EXPECT().DeleteSnapshot(&ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-expired")}).Return(&ec2.DeleteSnapshotOutput{}, nil)
+		mockAWSClient.EXPECT().DeleteSnapshot(&ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-ami")}).Return(nil, awserr.New("InvalidSnapshot.InUse", "The snapshot is currently in use by ami-1", nil))
+
+		recorder := newCleanupRecorder(mockAWSClient, "us-east-1")
+		exclusions := newCleanupExclusions(testutils.NewTestLogger().Logger(), recorder, nil)
+		r := &AccountCleanupReconciler{}
+		awsNotifications, awsErrors := make(chan string, 1), make(chan string, 1)
+		Expect(r.cleanUpAwsAccountSnapshots(testutils.NewTestLogger().Logger(), exclusions, awsNotifications, awsErrors)).To(Succeed())
+		Expect(awsErrors).To(BeEmpty())
+
+		reports := recorder.stepReports(nil)
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Found).To(Equal(5))
+		Expect(reports[0].Deleted).To(Equal([]string{"snap-shared", "snap-expired"}))
+		Expect(reports[0].Errored).To(BeEmpty())
+		Expect(reports[0].Kept).To(Equal([]string{
+			"snap-backup: managed by AWS Backup",
+			"snap-locked: locked in compliance mode",
+			"snap-ami: in use by an AMI",
+		}))
+	})
+})
+
+var _ = Describe("EBS volume detachment", func() {
+	var (
+		ctrl          *gomock.Controller
+		mockAWSClient *mock.MockClient
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+		ebsVolumeDetachDelay = 0
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	instance := func(state string) *ec2.DescribeInstancesOutput {
+		return &ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{{
+			InstanceId:     aws.String("i-lingering"),
+			RootDeviceName: aws.String("/dev/xvda"),
+			State:          &ec2.InstanceState{Name: aws.String(state)},
+		}}}}}
+	}
+
+	It("Detaches a volume from an instance stuck in shutting-down, then waits for it", func() {
+		volume := &ec2.Volume{
+			VolumeId: aws.String("vol-data"),
+			State:    aws.String(ec2.VolumeStateInUse),
+			Attachments: []*ec2.VolumeAttachment{{
+				InstanceId: aws.String("i-lingering"),
+				Device:     aws.String("/dev/xvdb"),
+				State:      aws.String(ec2.VolumeAttachmentStateAttached),
+			}},
+		}
+		gomock.InOrder(
+			mockAWSClient.EXPECT().DescribeInstances(&ec2.DescribeInstancesInput{InstanceIds: aws.StringSlice([]string{"i-lingering"})}).Return(instance(ec2.InstanceStateNameShuttingDown), nil),
+			mockAWSClient.EXPECT().DetachVolume(&ec2.DetachVolumeInput{VolumeId: aws.String("vol-data"), InstanceId: aws.String("i-lingering"), Force: aws.Bool(true)}).Return(&ec2.VolumeAttachment{}, nil),
+			mockAWSClient.EXPECT().DescribeVolumes(gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{{State: aws.String(ec2.VolumeStateInUse)}}}, nil),
+			mockAWSClient.EXPECT().DescribeVolumes(&ec2.DescribeVolumesInput{VolumeIds: aws.StringSlice([]string{"vol-data"})}).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{{State: aws.String(ec2.VolumeStateAvailable)}}}, nil),
+		)
+
+		leftToInstance, err := detachEbsVolume(testutils.NewTestLogger().Logger(), mockAWSClient, volume)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(leftToInstance).To(BeFalse())
+	})
+
+	It("Leaves a root volume to be deleted with its instance", func() {
+		volume := &ec2.Volume{
+			VolumeId: aws.String("vol-root"),
+			State:    aws.String(ec2.VolumeStateInUse),
+			Attachments: []*ec2.VolumeAttachment{{
+				InstanceId:          aws.String("i-lingering"),
+				Device:              aws.String("/dev/xvda"),
+				State:               aws.String(ec2.VolumeAttachmentStateAttached),
+				DeleteOnTermination: aws.Bool(true),
+			}},
+		}
+		mockAWSClient.EXPECT().DescribeInstances(gomock.Any()).Return(instance(ec2.InstanceStateNameShuttingDown), nil)
+
+		leftToInstance, err := detachEbsVolume(testutils.NewTestLogger().Logger(), mockAWSClient, volume)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(leftToInstance).To(BeTrue())
+	})
+
+	It("Gives up on a volume that stays attached", func() {
+		mockAWSClient.EXPECT().DescribeVolumes(gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{{State: aws.String(ec2.VolumeStateInUse)}}}, nil).Times(ebsVolumeDetachAttempts)
+
+		Expect(waitForEbsVolumeDetached(testutils.NewTestLogger().Logger(), mockAWSClient, aws.String("vol-data"))).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("Route53 record set deletion", func() {
+	var (
+		ctrl          *gomock.Controller
+		mockAWSClient *mock.MockClient
+		zoneID        = aws.String("/hostedzone/Z1")
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+		route53ChangeDelay = 0
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	deletion := func(name string, values ...string) *route53.Change {
+		records := []*route53.ResourceRecord{}
+		for _, value := range values {
+			records = append(records, &route53.ResourceRecord{Value: aws.String(value)})
+		}
+		return &route53.Change{
+			Action:            aws.String("DELETE"),
+			ResourceRecordSet: &route53.ResourceRecordSet{Name: aws.String(name), Type: aws.String("TXT"), ResourceRecords: records},
+		}
+	}
+
+	It("Splits the changes within the limits of a change batch", func() {
+		changes := []*route53.Change{}
+		for i := 0; i < 1500; i++ {
+			changes = append(changes, deletion(fmt.Sprintf("%d.example.com", i), "value"))
+		}
+		// 40 values of 1000 characters are over the 32000 characters a batch can hold
+		for i := 0; i < 40; i++ {
+			changes = append(changes, deletion(fmt.Sprintf("long-%d.example.com", i), strings.Repeat("a", 1000)))
+		}
+
+		batches := route53ChangeBatches(changes)
+		Expect(batches).To(HaveLen(3))
+		Expect(batches[0].Changes).To(HaveLen(1000))
+		Expect(batches[1].Changes).To(HaveLen(500 + 29))
+		Expect(batches[2].Changes).To(HaveLen(11))
+		Expect(route53ChangeBatches(nil)).To(BeEmpty())
+	})
+
+	It("Isolates the record sets already deleted from a rejected batch", func() {
+		changes := []*route53.Change{deletion("a.example.com", "a"), deletion("gone.example.com", "b"), deletion("c.example.com", "c")}
+		notFound := awserr.New(route53.ErrCodeInvalidChangeBatch, "Tried to delete resource record set [name='gone.example.com.', type='TXT'] but it was not found", nil)
+		batchOf := func(changes ...*route53.Change) *route53.ChangeResourceRecordSetsInput {
+			return &route53.ChangeResourceRecordSetsInput{HostedZoneId: zoneID, ChangeBatch: &route53.ChangeBatch{Changes: changes}}
+		}
+		gomock.InOrder(
+			mockAWSClient.EXPECT().ChangeResourceRecordSets(batchOf(changes...)).Return(nil, notFound),
+			mockAWSClient.EXPECT().ChangeResourceRecordSets(batchOf(changes[0])).Return(nil, awserr.New(route53.ErrCodePriorRequestNotComplete, "", nil)),
+			mockAWSClient.EXPECT().ChangeResourceRecordSets(batchOf(changes[0])).Return(&route53.ChangeResourceRecordSetsOutput{}, nil),
+			mockAWSClient.EXPECT().ChangeResourceRecordSets(batchOf(changes[1:]...)).Return(nil, notFound),
+			mockAWSClient.EXPECT().ChangeResourceRecordSets(batchOf(changes[1])).Return(nil, notFound),
+			mockAWSClient.EXPECT().ChangeResourceRecordSets(batchOf(changes[2])).Return(&route53.ChangeResourceRecordSetsOutput{}, nil),
+		)
+
+		Expect(changeRecordSets(testutils.NewTestLogger().Logger(), mockAWSClient, zoneID, changes)).To(Succeed())
+	})
+
+	It("Fails on a change Route53 can't apply", func() {
+		changes := []*route53.Change{deletion("a.example.com", "a")}
+		mockAWSClient.EXPECT().ChangeResourceRecordSets(gomock.Any()).Return(nil, awserr.New(route53.ErrCodeInvalidChangeBatch, "RRSet of type TXT with DNS name a.example.com. is not permitted", nil))
+
+		Expect(changeRecordSets(testutils.NewTestLogger().Logger(), mockAWSClient, zoneID, changes)).NotTo(Succeed())
+	})
+})
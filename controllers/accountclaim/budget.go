@@ -0,0 +1,209 @@
+package accountclaim
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/budgets"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// defaultBudgetThresholds are the percentages of the budget limit notified about when the configmap doesn't say
+const defaultBudgetThresholds = "80,100"
+
+// budgetDeletedReason is the reason of the CostAnomaly condition once the budget of the claim is deleted
+const budgetDeletedReason = "BudgetDeleted"
+
+// budgetConfig is the AWS Budgets integration configured in the operator configmap
+type budgetConfig struct {
+	// limit is the monthly cost of the budget, in USD
+	limit      string
+	thresholds []float64
+	topicARN   string
+}
+
+// getBudgetConfig returns the AWS Budgets integration configured in the operator configmap, or nil if it's disabled
+func getBudgetConfig(kubeClient client.Client) (*budgetConfig, error) {
+	cm, err := controllerutils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	limit := strings.TrimSpace(cm.Data[awsv1alpha1.BudgetLimitConfigMapKey])
+	if limit == "" {
+		return nil, nil
+	}
+	if amount, err := strconv.ParseFloat(limit, 64); err != nil || amount <= 0 {
+		return nil, fmt.Errorf("invalid %s %q, expected a positive amount of USD", awsv1alpha1.BudgetLimitConfigMapKey, limit)
+	}
+
+	topicARN := strings.TrimSpace(cm.Data[awsv1alpha1.BudgetSNSTopicARNConfigMapKey])
+	if topicARN == "" {
+		return nil, fmt.Errorf("%s is set without %s, the budget notifications would go nowhere", awsv1alpha1.BudgetLimitConfigMapKey, awsv1alpha1.BudgetSNSTopicARNConfigMapKey)
+	}
+
+	thresholds, err := parseBudgetThresholds(cm.Data[awsv1alpha1.BudgetThresholdsConfigMapKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", awsv1alpha1.BudgetThresholdsConfigMapKey, err)
+	}
+	return &budgetConfig{limit: limit, thresholds: thresholds, topicARN: topicARN}, nil
+}
+
+// parseBudgetThresholds parses the comma separated percentages of the budget limit notified about
+func parseBudgetThresholds(data string) ([]float64, error) {
+	if strings.TrimSpace(data) == "" {
+		data = defaultBudgetThresholds
+	}
+	thresholds := []float64{}
+	for _, field := range strings.Split(data, ",") {
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil || threshold <= 0 {
+			return nil, fmt.Errorf("%q isn't a percentage of the budget limit", strings.TrimSpace(field))
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds, nil
+}
+
+// budgetName returns the name of the budget of the AWS account
+func budgetName(awsAccountID string) string {
+	return awsv1alpha1.BudgetNamePrefix + awsAccountID
+}
+
+// getBudgetsClient returns a client of the payer account, where the budgets of the accounts are, and its ID
+func (r *AccountClaimReconciler) getBudgetsClient(reqLogger logr.Logger) (awsclient.Client, string, error) {
+	awsClient, err := r.awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
+		SecretName: controllerutils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		reqLogger.Error(err, "failed building operator AWS client")
+		return nil, "", err
+	}
+	identity, err := awsClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, "", err
+	}
+	return awsClient, aws.StringValue(identity.Account), nil
+}
+
+// ensureBudget creates the AWS Budget of the account of a non-CCS claim in the payer account, filtered on the
+// account, with a notification to the configured SNS topic for each threshold. The notifications reach the operator
+// through the AWS notifications queue and set the CostAnomaly condition of the account. An existing budget is left
+// as is.
+func (r *AccountClaimReconciler) ensureBudget(reqLogger logr.Logger, account *awsv1alpha1.Account) error {
+	budget, err := getBudgetConfig(r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Failed to read the AWS Budgets configuration")
+		return err
+	}
+	if budget == nil || account.Spec.AwsAccountID == "" {
+		return nil
+	}
+
+	awsClient, payerAccountID, err := r.getBudgetsClient(reqLogger)
+	if err != nil {
+		return err
+	}
+	name := budgetName(account.Spec.AwsAccountID)
+	_, err = awsClient.DescribeBudget(&budgets.DescribeBudgetInput{
+		AccountId:  aws.String(payerAccountID),
+		BudgetName: aws.String(name),
+	})
+	if err == nil {
+		return nil
+	}
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) || aerr.Code() != budgets.ErrCodeNotFoundException {
+		return err
+	}
+
+	notifications := []*budgets.NotificationWithSubscribers{}
+	for _, threshold := range budget.thresholds {
+		notifications = append(notifications, &budgets.NotificationWithSubscribers{
+			Notification: &budgets.Notification{
+				NotificationType:   aws.String(budgets.NotificationTypeActual),
+				ComparisonOperator: aws.String(budgets.ComparisonOperatorGreaterThan),
+				Threshold:          aws.Float64(threshold),
+				ThresholdType:      aws.String(budgets.ThresholdTypePercentage),
+			},
+			Subscribers: []*budgets.Subscriber{{
+				SubscriptionType: aws.String(budgets.SubscriptionTypeSns),
+				Address:          aws.String(budget.topicARN),
+			}},
+		})
+	}
+
+	reqLogger.Info("Creating the AWS Budget of the account", "budget", name, "limit", budget.limit)
+	_, err = awsClient.CreateBudget(&budgets.CreateBudgetInput{
+		AccountId: aws.String(payerAccountID),
+		Budget: &budgets.Budget{
+			BudgetName:  aws.String(name),
+			BudgetType:  aws.String(budgets.BudgetTypeCost),
+			TimeUnit:    aws.String(budgets.TimeUnitMonthly),
+			BudgetLimit: &budgets.Spend{Amount: aws.String(budget.limit), Unit: aws.String("USD")},
+			CostFilters: map[string][]*string{"LinkedAccount": {aws.String(account.Spec.AwsAccountID)}},
+		},
+		NotificationsWithSubscribers: notifications,
+	})
+	if err != nil && (!errors.As(err, &aerr) || aerr.Code() != budgets.ErrCodeDuplicateRecordException) {
+		reqLogger.Error(err, "Failed to create the AWS Budget of the account", "budget", name)
+		return err
+	}
+	return nil
+}
+
+// deleteBudget deletes the AWS Budget of the account of a claim, so the costs of the next claim are budgeted from
+// scratch, and clears the CostAnomaly condition of the account
+func (r *AccountClaimReconciler) deleteBudget(reqLogger logr.Logger, account *awsv1alpha1.Account) error {
+	budget, err := getBudgetConfig(r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Failed to read the AWS Budgets configuration")
+		return err
+	}
+	if budget == nil || account.Spec.AwsAccountID == "" {
+		return nil
+	}
+
+	awsClient, payerAccountID, err := r.getBudgetsClient(reqLogger)
+	if err != nil {
+		return err
+	}
+	name := budgetName(account.Spec.AwsAccountID)
+	reqLogger.Info("Deleting the AWS Budget of the account", "budget", name)
+	_, err = awsClient.DeleteBudget(&budgets.DeleteBudgetInput{
+		AccountId:  aws.String(payerAccountID),
+		BudgetName: aws.String(name),
+	})
+	var aerr awserr.Error
+	if err != nil && (!errors.As(err, &aerr) || aerr.Code() != budgets.ErrCodeNotFoundException) {
+		reqLogger.Error(err, "Failed to delete the AWS Budget of the account", "budget", name)
+		return err
+	}
+
+	condition := account.GetCondition(awsv1alpha1.AccountCostAnomaly)
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		return nil
+	}
+	return controllerutils.UpdateStatusWithRetry(r.Client, account, func() error {
+		account.Status.Conditions = controllerutils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountCostAnomaly, corev1.ConditionFalse, budgetDeletedReason, "The budget of the claim was deleted with the claim", controllerutils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+		return nil
+	})
+}
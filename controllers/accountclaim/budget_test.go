@@ -0,0 +1,137 @@
+package accountclaim
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/budgets"
+	"github.com/aws/aws-sdk-go/service/sts"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AWS Budgets", func() {
+	const topicARN = "arn:aws:sns:us-east-1:999999999999:aao-budgets"
+
+	var (
+		ctrl          *gomock.Controller
+		r             *AccountClaimReconciler
+		mockAWSClient *mock.MockClient
+		account       *awsv1alpha1.Account
+		configMap     *corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		ctrl = gomock.NewController(GinkgoT())
+		r = &AccountClaimReconciler{
+			Scheme:           scheme.Scheme,
+			awsClientBuilder: &mock.Builder{MockController: ctrl},
+		}
+		mockAWSClient = mock.GetMockClient(r.awsClientBuilder)
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "osd-creds-mgmt-abcdef", Namespace: awsv1alpha1.AccountCrNamespace},
+			Spec:       awsv1alpha1.AccountSpec{AwsAccountID: "123456789012"},
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+			Data: map[string]string{
+				awsv1alpha1.BudgetLimitConfigMapKey:       "500",
+				awsv1alpha1.BudgetSNSTopicARNConfigMapKey: topicARN,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	buildClient := func() {
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account, configMap).Build()
+	}
+
+	expectPayer := func() {
+		mockAWSClient.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{Account: aws.String("999999999999")}, nil)
+	}
+
+	It("Parses the thresholds, 80% and 100% by default", func() {
+		thresholds, err := parseBudgetThresholds("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(thresholds).To(Equal([]float64{80, 100}))
+
+		thresholds, err = parseBudgetThresholds("50, 90")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(thresholds).To(Equal([]float64{50, 90}))
+
+		_, err = parseBudgetThresholds("50,lots")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Refuses a limit without a topic", func() {
+		delete(configMap.Data, awsv1alpha1.BudgetSNSTopicARNConfigMapKey)
+		buildClient()
+		_, err := getBudgetConfig(r.Client)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Creates the budget of the account in the payer account", func() {
+		buildClient()
+		expectPayer()
+		mockAWSClient.EXPECT().DescribeBudget(gomock.Any()).Return(nil, awserr.New(budgets.ErrCodeNotFoundException, "not found", nil))
+		mockAWSClient.EXPECT().CreateBudget(gomock.Any()).DoAndReturn(func(input *budgets.CreateBudgetInput) (*budgets.CreateBudgetOutput, error) {
+			Expect(aws.StringValue(input.AccountId)).To(Equal("999999999999"))
+			Expect(aws.StringValue(input.Budget.BudgetName)).To(Equal(awsv1alpha1.BudgetNamePrefix + "123456789012"))
+			Expect(aws.StringValue(input.Budget.BudgetLimit.Amount)).To(Equal("500"))
+			Expect(aws.StringValueSlice(input.Budget.CostFilters["LinkedAccount"])).To(Equal([]string{"123456789012"}))
+			Expect(input.NotificationsWithSubscribers).To(HaveLen(2))
+			Expect(aws.StringValue(input.NotificationsWithSubscribers[0].Subscribers[0].Address)).To(Equal(topicARN))
+			return &budgets.CreateBudgetOutput{}, nil
+		})
+
+		Expect(r.ensureBudget(testutils.NewTestLogger().Logger(), account)).To(Succeed())
+	})
+
+	It("Leaves an existing budget as is", func() {
+		buildClient()
+		expectPayer()
+		mockAWSClient.EXPECT().DescribeBudget(gomock.Any()).Return(&budgets.DescribeBudgetOutput{}, nil)
+
+		Expect(r.ensureBudget(testutils.NewTestLogger().Logger(), account)).To(Succeed())
+	})
+
+	It("Doesn't budget accounts while it's disabled", func() {
+		configMap.Data = map[string]string{}
+		buildClient()
+
+		Expect(r.ensureBudget(testutils.NewTestLogger().Logger(), account)).To(Succeed())
+	})
+
+	It("Deletes the budget and clears the CostAnomaly condition", func() {
+		account.Status.Conditions = []awsv1alpha1.AccountCondition{{
+			Type:   awsv1alpha1.AccountCostAnomaly,
+			Status: corev1.ConditionTrue,
+			Reason: awsv1alpha1.AccountBudgetExceededReason,
+		}}
+		buildClient()
+		expectPayer()
+		mockAWSClient.EXPECT().DeleteBudget(gomock.Any()).Return(nil, awserr.New(budgets.ErrCodeNotFoundException, "not found", nil))
+
+		Expect(r.deleteBudget(testutils.NewTestLogger().Logger(), account)).To(Succeed())
+
+		updated := &awsv1alpha1.Account{}
+		Expect(r.Client.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		Expect(updated.GetCondition(awsv1alpha1.AccountCostAnomaly).Status).To(Equal(corev1.ConditionFalse))
+	})
+})
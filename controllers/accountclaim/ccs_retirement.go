@@ -0,0 +1,71 @@
+package accountclaim
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/controllers/account"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ccsAccountRetiredReason is the reason of the Retired condition of CCS accounts whose claim was deleted
+const ccsAccountRetiredReason = "AccountClaimDeleted"
+
+// retireCCSAccount removes the resources the operator created in the CCS account and marks the Account CR
+// Retired with a summary of the cleanup, instead of deleting it. A nil awsClient means the customer account
+// can't be accessed anymore, nothing is removed then.
+func (r *AccountClaimReconciler) retireCCSAccount(reqLogger logr.Logger, awsClient awsclient.Client, ccsAccount *awsv1alpha1.Account) error {
+	if ccsAccount.IsRetired() {
+		return nil
+	}
+
+	summary := "The customer account couldn't be accessed, no resource was removed from it"
+	if awsClient != nil {
+		err := account.CleanUpCCSAccount(reqLogger, r.Client, awsClient, ccsAccount)
+		if err != nil {
+			reqLogger.Error(err, "Failed to clean up CCS account")
+			return err
+		}
+		summary = ccsCleanupSummary(ccsAccount.Status.OperatorResources)
+	}
+
+	reqLogger.Info("Retiring CCS account", "summary", summary)
+	return controllerutils.UpdateStatusWithRetry(r.Client, ccsAccount, func() error {
+		ccsAccount.Status.State = string(awsv1alpha1.AccountRetired)
+		ccsAccount.Status.Conditions = controllerutils.SetAccountCondition(
+			ccsAccount.Status.Conditions,
+			awsv1alpha1.AccountRetired,
+			corev1.ConditionTrue,
+			ccsAccountRetiredReason,
+			summary,
+			controllerutils.UpdateConditionIfReasonOrMessageChange,
+			true,
+		)
+		return nil
+	})
+}
+
+// ccsCleanupSummary describes what the cleanup of a CCS account removed
+func ccsCleanupSummary(resources *awsv1alpha1.OperatorResources) string {
+	if resources.IsEmpty() {
+		return "Removed the IAM users and roles tagged with the account"
+	}
+	removed := []string{}
+	for _, list := range []struct {
+		kind  string
+		names []string
+	}{
+		{"IAM users", resources.IAMUsers},
+		{"IAM roles", resources.IAMRoles},
+		{"secrets", resources.Secrets},
+	} {
+		if len(list.names) > 0 {
+			removed = append(removed, fmt.Sprintf("%s %s", list.kind, strings.Join(list.names, ", ")))
+		}
+	}
+	return "Removed " + strings.Join(removed, "; ")
+}
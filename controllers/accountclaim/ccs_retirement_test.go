@@ -0,0 +1,85 @@
+package accountclaim
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CCS account retirement", func() {
+	var (
+		ctrl          *gomock.Controller
+		r             *AccountClaimReconciler
+		mockAWSClient *mock.MockClient
+		ccsAccount    *awsv1alpha1.Account
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+		ccsAccount = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "osd-creds-mgmt-abcdef", Namespace: awsv1alpha1.AccountCrNamespace},
+			Spec:       awsv1alpha1.AccountSpec{BYOC: true, AwsAccountID: "123456789012"},
+			Status: awsv1alpha1.AccountStatus{
+				State: string(awsv1alpha1.AccountReady),
+				OperatorResources: &awsv1alpha1.OperatorResources{
+					IAMRoles: []string{"ManagedOpenShift-Support-abcdef"},
+				},
+			},
+		}
+		r = &AccountClaimReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(ccsAccount).Build(),
+			Scheme: scheme.Scheme,
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	retiredCondition := func() *awsv1alpha1.AccountCondition {
+		updated := &awsv1alpha1.Account{}
+		Expect(r.Client.Get(context.TODO(), client.ObjectKeyFromObject(ccsAccount), updated)).To(Succeed())
+		Expect(updated.Status.State).To(Equal(string(awsv1alpha1.AccountRetired)))
+		return utils.FindAccountCondition(updated.Status.Conditions, awsv1alpha1.AccountRetired)
+	}
+
+	It("Cleans up the account and records what was removed", func() {
+		mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "role not found", nil))
+
+		Expect(r.retireCCSAccount(testutils.NewTestLogger().Logger(), mockAWSClient, ccsAccount)).To(Succeed())
+		condition := retiredCondition()
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Message).To(Equal("Removed IAM roles ManagedOpenShift-Support-abcdef"))
+	})
+
+	It("Retires an account it can't access anymore without cleaning it up", func() {
+		Expect(r.retireCCSAccount(testutils.NewTestLogger().Logger(), nil, ccsAccount)).To(Succeed())
+		condition := retiredCondition()
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Message).To(ContainSubstring("couldn't be accessed"))
+	})
+
+	It("Summarizes every kind of removed resource", func() {
+		Expect(ccsCleanupSummary(&awsv1alpha1.OperatorResources{
+			IAMUsers: []string{"osdManagedAdmin-abcdef"},
+			Secrets:  []string{"aws-account-operator/osd-creds-mgmt-abcdef-secret"},
+		})).To(Equal("Removed IAM users osdManagedAdmin-abcdef; secrets aws-account-operator/osd-creds-mgmt-abcdef-secret"))
+		Expect(ccsCleanupSummary(nil)).To(Equal("Removed the IAM users and roles tagged with the account"))
+	})
+})
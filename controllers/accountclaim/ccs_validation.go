@@ -0,0 +1,240 @@
+package accountclaim
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/controllers/account"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// Reasons of the ValidationFailed condition
+	ccsValidationReasonInvalidCredentials  = "InvalidCredentials"
+	ccsValidationReasonAccountIDMismatch   = "AccountIDMismatch"
+	ccsValidationReasonInsufficientQuota   = "InsufficientQuota"
+	ccsValidationReasonConflictingIAMUser  = "ConflictingIAMUser"
+	ccsValidationReasonValidationSucceeded = "ValidationSucceeded"
+
+	// ccsValidationRequeuePeriod is how often a CCS AccountClaim that failed validation is validated again
+	ccsValidationRequeuePeriod = 300
+)
+
+// ccsMinimumQuotas are the service quotas a CCS account needs to install a cluster, and their default minimum
+var ccsMinimumQuotas = []struct {
+	code    awsv1alpha1.SupportedServiceQuotas
+	name    string
+	minimum float64
+}{
+	{awsv1alpha1.RunningStandardInstances, "Running On-Demand Standard instances (vCPUs)", 100},
+	{awsv1alpha1.EC2VPCElasticIPsQuotaCode, "EC2-VPC Elastic IPs", 5},
+	{awsv1alpha1.VPCsPerRegionQuotaCode, "VPCs per Region", 5},
+}
+
+// ccsValidationFailure is a problem with the customer account the customer has to fix before the
+// AccountClaim can be accepted
+type ccsValidationFailure struct {
+	reason  string
+	message string
+}
+
+func (f *ccsValidationFailure) Error() string {
+	return f.message
+}
+
+// ccsValidationEnabled returns whether the customer account of CCS AccountClaims is validated before the
+// claim is accepted, along with the operator configmap
+func (r *AccountClaimReconciler) ccsValidationEnabled() (bool, *corev1.ConfigMap, error) {
+	cm, err := controllerutils.GetOperatorConfigMap(r.Client)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return false, nil, nil
+		}
+		return false, nil, err
+	}
+	enabled, _ := strconv.ParseBool(cm.Data[awsv1alpha1.CCSValidationConfigMapKey])
+	return enabled, cm, nil
+}
+
+// validateCCSAccount checks that the customer account of the claim can be used: the credentials or role
+// provided in the claim work and belong to the account, the service quotas are sufficient and no IAM
+// user conflicts with the ones the operator creates. Problems the customer has to fix are returned as a
+// *ccsValidationFailure.
+func (r *AccountClaimReconciler) validateCCSAccount(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim, cm *corev1.ConfigMap) error {
+	awsClient, err := r.getCCSValidationClient(reqLogger, accountClaim)
+	if err != nil {
+		return &ccsValidationFailure{
+			reason:  ccsValidationReasonInvalidCredentials,
+			message: fmt.Sprintf("Unable to access the AWS account with the credentials provided: %s", err),
+		}
+	}
+
+	identity, err := awsClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return &ccsValidationFailure{
+			reason:  ccsValidationReasonInvalidCredentials,
+			message: fmt.Sprintf("Unable to access the AWS account with the credentials provided: %s", err),
+		}
+	}
+	if aws.StringValue(identity.Account) != accountClaim.Spec.BYOCAWSAccountID {
+		return &ccsValidationFailure{
+			reason: ccsValidationReasonAccountIDMismatch,
+			message: fmt.Sprintf("The credentials provided belong to AWS account %s, not %s",
+				aws.StringValue(identity.Account), accountClaim.Spec.BYOCAWSAccountID),
+		}
+	}
+
+	insufficient := []string{}
+	for _, quota := range ccsMinimumQuotas {
+		minimum, err := ccsMinimumQuota(cm, quota.code, quota.minimum)
+		if err != nil {
+			return err
+		}
+		serviceCode, _ := account.GetServiceCode(quota.code)
+		output, err := awsClient.GetServiceQuota(&servicequotas.GetServiceQuotaInput{
+			QuotaCode:   aws.String(string(quota.code)),
+			ServiceCode: aws.String(serviceCode),
+		})
+		if err != nil {
+			reqLogger.Error(err, "Failed to get service quota", "quotaCode", quota.code)
+			return err
+		}
+		if output.Quota != nil && aws.Float64Value(output.Quota.Value) < minimum {
+			insufficient = append(insufficient, fmt.Sprintf("%s is %.0f, at least %.0f is required",
+				quota.name, aws.Float64Value(output.Quota.Value), minimum))
+		}
+	}
+	if len(insufficient) > 0 {
+		return &ccsValidationFailure{
+			reason:  ccsValidationReasonInsufficientQuota,
+			message: "Insufficient service quotas: " + strings.Join(insufficient, ", "),
+		}
+	}
+
+	candidateUsers := []string{}
+	err = awsClient.ListUsersPages(&iam.ListUsersInput{}, func(page *iam.ListUsersOutput, lastPage bool) bool {
+		for _, user := range page.Users {
+			if strings.HasPrefix(aws.StringValue(user.UserName), awsv1alpha1.IAMUserNamePrefix) {
+				candidateUsers = append(candidateUsers, aws.StringValue(user.UserName))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to list IAM users")
+		return err
+	}
+	// The users created for other clusters installed in the account don't conflict, each cluster gets its own
+	conflictingUsers := []string{}
+	for _, userName := range candidateUsers {
+		createdByOperator, err := iamUserCreatedByOperator(awsClient, userName)
+		if err != nil {
+			reqLogger.Error(err, "Failed to list IAM user tags", "user", userName)
+			return err
+		}
+		if !createdByOperator {
+			conflictingUsers = append(conflictingUsers, userName)
+		}
+	}
+	if len(conflictingUsers) > 0 {
+		sort.Strings(conflictingUsers)
+		return &ccsValidationFailure{
+			reason:  ccsValidationReasonConflictingIAMUser,
+			message: fmt.Sprintf("IAM users conflicting with the ones created for the cluster exist: %s", strings.Join(conflictingUsers, ", ")),
+		}
+	}
+
+	return nil
+}
+
+// iamUserCreatedByOperator returns whether the IAM user carries the tags the operator puts on the users
+// it creates for clusters
+func iamUserCreatedByOperator(awsClient awsclient.Client, userName string) (bool, error) {
+	output, err := awsClient.ListUserTags(&iam.ListUserTagsInput{UserName: aws.String(userName)})
+	if err != nil {
+		return false, err
+	}
+	for _, tag := range output.Tags {
+		if aws.StringValue(tag.Key) == awsv1alpha1.ClusterAccountNameTagKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getCCSValidationClient returns a client of the customer account built from the credentials or STS role
+// provided in the claim, in the region of the cluster
+func (r *AccountClaimReconciler) getCCSValidationClient(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) (awsclient.Client, error) {
+	if accountClaim.Spec.ManualSTSMode {
+		return r.getSTSRolesClient(reqLogger, accountClaim)
+	}
+
+	awsRegion := claimRegions(accountClaim)[0]
+	return r.awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
+		SecretName: accountClaim.Spec.BYOCSecretRef.Name,
+		NameSpace:  accountClaim.Spec.BYOCSecretRef.Namespace,
+		AwsRegion:  awsRegion,
+	})
+}
+
+// ccsMinimumQuota returns the minimum of the quota set in the configmap, or the default
+func ccsMinimumQuota(cm *corev1.ConfigMap, code awsv1alpha1.SupportedServiceQuotas, defaultMinimum float64) (float64, error) {
+	key := awsv1alpha1.CCSValidationMinimumQuotaConfigMapKeyPrefix + string(code)
+	value, ok := cm.Data[key]
+	if !ok {
+		return defaultMinimum, nil
+	}
+	minimum, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s is not a number: %s", awsv1alpha1.ErrInvalidConfigMap, key, value)
+	}
+	return minimum, nil
+}
+
+// setCCSValidationStatus records the outcome of the validation in the ValidationFailed condition. Returns
+// true if the status changed.
+func setCCSValidationStatus(accountClaim *awsv1alpha1.AccountClaim, err error) bool {
+	var failure *ccsValidationFailure
+	if errors.As(err, &failure) {
+		// The message is kept current, the customer may fix one problem only to run into the next
+		accountClaim.Status.Conditions = controllerutils.SetAccountClaimCondition(
+			accountClaim.Status.Conditions,
+			awsv1alpha1.ValidationFailed,
+			corev1.ConditionTrue,
+			failure.reason,
+			failure.message,
+			controllerutils.UpdateConditionIfReasonOrMessageChange,
+			true,
+		)
+		accountClaim.Status.State = awsv1alpha1.ClaimStatusError
+		return true
+	}
+
+	condition := controllerutils.FindAccountClaimCondition(accountClaim.Status.Conditions, awsv1alpha1.ValidationFailed)
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		return false
+	}
+	accountClaim.Status.Conditions = controllerutils.SetAccountClaimCondition(
+		accountClaim.Status.Conditions,
+		awsv1alpha1.ValidationFailed,
+		corev1.ConditionFalse,
+		ccsValidationReasonValidationSucceeded,
+		"The customer account passed validation",
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+		true,
+	)
+	accountClaim.Status.State = awsv1alpha1.ClaimStatusPending
+	return true
+}
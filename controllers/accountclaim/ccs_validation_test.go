@@ -0,0 +1,191 @@
+package accountclaim
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/aws/aws-sdk-go/service/sts"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CCS validation", func() {
+	var (
+		ctrl          *gomock.Controller
+		r             *AccountClaimReconciler
+		mockAWSClient *mock.MockClient
+		claim         *awsv1alpha1.AccountClaim
+		configMap     *corev1.ConfigMap
+		req           reconcile.Request
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		ctrl = gomock.NewController(GinkgoT())
+		r = &AccountClaimReconciler{
+			Scheme:           scheme.Scheme,
+			awsClientBuilder: &mock.Builder{MockController: ctrl},
+		}
+		mockAWSClient = mock.GetMockClient(r.awsClientBuilder)
+		secretRef := awsv1alpha1.SecretRef{Name: "byoc", Namespace: "claim-namespace"}
+		claim = &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "claim",
+				Namespace:  "claim-namespace",
+				Finalizers: []string{accountClaimFinalizer},
+			},
+			Spec: awsv1alpha1.AccountClaimSpec{
+				BYOC:                true,
+				BYOCAWSAccountID:    "123456789012",
+				BYOCSecretRef:       secretRef,
+				AwsCredentialSecret: secretRef,
+				Aws:                 awsv1alpha1.Aws{Regions: []awsv1alpha1.AwsRegions{{Name: "us-east-1"}}},
+			},
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+			Data: map[string]string{
+				awsv1alpha1.CCSValidationConfigMapKey: "true",
+				awsv1alpha1.CCSValidationMinimumQuotaConfigMapKeyPrefix + string(awsv1alpha1.RunningStandardInstances): "64",
+			},
+		}
+		req = reconcile.Request{NamespacedName: types.NamespacedName{Name: claim.Name, Namespace: claim.Namespace}}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectCallerIdentity := func(accountID string) {
+		mockAWSClient.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{Account: aws.String(accountID)}, nil)
+	}
+
+	expectQuotas := func(vCPUs float64) {
+		mockAWSClient.EXPECT().GetServiceQuota(gomock.Any()).DoAndReturn(func(input *servicequotas.GetServiceQuotaInput) (*servicequotas.GetServiceQuotaOutput, error) {
+			value := 5.0
+			if aws.StringValue(input.QuotaCode) == string(awsv1alpha1.RunningStandardInstances) {
+				value = vCPUs
+			}
+			return &servicequotas.GetServiceQuotaOutput{Quota: &servicequotas.ServiceQuota{Value: aws.Float64(value)}}, nil
+		}).Times(len(ccsMinimumQuotas))
+	}
+
+	expectUsers := func(names ...string) {
+		mockAWSClient.EXPECT().ListUsersPages(gomock.Any(), gomock.Any()).DoAndReturn(func(input *iam.ListUsersInput, fn func(*iam.ListUsersOutput, bool) bool) error {
+			page := &iam.ListUsersOutput{}
+			for _, name := range names {
+				page.Users = append(page.Users, &iam.User{UserName: aws.String(name)})
+			}
+			fn(page, true)
+			return nil
+		})
+	}
+
+	expectUserTags := func(name string, tags ...*iam.Tag) {
+		mockAWSClient.EXPECT().ListUserTags(&iam.ListUserTagsInput{UserName: aws.String(name)}).Return(&iam.ListUserTagsOutput{Tags: tags}, nil)
+	}
+
+	reconcileClaim := func(objects ...client.Object) (reconcile.Result, *awsv1alpha1.AccountClaim) {
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(objects, claim)...).Build()
+		result, err := r.Reconcile(context.TODO(), req)
+		Expect(err).NotTo(HaveOccurred())
+		updated := &awsv1alpha1.AccountClaim{}
+		Expect(r.Client.Get(context.TODO(), req.NamespacedName, updated)).To(Succeed())
+		return result, updated
+	}
+
+	It("Accepts a claim whose account passes validation", func() {
+		expectCallerIdentity("123456789012")
+		expectQuotas(64)
+		expectUsers("customer-admin")
+
+		_, updated := reconcileClaim(configMap)
+		Expect(updated.Spec.AccountLink).NotTo(BeEmpty())
+		Expect(utils.FindAccountClaimCondition(updated.Status.Conditions, awsv1alpha1.ValidationFailed)).To(BeNil())
+	})
+
+	It("Rejects credentials of another account", func() {
+		expectCallerIdentity("210987654321")
+
+		result, updated := reconcileClaim(configMap)
+		Expect(result.RequeueAfter).To(Equal(ccsValidationRequeuePeriod * time.Second))
+		Expect(updated.Spec.AccountLink).To(BeEmpty())
+		Expect(updated.Status.State).To(Equal(awsv1alpha1.ClaimStatusError))
+		condition := utils.FindAccountClaimCondition(updated.Status.Conditions, awsv1alpha1.ValidationFailed)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal(ccsValidationReasonAccountIDMismatch))
+	})
+
+	It("Rejects an account with insufficient quotas", func() {
+		expectCallerIdentity("123456789012")
+		expectQuotas(32)
+
+		_, updated := reconcileClaim(configMap)
+		condition := utils.FindAccountClaimCondition(updated.Status.Conditions, awsv1alpha1.ValidationFailed)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal(ccsValidationReasonInsufficientQuota))
+		Expect(condition.Message).To(ContainSubstring("is 32, at least 64 is required"))
+	})
+
+	It("Rejects an account with a conflicting osdManagedAdmin user", func() {
+		expectCallerIdentity("123456789012")
+		expectQuotas(100)
+		expectUsers("osdManagedAdmin-abcdef")
+		expectUserTags("osdManagedAdmin-abcdef")
+
+		_, updated := reconcileClaim(configMap)
+		condition := utils.FindAccountClaimCondition(updated.Status.Conditions, awsv1alpha1.ValidationFailed)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal(ccsValidationReasonConflictingIAMUser))
+		Expect(condition.Message).To(ContainSubstring("osdManagedAdmin-abcdef"))
+	})
+
+	It("Accepts an account with the osdManagedAdmin users of other clusters", func() {
+		expectCallerIdentity("123456789012")
+		expectQuotas(100)
+		expectUsers("osdManagedAdmin-abcdef")
+		expectUserTags("osdManagedAdmin-abcdef", &iam.Tag{Key: aws.String(awsv1alpha1.ClusterAccountNameTagKey), Value: aws.String("osd-creds-mgmt-abcdef")})
+
+		_, updated := reconcileClaim(configMap)
+		Expect(updated.Spec.AccountLink).NotTo(BeEmpty())
+		Expect(utils.FindAccountClaimCondition(updated.Status.Conditions, awsv1alpha1.ValidationFailed)).To(BeNil())
+	})
+
+	It("Clears the condition once the account passes validation", func() {
+		claim.Status.State = awsv1alpha1.ClaimStatusError
+		claim.Status.Conditions = []awsv1alpha1.AccountClaimCondition{
+			{Type: awsv1alpha1.ValidationFailed, Status: corev1.ConditionTrue, Reason: ccsValidationReasonInsufficientQuota},
+		}
+		expectCallerIdentity("123456789012")
+		expectQuotas(100)
+		expectUsers()
+
+		_, updated := reconcileClaim(configMap)
+		Expect(updated.Spec.AccountLink).NotTo(BeEmpty())
+		condition := utils.FindAccountClaimCondition(updated.Status.Conditions, awsv1alpha1.ValidationFailed)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+	})
+
+	It("Skips validation unless enabled", func() {
+		configMap.Data = nil
+
+		_, updated := reconcileClaim(configMap)
+		Expect(updated.Spec.AccountLink).NotTo(BeEmpty())
+	})
+})
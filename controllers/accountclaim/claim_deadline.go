@@ -0,0 +1,78 @@
+package accountclaim
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/notifier"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// claimDeadlineKey is the operator configmap key of the default deadline of the claims, e.g. "30m"
+const claimDeadlineKey = "accountclaim-deadline"
+
+// getClaimDeadline returns how long the claim may wait for an account, from its spec or the operator configmap,
+// and false if it has no deadline
+func (r *AccountClaimReconciler) getClaimDeadline(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) (time.Duration, bool) {
+	if accountClaim.Spec.ClaimDeadline != nil {
+		return accountClaim.Spec.ClaimDeadline.Duration, accountClaim.Spec.ClaimDeadline.Duration > 0
+	}
+	cm, err := controllerutils.GetOperatorConfigMap(r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Could not retrieve the operator configmap, the claim has no deadline")
+		return 0, false
+	}
+	value, ok := cm.Data[claimDeadlineKey]
+	if !ok {
+		return 0, false
+	}
+	deadline, err := time.ParseDuration(value)
+	if err != nil {
+		reqLogger.Error(err, "Invalid claim deadline in configmap, the claim has no deadline", "value", value)
+		return 0, false
+	}
+	return deadline, deadline > 0
+}
+
+// checkClaimDeadline sets the ClaimTimedOut condition on a claim still waiting for an account past its deadline.
+// The claim keeps waiting and is bound if an account becomes available, the condition records it was late.
+func (r *AccountClaimReconciler) checkClaimDeadline(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) error {
+	deadline, ok := r.getClaimDeadline(reqLogger, accountClaim)
+	if !ok {
+		return nil
+	}
+	waiting := time.Since(accountClaim.CreationTimestamp.Time)
+	if waiting <= deadline {
+		return nil
+	}
+	if condition := controllerutils.FindAccountClaimCondition(accountClaim.Status.Conditions, awsv1alpha1.AccountClaimTimedOut); condition != nil && condition.Status == corev1.ConditionTrue {
+		return nil
+	}
+
+	message := fmt.Sprintf("No account was bound to the claim within its %s deadline", deadline)
+	reqLogger.Info(message, "waiting", waiting.Round(time.Second).String())
+	accountClaim.Status.Conditions = controllerutils.SetAccountClaimCondition(
+		accountClaim.Status.Conditions,
+		awsv1alpha1.AccountClaimTimedOut,
+		corev1.ConditionTrue,
+		"DeadlineExceeded",
+		message,
+		controllerutils.UpdateConditionNever,
+		accountClaim.Spec.BYOC,
+	)
+	err := r.statusUpdate(reqLogger, accountClaim)
+	if err != nil {
+		return err
+	}
+
+	localmetrics.Collector.AddAccountClaimTimeout()
+	controllerutils.RecordEvent(r.recorder, accountClaim, corev1.EventTypeWarning, controllerutils.EventReasonClaimTimedOut, "%s", message)
+	notifier.Notify(reqLogger, notifier.ForAccountClaim(notifier.KindClaimDeadlineExceeded, accountClaim,
+		fmt.Sprintf("AccountClaim %s/%s not fulfilled within its deadline", accountClaim.Namespace, accountClaim.Name), message))
+	return nil
+}
@@ -0,0 +1,77 @@
+package accountclaim
+
+import (
+	"context"
+	"time"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Claim deadline", func() {
+	var (
+		claim     *awsv1alpha1.AccountClaim
+		configMap *corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		localmetrics.Collector = localmetrics.NewMetricsCollector(nil)
+		claim = &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "claim",
+				Namespace:         "claim-namespace",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+			},
+			Status: awsv1alpha1.AccountClaimStatus{State: awsv1alpha1.ClaimStatusPending},
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+			Data:       map[string]string{},
+		}
+	})
+
+	check := func() *awsv1alpha1.AccountClaimCondition {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(claim, configMap).Build()
+		r := &AccountClaimReconciler{Client: kubeClient, Scheme: scheme.Scheme}
+		Expect(r.checkClaimDeadline(testutils.NewTestLogger().Logger(), claim)).To(Succeed())
+
+		updated := &awsv1alpha1.AccountClaim{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(claim), updated)).To(Succeed())
+		return controllerutils.FindAccountClaimCondition(updated.Status.Conditions, awsv1alpha1.AccountClaimTimedOut)
+	}
+
+	It("Sets the ClaimTimedOut condition past the deadline of the claim", func() {
+		claim.Spec.ClaimDeadline = &metav1.Duration{Duration: 30 * time.Minute}
+		condition := check()
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("DeadlineExceeded"))
+	})
+
+	It("Falls back to the deadline of the operator configmap", func() {
+		configMap.Data[claimDeadlineKey] = "45m"
+		Expect(check()).NotTo(BeNil())
+	})
+
+	It("Leaves a claim within its deadline alone", func() {
+		claim.Spec.ClaimDeadline = &metav1.Duration{Duration: 2 * time.Hour}
+		configMap.Data[claimDeadlineKey] = "45m"
+		Expect(check()).To(BeNil())
+	})
+
+	It("Leaves a claim without a deadline alone", func() {
+		Expect(check()).To(BeNil())
+	})
+})
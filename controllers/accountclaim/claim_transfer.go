@@ -0,0 +1,315 @@
+package accountclaim
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/secretstore"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// claimTransferTarget returns the namespace/name the claim is transferred to
+func claimTransferTarget(accountClaim *awsv1alpha1.AccountClaim) types.NamespacedName {
+	target := types.NamespacedName{Namespace: accountClaim.Spec.TransferTo.Namespace, Name: accountClaim.Spec.TransferTo.Name}
+	if target.Name == "" {
+		target.Name = accountClaim.Name
+	}
+	return target
+}
+
+// handleClaimTransfer moves a claim with spec.transferTo to its new namespace/name: the claim is created there with
+// the spec and status of this one, the secrets of the claim are moved along, the account is linked to the new claim,
+// and this one is deleted with its account link cleared, so the account isn't cleaned up. Each step is skipped once
+// done, a transfer interrupted half way is resumed on the next reconcile. The transfer is refused, with an event
+// saying why, and spec.transferTo cleared when the claim can't be moved. Returns true if the transfer was handled.
+func (r *AccountClaimReconciler) handleClaimTransfer(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) (bool, error) {
+	if accountClaim.Spec.TransferTo == nil || accountClaim.DeletionTimestamp != nil {
+		return false, nil
+	}
+	targetKey := claimTransferTarget(accountClaim)
+	source := client.ObjectKeyFromObject(accountClaim)
+	reqLogger = reqLogger.WithValues("transferTo", targetKey.String())
+
+	target := &awsv1alpha1.AccountClaim{}
+	err := r.Client.Get(context.TODO(), targetKey, target)
+	if k8serr.IsNotFound(err) {
+		target = nil
+	} else if err != nil {
+		return true, err
+	}
+
+	transferred := target != nil && target.Annotations[awsv1alpha1.TransferredFromAnnotation] == source.String()
+	if !transferred {
+		if refusal := claimTransferRefusal(accountClaim, targetKey, target); refusal != "" {
+			reqLogger.Info("Refusing to transfer the claim", "reason", refusal)
+			controllerutils.RecordEvent(r.recorder, accountClaim, corev1.EventTypeWarning, controllerutils.EventReasonTransferRefused, "AccountClaim not transferred to %s: %s", targetKey, refusal)
+			return true, controllerutils.UpdateWithRetry(r.Client, accountClaim, func() error {
+				accountClaim.Spec.TransferTo = nil
+				return nil
+			})
+		}
+
+		// The namespace may be about to be recreated, the transfer waits for it
+		err = r.Client.Get(context.TODO(), types.NamespacedName{Name: targetKey.Namespace}, &corev1.Namespace{})
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				return true, fmt.Errorf("namespace %s of the claim transfer doesn't exist", targetKey.Namespace)
+			}
+			return true, err
+		}
+
+		target, err = r.createTransferredClaim(reqLogger, accountClaim, targetKey)
+		if err != nil {
+			return true, err
+		}
+	}
+
+	// The account link of this claim is cleared last, when it's gone the rest of the transfer is done
+	if accountClaim.Spec.AccountLink != "" {
+		err = r.completeClaimTransfer(reqLogger, accountClaim, target)
+		if err != nil {
+			return true, err
+		}
+		controllerutils.RecordEvent(r.recorder, target, corev1.EventTypeNormal, controllerutils.EventReasonClaimTransferred, "AccountClaim transferred from %s with account %s", source, target.Spec.AccountLink)
+		controllerutils.RecordEvent(r.recorder, accountClaim, corev1.EventTypeNormal, controllerutils.EventReasonClaimTransferred, "AccountClaim transferred to %s with account %s", targetKey, target.Spec.AccountLink)
+
+		err = controllerutils.UpdateWithRetry(r.Client, accountClaim, func() error {
+			accountClaim.Spec.AccountLink = ""
+			return nil
+		})
+		if err != nil {
+			return true, err
+		}
+	}
+
+	reqLogger.Info("Claim transferred, deleting it", "account", target.Spec.AccountLink)
+	err = r.Client.Delete(context.TODO(), accountClaim)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return true, err
+	}
+	return true, nil
+}
+
+// claimTransferRefusal returns why the claim can't be transferred to target, empty if it can. existing is the claim
+// already at target, if any.
+func claimTransferRefusal(accountClaim *awsv1alpha1.AccountClaim, target types.NamespacedName, existing *awsv1alpha1.AccountClaim) string {
+	switch {
+	case target == client.ObjectKeyFromObject(accountClaim):
+		return "the claim is already there"
+	case accountClaim.Spec.BYOC:
+		return "the secrets of CCS claims are provided by the customer in the namespace of the claim"
+	case accountClaim.Spec.AccountLink == "" || accountClaim.Status.State != awsv1alpha1.ClaimStatusReady:
+		return "only Ready claims bound to an account can be transferred"
+	case existing != nil:
+		return "another AccountClaim already exists there"
+	}
+	return ""
+}
+
+// createTransferredClaim creates the claim at target with the spec and status of accountClaim and returns it. Its
+// credentials secret, and its ClusterDeployment, move along to the new namespace when they were in the claim's.
+func (r *AccountClaimReconciler) createTransferredClaim(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim, target types.NamespacedName) (*awsv1alpha1.AccountClaim, error) {
+	transferred := &awsv1alpha1.AccountClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        target.Name,
+			Namespace:   target.Namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec: *accountClaim.Spec.DeepCopy(),
+	}
+	for key, value := range accountClaim.Labels {
+		transferred.Labels[key] = value
+	}
+	for key, value := range accountClaim.Annotations {
+		transferred.Annotations[key] = value
+	}
+	transferred.Annotations[awsv1alpha1.TransferredFromAnnotation] = client.ObjectKeyFromObject(accountClaim).String()
+	transferred.Spec.TransferTo = nil
+	if transferred.Spec.AwsCredentialSecret.Namespace == accountClaim.Namespace {
+		transferred.Spec.AwsCredentialSecret.Namespace = target.Namespace
+	}
+	if ref := transferred.Spec.ClusterDeploymentRef; ref != nil && ref.Namespace == accountClaim.Namespace {
+		ref.Namespace = target.Namespace
+	}
+
+	err := r.Client.Create(context.TODO(), transferred)
+	if err != nil {
+		reqLogger.Error(err, "Failed to create the transferred claim")
+		return nil, err
+	}
+
+	// Bound claims are satisfied and left alone by the reconcile, the status keeps the new claim from being bound
+	// to another account
+	return transferred, controllerutils.UpdateStatusWithRetry(r.Client, transferred, func() error {
+		transferred.Status = *accountClaim.Status.DeepCopy()
+		return nil
+	})
+}
+
+// completeClaimTransfer moves the secrets of accountClaim to the transferred claim and links the account to it
+func (r *AccountClaimReconciler) completeClaimTransfer(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim, transferred *awsv1alpha1.AccountClaim) error {
+	if transferred.Status.State != awsv1alpha1.ClaimStatusReady {
+		err := controllerutils.UpdateStatusWithRetry(r.Client, transferred, func() error {
+			transferred.Status = *accountClaim.Status.DeepCopy()
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err := r.moveClaimSecrets(reqLogger, accountClaim, transferred)
+	if err != nil {
+		reqLogger.Error(err, "Failed to move the secrets of the claim")
+		return err
+	}
+
+	account, err := r.getClaimedAccount(accountClaim.Spec.AccountLink, awsv1alpha1.AccountCrNamespace)
+	if err != nil {
+		return err
+	}
+	return controllerutils.UpdateWithRetry(r.Client, account, func() error {
+		account.Spec.ClaimLink = transferred.Name
+		account.Spec.ClaimLinkNamespace = transferred.Namespace
+		account.Spec.ClaimLinkUID = string(transferred.UID)
+		return nil
+	})
+}
+
+// claimTransferCompleted returns true if the claim was transferred: the transferred claim owns the account and the
+// secrets they shared
+func claimTransferCompleted(accountClaim *awsv1alpha1.AccountClaim) bool {
+	return accountClaim.Spec.TransferTo != nil && accountClaim.Spec.AccountLink == ""
+}
+
+// moveClaimSecrets moves the secrets of accountClaim, the ones cleanUpClaimSecrets would delete, to the transferred
+// claim: the secrets named after the claim are renamed after the transferred one, and the ones in the namespace of
+// the claim go to its new namespace. The secrets whose name and namespace don't change are shared with the
+// transferred claim, they're only given to it. Secrets already moved are skipped.
+func (r *AccountClaimReconciler) moveClaimSecrets(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim, transferred *awsv1alpha1.AccountClaim) error {
+	moves := map[types.NamespacedName]types.NamespacedName{}
+	for _, namespace := range []string{accountClaim.Namespace, awsv1alpha1.AccountCrNamespace} {
+		newNamespace := namespace
+		if namespace == accountClaim.Namespace {
+			newNamespace = transferred.Namespace
+		}
+		for _, suffix := range secretSuffixes {
+			moves[types.NamespacedName{Name: accountClaim.Name + "-" + suffix, Namespace: namespace}] = types.NamespacedName{Name: transferred.Name + "-" + suffix, Namespace: newNamespace}
+		}
+	}
+
+	store, err := secretstore.New(r.Client)
+	if err != nil {
+		return err
+	}
+	// Credentials kept outside of Kubernetes are moved in their store
+	if accountClaim.Spec.AwsCredentialSecret.Name != "" {
+		if store.Backend() == secretstore.BackendKubernetes {
+			moves[claimSecretKey(accountClaim)] = claimSecretKey(transferred)
+		} else {
+			err = r.moveStoredClaimSecret(store, accountClaim, transferred)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for from, to := range moves {
+		secret := &corev1.Secret{}
+		err := r.Client.Get(context.TODO(), from, secret)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if secret.Labels[awsv1alpha1.SecretOwnerKindLabel] == "Account" {
+			continue
+		}
+		if from == to {
+			err = r.reownClaimSecret(accountClaim, transferred, secret)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		moved := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        to.Name,
+				Namespace:   to.Namespace,
+				Labels:      secret.Labels,
+				Annotations: secret.Annotations,
+			},
+			Type: secret.Type,
+			Data: secret.Data,
+		}
+		err = controllerutils.SetSecretOwner(moved, transferred, r.Scheme)
+		if err != nil {
+			return err
+		}
+		err = r.Client.Create(context.TODO(), moved)
+		if err != nil && !k8serr.IsAlreadyExists(err) {
+			return err
+		}
+		err = r.Client.Delete(context.TODO(), secret)
+		if err != nil && !k8serr.IsNotFound(err) {
+			return err
+		}
+		reqLogger.Info("Moved claim secret", "from", from.String(), "to", to.String())
+	}
+	return nil
+}
+
+// reownClaimSecret gives a secret accountClaim shares with the transferred claim to the transferred one, so it's
+// neither garbage collected nor deleted by the secret collector once accountClaim is gone
+func (r *AccountClaimReconciler) reownClaimSecret(accountClaim *awsv1alpha1.AccountClaim, transferred *awsv1alpha1.AccountClaim, secret *corev1.Secret) error {
+	return controllerutils.UpdateWithRetry(r.Client, secret, func() error {
+		var ownerReferences []metav1.OwnerReference
+		for _, ref := range secret.OwnerReferences {
+			if ref.UID != accountClaim.UID {
+				ownerReferences = append(ownerReferences, ref)
+			}
+		}
+		secret.OwnerReferences = ownerReferences
+		return controllerutils.SetSecretOwner(secret, transferred, r.Scheme)
+	})
+}
+
+// moveStoredClaimSecret moves the credentials of accountClaim kept in store to the key of the transferred claim
+func (r *AccountClaimReconciler) moveStoredClaimSecret(store secretstore.Store, accountClaim *awsv1alpha1.AccountClaim, transferred *awsv1alpha1.AccountClaim) error {
+	from, to := claimSecretKey(accountClaim), claimSecretKey(transferred)
+	if from == to {
+		return nil
+	}
+	data, err := store.Get(context.TODO(), from)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: to.Name, Namespace: to.Namespace},
+		Type:       "Opaque",
+		Data:       data,
+	}
+	err = controllerutils.SetSecretOwner(secret, transferred, r.Scheme)
+	if err != nil {
+		return err
+	}
+	err = store.Put(context.TODO(), secret)
+	if err != nil {
+		return err
+	}
+	return store.Delete(context.TODO(), from)
+}
@@ -0,0 +1,152 @@
+package accountclaim
+
+import (
+	"context"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AccountClaim transfer", func() {
+	var (
+		claim          *awsv1alpha1.AccountClaim
+		claimedAccount *awsv1alpha1.Account
+		claimSecret    *corev1.Secret
+		namespace      *corev1.Namespace
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		claim = &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "old-namespace", Finalizers: []string{accountClaimFinalizer}},
+			Spec: awsv1alpha1.AccountClaimSpec{
+				AccountLink:          "osd-creds-mgmt-abcdef",
+				AccountOU:            "ou-abcd-12345678",
+				AwsCredentialSecret:  awsv1alpha1.SecretRef{Name: "aws", Namespace: "old-namespace"},
+				ClusterDeploymentRef: &awsv1alpha1.ClusterDeploymentRef{Name: "cluster", Namespace: "old-namespace"},
+				TransferTo:           &awsv1alpha1.ClaimTransfer{Namespace: "new-namespace"},
+			},
+			Status: awsv1alpha1.AccountClaimStatus{State: awsv1alpha1.ClaimStatusReady},
+		}
+		claimedAccount = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "osd-creds-mgmt-abcdef", Namespace: awsv1alpha1.AccountCrNamespace},
+			Spec:       awsv1alpha1.AccountSpec{ClaimLink: "claim", ClaimLinkNamespace: "old-namespace"},
+		}
+		claimSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "aws",
+				Namespace:   "old-namespace",
+				Labels:      map[string]string{awsv1alpha1.SecretOwnerKindLabel: "AccountClaim"},
+				Annotations: map[string]string{awsv1alpha1.ClusterNameAnnotation: "my-cluster"},
+			},
+			Data: map[string][]byte{awsCredsAccessKeyID: []byte("AKIA"), awsCredsSecretAccessKey: []byte("secret")},
+		}
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "new-namespace"}}
+	})
+
+	transfer := func(objects ...client.Object) (client.Client, *record.FakeRecorder, error) {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(objects, claim, claimedAccount, claimSecret)...).Build()
+		recorder := record.NewFakeRecorder(2)
+		r := &AccountClaimReconciler{Client: kubeClient, Scheme: scheme.Scheme, recorder: recorder}
+		handled, err := r.handleClaimTransfer(testutils.NewTestLogger().Logger(), claim)
+		Expect(handled).To(BeTrue())
+		return kubeClient, recorder, err
+	}
+
+	It("Moves the claim, its secret and its account link to the new namespace", func() {
+		kubeClient, _, err := transfer(namespace)
+		Expect(err).NotTo(HaveOccurred())
+
+		transferred := &awsv1alpha1.AccountClaim{}
+		Expect(kubeClient.Get(context.TODO(), types.NamespacedName{Name: "claim", Namespace: "new-namespace"}, transferred)).To(Succeed())
+		Expect(transferred.Annotations).To(HaveKeyWithValue(awsv1alpha1.TransferredFromAnnotation, "old-namespace/claim"))
+		Expect(transferred.Spec.TransferTo).To(BeNil())
+		Expect(transferred.Spec.AccountLink).To(Equal("osd-creds-mgmt-abcdef"))
+		Expect(transferred.Spec.AwsCredentialSecret).To(Equal(awsv1alpha1.SecretRef{Name: "aws", Namespace: "new-namespace"}))
+		Expect(transferred.Spec.ClusterDeploymentRef.Namespace).To(Equal("new-namespace"))
+		Expect(transferred.Status.State).To(Equal(awsv1alpha1.ClaimStatusReady))
+
+		secret := &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), types.NamespacedName{Name: "aws", Namespace: "new-namespace"}, secret)).To(Succeed())
+		Expect(secret.Data).To(Equal(claimSecret.Data))
+		Expect(secret.Annotations).To(HaveKeyWithValue(awsv1alpha1.ClusterNameAnnotation, "my-cluster"))
+		Expect(secret.Annotations).To(HaveKeyWithValue(awsv1alpha1.SecretOwnerNamespaceAnnotation, "new-namespace"))
+		err = kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(claimSecret), &corev1.Secret{})
+		Expect(k8serr.IsNotFound(err)).To(BeTrue())
+
+		account := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(claimedAccount), account)).To(Succeed())
+		Expect(account.Spec.ClaimLink).To(Equal("claim"))
+		Expect(account.Spec.ClaimLinkNamespace).To(Equal("new-namespace"))
+
+		// The old claim goes without its account link, so its deletion doesn't clean up the account
+		old := &awsv1alpha1.AccountClaim{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(claim), old)).To(Succeed())
+		Expect(old.DeletionTimestamp).NotTo(BeNil())
+		Expect(old.Spec.AccountLink).To(BeEmpty())
+	})
+
+	It("Gives the secret it shares with the transferred claim to it", func() {
+		claim.Spec.AwsCredentialSecret.Namespace = "shared"
+		claimSecret.Namespace = "shared"
+		kubeClient, _, err := transfer(namespace)
+		Expect(err).NotTo(HaveOccurred())
+
+		transferred := &awsv1alpha1.AccountClaim{}
+		Expect(kubeClient.Get(context.TODO(), types.NamespacedName{Name: "claim", Namespace: "new-namespace"}, transferred)).To(Succeed())
+		Expect(transferred.Spec.AwsCredentialSecret).To(Equal(awsv1alpha1.SecretRef{Name: "aws", Namespace: "shared"}))
+
+		secret := &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(claimSecret), secret)).To(Succeed())
+		Expect(secret.Data).To(Equal(claimSecret.Data))
+		Expect(secret.Annotations).To(HaveKeyWithValue(awsv1alpha1.SecretOwnerNameAnnotation, "claim"))
+		Expect(secret.Annotations).To(HaveKeyWithValue(awsv1alpha1.SecretOwnerNamespaceAnnotation, "new-namespace"))
+
+		old := &awsv1alpha1.AccountClaim{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(claim), old)).To(Succeed())
+		Expect(claimTransferCompleted(old)).To(BeTrue())
+	})
+
+	It("Waits for the namespace to exist", func() {
+		kubeClient, _, err := transfer()
+		Expect(err).To(HaveOccurred())
+
+		old := &awsv1alpha1.AccountClaim{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(claim), old)).To(Succeed())
+		Expect(old.Spec.TransferTo).NotTo(BeNil())
+		Expect(old.Spec.AccountLink).To(Equal("osd-creds-mgmt-abcdef"))
+	})
+
+	It("Refuses to replace another claim", func() {
+		existing := &awsv1alpha1.AccountClaim{ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "new-namespace"}}
+		kubeClient, recorder, err := transfer(namespace, existing)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(<-recorder.Events).To(ContainSubstring("another AccountClaim already exists there"))
+
+		old := &awsv1alpha1.AccountClaim{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(claim), old)).To(Succeed())
+		Expect(old.Spec.TransferTo).To(BeNil())
+		Expect(old.Spec.AccountLink).To(Equal("osd-creds-mgmt-abcdef"))
+		Expect(old.DeletionTimestamp).To(BeNil())
+	})
+
+	It("Refuses claims that aren't bound yet", func() {
+		claim.Spec.AccountLink = ""
+		claim.Status.State = awsv1alpha1.ClaimStatusPending
+		_, recorder, err := transfer(namespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(<-recorder.Events).To(ContainSubstring("only Ready claims bound to an account"))
+	})
+})
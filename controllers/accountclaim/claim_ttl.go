@@ -0,0 +1,63 @@
+package accountclaim
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// claimExpiresIn returns how long until the claim is past its TTL, negative once it is, and false if it has no TTL
+func claimExpiresIn(accountClaim *awsv1alpha1.AccountClaim) (time.Duration, bool) {
+	if accountClaim.Spec.TTL == nil || accountClaim.Spec.TTL.Duration <= 0 {
+		return 0, false
+	}
+	return time.Until(accountClaim.CreationTimestamp.Add(accountClaim.Spec.TTL.Duration)), true
+}
+
+// expireClaim deletes a claim past its TTL. It's then finalized like any deleted claim, its account is cleaned up
+// and returned to the pool. Returns true if the claim was deleted.
+func (r *AccountClaimReconciler) expireClaim(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) (bool, error) {
+	if accountClaim.DeletionTimestamp != nil {
+		return false, nil
+	}
+	expiresIn, ok := claimExpiresIn(accountClaim)
+	if !ok || expiresIn > 0 {
+		return false, nil
+	}
+
+	reqLogger.Info("Deleting the claim past its TTL", "ttl", accountClaim.Spec.TTL.Duration.String())
+	err := r.Client.Delete(context.TODO(), accountClaim)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	controllerutils.RecordEvent(r.recorder, accountClaim, corev1.EventTypeNormal, controllerutils.EventReasonClaimExpired, "AccountClaim deleted past its %s TTL", accountClaim.Spec.TTL.Duration)
+	return true, nil
+}
+
+// requeueBeforeExpiry has a claim with a TTL reconciled again when it expires, if it isn't going to be before
+func requeueBeforeExpiry(accountClaim *awsv1alpha1.AccountClaim, result ctrl.Result) ctrl.Result {
+	if accountClaim.DeletionTimestamp != nil || (result.Requeue && result.RequeueAfter == 0) {
+		return result
+	}
+	expiresIn, ok := claimExpiresIn(accountClaim)
+	if !ok {
+		return result
+	}
+	if expiresIn < time.Second {
+		expiresIn = time.Second
+	}
+	if result.RequeueAfter == 0 || expiresIn < result.RequeueAfter {
+		result.RequeueAfter = expiresIn
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package accountclaim
+
+import (
+	"context"
+	"time"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Claim TTL", func() {
+	var claim *awsv1alpha1.AccountClaim
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		claim = &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "claim",
+				Namespace:         "claim-namespace",
+				Finalizers:        []string{accountClaimFinalizer},
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+			},
+			Spec: awsv1alpha1.AccountClaimSpec{
+				AccountLink: "osd-creds-mgmt-abcdef",
+				TTL:         &metav1.Duration{Duration: time.Hour},
+			},
+		}
+	})
+
+	expire := func() (bool, *awsv1alpha1.AccountClaim) {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(claim).Build()
+		r := &AccountClaimReconciler{Client: kubeClient, Scheme: scheme.Scheme}
+		expired, err := r.expireClaim(testutils.NewTestLogger().Logger(), claim)
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &awsv1alpha1.AccountClaim{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(claim), updated)).To(Succeed())
+		return expired, updated
+	}
+
+	It("Deletes the claim past its TTL", func() {
+		expired, updated := expire()
+		Expect(expired).To(BeTrue())
+		Expect(updated.DeletionTimestamp).NotTo(BeNil())
+	})
+
+	It("Leaves the claim alone before its TTL", func() {
+		claim.Spec.TTL.Duration = 3 * time.Hour
+		expired, updated := expire()
+		Expect(expired).To(BeFalse())
+		Expect(updated.DeletionTimestamp).To(BeNil())
+	})
+
+	It("Leaves claims without a TTL alone", func() {
+		claim.Spec.TTL = nil
+		expired, updated := expire()
+		Expect(expired).To(BeFalse())
+		Expect(updated.DeletionTimestamp).To(BeNil())
+	})
+
+	It("Requeues the claim when it expires", func() {
+		claim.Spec.TTL.Duration = 3 * time.Hour
+		result := requeueBeforeExpiry(claim, ctrl.Result{})
+		Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+
+		result = requeueBeforeExpiry(claim, ctrl.Result{RequeueAfter: time.Minute})
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+		claim.Spec.TTL = nil
+		Expect(requeueBeforeExpiry(claim, ctrl.Result{})).To(Equal(ctrl.Result{}))
+	})
+})
@@ -0,0 +1,62 @@
+package accountclaim
+
+import (
+	"sync"
+
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cleanupCheckpoint records the progress of an AccountCleanup in its status. The cleanup steps run in parallel,
+// the mutex serializes their updates.
+type cleanupCheckpoint struct {
+	mutex   sync.Mutex
+	cleanup *awsv1alpha1.AccountCleanup
+}
+
+// completedSteps returns the steps completed by the previous runs of the cleanup
+func (c *cleanupCheckpoint) completedSteps() []string {
+	return c.cleanup.Status.CompletedSteps
+}
+
+// completeStep records that step completed
+func (c *cleanupCheckpoint) completeStep(reqLogger logr.Logger, kubeClient client.Client, step string) {
+	c.update(reqLogger, kubeClient, func(status *awsv1alpha1.AccountCleanupStatus) {
+		if !utils.Contains(status.CompletedSteps, step) {
+			status.CompletedSteps = append(status.CompletedSteps, step)
+		}
+	})
+}
+
+// preserveResources records the excluded resources the cleanup left in place
+func (c *cleanupCheckpoint) preserveResources(reqLogger logr.Logger, kubeClient client.Client, resourceARNs []string) {
+	c.update(reqLogger, kubeClient, func(status *awsv1alpha1.AccountCleanupStatus) {
+		for _, resourceARN := range resourceARNs {
+			if !utils.Contains(status.PreservedResources, resourceARN) {
+				status.PreservedResources = append(status.PreservedResources, resourceARN)
+			}
+		}
+	})
+}
+
+// interrupt records that the cleanup was interrupted by an operator shutdown
+func (c *cleanupCheckpoint) interrupt(reqLogger logr.Logger, kubeClient client.Client) {
+	c.update(reqLogger, kubeClient, func(status *awsv1alpha1.AccountCleanupStatus) {
+		status.Interrupted = true
+	})
+}
+
+func (c *cleanupCheckpoint) update(reqLogger logr.Logger, kubeClient client.Client, mutate func(*awsv1alpha1.AccountCleanupStatus)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	err := utils.UpdateStatusWithRetry(kubeClient, c.cleanup, func() error {
+		mutate(&c.cleanup.Status)
+		return nil
+	})
+	if err != nil {
+		// The step is run again when the cleanup is retried, which is harmless
+		reqLogger.Error(err, "Failed to record the cleanup checkpoint")
+	}
+}
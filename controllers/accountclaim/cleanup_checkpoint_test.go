@@ -0,0 +1,48 @@
+package accountclaim
+
+import (
+	"context"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cleanup checkpoint", func() {
+	var (
+		cleanup    *awsv1alpha1.AccountCleanup
+		kubeClient client.Client
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		cleanup = &awsv1alpha1.AccountCleanup{
+			ObjectMeta: metav1.ObjectMeta{Name: "account-claim-uid", Namespace: awsv1alpha1.AccountCrNamespace},
+			Spec:       awsv1alpha1.AccountCleanupSpec{AccountLink: "account", ClaimUID: "claim-uid"},
+		}
+		kubeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cleanup).Build()
+	})
+
+	It("Records the completed steps in the cleanup status", func() {
+		checkpoint := &cleanupCheckpoint{cleanup: cleanup}
+		checkpoint.completeStep(testutils.NewTestLogger().Logger(), kubeClient, "s3")
+		checkpoint.completeStep(testutils.NewTestLogger().Logger(), kubeClient, "route53")
+		checkpoint.completeStep(testutils.NewTestLogger().Logger(), kubeClient, "s3")
+		checkpoint.interrupt(testutils.NewTestLogger().Logger(), kubeClient)
+
+		stored := &awsv1alpha1.AccountCleanup{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(cleanup), stored)).To(Succeed())
+		Expect(stored.Status.CompletedSteps).To(ConsistOf("s3", "route53"))
+		Expect(stored.Status.Interrupted).To(BeTrue())
+
+		resumed := &cleanupCheckpoint{cleanup: stored}
+		Expect(resumed.completedSteps()).To(ConsistOf("s3", "route53"))
+	})
+})
@@ -0,0 +1,171 @@
+package accountclaim
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/go-logr/logr"
+
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// cleanupExclusions filters the resources excluded from the cleanup of an account out of the resources listed by
+// the cleanup steps, so the steps leave them in place, and records the ones found
+type cleanupExclusions struct {
+	awsclient.Client
+	// excluded are the ARNs of the excluded resources, by service and resource, e.g. "s3:my-bucket"
+	excluded  map[string]string
+	mutex     sync.Mutex
+	preserved []string
+}
+
+// newCleanupExclusions wraps the AWS client of a cleanup to exclude the resources with the given ARNs
+func newCleanupExclusions(reqLogger logr.Logger, awsClient awsclient.Client, resourceARNs []string) *cleanupExclusions {
+	exclusions := &cleanupExclusions{Client: awsClient, excluded: map[string]string{}}
+	for _, resourceARN := range resourceARNs {
+		parsed, err := arn.Parse(resourceARN)
+		if err != nil {
+			reqLogger.Info("Ignoring invalid cleanup exclusion", "arn", resourceARN)
+			continue
+		}
+		exclusions.excluded[parsed.Service+":"+parsed.Resource] = resourceARN
+	}
+	return exclusions
+}
+
+// keep returns true if the resource is excluded from the cleanup, and records it as preserved
+func (e *cleanupExclusions) keep(service, resource string) bool {
+	resourceARN, ok := e.excluded[service+":"+resource]
+	if !ok {
+		return false
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if !utils.Contains(e.preserved, resourceARN) {
+		e.preserved = append(e.preserved, resourceARN)
+	}
+	return true
+}
+
+// preservedResources returns the ARNs of the excluded resources found by the cleanup steps
+func (e *cleanupExclusions) preservedResources() []string {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	preserved := append([]string{}, e.preserved...)
+	sort.Strings(preserved)
+	return preserved
+}
+
+// keepResource implements keptResourceRecorder, for the recorder the exclusions wrap
+func (e *cleanupExclusions) keepResource(step, resource, reason string) {
+	if recorder, ok := e.Client.(keptResourceRecorder); ok {
+		recorder.keepResource(step, resource, reason)
+	}
+}
+
+// DescribeSnapshots implements awsclient.Client, without the excluded snapshots
+func (e *cleanupExclusions) DescribeSnapshots(input *ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error) {
+	output, err := e.Client.DescribeSnapshots(input)
+	if err != nil || output == nil || len(e.excluded) == 0 {
+		return output, err
+	}
+	filtered := *output
+	filtered.Snapshots = nil
+	for _, snapshot := range output.Snapshots {
+		if !e.keep(ec2.ServiceName, "snapshot/"+aws.StringValue(snapshot.SnapshotId)) {
+			filtered.Snapshots = append(filtered.Snapshots, snapshot)
+		}
+	}
+	return &filtered, nil
+}
+
+// DescribeVolumes implements awsclient.Client, without the excluded EBS volumes
+func (e *cleanupExclusions) DescribeVolumes(input *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+	output, err := e.Client.DescribeVolumes(input)
+	if err != nil || output == nil || len(e.excluded) == 0 {
+		return output, err
+	}
+	filtered := *output
+	filtered.Volumes = nil
+	for _, volume := range output.Volumes {
+		if !e.keep(ec2.ServiceName, "volume/"+aws.StringValue(volume.VolumeId)) {
+			filtered.Volumes = append(filtered.Volumes, volume)
+		}
+	}
+	return &filtered, nil
+}
+
+// DescribeVpcEndpointServiceConfigurations implements awsclient.Client, without the excluded VPC endpoint services
+func (e *cleanupExclusions) DescribeVpcEndpointServiceConfigurations(input *ec2.DescribeVpcEndpointServiceConfigurationsInput) (*ec2.DescribeVpcEndpointServiceConfigurationsOutput, error) {
+	output, err := e.Client.DescribeVpcEndpointServiceConfigurations(input)
+	if err != nil || output == nil || len(e.excluded) == 0 {
+		return output, err
+	}
+	filtered := *output
+	filtered.ServiceConfigurations = nil
+	for _, configuration := range output.ServiceConfigurations {
+		if !e.keep(ec2.ServiceName, "vpc-endpoint-service/"+aws.StringValue(configuration.ServiceId)) {
+			filtered.ServiceConfigurations = append(filtered.ServiceConfigurations, configuration)
+		}
+	}
+	return &filtered, nil
+}
+
+// ListBuckets implements awsclient.Client, without the excluded S3 buckets
+func (e *cleanupExclusions) ListBuckets(input *s3.ListBucketsInput) (*s3.ListBucketsOutput, error) {
+	output, err := e.Client.ListBuckets(input)
+	if err != nil || output == nil || len(e.excluded) == 0 {
+		return output, err
+	}
+	filtered := *output
+	filtered.Buckets = nil
+	for _, bucket := range output.Buckets {
+		if !e.keep(s3.ServiceName, aws.StringValue(bucket.Name)) {
+			filtered.Buckets = append(filtered.Buckets, bucket)
+		}
+	}
+	return &filtered, nil
+}
+
+// ListHostedZones implements awsclient.Client, without the excluded Route53 hosted zones
+func (e *cleanupExclusions) ListHostedZones(input *route53.ListHostedZonesInput) (*route53.ListHostedZonesOutput, error) {
+	output, err := e.Client.ListHostedZones(input)
+	if err != nil || output == nil || len(e.excluded) == 0 {
+		return output, err
+	}
+	filtered := *output
+	filtered.HostedZones = nil
+	for _, zone := range output.HostedZones {
+		// The IDs of the hosted zones are their paths, e.g. /hostedzone/Z0123456789
+		if !e.keep(route53.ServiceName, strings.TrimPrefix(aws.StringValue(zone.Id), "/")) {
+			filtered.HostedZones = append(filtered.HostedZones, zone)
+		}
+	}
+	return &filtered, nil
+}
+
+// ListUsersPages implements awsclient.Client, without the excluded IAM users
+func (e *cleanupExclusions) ListUsersPages(input *iam.ListUsersInput, fn func(*iam.ListUsersOutput, bool) bool) error {
+	if len(e.excluded) == 0 {
+		return e.Client.ListUsersPages(input, fn)
+	}
+	return e.Client.ListUsersPages(input, func(page *iam.ListUsersOutput, lastPage bool) bool {
+		filtered := *page
+		filtered.Users = nil
+		for _, user := range page.Users {
+			// The resources of the ARNs of the IAM users are their paths and names, e.g. user/my-user
+			if !e.keep(iam.ServiceName, "user"+aws.StringValue(user.Path)+aws.StringValue(user.UserName)) {
+				filtered.Users = append(filtered.Users, user)
+			}
+		}
+		return fn(&filtered, lastPage)
+	})
+}
@@ -0,0 +1,80 @@
+package accountclaim
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cleanup exclusions", func() {
+	var (
+		ctrl          *gomock.Controller
+		mockAWSClient *mock.MockClient
+		exclusions    *cleanupExclusions
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+		exclusions = newCleanupExclusions(testutils.NewTestLogger().Logger(), mockAWSClient, []string{
+			"arn:aws:s3:::forensics",
+			"arn:aws:ec2:us-east-1:123456789012:volume/vol-kept",
+			"arn:aws:route53:::hostedzone/ZKEPT",
+			"not an arn",
+		})
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("Hides the excluded resources from the cleanup steps and records them", func() {
+		mockAWSClient.EXPECT().ListBuckets(gomock.Any()).Return(&s3.ListBucketsOutput{Buckets: []*s3.Bucket{
+			{Name: aws.String("forensics")},
+			{Name: aws.String("image-registry")},
+		}}, nil)
+		mockAWSClient.EXPECT().DescribeVolumes(gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{
+			{VolumeId: aws.String("vol-kept")},
+			{VolumeId: aws.String("vol-deleted")},
+		}}, nil)
+		mockAWSClient.EXPECT().ListHostedZones(gomock.Any()).Return(&route53.ListHostedZonesOutput{
+			HostedZones: []*route53.HostedZone{{Id: aws.String("/hostedzone/ZKEPT")}},
+			IsTruncated: aws.Bool(false),
+		}, nil)
+
+		buckets, err := exclusions.ListBuckets(&s3.ListBucketsInput{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buckets.Buckets).To(HaveLen(1))
+		Expect(aws.StringValue(buckets.Buckets[0].Name)).To(Equal("image-registry"))
+
+		volumes, err := exclusions.DescribeVolumes(&ec2.DescribeVolumesInput{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(volumes.Volumes).To(HaveLen(1))
+		Expect(aws.StringValue(volumes.Volumes[0].VolumeId)).To(Equal("vol-deleted"))
+
+		zones, err := exclusions.ListHostedZones(&route53.ListHostedZonesInput{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(zones.HostedZones).To(BeEmpty())
+		Expect(aws.BoolValue(zones.IsTruncated)).To(BeFalse())
+
+		Expect(exclusions.preservedResources()).To(Equal([]string{
+			"arn:aws:ec2:us-east-1:123456789012:volume/vol-kept",
+			"arn:aws:route53:::hostedzone/ZKEPT",
+			"arn:aws:s3:::forensics",
+		}))
+	})
+
+	It("Passes the other calls through", func() {
+		mockAWSClient.EXPECT().DeleteBucket(gomock.Any()).Return(&s3.DeleteBucketOutput{}, nil)
+		_, err := exclusions.DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String("image-registry")})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exclusions.preservedResources()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,436 @@
+package accountclaim
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+const (
+	// cleanupReportKey is the key of the report in the ConfigMap of a cleanup report
+	cleanupReportKey = "report.json"
+	// globalRegion is the region reported for the S3 buckets, Route53 hosted zones and IAM users, which are listed
+	// globally
+	globalRegion = "global"
+)
+
+// cleanupSteps are the names of the cleanup steps, in the order they're reported
+var cleanupSteps = []string{"snapshots", "ebs_volumes", "s3", "vpc_endpoint_service_configurations", "route53", "iam_users", "route53_delegation"}
+
+// regionalCleanupSteps are the cleanup steps run in every region of the claim, the others list global resources and
+// run once
+var regionalCleanupSteps = []string{"snapshots", "ebs_volumes", "vpc_endpoint_service_configurations"}
+
+// cleanupStepKey identifies a step run in a region in the checkpoint and the recorder. The steps run in the first
+// region of the cleanup, and the global ones, are identified by their name alone, like before the cleanups ran in
+// several regions.
+func cleanupStepKey(step, region, firstRegion string) string {
+	if region == firstRegion || !utils.Contains(regionalCleanupSteps, step) {
+		return step
+	}
+	return step + "@" + region
+}
+
+// cleanupReport is what a run of an AccountCleanup found in the account and removed from it, kept in a ConfigMap for
+// post-incident reviews
+type cleanupReport struct {
+	AccountCleanup string      `json:"accountCleanup"`
+	Account        string      `json:"account"`
+	AwsAccountID   string      `json:"awsAccountID"`
+	Claim          string      `json:"claim"`
+	Time           metav1.Time `json:"time"`
+	// Error is the error of the run, empty if it succeeded
+	Error string              `json:"error,omitempty"`
+	Steps []cleanupStepReport `json:"steps"`
+}
+
+// cleanupStepReport is what a cleanup step found and removed. The resources are identified by their IDs, or names
+// for the S3 buckets.
+type cleanupStepReport struct {
+	Step   string `json:"step"`
+	Region string `json:"region"`
+	// SkippedReason is why the step wasn't run, empty if it was
+	SkippedReason string `json:"skippedReason,omitempty"`
+	Found         int    `json:"found"`
+	// Deleted are the resources deleted
+	Deleted []string `json:"deleted,omitempty"`
+	// Skipped are the ARNs of the resources excluded from the cleanup and left in place
+	Skipped []string `json:"skipped,omitempty"`
+	// Errored are the resources that failed to be deleted
+	Errored []string `json:"errored,omitempty"`
+	// Kept are the resources the step can't delete, left in place, with why
+	Kept []string `json:"kept,omitempty"`
+}
+
+// cleanupReportSummary is the summary of a cleanup report set in the cleanup report annotation of the account
+type cleanupReportSummary struct {
+	ConfigMap string `json:"configMap"`
+	Deleted   int    `json:"deleted"`
+	Skipped   int    `json:"skipped"`
+	Errored   int    `json:"errored"`
+	Kept      int    `json:"kept"`
+}
+
+// keptResourceRecorder is implemented by the clients of the cleanup steps recording the resources a step can't delete
+type keptResourceRecorder interface {
+	keepResource(step, resource, reason string)
+}
+
+// cleanupRecorder records the resources the cleanup steps list and delete through it
+type cleanupRecorder struct {
+	awsclient.Client
+	region string
+	// firstRegion is the region of the recorder the recorders of the other regions of the cleanup were made from
+	firstRegion string
+	mutex       *sync.Mutex
+	steps       map[string]*cleanupStepReport
+}
+
+// newCleanupRecorder wraps the AWS client of a cleanup run in the region to record what the steps do
+func newCleanupRecorder(awsClient awsclient.Client, region string) *cleanupRecorder {
+	return &cleanupRecorder{Client: awsClient, region: region, firstRegion: region, mutex: &sync.Mutex{}, steps: map[string]*cleanupStepReport{}}
+}
+
+// inRegion wraps the AWS client of another region of the cleanup, recording in the same report
+func (c *cleanupRecorder) inRegion(awsClient awsclient.Client, region string) *cleanupRecorder {
+	return &cleanupRecorder{Client: awsClient, region: region, firstRegion: c.firstRegion, mutex: c.mutex, steps: c.steps}
+}
+
+// reportedRegion returns the region the step is reported in by the recorder
+func (c *cleanupRecorder) reportedRegion(step string) string {
+	if !utils.Contains(regionalCleanupSteps, step) {
+		return globalRegion
+	}
+	return c.region
+}
+
+// record updates the report of a step
+func (c *cleanupRecorder) record(step string, update func(*cleanupStepReport)) {
+	c.recordIn(c.region, step, update)
+}
+
+// recordIn updates the report of a step run in the region
+func (c *cleanupRecorder) recordIn(region, step string, update func(*cleanupStepReport)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	key := cleanupStepKey(step, region, c.firstRegion)
+	report, ok := c.steps[key]
+	if !ok {
+		if !utils.Contains(regionalCleanupSteps, step) {
+			region = globalRegion
+		}
+		report = &cleanupStepReport{Step: step, Region: region}
+		c.steps[key] = report
+	}
+	update(report)
+}
+
+// carryOver records the report of a step completed by a previous run of the cleanup
+func (c *cleanupRecorder) carryOver(previous *cleanupReport, step string) {
+	if previous == nil {
+		c.skipStep(step, "completed by a previous run")
+		return
+	}
+	region := c.reportedRegion(step)
+	for i := range previous.Steps {
+		if previous.Steps[i].Step == step && previous.Steps[i].Region == region {
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			c.steps[cleanupStepKey(step, c.region, c.firstRegion)] = &previous.Steps[i]
+			return
+		}
+	}
+	c.skipStep(step, "completed by a previous run")
+}
+
+// skipStep records that a step wasn't run
+func (c *cleanupRecorder) skipStep(step, reason string) {
+	c.record(step, func(report *cleanupStepReport) {
+		report.SkippedReason = reason
+	})
+}
+
+// keepResource records a resource the step can't delete and leaves in place
+func (c *cleanupRecorder) keepResource(step, resource, reason string) {
+	c.record(step, func(report *cleanupStepReport) {
+		report.Kept = append(report.Kept, resource+": "+reason)
+	})
+}
+
+// recordDeletion records the outcome of the deletion of a resource by a step
+func (c *cleanupRecorder) recordDeletion(step, resource string, failed bool) {
+	c.record(step, func(report *cleanupStepReport) {
+		if failed {
+			report.Errored = append(report.Errored, resource)
+			return
+		}
+		report.Deleted = append(report.Deleted, resource)
+	})
+}
+
+// stepReports returns the reports of the steps, with the excluded resources left in place
+func (c *cleanupRecorder) stepReports(preserved []string) []cleanupStepReport {
+	for _, resourceARN := range preserved {
+		resourceARN := resourceARN
+		region := c.firstRegion
+		if parsed, err := arn.Parse(resourceARN); err == nil && parsed.Region != "" {
+			region = parsed.Region
+		}
+		c.recordIn(region, cleanupStepOf(resourceARN), func(report *cleanupStepReport) {
+			report.Skipped = append(report.Skipped, resourceARN)
+		})
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	reports := []cleanupStepReport{}
+	for _, step := range cleanupSteps {
+		if report, ok := c.steps[step]; ok {
+			reports = append(reports, *report)
+		}
+		// The step in the other regions, after the first one
+		keys := []string{}
+		for key := range c.steps {
+			if strings.HasPrefix(key, step+"@") {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			reports = append(reports, *c.steps[key])
+		}
+	}
+	return reports
+}
+
+// cleanupStepOf returns the cleanup step deleting the resource with the given ARN
+func cleanupStepOf(resourceARN string) string {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case parsed.Service == s3.ServiceName:
+		return "s3"
+	case parsed.Service == route53.ServiceName:
+		return "route53"
+	case parsed.Service == iam.ServiceName && strings.HasPrefix(parsed.Resource, "user/"):
+		return "iam_users"
+	case strings.HasPrefix(parsed.Resource, "snapshot/"):
+		return "snapshots"
+	case strings.HasPrefix(parsed.Resource, "volume/"):
+		return "ebs_volumes"
+	case strings.HasPrefix(parsed.Resource, "vpc-endpoint-service/"):
+		return "vpc_endpoint_service_configurations"
+	}
+	return ""
+}
+
+// DescribeSnapshots implements awsclient.Client, counting the snapshots found
+func (c *cleanupRecorder) DescribeSnapshots(input *ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error) {
+	output, err := c.Client.DescribeSnapshots(input)
+	if err == nil && output != nil {
+		c.record("snapshots", func(report *cleanupStepReport) { report.Found += len(output.Snapshots) })
+	}
+	return output, err
+}
+
+// DeleteSnapshot implements awsclient.Client, recording the deletion. The snapshots in use by an AMI are recorded as
+// kept by the step.
+func (c *cleanupRecorder) DeleteSnapshot(input *ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error) {
+	output, err := c.Client.DeleteSnapshot(input)
+	if !isSnapshotInUse(err) {
+		c.recordDeletion("snapshots", aws.StringValue(input.SnapshotId), err != nil)
+	}
+	return output, err
+}
+
+// DescribeVolumes implements awsclient.Client, counting the EBS volumes found. The volumes described by ID, while
+// they're detached, were already counted.
+func (c *cleanupRecorder) DescribeVolumes(input *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+	output, err := c.Client.DescribeVolumes(input)
+	if err == nil && output != nil && len(input.VolumeIds) == 0 {
+		c.record("ebs_volumes", func(report *cleanupStepReport) { report.Found += len(output.Volumes) })
+	}
+	return output, err
+}
+
+// DeleteVolume implements awsclient.Client, recording the deletion
+func (c *cleanupRecorder) DeleteVolume(input *ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error) {
+	output, err := c.Client.DeleteVolume(input)
+	c.recordDeletion("ebs_volumes", aws.StringValue(input.VolumeId), err != nil)
+	return output, err
+}
+
+// DescribeVpcEndpointServiceConfigurations implements awsclient.Client, counting the VPC endpoint services found
+func (c *cleanupRecorder) DescribeVpcEndpointServiceConfigurations(input *ec2.DescribeVpcEndpointServiceConfigurationsInput) (*ec2.DescribeVpcEndpointServiceConfigurationsOutput, error) {
+	output, err := c.Client.DescribeVpcEndpointServiceConfigurations(input)
+	if err == nil && output != nil {
+		c.record("vpc_endpoint_service_configurations", func(report *cleanupStepReport) { report.Found += len(output.ServiceConfigurations) })
+	}
+	return output, err
+}
+
+// DeleteVpcEndpointServiceConfigurations implements awsclient.Client, recording the deletions. The services AWS
+// reports as unsuccessful, or all of them when the call fails, are recorded as errored.
+func (c *cleanupRecorder) DeleteVpcEndpointServiceConfigurations(input *ec2.DeleteVpcEndpointServiceConfigurationsInput) (*ec2.DeleteVpcEndpointServiceConfigurationsOutput, error) {
+	output, err := c.Client.DeleteVpcEndpointServiceConfigurations(input)
+	unsuccessful := map[string]bool{}
+	if output != nil {
+		for _, item := range output.Unsuccessful {
+			unsuccessful[aws.StringValue(item.ResourceId)] = true
+		}
+	}
+	for _, serviceID := range aws.StringValueSlice(input.ServiceIds) {
+		c.recordDeletion("vpc_endpoint_service_configurations", serviceID, err != nil || unsuccessful[serviceID])
+	}
+	return output, err
+}
+
+// ListBuckets implements awsclient.Client, counting the S3 buckets found
+func (c *cleanupRecorder) ListBuckets(input *s3.ListBucketsInput) (*s3.ListBucketsOutput, error) {
+	output, err := c.Client.ListBuckets(input)
+	if err == nil && output != nil {
+		c.record("s3", func(report *cleanupStepReport) { report.Found += len(output.Buckets) })
+	}
+	return output, err
+}
+
+// DeleteBucket implements awsclient.Client, recording the deletion. A bucket already gone counts as deleted.
+func (c *cleanupRecorder) DeleteBucket(input *s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error) {
+	output, err := c.Client.DeleteBucket(input)
+	aerr, ok := err.(awserr.Error)
+	c.recordDeletion("s3", aws.StringValue(input.Bucket), err != nil && !(ok && aerr.Code() == s3.ErrCodeNoSuchBucket))
+	return output, err
+}
+
+// ListHostedZones implements awsclient.Client, counting the Route53 hosted zones found
+func (c *cleanupRecorder) ListHostedZones(input *route53.ListHostedZonesInput) (*route53.ListHostedZonesOutput, error) {
+	output, err := c.Client.ListHostedZones(input)
+	if err == nil && output != nil {
+		c.record("route53", func(report *cleanupStepReport) { report.Found += len(output.HostedZones) })
+	}
+	return output, err
+}
+
+// DeleteHostedZone implements awsclient.Client, recording the deletion
+func (c *cleanupRecorder) DeleteHostedZone(input *route53.DeleteHostedZoneInput) (*route53.DeleteHostedZoneOutput, error) {
+	output, err := c.Client.DeleteHostedZone(input)
+	c.recordDeletion("route53", aws.StringValue(input.Id), err != nil)
+	return output, err
+}
+
+// ListUsersPages implements awsclient.Client, reporting the step. The IAM users of the account are listed, only the
+// ones of the claim are counted as found, when they're deleted.
+func (c *cleanupRecorder) ListUsersPages(input *iam.ListUsersInput, fn func(*iam.ListUsersOutput, bool) bool) error {
+	err := c.Client.ListUsersPages(input, fn)
+	if err == nil {
+		c.record("iam_users", func(report *cleanupStepReport) {})
+	}
+	return err
+}
+
+// DeleteUser implements awsclient.Client, recording the deletion
+func (c *cleanupRecorder) DeleteUser(input *iam.DeleteUserInput) (*iam.DeleteUserOutput, error) {
+	output, err := c.Client.DeleteUser(input)
+	c.record("iam_users", func(report *cleanupStepReport) { report.Found++ })
+	c.recordDeletion("iam_users", aws.StringValue(input.UserName), err != nil)
+	return output, err
+}
+
+// cleanupReportName returns the name of the ConfigMap of the report of a cleanup
+func cleanupReportName(cleanup *awsv1alpha1.AccountCleanup) string {
+	return cleanup.Name + "-report"
+}
+
+// summary returns the totals of the report
+func (r *cleanupReport) summary(configMap string) cleanupReportSummary {
+	summary := cleanupReportSummary{ConfigMap: configMap}
+	for _, step := range r.Steps {
+		summary.Deleted += len(step.Deleted)
+		summary.Skipped += len(step.Skipped)
+		summary.Errored += len(step.Errored)
+		summary.Kept += len(step.Kept)
+	}
+	return summary
+}
+
+// writeCleanupReport stores the report of a cleanup run in a ConfigMap owned by the AccountCleanup, replacing the
+// report of a previous run, and its summary in the cleanup report annotation of the account
+func (r *AccountCleanupReconciler) writeCleanupReport(reqLogger logr.Logger, cleanup *awsv1alpha1.AccountCleanup, reusedAccount *awsv1alpha1.Account, report *cleanupReport) error {
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cleanupReportName(cleanup),
+			Namespace: cleanup.Namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, configMap, func() error {
+		configMap.Data = map[string]string{cleanupReportKey: string(reportJSON)}
+		return controllerutil.SetOwnerReference(cleanup, configMap, r.Scheme)
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to write the cleanup report", "configMap", configMap.Name)
+		return err
+	}
+
+	summaryJSON, err := json.Marshal(report.summary(configMap.Name))
+	if err != nil {
+		return err
+	}
+	err = utils.UpdateWithRetry(r.Client, reusedAccount, func() error {
+		if reusedAccount.Annotations == nil {
+			reusedAccount.Annotations = map[string]string{}
+		}
+		reusedAccount.Annotations[awsv1alpha1.CleanupReportAnnotation] = string(summaryJSON)
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to annotate the account with the cleanup report")
+		return err
+	}
+	reqLogger.Info("Wrote the cleanup report", "configMap", configMap.Name)
+	return nil
+}
+
+// getCleanupReport returns the report of the last run of a cleanup, nil if there's none
+func getCleanupReport(kubeClient client.Client, cleanup *awsv1alpha1.AccountCleanup) (*cleanupReport, error) {
+	configMap := &corev1.ConfigMap{}
+	err := kubeClient.Get(context.TODO(), client.ObjectKey{Name: cleanupReportName(cleanup), Namespace: cleanup.Namespace}, configMap)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	report := &cleanupReport{}
+	err = json.Unmarshal([]byte(configMap.Data[cleanupReportKey]), report)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,103 @@
+package accountclaim
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cleanup report", func() {
+	var (
+		ctrl          *gomock.Controller
+		mockAWSClient *mock.MockClient
+		recorder      *cleanupRecorder
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+		recorder = newCleanupRecorder(mockAWSClient, "us-east-1")
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("Records the resources found and deleted by the steps", func() {
+		mockAWSClient.EXPECT().DescribeVolumes(gomock.Any()).Return(&ec2.DescribeVolumesOutput{Volumes: []*ec2.Volume{
+			{VolumeId: aws.String("vol-1")},
+			{VolumeId: aws.String("vol-2")},
+		}}, nil)
+		mockAWSClient.EXPECT().DeleteVolume(gomock.Any()).Return(&ec2.DeleteVolumeOutput{}, nil)
+		mockAWSClient.EXPECT().DeleteVolume(gomock.Any()).Return(nil, awserr.New("VolumeInUse", "", nil))
+		mockAWSClient.EXPECT().ListBuckets(gomock.Any()).Return(&s3.ListBucketsOutput{Buckets: []*s3.Bucket{{Name: aws.String("gone")}}}, nil)
+		mockAWSClient.EXPECT().DeleteBucket(gomock.Any()).Return(nil, awserr.New(s3.ErrCodeNoSuchBucket, "", nil))
+
+		_, err := recorder.DescribeVolumes(&ec2.DescribeVolumesInput{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = recorder.DeleteVolume(&ec2.DeleteVolumeInput{VolumeId: aws.String("vol-1")})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = recorder.DeleteVolume(&ec2.DeleteVolumeInput{VolumeId: aws.String("vol-2")})
+		Expect(err).To(HaveOccurred())
+		_, err = recorder.ListBuckets(&s3.ListBucketsInput{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = recorder.DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String("gone")})
+		Expect(err).To(HaveOccurred())
+		recorder.skipStep("route53", "excluded by the claim")
+
+		Expect(recorder.stepReports([]string{"arn:aws:s3:::forensics"})).To(Equal([]cleanupStepReport{
+			{Step: "ebs_volumes", Region: "us-east-1", Found: 2, Deleted: []string{"vol-1"}, Errored: []string{"vol-2"}},
+			{Step: "s3", Region: globalRegion, Found: 1, Deleted: []string{"gone"}, Skipped: []string{"arn:aws:s3:::forensics"}},
+			{Step: "route53", Region: globalRegion, SkippedReason: "excluded by the claim"},
+		}))
+	})
+
+	It("Carries over the steps completed by a previous run", func() {
+		previous := &cleanupReport{Steps: []cleanupStepReport{
+			{Step: "snapshots", Region: "us-east-1", Found: 1, Deleted: []string{"snap-1"}},
+		}}
+		recorder.carryOver(previous, "snapshots")
+		recorder.carryOver(previous, "ebs_volumes")
+
+		Expect(recorder.stepReports(nil)).To(Equal([]cleanupStepReport{
+			{Step: "snapshots", Region: "us-east-1", Found: 1, Deleted: []string{"snap-1"}},
+			{Step: "ebs_volumes", Region: "us-east-1", SkippedReason: "completed by a previous run"},
+		}))
+	})
+
+	It("Reports the regional steps of every region of the cleanup", func() {
+		otherRegion := mock.NewMockClient(ctrl)
+		mockAWSClient.EXPECT().DescribeSnapshots(gomock.Any()).Return(&ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{{SnapshotId: aws.String("snap-1")}}}, nil)
+		otherRegion.EXPECT().DescribeSnapshots(gomock.Any()).Return(&ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{{SnapshotId: aws.String("snap-2")}}}, nil)
+		otherRegion.EXPECT().DeleteSnapshot(gomock.Any()).Return(&ec2.DeleteSnapshotOutput{}, nil)
+
+		_, err := recorder.DescribeSnapshots(&ec2.DescribeSnapshotsInput{})
+		Expect(err).NotTo(HaveOccurred())
+		westRecorder := recorder.inRegion(otherRegion, "us-west-2")
+		_, err = westRecorder.DescribeSnapshots(&ec2.DescribeSnapshotsInput{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = westRecorder.DeleteSnapshot(&ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-2")})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(recorder.stepReports([]string{"arn:aws:ec2:us-east-1::snapshot/snap-1"})).To(Equal([]cleanupStepReport{
+			{Step: "snapshots", Region: "us-east-1", Found: 1, Skipped: []string{"arn:aws:ec2:us-east-1::snapshot/snap-1"}},
+			{Step: "snapshots", Region: "us-west-2", Found: 1, Deleted: []string{"snap-2"}},
+		}))
+		Expect(cleanupStepKey("snapshots", "us-west-2", "us-east-1")).To(Equal("snapshots@us-west-2"))
+		Expect(cleanupStepKey("s3", "us-west-2", "us-east-1")).To(Equal("s3"))
+	})
+
+	It("Sums up the report", func() {
+		report := &cleanupReport{Steps: []cleanupStepReport{
+			{Step: "snapshots", Deleted: []string{"snap-1", "snap-2"}, Errored: []string{"snap-3"}},
+			{Step: "s3", Skipped: []string{"arn:aws:s3:::forensics"}},
+		}}
+		Expect(report.summary("cleanup-report")).To(Equal(cleanupReportSummary{ConfigMap: "cleanup-report", Deleted: 2, Skipped: 1, Errored: 1}))
+	})
+})
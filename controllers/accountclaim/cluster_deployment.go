@@ -0,0 +1,193 @@
+package accountclaim
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// clusterDeploymentGVK is the kind of the Hive ClusterDeployments. They're read as unstructured objects, so the
+// operator doesn't depend on the Hive API and runs on clusters without Hive.
+var clusterDeploymentGVK = schema.GroupVersionKind{Group: "hive.openshift.io", Version: "v1", Kind: "ClusterDeployment"}
+
+func newClusterDeployment() *unstructured.Unstructured {
+	clusterDeployment := &unstructured.Unstructured{}
+	clusterDeployment.SetGroupVersionKind(clusterDeploymentGVK)
+	return clusterDeployment
+}
+
+// hiveInstalled returns true if the ClusterDeployment CRD is installed, the ClusterDeployments are only watched then
+func hiveInstalled(mapper meta.RESTMapper) bool {
+	_, err := mapper.RESTMapping(clusterDeploymentGVK.GroupKind(), clusterDeploymentGVK.Version)
+	return err == nil
+}
+
+// clusterDeploymentStatus returns the metadata of the cluster of a ClusterDeployment
+func clusterDeploymentStatus(clusterDeployment *unstructured.Unstructured) *awsv1alpha1.ClusterDeploymentStatus {
+	status := &awsv1alpha1.ClusterDeploymentStatus{
+		Name:              clusterDeployment.GetName(),
+		Namespace:         clusterDeployment.GetNamespace(),
+		DeletionTimestamp: clusterDeployment.GetDeletionTimestamp(),
+	}
+	status.ClusterName, _, _ = unstructured.NestedString(clusterDeployment.Object, "spec", "clusterName")
+	status.ClusterID, _, _ = unstructured.NestedString(clusterDeployment.Object, "spec", "clusterMetadata", "clusterID")
+	status.InfraID, _, _ = unstructured.NestedString(clusterDeployment.Object, "spec", "clusterMetadata", "infraID")
+	status.Region, _, _ = unstructured.NestedString(clusterDeployment.Object, "spec", "platform", "aws", "region")
+	status.APIURL, _, _ = unstructured.NestedString(clusterDeployment.Object, "status", "apiURL")
+	return status
+}
+
+// linkClusterDeployment records the metadata of the ClusterDeployment of the claim in the status of the claimed
+// account and in annotations of the secrets of the claim. The deletion of the ClusterDeployment is recorded as soon
+// as it's seen, before the claim is deleted, so the cleanup of the account can be anticipated.
+func (r *AccountClaimReconciler) linkClusterDeployment(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) error {
+	ref := accountClaim.Spec.ClusterDeploymentRef
+	reqLogger = reqLogger.WithValues("clusterDeployment", ref.Namespace+"/"+ref.Name)
+
+	claimedAccount, err := r.getClaimedAccount(accountClaim.Spec.AccountLink, awsv1alpha1.AccountCrNamespace)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	previous := claimedAccount.Status.ClusterDeployment
+
+	var status *awsv1alpha1.ClusterDeploymentStatus
+	clusterDeployment := newClusterDeployment()
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, clusterDeployment)
+	switch {
+	case meta.IsNoMatchError(err):
+		reqLogger.Info("Hive isn't installed, ignoring the ClusterDeployment of the claim")
+		return nil
+	case k8serr.IsNotFound(err):
+		// Either the ClusterDeployment isn't created yet, or it's gone since it was recorded
+		if previous == nil || previous.Name != ref.Name || previous.Namespace != ref.Namespace {
+			return nil
+		}
+		status = previous.DeepCopy()
+		if status.DeletionTimestamp == nil {
+			now := metav1.Now()
+			status.DeletionTimestamp = &now
+		}
+	case err != nil:
+		reqLogger.Error(err, "Failed to get the ClusterDeployment of the claim")
+		return err
+	default:
+		status = clusterDeploymentStatus(clusterDeployment)
+	}
+
+	// The secrets of the claim may be created after the ClusterDeployment was recorded
+	err = r.annotateClaimSecrets(reqLogger, accountClaim, claimedAccount, status)
+	if err != nil {
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(status, previous) {
+		return nil
+	}
+	err = utils.UpdateStatusWithRetry(r.Client, claimedAccount, func() error {
+		claimedAccount.Status.ClusterDeployment = status
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to record the ClusterDeployment on the claimed account")
+		return err
+	}
+	reqLogger.Info("Recorded the ClusterDeployment on the claimed account", "account", claimedAccount.Name)
+
+	if status.DeletionTimestamp != nil && (previous == nil || previous.DeletionTimestamp == nil) {
+		reqLogger.Info("ClusterDeployment deleted, the account will be cleaned up once the claim is deleted", "account", claimedAccount.Name)
+		utils.RecordEvent(r.recorder, accountClaim, corev1.EventTypeNormal, utils.EventReasonClusterDeleted, "ClusterDeployment %s/%s deleted, account %s will be cleaned up once the claim is deleted", ref.Namespace, ref.Name, claimedAccount.Name)
+		utils.RecordEvent(r.recorder, claimedAccount, corev1.EventTypeNormal, utils.EventReasonClusterDeleted, "ClusterDeployment %s/%s of claim %s/%s deleted", ref.Namespace, ref.Name, accountClaim.Namespace, accountClaim.Name)
+	}
+	return nil
+}
+
+// annotateClaimSecrets sets the cluster metadata annotations on the credentials secret of the claim and on the IAM
+// user secret of the account. Secrets kept outside of Kubernetes, e.g. in Vault, aren't annotated.
+func (r *AccountClaimReconciler) annotateClaimSecrets(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim, claimedAccount *awsv1alpha1.Account, status *awsv1alpha1.ClusterDeploymentStatus) error {
+	keys := []types.NamespacedName{claimSecretKey(accountClaim)}
+	if claimedAccount.Spec.IAMUserSecret != "" {
+		keys = append(keys, types.NamespacedName{Name: claimedAccount.Spec.IAMUserSecret, Namespace: claimedAccount.Namespace})
+	}
+
+	annotations := map[string]string{
+		awsv1alpha1.ClusterDeploymentAnnotation: status.Namespace + "/" + status.Name,
+		awsv1alpha1.ClusterNameAnnotation:       status.ClusterName,
+		awsv1alpha1.ClusterInfraIDAnnotation:    status.InfraID,
+	}
+	for _, key := range keys {
+		secret := &corev1.Secret{}
+		err := r.Client.Get(context.TODO(), key, secret)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if secretAnnotated(secret, annotations) {
+			continue
+		}
+		err = utils.UpdateWithRetry(r.Client, secret, func() error {
+			if secret.Annotations == nil {
+				secret.Annotations = map[string]string{}
+			}
+			for annotation, value := range annotations {
+				if value == "" {
+					delete(secret.Annotations, annotation)
+					continue
+				}
+				secret.Annotations[annotation] = value
+			}
+			return nil
+		})
+		if err != nil {
+			reqLogger.Error(err, "Failed to annotate secret with the cluster metadata", "secret", key.String())
+			return err
+		}
+	}
+	return nil
+}
+
+// secretAnnotated returns true if the secret has the annotations, an empty value meaning no annotation
+func secretAnnotated(secret *corev1.Secret, annotations map[string]string) bool {
+	for annotation, value := range annotations {
+		if secret.Annotations[annotation] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// accountClaimsForClusterDeployment returns the claims linked to a ClusterDeployment, which are reconciled when
+// it's updated or deleted
+func (r *AccountClaimReconciler) accountClaimsForClusterDeployment(object client.Object) []reconcile.Request {
+	accountClaims := &awsv1alpha1.AccountClaimList{}
+	if err := r.Client.List(context.TODO(), accountClaims); err != nil {
+		log.Error(err, "Failed to list the claims of ClusterDeployment", "clusterDeployment", object.GetNamespace()+"/"+object.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, accountClaim := range accountClaims.Items {
+		ref := accountClaim.Spec.ClusterDeploymentRef
+		if ref != nil && ref.Name == object.GetName() && ref.Namespace == object.GetNamespace() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: accountClaim.Name, Namespace: accountClaim.Namespace}})
+		}
+	}
+	return requests
+}
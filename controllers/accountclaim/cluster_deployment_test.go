@@ -0,0 +1,106 @@
+package accountclaim
+
+import (
+	"context"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ClusterDeployment linkage", func() {
+	var (
+		claim          *awsv1alpha1.AccountClaim
+		claimedAccount *awsv1alpha1.Account
+		claimSecret    *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		claim = &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "claim-namespace"},
+			Spec: awsv1alpha1.AccountClaimSpec{
+				AccountLink:          "osd-creds-mgmt-abcdef",
+				AwsCredentialSecret:  awsv1alpha1.SecretRef{Name: "aws", Namespace: "claim-namespace"},
+				ClusterDeploymentRef: &awsv1alpha1.ClusterDeploymentRef{Name: "cluster", Namespace: "cluster-namespace"},
+			},
+		}
+		claimedAccount = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "osd-creds-mgmt-abcdef", Namespace: awsv1alpha1.AccountCrNamespace},
+			Spec:       awsv1alpha1.AccountSpec{ClaimLink: "claim", ClaimLinkNamespace: "claim-namespace"},
+		}
+		claimSecret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "aws", Namespace: "claim-namespace"}}
+	})
+
+	clusterDeployment := func() *unstructured.Unstructured {
+		clusterDeployment := newClusterDeployment()
+		clusterDeployment.SetName("cluster")
+		clusterDeployment.SetNamespace("cluster-namespace")
+		Expect(unstructured.SetNestedField(clusterDeployment.Object, "my-cluster", "spec", "clusterName")).To(Succeed())
+		Expect(unstructured.SetNestedField(clusterDeployment.Object, "my-cluster-x7k2p", "spec", "clusterMetadata", "infraID")).To(Succeed())
+		Expect(unstructured.SetNestedField(clusterDeployment.Object, "us-east-2", "spec", "platform", "aws", "region")).To(Succeed())
+		return clusterDeployment
+	}
+
+	link := func(objects ...client.Object) (client.Client, *awsv1alpha1.Account) {
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(objects, claim, claimedAccount, claimSecret)...).Build()
+		r := &AccountClaimReconciler{Client: kubeClient, Scheme: scheme.Scheme}
+		Expect(r.linkClusterDeployment(testutils.NewTestLogger().Logger(), claim)).To(Succeed())
+
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(claimedAccount), updated)).To(Succeed())
+		return kubeClient, updated
+	}
+
+	It("Records the cluster metadata on the account and the claim secret", func() {
+		kubeClient, updated := link(clusterDeployment())
+
+		Expect(updated.Status.ClusterDeployment).To(Equal(&awsv1alpha1.ClusterDeploymentStatus{
+			Name:        "cluster",
+			Namespace:   "cluster-namespace",
+			ClusterName: "my-cluster",
+			InfraID:     "my-cluster-x7k2p",
+			Region:      "us-east-2",
+		}))
+
+		secret := &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(claimSecret), secret)).To(Succeed())
+		Expect(secret.Annotations).To(Equal(map[string]string{
+			awsv1alpha1.ClusterDeploymentAnnotation: "cluster-namespace/cluster",
+			awsv1alpha1.ClusterNameAnnotation:       "my-cluster",
+			awsv1alpha1.ClusterInfraIDAnnotation:    "my-cluster-x7k2p",
+		}))
+	})
+
+	It("Records the deletion of a recorded ClusterDeployment that is gone", func() {
+		claimedAccount.Status.ClusterDeployment = &awsv1alpha1.ClusterDeploymentStatus{Name: "cluster", Namespace: "cluster-namespace", ClusterName: "my-cluster"}
+
+		_, updated := link()
+		Expect(updated.Status.ClusterDeployment.ClusterName).To(Equal("my-cluster"))
+		Expect(updated.Status.ClusterDeployment.DeletionTimestamp).NotTo(BeNil())
+	})
+
+	It("Waits for a ClusterDeployment that isn't created yet", func() {
+		_, updated := link()
+		Expect(updated.Status.ClusterDeployment).To(BeNil())
+	})
+
+	It("Maps a ClusterDeployment to the claims linked to it", func() {
+		otherClaim := &awsv1alpha1.AccountClaim{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "claim-namespace"}}
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(claim, otherClaim).Build()
+		r := &AccountClaimReconciler{Client: kubeClient, Scheme: scheme.Scheme}
+
+		requests := r.accountClaimsForClusterDeployment(clusterDeployment())
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].NamespacedName).To(Equal(client.ObjectKeyFromObject(claim)))
+	})
+})
@@ -0,0 +1,138 @@
+package accountclaim
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-logr/logr"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// costAllocationTagKeys are the tag keys FinOps attributes the costs of the accounts with
+var costAllocationTagKeys = []string{awsv1alpha1.ClusterIDTagKey, awsv1alpha1.LegalEntityIDTagKey}
+
+// costAllocationTagsEnabled returns true if the operator configmap enables the cost allocation tags
+func costAllocationTagsEnabled(kubeClient client.Client) (bool, error) {
+	cm, err := controllerutils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return cm.Data[awsv1alpha1.CostAllocationTagsConfigMapKey] == "true", nil
+}
+
+// costAllocationTags returns the cost allocation tags of the account, leaving out the ones the claim didn't set
+func costAllocationTags(account *awsv1alpha1.Account) []*iam.Tag {
+	tags := []*iam.Tag{}
+	for _, tag := range awsclient.AWSTags.BuildTags(account, nil, nil).GetIAMTags() {
+		for _, key := range costAllocationTagKeys {
+			if aws.StringValue(tag.Key) == key && aws.StringValue(tag.Value) != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// ensureCostAllocationTags activates the cost allocation tags in the payer account and tags the IAM user of the
+// account of a non-CCS claim with them, so the costs of the operator resources are attributed to the cluster and
+// legal entity of the claim. The IAM user of a pool account is created before the account is claimed, without them.
+func (r *AccountClaimReconciler) ensureCostAllocationTags(reqLogger logr.Logger, account *awsv1alpha1.Account) error {
+	enabled, err := costAllocationTagsEnabled(r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Failed to read the cost allocation tags configuration")
+		return err
+	}
+	if !enabled || account.Spec.AwsAccountID == "" {
+		return nil
+	}
+
+	awsSetupClient, err := r.awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
+		SecretName: controllerutils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		reqLogger.Error(err, "failed building operator AWS client")
+		return err
+	}
+	err = activateCostAllocationTags(reqLogger, awsSetupClient)
+	if err != nil {
+		reqLogger.Error(err, "Failed to activate the cost allocation tags")
+		return err
+	}
+
+	// STS accounts have no IAM user, and the IAM user of the accounts of fleet manager claims is deleted
+	tags := costAllocationTags(account)
+	if account.IsSTS() || account.Spec.IAMUserSecret == "" || len(tags) == 0 {
+		return nil
+	}
+	awsClient, _, err := stsclient.HandleRoleAssumption(reqLogger, r.awsClientBuilder, account, r.Client, awsSetupClient, "", account.GetAssumeRole(), "")
+	if err != nil {
+		reqLogger.Error(err, "failed building AWS client from assume_role")
+		return err
+	}
+	userName := account.GetIAMUserName()
+	_, err = awsClient.TagUser(&iam.TagUserInput{UserName: aws.String(userName), Tags: tags})
+	var aerr awserr.Error
+	if err != nil && errors.As(err, &aerr) && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+		// The IAM drift audit flags the account of a missing user
+		reqLogger.Info("IAM user of the account not found, not tagging it", "user", userName)
+		return nil
+	}
+	if err != nil {
+		reqLogger.Error(err, "Failed to tag the IAM user of the account", "user", userName)
+		return err
+	}
+	return nil
+}
+
+// activateCostAllocationTags activates the inactive cost allocation tags in the payer account. Cost Explorer only
+// knows the tag keys it has seen in the billing data, which can take a day after they're first used; the keys it
+// doesn't know yet are activated with a later claim.
+func activateCostAllocationTags(reqLogger logr.Logger, awsClient awsclient.Client) error {
+	output, err := awsClient.ListCostAllocationTags(&costexplorer.ListCostAllocationTagsInput{
+		TagKeys: aws.StringSlice(costAllocationTagKeys),
+		Type:    aws.String(costexplorer.CostAllocationTagTypeUserDefined),
+	})
+	if err != nil {
+		return err
+	}
+
+	inactive := []*costexplorer.CostAllocationTagStatusEntry{}
+	for _, tag := range output.CostAllocationTags {
+		if aws.StringValue(tag.Status) != costexplorer.CostAllocationTagStatusActive {
+			inactive = append(inactive, &costexplorer.CostAllocationTagStatusEntry{
+				TagKey: tag.TagKey,
+				Status: aws.String(costexplorer.CostAllocationTagStatusActive),
+			})
+		}
+	}
+	if len(inactive) == 0 {
+		return nil
+	}
+
+	reqLogger.Info("Activating cost allocation tags", "count", len(inactive))
+	updated, err := awsClient.UpdateCostAllocationTagsStatus(&costexplorer.UpdateCostAllocationTagsStatusInput{
+		CostAllocationTagsStatus: inactive,
+	})
+	if err != nil {
+		return err
+	}
+	for _, tagErr := range updated.Errors {
+		reqLogger.Info("Cost allocation tag not activated", "tagKey", aws.StringValue(tagErr.TagKey), "code", aws.StringValue(tagErr.Code), "message", aws.StringValue(tagErr.Message))
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package accountclaim
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cost allocation tags", func() {
+	var (
+		ctrl          *gomock.Controller
+		r             *AccountClaimReconciler
+		mockAWSClient *mock.MockClient
+		account       *awsv1alpha1.Account
+		configMap     *corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		ctrl = gomock.NewController(GinkgoT())
+		r = &AccountClaimReconciler{
+			Scheme:           scheme.Scheme,
+			awsClientBuilder: &mock.Builder{MockController: ctrl},
+		}
+		mockAWSClient = mock.GetMockClient(r.awsClientBuilder)
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "osd-creds-mgmt-abcdef", Namespace: awsv1alpha1.AccountCrNamespace},
+			Spec: awsv1alpha1.AccountSpec{
+				AwsAccountID:  "123456789012",
+				IAMUserSecret: "osd-creds-mgmt-abcdef-secret",
+				ClusterID:     "cluster-id",
+				LegalEntity:   awsv1alpha1.LegalEntity{ID: "legal-entity-id", Name: "legal-entity"},
+			},
+			Status: awsv1alpha1.AccountStatus{IAMUserName: "osdManagedAdmin-abcdef"},
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+			Data:       map[string]string{awsv1alpha1.CostAllocationTagsConfigMapKey: "true"},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	buildClient := func() {
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account, configMap).Build()
+	}
+
+	expectAssumeRole := func() {
+		mockAWSClient.EXPECT().AssumeRole(gomock.Any()).Return(&sts.AssumeRoleOutput{
+			AssumedRoleUser: &sts.AssumedRoleUser{
+				Arn:           aws.String("aws:::OrganizationAccountAccessRole/awsAccountOperator"),
+				AssumedRoleId: aws.String("OrganizationAccountAccessRole/awsAccountOperator"),
+			},
+			Credentials: &sts.Credentials{
+				AccessKeyId:     aws.String("ACCESS_KEY"),
+				SecretAccessKey: aws.String("SECRET_KEY"),
+				SessionToken:    aws.String("SESSION_TOKEN"),
+			},
+		}, nil)
+	}
+
+	It("Only tags with the cost allocation tags the claim set", func() {
+		account.Spec.LegalEntity.ID = ""
+		tags := costAllocationTags(account)
+		Expect(tags).To(HaveLen(1))
+		Expect(aws.StringValue(tags[0].Key)).To(Equal(awsv1alpha1.ClusterIDTagKey))
+		Expect(aws.StringValue(tags[0].Value)).To(Equal("cluster-id"))
+	})
+
+	It("Activates the inactive tags and tags the IAM user of the account", func() {
+		buildClient()
+		mockAWSClient.EXPECT().ListCostAllocationTags(gomock.Any()).Return(&costexplorer.ListCostAllocationTagsOutput{
+			CostAllocationTags: []*costexplorer.CostAllocationTag{
+				{TagKey: aws.String(awsv1alpha1.ClusterIDTagKey), Status: aws.String(costexplorer.CostAllocationTagStatusActive)},
+				{TagKey: aws.String(awsv1alpha1.LegalEntityIDTagKey), Status: aws.String(costexplorer.CostAllocationTagStatusInactive)},
+			},
+		}, nil)
+		mockAWSClient.EXPECT().UpdateCostAllocationTagsStatus(&costexplorer.UpdateCostAllocationTagsStatusInput{
+			CostAllocationTagsStatus: []*costexplorer.CostAllocationTagStatusEntry{{
+				TagKey: aws.String(awsv1alpha1.LegalEntityIDTagKey),
+				Status: aws.String(costexplorer.CostAllocationTagStatusActive),
+			}},
+		}).Return(&costexplorer.UpdateCostAllocationTagsStatusOutput{}, nil)
+		expectAssumeRole()
+		mockAWSClient.EXPECT().TagUser(gomock.Any()).DoAndReturn(func(input *iam.TagUserInput) (*iam.TagUserOutput, error) {
+			Expect(aws.StringValue(input.UserName)).To(Equal("osdManagedAdmin-abcdef"))
+			Expect(input.Tags).To(HaveLen(2))
+			return &iam.TagUserOutput{}, nil
+		})
+
+		Expect(r.ensureCostAllocationTags(testutils.NewTestLogger().Logger(), account)).To(Succeed())
+	})
+
+	It("Tolerates a missing IAM user", func() {
+		buildClient()
+		mockAWSClient.EXPECT().ListCostAllocationTags(gomock.Any()).Return(&costexplorer.ListCostAllocationTagsOutput{}, nil)
+		expectAssumeRole()
+		mockAWSClient.EXPECT().TagUser(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "not found", nil))
+
+		Expect(r.ensureCostAllocationTags(testutils.NewTestLogger().Logger(), account)).To(Succeed())
+	})
+
+	It("Doesn't tag the accounts of fleet manager claims", func() {
+		account.Spec.IAMUserSecret = ""
+		buildClient()
+		mockAWSClient.EXPECT().ListCostAllocationTags(gomock.Any()).Return(&costexplorer.ListCostAllocationTagsOutput{}, nil)
+
+		Expect(r.ensureCostAllocationTags(testutils.NewTestLogger().Logger(), account)).To(Succeed())
+	})
+
+	It("Does nothing while it's disabled", func() {
+		configMap.Data = map[string]string{}
+		buildClient()
+
+		Expect(r.ensureCostAllocationTags(testutils.NewTestLogger().Logger(), account)).To(Succeed())
+	})
+})
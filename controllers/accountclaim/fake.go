@@ -1,17 +1,21 @@
 package accountclaim
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/go-logr/logr"
 	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
 	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// Fake process creates a fake secret in the fake account claim namespace and also handles cleaning up of these secrets and account claims once the crd is deleted.
+// isFakeClaim returns true for the claims with spec.fake, or the older `managed.openshift.com/fake: "true"` annotation
+func isFakeClaim(accountClaim *awsv1alpha1.AccountClaim) bool {
+	return accountClaim.Spec.Fake || accountClaim.Annotations[fakeAnnotation] == "true"
+}
+
+// Fake process takes a fake account claim through the states of a real one, Pending then Ready, with a fake secret in
+// its namespace, and cleans up the secret once the claim is deleted. It makes no AWS call and binds no account.
 // This process has been added to facilitate testing in hive per https://issues.redhat.com/browse/OSD-7173
 func (r *AccountClaimReconciler) processFake(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) (bool, error) {
 	// Add finalizer to the CR in case it's not present (e.g. old accounts)
@@ -26,37 +30,53 @@ func (r *AccountClaimReconciler) processFake(reqLogger logr.Logger, accountClaim
 	// Check if accountClaim is being deleted, and remove the fakesecret
 	if accountClaim.DeletionTimestamp != nil {
 		// Delete fake secret if it exists
-		// Create secret for OCM to consume
-		if r.checkIAMSecretExists(accountClaim.Spec.AwsCredentialSecret.Name, accountClaim.Spec.AwsCredentialSecret.Namespace) {
-
-			// Need to check if the secret exists AND that it matches what we're expecting
-			secret := corev1.Secret{}
-			secretObjectKey := client.ObjectKey{Name: accountClaim.Spec.AwsCredentialSecret.Name, Namespace: accountClaim.Spec.AwsCredentialSecret.Namespace}
-			err := r.Client.Get(context.TODO(), secretObjectKey, &secret)
-			if err != nil { //nolint; gosimple // Ignores false-positive S1008 gosimple notice
-				return true, err
-			}
-
-			err = r.Client.Delete(context.TODO(), &secret)
-			if err != nil {
-				reqLogger.Error(err, "failed to fake secret during fake cleanup")
-				return true, err
-			}
+		err := r.deleteClaimSecret(reqLogger, accountClaim)
+		if err != nil {
+			reqLogger.Error(err, "failed to fake secret during fake cleanup")
+			return true, err
 		}
 
 		// Remove finalizer to unlock deletion of the accountClaim
-		err := r.removeFinalizer(reqLogger, accountClaim, accountClaimFinalizer)
+		err = r.removeFinalizer(reqLogger, accountClaim, accountClaimFinalizer)
 		if err != nil {
 			return true, err
 		}
 		return false, nil
 	}
 
+	// Start Pending, like a claim waiting for an account from the pool
+	if accountClaim.Status.State == "" {
+		message := "Attempting to claim fake account"
+		reqLogger.Info(message)
+		accountClaim.Status.State = awsv1alpha1.ClaimStatusPending
+		accountClaim.Status.Conditions = controllerutils.SetAccountClaimCondition(
+			accountClaim.Status.Conditions,
+			awsv1alpha1.AccountUnclaimed,
+			corev1.ConditionTrue,
+			AccountClaimed,
+			message,
+			controllerutils.UpdateConditionNever,
+			accountClaim.Spec.BYOCAWSAccountID != "")
+		err := r.statusUpdate(reqLogger, accountClaim)
+		if err != nil {
+			return true, err
+		}
+		return true, nil
+	}
+
 	// Create Fake Secret if it doesnt exist
-	if !r.checkIAMSecretExists(accountClaim.Spec.AwsCredentialSecret.Name, accountClaim.Spec.AwsCredentialSecret.Namespace) {
-		err := r.Client.Create(context.TODO(), newSecretforCR(accountClaim.Spec.AwsCredentialSecret.Name, accountClaim.Spec.AwsCredentialSecret.Namespace, []byte("fakeAccessKey"), []byte("FakeSecretAccesskey")))
+	exists, err := r.checkClaimSecretExists(accountClaim)
+	if err != nil {
+		return true, err
+	}
+	if !exists {
+		fakeSecret := newSecretforCR(accountClaim.Spec.AwsCredentialSecret.Name, accountClaim.Spec.AwsCredentialSecret.Namespace, []byte("fakeAccessKey"), []byte("FakeSecretAccesskey"))
+		err := controllerutils.SetSecretOwner(fakeSecret, accountClaim, r.Scheme)
+		if err != nil {
+			return true, err
+		}
+		err = r.putClaimSecret(reqLogger, fakeSecret)
 		if err != nil {
-			reqLogger.Error(err, "Unable to create secret for OCM")
 			return true, err
 		}
 	}
@@ -69,7 +89,7 @@ func (r *AccountClaimReconciler) processFake(reqLogger logr.Logger, accountClaim
 			awsv1alpha1.AccountClaimed,
 			corev1.ConditionTrue,
 			AccountClaimed,
-			"Fake ccount claim fulfilled",
+			"Fake account claim fulfilled",
 			controllerutils.UpdateConditionNever,
 			accountClaim.Spec.BYOCAWSAccountID != "")
 		accountClaim.Status.State = awsv1alpha1.ClaimStatusReady
@@ -78,6 +98,7 @@ func (r *AccountClaimReconciler) processFake(reqLogger logr.Logger, accountClaim
 		if err != nil {
 			return true, err
 		}
+		controllerutils.RecordEvent(r.recorder, accountClaim, corev1.EventTypeNormal, controllerutils.EventReasonClaimBound, "Fake account claim fulfilled, no account is bound to it")
 		return false, nil
 	}
 
@@ -0,0 +1,95 @@
+package accountclaim
+
+import (
+	"context"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fake AccountClaim", func() {
+	var (
+		ctrl  *gomock.Controller
+		claim *awsv1alpha1.AccountClaim
+		r     *AccountClaimReconciler
+		req   reconcile.Request
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		localmetrics.Collector = localmetrics.NewMetricsCollector(nil)
+		// The mocked AWS client expects no call, a fake claim doesn't touch AWS
+		ctrl = gomock.NewController(GinkgoT())
+		claim = &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "fake-claim", Namespace: "claim-namespace"},
+			Spec: awsv1alpha1.AccountClaimSpec{
+				Fake:                true,
+				AwsCredentialSecret: awsv1alpha1.SecretRef{Name: "aws", Namespace: "claim-namespace"},
+			},
+		}
+		req = reconcile.Request{NamespacedName: types.NamespacedName{Name: claim.Name, Namespace: claim.Namespace}}
+		r = &AccountClaimReconciler{
+			Client:           fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(claim).Build(),
+			Scheme:           scheme.Scheme,
+			awsClientBuilder: &mock.Builder{MockController: ctrl},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	reconcileClaim := func() *awsv1alpha1.AccountClaim {
+		_, err := r.Reconcile(context.TODO(), req)
+		Expect(err).NotTo(HaveOccurred())
+		updated := &awsv1alpha1.AccountClaim{}
+		err = r.Client.Get(context.TODO(), req.NamespacedName, updated)
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return updated
+	}
+
+	It("Goes through the claim lifecycle without an account", func() {
+		updated := reconcileClaim()
+		Expect(updated.Finalizers).To(ContainElement(accountClaimFinalizer))
+
+		updated = reconcileClaim()
+		Expect(updated.Status.State).To(Equal(awsv1alpha1.ClaimStatusPending))
+
+		updated = reconcileClaim()
+		Expect(updated.Status.State).To(Equal(awsv1alpha1.ClaimStatusReady))
+		Expect(updated.Spec.AccountLink).To(BeEmpty())
+		secret := &corev1.Secret{}
+		Expect(r.Client.Get(context.TODO(), client.ObjectKey{Name: "aws", Namespace: "claim-namespace"}, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("aws_access_key_id", []byte("fakeAccessKey")))
+
+		// The claim is deleted, its secret and finalizer are removed
+		Expect(r.Client.Delete(context.TODO(), updated)).To(Succeed())
+		Expect(reconcileClaim()).To(BeNil())
+		err := r.Client.Get(context.TODO(), client.ObjectKey{Name: "aws", Namespace: "claim-namespace"}, secret)
+		Expect(k8serr.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Recognizes the fake annotation", func() {
+		claim.Spec.Fake = false
+		Expect(isFakeClaim(claim)).To(BeFalse())
+		claim.Annotations = map[string]string{fakeAnnotation: "true"}
+		Expect(isFakeClaim(claim)).To(BeTrue())
+	})
+})
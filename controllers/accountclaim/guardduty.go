@@ -0,0 +1,243 @@
+package accountclaim
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/guardduty"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+const (
+	guardDutyEnrolledReason = "GuardDutyMember"
+	guardDutyRemovedReason  = "GuardDutyMemberRemoved"
+)
+
+// getGuardDutyAdminAccountID returns the ID of the GuardDuty delegated administrator account configured in the
+// operator configmap, empty when the GuardDuty enrollment is disabled
+func getGuardDutyAdminAccountID(kubeClient client.Client) (string, error) {
+	cm, err := controllerutils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(cm.Data[awsv1alpha1.GuardDutyAdminAccountIDConfigMapKey]), nil
+}
+
+// guardDutyAdminClients assumes the operator role into the GuardDuty delegated administrator account and returns a
+// client for each of the regions. The administrator account is a member of the organization like the accounts of the
+// pool.
+func (r *AccountClaimReconciler) guardDutyAdminClients(reqLogger logr.Logger, awsSetupClient awsclient.Client, adminAccountID string, regions []string) (map[string]awsclient.Client, error) {
+	roleARN := config.GetIAMArn(adminAccountID, config.AwsResourceTypeRole, awsv1alpha1.AccountOperatorIAMRole)
+	creds, err := stsclient.GetSTSCredentials(reqLogger, awsSetupClient, roleARN, "", "awsAccountOperator")
+	if err != nil {
+		return nil, err
+	}
+
+	clients := map[string]awsclient.Client{}
+	for _, region := range regions {
+		awsClient, err := r.awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
+			AwsCredsSecretIDKey:     aws.StringValue(creds.Credentials.AccessKeyId),
+			AwsCredsSecretAccessKey: aws.StringValue(creds.Credentials.SecretAccessKey),
+			AwsToken:                aws.StringValue(creds.Credentials.SessionToken),
+			AwsRegion:               region,
+			AwsAccountID:            adminAccountID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		clients[region] = awsClient
+	}
+	return clients, nil
+}
+
+// getGuardDutyDetectorID returns the ID of the GuardDuty detector of the region, there's at most one per region
+func getGuardDutyDetectorID(awsClient awsclient.Client) (string, error) {
+	output, err := awsClient.ListDetectors(&guardduty.ListDetectorsInput{})
+	if err != nil {
+		return "", err
+	}
+	if len(output.DetectorIds) == 0 {
+		return "", errors.New("GuardDuty isn't enabled in the delegated administrator account")
+	}
+	return aws.StringValue(output.DetectorIds[0]), nil
+}
+
+// ensureGuardDutyMembership enrolls the account of a non-CCS claim as a GuardDuty member of the delegated
+// administrator account in the regions of the cluster, and sets its GuardDutyEnrolled condition. Members of the
+// organization are monitored as soon as they're created, without an invitation.
+func (r *AccountClaimReconciler) ensureGuardDutyMembership(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim, account *awsv1alpha1.Account) error {
+	adminAccountID, err := getGuardDutyAdminAccountID(r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Failed to read the GuardDuty configuration")
+		return err
+	}
+	if adminAccountID == "" || account.Spec.AwsAccountID == "" {
+		return nil
+	}
+	if condition := account.GetCondition(awsv1alpha1.AccountGuardDutyEnrolled); condition != nil && condition.Status == corev1.ConditionTrue {
+		return nil
+	}
+
+	awsSetupClient, err := r.awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
+		SecretName: controllerutils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		reqLogger.Error(err, "failed building operator AWS client")
+		return err
+	}
+	// GuardDuty wants the email of the member account, which only AWS Organizations knows
+	orgAccount, err := awsSetupClient.DescribeAccount(&organizations.DescribeAccountInput{AccountId: aws.String(account.Spec.AwsAccountID)})
+	if err != nil {
+		reqLogger.Error(err, "Failed to describe the AWS account")
+		return err
+	}
+
+	regions := claimRegions(accountClaim)
+	clients, err := r.guardDutyAdminClients(reqLogger, awsSetupClient, adminAccountID, regions)
+	if err != nil {
+		reqLogger.Error(err, "Failed to assume role into the GuardDuty delegated administrator account", "adminAccountID", adminAccountID)
+		return err
+	}
+	for _, region := range regions {
+		detectorID, err := getGuardDutyDetectorID(clients[region])
+		if err != nil {
+			reqLogger.Error(err, "Failed to get the GuardDuty detector", "region", region)
+			return err
+		}
+		reqLogger.Info("Enrolling the account as a GuardDuty member", "adminAccountID", adminAccountID, "region", region)
+		output, err := clients[region].CreateMembers(&guardduty.CreateMembersInput{
+			DetectorId: aws.String(detectorID),
+			AccountDetails: []*guardduty.AccountDetail{{
+				AccountId: aws.String(account.Spec.AwsAccountID),
+				Email:     orgAccount.Account.Email,
+			}},
+		})
+		if err == nil {
+			err = unprocessedGuardDutyAccountsError(output.UnprocessedAccounts, false)
+		}
+		if err != nil {
+			reqLogger.Error(err, "Failed to enroll the account as a GuardDuty member", "region", region)
+			return err
+		}
+	}
+
+	message := fmt.Sprintf("GuardDuty member of %s in %s", adminAccountID, strings.Join(regions, ", "))
+	return controllerutils.UpdateStatusWithRetry(r.Client, account, func() error {
+		account.Status.Conditions = controllerutils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountGuardDutyEnrolled, corev1.ConditionTrue, guardDutyEnrolledReason, message, controllerutils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+		return nil
+	})
+}
+
+// removeGuardDutyMembership suspends the monitoring of the account of the claim, disassociates it from the GuardDuty
+// delegated administrator account and deletes its membership, so the findings of the claim don't follow the account
+// back into the pool. The account is enrolled again by its next claim.
+func (r *AccountClaimReconciler) removeGuardDutyMembership(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim, account *awsv1alpha1.Account) error {
+	if condition := account.GetCondition(awsv1alpha1.AccountGuardDutyEnrolled); condition == nil || condition.Status != corev1.ConditionTrue {
+		return nil
+	}
+	adminAccountID, err := getGuardDutyAdminAccountID(r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Failed to read the GuardDuty configuration")
+		return err
+	}
+	if adminAccountID == "" {
+		reqLogger.Info("GuardDuty enrollment is disabled, leaving the GuardDuty membership of the account in place")
+		return nil
+	}
+
+	awsSetupClient, err := r.awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
+		SecretName: controllerutils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		reqLogger.Error(err, "failed building operator AWS client")
+		return err
+	}
+	regions := claimRegions(accountClaim)
+	clients, err := r.guardDutyAdminClients(reqLogger, awsSetupClient, adminAccountID, regions)
+	if err != nil {
+		reqLogger.Error(err, "Failed to assume role into the GuardDuty delegated administrator account", "adminAccountID", adminAccountID)
+		return err
+	}
+
+	accountIDs := aws.StringSlice([]string{account.Spec.AwsAccountID})
+	for _, region := range regions {
+		detectorID, err := getGuardDutyDetectorID(clients[region])
+		if err != nil {
+			reqLogger.Error(err, "Failed to get the GuardDuty detector", "region", region)
+			return err
+		}
+		reqLogger.Info("Removing the GuardDuty membership of the account", "adminAccountID", adminAccountID, "region", region)
+		err = removeGuardDutyMember(clients[region], aws.String(detectorID), accountIDs)
+		if err != nil {
+			reqLogger.Error(err, "Failed to remove the GuardDuty membership of the account", "region", region)
+			return err
+		}
+	}
+
+	return controllerutils.UpdateStatusWithRetry(r.Client, account, func() error {
+		account.Status.Conditions = controllerutils.SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountGuardDutyEnrolled, corev1.ConditionFalse, guardDutyRemovedReason, "The GuardDuty membership was removed with the claim", controllerutils.UpdateConditionIfReasonOrMessageChange, account.Spec.BYOC)
+		return nil
+	})
+}
+
+// removeGuardDutyMember stops monitoring, disassociates and deletes the member accounts of the detector. Accounts
+// that are no longer members are skipped.
+func removeGuardDutyMember(awsClient awsclient.Client, detectorID *string, accountIDs []*string) error {
+	stopped, err := awsClient.StopMonitoringMembers(&guardduty.StopMonitoringMembersInput{DetectorId: detectorID, AccountIds: accountIDs})
+	if err != nil {
+		return err
+	}
+	err = unprocessedGuardDutyAccountsError(stopped.UnprocessedAccounts, true)
+	if err != nil {
+		return err
+	}
+	disassociated, err := awsClient.DisassociateMembers(&guardduty.DisassociateMembersInput{DetectorId: detectorID, AccountIds: accountIDs})
+	if err != nil {
+		return err
+	}
+	err = unprocessedGuardDutyAccountsError(disassociated.UnprocessedAccounts, true)
+	if err != nil {
+		return err
+	}
+	deleted, err := awsClient.DeleteMembers(&guardduty.DeleteMembersInput{DetectorId: detectorID, AccountIds: accountIDs})
+	if err != nil {
+		return err
+	}
+	return unprocessedGuardDutyAccountsError(deleted.UnprocessedAccounts, true)
+}
+
+// unprocessedGuardDutyAccountsError returns an error listing the accounts GuardDuty couldn't process, if any. With
+// ignoreNonMembers, the accounts that aren't members of the detector are left out.
+func unprocessedGuardDutyAccountsError(unprocessed []*guardduty.UnprocessedAccount, ignoreNonMembers bool) error {
+	failures := []string{}
+	for _, account := range unprocessed {
+		result := aws.StringValue(account.Result)
+		if ignoreNonMembers && strings.Contains(strings.ToLower(result), "not a member") {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("%s: %s", aws.StringValue(account.AccountId), result))
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("GuardDuty didn't process %s", strings.Join(failures, "; "))
+}
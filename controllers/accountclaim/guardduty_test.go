@@ -0,0 +1,141 @@
+package accountclaim
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/guardduty"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/sts"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GuardDuty", func() {
+	const (
+		adminAccountID = "222222222222"
+		detectorID     = "detector-id"
+	)
+
+	var (
+		ctrl          *gomock.Controller
+		r             *AccountClaimReconciler
+		mockAWSClient *mock.MockClient
+		accountClaim  *awsv1alpha1.AccountClaim
+		account       *awsv1alpha1.Account
+		configMap     *corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		ctrl = gomock.NewController(GinkgoT())
+		r = &AccountClaimReconciler{
+			Scheme:           scheme.Scheme,
+			awsClientBuilder: &mock.Builder{MockController: ctrl},
+		}
+		mockAWSClient = mock.GetMockClient(r.awsClientBuilder)
+		accountClaim = &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "claim-ns"},
+			Spec: awsv1alpha1.AccountClaimSpec{
+				Aws: awsv1alpha1.Aws{Regions: []awsv1alpha1.AwsRegions{{Name: "us-east-2"}}},
+			},
+		}
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "osd-creds-mgmt-abcdef", Namespace: awsv1alpha1.AccountCrNamespace},
+			Spec:       awsv1alpha1.AccountSpec{AwsAccountID: "123456789012"},
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+			Data:       map[string]string{awsv1alpha1.GuardDutyAdminAccountIDConfigMapKey: adminAccountID},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	buildClient := func() {
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(account, configMap).Build()
+	}
+
+	getCondition := func() *awsv1alpha1.AccountCondition {
+		updated := &awsv1alpha1.Account{}
+		Expect(r.Client.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		return updated.GetCondition(awsv1alpha1.AccountGuardDutyEnrolled)
+	}
+
+	expectAdminAccount := func() {
+		mockAWSClient.EXPECT().AssumeRole(gomock.Any()).DoAndReturn(func(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+			Expect(aws.StringValue(input.RoleArn)).To(ContainSubstring(adminAccountID))
+			return &sts.AssumeRoleOutput{
+				AssumedRoleUser: &sts.AssumedRoleUser{
+					Arn:           aws.String("aws:::OrganizationAccountAccessRole/awsAccountOperator"),
+					AssumedRoleId: aws.String("OrganizationAccountAccessRole/awsAccountOperator"),
+				},
+				Credentials: &sts.Credentials{
+					AccessKeyId:     aws.String("ACCESS_KEY"),
+					SecretAccessKey: aws.String("SECRET_KEY"),
+					SessionToken:    aws.String("SESSION_TOKEN"),
+				},
+			}, nil
+		})
+		mockAWSClient.EXPECT().ListDetectors(gomock.Any()).Return(&guardduty.ListDetectorsOutput{DetectorIds: aws.StringSlice([]string{detectorID})}, nil)
+	}
+
+	It("Enrolls the account of the claim as a GuardDuty member", func() {
+		buildClient()
+		mockAWSClient.EXPECT().DescribeAccount(&organizations.DescribeAccountInput{AccountId: aws.String("123456789012")}).Return(&organizations.DescribeAccountOutput{
+			Account: &organizations.Account{Email: aws.String("osd-creds-mgmt+abcdef@example.com")},
+		}, nil)
+		expectAdminAccount()
+		mockAWSClient.EXPECT().CreateMembers(&guardduty.CreateMembersInput{
+			DetectorId: aws.String(detectorID),
+			AccountDetails: []*guardduty.AccountDetail{{
+				AccountId: aws.String("123456789012"),
+				Email:     aws.String("osd-creds-mgmt+abcdef@example.com"),
+			}},
+		}).Return(&guardduty.CreateMembersOutput{}, nil)
+
+		Expect(r.ensureGuardDutyMembership(testutils.NewTestLogger().Logger(), accountClaim, account)).To(Succeed())
+		condition := getCondition()
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition.Message).To(Equal("GuardDuty member of 222222222222 in us-east-2"))
+
+		// The enrolled account is left alone
+		Expect(r.ensureGuardDutyMembership(testutils.NewTestLogger().Logger(), accountClaim, account)).To(Succeed())
+	})
+
+	It("Removes the GuardDuty membership of the reused account", func() {
+		account.Status.Conditions = []awsv1alpha1.AccountCondition{{Type: awsv1alpha1.AccountGuardDutyEnrolled, Status: corev1.ConditionTrue}}
+		buildClient()
+		expectAdminAccount()
+		accountIDs := aws.StringSlice([]string{"123456789012"})
+		mockAWSClient.EXPECT().StopMonitoringMembers(&guardduty.StopMonitoringMembersInput{DetectorId: aws.String(detectorID), AccountIds: accountIDs}).Return(&guardduty.StopMonitoringMembersOutput{}, nil)
+		mockAWSClient.EXPECT().DisassociateMembers(&guardduty.DisassociateMembersInput{DetectorId: aws.String(detectorID), AccountIds: accountIDs}).Return(&guardduty.DisassociateMembersOutput{}, nil)
+		mockAWSClient.EXPECT().DeleteMembers(&guardduty.DeleteMembersInput{DetectorId: aws.String(detectorID), AccountIds: accountIDs}).Return(&guardduty.DeleteMembersOutput{
+			UnprocessedAccounts: []*guardduty.UnprocessedAccount{{AccountId: aws.String("123456789012"), Result: aws.String("The request is rejected because the given account ID is not a member")}},
+		}, nil)
+
+		Expect(r.removeGuardDutyMembership(testutils.NewTestLogger().Logger(), accountClaim, account)).To(Succeed())
+		Expect(getCondition().Status).To(Equal(corev1.ConditionFalse))
+	})
+
+	It("Does nothing while it's disabled", func() {
+		configMap.Data = map[string]string{}
+		buildClient()
+
+		Expect(r.ensureGuardDutyMembership(testutils.NewTestLogger().Logger(), accountClaim, account)).To(Succeed())
+		Expect(getCondition()).To(BeNil())
+	})
+})
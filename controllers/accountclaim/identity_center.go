@@ -0,0 +1,304 @@
+package accountclaim
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ssoadmin"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+	"gopkg.in/yaml.v2"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// identityCenterPollAttempts is how many times the creation of an account assignment is checked before the
+	// reconcile is retried
+	identityCenterPollAttempts = 15
+)
+
+// identityCenterPollInterval is how long to wait between the checks of the creation of an account assignment
+var identityCenterPollInterval = 2 * time.Second
+
+// errIdentityCenterAssignmentInProgress indicates an account assignment is still being created
+var errIdentityCenterAssignmentInProgress = errors.New("IdentityCenterAssignmentInProgress")
+
+// identityCenterConfig is the IAM Identity Center integration configured in the operator configmap
+type identityCenterConfig struct {
+	instanceARN string
+	region      string
+	assignments []awsv1alpha1.IdentityCenterAssignment
+}
+
+// getIdentityCenterConfig returns the IAM Identity Center integration configured in the operator configmap, or
+// nil if it's disabled
+func getIdentityCenterConfig(kubeClient client.Client) (*identityCenterConfig, error) {
+	cm, err := controllerutils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	instanceARN := strings.TrimSpace(cm.Data[awsv1alpha1.IdentityCenterInstanceARNConfigMapKey])
+	if instanceARN == "" {
+		return nil, nil
+	}
+
+	region := strings.TrimSpace(cm.Data[awsv1alpha1.IdentityCenterRegionConfigMapKey])
+	if region == "" {
+		region = config.GetDefaultRegion()
+	}
+
+	assignments, err := parseIdentityCenterAssignments(cm.Data[awsv1alpha1.IdentityCenterAssignmentsConfigMapKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", awsv1alpha1.IdentityCenterAssignmentsConfigMapKey, err)
+	}
+
+	return &identityCenterConfig{instanceARN: instanceARN, region: region, assignments: assignments}, nil
+}
+
+// parseIdentityCenterAssignments parses the permission sets assigned in claimed accounts. Each entry assigns
+// its permission set to either a group or a user.
+func parseIdentityCenterAssignments(data string) ([]awsv1alpha1.IdentityCenterAssignment, error) {
+	type assignmentConfig struct {
+		PermissionSetARN string `yaml:"permissionSetArn"`
+		GroupID          string `yaml:"groupId,omitempty"`
+		UserID           string `yaml:"userId,omitempty"`
+	}
+
+	entries := []assignmentConfig{}
+	err := yaml.Unmarshal([]byte(data), &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := []awsv1alpha1.IdentityCenterAssignment{}
+	for i, entry := range entries {
+		if entry.PermissionSetARN == "" {
+			return nil, fmt.Errorf("assignment %d has no permissionSetArn", i+1)
+		}
+		if (entry.GroupID == "") == (entry.UserID == "") {
+			return nil, fmt.Errorf("assignment %d must have either a groupId or a userId", i+1)
+		}
+
+		assignment := awsv1alpha1.IdentityCenterAssignment{
+			PermissionSetARN: entry.PermissionSetARN,
+			PrincipalType:    ssoadmin.PrincipalTypeGroup,
+			PrincipalID:      entry.GroupID,
+		}
+		if entry.UserID != "" {
+			assignment.PrincipalType = ssoadmin.PrincipalTypeUser
+			assignment.PrincipalID = entry.UserID
+		}
+		assignments = append(assignments, assignment)
+	}
+	return assignments, nil
+}
+
+// getIdentityCenterClient returns a client of the organization in the region of the IAM Identity Center instance
+func (r *AccountClaimReconciler) getIdentityCenterClient(reqLogger logr.Logger, identityCenter *identityCenterConfig) (awsclient.Client, error) {
+	awsClient, err := r.awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
+		SecretName: controllerutils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  identityCenter.region,
+	})
+	if err != nil {
+		reqLogger.Error(err, "failed building operator AWS client")
+		return nil, err
+	}
+	return awsClient, nil
+}
+
+// assignIdentityCenterPermissionSets assigns the configured IAM Identity Center permission sets in the account of
+// a non-CCS claim, removes the assignments dropped from the configmap and records the assignments in the status
+func (r *AccountClaimReconciler) assignIdentityCenterPermissionSets(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim, account *awsv1alpha1.Account) error {
+	identityCenter, err := getIdentityCenterConfig(r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Failed to read the IAM Identity Center configuration")
+		return err
+	}
+	if identityCenter == nil {
+		return nil
+	}
+
+	awsClient, err := r.getIdentityCenterClient(reqLogger, identityCenter)
+	if err != nil {
+		return err
+	}
+
+	wanted := map[awsv1alpha1.IdentityCenterAssignment]bool{}
+	for _, assignment := range identityCenter.assignments {
+		err := ensureAccountAssignment(reqLogger, awsClient, identityCenter.instanceARN, account.Spec.AwsAccountID, assignment)
+		if err != nil {
+			reqLogger.Error(err, "Failed to assign IAM Identity Center permission set", "permissionSet", assignment.PermissionSetARN, "principal", assignment.PrincipalID)
+			return err
+		}
+		wanted[assignment] = true
+	}
+
+	for _, assignment := range accountClaim.Status.IdentityCenterAssignments {
+		if wanted[assignment] {
+			continue
+		}
+		err := deleteAccountAssignment(reqLogger, awsClient, identityCenter.instanceARN, account.Spec.AwsAccountID, assignment)
+		if err != nil {
+			reqLogger.Error(err, "Failed to remove IAM Identity Center permission set", "permissionSet", assignment.PermissionSetARN, "principal", assignment.PrincipalID)
+			return err
+		}
+	}
+
+	assignments := identityCenter.assignments
+	if len(assignments) == 0 {
+		assignments = nil
+	}
+	if reflect.DeepEqual(assignments, accountClaim.Status.IdentityCenterAssignments) {
+		return nil
+	}
+	accountClaim.Status.IdentityCenterAssignments = assignments
+	return r.statusUpdate(reqLogger, accountClaim)
+}
+
+// removeIdentityCenterPermissionSets removes the IAM Identity Center assignments of a claim from its account, so
+// the account is handed out again without them
+func (r *AccountClaimReconciler) removeIdentityCenterPermissionSets(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim, account *awsv1alpha1.Account) error {
+	if len(accountClaim.Status.IdentityCenterAssignments) == 0 {
+		return nil
+	}
+
+	identityCenter, err := getIdentityCenterConfig(r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Failed to read the IAM Identity Center configuration")
+		return err
+	}
+	if identityCenter == nil {
+		reqLogger.Info("IAM Identity Center integration is disabled, leaving the assignments of the claim in place", "assignments", len(accountClaim.Status.IdentityCenterAssignments))
+		return nil
+	}
+
+	awsClient, err := r.getIdentityCenterClient(reqLogger, identityCenter)
+	if err != nil {
+		return err
+	}
+
+	for _, assignment := range accountClaim.Status.IdentityCenterAssignments {
+		err := deleteAccountAssignment(reqLogger, awsClient, identityCenter.instanceARN, account.Spec.AwsAccountID, assignment)
+		if err != nil {
+			reqLogger.Error(err, "Failed to remove IAM Identity Center permission set", "permissionSet", assignment.PermissionSetARN, "principal", assignment.PrincipalID)
+			return err
+		}
+	}
+	return nil
+}
+
+// hasAccountAssignment checks whether the permission set is assigned to the principal in the account
+func hasAccountAssignment(awsClient awsclient.Client, instanceARN string, accountID string, assignment awsv1alpha1.IdentityCenterAssignment) (bool, error) {
+	var nextToken *string
+	for {
+		output, err := awsClient.ListAccountAssignments(&ssoadmin.ListAccountAssignmentsInput{
+			InstanceArn:      aws.String(instanceARN),
+			AccountId:        aws.String(accountID),
+			PermissionSetArn: aws.String(assignment.PermissionSetARN),
+			NextToken:        nextToken,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		for _, existing := range output.AccountAssignments {
+			if aws.StringValue(existing.PrincipalType) == assignment.PrincipalType && aws.StringValue(existing.PrincipalId) == assignment.PrincipalID {
+				return true, nil
+			}
+		}
+
+		if output.NextToken == nil {
+			return false, nil
+		}
+		nextToken = output.NextToken
+	}
+}
+
+// ensureAccountAssignment assigns the permission set to the principal in the account, waiting for the assignment
+// to be provisioned
+func ensureAccountAssignment(reqLogger logr.Logger, awsClient awsclient.Client, instanceARN string, accountID string, assignment awsv1alpha1.IdentityCenterAssignment) error {
+	assigned, err := hasAccountAssignment(awsClient, instanceARN, accountID, assignment)
+	if err != nil || assigned {
+		return err
+	}
+
+	reqLogger.Info("Assigning IAM Identity Center permission set", "permissionSet", assignment.PermissionSetARN, "principal", assignment.PrincipalID)
+	output, err := awsClient.CreateAccountAssignment(&ssoadmin.CreateAccountAssignmentInput{
+		InstanceArn:      aws.String(instanceARN),
+		PermissionSetArn: aws.String(assignment.PermissionSetARN),
+		PrincipalType:    aws.String(assignment.PrincipalType),
+		PrincipalId:      aws.String(assignment.PrincipalID),
+		TargetType:       aws.String(ssoadmin.TargetTypeAwsAccount),
+		TargetId:         aws.String(accountID),
+	})
+	if err != nil {
+		return err
+	}
+
+	status := output.AccountAssignmentCreationStatus
+	for attempt := 0; ; attempt++ {
+		if status == nil {
+			return fmt.Errorf("CreateAccountAssignment returned no status")
+		}
+		switch aws.StringValue(status.Status) {
+		case ssoadmin.StatusValuesSucceeded:
+			return nil
+		case ssoadmin.StatusValuesFailed:
+			return fmt.Errorf("account assignment failed: %s", aws.StringValue(status.FailureReason))
+		}
+		if attempt >= identityCenterPollAttempts {
+			return errIdentityCenterAssignmentInProgress
+		}
+
+		time.Sleep(identityCenterPollInterval)
+		describeOutput, err := awsClient.DescribeAccountAssignmentCreationStatus(&ssoadmin.DescribeAccountAssignmentCreationStatusInput{
+			InstanceArn:                        aws.String(instanceARN),
+			AccountAssignmentCreationRequestId: status.RequestId,
+		})
+		if err != nil {
+			return err
+		}
+		status = describeOutput.AccountAssignmentCreationStatus
+	}
+}
+
+// deleteAccountAssignment removes the assignment of the permission set to the principal in the account. Its
+// deletion completes asynchronously.
+func deleteAccountAssignment(reqLogger logr.Logger, awsClient awsclient.Client, instanceARN string, accountID string, assignment awsv1alpha1.IdentityCenterAssignment) error {
+	reqLogger.Info("Removing IAM Identity Center permission set", "permissionSet", assignment.PermissionSetARN, "principal", assignment.PrincipalID)
+	output, err := awsClient.DeleteAccountAssignment(&ssoadmin.DeleteAccountAssignmentInput{
+		InstanceArn:      aws.String(instanceARN),
+		PermissionSetArn: aws.String(assignment.PermissionSetARN),
+		PrincipalType:    aws.String(assignment.PrincipalType),
+		PrincipalId:      aws.String(assignment.PrincipalID),
+		TargetType:       aws.String(ssoadmin.TargetTypeAwsAccount),
+		TargetId:         aws.String(accountID),
+	})
+	if err != nil {
+		var aerr awserr.Error
+		if errors.As(err, &aerr) && aerr.Code() == ssoadmin.ErrCodeResourceNotFoundException {
+			return nil
+		}
+		return err
+	}
+
+	status := output.AccountAssignmentDeletionStatus
+	if status != nil && aws.StringValue(status.Status) == ssoadmin.StatusValuesFailed {
+		return fmt.Errorf("account assignment deletion failed: %s", aws.StringValue(status.FailureReason))
+	}
+	return nil
+}
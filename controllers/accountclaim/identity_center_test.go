@@ -0,0 +1,166 @@
+package accountclaim
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ssoadmin"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IAM Identity Center", func() {
+	const (
+		instanceARN      = "arn:aws:sso:::instance/ssoins-1234567890abcdef"
+		permissionSetARN = "arn:aws:sso:::permissionSet/ssoins-1234567890abcdef/ps-1234567890abcdef"
+	)
+
+	var (
+		ctrl          *gomock.Controller
+		r             *AccountClaimReconciler
+		mockAWSClient *mock.MockClient
+		claim         *awsv1alpha1.AccountClaim
+		account       *awsv1alpha1.Account
+		configMap     *corev1.ConfigMap
+		sreGroup      = awsv1alpha1.IdentityCenterAssignment{PermissionSetARN: permissionSetARN, PrincipalType: ssoadmin.PrincipalTypeGroup, PrincipalID: "sre-group"}
+		oldGroup      = awsv1alpha1.IdentityCenterAssignment{PermissionSetARN: permissionSetARN, PrincipalType: ssoadmin.PrincipalTypeGroup, PrincipalID: "old-group"}
+		previousPoll  time.Duration
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		ctrl = gomock.NewController(GinkgoT())
+		r = &AccountClaimReconciler{
+			Scheme:           scheme.Scheme,
+			awsClientBuilder: &mock.Builder{MockController: ctrl},
+		}
+		mockAWSClient = mock.GetMockClient(r.awsClientBuilder)
+		claim = &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "claim-namespace"},
+		}
+		account = &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "osd-creds-mgmt-abcdef", Namespace: awsv1alpha1.AccountCrNamespace},
+			Spec:       awsv1alpha1.AccountSpec{AwsAccountID: "123456789012"},
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+			Data: map[string]string{
+				awsv1alpha1.IdentityCenterInstanceARNConfigMapKey: instanceARN,
+				awsv1alpha1.IdentityCenterAssignmentsConfigMapKey: "- permissionSetArn: " + permissionSetARN + "\n  groupId: sre-group\n",
+			},
+		}
+		previousPoll = identityCenterPollInterval
+		identityCenterPollInterval = 0
+	})
+
+	AfterEach(func() {
+		identityCenterPollInterval = previousPoll
+		ctrl.Finish()
+	})
+
+	buildClient := func() {
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(claim, account, configMap).Build()
+	}
+
+	Context("Parsing the configmap", func() {
+		It("Assigns permission sets to groups and users", func() {
+			assignments, err := parseIdentityCenterAssignments("- permissionSetArn: ps-1\n  groupId: group\n- permissionSetArn: ps-2\n  userId: user\n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(assignments).To(Equal([]awsv1alpha1.IdentityCenterAssignment{
+				{PermissionSetARN: "ps-1", PrincipalType: ssoadmin.PrincipalTypeGroup, PrincipalID: "group"},
+				{PermissionSetARN: "ps-2", PrincipalType: ssoadmin.PrincipalTypeUser, PrincipalID: "user"},
+			}))
+		})
+
+		It("Rejects assignments without exactly one principal", func() {
+			_, err := parseIdentityCenterAssignments("- permissionSetArn: ps-1\n  groupId: group\n  userId: user\n")
+			Expect(err).To(HaveOccurred())
+			_, err = parseIdentityCenterAssignments("- permissionSetArn: ps-1\n")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Is disabled without an instance ARN", func() {
+			delete(configMap.Data, awsv1alpha1.IdentityCenterInstanceARNConfigMapKey)
+			buildClient()
+			identityCenter, err := getIdentityCenterConfig(r.Client)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(identityCenter).To(BeNil())
+		})
+	})
+
+	Context("Claiming an account", func() {
+		It("Creates the missing assignments and records them", func() {
+			buildClient()
+			mockAWSClient.EXPECT().ListAccountAssignments(gomock.Any()).Return(&ssoadmin.ListAccountAssignmentsOutput{}, nil)
+			mockAWSClient.EXPECT().CreateAccountAssignment(&ssoadmin.CreateAccountAssignmentInput{
+				InstanceArn:      aws.String(instanceARN),
+				PermissionSetArn: aws.String(permissionSetARN),
+				PrincipalType:    aws.String(ssoadmin.PrincipalTypeGroup),
+				PrincipalId:      aws.String("sre-group"),
+				TargetType:       aws.String(ssoadmin.TargetTypeAwsAccount),
+				TargetId:         aws.String("123456789012"),
+			}).Return(&ssoadmin.CreateAccountAssignmentOutput{
+				AccountAssignmentCreationStatus: &ssoadmin.AccountAssignmentOperationStatus{Status: aws.String(ssoadmin.StatusValuesInProgress), RequestId: aws.String("request")},
+			}, nil)
+			mockAWSClient.EXPECT().DescribeAccountAssignmentCreationStatus(gomock.Any()).Return(&ssoadmin.DescribeAccountAssignmentCreationStatusOutput{
+				AccountAssignmentCreationStatus: &ssoadmin.AccountAssignmentOperationStatus{Status: aws.String(ssoadmin.StatusValuesSucceeded)},
+			}, nil)
+
+			Expect(r.assignIdentityCenterPermissionSets(testutils.NewTestLogger().Logger(), claim, account)).To(Succeed())
+
+			updated := &awsv1alpha1.AccountClaim{}
+			Expect(r.Client.Get(context.TODO(), types.NamespacedName{Name: claim.Name, Namespace: claim.Namespace}, updated)).To(Succeed())
+			Expect(updated.Status.IdentityCenterAssignments).To(Equal([]awsv1alpha1.IdentityCenterAssignment{sreGroup}))
+		})
+
+		It("Keeps existing assignments and removes the ones dropped from the configmap", func() {
+			claim.Status.IdentityCenterAssignments = []awsv1alpha1.IdentityCenterAssignment{sreGroup, oldGroup}
+			buildClient()
+			mockAWSClient.EXPECT().ListAccountAssignments(gomock.Any()).Return(&ssoadmin.ListAccountAssignmentsOutput{
+				AccountAssignments: []*ssoadmin.AccountAssignment{{PrincipalType: aws.String(ssoadmin.PrincipalTypeGroup), PrincipalId: aws.String("sre-group")}},
+			}, nil)
+			mockAWSClient.EXPECT().DeleteAccountAssignment(gomock.Any()).DoAndReturn(func(input *ssoadmin.DeleteAccountAssignmentInput) (*ssoadmin.DeleteAccountAssignmentOutput, error) {
+				Expect(aws.StringValue(input.PrincipalId)).To(Equal("old-group"))
+				return &ssoadmin.DeleteAccountAssignmentOutput{}, nil
+			})
+
+			Expect(r.assignIdentityCenterPermissionSets(testutils.NewTestLogger().Logger(), claim, account)).To(Succeed())
+			Expect(claim.Status.IdentityCenterAssignments).To(Equal([]awsv1alpha1.IdentityCenterAssignment{sreGroup}))
+		})
+
+		It("Fails when the assignment fails", func() {
+			buildClient()
+			mockAWSClient.EXPECT().ListAccountAssignments(gomock.Any()).Return(&ssoadmin.ListAccountAssignmentsOutput{}, nil)
+			mockAWSClient.EXPECT().CreateAccountAssignment(gomock.Any()).Return(&ssoadmin.CreateAccountAssignmentOutput{
+				AccountAssignmentCreationStatus: &ssoadmin.AccountAssignmentOperationStatus{Status: aws.String(ssoadmin.StatusValuesFailed), FailureReason: aws.String("permission set not provisioned")},
+			}, nil)
+
+			err := r.assignIdentityCenterPermissionSets(testutils.NewTestLogger().Logger(), claim, account)
+			Expect(err).To(MatchError(ContainSubstring("permission set not provisioned")))
+		})
+	})
+
+	Context("Reusing an account", func() {
+		It("Removes the assignments of the claim, ignoring the ones already gone", func() {
+			claim.Status.IdentityCenterAssignments = []awsv1alpha1.IdentityCenterAssignment{sreGroup, oldGroup}
+			buildClient()
+			mockAWSClient.EXPECT().DeleteAccountAssignment(gomock.Any()).Return(&ssoadmin.DeleteAccountAssignmentOutput{}, nil)
+			mockAWSClient.EXPECT().DeleteAccountAssignment(gomock.Any()).Return(nil, awserr.New(ssoadmin.ErrCodeResourceNotFoundException, "not found", nil))
+
+			Expect(r.removeIdentityCenterPermissionSets(testutils.NewTestLogger().Logger(), claim, account)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,192 @@
+package accountclaim
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/controllers/account"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+const (
+	// onDemandCreationKey is the operator configmap key enabling the on-demand creation of accounts for the claims
+	// the pool has no account for: "immediate" creates the account as soon as the pool is found empty,
+	// "after-deadline" once the claim has timed out. Unset, the claims wait for the pool.
+	onDemandCreationKey = "accountclaim-on-demand-creation"
+	// onDemandMaxConcurrentKey is the operator configmap key of how many accounts may be created on demand at once
+	onDemandMaxConcurrentKey = "accountclaim-on-demand-max-concurrent"
+
+	onDemandImmediate     = "immediate"
+	onDemandAfterDeadline = "after-deadline"
+
+	defaultOnDemandMaxConcurrent = 5
+	// onDemandRequeueDelay is how often a claim checks whether the account created for it is Ready
+	onDemandRequeueDelay = time.Minute
+	// accountLimitRequeueDelay is how long a claim waits when the account limit prevents creating its account
+	accountLimitRequeueDelay = 5 * time.Minute
+)
+
+// errNoUnclaimedAccount is returned when the pool has no account the claim can be bound to
+var errNoUnclaimedAccount = errors.New("can't find a suitable account to claim")
+
+// onDemandCreationEnabled returns true if an account is to be created for the claim, which the pool has no
+// account for
+func (r *AccountClaimReconciler) onDemandCreationEnabled(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) bool {
+	cm, err := controllerutils.GetOperatorConfigMap(r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Could not retrieve the operator configmap, not creating an account on demand")
+		return false
+	}
+	switch mode := cm.Data[onDemandCreationKey]; mode {
+	case "":
+		return false
+	case onDemandImmediate:
+		return true
+	case onDemandAfterDeadline:
+		condition := controllerutils.FindAccountClaimCondition(accountClaim.Status.Conditions, awsv1alpha1.AccountClaimTimedOut)
+		return condition != nil && condition.Status == corev1.ConditionTrue
+	default:
+		reqLogger.Info("Invalid on-demand account creation mode in configmap, not creating an account on demand", "mode", mode)
+		return false
+	}
+}
+
+// onDemandMaxConcurrent returns how many accounts may be created on demand at once
+func (r *AccountClaimReconciler) onDemandMaxConcurrent(reqLogger logr.Logger) int {
+	cm, err := controllerutils.GetOperatorConfigMap(r.Client)
+	if err != nil {
+		return defaultOnDemandMaxConcurrent
+	}
+	value, ok := cm.Data[onDemandMaxConcurrentKey]
+	if !ok {
+		return defaultOnDemandMaxConcurrent
+	}
+	maxConcurrent, err := strconv.Atoi(value)
+	if err != nil || maxConcurrent < 0 {
+		reqLogger.Info("Invalid on-demand account creation concurrency in configmap, using the default", "value", value)
+		return defaultOnDemandMaxConcurrent
+	}
+	return maxConcurrent
+}
+
+// accountsCanBeCreated returns true if the organization is below the account limit of the operator configmap
+func (r *AccountClaimReconciler) accountsCanBeCreated() bool {
+	if r.accountWatcher == nil {
+		return false
+	}
+	return r.accountWatcher.GetAccountCount() < r.accountWatcher.GetLimit()
+}
+
+// createAccountOnDemand creates an Account dedicated to a claim the pool has no account for. The claim waits for
+// it, and is bound to it by getUnclaimedAccount once it's Ready.
+func (r *AccountClaimReconciler) createAccountOnDemand(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) (reconcile.Result, error) {
+	claimKey := accountClaim.Namespace + "/" + accountClaim.Name
+
+	accountList := &awsv1alpha1.AccountList{}
+	err := r.Client.List(context.TODO(), accountList, client.InNamespace(awsv1alpha1.AccountCrNamespace))
+	if err != nil {
+		reqLogger.Error(err, "Unable to get accountList")
+		return reconcile.Result{}, err
+	}
+	inFlight := 0
+	for i := range accountList.Items {
+		existing := &accountList.Items[i]
+		if !existing.IsCreatedOnDemand() || !existing.HasNeverBeenClaimed() {
+			continue
+		}
+		if existing.Annotations[awsv1alpha1.OnDemandClaimAnnotation] == claimKey {
+			// A failed account isn't replaced, so a persistent failure doesn't create accounts in a loop
+			if existing.IsFailed() {
+				reqLogger.Info("The account created on demand for the claim failed, the claim waits for the pool", "account", existing.Name)
+			} else {
+				reqLogger.Info("Waiting for the account created on demand for the claim", "account", existing.Name, "state", existing.Status.State)
+			}
+			return reconcile.Result{RequeueAfter: onDemandRequeueDelay}, nil
+		}
+		if !existing.IsFailed() && existing.Status.State != AccountReady {
+			inFlight++
+		}
+	}
+
+	if maxConcurrent := r.onDemandMaxConcurrent(reqLogger); inFlight >= maxConcurrent {
+		reqLogger.Info("Too many accounts being created on demand, the claim waits", "inFlight", inFlight, "max", maxConcurrent)
+		return reconcile.Result{RequeueAfter: onDemandRequeueDelay}, nil
+	}
+	if controllerutils.IsAWSMutationPaused(r.Client) {
+		reqLogger.Info("AWS changes are paused, not creating an account on demand")
+		return reconcile.Result{RequeueAfter: controllerutils.MaintenanceModeRequeueDelay}, nil
+	}
+	if !config.IsFedramp() && !r.accountsCanBeCreated() {
+		reqLogger.Info("AWS Account limit reached, not creating an account on demand")
+		return reconcile.Result{RequeueAfter: accountLimitRequeueDelay}, nil
+	}
+
+	poolName := accountClaim.Spec.AccountPool
+	if poolName == "" {
+		poolName, err = config.GetDefaultAccountPoolName(reqLogger, r.Client)
+		if err != nil {
+			reqLogger.Error(err, "Failed getting default AccountPool name")
+			return reconcile.Result{}, err
+		}
+	}
+
+	newAccount := account.GenerateAccountCR(awsv1alpha1.AccountCrNamespace)
+	newAccount.Spec.AccountPool = poolName
+	newAccount.Annotations = map[string]string{awsv1alpha1.OnDemandClaimAnnotation: claimKey}
+	controllerutils.AddFinalizer(newAccount, awsv1alpha1.AccountFinalizer)
+	// The account gets the service quotas of the accounts of its pool
+	newAccount.Spec.RegionalServiceQuotas, err = controllerutils.GetServiceQuotasFromAccountPool(reqLogger, poolName, r.Client)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	newAccount.Status.RegionalServiceQuotas = make(awsv1alpha1.RegionalServiceQuotas)
+
+	err = r.Client.Create(context.TODO(), newAccount)
+	if err != nil {
+		reqLogger.Error(err, "Failed to create an account on demand")
+		return reconcile.Result{}, err
+	}
+	reqLogger.Info("Created an account on demand for the claim, the pool has no account for it", "account", newAccount.Name, "accountPool", poolName)
+	controllerutils.RecordEvent(r.recorder, accountClaim, corev1.EventTypeNormal, controllerutils.EventReasonAccountOnDemand, "Pool %s has no account for the claim, created account %s for it", poolName, newAccount.Name)
+	return reconcile.Result{RequeueAfter: onDemandRequeueDelay}, nil
+}
+
+// releaseOnDemandAccounts removes the OnDemandClaimAnnotation from the accounts created on demand for the claim
+// that it isn't bound to, once it's bound to another account or deleted, so any claim of their pool can get them
+func (r *AccountClaimReconciler) releaseOnDemandAccounts(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) error {
+	claimKey := accountClaim.Namespace + "/" + accountClaim.Name
+
+	accountList := &awsv1alpha1.AccountList{}
+	err := r.Client.List(context.TODO(), accountList, client.InNamespace(awsv1alpha1.AccountCrNamespace))
+	if err != nil {
+		reqLogger.Error(err, "Unable to get accountList")
+		return err
+	}
+	for i := range accountList.Items {
+		reserved := &accountList.Items[i]
+		if reserved.Annotations[awsv1alpha1.OnDemandClaimAnnotation] != claimKey || reserved.Name == accountClaim.Spec.AccountLink {
+			continue
+		}
+		err = controllerutils.UpdateWithRetry(r.Client, reserved, func() error {
+			if reserved.Annotations[awsv1alpha1.OnDemandClaimAnnotation] == claimKey {
+				delete(reserved.Annotations, awsv1alpha1.OnDemandClaimAnnotation)
+			}
+			return nil
+		})
+		if err != nil {
+			reqLogger.Error(err, "Failed to release the account created on demand for the claim", "account", reserved.Name)
+			return err
+		}
+		reqLogger.Info("Released the account created on demand for the claim to its pool", "account", reserved.Name)
+	}
+	return nil
+}
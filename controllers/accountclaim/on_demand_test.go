@@ -0,0 +1,153 @@
+package accountclaim
+
+import (
+	"context"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// accountLimit is a totalaccountwatcher.AccountWatcherIface with a fixed account count and limit
+type accountLimit struct {
+	accounts int
+	limit    int
+}
+
+func (a *accountLimit) GetAccountCount() int {
+	return a.accounts
+}
+
+func (a *accountLimit) GetLimit() int {
+	return a.limit
+}
+
+var _ = Describe("On-demand account creation", func() {
+	var (
+		claim     *awsv1alpha1.AccountClaim
+		configMap *corev1.ConfigMap
+		r         *AccountClaimReconciler
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		claim = &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "claim-namespace"},
+			Status:     awsv1alpha1.AccountClaimStatus{State: awsv1alpha1.ClaimStatusPending},
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+			Data: map[string]string{
+				"accountpool":       "my-default-accountpool:\n  default: true",
+				onDemandCreationKey: onDemandImmediate,
+			},
+		}
+		r = &AccountClaimReconciler{Scheme: scheme.Scheme, accountWatcher: &accountLimit{accounts: 10, limit: 100}}
+	})
+
+	onDemandAccounts := func() []awsv1alpha1.Account {
+		accounts := &awsv1alpha1.AccountList{}
+		Expect(r.Client.List(context.TODO(), accounts)).To(Succeed())
+		var onDemand []awsv1alpha1.Account
+		for _, account := range accounts.Items {
+			if account.IsCreatedOnDemand() {
+				onDemand = append(onDemand, account)
+			}
+		}
+		return onDemand
+	}
+
+	It("Creates a single account dedicated to the claim", func() {
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(claim, configMap).Build()
+		Expect(r.onDemandCreationEnabled(testutils.NewTestLogger().Logger(), claim)).To(BeTrue())
+
+		result, err := r.createAccountOnDemand(testutils.NewTestLogger().Logger(), claim)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(onDemandRequeueDelay))
+		// The claim waits for its account rather than creating another one
+		_, err = r.createAccountOnDemand(testutils.NewTestLogger().Logger(), claim)
+		Expect(err).NotTo(HaveOccurred())
+
+		accounts := onDemandAccounts()
+		Expect(accounts).To(HaveLen(1))
+		Expect(accounts[0].Spec.AccountPool).To(Equal("my-default-accountpool"))
+		Expect(accounts[0].Annotations[awsv1alpha1.OnDemandClaimAnnotation]).To(Equal("claim-namespace/claim"))
+	})
+
+	It("Hands out an account created on demand only to its claim", func() {
+		account := &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{awsv1alpha1.OnDemandClaimAnnotation: "claim-namespace/claim"}},
+			Status:     awsv1alpha1.AccountStatus{State: AccountReady},
+		}
+		otherClaim := &awsv1alpha1.AccountClaim{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "claim-namespace"}}
+		Expect(CanAccountBeClaimedByAccountClaim(account, claim)).To(BeTrue())
+		Expect(CanAccountBeClaimedByAccountClaim(account, otherClaim)).To(BeFalse())
+	})
+
+	It("Waits for the deadline of the claim in after-deadline mode", func() {
+		configMap.Data[onDemandCreationKey] = onDemandAfterDeadline
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(claim, configMap).Build()
+		Expect(r.onDemandCreationEnabled(testutils.NewTestLogger().Logger(), claim)).To(BeFalse())
+
+		claim.Status.Conditions = []awsv1alpha1.AccountClaimCondition{{Type: awsv1alpha1.AccountClaimTimedOut, Status: corev1.ConditionTrue}}
+		Expect(r.onDemandCreationEnabled(testutils.NewTestLogger().Logger(), claim)).To(BeTrue())
+	})
+
+	It("Doesn't create more accounts than the concurrency cap", func() {
+		configMap.Data[onDemandMaxConcurrentKey] = "1"
+		creating := &awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "creating",
+				Namespace:   awsv1alpha1.AccountCrNamespace,
+				Annotations: map[string]string{awsv1alpha1.OnDemandClaimAnnotation: "claim-namespace/other"},
+			},
+			Status: awsv1alpha1.AccountStatus{State: string(awsv1alpha1.AccountCreating)},
+		}
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(claim, configMap, creating).Build()
+
+		_, err := r.createAccountOnDemand(testutils.NewTestLogger().Logger(), claim)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(onDemandAccounts()).To(HaveLen(1))
+	})
+
+	It("Releases the accounts created on demand that the claim isn't bound to", func() {
+		reserved := func(name string, claimKey string) *awsv1alpha1.Account {
+			return &awsv1alpha1.Account{ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   awsv1alpha1.AccountCrNamespace,
+				Annotations: map[string]string{awsv1alpha1.OnDemandClaimAnnotation: claimKey},
+			}}
+		}
+		claim.Spec.AccountLink = "bound"
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+			claim,
+			reserved("bound", "claim-namespace/claim"),
+			reserved("unbound", "claim-namespace/claim"),
+			reserved("other", "claim-namespace/other"),
+		).Build()
+
+		Expect(r.releaseOnDemandAccounts(testutils.NewTestLogger().Logger(), claim)).To(Succeed())
+		var names []string
+		for _, account := range onDemandAccounts() {
+			names = append(names, account.Name)
+		}
+		Expect(names).To(ConsistOf("bound", "other"))
+	})
+
+	It("Doesn't create accounts past the account limit", func() {
+		r.accountWatcher = &accountLimit{accounts: 100, limit: 100}
+		r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(claim, configMap).Build()
+
+		result, err := r.createAccountOnDemand(testutils.NewTestLogger().Logger(), claim)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(accountLimitRequeueDelay))
+		Expect(onDemandAccounts()).To(BeEmpty())
+	})
+})
@@ -24,8 +24,10 @@ func MoveAccountToOU(r *AccountClaimReconciler, reqLogger logr.Logger, awsClient
 		// If we failed to retrieve the ConfigMap, simply leave the account in Root
 		unexpectedErrorMsg := "OU: Failed to find OU mapping ConfigMap, leaving account in root"
 		reqLogger.Info(unexpectedErrorMsg)
-		accountClaim.Spec.AccountOU = "ROOT"
-		return r.specUpdate(reqLogger, accountClaim)
+		return r.specUpdate(reqLogger, accountClaim, func() error {
+			accountClaim.Spec.AccountOU = "ROOT"
+			return nil
+		})
 	}
 
 	// Get OU ID for root and base
@@ -62,8 +64,10 @@ func MoveAccountToOU(r *AccountClaimReconciler, reqLogger logr.Logger, awsClient
 			accountMovedMsg := fmt.Sprintf("OU: Account %s was already in the desired OU %s", account.Name, ouName)
 			reqLogger.Info(accountMovedMsg)
 			// Update accountclaim spec
-			accountClaim.Spec.AccountOU = ouID
-			return r.specUpdate(reqLogger, accountClaim)
+			return r.specUpdate(reqLogger, accountClaim, func() error {
+				accountClaim.Spec.AccountOU = ouID
+				return nil
+			})
 		}
 		return err
 	}
@@ -73,8 +77,10 @@ func MoveAccountToOU(r *AccountClaimReconciler, reqLogger logr.Logger, awsClient
 	reqLogger.Info(accountMovedMsg)
 
 	// Update unclaimedAccount.Spec.AwsAccountOU
-	accountClaim.Spec.AccountOU = ouID
-	return r.specUpdate(reqLogger, accountClaim)
+	return r.specUpdate(reqLogger, accountClaim, func() error {
+		accountClaim.Spec.AccountOU = ouID
+		return nil
+	})
 }
 
 // CreateOrFindOU will create or find an existing OU and return its ID
@@ -2,25 +2,34 @@ package accountclaim
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/openshift/aws-account-operator/config"
 	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
 
-	"github.com/rkt/rkt/tests/testutils/logger"
-
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/go-logr/logr"
 	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/controllers/account"
+	"github.com/openshift/aws-account-operator/pkg/audit"
 	"github.com/openshift/aws-account-operator/pkg/awsclient"
 	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/tracing"
 	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -28,9 +37,54 @@ const (
 	AccountReady = "Ready"
 	// AccountFailed indicates account reuse has failed
 	AccountFailed = "Failed"
+	// AccountPendingVerification indicates the reused account waits for its preflight checks to pass
+	AccountPendingVerification = "PendingVerification"
+
+	// bucketNotEmptyAttempts is how many times the deletion of an S3 bucket is tried while its deleted objects are
+	// still listed
+	bucketNotEmptyAttempts = 5
+
+	// route53MaxBatchRecords and route53MaxBatchValueChars are the limits of a Route53 change batch
+	route53MaxBatchRecords    = 1000
+	route53MaxBatchValueChars = 32000
+
+	// route53ChangeAttempts is how many times a throttled Route53 change batch is sent
+	route53ChangeAttempts = 5
+
+	// awsBackupSourceResourceTag is set by AWS Backup on the snapshots it creates
+	awsBackupSourceResourceTag = "aws:backup:source-resource"
+
+	// ebsVolumeDetachAttempts is how many times a detached EBS volume is checked before it's given up on
+	ebsVolumeDetachAttempts = 10
 )
 
-func (r *AccountClaimReconciler) finalizeAccountClaim(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) error {
+// bucketNotEmptyDelay is how long the deletion of a bucket that isn't empty yet waits before the next attempt, growing
+// with each attempt. Tests set it to 0.
+var bucketNotEmptyDelay = 2 * time.Second
+
+// route53ChangeDelay is how long a throttled Route53 change batch waits before it's sent again, growing with each
+// attempt. Tests set it to 0.
+var route53ChangeDelay = time.Second
+
+// ebsVolumeDetachDelay is how long to wait between the checks of an EBS volume being detached. Tests set it to 0.
+var ebsVolumeDetachDelay = 3 * time.Second
+
+func (r *AccountClaimReconciler) finalizeAccountClaim(ctx context.Context, reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) (err error) {
+
+	ctx, span := tracing.Start(ctx, "Finalize AccountClaim")
+	defer func() { span.End(err) }()
+	// Clients built here trace their AWS calls under the finalize span
+	awsClientBuilder := awsclient.WithContext(ctx, r.awsClientBuilder)
+
+	reqLogger = reqLogger.WithValues("claim", accountClaim.Name, "account", accountClaim.Spec.AccountLink)
+
+	// Delete the secrets created for the claim first, so they're removed whichever way the rest
+	// of the finalization goes
+	_, err = r.cleanUpClaimSecrets(reqLogger, accountClaim)
+	if err != nil {
+		reqLogger.Error(err, "Failed to delete AccountClaim secrets")
+		return err
+	}
 
 	// Get account claimed by deleted accountclaim
 	reusedAccount, err := r.getClaimedAccount(accountClaim.Spec.AccountLink, awsv1alpha1.AccountCrNamespace)
@@ -52,9 +106,13 @@ func (r *AccountClaimReconciler) finalizeAccountClaim(reqLogger logr.Logger, acc
 		return nil
 	}
 
-	// If the reused account is STS, then we don't have to clean up
+	// If the reused account is STS, then we only have to delete the roles provisioned for the claim
 	if reusedAccount.Spec.ManualSTSMode {
-		err := r.Client.Delete(context.TODO(), reusedAccount)
+		err := r.cleanUpSTSRoles(reqLogger, accountClaim)
+		if err != nil {
+			return err
+		}
+		err = r.Client.Delete(context.TODO(), reusedAccount)
 		if err != nil {
 			reqLogger.Error(err, "Failed to delete STS account from accountclaim cleanup")
 			return err
@@ -62,117 +120,135 @@ func (r *AccountClaimReconciler) finalizeAccountClaim(reqLogger logr.Logger, acc
 		return nil
 	}
 
-	var awsClient awsclient.Client
-	var awsClientInput awsclient.NewAwsClientInput
-
-	clusterAwsRegion := accountClaim.Spec.Aws.Regions[0].Name
-	if reusedAccount.IsBYOC() {
-		// AWS credential comes from accountclaim object osdCcsAdmin user
-		// We must use this user as we would other delete the osdManagedAdmin
-		// user that we're going to delete
-		// TODO: We should use the role here
-		awsClientInput = awsclient.NewAwsClientInput{
-			SecretName: accountClaim.Spec.BYOCSecretRef.Name,
-			NameSpace:  accountClaim.Namespace,
-			AwsRegion:  clusterAwsRegion,
-		}
-		awsClient, err = r.awsClientBuilder.GetClient(controllerName, r.Client, awsClientInput)
+	// The IAM Identity Center access, the budget and the GuardDuty membership of the claim go before the account is
+	// cleaned up for the next one
+	if !reusedAccount.IsBYOC() {
+		err = r.removeIdentityCenterPermissionSets(reqLogger, accountClaim, reusedAccount)
 		if err != nil {
-			connErr := fmt.Sprintf("Unable to create aws client for region %s", clusterAwsRegion)
-			reqLogger.Error(err, connErr)
 			return err
 		}
-	} else {
-		defaultRegion := config.GetDefaultRegion()
-		// We expect this secret to exist in the same namespace Account CR's are created
-		awsSetupClient, err := r.awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
-			SecretName: utils.AwsSecretName,
-			NameSpace:  awsv1alpha1.AccountCrNamespace,
-			AwsRegion:  defaultRegion,
-		})
+		err = r.deleteBudget(reqLogger, reusedAccount)
 		if err != nil {
-			reqLogger.Error(err, "failed building operator AWS client")
 			return err
 		}
-
-		// This can not be the default region us-east-1 when cleaning up S3 buckets that live in other regions (if the cluster is not in us-east-1):
-		// e.g. https://github.com/parallelworks/interactive_session/pull/65
-		awsClient, _, err = stsclient.HandleRoleAssumption(reqLogger, r.awsClientBuilder, reusedAccount, r.Client, awsSetupClient, clusterAwsRegion, awsv1alpha1.AccountOperatorIAMRole, "")
+		err = r.removeGuardDutyMembership(reqLogger, accountClaim, reusedAccount)
 		if err != nil {
-			connErr := fmt.Sprintf("Unable to create aws client for region %s", clusterAwsRegion)
-			reqLogger.Error(err, connErr)
 			return err
 		}
+
+		// The account is cleaned up and returned to the pool by the AccountCleanup controller, the deletion of the
+		// claim doesn't wait for it
+		return r.queueAccountCleanup(reqLogger, accountClaim, reusedAccount)
 	}
 
-	if reusedAccount.IsBYOC() {
-		err := r.Client.Delete(context.TODO(), reusedAccount)
-		if err != nil {
-			reqLogger.Error(err, "Failed to delete BYOC account from accountclaim cleanup")
-			return err
+	clusterAwsRegion := claimRegions(accountClaim)[0]
+	reqLogger = reqLogger.WithValues("awsAccountID", reusedAccount.Spec.AwsAccountID, "region", clusterAwsRegion)
+	awsClientBuilder = awsclient.WithContext(audit.WithFields(ctx, audit.Fields{AWSAccountID: reusedAccount.Spec.AwsAccountID}), r.awsClientBuilder)
+
+	if account.GetCCSAccountRetention(reqLogger, r.Client) > 0 {
+		// The Account CR is kept as a record of the cleanup, the retired account collector deletes it
+		// once the retention expires. Only an account that isn't retired yet needs to be accessed.
+		if !reusedAccount.IsRetired() {
+			awsClient, err := ccsAccountClient(reqLogger, awsClientBuilder, r.Client, reusedAccount, clusterAwsRegion)
+			if err != nil {
+				return err
+			}
+			err = r.retireCCSAccount(reqLogger, awsClient, reusedAccount)
+			if err != nil {
+				reqLogger.Error(err, "Failed to retire BYOC account from accountclaim cleanup")
+				return err
+			}
 		}
-
-		// Cleanup BYOC secret
-		err = r.removeBYOCSecretFinalizer(accountClaim)
+	} else {
+		// The Account finalizer removes the resources the operator created in the customer account, with its
+		// own access to it
+		err = r.Client.Delete(context.TODO(), reusedAccount)
 		if err != nil {
-			reqLogger.Error(err, "Failed to remove BYOC secret finalizer")
+			reqLogger.Error(err, "Failed to delete BYOC account from accountclaim cleanup")
 			return err
 		}
-
-		return nil
 	}
 
-	before := time.Now()
-	// Perform account clean up in AWS
-	err = r.cleanUpAwsAccount(reqLogger, awsClient)
+	// Cleanup BYOC secret
+	err = r.removeBYOCSecretFinalizer(accountClaim)
 	if err != nil {
-		localmetrics.Collector.AddAccountReuseCleanupFailure()
-		reqLogger.Error(err, "Failed to clean up AWS account")
+		reqLogger.Error(err, "Failed to remove BYOC secret finalizer")
 		return err
 	}
-	localmetrics.Collector.SetAccountReusedCleanupDuration(time.Since(before).Seconds())
 
-	err = r.resetAccountSpecStatus(reqLogger, reusedAccount, accountClaim, awsv1alpha1.AccountReused, "Ready")
-	if err != nil {
-		reqLogger.Error(err, "Failed to reset account entity")
-		return err
-	}
-
-	reqLogger.Info("Successfully finalized AccountClaim")
 	return nil
 }
 
-func (r *AccountClaimReconciler) resetAccountSpecStatus(reqLogger logr.Logger, reusedAccount *awsv1alpha1.Account, deletedAccountClaim *awsv1alpha1.AccountClaim, accountState awsv1alpha1.AccountConditionType, conditionStatus string) error {
-
-	// Reset claimlink and carry over legal entity from deleted claim
-	reusedAccount.Spec.ClaimLink = ""
-	reusedAccount.Spec.ClaimLinkNamespace = ""
+// ccsAccountClient returns a client of the CCS account in the region of the cluster. CCS accounts are accessed
+// through the ManagedOpenShift-Support role rather than the osdCcsAdmin keys of the BYOC secret, which the customer
+// may have rotated or deleted by the time the claim is deleted. The client is nil when the customer removed the
+// role, like the Account finalizer the deletion of the claim isn't blocked on it.
+func ccsAccountClient(reqLogger logr.Logger, awsClientBuilder awsclient.IBuilder, kubeClient client.Client, ccsAccount *awsv1alpha1.Account, region string) (awsclient.Client, error) {
+	// We expect this secret to exist in the same namespace Account CR's are created
+	awsSetupClient, err := awsClientBuilder.GetClient(controllerName, kubeClient, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		reqLogger.Error(err, "failed building operator AWS client")
+		return nil, err
+	}
 
-	// LegalEntity is being carried over here to support older accounts, that were claimed
-	// prior to the introduction of reuse (their account's legalEntity will be blank )
-	if reusedAccount.Spec.LegalEntity.ID == "" {
-		reusedAccount.Spec.LegalEntity.ID = deletedAccountClaim.Spec.LegalEntity.ID
-		reusedAccount.Spec.LegalEntity.Name = deletedAccountClaim.Spec.LegalEntity.Name
+	// This can not be the default region us-east-1 when cleaning up S3 buckets that live in other regions (if the cluster is not in us-east-1):
+	// e.g. https://github.com/parallelworks/interactive_session/pull/65
+	awsClient, _, err := stsclient.HandleRoleAssumption(reqLogger, awsClientBuilder, ccsAccount, kubeClient, awsSetupClient, region, ccsAccount.GetAssumeRole(), "")
+	if err != nil {
+		var aerr awserr.Error
+		if !errors.As(err, &aerr) || aerr.Code() != "AccessDenied" {
+			reqLogger.Error(err, "Unable to create aws client")
+			return nil, err
+		}
+		reqLogger.Info("Access to the CCS account was denied, retiring the account anyway", "role", ccsAccount.GetAssumeRole())
+		return nil, nil
 	}
+	return awsClient, nil
+}
 
-	err := r.accountSpecUpdate(reqLogger, reusedAccount)
+// resetAccountSpecStatus releases an account from its deleted claim, with the given state
+func resetAccountSpecStatus(reqLogger logr.Logger, kubeClient client.Client, reusedAccount *awsv1alpha1.Account, legalEntity awsv1alpha1.LegalEntity, accountState awsv1alpha1.AccountConditionType, conditionStatus string) error {
+
+	// Updates are retried on conflicts, the account may be changed concurrently by the account
+	// controller or the background watchers
+	err := utils.UpdateWithRetry(kubeClient, reusedAccount, func() error {
+		// Reset claimlink and carry over legal entity from deleted claim
+		reusedAccount.Spec.ClaimLink = ""
+		reusedAccount.Spec.ClaimLinkNamespace = ""
+		reusedAccount.Spec.ClaimLinkUID = ""
+		reusedAccount.Spec.ClusterID = ""
+
+		// LegalEntity is being carried over here to support older accounts, that were claimed
+		// prior to the introduction of reuse (their account's legalEntity will be blank )
+		if reusedAccount.Spec.LegalEntity.ID == "" {
+			reusedAccount.Spec.LegalEntity.ID = legalEntity.ID
+			reusedAccount.Spec.LegalEntity.Name = legalEntity.Name
+		}
+		return nil
+	})
 	if err != nil {
 		reqLogger.Error(err, "Failed to update account spec for reuse")
 		return err
 	}
 
-	reqLogger.Info(fmt.Sprintf(
-		"Setting RotateCredentials and RotateConsoleCredentials for account %s", reusedAccount.Spec.AwsAccountID))
-	reusedAccount.Status.RotateConsoleCredentials = true
-	reusedAccount.Status.RotateCredentials = true
-
-	// Update account status and add conditions indicating account reuse
-	reusedAccount.Status.State = conditionStatus
-	reusedAccount.Status.Claimed = false
-	reusedAccount.Status.Reused = true
-	conditionMsg := fmt.Sprintf("Account Reuse - %s", conditionStatus)
-	utils.SetAccountStatus(reusedAccount, conditionMsg, accountState, conditionStatus)
-	err = r.accountStatusUpdate(reqLogger, reusedAccount)
+	reqLogger.Info("Setting RotateCredentials and RotateConsoleCredentials")
+	err = utils.UpdateStatusWithRetry(kubeClient, reusedAccount, func() error {
+		reusedAccount.Status.RotateConsoleCredentials = true
+		reusedAccount.Status.RotateCredentials = true
+
+		// Update account status and add conditions indicating account reuse
+		reusedAccount.Status.State = conditionStatus
+		reusedAccount.Status.Claimed = false
+		reusedAccount.Status.Reused = true
+		reusedAccount.Status.ClusterDeployment = nil
+		conditionMsg := fmt.Sprintf("Account Reuse - %s", conditionStatus)
+		utils.SetAccountStatus(reusedAccount, conditionMsg, accountState, conditionStatus)
+		return nil
+	})
 	if err != nil {
 		reqLogger.Error(err, "Failed to update account status for reuse")
 		return err
@@ -181,7 +257,51 @@ func (r *AccountClaimReconciler) resetAccountSpecStatus(reqLogger logr.Logger, r
 	return nil
 }
 
-func (r *AccountClaimReconciler) cleanUpAwsAccount(reqLogger logr.Logger, awsClient awsclient.Client) error {
+// hardenReusedAccount re-applies the account hardening in the regions enabled in the account and
+// persists the resulting conditions
+func (r *AccountCleanupReconciler) hardenReusedAccount(reqLogger logr.Logger, awsClientBuilder awsclient.IBuilder, awsClient awsclient.Client, reusedAccount *awsv1alpha1.Account, creds *sts.AssumeRoleOutput) error {
+	regionsEnabledInAccount, err := awsClient.DescribeRegions(&ec2.DescribeRegionsInput{
+		AllRegions: aws.Bool(false),
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to retrieve list of regions enabled in this account")
+		return err
+	}
+
+	var regions []awsv1alpha1.AwsRegions
+	for _, region := range regionsEnabledInAccount.Regions {
+		regions = append(regions, awsv1alpha1.AwsRegions{Name: aws.StringValue(region.RegionName)})
+	}
+
+	deleteDefaultVPC := !utils.GetKeepDefaultVPCFromAccountPool(reqLogger, reusedAccount.Spec.AccountPool, r.Client)
+	hardenErr := account.HardenAccount(reqLogger, r.Client, awsClientBuilder, reusedAccount, creds, regions, deleteDefaultVPC)
+
+	// The conditions are saved before the account spec is reset, which would discard them
+	hardened := reusedAccount.Status.Conditions
+	err = utils.UpdateStatusWithRetry(r.Client, reusedAccount, func() error {
+		for _, conditionType := range []awsv1alpha1.AccountConditionType{awsv1alpha1.AccountS3PublicAccessBlocked, awsv1alpha1.AccountEBSEncryptionByDefault, awsv1alpha1.AccountDefaultVPCDeleted} {
+			if condition := utils.FindAccountCondition(hardened, conditionType); condition != nil {
+				reusedAccount.Status.Conditions = utils.SetAccountCondition(reusedAccount.Status.Conditions, conditionType, condition.Status, condition.Reason, condition.Message, utils.UpdateConditionIfReasonOrMessageChange, reusedAccount.Spec.BYOC)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Account status update failed", "account", reusedAccount.Name)
+		return err
+	}
+	return hardenErr
+}
+
+// cleanUpAwsAccount runs the cleanup steps with the clients of the regions of the cleanup. The regional steps run in
+// each of the regions, the global ones with the client of the first region.
+func (r *AccountCleanupReconciler) cleanUpAwsAccount(ctx context.Context, reqLogger logr.Logger, awsClients map[string]awsclient.Client, cleanup *awsv1alpha1.AccountCleanup, reusedAccount *awsv1alpha1.Account) error {
+	// The S3, EC2 and Route53 data of a CCS account belongs to the customer, the cleanup of CCS accounts is
+	// limited to the resources the operator created, see account.CleanUpCCSAccount
+	if reusedAccount.IsBYOC() {
+		return fmt.Errorf("%w: refusing to clean up the data of CCS account %s", awsv1alpha1.ErrBYOCAccountCleanUp, reusedAccount.Spec.AwsAccountID)
+	}
+
 	// Clean up status, used to store an error if any of the cleanup functions received one
 	cleanUpStatusFailed := false
 
@@ -191,36 +311,154 @@ func (r *AccountClaimReconciler) cleanUpAwsAccount(reqLogger logr.Logger, awsCli
 	defer close(awsNotifications)
 	defer close(awsErrors)
 
-	// Declare un array of cleanup functions
-	cleanUpFunctions := []func(logr.Logger, awsclient.Client, chan string, chan string) error{
-		r.cleanUpAwsAccountSnapshots,
-		r.cleanUpAwsAccountEbsVolumes,
-		r.cleanUpAwsAccountS3,
-		r.CleanUpAwsAccountVpcEndpointServiceConfigurations,
-		r.cleanUpAwsRoute53,
+	// The steps list and delete the resources through the recorder, for the cleanup report. They don't see the
+	// resources excluded by the claim, so they're left in place.
+	regions := cleanup.Spec.GetRegions()
+	firstRegion := regions[0]
+	recorder := newCleanupRecorder(awsClients[firstRegion], firstRegion)
+
+	// Declare un array of cleanup functions, named for the metrics
+	cleanUpFunctions := []struct {
+		step    string
+		cleanUp func(logr.Logger, awsclient.Client, chan string, chan string) error
+	}{
+		{"snapshots", r.cleanUpAwsAccountSnapshots},
+		{"ebs_volumes", r.cleanUpAwsAccountEbsVolumes},
+		{"s3", r.cleanUpAwsAccountS3},
+		{"vpc_endpoint_service_configurations", r.CleanUpAwsAccountVpcEndpointServiceConfigurations},
+		{"route53", r.cleanUpAwsRoute53},
+		{"iam_users", r.cleanUpAwsAccountIAMUsers(cleanup.Spec.ClaimUID)},
+		{route53DelegationStep, r.cleanUpRoute53Delegation(ctx, cleanup.Spec.Route53Delegation, recorder)},
+	}
+
+	// The steps completed by a previous run of the cleanup, interrupted by an operator shutdown or failed, aren't
+	// run again
+	checkpoint := &cleanupCheckpoint{cleanup: cleanup}
+	completedSteps := checkpoint.completedSteps()
+
+	var previousReport *cleanupReport
+	if len(completedSteps) > 0 {
+		var reportErr error
+		previousReport, reportErr = getCleanupReport(r.Client, cleanup)
+		if reportErr != nil {
+			reqLogger.Error(reportErr, "Failed to read the report of the previous run of the cleanup")
+		}
 	}
 
 	// Call the clean up functions in parallel
-	for _, cleanUpFunc := range cleanUpFunctions {
-		//nolint:errcheck // Not checking return value of goroutine
-		go cleanUpFunc(reqLogger, awsClient, awsNotifications, awsErrors)
+	var wg sync.WaitGroup
+	started := 0
+	var allExclusions []*cleanupExclusions
+	// The errors returned by the steps keep the AWS errors, unlike the messages they notify
+	var stepErrorsMutex sync.Mutex
+	var stepErrors []error
+	for _, region := range regions {
+		regionRecorder := recorder
+		if region != firstRegion {
+			regionRecorder = recorder.inRegion(awsClients[region], region)
+		}
+		exclusions := newCleanupExclusions(reqLogger, regionRecorder, cleanup.Spec.Exclusions.ResourceARNs)
+		allExclusions = append(allExclusions, exclusions)
+		for _, cleanUpFunc := range cleanUpFunctions {
+			if region != firstRegion && !utils.Contains(regionalCleanupSteps, cleanUpFunc.step) {
+				continue
+			}
+			stepKey := cleanupStepKey(cleanUpFunc.step, region, firstRegion)
+			if len(cleanup.Spec.Steps) > 0 && !utils.Contains(cleanup.Spec.Steps, cleanUpFunc.step) {
+				regionRecorder.skipStep(cleanUpFunc.step, "not in the steps of the cleanup")
+				continue
+			}
+			if cleanUpFunc.step == route53DelegationStep && cleanup.Spec.Route53Delegation == nil {
+				regionRecorder.skipStep(cleanUpFunc.step, "no Route53 delegation")
+				continue
+			}
+			if utils.Contains(cleanup.Spec.Exclusions.Steps, cleanUpFunc.step) {
+				reqLogger.Info("Skipping cleanup step excluded by the claim", "cleanupStep", cleanUpFunc.step, "region", region)
+				regionRecorder.skipStep(cleanUpFunc.step, "excluded by the claim")
+				continue
+			}
+			if utils.Contains(completedSteps, stepKey) {
+				reqLogger.Info("Skipping cleanup step completed by a previous run", "cleanupStep", cleanUpFunc.step, "region", region)
+				regionRecorder.carryOver(previousReport, cleanUpFunc.step)
+				continue
+			}
+			started++
+			wg.Add(1)
+			go func(step, stepKey, region string, cleanUp func(logr.Logger, awsclient.Client, chan string, chan string) error, exclusions *cleanupExclusions) {
+				defer wg.Done()
+				start := time.Now()
+				_, span := tracing.Start(ctx, "Clean up "+step, tracing.String("cleanup.step", step), tracing.String("aws.region", region))
+				err := cleanUp(reqLogger.WithValues("cleanupStep", step, "region", region), exclusions, awsNotifications, awsErrors)
+				span.End(err)
+				localmetrics.Collector.SetAccountReuseCleanupStepDuration(step, time.Since(start).Seconds(), err)
+				if err != nil {
+					utils.RecordEvent(r.recorder, cleanup, corev1.EventTypeWarning, utils.EventReasonCleanupStepFailed, "Cleanup step %s failed in %s: %v", step, region, err)
+					stepErrorsMutex.Lock()
+					stepErrors = append(stepErrors, fmt.Errorf("%s: %w", stepKey, err))
+					stepErrorsMutex.Unlock()
+					return
+				}
+				// Recorded as soon as the step completes, so the progress survives the operator being killed
+				// once its graceful shutdown timeout expires
+				checkpoint.completeStep(reqLogger, r.Client, stepKey)
+			}(cleanUpFunc.step, stepKey, region, cleanUpFunc.cleanUp, exclusions)
+		}
 	}
 
 	var err error
 	// Wait for clean up functions to end
-	for i := 0; i < len(cleanUpFunctions); i++ {
+	// Each step logs its own outcome with its cleanupStep, only the errors are collected here
+	for i := 0; i < started; i++ {
 		select {
-		case msg := <-awsNotifications:
-			reqLogger.Info(msg)
+		case <-awsNotifications:
 		case errMsg := <-awsErrors:
 			err = errors.New(errMsg)
-			reqLogger.Error(err, errMsg)
 			cleanUpStatusFailed = true
 		}
 	}
+	// The steps record their checkpoint after notifying, don't let a late one outlive the cleanup
+	wg.Wait()
+	if len(stepErrors) > 0 {
+		sort.Slice(stepErrors, func(i, j int) bool { return stepErrors[i].Error() < stepErrors[j].Error() })
+		err = errors.Join(stepErrors...)
+	}
+
+	preserved := []string{}
+	for _, exclusions := range allExclusions {
+		for _, resourceARN := range exclusions.preservedResources() {
+			if !utils.Contains(preserved, resourceARN) {
+				preserved = append(preserved, resourceARN)
+			}
+		}
+	}
+	sort.Strings(preserved)
+	if len(preserved) > 0 {
+		reqLogger.Info("Left the resources excluded by the claim in place", "resources", preserved)
+		checkpoint.preserveResources(reqLogger, r.Client, preserved)
+	}
+
+	report := &cleanupReport{
+		AccountCleanup: cleanup.Name,
+		Account:        reusedAccount.Name,
+		AwsAccountID:   reusedAccount.Spec.AwsAccountID,
+		Claim:          cleanup.Spec.ClaimNamespace + "/" + cleanup.Spec.ClaimName,
+		Time:           metav1.Now(),
+		Steps:          recorder.stepReports(preserved),
+	}
+	if err != nil {
+		report.Error = err.Error()
+	}
+	// The report is for the reviews, failing to write it doesn't fail the cleanup
+	_ = r.writeCleanupReport(reqLogger, cleanup, reusedAccount, report)
 
 	// Return an error if we saw any errors on the awsErrors channel so we can make the reused account as failed
 	if cleanUpStatusFailed {
+		// A step failing while the operator shuts down is retried after the restart rather than failing the account
+		if utils.IsShuttingDown(ctx) {
+			checkpoint.interrupt(reqLogger, r.Client)
+			utils.RecordEvent(r.recorder, cleanup, corev1.EventTypeWarning, utils.EventReasonCleanupInterrupted, "Cleanup of account %s interrupted by the operator shutdown, it resumes after the restart", reusedAccount.Name)
+			return fmt.Errorf("%w: %v", utils.ErrShuttingDown, err)
+		}
 		cleanUpStatusFailedMsg := "failed to clean up AWS account"
 		reqLogger.Error(err, cleanUpStatusFailedMsg)
 		return err
@@ -231,7 +469,7 @@ func (r *AccountClaimReconciler) cleanUpAwsAccount(reqLogger logr.Logger, awsCli
 	return nil
 }
 
-func (r *AccountClaimReconciler) cleanUpAwsAccountSnapshots(reqLogger logr.Logger, awsClient awsclient.Client, awsNotifications chan string, awsErrors chan string) error {
+func (r *AccountCleanupReconciler) cleanUpAwsAccountSnapshots(reqLogger logr.Logger, awsClient awsclient.Client, awsNotifications chan string, awsErrors chan string) error {
 
 	// Filter only for snapshots owned by the account
 	selfOwnerFilter := ec2.Filter{
@@ -248,35 +486,117 @@ func (r *AccountClaimReconciler) cleanUpAwsAccountSnapshots(reqLogger logr.Logge
 	ebsSnapshots, err := awsClient.DescribeSnapshots(&describeSnapshotsInput)
 	if err != nil {
 		descError := "Failed describing EBS snapshots"
+		reqLogger.Error(err, descError)
 		awsErrors <- descError
 		return err
 	}
 
-	for _, snapshot := range ebsSnapshots.Snapshots {
+	locked, err := lockedSnapshots(awsClient, ebsSnapshots.Snapshots)
+	if err != nil {
+		descError := "Failed describing the locks of the EBS snapshots"
+		reqLogger.Error(err, descError)
+		awsErrors <- descError
+		return err
+	}
 
-		deleteSnapshotInput := ec2.DeleteSnapshotInput{
-			SnapshotId: aws.String(*snapshot.SnapshotId),
+	kept := 0
+	for _, snapshot := range ebsSnapshots.Snapshots {
+		reason := undeletableSnapshotReason(snapshot, locked)
+		if reason == "" {
+			reason, err = deleteSnapshot(awsClient, snapshot.SnapshotId)
+			if err != nil {
+				delError := fmt.Errorf("failed deleting EBS snapshot: %s: %w", *snapshot.SnapshotId, err).Error()
+				reqLogger.Error(err, "Failed deleting EBS snapshot", "snapshotID", *snapshot.SnapshotId)
+				awsErrors <- delError
+				return err
+			}
 		}
-
-		_, err = awsClient.DeleteSnapshot(&deleteSnapshotInput)
-		if err != nil {
-			delError := fmt.Errorf("failed deleting EBS snapshot: %s: %w", *snapshot.SnapshotId, err).Error()
-			awsErrors <- delError
-			return err
+		if reason != "" {
+			kept++
+			reqLogger.Info("Leaving in place an EBS snapshot that can't be deleted", "snapshotID", *snapshot.SnapshotId, "reason", reason)
+			if recorder, ok := awsClient.(keptResourceRecorder); ok {
+				recorder.keepResource("snapshots", *snapshot.SnapshotId, reason)
+			}
 		}
 	}
 
 	successMsg := "Snapshot cleanup finished successfully"
+	reqLogger.Info(successMsg, "snapshots", len(ebsSnapshots.Snapshots), "kept", kept)
 	awsNotifications <- successMsg
 	return nil
 }
 
-func (r *AccountClaimReconciler) CleanUpAwsAccountVpcEndpointServiceConfigurations(reqLogger logr.Logger, awsClient awsclient.Client, awsNotifications chan string, awsErrors chan string) error {
+// lockedSnapshots returns the lock state of the locked snapshots among the given ones, by snapshot ID. Expired locks
+// don't keep the snapshots from being deleted.
+func lockedSnapshots(awsClient awsclient.Client, snapshots []*ec2.Snapshot) (map[string]string, error) {
+	locked := map[string]string{}
+	if len(snapshots) == 0 {
+		return locked, nil
+	}
+	var snapshotIDs []*string
+	for _, snapshot := range snapshots {
+		snapshotIDs = append(snapshotIDs, snapshot.SnapshotId)
+	}
+	output, err := awsClient.DescribeLockedSnapshots(&ec2.DescribeLockedSnapshotsInput{SnapshotIds: snapshotIDs})
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range output.Snapshots {
+		if state := aws.StringValue(info.LockState); state != ec2.LockStateExpired {
+			locked[aws.StringValue(info.SnapshotId)] = state
+		}
+	}
+	return locked, nil
+}
+
+// undeletableSnapshotReason returns why the snapshot can't be deleted by the cleanup, empty if it can. The snapshots
+// created by AWS Backup are deleted through their recovery point, and the locked ones can't be deleted until their
+// lock expires.
+func undeletableSnapshotReason(snapshot *ec2.Snapshot, locked map[string]string) string {
+	for _, tag := range snapshot.Tags {
+		if aws.StringValue(tag.Key) == awsBackupSourceResourceTag {
+			return "managed by AWS Backup"
+		}
+	}
+	if state, ok := locked[aws.StringValue(snapshot.SnapshotId)]; ok {
+		return fmt.Sprintf("locked in %s mode", state)
+	}
+	return ""
+}
+
+// deleteSnapshot stops sharing the snapshot with other accounts, so they can't create volumes from it while it's
+// deleted, then deletes it. Returns why it can't be deleted when it's in use by an AMI.
+func deleteSnapshot(awsClient awsclient.Client, snapshotID *string) (string, error) {
+	_, err := awsClient.ResetSnapshotAttribute(&ec2.ResetSnapshotAttributeInput{
+		SnapshotId: snapshotID,
+		Attribute:  aws.String(ec2.SnapshotAttributeNameCreateVolumePermission),
+	})
+	if err != nil {
+		return "", err
+	}
+	_, err = awsClient.DeleteSnapshot(&ec2.DeleteSnapshotInput{SnapshotId: snapshotID})
+	if isSnapshotInUse(err) {
+		return "in use by an AMI", nil
+	}
+	return "", err
+}
+
+// isSnapshotInUse returns true if the snapshot couldn't be deleted because an AMI uses it
+func isSnapshotInUse(err error) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == "InvalidSnapshot.InUse"
+}
+
+func (r *AccountCleanupReconciler) CleanUpAwsAccountVpcEndpointServiceConfigurations(reqLogger logr.Logger, awsClient awsclient.Client, awsNotifications chan string, awsErrors chan string) error {
 	describeVpcEndpointServiceConfigurationsInput := ec2.DescribeVpcEndpointServiceConfigurationsInput{}
 	vpcEndpointServiceConfigurations, err := awsClient.DescribeVpcEndpointServiceConfigurations(&describeVpcEndpointServiceConfigurationsInput)
 	if vpcEndpointServiceConfigurations == nil || err != nil {
 		descError := "Failed describing VPC endpoint service configurations"
+		reqLogger.Error(err, descError)
 		awsErrors <- descError
+		if err == nil {
+			err = errors.New(descError)
+		}
 		return err
 	}
 
@@ -288,6 +608,7 @@ func (r *AccountClaimReconciler) CleanUpAwsAccountVpcEndpointServiceConfiguratio
 
 	successMsg := "VPC endpoint service configuration cleanup finished successfully"
 	if len(serviceIds) == 0 {
+		reqLogger.Info(successMsg, "vpcEndpointServiceConfigurations", 0)
 		awsNotifications <- successMsg + " (nothing to do)"
 		return nil
 	}
@@ -306,25 +627,46 @@ func (r *AccountClaimReconciler) CleanUpAwsAccountVpcEndpointServiceConfiguratio
 			unsuccessfulList += *unsuccessfulEndpoint.ResourceId
 		}
 		delError := fmt.Sprintf("Failed deleting VPC endpoint service configurations: %s", unsuccessfulList)
+		reqLogger.Error(err, "Failed deleting VPC endpoint service configurations", "serviceIDs", unsuccessfulList)
 		awsErrors <- delError
+		if err == nil {
+			err = errors.New(delError)
+		}
 		return err
 	}
 
+	reqLogger.Info(successMsg, "vpcEndpointServiceConfigurations", len(serviceIds))
 	awsNotifications <- successMsg
 	return nil
 }
 
-func (r *AccountClaimReconciler) cleanUpAwsAccountEbsVolumes(reqLogger logr.Logger, awsClient awsclient.Client, awsNotifications chan string, awsErrors chan string) error {
+func (r *AccountCleanupReconciler) cleanUpAwsAccountEbsVolumes(reqLogger logr.Logger, awsClient awsclient.Client, awsNotifications chan string, awsErrors chan string) error {
 
 	describeVolumesInput := ec2.DescribeVolumesInput{}
 	ebsVolumes, err := awsClient.DescribeVolumes(&describeVolumesInput)
 	if err != nil {
 		descError := "Failed describing EBS volumes"
+		reqLogger.Error(err, descError)
 		awsErrors <- descError
 		return err
 	}
 
 	for _, volume := range ebsVolumes.Volumes {
+		switch aws.StringValue(volume.State) {
+		case ec2.VolumeStateDeleting, ec2.VolumeStateDeleted:
+			continue
+		case ec2.VolumeStateInUse:
+			leftToInstance, detachErr := detachEbsVolume(reqLogger, awsClient, volume)
+			if detachErr != nil {
+				detachError := fmt.Errorf("failed detaching EBS volume: %s: %w", *volume.VolumeId, detachErr).Error()
+				reqLogger.Error(detachErr, "Failed detaching EBS volume", "volumeID", *volume.VolumeId)
+				awsErrors <- detachError
+				return detachErr
+			}
+			if leftToInstance {
+				continue
+			}
+		}
 
 		deleteVolumeInput := ec2.DeleteVolumeInput{
 			VolumeId: aws.String(*volume.VolumeId),
@@ -333,7 +675,7 @@ func (r *AccountClaimReconciler) cleanUpAwsAccountEbsVolumes(reqLogger logr.Logg
 		_, err = awsClient.DeleteVolume(&deleteVolumeInput)
 		if err != nil {
 			delError := fmt.Errorf("failed deleting EBS volume: %s: %w", *volume.VolumeId, err).Error()
-			logger.Error(delError)
+			reqLogger.Error(err, "Failed deleting EBS volume", "volumeID", *volume.VolumeId)
 			awsErrors <- delError
 			return err
 		}
@@ -341,40 +683,105 @@ func (r *AccountClaimReconciler) cleanUpAwsAccountEbsVolumes(reqLogger logr.Logg
 	}
 
 	successMsg := "EBS Volume cleanup finished successfully"
+	reqLogger.Info(successMsg, "volumes", len(ebsVolumes.Volumes))
 	awsNotifications <- successMsg
 	return nil
 }
 
-func (r *AccountClaimReconciler) cleanUpAwsAccountS3(reqLogger logr.Logger, awsClient awsclient.Client, awsNotifications chan string, awsErrors chan string) error {
+// detachEbsVolume detaches an EBS volume from its instances so it can be deleted. The root volumes deleted on the
+// termination of their instance can't be detached from it while it runs, they're left to go away with the instance
+// and true is returned. Instances lingering in shutting-down don't let go of their volumes, so the volumes are
+// detached with force: their data is being deleted anyway.
+func detachEbsVolume(reqLogger logr.Logger, awsClient awsclient.Client, volume *ec2.Volume) (bool, error) {
+	var instanceIDs []*string
+	for _, attachment := range volume.Attachments {
+		instanceIDs = append(instanceIDs, attachment.InstanceId)
+	}
+	if len(instanceIDs) == 0 {
+		return false, nil
+	}
+	instances, err := awsClient.DescribeInstances(&ec2.DescribeInstancesInput{InstanceIds: instanceIDs})
+	if err != nil {
+		return false, err
+	}
+	rootDevices := map[string]string{}
+	for _, reservation := range instances.Reservations {
+		for _, instance := range reservation.Instances {
+			rootDevices[aws.StringValue(instance.InstanceId)] = aws.StringValue(instance.RootDeviceName)
+		}
+	}
+
+	for _, attachment := range volume.Attachments {
+		instanceID := aws.StringValue(attachment.InstanceId)
+		if aws.BoolValue(attachment.DeleteOnTermination) && aws.StringValue(attachment.Device) == rootDevices[instanceID] {
+			reqLogger.Info("Leaving the root EBS volume to be deleted with its instance", "volumeID", aws.StringValue(volume.VolumeId), "instanceID", instanceID)
+			return true, nil
+		}
+	}
+	for _, attachment := range volume.Attachments {
+		state := aws.StringValue(attachment.State)
+		if state == ec2.VolumeAttachmentStateDetaching || state == ec2.VolumeAttachmentStateDetached {
+			continue
+		}
+		reqLogger.Info("Detaching EBS volume", "volumeID", aws.StringValue(volume.VolumeId), "instanceID", aws.StringValue(attachment.InstanceId))
+		_, err = awsClient.DetachVolume(&ec2.DetachVolumeInput{
+			VolumeId:   volume.VolumeId,
+			InstanceId: attachment.InstanceId,
+			Force:      aws.Bool(true),
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	return false, waitForEbsVolumeDetached(reqLogger, awsClient, volume.VolumeId)
+}
+
+// waitForEbsVolumeDetached waits for a detached EBS volume to be available, up to ebsVolumeDetachAttempts checks
+func waitForEbsVolumeDetached(reqLogger logr.Logger, awsClient awsclient.Client, volumeID *string) error {
+	for attempt := 1; attempt <= ebsVolumeDetachAttempts; attempt++ {
+		time.Sleep(ebsVolumeDetachDelay)
+		output, err := awsClient.DescribeVolumes(&ec2.DescribeVolumesInput{VolumeIds: []*string{volumeID}})
+		if err != nil {
+			return err
+		}
+		if len(output.Volumes) > 0 && aws.StringValue(output.Volumes[0].State) == ec2.VolumeStateAvailable {
+			return nil
+		}
+		reqLogger.Info("Waiting for the EBS volume to be detached", "volumeID", aws.StringValue(volumeID), "attempt", attempt)
+	}
+	return fmt.Errorf("EBS volume %s is still attached after %d checks", aws.StringValue(volumeID), ebsVolumeDetachAttempts)
+}
+
+func (r *AccountCleanupReconciler) cleanUpAwsAccountS3(reqLogger logr.Logger, awsClient awsclient.Client, awsNotifications chan string, awsErrors chan string) error {
 	listBucketsInput := s3.ListBucketsInput{}
 	s3Buckets, err := awsClient.ListBuckets(&listBucketsInput)
 	if err != nil {
 		listError := fmt.Errorf("failed listing S3 buckets: %w", err).Error()
+		reqLogger.Error(err, "Failed listing S3 buckets")
 		awsErrors <- listError
 		return err
 	}
 
+	var lockedBuckets []string
 	for _, bucket := range s3Buckets.Buckets {
-
-		deleteBucketInput := s3.DeleteBucketInput{
-			Bucket: aws.String(*bucket.Name),
-		}
-
-		// delete any content if any
-		err := DeleteBucketContent(awsClient, *bucket.Name)
+		reason, err := unlockBucket(reqLogger, awsClient, *bucket.Name)
 		if err != nil {
-			ContentDelErr := fmt.Errorf("failed to delete bucket content: %s: %w", *bucket.Name, err).Error()
-			if aerr, ok := err.(awserr.Error); ok {
-				switch aerr.Code() {
-				case s3.ErrCodeNoSuchBucket:
-					//ignore these errors
-				default:
-					awsErrors <- ContentDelErr
-					return err
-				}
+			checkError := fmt.Errorf("failed checking the object lock and policy of S3 bucket: %s: %w", *bucket.Name, err).Error()
+			reqLogger.Error(err, "Failed checking the object lock and policy of S3 bucket", "bucket", *bucket.Name)
+			awsErrors <- checkError
+			return err
+		}
+		if reason != "" {
+			// The other buckets are still deleted, so the ones left to remove by hand are reported together
+			lockedBuckets = append(lockedBuckets, *bucket.Name)
+			reqLogger.Info("Leaving in place an S3 bucket that has to be deleted by hand", "bucket", *bucket.Name, "reason", reason)
+			if recorder, ok := awsClient.(keptResourceRecorder); ok {
+				recorder.keepResource("s3", *bucket.Name, reason)
 			}
+			continue
 		}
-		_, err = awsClient.DeleteBucket(&deleteBucketInput)
+
+		err = deleteBucket(reqLogger, awsClient, *bucket.Name)
 		if err != nil {
 			DelError := fmt.Errorf("failed deleting S3 bucket: %s: %w", *bucket.Name, err).Error()
 			if aerr, ok := err.(awserr.Error); ok {
@@ -382,6 +789,7 @@ func (r *AccountClaimReconciler) cleanUpAwsAccountS3(reqLogger logr.Logger, awsC
 				case s3.ErrCodeNoSuchBucket:
 					//ignore these errors
 				default:
+					reqLogger.Error(err, "Failed deleting S3 bucket", "bucket", *bucket.Name)
 					awsErrors <- DelError
 					return err
 				}
@@ -389,13 +797,20 @@ func (r *AccountClaimReconciler) cleanUpAwsAccountS3(reqLogger logr.Logger, awsC
 		}
 
 	}
+	if len(lockedBuckets) > 0 {
+		err = fmt.Errorf("%w: S3 buckets %s can't be deleted by the operator", awsv1alpha1.ErrManualActionRequired, strings.Join(lockedBuckets, ", "))
+		reqLogger.Error(err, "S3 buckets have to be deleted by hand")
+		awsErrors <- err.Error()
+		return err
+	}
 
 	successMsg := "S3 cleanup finished successfully"
+	reqLogger.Info(successMsg, "buckets", len(s3Buckets.Buckets))
 	awsNotifications <- successMsg
 	return nil
 }
 
-func (r *AccountClaimReconciler) cleanUpAwsRoute53(reqLogger logr.Logger, awsClient awsclient.Client, awsNotifications chan string, awsErrors chan string) error {
+func (r *AccountCleanupReconciler) cleanUpAwsRoute53(reqLogger logr.Logger, awsClient awsclient.Client, awsNotifications chan string, awsErrors chan string) error {
 
 	var nextZoneMarker *string
 
@@ -405,6 +820,7 @@ func (r *AccountClaimReconciler) cleanUpAwsRoute53(reqLogger logr.Logger, awsCli
 		hostedZonesOutput, err := awsClient.ListHostedZones(&route53.ListHostedZonesInput{Marker: nextZoneMarker})
 		if err != nil {
 			listError := fmt.Errorf("failed to list Hosted Zones: %w", err).Error()
+			reqLogger.Error(err, "Failed to list hosted zones")
 			awsErrors <- listError
 			return err
 		}
@@ -417,28 +833,31 @@ func (r *AccountClaimReconciler) cleanUpAwsRoute53(reqLogger logr.Logger, awsCli
 			for {
 				recordSet, listRecordsError := awsClient.ListResourceRecordSets(&route53.ListResourceRecordSetsInput{HostedZoneId: zone.Id, StartRecordName: nextRecordName})
 				if listRecordsError != nil {
-					recordSetListError := fmt.Errorf("failed to list Record sets for hosted zone %s: %w", *zone.Name, err).Error()
+					recordSetListError := fmt.Errorf("failed to list Record sets for hosted zone %s: %w", *zone.Name, listRecordsError).Error()
+					reqLogger.Error(listRecordsError, "Failed to list record sets", "hostedZone", *zone.Name)
 					awsErrors <- recordSetListError
 					return listRecordsError
 				}
 
-				changeBatch := &route53.ChangeBatch{}
+				var changes []*route53.Change
 				for _, record := range recordSet.ResourceRecordSets {
 					// Build ChangeBatch
 					// https://docs.aws.amazon.com/sdk-for-go/api/service/route53/#ChangeBatch
 					//https://docs.aws.amazon.com/sdk-for-go/api/service/route53/#Change
 					if *record.Type != "NS" && *record.Type != "SOA" {
-						changeBatch.Changes = append(changeBatch.Changes, &route53.Change{
+						changes = append(changes, &route53.Change{
 							Action:            aws.String("DELETE"),
 							ResourceRecordSet: record,
 						})
 					}
 				}
 
-				if changeBatch.Changes != nil {
-					_, changeErr := awsClient.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{HostedZoneId: zone.Id, ChangeBatch: changeBatch})
+				// A page of record sets can hold more records than a change batch can
+				for _, changeBatch := range route53ChangeBatches(changes) {
+					changeErr := changeRecordSets(reqLogger, awsClient, zone.Id, changeBatch.Changes)
 					if changeErr != nil {
-						recordDeleteError := fmt.Errorf("failed to delete record sets for hosted zone %s: %w", *zone.Name, err).Error()
+						recordDeleteError := fmt.Errorf("failed to delete record sets for hosted zone %s: %w", *zone.Name, changeErr).Error()
+						reqLogger.Error(changeErr, "Failed to delete record sets", "hostedZone", *zone.Name)
 						awsErrors <- recordDeleteError
 						return changeErr
 					}
@@ -453,7 +872,8 @@ func (r *AccountClaimReconciler) cleanUpAwsRoute53(reqLogger logr.Logger, awsCli
 
 			_, deleteError := awsClient.DeleteHostedZone(&route53.DeleteHostedZoneInput{Id: zone.Id})
 			if deleteError != nil {
-				zoneDelErr := fmt.Errorf("failed to delete hosted zone: %s: %w", *zone.Name, err).Error()
+				zoneDelErr := fmt.Errorf("failed to delete hosted zone: %s: %w", *zone.Name, deleteError).Error()
+				reqLogger.Error(deleteError, "Failed to delete hosted zone", "hostedZone", *zone.Name)
 				awsErrors <- zoneDelErr
 				return deleteError
 			}
@@ -467,34 +887,222 @@ func (r *AccountClaimReconciler) cleanUpAwsRoute53(reqLogger logr.Logger, awsCli
 	}
 
 	successMsg := "Route53 cleanup finished successfully"
+	reqLogger.Info(successMsg)
 	awsNotifications <- successMsg
 	return nil
 }
 
-// DeleteBucketContent deletes any content in a bucket if it is not empty
+// route53ChangeBatches splits the changes into change batches within the limits of Route53, which accepts at most
+// 1000 resource records and 32000 characters of record values per request. Alias record sets have no resource
+// records and count as one.
+func route53ChangeBatches(changes []*route53.Change) []*route53.ChangeBatch {
+	var batches []*route53.ChangeBatch
+	batch := &route53.ChangeBatch{}
+	records, valueChars := 0, 0
+	for _, change := range changes {
+		changeRecords := len(change.ResourceRecordSet.ResourceRecords)
+		if changeRecords == 0 {
+			changeRecords = 1
+		}
+		changeValueChars := 0
+		for _, record := range change.ResourceRecordSet.ResourceRecords {
+			changeValueChars += len(aws.StringValue(record.Value))
+		}
+
+		if len(batch.Changes) > 0 && (records+changeRecords > route53MaxBatchRecords || valueChars+changeValueChars > route53MaxBatchValueChars) {
+			batches = append(batches, batch)
+			batch = &route53.ChangeBatch{}
+			records, valueChars = 0, 0
+		}
+		batch.Changes = append(batch.Changes, change)
+		records += changeRecords
+		valueChars += changeValueChars
+	}
+	if len(batch.Changes) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// changeRecordSets submits the changes of a hosted zone as one change batch. Throttled batches, and batches sent while
+// a previous change of the zone is still being applied, are retried with a growing delay. Route53 rejects a whole
+// batch as invalid when one of its changes is, e.g. the deletion of a record set that's already gone: the batch is
+// split in two and each half is retried, until the invalid change is isolated. The deletion of a record set that's
+// already gone succeeds.
+func changeRecordSets(reqLogger logr.Logger, awsClient awsclient.Client, hostedZoneID *string, changes []*route53.Change) error {
+	var err error
+	for attempt := 1; attempt <= route53ChangeAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(attempt-1) * route53ChangeDelay)
+		}
+		_, err = awsClient.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+			HostedZoneId: hostedZoneID,
+			ChangeBatch:  &route53.ChangeBatch{Changes: changes},
+		})
+		var aerr awserr.Error
+		if !errors.As(err, &aerr) {
+			return err
+		}
+
+		switch {
+		case aerr.Code() == route53.ErrCodeInvalidChangeBatch && len(changes) > 1:
+			reqLogger.Info("Route53 rejected the change batch, splitting it", "hostedZoneID", aws.StringValue(hostedZoneID), "changes", len(changes))
+			half := len(changes) / 2
+			err = changeRecordSets(reqLogger, awsClient, hostedZoneID, changes[:half])
+			if err != nil {
+				return err
+			}
+			return changeRecordSets(reqLogger, awsClient, hostedZoneID, changes[half:])
+		case aerr.Code() == route53.ErrCodeInvalidChangeBatch && strings.Contains(aerr.Message(), "not found"):
+			reqLogger.Info("Record set already deleted", "hostedZoneID", aws.StringValue(hostedZoneID), "recordSet", aws.StringValue(changes[0].ResourceRecordSet.Name))
+			return nil
+		case aerr.Code() == route53.ErrCodePriorRequestNotComplete || awsv1alpha1.GetAWSErrorCategory(err) == awsv1alpha1.AWSErrorThrottled:
+			reqLogger.Info("Route53 change throttled, retrying", "hostedZoneID", aws.StringValue(hostedZoneID), "attempt", attempt)
+		default:
+			return err
+		}
+	}
+	return err
+}
+
+// cleanUpAwsAccountIAMUsers returns the cleanup step deleting the IAM users created for the claim, tagged with its UID
+func (r *AccountCleanupReconciler) cleanUpAwsAccountIAMUsers(claimUID string) func(logr.Logger, awsclient.Client, chan string, chan string) error {
+	return func(reqLogger logr.Logger, awsClient awsclient.Client, awsNotifications chan string, awsErrors chan string) error {
+		err := account.DeleteClaimIAMUsers(reqLogger, awsClient, claimUID)
+		if err != nil {
+			delError := fmt.Errorf("failed deleting the IAM users of the claim: %w", err).Error()
+			reqLogger.Error(err, "Failed deleting the IAM users of the claim")
+			awsErrors <- delError
+			return err
+		}
+
+		successMsg := "IAM user cleanup finished successfully"
+		reqLogger.Info(successMsg)
+		awsNotifications <- successMsg
+		return nil
+	}
+}
+
+// unlockBucket removes the bucket policy denying the deletion of the bucket or its content, and returns why the
+// bucket has to be deleted by hand, empty if it can be deleted. The objects of a bucket with object lock may be under
+// retention or a legal hold, and a policy the operator isn't allowed to remove can only be removed by the root user
+// of the account.
+func unlockBucket(reqLogger logr.Logger, awsClient awsclient.Client, bucketName string) (string, error) {
+	lock, err := awsClient.GetObjectLockConfiguration(&s3.GetObjectLockConfigurationInput{Bucket: aws.String(bucketName)})
+	switch {
+	case isObjectLockNotConfigured(err):
+	case awsv1alpha1.GetAWSErrorCategory(err) == awsv1alpha1.AWSErrorAccessDenied:
+		return "bucket policy denies the operator access to it", nil
+	case err != nil:
+		return "", err
+	case lock.ObjectLockConfiguration != nil && aws.StringValue(lock.ObjectLockConfiguration.ObjectLockEnabled) == s3.ObjectLockEnabledEnabled:
+		return "object lock enabled", nil
+	}
+
+	policy, err := awsClient.GetBucketPolicy(&s3.GetBucketPolicyInput{Bucket: aws.String(bucketName)})
+	if err != nil {
+		switch awsv1alpha1.GetAWSErrorCategory(err) {
+		case awsv1alpha1.AWSErrorNotFound:
+			return "", nil
+		case awsv1alpha1.AWSErrorAccessDenied:
+			return "bucket policy denies the operator access to it", nil
+		}
+		return "", err
+	}
+	deny, err := bucketPolicyDenies(aws.StringValue(policy.Policy))
+	if err != nil {
+		return "", fmt.Errorf("failed parsing the bucket policy: %w", err)
+	}
+	if !deny {
+		return "", nil
+	}
+
+	reqLogger.Info("Deleting the bucket policy denying access to the S3 bucket", "bucket", bucketName)
+	_, err = awsClient.DeleteBucketPolicy(&s3.DeleteBucketPolicyInput{Bucket: aws.String(bucketName)})
+	if awsv1alpha1.GetAWSErrorCategory(err) == awsv1alpha1.AWSErrorAccessDenied {
+		return "bucket policy denying access can't be removed by the operator", nil
+	}
+	return "", err
+}
+
+// isObjectLockNotConfigured returns true for the error of a bucket without object lock
+func isObjectLockNotConfigured(err error) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == "ObjectLockConfigurationNotFoundError"
+}
+
+// bucketPolicyDenies returns true if the bucket policy has a Deny statement. Which principals and actions it denies
+// isn't worked out, any Deny statement may keep the operator from deleting the bucket.
+func bucketPolicyDenies(policy string) (bool, error) {
+	if policy == "" {
+		return false, nil
+	}
+	var document struct {
+		Statement json.RawMessage
+	}
+	err := json.Unmarshal([]byte(policy), &document)
+	if err != nil {
+		return false, err
+	}
+	type statement struct {
+		Effect string
+	}
+	// The Statement of a policy is either a single statement or a list of them
+	var statements []statement
+	if err = json.Unmarshal(document.Statement, &statements); err != nil {
+		var single statement
+		if err = json.Unmarshal(document.Statement, &single); err != nil {
+			return false, err
+		}
+		statements = []statement{single}
+	}
+	for _, statement := range statements {
+		if strings.EqualFold(statement.Effect, "Deny") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// deleteBucket deletes the content of a bucket, then the bucket. Deleted objects can still be listed for a while, so
+// a bucket that isn't empty yet has its content deleted again, up to bucketNotEmptyAttempts times.
+func deleteBucket(reqLogger logr.Logger, awsClient awsclient.Client, bucketName string) error {
+	var err error
+	for attempt := 1; attempt <= bucketNotEmptyAttempts; attempt++ {
+		if attempt > 1 {
+			reqLogger.Info("S3 bucket isn't empty yet, deleting its content again", "bucket", bucketName, "attempt", attempt)
+			time.Sleep(time.Duration(attempt-1) * bucketNotEmptyDelay)
+		}
+		err = DeleteBucketContent(awsClient, bucketName)
+		if err != nil {
+			return err
+		}
+		_, err = awsClient.DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String(bucketName)})
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "BucketNotEmpty" {
+			return err
+		}
+	}
+	return err
+}
+
+// DeleteBucketContent deletes any content in a bucket if it is not empty, including the noncurrent versions and the
+// delete markers of a versioned bucket
 func DeleteBucketContent(awsClient awsclient.Client, bucketName string) error {
-	// check if objects exits
-	objects, err := awsClient.ListObjectsV2(&s3.ListObjectsV2Input{
-		Bucket: aws.String(bucketName),
+	// check if objects exits, the versions of an unversioned bucket are its objects
+	versions, err := awsClient.ListObjectVersions(&s3.ListObjectVersionsInput{
+		Bucket:  aws.String(bucketName),
+		MaxKeys: aws.Int64(1),
 	})
 	if err != nil {
 		return err
 	}
-	if len((*objects).Contents) == 0 {
+	if len(versions.Versions) == 0 && len(versions.DeleteMarkers) == 0 {
 		return nil
 	}
 
-	err = awsClient.BatchDeleteBucketObjects(aws.String(bucketName))
+	err = awsClient.BatchDeleteBucketObjectVersions(aws.String(bucketName))
 	if err != nil {
 		return err
 	}
 	return nil
 }
-
-func (r *AccountClaimReconciler) accountStatusUpdate(reqLogger logr.Logger, account *awsv1alpha1.Account) error {
-	err := r.Client.Status().Update(context.TODO(), account)
-	if err != nil {
-		reqLogger.Error(err, fmt.Sprintf("Status update for %s failed", account.Name))
-	}
-	return err
-}
@@ -46,7 +46,7 @@ func runCleanupFunc(functorun cleanupfunc, client awsclient.Client) (string, str
 
 var _ = Describe("Account Reuse", func() {
 	var (
-		r                *accountclaim.AccountClaimReconciler
+		r                *accountclaim.AccountCleanupReconciler
 		ctrl             *gomock.Controller
 		awsClientBuilder *awsmock.Builder
 		mockAwsClient    *awsmock.MockClient
@@ -59,7 +59,7 @@ var _ = Describe("Account Reuse", func() {
 		kubeClient = mock.NewMockClient(ctrl)
 
 		// Create the reconciler with a mocking AWS client IBuilder.
-		r = accountclaim.NewAccountClaimReconciler(
+		r = accountclaim.NewAccountCleanupReconciler(
 			kubeClient,
 			scheme.Scheme,
 			awsClientBuilder,
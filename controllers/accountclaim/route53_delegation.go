@@ -0,0 +1,136 @@
+package accountclaim
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/go-logr/logr"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+const route53DelegationStep = "route53_delegation"
+
+// route53DelegationRecordTypes are the types of the record sets delegating a subdomain, the DS records come with
+// DNSSEC
+var route53DelegationRecordTypes = []string{"NS", "DS"}
+
+// cleanUpRoute53Delegation returns the cleanup step removing the delegation of the subdomain of the cluster from the
+// parent hosted zone. The parent zone is shared with other clusters and lives in the DNS account, so the step works
+// with its own client rather than the one of the cleaned up account.
+func (r *AccountCleanupReconciler) cleanUpRoute53Delegation(ctx context.Context, delegation *awsv1alpha1.Route53Delegation, recorder *cleanupRecorder) func(logr.Logger, awsclient.Client, chan string, chan string) error {
+	return func(reqLogger logr.Logger, _ awsclient.Client, awsNotifications chan string, awsErrors chan string) error {
+		reqLogger = reqLogger.WithValues("parentHostedZoneID", delegation.ParentHostedZoneID, "subdomain", delegation.Subdomain)
+		dnsClient, err := r.route53DelegationClient(ctx, reqLogger, delegation)
+		if err != nil {
+			assumeError := fmt.Errorf("failed to assume role into the DNS account: %w", err).Error()
+			reqLogger.Error(err, "Failed to assume role into the DNS account", "roleARN", delegation.RoleARN)
+			awsErrors <- assumeError
+			return err
+		}
+
+		err = deleteRoute53Delegation(reqLogger, dnsClient, delegation, recorder)
+		if err != nil {
+			delError := fmt.Errorf("failed to remove the delegation of %s: %w", delegation.Subdomain, err).Error()
+			reqLogger.Error(err, "Failed to remove the Route53 delegation")
+			awsErrors <- delError
+			return err
+		}
+
+		successMsg := "Route53 delegation cleanup finished successfully"
+		reqLogger.Info(successMsg)
+		awsNotifications <- successMsg
+		return nil
+	}
+}
+
+// route53DelegationClient assumes the role of the delegation into the DNS account from the operator account
+func (r *AccountCleanupReconciler) route53DelegationClient(ctx context.Context, reqLogger logr.Logger, delegation *awsv1alpha1.Route53Delegation) (awsclient.Client, error) {
+	roleARN, err := arn.Parse(delegation.RoleARN)
+	if err != nil {
+		return nil, err
+	}
+	awsClientBuilder := awsclient.WithContext(ctx, r.awsClientBuilder)
+	awsSetupClient, err := awsClientBuilder.GetClient(cleanupControllerName, r.Client, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	creds, err := stsclient.GetSTSCredentials(reqLogger, awsSetupClient, delegation.RoleARN, delegation.ExternalID, "awsAccountOperator")
+	if err != nil {
+		return nil, err
+	}
+	return awsClientBuilder.GetClient(cleanupControllerName, r.Client, awsclient.NewAwsClientInput{
+		AwsCredsSecretIDKey:     aws.StringValue(creds.Credentials.AccessKeyId),
+		AwsCredsSecretAccessKey: aws.StringValue(creds.Credentials.SecretAccessKey),
+		AwsToken:                aws.StringValue(creds.Credentials.SessionToken),
+		AwsRegion:               config.GetDefaultRegion(),
+		AwsAccountID:            roleARN.AccountID,
+	})
+}
+
+// deleteRoute53Delegation deletes the NS and DS record sets of the subdomain from the parent hosted zone. A delegation
+// that's already gone is left alone, the other record sets of the parent zone are never touched.
+func deleteRoute53Delegation(reqLogger logr.Logger, awsClient awsclient.Client, delegation *awsv1alpha1.Route53Delegation, recorder *cleanupRecorder) error {
+	subdomain := normalizeRecordName(delegation.Subdomain)
+	if subdomain == "" {
+		return fmt.Errorf("no subdomain in the Route53 delegation of parent hosted zone %s", delegation.ParentHostedZoneID)
+	}
+
+	// The record sets are listed in order of their name, the ones of the subdomain come first
+	output, err := awsClient.ListResourceRecordSets(&route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(delegation.ParentHostedZoneID),
+		StartRecordName: aws.String(subdomain),
+	})
+	if err != nil {
+		return err
+	}
+	var changes []*route53.Change
+	for _, recordSet := range output.ResourceRecordSets {
+		if normalizeRecordName(aws.StringValue(recordSet.Name)) != subdomain {
+			break
+		}
+		if !utils.Contains(route53DelegationRecordTypes, aws.StringValue(recordSet.Type)) {
+			continue
+		}
+		changes = append(changes, &route53.Change{
+			Action:            aws.String("DELETE"),
+			ResourceRecordSet: recordSet,
+		})
+	}
+	recorder.record(route53DelegationStep, func(report *cleanupStepReport) { report.Found += len(changes) })
+	if len(changes) == 0 {
+		reqLogger.Info("The Route53 delegation is already removed")
+		return nil
+	}
+
+	err = changeRecordSets(reqLogger, awsClient, aws.String(delegation.ParentHostedZoneID), changes)
+	for _, change := range changes {
+		recorder.recordDeletion(route53DelegationStep, aws.StringValue(change.ResourceRecordSet.Type)+" "+subdomain, err != nil)
+	}
+	if err != nil {
+		return err
+	}
+	reqLogger.Info("Removed the Route53 delegation", "recordSets", len(changes))
+	return nil
+}
+
+// normalizeRecordName returns the DNS name in the form Route53 lists it, lower case and fully qualified
+func normalizeRecordName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
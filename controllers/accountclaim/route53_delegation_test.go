@@ -0,0 +1,80 @@
+package accountclaim
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Route53 delegation cleanup", func() {
+	const parentZoneID = "Z0123456789"
+
+	var (
+		ctrl          *gomock.Controller
+		mockAWSClient *mock.MockClient
+		recorder      *cleanupRecorder
+		delegation    *awsv1alpha1.Route53Delegation
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+		recorder = newCleanupRecorder(mockAWSClient, "us-east-1")
+		delegation = &awsv1alpha1.Route53Delegation{
+			ParentHostedZoneID: parentZoneID,
+			Subdomain:          "MyCluster.example.com",
+			RoleARN:            "arn:aws:iam::333333333333:role/dns-delegation",
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	recordSet := func(name, recordType string) *route53.ResourceRecordSet {
+		return &route53.ResourceRecordSet{Name: aws.String(name), Type: aws.String(recordType)}
+	}
+
+	It("Deletes the NS and DS record sets of the subdomain from the parent zone", func() {
+		ns := recordSet("mycluster.example.com.", "NS")
+		ds := recordSet("mycluster.example.com.", "DS")
+		mockAWSClient.EXPECT().ListResourceRecordSets(&route53.ListResourceRecordSetsInput{
+			HostedZoneId:    aws.String(parentZoneID),
+			StartRecordName: aws.String("mycluster.example.com."),
+		}).Return(&route53.ListResourceRecordSetsOutput{ResourceRecordSets: []*route53.ResourceRecordSet{
+			ns,
+			recordSet("mycluster.example.com.", "TXT"),
+			ds,
+			recordSet("othercluster.example.com.", "NS"),
+		}}, nil)
+		mockAWSClient.EXPECT().ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+			HostedZoneId: aws.String(parentZoneID),
+			ChangeBatch: &route53.ChangeBatch{Changes: []*route53.Change{
+				{Action: aws.String("DELETE"), ResourceRecordSet: ns},
+				{Action: aws.String("DELETE"), ResourceRecordSet: ds},
+			}},
+		}).Return(&route53.ChangeResourceRecordSetsOutput{}, nil)
+
+		Expect(deleteRoute53Delegation(testutils.NewTestLogger().Logger(), mockAWSClient, delegation, recorder)).To(Succeed())
+		reports := recorder.stepReports(nil)
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Region).To(Equal(globalRegion))
+		Expect(reports[0].Found).To(Equal(2))
+		Expect(reports[0].Deleted).To(Equal([]string{"NS mycluster.example.com.", "DS mycluster.example.com."}))
+	})
+
+	It("Skips a delegation already removed", func() {
+		mockAWSClient.EXPECT().ListResourceRecordSets(gomock.Any()).Return(&route53.ListResourceRecordSetsOutput{ResourceRecordSets: []*route53.ResourceRecordSet{
+			recordSet("othercluster.example.com.", "NS"),
+		}}, nil)
+
+		Expect(deleteRoute53Delegation(testutils.NewTestLogger().Logger(), mockAWSClient, delegation, recorder)).To(Succeed())
+		Expect(recorder.stepReports(nil)[0].Found).To(BeZero())
+	})
+})
@@ -0,0 +1,104 @@
+package accountclaim
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// secretCollectionInterval is how often orphaned operator secrets are looked for
+const secretCollectionInterval = time.Hour
+
+// SecretCollector periodically deletes the secrets the operator created for Accounts and
+// AccountClaims that no longer exist. Kubernetes only garbage collects the secrets in the namespace
+// of their owner, claim secrets in other namespaces are left behind when a claim is deleted abnormally.
+type SecretCollector struct {
+	client client.Client
+}
+
+// NewSecretCollector returns a new SecretCollector
+func NewSecretCollector(client client.Client) *SecretCollector {
+	return &SecretCollector{client: client}
+}
+
+// Start looks for orphaned secrets every secretCollectionInterval, until the operator is stopped
+func (c *SecretCollector) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the secret collector")
+	for {
+		_, err := c.CollectSecrets(log)
+		if err != nil {
+			log.Error(err, "Failed to collect orphaned secrets")
+		}
+
+		select {
+		case <-time.After(secretCollectionInterval):
+		case <-stopCh.Done():
+			log.Info("Stopping the secret collector")
+			return
+		}
+	}
+}
+
+// CollectSecrets deletes the operator secrets whose Account or AccountClaim doesn't exist anymore and
+// returns their names
+func (c *SecretCollector) CollectSecrets(log logr.Logger) ([]string, error) {
+	secrets := &corev1.SecretList{}
+	err := c.client.List(context.TODO(), secrets, client.MatchingLabels{awsv1alpha1.ManagedByLabel: awsv1alpha1.ManagedByOperator})
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		exists, err := c.secretOwnerExists(secret)
+		if err != nil {
+			log.Error(err, "Failed to get secret owner", "secret", secret.Name, "namespace", secret.Namespace)
+			continue
+		}
+		if exists {
+			continue
+		}
+
+		log.Info("Deleting orphaned secret", "secret", secret.Name, "namespace", secret.Namespace, "ownerKind", secret.Labels[awsv1alpha1.SecretOwnerKindLabel], "owner", secret.Annotations[awsv1alpha1.SecretOwnerNameAnnotation])
+		err = c.client.Delete(context.TODO(), secret)
+		if err != nil && !k8serr.IsNotFound(err) {
+			log.Error(err, "Failed to delete orphaned secret", "secret", secret.Name, "namespace", secret.Namespace)
+			continue
+		}
+		deleted = append(deleted, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}.String())
+	}
+	return deleted, nil
+}
+
+// secretOwnerExists checks whether the CR a secret was created for still exists. Secrets without a
+// known owner are treated as owned, so they're never deleted by mistake.
+func (c *SecretCollector) secretOwnerExists(secret *corev1.Secret) (bool, error) {
+	var owner client.Object
+	switch secret.Labels[awsv1alpha1.SecretOwnerKindLabel] {
+	case "Account":
+		owner = &awsv1alpha1.Account{}
+	case "AccountClaim":
+		owner = &awsv1alpha1.AccountClaim{}
+	case "IAMUserRequest":
+		owner = &awsv1alpha1.IAMUserRequest{}
+	default:
+		return true, nil
+	}
+
+	name := secret.Annotations[awsv1alpha1.SecretOwnerNameAnnotation]
+	if name == "" {
+		return true, nil
+	}
+	err := c.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: secret.Annotations[awsv1alpha1.SecretOwnerNamespaceAnnotation]}, owner)
+	if k8serr.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
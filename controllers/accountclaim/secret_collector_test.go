@@ -0,0 +1,68 @@
+package accountclaim
+
+import (
+	"context"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SecretCollector", func() {
+	var (
+		claim *awsv1alpha1.AccountClaim
+	)
+
+	BeforeEach(func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		claim = &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "claim-namespace"},
+		}
+	})
+
+	ownedSecret := func(name string, owner client.Object) *corev1.Secret {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "claim-namespace"}}
+		Expect(utils.SetSecretOwner(secret, owner, scheme.Scheme)).To(Succeed())
+		return secret
+	}
+
+	It("Deletes the secrets of claims that don't exist anymore", func() {
+		deletedClaim := &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "deleted-claim", Namespace: "claim-namespace"},
+		}
+		orphan := ownedSecret("orphan-secret", deletedClaim)
+		owned := ownedSecret("owned-secret", claim)
+		unmanaged := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged-secret", Namespace: "claim-namespace"}}
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(claim, orphan, owned, unmanaged).Build()
+
+		deleted, err := NewSecretCollector(kubeClient).CollectSecrets(testutils.NewTestLogger().Logger())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(ConsistOf("claim-namespace/orphan-secret"))
+
+		err = kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(orphan), &corev1.Secret{})
+		Expect(k8serr.IsNotFound(err)).To(BeTrue())
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(owned), &corev1.Secret{})).To(Succeed())
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(unmanaged), &corev1.Secret{})).To(Succeed())
+	})
+
+	It("Keeps managed secrets of an unknown owner kind", func() {
+		secret := ownedSecret("other-secret", claim)
+		secret.Labels[awsv1alpha1.SecretOwnerKindLabel] = "ConfigMap"
+		secret.Annotations[awsv1alpha1.SecretOwnerNameAnnotation] = "missing"
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+
+		deleted, err := NewSecretCollector(kubeClient).CollectSecrets(testutils.NewTestLogger().Logger())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(BeEmpty())
+	})
+})
@@ -0,0 +1,268 @@
+package accountclaim
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/controllers/account"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// provisionSTSRoles creates or updates the spec.stsRoles of a manualSTSMode claim in the customer
+// account, deletes the roles removed from the spec and records the provisioned roles in the status
+func (r *AccountClaimReconciler) provisionSTSRoles(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) error {
+	if len(accountClaim.Spec.STSRoles) == 0 && len(accountClaim.Status.STSRoles) == 0 {
+		return nil
+	}
+
+	awsClient, err := r.getSTSRolesClient(reqLogger, accountClaim)
+	if err != nil {
+		return err
+	}
+
+	provisioned := []awsv1alpha1.ProvisionedSTSRole{}
+	wanted := map[string]bool{}
+	for _, role := range accountClaim.Spec.STSRoles {
+		roleARN, err := ensureSTSRole(reqLogger, awsClient, accountClaim, role)
+		if err != nil {
+			reqLogger.Error(err, "Failed to provision STS role", "role", role.Name)
+			return err
+		}
+		provisioned = append(provisioned, awsv1alpha1.ProvisionedSTSRole{Name: role.Name, ARN: roleARN})
+		wanted[role.Name] = true
+	}
+
+	for _, role := range accountClaim.Status.STSRoles {
+		if wanted[role.Name] {
+			continue
+		}
+		err := deleteSTSRole(reqLogger, awsClient, role.Name)
+		if err != nil {
+			reqLogger.Error(err, "Failed to delete STS role", "role", role.Name)
+			return err
+		}
+	}
+
+	if len(provisioned) == 0 {
+		provisioned = nil
+	}
+	if reflect.DeepEqual(provisioned, accountClaim.Status.STSRoles) {
+		return nil
+	}
+	accountClaim.Status.STSRoles = provisioned
+	return r.statusUpdate(reqLogger, accountClaim)
+}
+
+// cleanUpSTSRoles deletes the roles provisioned for a manualSTSMode claim
+func (r *AccountClaimReconciler) cleanUpSTSRoles(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) error {
+	if len(accountClaim.Status.STSRoles) == 0 {
+		return nil
+	}
+
+	awsClient, err := r.getSTSRolesClient(reqLogger, accountClaim)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range accountClaim.Status.STSRoles {
+		err := deleteSTSRole(reqLogger, awsClient, role.Name)
+		if err != nil {
+			reqLogger.Error(err, "Failed to delete STS role", "role", role.Name)
+			return err
+		}
+	}
+	return nil
+}
+
+// getSTSRolesClient returns a client of the customer account of the claim
+func (r *AccountClaimReconciler) getSTSRolesClient(reqLogger logr.Logger, accountClaim *awsv1alpha1.AccountClaim) (awsclient.Client, error) {
+	awsSetupClient, err := r.awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
+		SecretName: controllerutils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		reqLogger.Error(err, "failed building operator AWS client")
+		return nil, err
+	}
+
+	awsClient, _, err := account.GetSTSClient(reqLogger, r.Client, r.awsClientBuilder, accountClaim, awsSetupClient)
+	if err != nil {
+		reqLogger.Error(err, "failed building AWS client of the STS account")
+		return nil, err
+	}
+	return awsClient, nil
+}
+
+// ensureSTSRole creates the role if it doesn't exist yet, otherwise brings its trust policy and attached
+// policies in line with the spec. Roles not tagged with the claim are left alone.
+func ensureSTSRole(reqLogger logr.Logger, awsClient awsclient.Client, accountClaim *awsv1alpha1.AccountClaim, role awsv1alpha1.STSRole) (string, error) {
+	trustPolicy, err := stsRoleTrustPolicy(role)
+	if err != nil {
+		return "", err
+	}
+
+	existingRole, err := account.GetExistingRole(reqLogger, role.Name, awsClient)
+	if err != nil {
+		return "", err
+	}
+
+	var roleARN string
+	if existingRole.Role == nil {
+		reqLogger.Info("Creating STS role", "role", role.Name)
+		createRoleOutput, err := awsClient.CreateRole(&iam.CreateRoleInput{
+			RoleName:                 aws.String(role.Name),
+			Description:              aws.String(fmt.Sprintf("Managed by AAO for AccountClaim %s/%s", accountClaim.Namespace, accountClaim.Name)),
+			AssumeRolePolicyDocument: aws.String(trustPolicy),
+			Tags:                     stsRoleTags(accountClaim),
+		})
+		if err != nil {
+			return "", err
+		}
+		roleARN = *createRoleOutput.Role.Arn
+	} else {
+		if !isSTSRoleOf(existingRole.Role, accountClaim) {
+			return "", fmt.Errorf("role %s already exists and wasn't provisioned for AccountClaim %s/%s", role.Name, accountClaim.Namespace, accountClaim.Name)
+		}
+		equal, err := account.PolicyDocumentsEqual(aws.StringValue(existingRole.Role.AssumeRolePolicyDocument), trustPolicy)
+		if err != nil || !equal {
+			reqLogger.Info("Updating STS role trust policy", "role", role.Name)
+			_, err = awsClient.UpdateAssumeRolePolicy(&iam.UpdateAssumeRolePolicyInput{
+				RoleName:       aws.String(role.Name),
+				PolicyDocument: aws.String(trustPolicy),
+			})
+			if err != nil {
+				return "", err
+			}
+		}
+		// Roles provisioned before the claim UID was tagged get the tag
+		if accountClaim.UID != "" && roleTags(existingRole.Role)[awsv1alpha1.ClaimUIDTagKey] == "" {
+			reqLogger.Info("Tagging STS role with the claim UID", "role", role.Name)
+			_, err = awsClient.TagRole(&iam.TagRoleInput{
+				RoleName: aws.String(role.Name),
+				Tags:     []*iam.Tag{{Key: aws.String(awsv1alpha1.ClaimUIDTagKey), Value: aws.String(string(accountClaim.UID))}},
+			})
+			if err != nil {
+				return "", err
+			}
+		}
+		roleARN = *existingRole.Role.Arn
+	}
+
+	attachedPolicies, err := account.GetAttachedPolicies(reqLogger, role.Name, awsClient)
+	if err != nil {
+		return "", err
+	}
+	attached := map[string]bool{}
+	for _, policy := range attachedPolicies.AttachedPolicies {
+		if !controllerutils.Contains(role.PolicyARNs, *policy.PolicyArn) {
+			err = account.DetachPolicyFromRole(reqLogger, policy, role.Name, awsClient)
+			if err != nil {
+				return "", err
+			}
+			continue
+		}
+		attached[*policy.PolicyArn] = true
+	}
+	for _, policyARN := range role.PolicyARNs {
+		if attached[policyARN] {
+			continue
+		}
+		reqLogger.Info("Attaching policy to STS role", "role", role.Name, "policy", policyARN)
+		_, err = awsClient.AttachRolePolicy(&iam.AttachRolePolicyInput{
+			RoleName:  aws.String(role.Name),
+			PolicyArn: aws.String(policyARN),
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return roleARN, nil
+}
+
+// deleteSTSRole detaches the policies of the role and deletes it, if it still exists
+func deleteSTSRole(reqLogger logr.Logger, awsClient awsclient.Client, roleName string) error {
+	existingRole, err := account.GetExistingRole(reqLogger, roleName, awsClient)
+	if err != nil {
+		return err
+	}
+	if existingRole.Role == nil {
+		return nil
+	}
+
+	attachedPolicies, err := account.GetAttachedPolicies(reqLogger, roleName, awsClient)
+	if err != nil {
+		return err
+	}
+	for _, policy := range attachedPolicies.AttachedPolicies {
+		err = account.DetachPolicyFromRole(reqLogger, policy, roleName, awsClient)
+		if err != nil {
+			return err
+		}
+	}
+	return account.DeleteRole(reqLogger, roleName, awsClient)
+}
+
+func stsRoleTrustPolicy(role awsv1alpha1.STSRole) (string, error) {
+	type awsStatement struct {
+		Effect    string                 `json:"Effect"`
+		Action    []string               `json:"Action"`
+		Principal *awsv1alpha1.Principal `json:"Principal,omitempty"`
+	}
+
+	trustPolicy := struct {
+		Version   string
+		Statement []awsStatement
+	}{
+		Version: "2012-10-17",
+		Statement: []awsStatement{{
+			Effect:    "Allow",
+			Action:    []string{"sts:AssumeRole"},
+			Principal: &awsv1alpha1.Principal{AWS: role.TrustedARNs},
+		}},
+	}
+	jsonTrustPolicy, err := json.Marshal(trustPolicy)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonTrustPolicy), nil
+}
+
+func stsRoleTags(accountClaim *awsv1alpha1.AccountClaim) []*iam.Tag {
+	tags := []*iam.Tag{
+		{Key: aws.String(awsv1alpha1.ClusterClaimLinkTagKey), Value: aws.String(accountClaim.Name)},
+		{Key: aws.String(awsv1alpha1.ClusterClaimLinkNamespaceTagKey), Value: aws.String(accountClaim.Namespace)},
+	}
+	if accountClaim.UID != "" {
+		tags = append(tags, &iam.Tag{Key: aws.String(awsv1alpha1.ClaimUIDTagKey), Value: aws.String(string(accountClaim.UID))})
+	}
+	return tags
+}
+
+// isSTSRoleOf returns true if the role is tagged with the claim, matched by claim UID if both have one, otherwise by
+// claim name and namespace
+func isSTSRoleOf(role *iam.Role, accountClaim *awsv1alpha1.AccountClaim) bool {
+	tags := roleTags(role)
+	if uid := tags[awsv1alpha1.ClaimUIDTagKey]; uid != "" && accountClaim.UID != "" {
+		return uid == string(accountClaim.UID)
+	}
+	return tags[awsv1alpha1.ClusterClaimLinkTagKey] == accountClaim.Name &&
+		tags[awsv1alpha1.ClusterClaimLinkNamespaceTagKey] == accountClaim.Namespace
+}
+
+// roleTags returns the tags of the role by key
+func roleTags(role *iam.Role) map[string]string {
+	tags := map[string]string{}
+	for _, tag := range role.Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tags
+}
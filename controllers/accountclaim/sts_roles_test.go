@@ -0,0 +1,166 @@
+package accountclaim
+
+import (
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("STS roles", func() {
+	var (
+		nullLogger    logr.Logger
+		ctrl          *gomock.Controller
+		mockAWSClient *mock.MockClient
+		claim         *awsv1alpha1.AccountClaim
+		role          awsv1alpha1.STSRole
+		roleARN       = "arn:aws:iam::123456789012:role/installer"
+		noSuchEntity  = awserr.New(iam.ErrCodeNoSuchEntityException, "role not found", nil)
+	)
+
+	BeforeEach(func() {
+		nullLogger = testutils.NewTestLogger().Logger()
+		ctrl = gomock.NewController(GinkgoT())
+		mockAWSClient = mock.NewMockClient(ctrl)
+		claim = &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "claim-namespace"},
+		}
+		role = awsv1alpha1.STSRole{
+			Name:        "installer",
+			TrustedARNs: []string{"arn:aws:iam::210987654321:role/ocm"},
+			PolicyARNs:  []string{"arn:aws:iam::aws:policy/ReadOnlyAccess"},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	existingRole := func(tags ...*iam.Tag) *iam.GetRoleOutput {
+		return &iam.GetRoleOutput{Role: &iam.Role{RoleName: aws.String(role.Name), Arn: aws.String(roleARN), Tags: tags}}
+	}
+
+	It("Creates a missing role tagged with the claim and attaches its policies", func() {
+		mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(nil, noSuchEntity)
+		mockAWSClient.EXPECT().CreateRole(gomock.Any()).DoAndReturn(func(input *iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+			Expect(aws.StringValue(input.RoleName)).To(Equal("installer"))
+			Expect(aws.StringValue(input.AssumeRolePolicyDocument)).To(Equal(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["sts:AssumeRole"],"Principal":{"AWS":["arn:aws:iam::210987654321:role/ocm"]}}]}`))
+			Expect(input.Tags).To(Equal(stsRoleTags(claim)))
+			return &iam.CreateRoleOutput{Role: &iam.Role{Arn: aws.String(roleARN)}}, nil
+		})
+		mockAWSClient.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{}, nil)
+		mockAWSClient.EXPECT().AttachRolePolicy(&iam.AttachRolePolicyInput{
+			RoleName:  aws.String("installer"),
+			PolicyArn: aws.String("arn:aws:iam::aws:policy/ReadOnlyAccess"),
+		}).Return(&iam.AttachRolePolicyOutput{}, nil)
+
+		arn, err := ensureSTSRole(nullLogger, mockAWSClient, claim, role)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(arn).To(Equal(roleARN))
+	})
+
+	It("Updates an existing role and detaches the policies removed from the spec", func() {
+		mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(existingRole(stsRoleTags(claim)...), nil)
+		mockAWSClient.EXPECT().UpdateAssumeRolePolicy(gomock.Any()).Return(&iam.UpdateAssumeRolePolicyOutput{}, nil)
+		mockAWSClient.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{
+			AttachedPolicies: []*iam.AttachedPolicy{
+				{PolicyName: aws.String("ReadOnlyAccess"), PolicyArn: aws.String("arn:aws:iam::aws:policy/ReadOnlyAccess")},
+				{PolicyName: aws.String("AdministratorAccess"), PolicyArn: aws.String("arn:aws:iam::aws:policy/AdministratorAccess")},
+			},
+		}, nil)
+		mockAWSClient.EXPECT().DetachRolePolicy(&iam.DetachRolePolicyInput{
+			RoleName:  aws.String("installer"),
+			PolicyArn: aws.String("arn:aws:iam::aws:policy/AdministratorAccess"),
+		}).Return(&iam.DetachRolePolicyOutput{}, nil)
+
+		arn, err := ensureSTSRole(nullLogger, mockAWSClient, claim, role)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(arn).To(Equal(roleARN))
+	})
+
+	It("Leaves an existing role matching the spec alone", func() {
+		claim.UID = "claim-uid"
+		document := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":"arn:aws:iam::210987654321:role/ocm"}}]}`
+		current := existingRole(stsRoleTags(claim)...)
+		current.Role.AssumeRolePolicyDocument = aws.String(url.QueryEscape(document))
+		mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(current, nil)
+		mockAWSClient.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{
+			AttachedPolicies: []*iam.AttachedPolicy{
+				{PolicyName: aws.String("ReadOnlyAccess"), PolicyArn: aws.String("arn:aws:iam::aws:policy/ReadOnlyAccess")},
+			},
+		}, nil)
+
+		arn, err := ensureSTSRole(nullLogger, mockAWSClient, claim, role)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(arn).To(Equal(roleARN))
+	})
+
+	It("Tags the role with the claim UID", func() {
+		claim.UID = "claim-uid"
+		Expect(stsRoleTags(claim)).To(ContainElement(&iam.Tag{Key: aws.String(awsv1alpha1.ClaimUIDTagKey), Value: aws.String("claim-uid")}))
+
+		// A role provisioned before the claim UID was tagged
+		mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(existingRole(stsRoleTags(claim)[:2]...), nil)
+		mockAWSClient.EXPECT().UpdateAssumeRolePolicy(gomock.Any()).Return(&iam.UpdateAssumeRolePolicyOutput{}, nil)
+		mockAWSClient.EXPECT().TagRole(&iam.TagRoleInput{
+			RoleName: aws.String("installer"),
+			Tags:     []*iam.Tag{{Key: aws.String(awsv1alpha1.ClaimUIDTagKey), Value: aws.String("claim-uid")}},
+		}).Return(&iam.TagRoleOutput{}, nil)
+		mockAWSClient.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{
+			AttachedPolicies: []*iam.AttachedPolicy{
+				{PolicyName: aws.String("ReadOnlyAccess"), PolicyArn: aws.String("arn:aws:iam::aws:policy/ReadOnlyAccess")},
+			},
+		}, nil)
+
+		_, err := ensureSTSRole(nullLogger, mockAWSClient, claim, role)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Refuses the role of a former claim of the same name", func() {
+		previous := claim.DeepCopy()
+		previous.UID = "previous-claim-uid"
+		claim.UID = "claim-uid"
+		mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(existingRole(stsRoleTags(previous)...), nil)
+
+		_, err := ensureSTSRole(nullLogger, mockAWSClient, claim, role)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Refuses to take over a role it didn't provision", func() {
+		mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(existingRole(), nil)
+
+		_, err := ensureSTSRole(nullLogger, mockAWSClient, claim, role)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Detaches the policies of a role before deleting it", func() {
+		mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(existingRole(stsRoleTags(claim)...), nil)
+		mockAWSClient.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{
+			AttachedPolicies: []*iam.AttachedPolicy{
+				{PolicyName: aws.String("ReadOnlyAccess"), PolicyArn: aws.String("arn:aws:iam::aws:policy/ReadOnlyAccess")},
+			},
+		}, nil)
+		gomock.InOrder(
+			mockAWSClient.EXPECT().DetachRolePolicy(gomock.Any()).Return(&iam.DetachRolePolicyOutput{}, nil),
+			mockAWSClient.EXPECT().DeleteRole(&iam.DeleteRoleInput{RoleName: aws.String("installer")}).Return(&iam.DeleteRoleOutput{}, nil),
+		)
+
+		Expect(deleteSTSRole(nullLogger, mockAWSClient, "installer")).To(Succeed())
+	})
+
+	It("Ignores roles that are already gone", func() {
+		mockAWSClient.EXPECT().GetRole(gomock.Any()).Return(nil, noSuchEntity)
+
+		Expect(deleteSTSRole(nullLogger, mockAWSClient, "installer")).To(Succeed())
+	})
+})
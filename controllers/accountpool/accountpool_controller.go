@@ -3,20 +3,28 @@ package accountpool
 import (
 	"context"
 	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
 	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
 	"github.com/openshift/aws-account-operator/config"
 	"github.com/openshift/aws-account-operator/controllers/account"
+	"github.com/openshift/aws-account-operator/pkg/notifier"
 	"github.com/openshift/aws-account-operator/pkg/totalaccountwatcher"
 	"github.com/openshift/aws-account-operator/pkg/utils"
 )
@@ -32,11 +40,13 @@ type AccountPoolReconciler struct {
 	client.Client
 	Scheme         *runtime.Scheme
 	accountWatcher totalaccountwatcher.AccountWatcherIface
+	recorder       record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=accountpools,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=accountpools/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=accountpools/finalizers,verbs=update
+//+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=accountpoolpolicies,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a AccountPool object and makes changes based on the state read
 // and what is in the AccountPool.Spec
@@ -59,31 +69,56 @@ func (r *AccountPoolReconciler) Reconcile(ctx context.Context, request ctrl.Requ
 		return reconcile.Result{}, err
 	}
 
+	// The AccountPoolPolicies of the pool may target another size than its spec at this time
+	target, err := r.getPoolTarget(reqLogger, currentAccountPool, time.Now())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	// The target changes when a window starts or ends
+	result := reconcile.Result{RequeueAfter: target.nextChange}
+
 	// Calculate unclaimed accounts vs claimed accounts
 	calculatedStatus, err := r.calculateAccountPoolStatus(reqLogger, currentAccountPool.Name)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 	// Update the pool size after we calculate all other values
-	calculatedStatus.PoolSize = currentAccountPool.Spec.PoolSize
+	calculatedStatus.PoolSize = target.poolSize
+	calculatedStatus.ActivePolicy = target.activePolicy
 
 	if shouldUpdateAccountPoolStatus(currentAccountPool, calculatedStatus) {
+		// Only the transition is notified, not every reconcile of an empty pool
+		exhausted := currentAccountPool.Status.UnclaimedAccounts > 0 && calculatedStatus.UnclaimedAccounts == 0 && target.poolSize > 0
 		currentAccountPool.Status = calculatedStatus
 		err = r.Client.Status().Update(context.TODO(), currentAccountPool)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
+		if exhausted {
+			reqLogger.Info("AccountPool has no unclaimed account left")
+			notifier.Notify(reqLogger, notifier.ForAccountPool(notifier.KindPoolExhausted, currentAccountPool,
+				fmt.Sprintf("AccountPool %s has no unclaimed account left", currentAccountPool.Name),
+				fmt.Sprintf("%d claimed accounts, the pool targets %d unclaimed accounts. Claims wait until accounts are created or cleaned up.", calculatedStatus.ClaimedAccounts, target.poolSize)))
+		}
 	}
 
 	// Get the number of desired unclaimed AWS accounts in the pool
-	poolSizeCount := currentAccountPool.Spec.PoolSize
+	poolSizeCount := target.poolSize
 	unclaimedAccountCount := calculatedStatus.UnclaimedAccounts
 
 	reqLogger.Info(fmt.Sprintf("AccountPool Calculations Completed: %+v", calculatedStatus))
 
 	if unclaimedAccountCount >= poolSizeCount {
 		reqLogger.Info(fmt.Sprintf("unclaimed account pool satisfied, unclaimedAccounts %d >= poolSize %d", unclaimedAccountCount, poolSizeCount))
-		return reconcile.Result{}, nil
+		return result, nil
+	}
+
+	if utils.IsAWSMutationPaused(r.Client) {
+		reqLogger.Info("AWS changes are paused, not creating accounts for the pool")
+		if utils.IsObserveOnly() {
+			utils.RecordEvent(r.recorder, currentAccountPool, corev1.EventTypeNormal, utils.EventReasonPlannedAction, "Observe-only mode, would have created an account to fill the pool")
+		}
+		return reconcile.Result{RequeueAfter: utils.MaintenanceModeRequeueDelay}, nil
 	}
 
 	// Create Account CR
@@ -105,8 +140,9 @@ func (r *AccountPoolReconciler) Reconcile(ctx context.Context, request ctrl.Requ
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	utils.RecordEvent(r.recorder, currentAccountPool, corev1.EventTypeNormal, utils.EventReasonAccountCreated, "Created account %s to fill the pool", newAccount.Name)
 
-	return reconcile.Result{}, nil
+	return result, nil
 }
 
 func (r *AccountPoolReconciler) handleServiceQuotas(reqLogger logr.Logger, account *awsv1alpha1.Account) error {
@@ -145,6 +181,11 @@ func (r *AccountPoolReconciler) calculateAccountPoolStatus(reqLogger logr.Logger
 			continue
 		}
 
+		// Accounts created on demand for a claim only join the pool once they're reused
+		if account.IsCreatedOnDemand() && account.HasNeverBeenClaimed() {
+			continue
+		}
+
 		// Special intermediary case until all account crs have had their account.Spec.AccountPool set appropriately.
 		// If account.Spec.AccountPool is empty, we count it as if it's from the default accountpool.
 		if account.Spec.AccountPool == "" {
@@ -214,6 +255,7 @@ func shouldUpdateAccountPoolStatus(currentAccountPool *awsv1alpha1.AccountPool,
 // SetupWithManager sets up the controller with the Manager.
 func (r *AccountPoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.accountWatcher = totalaccountwatcher.TotalAccountWatcher
+	r.recorder = mgr.GetEventRecorderFor(controllerName)
 	maxReconciles, err := utils.GetControllerMaxReconciles(controllerName)
 	if err != nil {
 		log.Error(err, "missing max reconciles for controller", "controller", controllerName)
@@ -221,8 +263,9 @@ func (r *AccountPoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	rwm := utils.NewReconcilerWithMetrics(r, controllerName)
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&awsv1alpha1.AccountPool{}).
-		Owns(&awsv1alpha1.Account{}).
+		For(&awsv1alpha1.AccountPool{}, builder.WithPredicates(utils.SpecOrMetadataChanged)).
+		Owns(&awsv1alpha1.Account{}, builder.WithPredicates(utils.IgnoreReconcileOutcomeUpdates)).
+		Watches(&source.Kind{Type: &awsv1alpha1.AccountPoolPolicy{}}, handler.EnqueueRequestsFromMapFunc(accountPoolForPolicy)).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxReconciles,
 		}).Complete(rwm)
@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -109,6 +110,7 @@ func TestReconcileAccountPool(t *testing.T) {
 		verifyAccountFunction func(client.Client, *awsv1alpha1.AccountPool) bool
 		expectedAWSCount      int
 		expectedLimit         int
+		expectedEvents        int
 	}{
 		{
 			name: "Account count >= Pool Size",
@@ -181,6 +183,54 @@ func TestReconcileAccountPool(t *testing.T) {
 			},
 			expectedAWSCount:      1,
 			expectedLimit:         1,
+			expectedEvents:        1,
+			verifyAccountFunction: verifyAccountCreated,
+		},
+		{
+			name: "Active AccountPoolPolicy window raises the pool size",
+			localObjects: []runtime.Object{
+				&awsv1alpha1.AccountPool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test",
+						Namespace: "aws-account-operator",
+					},
+					Spec: awsv1alpha1.AccountPoolSpec{
+						PoolSize: 1,
+					},
+				},
+				&awsv1alpha1.AccountPoolPolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "peak",
+						Namespace: "aws-account-operator",
+					},
+					Spec: awsv1alpha1.AccountPoolPolicySpec{
+						AccountPool: "test",
+						// A window ending at its start lasts the whole day
+						Windows: []awsv1alpha1.PoolSizeWindow{{Start: "00:00", End: "00:00", PoolSize: 3}},
+					},
+				},
+				configmap,
+				createAccountMock("account1", "Ready", unclaimed),
+				createAccountMock("account2", "Ready", unclaimed),
+			},
+			expectedAccountPool: awsv1alpha1.AccountPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "aws-account-operator",
+				},
+				Spec: awsv1alpha1.AccountPoolSpec{
+					PoolSize: 1,
+				},
+				Status: awsv1alpha1.AccountPoolStatus{
+					PoolSize:          3,
+					UnclaimedAccounts: 3,
+					AvailableAccounts: 2,
+					ActivePolicy:      "peak",
+				},
+			},
+			expectedAWSCount:      2,
+			expectedLimit:         2,
+			expectedEvents:        1,
 			verifyAccountFunction: verifyAccountCreated,
 		},
 		{
@@ -233,6 +283,7 @@ func TestReconcileAccountPool(t *testing.T) {
 			// after mocks is defined
 			defer mocks.mockCtrl.Finish()
 
+			recorder := record.NewFakeRecorder(10)
 			rap := &AccountPoolReconciler{
 				Client: mocks.fakeKubeClient,
 				Scheme: scheme.Scheme,
@@ -240,6 +291,7 @@ func TestReconcileAccountPool(t *testing.T) {
 					accounts: test.expectedAWSCount,
 					limit:    test.expectedLimit,
 				},
+				recorder: recorder,
 			}
 
 			ap := awsv1alpha1.AccountPool{}
@@ -264,6 +316,7 @@ func TestReconcileAccountPool(t *testing.T) {
 
 			assert.NoError(t, err, "Unexpected Error")
 			assert.True(t, test.verifyAccountFunction(mocks.fakeKubeClient, &test.expectedAccountPool)) // #nosec G601
+			assert.Len(t, recorder.Events, test.expectedEvents)
 		})
 	}
 }
@@ -0,0 +1,166 @@
+package accountpool
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+)
+
+const day = 24 * time.Hour
+
+// poolTarget is the pool size an AccountPool targets at a point in time
+type poolTarget struct {
+	poolSize int
+	// activePolicy is the AccountPoolPolicy whose window sets the pool size, empty for the spec of the pool
+	activePolicy string
+	// nextChange is how long until a window of the policies of the pool starts or ends, 0 without windows
+	nextChange time.Duration
+}
+
+// getPoolTarget returns the pool size targeted by the AccountPool at the given time: the largest pool size of the
+// active windows of its AccountPoolPolicies, spec.poolSize when none is active
+func (r *AccountPoolReconciler) getPoolTarget(reqLogger logr.Logger, accountPool *awsv1alpha1.AccountPool, now time.Time) (poolTarget, error) {
+	target := poolTarget{poolSize: accountPool.Spec.PoolSize}
+
+	policies := &awsv1alpha1.AccountPoolPolicyList{}
+	if err := r.Client.List(context.TODO(), policies, client.InNamespace(accountPool.Namespace)); err != nil {
+		return target, err
+	}
+
+	active := false
+	for _, policy := range policies.Items {
+		if policy.Spec.AccountPool != accountPool.Name {
+			continue
+		}
+		for _, window := range policy.Spec.Windows {
+			start, end, err := parseWindow(window)
+			if err != nil {
+				reqLogger.Error(err, "Ignoring invalid AccountPoolPolicy window", "policy", policy.Name)
+				continue
+			}
+			if next := nextWindowChange(start, end, now); target.nextChange == 0 || next < target.nextChange {
+				target.nextChange = next
+			}
+			if !windowActive(window.Days, start, end, now) {
+				continue
+			}
+			if !active || window.PoolSize > target.poolSize {
+				target.poolSize = window.PoolSize
+				target.activePolicy = policy.Name
+			}
+			active = true
+		}
+	}
+	return target, nil
+}
+
+// parseWindow returns the start and end of a window as offsets from midnight UTC
+func parseWindow(window awsv1alpha1.PoolSizeWindow) (time.Duration, time.Duration, error) {
+	start, err := parseTimeOfDay(window.Start)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseTimeOfDay(window.End)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, weekday := range window.Days {
+		if _, ok := weekdays[weekday]; !ok {
+			return 0, 0, fmt.Errorf("invalid day %q, expected one of Mon, Tue, Wed, Thu, Fri, Sat or Sun", weekday)
+		}
+	}
+	return start, end, nil
+}
+
+// weekdays are the days of a window, by their abbreviation
+var weekdays = map[string]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// parseTimeOfDay parses an HH:MM time of day into an offset from midnight
+func parseTimeOfDay(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", value)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+// windowActive returns true if a window starting on one of the days is active at the given time. A window ending
+// before its start runs past midnight, so the one of the day before may still be active.
+func windowActive(days []string, start, end time.Duration, now time.Time) bool {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	sinceMidnight := now.Sub(midnight)
+
+	length := end - start
+	if length <= 0 {
+		length += day
+	}
+	// The windows of today and of yesterday are the only ones that can include now
+	for _, windowDay := range []time.Time{midnight, midnight.Add(-day)} {
+		if !startsOn(days, windowDay.Weekday()) {
+			continue
+		}
+		offset := sinceMidnight + midnight.Sub(windowDay)
+		if offset >= start && offset < start+length {
+			return true
+		}
+	}
+	return false
+}
+
+// startsOn returns true if a window of the days starts on the weekday
+func startsOn(days []string, weekday time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, d := range days {
+		if weekdays[d] == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// nextWindowChange returns how long until the next start or end time of day of a window. The days of the window
+// aren't considered, reconciling on a day the window doesn't start changes nothing.
+func nextWindowChange(start, end time.Duration, now time.Time) time.Duration {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	sinceMidnight := now.Sub(midnight)
+
+	next := day
+	for _, change := range []time.Duration{start, end} {
+		until := change - sinceMidnight
+		if until <= 0 {
+			until += day
+		}
+		if until < next {
+			next = until
+		}
+	}
+	return next
+}
+
+// accountPoolForPolicy returns the request of the AccountPool sized by a policy
+func accountPoolForPolicy(object client.Object) []reconcile.Request {
+	policy, ok := object.(*awsv1alpha1.AccountPoolPolicy)
+	if !ok || policy.Spec.AccountPool == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: policy.Spec.AccountPool, Namespace: policy.Namespace}}}
+}
@@ -0,0 +1,57 @@
+package accountpool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowActive(t *testing.T) {
+	// Wednesday
+	wednesday := time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		days       []string
+		start, end string
+		now        time.Time
+		expected   bool
+	}{
+		{name: "inside a daily window", start: "08:00", end: "20:00", now: wednesday.Add(12 * time.Hour), expected: true},
+		{name: "at the start of a window", start: "08:00", end: "20:00", now: wednesday.Add(8 * time.Hour), expected: true},
+		{name: "at the end of a window", start: "08:00", end: "20:00", now: wednesday.Add(20 * time.Hour)},
+		{name: "before a window", start: "08:00", end: "20:00", now: wednesday.Add(7 * time.Hour)},
+		{name: "on a day of the window", days: []string{"Mon", "Wed"}, start: "08:00", end: "20:00", now: wednesday.Add(12 * time.Hour), expected: true},
+		{name: "on another day", days: []string{"Sat", "Sun"}, start: "08:00", end: "20:00", now: wednesday.Add(12 * time.Hour)},
+		{name: "overnight window after its start", days: []string{"Wed"}, start: "22:00", end: "06:00", now: wednesday.Add(23 * time.Hour), expected: true},
+		{name: "overnight window the next morning", days: []string{"Tue"}, start: "22:00", end: "06:00", now: wednesday.Add(2 * time.Hour), expected: true},
+		{name: "overnight window not started the day before", days: []string{"Wed"}, start: "22:00", end: "06:00", now: wednesday.Add(2 * time.Hour)},
+		{name: "whole day window", days: []string{"Wed"}, start: "00:00", end: "00:00", now: wednesday.Add(23*time.Hour + 59*time.Minute), expected: true},
+	}
+	for _, test := range tests {
+		start, err := parseTimeOfDay(test.start)
+		assert.NoError(t, err)
+		end, err := parseTimeOfDay(test.end)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, windowActive(test.days, start, end, test.now), test.name)
+	}
+}
+
+func TestNextWindowChange(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 12, 0, 0, 0, time.UTC)
+
+	// The window ends today at 20:00
+	assert.Equal(t, 8*time.Hour, nextWindowChange(8*time.Hour, 20*time.Hour, now))
+	// The window starts again tomorrow at 08:00
+	assert.Equal(t, 20*time.Hour, nextWindowChange(8*time.Hour, 12*time.Hour, now))
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	offset, err := parseTimeOfDay("20:30")
+	assert.NoError(t, err)
+	assert.Equal(t, 20*time.Hour+30*time.Minute, offset)
+
+	_, err = parseTimeOfDay("8pm")
+	assert.Error(t, err)
+}
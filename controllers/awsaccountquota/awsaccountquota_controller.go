@@ -0,0 +1,231 @@
+package awsaccountquota
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+const (
+	controllerName = "awsaccountquota"
+
+	// defaultSnapshotInterval is how often the quotas are snapshotted unless the configmap says otherwise.
+	// An interval of 0 disables the snapshots.
+	defaultSnapshotInterval = 6 * time.Hour
+	// defaultExhaustionThreshold is the usage, as a percentage of a quota, from which an account is nearly
+	// exhausted unless the configmap says otherwise
+	defaultExhaustionThreshold = 80
+)
+
+var log = logf.Log.WithName("controller_awsaccountquota")
+
+// AWSAccountQuotaReconciler snapshots the service quotas of the Ready non-CCS Accounts and their usage in
+// AWSAccountQuotas
+type AWSAccountQuotaReconciler struct {
+	client.Client
+	Scheme           *runtime.Scheme
+	awsClientBuilder awsclient.IBuilder
+}
+
+//+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=awsaccountquotas,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=awsaccountquotas/status,verbs=get;update;patch
+
+// NewAWSAccountQuotaReconciler initializes AWSAccountQuotaReconciler
+func NewAWSAccountQuotaReconciler(client client.Client, scheme *runtime.Scheme, awsClientBuilder awsclient.IBuilder) *AWSAccountQuotaReconciler {
+	return &AWSAccountQuotaReconciler{
+		Client:           client,
+		Scheme:           scheme,
+		awsClientBuilder: awsClientBuilder,
+	}
+}
+
+// Reconcile snapshots the service quotas of an Account and their usage in its AWSAccountQuota, once per snapshot
+// interval. The AWSAccountQuota is named after its Account, so requests for either are handled the same.
+func (r *AWSAccountQuotaReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.WithValues("Controller", controllerName, "Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	// The quotas of the accounts of other shards are snapshotted by other replicas
+	if !utils.InShard(request.Name) {
+		return reconcile.Result{}, nil
+	}
+
+	interval := getSnapshotInterval(reqLogger, r.Client)
+	if interval == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	account := &awsv1alpha1.Account{}
+	err := r.Client.Get(ctx, request.NamespacedName, account)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			// The AWSAccountQuota is garbage collected with its Account
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// CCS accounts belong to the customer, their quotas are checked when their claim is validated
+	if !account.HasAwsAccountID() || account.IsBYOC() || account.IsPendingDeletion() || !account.IsReady() {
+		return reconcile.Result{}, nil
+	}
+	reqLogger = reqLogger.WithValues("awsAccountID", account.Spec.AwsAccountID)
+
+	quota, err := r.getOrCreateAccountQuota(ctx, reqLogger, account)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if quota.Status.LastSnapshotTime != nil {
+		if next := time.Until(quota.Status.LastSnapshotTime.Add(interval)); next > 0 {
+			return reconcile.Result{RequeueAfter: next}, nil
+		}
+	}
+
+	quotas, snapshotErr := r.snapshotQuotas(ctx, reqLogger, account, quota.Spec.Regions)
+	if snapshotErr != nil {
+		reqLogger.Error(snapshotErr, "Failed to snapshot the service quotas")
+		err = utils.UpdateStatusWithRetry(r.Client, quota, func() error {
+			quota.Status.LastError = snapshotErr.Error()
+			return nil
+		})
+		if err != nil {
+			reqLogger.Error(err, "Failed to update AWSAccountQuota status")
+		}
+		return reconcile.Result{}, snapshotErr
+	}
+
+	threshold := getExhaustionThreshold(reqLogger, r.Client)
+	err = utils.UpdateStatusWithRetry(r.Client, quota, func() error {
+		now := metav1.Now()
+		quota.Status.Quotas = quotas
+		quota.Status.NearlyExhausted = isNearlyExhausted(quotas, threshold)
+		quota.Status.LastSnapshotTime = &now
+		quota.Status.LastError = ""
+		return nil
+	})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if quota.Status.NearlyExhausted {
+		reqLogger.Info("Account service quotas nearly exhausted", "threshold", threshold)
+	}
+	return reconcile.Result{RequeueAfter: interval}, nil
+}
+
+// getOrCreateAccountQuota returns the AWSAccountQuota of the account, creating it if needed. It's owned by the
+// Account, so it's deleted with it.
+func (r *AWSAccountQuotaReconciler) getOrCreateAccountQuota(ctx context.Context, reqLogger logr.Logger, account *awsv1alpha1.Account) (*awsv1alpha1.AWSAccountQuota, error) {
+	quota := &awsv1alpha1.AWSAccountQuota{}
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(account), quota)
+	if err == nil || !k8serr.IsNotFound(err) {
+		return quota, err
+	}
+
+	quota = &awsv1alpha1.AWSAccountQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      account.Name,
+			Namespace: account.Namespace,
+		},
+		Spec: awsv1alpha1.AWSAccountQuotaSpec{
+			AccountLink: account.Name,
+		},
+	}
+	err = controllerutil.SetControllerReference(account, quota, r.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	err = r.Client.Create(ctx, quota)
+	if err != nil {
+		return nil, err
+	}
+	reqLogger.Info("Created AWSAccountQuota")
+	return quota, nil
+}
+
+// isNearlyExhausted returns true if the usage of a quota reached the threshold
+func isNearlyExhausted(quotas []awsv1alpha1.TrackedServiceQuota, threshold int) bool {
+	for _, quota := range quotas {
+		if quota.UsagePercent >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// getSnapshotInterval reads the snapshot interval from the configmap, falling back to the default when it's
+// missing or invalid
+func getSnapshotInterval(reqLogger logr.Logger, kubeClient client.Client) time.Duration {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		reqLogger.Error(err, "Unable to get the account quota snapshot interval from the configmap, using the default")
+		return defaultSnapshotInterval
+	}
+
+	value, ok := configMap.Data[awsv1alpha1.AccountQuotaSnapshotIntervalConfigMapKey]
+	if !ok || value == "" {
+		return defaultSnapshotInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		reqLogger.Error(err, "Invalid account quota snapshot interval in configmap, using the default", "value", value)
+		return defaultSnapshotInterval
+	}
+	return interval
+}
+
+// getExhaustionThreshold reads the exhaustion threshold from the configmap, falling back to the default when it's
+// missing or invalid
+func getExhaustionThreshold(reqLogger logr.Logger, kubeClient client.Client) int {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		reqLogger.Error(err, "Unable to get the account quota exhaustion threshold from the configmap, using the default")
+		return defaultExhaustionThreshold
+	}
+
+	value, ok := configMap.Data[awsv1alpha1.AccountQuotaExhaustionThresholdConfigMapKey]
+	if !ok || value == "" {
+		return defaultExhaustionThreshold
+	}
+	threshold, err := strconv.Atoi(value)
+	if err != nil || threshold <= 0 || threshold > 100 {
+		reqLogger.Error(err, "Invalid account quota exhaustion threshold in configmap, using the default", "value", value)
+		return defaultExhaustionThreshold
+	}
+	return threshold
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AWSAccountQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.awsClientBuilder = &awsclient.Builder{}
+	maxReconciles, err := utils.GetControllerMaxReconciles(controllerName)
+	if err != nil {
+		log.Error(err, "missing max reconciles for controller", "controller", controllerName)
+	}
+
+	rwm := utils.NewReconcilerWithMetrics(r, controllerName)
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&awsv1alpha1.AWSAccountQuota{}, builder.WithPredicates(utils.SpecOrMetadataChanged, utils.InShardFilter)).
+		// An Account becoming Ready gets its quotas snapshotted right away
+		Watches(&source.Kind{Type: &awsv1alpha1.Account{}}, &handler.EnqueueRequestForObject{}, builder.WithPredicates(utils.InShardFilter)).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: maxReconciles,
+		}).Complete(rwm)
+}
@@ -0,0 +1,166 @@
+package awsaccountquota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+)
+
+const accountName = "osd-creds-mgmt-aaabbb"
+
+func newTestReconciler(t *testing.T, objs ...runtime.Object) (*AWSAccountQuotaReconciler, *mock.MockClient) {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatalf("failed adding apis to scheme: %v", err)
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      awsv1alpha1.DefaultConfigMap,
+			Namespace: awsv1alpha1.AccountCrNamespace,
+		},
+		Data: map[string]string{awsv1alpha1.AccountQuotaExhaustionThresholdConfigMapKey: "50"},
+	}
+	objs = append(objs, configMap)
+	awsClientBuilder := &mock.Builder{MockController: gomock.NewController(t)}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
+	return NewAWSAccountQuotaReconciler(kubeClient, scheme.Scheme, awsClientBuilder), mock.GetMockClient(awsClientBuilder)
+}
+
+func newReadyAccount() *awsv1alpha1.Account {
+	return &awsv1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      accountName,
+			Namespace: awsv1alpha1.AccountCrNamespace,
+		},
+		Spec: awsv1alpha1.AccountSpec{
+			AwsAccountID: "123456789012",
+		},
+		Status: awsv1alpha1.AccountStatus{
+			State: string(awsv1alpha1.AccountReady),
+		},
+	}
+}
+
+func expectQuotaSnapshot(mockAWSClient *mock.MockClient) {
+	mockAWSClient.EXPECT().AssumeRole(gomock.Any()).Return(&sts.AssumeRoleOutput{
+		AssumedRoleUser: &sts.AssumedRoleUser{
+			Arn:           aws.String("aws:::OrganizationAccountAccessRole/awsAccountOperator"),
+			AssumedRoleId: aws.String("OrganizationAccountAccessRole/awsAccountOperator"),
+		},
+		Credentials: &sts.Credentials{
+			AccessKeyId:     aws.String("ACCESS_KEY"),
+			SecretAccessKey: aws.String("SECRET_KEY"),
+			SessionToken:    aws.String("SESSION_TOKEN"),
+		},
+	}, nil)
+	mockAWSClient.EXPECT().GetServiceQuota(gomock.Any()).Return(&servicequotas.GetServiceQuotaOutput{
+		Quota: &servicequotas.ServiceQuota{Value: aws.Float64(10)},
+	}, nil).Times(len(trackedQuotas))
+	mockAWSClient.EXPECT().DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{
+		Reservations: []*ec2.Reservation{{
+			Instances: []*ec2.Instance{
+				{InstanceType: aws.String("m5.xlarge"), CpuOptions: &ec2.CpuOptions{CoreCount: aws.Int64(2), ThreadsPerCore: aws.Int64(2)}},
+				// GPU instances count against another quota
+				{InstanceType: aws.String("p3.2xlarge"), CpuOptions: &ec2.CpuOptions{CoreCount: aws.Int64(4), ThreadsPerCore: aws.Int64(2)}},
+			},
+		}},
+	}, nil)
+	mockAWSClient.EXPECT().DescribeAddresses(gomock.Any()).Return(&ec2.DescribeAddressesOutput{
+		Addresses: []*ec2.Address{{}, {}, {}, {}, {}, {}},
+	}, nil)
+	mockAWSClient.EXPECT().DescribeVpcs(gomock.Any()).Return(&ec2.DescribeVpcsOutput{
+		Vpcs: []*ec2.Vpc{{}},
+	}, nil)
+	mockAWSClient.EXPECT().ListHostedZones(gomock.Any()).Return(&route53.ListHostedZonesOutput{
+		HostedZones: []*route53.HostedZone{},
+		IsTruncated: aws.Bool(false),
+	}, nil)
+}
+
+func TestReconcileSnapshotsQuotas(t *testing.T) {
+	r, mockAWSClient := newTestReconciler(t, newReadyAccount())
+	expectQuotaSnapshot(mockAWSClient)
+
+	result, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: accountName, Namespace: awsv1alpha1.AccountCrNamespace}})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultSnapshotInterval, result.RequeueAfter)
+
+	quota := &awsv1alpha1.AWSAccountQuota{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: accountName, Namespace: awsv1alpha1.AccountCrNamespace}, quota)
+	assert.NoError(t, err)
+	assert.Equal(t, accountName, quota.Spec.AccountLink)
+	assert.Len(t, quota.OwnerReferences, 1)
+	assert.NotNil(t, quota.Status.LastSnapshotTime)
+	assert.Empty(t, quota.Status.LastError)
+
+	usage := map[string]int{}
+	for _, q := range quota.Status.Quotas {
+		usage[q.Name] = q.UsagePercent
+	}
+	assert.Equal(t, map[string]int{"vcpus": 40, "elastic-ips": 60, "vpcs": 10, "route53-hosted-zones": 0}, usage)
+	// 6 of the 10 Elastic IPs are in use, above the threshold of 50%
+	assert.True(t, quota.Status.NearlyExhausted)
+}
+
+func TestReconcileKeepsFreshSnapshot(t *testing.T) {
+	lastSnapshot := metav1.NewTime(time.Now().Add(-time.Hour))
+	quota := &awsv1alpha1.AWSAccountQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: accountName, Namespace: awsv1alpha1.AccountCrNamespace},
+		Spec:       awsv1alpha1.AWSAccountQuotaSpec{AccountLink: accountName},
+		Status:     awsv1alpha1.AWSAccountQuotaStatus{LastSnapshotTime: &lastSnapshot},
+	}
+	r, _ := newTestReconciler(t, newReadyAccount(), quota)
+
+	// No AWS call is expected
+	result, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: accountName, Namespace: awsv1alpha1.AccountCrNamespace}})
+	assert.NoError(t, err)
+	assert.Greater(t, result.RequeueAfter, defaultSnapshotInterval-time.Hour-time.Minute)
+	assert.LessOrEqual(t, result.RequeueAfter, defaultSnapshotInterval-time.Hour)
+}
+
+func TestReconcileSkipsCCSAccounts(t *testing.T) {
+	account := newReadyAccount()
+	account.Spec.BYOC = true
+	r, _ := newTestReconciler(t, account)
+
+	result, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: accountName, Namespace: awsv1alpha1.AccountCrNamespace}})
+	assert.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+
+	quotas := &awsv1alpha1.AWSAccountQuotaList{}
+	assert.NoError(t, r.Client.List(context.TODO(), quotas))
+	assert.Empty(t, quotas.Items)
+}
+
+func TestUsagePercent(t *testing.T) {
+	tests := []struct {
+		usage, limit, expected int
+	}{
+		{usage: 0, limit: 10, expected: 0},
+		{usage: 8, limit: 10, expected: 80},
+		{usage: 12, limit: 10, expected: 120},
+		{usage: 0, limit: 0, expected: 0},
+		{usage: 1, limit: 0, expected: 100},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, usagePercent(test.usage, test.limit))
+	}
+}
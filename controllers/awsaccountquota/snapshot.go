@@ -0,0 +1,223 @@
+package awsaccountquota
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/go-logr/logr"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+const (
+	// globalRegion is the region of the quotas of global services
+	globalRegion = "global"
+	// route53HostedZonesQuotaCode is the quota of Route53 hosted zones per account
+	route53HostedZonesQuotaCode = "L-4EA4796A"
+	// standardInstanceFamilies are the first letters of the instance types counted against the
+	// Running On-Demand Standard instances quota
+	standardInstanceFamilies = "acdhimrtz"
+)
+
+// trackedQuota is a service quota whose usage is tracked, and how to measure it
+type trackedQuota struct {
+	name        string
+	serviceCode string
+	quotaCode   string
+	// global quotas apply to the whole account rather than to a region
+	global bool
+	usage  func(awsclient.Client) (int, error)
+}
+
+// trackedQuotas are the quotas a cluster is most likely to run into
+var trackedQuotas = []trackedQuota{
+	{name: "vcpus", serviceCode: "ec2", quotaCode: string(awsv1alpha1.RunningStandardInstances), usage: countRunningStandardVCPUs},
+	{name: "elastic-ips", serviceCode: "ec2", quotaCode: string(awsv1alpha1.EC2VPCElasticIPsQuotaCode), usage: countElasticIPs},
+	{name: "vpcs", serviceCode: "vpc", quotaCode: string(awsv1alpha1.VPCsPerRegionQuotaCode), usage: countVPCs},
+	{name: "route53-hosted-zones", serviceCode: "route53", quotaCode: route53HostedZonesQuotaCode, global: true, usage: countHostedZones},
+}
+
+// snapshotQuotas returns the tracked quotas of the account and their usage, the regional ones in the given regions,
+// the default region when none is given
+func (r *AWSAccountQuotaReconciler) snapshotQuotas(ctx context.Context, reqLogger logr.Logger, account *awsv1alpha1.Account, regions []string) ([]awsv1alpha1.TrackedServiceQuota, error) {
+	awsClientBuilder := awsclient.WithContext(ctx, r.awsClientBuilder)
+	awsSetupClient, err := awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Global services are queried from the default region
+	defaultRegion := config.GetDefaultRegion()
+	if len(regions) == 0 {
+		regions = []string{defaultRegion}
+	}
+	clients := map[string]awsclient.Client{}
+	getClient := func(region string) (awsclient.Client, error) {
+		if awsClient, ok := clients[region]; ok {
+			return awsClient, nil
+		}
+		awsClient, _, err := stsclient.HandleRoleAssumption(reqLogger, awsClientBuilder, account, r.Client, awsSetupClient, region, account.GetAssumeRole(), "")
+		if err != nil {
+			return nil, err
+		}
+		clients[region] = awsClient
+		return awsClient, nil
+	}
+
+	var quotas []awsv1alpha1.TrackedServiceQuota
+	for _, tracked := range trackedQuotas {
+		quotaRegions := regions
+		if tracked.global {
+			quotaRegions = []string{defaultRegion}
+		}
+		for _, region := range quotaRegions {
+			awsClient, err := getClient(region)
+			if err != nil {
+				return nil, err
+			}
+			quota, err := measureQuota(awsClient, tracked)
+			if err != nil {
+				return nil, fmt.Errorf("failed to snapshot quota %s in %s: %w", tracked.name, region, err)
+			}
+			quota.Region = region
+			if tracked.global {
+				quota.Region = globalRegion
+			}
+			quotas = append(quotas, quota)
+		}
+	}
+	return quotas, nil
+}
+
+// measureQuota returns the value of a quota and its usage
+func measureQuota(awsClient awsclient.Client, tracked trackedQuota) (awsv1alpha1.TrackedServiceQuota, error) {
+	quota := awsv1alpha1.TrackedServiceQuota{
+		Name:        tracked.name,
+		ServiceCode: tracked.serviceCode,
+		QuotaCode:   tracked.quotaCode,
+	}
+
+	output, err := awsClient.GetServiceQuota(&servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String(tracked.serviceCode),
+		QuotaCode:   aws.String(tracked.quotaCode),
+	})
+	if err != nil {
+		return quota, err
+	}
+	if output.Quota != nil {
+		quota.Limit = int(aws.Float64Value(output.Quota.Value))
+	}
+
+	quota.Usage, err = tracked.usage(awsClient)
+	if err != nil {
+		return quota, err
+	}
+	quota.UsagePercent = usagePercent(quota.Usage, quota.Limit)
+	return quota, nil
+}
+
+// usagePercent returns the usage as a percentage of the limit, a quota of 0 in use is full
+func usagePercent(usage, limit int) int {
+	if limit <= 0 {
+		if usage > 0 {
+			return 100
+		}
+		return 0
+	}
+	return usage * 100 / limit
+}
+
+// countRunningStandardVCPUs counts the vCPUs of the pending and running instances of the standard families
+func countRunningStandardVCPUs(awsClient awsclient.Client) (int, error) {
+	vcpus := 0
+	var nextToken *string
+	for {
+		output, err := awsClient.DescribeInstances(&ec2.DescribeInstancesInput{
+			Filters: []*ec2.Filter{{
+				Name:   aws.String("instance-state-name"),
+				Values: aws.StringSlice([]string{ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning}),
+			}},
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return 0, err
+		}
+		for _, reservation := range output.Reservations {
+			for _, instance := range reservation.Instances {
+				instanceType := aws.StringValue(instance.InstanceType)
+				if instanceType == "" || !strings.ContainsRune(standardInstanceFamilies, rune(instanceType[0])) {
+					continue
+				}
+				if instance.CpuOptions == nil {
+					continue
+				}
+				vcpus += int(aws.Int64Value(instance.CpuOptions.CoreCount) * aws.Int64Value(instance.CpuOptions.ThreadsPerCore))
+			}
+		}
+		if aws.StringValue(output.NextToken) == "" {
+			return vcpus, nil
+		}
+		nextToken = output.NextToken
+	}
+}
+
+// countElasticIPs counts the allocated VPC Elastic IPs
+func countElasticIPs(awsClient awsclient.Client) (int, error) {
+	output, err := awsClient.DescribeAddresses(&ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("domain"),
+			Values: aws.StringSlice([]string{ec2.DomainTypeVpc}),
+		}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(output.Addresses), nil
+}
+
+// countVPCs counts the VPCs, including the default one
+func countVPCs(awsClient awsclient.Client) (int, error) {
+	vpcs := 0
+	var nextToken *string
+	for {
+		output, err := awsClient.DescribeVpcs(&ec2.DescribeVpcsInput{NextToken: nextToken})
+		if err != nil {
+			return 0, err
+		}
+		vpcs += len(output.Vpcs)
+		if aws.StringValue(output.NextToken) == "" {
+			return vpcs, nil
+		}
+		nextToken = output.NextToken
+	}
+}
+
+// countHostedZones counts the Route53 hosted zones
+func countHostedZones(awsClient awsclient.Client) (int, error) {
+	zones := 0
+	var marker *string
+	for {
+		output, err := awsClient.ListHostedZones(&route53.ListHostedZonesInput{Marker: marker})
+		if err != nil {
+			return 0, err
+		}
+		zones += len(output.HostedZones)
+		if !aws.BoolValue(output.IsTruncated) {
+			return zones, nil
+		}
+		marker = output.NextMarker
+	}
+}
@@ -0,0 +1,282 @@
+package awsfederatedaccessgroup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/go-logr/logr"
+	userv1 "github.com/openshift/api/user/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+const (
+	controllerName = "awsfederatedaccessgroup"
+
+	// accessGroupResyncInterval is how often the accounts selected by an access group are checked again.
+	// Changes to the OpenShift Group are picked up immediately.
+	accessGroupResyncInterval = 10 * time.Minute
+)
+
+var log = logf.Log.WithName("controller_awsfederatedaccessgroup")
+
+// AWSFederatedAccessGroupReconciler reconciles a AWSFederatedAccessGroup object
+type AWSFederatedAccessGroupReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// iamARNTemplateData is what the IAM ARN template of an access group is rendered with
+type iamARNTemplateData struct {
+	// User is the name of the group member
+	User string
+}
+
+//+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=awsfederatedaccessgroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=awsfederatedaccessgroups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=awsfederatedaccountaccesses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=user.openshift.io,resources=groups,verbs=get;list;watch
+
+// Reconcile grants the members of the OpenShift Group of an AWSFederatedAccessGroup its federated role in
+// each selected account, by creating an AWSFederatedAccountAccess per member and account. The account
+// accesses of users who left the group, or of accounts no longer selected, are deleted, which revokes them.
+func (r *AWSFederatedAccessGroupReconciler) Reconcile(_ context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.WithValues("Controller", controllerName, "Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	accessGroup := &awsv1alpha1.AWSFederatedAccessGroup{}
+	err := r.Client.Get(context.TODO(), request.NamespacedName, accessGroup)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// The account accesses are owned by the access group and garbage collected with it
+	if accessGroup.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	members, err := r.getGroupMembers(reqLogger, accessGroup.Spec.GroupName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	accounts, err := r.getSelectedAccounts(accessGroup)
+	if err != nil {
+		reqLogger.Error(err, "Failed to list the accounts selected by the access group")
+		return reconcile.Result{}, err
+	}
+
+	desired, err := buildAccountAccesses(accessGroup, members, accounts)
+	if err != nil {
+		// The spec needs fixing, retrying won't help
+		reqLogger.Error(err, "Failed to build the account accesses of the access group")
+		return reconcile.Result{}, nil
+	}
+
+	current := &awsv1alpha1.AWSFederatedAccountAccessList{}
+	err = r.Client.List(context.TODO(), current, client.InNamespace(accessGroup.Namespace), client.MatchingLabels{awsv1alpha1.FederatedAccessGroupLabel: accessGroup.Name})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	for i := range current.Items {
+		accountAccess := &current.Items[i]
+		want, ok := desired[accountAccess.Name]
+		if ok && reflect.DeepEqual(accountAccess.Spec, want.Spec) {
+			delete(desired, accountAccess.Name)
+			continue
+		}
+		if !ok {
+			reqLogger.Info("Revoking account access", "accountAccess", accountAccess.Name, "user", accountAccess.Annotations[awsv1alpha1.FederatedAccessGroupUserAnnotation])
+		} else {
+			// The spec of an account access can't change, it's recreated once the outdated one is deleted
+			reqLogger.Info("Replacing outdated account access", "accountAccess", accountAccess.Name)
+			delete(desired, accountAccess.Name)
+		}
+		if accountAccess.DeletionTimestamp != nil {
+			continue
+		}
+		err = r.Client.Delete(context.TODO(), accountAccess)
+		if err != nil && !k8serr.IsNotFound(err) {
+			reqLogger.Error(err, "Failed to delete account access", "accountAccess", accountAccess.Name)
+			return reconcile.Result{}, err
+		}
+	}
+
+	for _, accountAccess := range desired {
+		if err := controllerutil.SetControllerReference(accessGroup, accountAccess, r.Scheme); err != nil {
+			return reconcile.Result{}, err
+		}
+		reqLogger.Info("Granting account access", "accountAccess", accountAccess.Name, "user", accountAccess.Annotations[awsv1alpha1.FederatedAccessGroupUserAnnotation])
+		err = r.Client.Create(context.TODO(), accountAccess)
+		if err != nil && !k8serr.IsAlreadyExists(err) {
+			reqLogger.Error(err, "Failed to create account access", "accountAccess", accountAccess.Name)
+			return reconcile.Result{}, err
+		}
+	}
+
+	accountNames := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		accountNames = append(accountNames, account.Name)
+	}
+	err = controllerutils.UpdateStatusWithRetry(r.Client, accessGroup, func() error {
+		accessGroup.Status.Members = members
+		accessGroup.Status.Accounts = accountNames
+		accessGroup.Status.AccountAccesses = len(members) * len(accounts)
+		return nil
+	})
+	if err != nil {
+		reqLogger.Error(err, "Failed to update the access group status")
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{RequeueAfter: accessGroupResyncInterval}, nil
+}
+
+// getGroupMembers returns the sorted users of the OpenShift Group. A missing group has no members.
+func (r *AWSFederatedAccessGroupReconciler) getGroupMembers(reqLogger logr.Logger, groupName string) ([]string, error) {
+	group := &userv1.Group{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: groupName}, group)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			reqLogger.Info("OpenShift Group not found, revoking the access of its former members", "group", groupName)
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	members := append([]string{}, group.Users...)
+	sort.Strings(members)
+	return members, nil
+}
+
+// getSelectedAccounts returns the Ready accounts selected by the access group, sorted by name. Accounts
+// without an IAM user secret can't be accessed by the federated account access controller and are skipped.
+func (r *AWSFederatedAccessGroupReconciler) getSelectedAccounts(accessGroup *awsv1alpha1.AWSFederatedAccessGroup) ([]awsv1alpha1.Account, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&accessGroup.Spec.AccountSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	accountList := &awsv1alpha1.AccountList{}
+	err = r.Client.List(context.TODO(), accountList, client.InNamespace(awsv1alpha1.AccountCrNamespace), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := []awsv1alpha1.Account{}
+	for _, account := range accountList.Items {
+		if !account.IsReady() || account.Spec.IAMUserSecret == "" {
+			continue
+		}
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Name < accounts[j].Name })
+	return accounts, nil
+}
+
+// buildAccountAccesses returns the account accesses of the access group by name, one per member and account
+func buildAccountAccesses(accessGroup *awsv1alpha1.AWSFederatedAccessGroup, members []string, accounts []awsv1alpha1.Account) (map[string]*awsv1alpha1.AWSFederatedAccountAccess, error) {
+	tmpl, err := template.New("iam-arn").Option("missingkey=error").Parse(accessGroup.Spec.IAMARNTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IAM ARN template: %w", err)
+	}
+
+	accountAccesses := map[string]*awsv1alpha1.AWSFederatedAccountAccess{}
+	for _, member := range members {
+		var iamARN bytes.Buffer
+		if err := tmpl.Execute(&iamARN, iamARNTemplateData{User: member}); err != nil {
+			return nil, fmt.Errorf("failed to render the IAM ARN of %s: %w", member, err)
+		}
+
+		for _, account := range accounts {
+			name := accountAccessName(accessGroup.Name, member, account.Name)
+			accountAccesses[name] = &awsv1alpha1.AWSFederatedAccountAccess{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: accessGroup.Namespace,
+					Labels:    map[string]string{awsv1alpha1.FederatedAccessGroupLabel: accessGroup.Name},
+					Annotations: map[string]string{
+						awsv1alpha1.FederatedAccessGroupUserAnnotation:    member,
+						awsv1alpha1.FederatedAccessGroupAccountAnnotation: account.Name,
+					},
+				},
+				Spec: awsv1alpha1.AWSFederatedAccountAccessSpec{
+					ExternalCustomerAWSIAMARN: iamARN.String(),
+					AWSCustomerCredentialSecret: awsv1alpha1.AWSSecretReference{
+						Name:      account.Spec.IAMUserSecret,
+						Namespace: account.Namespace,
+					},
+					AWSFederatedRole: accessGroup.Spec.AWSFederatedRole,
+					Expiration:       accessGroup.Spec.Expiration,
+				},
+			}
+		}
+	}
+	return accountAccesses, nil
+}
+
+// accountAccessName returns the name of the account access of a member in an account. User names aren't
+// always valid in object names, so the member and account are hashed.
+func accountAccessName(accessGroupName, member, accountName string) string {
+	sum := sha256.Sum256([]byte(member + "/" + accountName))
+	return fmt.Sprintf("%s-%s", accessGroupName, hex.EncodeToString(sum[:])[:10])
+}
+
+// accessGroupsForGroup returns the access groups of an OpenShift Group, which are reconciled when its
+// membership changes
+func (r *AWSFederatedAccessGroupReconciler) accessGroupsForGroup(object client.Object) []reconcile.Request {
+	accessGroups := &awsv1alpha1.AWSFederatedAccessGroupList{}
+	if err := r.Client.List(context.TODO(), accessGroups); err != nil {
+		log.Error(err, "Failed to list the access groups of OpenShift Group", "group", object.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, accessGroup := range accessGroups.Items {
+		if accessGroup.Spec.GroupName == object.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: accessGroup.Name, Namespace: accessGroup.Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AWSFederatedAccessGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	maxReconciles, err := controllerutils.GetControllerMaxReconciles(controllerName)
+	if err != nil {
+		log.Error(err, "missing max reconciles for controller", "controller", controllerName)
+	}
+
+	rwm := controllerutils.NewReconcilerWithMetrics(r, controllerName)
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&awsv1alpha1.AWSFederatedAccessGroup{}, builder.WithPredicates(controllerutils.SpecOrMetadataChanged)).
+		Owns(&awsv1alpha1.AWSFederatedAccountAccess{}).
+		Watches(&source.Kind{Type: &userv1.Group{}}, handler.EnqueueRequestsFromMapFunc(r.accessGroupsForGroup)).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: maxReconciles,
+		}).Complete(rwm)
+}
@@ -0,0 +1,129 @@
+package awsfederatedaccessgroup
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	userv1 "github.com/openshift/api/user/v1"
+	apis "github.com/openshift/aws-account-operator/api"
+	"github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testAccessGroupName = "sre-read-only"
+	testNamespace       = "aws-account-operator"
+)
+
+func newAccessGroup() *v1alpha1.AWSFederatedAccessGroup {
+	return &v1alpha1.AWSFederatedAccessGroup{
+		ObjectMeta: v1.ObjectMeta{Name: testAccessGroupName, Namespace: testNamespace},
+		Spec: v1alpha1.AWSFederatedAccessGroupSpec{
+			GroupName:        "sre",
+			IAMARNTemplate:   "arn:aws:iam::123456789012:user/{{.User}}",
+			AWSFederatedRole: v1alpha1.AWSFederatedRoleRef{Name: "read-only", Namespace: testNamespace},
+			AccountSelector:  v1.LabelSelector{MatchLabels: map[string]string{"environment": "staging"}},
+		},
+	}
+}
+
+func newAccount(name, environment, state string) *v1alpha1.Account {
+	return &v1alpha1.Account{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: v1alpha1.AccountCrNamespace, Labels: map[string]string{"environment": environment}},
+		Spec:       v1alpha1.AccountSpec{IAMUserSecret: name + "-secret"},
+		Status:     v1alpha1.AccountStatus{State: state},
+	}
+}
+
+func newGroup(users ...string) *userv1.Group {
+	return &userv1.Group{ObjectMeta: v1.ObjectMeta{Name: "sre"}, Users: users}
+}
+
+func TestAWSFederatedAccessGroupReconciler_Reconcile(t *testing.T) {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		fmt.Printf("failed adding to scheme in awsfederatedaccessgroup_controller_test.go")
+	}
+	if err := userv1.Install(scheme.Scheme); err != nil {
+		fmt.Printf("failed adding user/v1 to scheme in awsfederatedaccessgroup_controller_test.go")
+	}
+
+	staging := newAccount("staging", "staging", string(v1alpha1.AccountReady))
+	production := newAccount("production", "production", string(v1alpha1.AccountReady))
+	creating := newAccount("creating", "staging", string(v1alpha1.AccountCreating))
+
+	// An account access of a user who left the group
+	formerMember := &v1alpha1.AWSFederatedAccountAccess{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        accountAccessName(testAccessGroupName, "carol", staging.Name),
+			Namespace:   testNamespace,
+			Labels:      map[string]string{v1alpha1.FederatedAccessGroupLabel: testAccessGroupName},
+			Annotations: map[string]string{v1alpha1.FederatedAccessGroupUserAnnotation: "carol"},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		localObjects     []runtime.Object
+		expectedMembers  []string
+		expectedAccesses []string
+	}{
+		{
+			name:             "Members granted access to the selected ready accounts",
+			localObjects:     []runtime.Object{newAccessGroup(), newGroup("bob", "alice"), staging, production, creating},
+			expectedMembers:  []string{"alice", "bob"},
+			expectedAccesses: []string{accountAccessName(testAccessGroupName, "alice", staging.Name), accountAccessName(testAccessGroupName, "bob", staging.Name)},
+		},
+		{
+			name:             "Access of former members revoked",
+			localObjects:     []runtime.Object{newAccessGroup(), newGroup("alice"), staging, formerMember},
+			expectedMembers:  []string{"alice"},
+			expectedAccesses: []string{accountAccessName(testAccessGroupName, "alice", staging.Name)},
+		},
+		{
+			name:             "Missing group has no members",
+			localObjects:     []runtime.Object{newAccessGroup(), staging, formerMember},
+			expectedMembers:  []string{},
+			expectedAccesses: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeKubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(tt.localObjects...).Build()
+			r := &AWSFederatedAccessGroupReconciler{Client: fakeKubeClient, Scheme: scheme.Scheme}
+
+			_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testAccessGroupName, Namespace: testNamespace}})
+			assert.NoError(t, err)
+
+			accountAccesses := &v1alpha1.AWSFederatedAccountAccessList{}
+			assert.NoError(t, fakeKubeClient.List(context.TODO(), accountAccesses, client.MatchingLabels{v1alpha1.FederatedAccessGroupLabel: testAccessGroupName}))
+			names := []string{}
+			for _, accountAccess := range accountAccesses.Items {
+				names = append(names, accountAccess.Name)
+				assert.Equal(t, "arn:aws:iam::123456789012:user/"+accountAccess.Annotations[v1alpha1.FederatedAccessGroupUserAnnotation], accountAccess.Spec.ExternalCustomerAWSIAMARN)
+				assert.Equal(t, "staging-secret", accountAccess.Spec.AWSCustomerCredentialSecret.Name)
+			}
+			assert.ElementsMatch(t, tt.expectedAccesses, names)
+
+			accessGroup := &v1alpha1.AWSFederatedAccessGroup{}
+			assert.NoError(t, fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testAccessGroupName, Namespace: testNamespace}, accessGroup))
+			assert.ElementsMatch(t, tt.expectedMembers, accessGroup.Status.Members)
+			assert.Equal(t, len(tt.expectedAccesses), accessGroup.Status.AccountAccesses)
+		})
+	}
+}
+
+func TestBuildAccountAccessesInvalidTemplate(t *testing.T) {
+	accessGroup := newAccessGroup()
+	accessGroup.Spec.IAMARNTemplate = "arn:aws:iam::123456789012:user/{{.Username}}"
+
+	_, err := buildAccountAccesses(accessGroup, []string{"alice"}, []v1alpha1.Account{*newAccount("staging", "staging", string(v1alpha1.AccountReady))})
+	assert.Error(t, err)
+}
@@ -9,8 +9,11 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/sts"
+	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
+	"time"
 
 	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
@@ -18,16 +21,20 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
 
 	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
 	"github.com/openshift/aws-account-operator/config"
 	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/localmetrics"
 )
 
 const (
@@ -49,6 +56,7 @@ type AWSFederatedAccountAccessReconciler struct {
 	client.Client
 	Scheme           *runtime.Scheme
 	awsClientBuilder awsclient.IBuilder
+	httpClient       *http.Client
 }
 
 //+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=awsfederatedaccountaccesses,verbs=get;list;watch;create;update;patch;delete
@@ -140,6 +148,16 @@ func (r *AWSFederatedAccountAccessReconciler) Reconcile(_ context.Context, reque
 		return reconcile.Result{}, err
 	}
 
+	// Expired account accesses keep their revoked role until they are deleted
+	if currentFAA.Status.State == awsv1alpha1.AWSFederatedAccountStateExpired {
+		return reconcile.Result{}, nil
+	}
+
+	// Revoke the access once it expires
+	if remaining, ok := untilExpiration(currentFAA); ok && remaining <= 0 {
+		return r.expireAccountAccess(reqLogger, awsClient, currentFAA)
+	}
+
 	if currentFAA.Status.State != "" {
 		// Make sure the awsFederatedRoleName label is present
 		if !hasLabel(currentFAA, awsv1alpha1.FederatedRoleNameLabel) {
@@ -165,8 +183,33 @@ func (r *AWSFederatedAccountAccessReconciler) Reconcile(_ context.Context, reque
 		}
 	}
 
-	// If the state is ready or failed don't do anything
-	if currentFAA.Status.State == awsv1alpha1.AWSFederatedAccountStateReady || currentFAA.Status.State == awsv1alpha1.AWSFederatedAccountStateFailed {
+	// Repair the drift of the role of Ready account accesses, and check again for drift periodically
+	if currentFAA.Status.State == awsv1alpha1.AWSFederatedAccountStateReady {
+		if err = r.repairRoleDrift(reqLogger, awsClient, requestedRole, currentFAA); err != nil {
+			reqLogger.Error(err, fmt.Sprintf("Failed to repair the drift of the role of account access %s/%s", currentFAA.Namespace, currentFAA.Name))
+			return reconcile.Result{}, err
+		}
+
+		signinURLSecret := currentFAA.Status.SigninURLSecret
+		refreshAt, err := r.ensureSigninURL(reqLogger, awsClient, requestedRole, currentFAA)
+		if err != nil {
+			reqLogger.Error(err, fmt.Sprintf("Failed to generate the console sign-in URL of account access %s/%s", currentFAA.Namespace, currentFAA.Name))
+			return reconcile.Result{}, err
+		}
+		if currentFAA.Status.SigninURLSecret != signinURLSecret {
+			err = r.Client.Status().Update(context.TODO(), currentFAA)
+			if err != nil {
+				reqLogger.Error(err, fmt.Sprintf("Status update for %s failed", currentFAA.Name))
+				return reconcile.Result{}, err
+			}
+		}
+
+		result := requeueBeforeSigninRefresh(reconcile.Result{RequeueAfter: getRoleResyncInterval(reqLogger, r.Client)}, refreshAt)
+		return requeueBeforeExpiration(result, currentFAA), nil
+	}
+
+	// If the state is failed don't do anything
+	if currentFAA.Status.State == awsv1alpha1.AWSFederatedAccountStateFailed {
 		return reconcile.Result{}, nil
 	}
 
@@ -267,9 +310,12 @@ func (r *AWSFederatedAccountAccessReconciler) Reconcile(_ context.Context, reque
 	// Get policy arns for managed policies
 	policyArns := createPolicyArns(accountID, awsManagedPolicyNames, true)
 	// Get custom policy arns
-	customPolicy := []string{requestedRole.Spec.AWSCustomPolicy.Name + "-" + uidLabel}
-	customerPolArns := createPolicyArns(accountID, customPolicy, false)
-	policyArns = append(policyArns, customerPolArns[0])
+	customerPolArns, err := customPolicyArns(accountID, *requestedRole, uidLabel)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	policyArns = append(policyArns, customerPolArns...)
+	currentFAA.Status.CustomPolicyArns = customerPolArns
 
 	// Attach the requested policy to the newly created role
 	err = r.attachIAMPolices(awsClient, currentFAA.Spec.AWSFederatedRole.Name+"-"+uidLabel, policyArns)
@@ -286,6 +332,16 @@ func (r *AWSFederatedAccountAccessReconciler) Reconcile(_ context.Context, reque
 
 		return reconcile.Result{}, nil
 	}
+	// Publish the console sign-in URL. The role is usable without it, so failures are retried once Ready.
+	result := reconcile.Result{}
+	refreshAt, err := r.ensureSigninURL(reqLogger, awsClient, requestedRole, currentFAA)
+	if err != nil {
+		reqLogger.Error(err, fmt.Sprintf("Failed to generate the console sign-in URL of account access %s/%s", currentFAA.Namespace, currentFAA.Name))
+		result.RequeueAfter = time.Minute
+	} else {
+		result = requeueBeforeSigninRefresh(result, refreshAt)
+	}
+
 	// Mark AWSFederatedAccountAccess CR as Ready.
 	SetStatuswithCondition(currentFAA, "Account Access Ready", awsv1alpha1.AWSFederatedAccountReady, awsv1alpha1.AWSFederatedAccountStateReady)
 	reqLogger.Info(fmt.Sprintf("Successfully applied %s", currentFAA.Name))
@@ -295,29 +351,11 @@ func (r *AWSFederatedAccountAccessReconciler) Reconcile(_ context.Context, reque
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{}, nil
-}
-
-func detachRolePolicy(awsClient awsclient.Client, federatedRole *awsv1alpha1.AWSFederatedRole, awsAccountID string, uid string) error {
-	roleName := federatedRole.Name + "-" + uid
-	policyName := federatedRole.Spec.AWSCustomPolicy.Name + "-" + uid
-	policyArn := fmt.Sprintf("arn:aws:iam::%s:policy/%s", awsAccountID, policyName)
-
-	if _, err := awsClient.DetachRolePolicy(&iam.DetachRolePolicyInput{
-		PolicyArn: &policyArn,
-		RoleName:  &roleName,
-	}); err != nil {
-		if aerr, ok := err.(awserr.Error); ok {
-			if aerr.Code() != iam.ErrCodeNoSuchEntityException {
-				return err
-			}
-		} else {
-			return err
-		}
-	}
-	return nil
+	return requeueBeforeExpiration(result, currentFAA), nil
 }
 
+// syncIAMPolicy validates that the custom policy parts attached to the role match the AWSFederatedRole, and
+// recreates them when they don't, e.g. after the AWSFederatedRole changed
 func (r *AWSFederatedAccountAccessReconciler) syncIAMPolicy(currentFAA *awsv1alpha1.AWSFederatedAccountAccess, requestedRole *awsv1alpha1.AWSFederatedRole, awsClient awsclient.Client, reqLogger logr.Logger) error {
 	// validate that the policy in AWS matches the CR
 	uid, ok := currentFAA.Labels[awsv1alpha1.UIDLabel]
@@ -327,101 +365,147 @@ func (r *AWSFederatedAccountAccessReconciler) syncIAMPolicy(currentFAA *awsv1alp
 		return err
 	}
 	roleName := fmt.Sprintf("%s-%s", requestedRole.Name, uid)
-	policyName := fmt.Sprintf("%s-%s", requestedRole.Spec.AWSCustomPolicy.Name, uid)
+	accountID := currentFAA.Labels[awsv1alpha1.AccountIDLabel]
+
+	policyNames, policyDocuments, err := customPolicyParts(*requestedRole, uid)
+	if err != nil {
+		reqLogger.Error(err, fmt.Sprintf("Failed to marshal policy %s for role %s", requestedRole.Spec.AWSCustomPolicy.Name, roleName))
+		return err
+	}
+	expectedDocuments := map[string]string{}
+	for i, policyName := range policyNames {
+		expectedDocuments[policyName] = policyDocuments[i]
+	}
+	expectedArns := createPolicyArns(accountID, policyNames, false)
+	currentArns := currentCustomPolicyArns(currentFAA, *requestedRole, accountID, uid)
+
 	awsRolePolicies, err := awsClient.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{RoleName: &roleName})
 	if err != nil {
 		reqLogger.Error(err, fmt.Sprintf("Failed to list policies for role %s from AWS", roleName))
 		return err
 	}
 
+	// The parts are recreated when any of them differs, or when the policy was split differently
+	inSync := reflect.DeepEqual(currentArns, expectedArns)
 	for _, awsAttachedPolicy := range awsRolePolicies.AttachedPolicies {
-		if *awsAttachedPolicy.PolicyName == policyName {
-			awsPolicy, err := awsClient.GetPolicy(&iam.GetPolicyInput{PolicyArn: awsAttachedPolicy.PolicyArn})
-			if err != nil {
-				reqLogger.Error(err, fmt.Sprintf("Failed to get policy %s for role %s from AWS", *awsAttachedPolicy.PolicyName, roleName))
-				return err
-			}
+		expectedDocument, ok := expectedDocuments[*awsAttachedPolicy.PolicyName]
+		if !ok || !inSync {
+			continue
+		}
 
-			awsPolicyVersion, err := awsClient.GetPolicyVersion(&iam.GetPolicyVersionInput{
-				PolicyArn: awsAttachedPolicy.PolicyArn,
-				VersionId: awsPolicy.Policy.DefaultVersionId,
-			})
-			if err != nil {
-				reqLogger.Error(err, fmt.Sprintf("Failed to get version %s for policy %s for role %s from AWS", *awsPolicy.Policy.DefaultVersionId, *awsAttachedPolicy.PolicyName, roleName))
-				return err
-			}
+		awsPolicy, err := awsClient.GetPolicy(&iam.GetPolicyInput{PolicyArn: awsAttachedPolicy.PolicyArn})
+		if err != nil {
+			reqLogger.Error(err, fmt.Sprintf("Failed to get policy %s for role %s from AWS", *awsAttachedPolicy.PolicyName, roleName))
+			return err
+		}
 
-			awsDocument, err := url.QueryUnescape(*awsPolicyVersion.PolicyVersion.Document)
-			if err != nil {
-				reqLogger.Error(err, fmt.Sprintf("Failed to parse policy document from AWS for %v", awsAttachedPolicy.PolicyName))
-			}
+		awsPolicyVersion, err := awsClient.GetPolicyVersion(&iam.GetPolicyVersionInput{
+			PolicyArn: awsAttachedPolicy.PolicyArn,
+			VersionId: awsPolicy.Policy.DefaultVersionId,
+		})
+		if err != nil {
+			reqLogger.Error(err, fmt.Sprintf("Failed to get version %s for policy %s for role %s from AWS", *awsPolicy.Policy.DefaultVersionId, *awsAttachedPolicy.PolicyName, roleName))
+			return err
+		}
 
-			jsonRequestedRole, err := controllerutils.MarshalIAMPolicy(*requestedRole)
-			if err != nil {
-				reqLogger.Error(err, fmt.Sprintf("Failed to marshal policy %s for role %s from AWS", *awsAttachedPolicy.PolicyName, roleName))
-				return err
-			}
+		awsDocument, err := url.QueryUnescape(*awsPolicyVersion.PolicyVersion.Document)
+		if err != nil {
+			reqLogger.Error(err, fmt.Sprintf("Failed to parse policy document from AWS for %v", awsAttachedPolicy.PolicyName))
+		}
 
-			if jsonRequestedRole == awsDocument {
-				return nil
-			}
+		if expectedDocument != awsDocument {
+			inSync = false
+		}
+	}
+	if inSync {
+		return nil
+	}
 
-			err = detachRolePolicy(awsClient, requestedRole, currentFAA.Labels[awsv1alpha1.AccountIDLabel], uid)
-			if err != nil {
-				reqLogger.Error(err, fmt.Sprintf("Failed to detach policy %s from role %s", requestedRole.Spec.AWSCustomPolicy.Name, requestedRole.Name))
-				return err
-			}
-			err = r.createOrUpdateIAMPolicy(awsClient, *requestedRole, *currentFAA)
-			if err != nil {
-				reqLogger.Error(err, fmt.Sprintf("Failed to apply IAM policy for AWS federated account access CR %s/%s", requestedRole.Namespace, roleName))
-				return err
-			}
-			err = r.attachIAMPolices(awsClient, roleName, createPolicyArns(currentFAA.Labels[awsv1alpha1.AccountIDLabel], []string{requestedRole.Spec.AWSCustomPolicy.Name + "-" + uid}, false))
-			if err != nil {
-				reqLogger.Error(err, fmt.Sprintf("Failed to attach IAM policy for AWS federated account access CR %s/%s", requestedRole.Namespace, roleName))
+	// Detach every part before recreating them, attached policies can't be deleted
+	err = detachRolePolicies(awsClient, roleName, append(append([]string{}, currentArns...), expectedArns...))
+	if err != nil {
+		reqLogger.Error(err, fmt.Sprintf("Failed to detach policy %s from role %s", requestedRole.Spec.AWSCustomPolicy.Name, requestedRole.Name))
+		return err
+	}
+	for _, policyArn := range currentArns {
+		if policyInSlice(policyArn, expectedArns) {
+			continue
+		}
+		// Parts the policy no longer needs
+		if err = deleteCustomPolicy(awsClient, aws.String(policyArn)); err != nil {
+			if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
+				reqLogger.Error(err, fmt.Sprintf("Failed to delete policy %s", policyArn))
 				return err
 			}
 		}
 	}
+	err = r.createOrUpdateIAMPolicy(awsClient, *requestedRole, *currentFAA)
+	if err != nil {
+		reqLogger.Error(err, fmt.Sprintf("Failed to apply IAM policy for AWS federated account access CR %s/%s", requestedRole.Namespace, roleName))
+		return err
+	}
+	err = r.attachIAMPolices(awsClient, roleName, expectedArns)
+	if err != nil {
+		reqLogger.Error(err, fmt.Sprintf("Failed to attach IAM policy for AWS federated account access CR %s/%s", requestedRole.Namespace, roleName))
+		return err
+	}
+	reqLogger.Info(fmt.Sprintf("Repaired custom policy %s of role %s", requestedRole.Spec.AWSCustomPolicy.Name, roleName))
+	localmetrics.Collector.AddFederatedRoleRepair(driftCustomPolicy)
+
+	if !reflect.DeepEqual(currentFAA.Status.CustomPolicyArns, expectedArns) {
+		currentFAA.Status.CustomPolicyArns = expectedArns
+		if err = r.Client.Status().Update(context.TODO(), currentFAA); err != nil {
+			reqLogger.Error(err, fmt.Sprintf("Status update for %s failed", currentFAA.Name))
+			return err
+		}
+	}
 	return nil
 }
 
-// createIAMPolicy creates the IAM policies in AWSFederatedRole inside our cluster account
-func (r *AWSFederatedAccountAccessReconciler) createIAMPolicy(awsClient awsclient.Client, afr awsv1alpha1.AWSFederatedRole, afaa awsv1alpha1.AWSFederatedAccountAccess) (*iam.Policy, error) {
-	// Same struct from the afr.Spec.AWSCustomPolicy.Statements , but with json tags as capitals due to requirements for the policydoc
-
-	statements := []controllerutils.AwsStatement{}
-
-	for _, sm := range afr.Spec.AWSCustomPolicy.Statements {
-		var a = controllerutils.AwsStatement(sm)
-		statements = append(statements, a)
+// policyInSlice returns true if the policy is in the list
+func policyInSlice(policy string, policyList []string) bool {
+	for _, p := range policyList {
+		if p == policy {
+			return true
+		}
 	}
+	return false
+}
 
-	// Create an aws policydoc formated struct
-	policyDoc := controllerutils.AwsPolicy{
-		Version:   "2012-10-17",
-		Statement: statements,
+// createIAMPolicy creates the IAM policies in AWSFederatedRole inside our cluster account. Custom policies too
+// large for a single customer managed policy are created in parts, the first one is returned.
+func (r *AWSFederatedAccountAccessReconciler) createIAMPolicy(awsClient awsclient.Client, afr awsv1alpha1.AWSFederatedRole, afaa awsv1alpha1.AWSFederatedAccountAccess) (*iam.Policy, error) {
+	uidLabel, ok := afaa.Labels["uid"]
+	if !ok {
+		// Just in case the UID somehow doesn't exist
+		return nil, errors.New("Failed to get UID label")
 	}
 
-	// Marshal policydoc to json
-	jsonPolicyDoc, err := json.Marshal(&policyDoc)
+	policyNames, policyDocuments, err := customPolicyParts(afr, uidLabel)
 	if err != nil {
 		return &iam.Policy{}, fmt.Errorf("Error marshalling jsonPolicy doc : Error %s", err.Error())
 	}
 
-	var policyName string
-	// Try and build policy name
-	if uidLabel, ok := afaa.Labels["uid"]; ok {
-		policyName = afr.Spec.AWSCustomPolicy.Name + "-" + uidLabel
-	} else {
-		// Just in case the UID somehow doesn't exist
-		return nil, errors.New("Failed to get UID label")
+	var policy *iam.Policy
+	for i, policyName := range policyNames {
+		output, err := createCustomPolicyPart(awsClient, afr, policyName, policyDocuments[i])
+		if err != nil {
+			return nil, err
+		}
+		if policy == nil {
+			policy = output
+		}
 	}
 
+	return policy, nil
+}
+
+// createCustomPolicyPart creates a single customer managed policy for the custom policy of the AWSFederatedRole
+func createCustomPolicyPart(awsClient awsclient.Client, afr awsv1alpha1.AWSFederatedRole, policyName string, policyDocument string) (*iam.Policy, error) {
 	output, err := awsClient.CreatePolicy(&iam.CreatePolicyInput{
 		PolicyName:     aws.String(policyName),
 		Description:    aws.String(afr.Spec.AWSCustomPolicy.Description),
-		PolicyDocument: aws.String(string(jsonPolicyDoc)),
+		PolicyDocument: aws.String(policyDocument),
 	})
 	if err != nil {
 		return nil, err
@@ -430,7 +514,9 @@ func (r *AWSFederatedAccountAccessReconciler) createIAMPolicy(awsClient awsclien
 	return output.Policy, nil
 }
 
-func (r *AWSFederatedAccountAccessReconciler) createIAMRole(awsClient awsclient.Client, afr awsv1alpha1.AWSFederatedRole, afaa awsv1alpha1.AWSFederatedAccountAccess) (*iam.Role, error) {
+// buildAssumeRolePolicyDocument returns the trust policy of the federated role, allowing the external
+// customer IAM ARN of the AWSFederatedAccountAccess to assume it
+func buildAssumeRolePolicyDocument(afaa awsv1alpha1.AWSFederatedAccountAccess) (string, error) {
 	type awsStatement struct {
 		Effect    string                 `json:"Effect"`
 		Action    []string               `json:"Action"`
@@ -454,6 +540,14 @@ func (r *AWSFederatedAccountAccessReconciler) createIAMRole(awsClient awsclient.
 
 	// Marshal assumeRolePolicyDoc to json
 	jsonAssumeRolePolicyDoc, err := json.Marshal(&assumeRolePolicyDoc)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonAssumeRolePolicyDoc), nil
+}
+
+func (r *AWSFederatedAccountAccessReconciler) createIAMRole(awsClient awsclient.Client, afr awsv1alpha1.AWSFederatedRole, afaa awsv1alpha1.AWSFederatedAccountAccess) (*iam.Role, error) {
+	jsonAssumeRolePolicyDoc, err := buildAssumeRolePolicyDocument(afaa)
 	if err != nil {
 		return nil, err
 	}
@@ -470,7 +564,7 @@ func (r *AWSFederatedAccountAccessReconciler) createIAMRole(awsClient awsclient.
 	createRoleOutput, err := awsClient.CreateRole(&iam.CreateRoleInput{
 		RoleName:                 aws.String(roleName),
 		Description:              aws.String(afr.Spec.RoleDescription),
-		AssumeRolePolicyDocument: aws.String(string(jsonAssumeRolePolicyDoc)),
+		AssumeRolePolicyDocument: aws.String(jsonAssumeRolePolicyDoc),
 	})
 	if err != nil {
 		return nil, err
@@ -491,22 +585,27 @@ func (r *AWSFederatedAccountAccessReconciler) createOrUpdateIAMPolicy(awsClient
 		return err
 	}
 
-	customPolArns := createPolicyArns(*gciOut.Account, []string{afr.Spec.AWSCustomPolicy.Name + "-" + uidLabel}, false)
-
-	_, err = r.createIAMPolicy(awsClient, afr, afaa)
+	policyNames, policyDocuments, err := customPolicyParts(afr, uidLabel)
 	if err != nil {
-		if aerr, ok := err.(awserr.Error); ok {
-			if aerr.Code() == "EntityAlreadyExists" {
-				policyName := afr.Spec.AWSCustomPolicy.Name + "-" + uidLabel
-				err = checkAndDeletePolicy(awsClient, uidLabel, afr.Spec.AWSCustomPolicy.Name, &policyName, &customPolArns[0])
-				if err != nil {
-					return err
-				}
-				_, err = r.createIAMPolicy(awsClient, afr, afaa)
-				if err != nil {
-					return err
-				}
+		return err
+	}
+	customPolArns := createPolicyArns(*gciOut.Account, policyNames, false)
 
+	for i, policyName := range policyNames {
+		_, err = createCustomPolicyPart(awsClient, afr, policyName, policyDocuments[i])
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok {
+				if aerr.Code() == "EntityAlreadyExists" {
+					err = deleteCustomPolicy(awsClient, &customPolArns[i])
+					if err != nil {
+						return err
+					}
+					_, err = createCustomPolicyPart(awsClient, afr, policyName, policyDocuments[i])
+					if err != nil {
+						return err
+					}
+
+				}
 			}
 		}
 	}
@@ -684,12 +783,16 @@ func (r *AWSFederatedAccountAccessReconciler) cleanFederatedRoles(reqLogger logr
 		AwsCredsSecretAccessKey: *assumeRoleOutput.Credentials.SecretAccessKey,
 		AwsToken:                *assumeRoleOutput.Credentials.SessionToken,
 		AwsRegion:               awsRegion,
+		AwsAccountID:            accountIDLabel,
 	})
 	if err != nil {
 		reqLogger.Error(err, "Unable to create aws client for target linked account in region ")
 		return err
 	}
 
+	// The parts of the custom policy, which are deleted along with the role
+	customPolicies := customPolicyArnSet(currentFAA, federatedRoleCR, accountIDLabel, uidLabel)
+
 	var nextMarker *string
 
 	// Paginate through attached policies and attempt to remove them
@@ -738,7 +841,11 @@ func (r *AWSFederatedAccountAccessReconciler) cleanFederatedRoles(reqLogger logr
 				}
 			}
 
-			err = checkAndDeletePolicy(awsClient, uidLabel, federatedRoleCR.Spec.AWSCustomPolicy.Name, attachedPolicy.PolicyName, attachedPolicy.PolicyArn)
+			if customPolicies[aws.StringValue(attachedPolicy.PolicyArn)] {
+				err = deleteCustomPolicy(awsClient, attachedPolicy.PolicyArn)
+			} else {
+				err = checkAndDeletePolicy(awsClient, uidLabel, federatedRoleCR.Spec.AWSCustomPolicy.Name, attachedPolicy.PolicyName, attachedPolicy.PolicyArn)
+			}
 			if err != nil {
 				return err
 			}
@@ -751,6 +858,11 @@ func (r *AWSFederatedAccountAccessReconciler) cleanFederatedRoles(reqLogger logr
 		}
 	}
 
+	// Delete the inline policies, e.g. the one revoking the sessions of an expired access, as they block the deletion
+	if _, err = deleteInlinePolicies(reqLogger, awsClient, roleName); err != nil {
+		return err
+	}
+
 	// Delete the role
 	reqLogger.Info("Deleting Role")
 	_, err = awsClient.DeleteRole(&iam.DeleteRoleInput{RoleName: aws.String(roleName)})
@@ -778,6 +890,8 @@ func (r *AWSFederatedAccountAccessReconciler) deleteNonAttachedCustomPolicy(reqL
 		return errors.New("Unable to get UID label")
 	}
 
+	customPolicies := customPolicyArnSet(currentFAA, federatedRoleCR, currentFAA.Labels[awsv1alpha1.AccountIDLabel], uidLabel)
+
 	var policyMarker *string
 	// Paginate through custom policies
 	for {
@@ -794,7 +908,11 @@ func (r *AWSFederatedAccountAccessReconciler) deleteNonAttachedCustomPolicy(reqL
 		}
 
 		for _, policy := range policyListOutput.Policies {
-			err = checkAndDeletePolicy(awsClient, uidLabel, federatedRoleCR.Spec.AWSCustomPolicy.Name, policy.PolicyName, policy.Arn)
+			if customPolicies[aws.StringValue(policy.Arn)] {
+				err = deleteCustomPolicy(awsClient, policy.Arn)
+			} else {
+				err = checkAndDeletePolicy(awsClient, uidLabel, federatedRoleCR.Spec.AWSCustomPolicy.Name, policy.PolicyName, policy.Arn)
+			}
 			if err != nil {
 				return err
 			}
@@ -823,30 +941,7 @@ func checkAndDeletePolicy(awsClient awsclient.Client, uidLabel string, crPolicyN
 	awsCustomPolicyname := getPolicyNameWithUID(crPolicyName, uidLabel)
 
 	if *policyName == awsCustomPolicyname {
-		policyVersions, err := awsClient.ListPolicyVersions(&iam.ListPolicyVersionsInput{PolicyArn: policyArn})
-		if err != nil {
-			return err
-		}
-
-		for _, policyVersion := range policyVersions.Versions {
-			if !*policyVersion.IsDefaultVersion {
-				if _, err = awsClient.DeletePolicyVersion(&iam.DeletePolicyVersionInput{VersionId: policyVersion.VersionId, PolicyArn: policyArn}); err != nil {
-					return err
-				}
-			}
-		}
-
-		_, err = awsClient.DeletePolicy(&iam.DeletePolicyInput{PolicyArn: policyArn})
-		if err != nil {
-			if aerr, ok := err.(awserr.Error); ok {
-				switch aerr.Code() {
-				default:
-					return err
-				}
-			} else {
-				return err
-			}
-		}
+		return deleteCustomPolicy(awsClient, policyArn)
 	}
 	return nil
 }
@@ -858,9 +953,29 @@ func getPolicyNameWithUID(crPolicyName string, uidLabel string) string {
 	return crPolicyName
 }
 
+// accountAccessesForRole returns the account accesses of an AWSFederatedRole, whose roles are repaired as soon as it
+// changes
+func (r *AWSFederatedAccountAccessReconciler) accountAccessesForRole(object client.Object) []reconcile.Request {
+	accountAccesses := &awsv1alpha1.AWSFederatedAccountAccessList{}
+	if err := r.Client.List(context.TODO(), accountAccesses); err != nil {
+		log.Error(err, "Failed to list the account accesses of AWSFederatedRole", "role", object.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, accountAccess := range accountAccesses.Items {
+		role := accountAccess.Spec.AWSFederatedRole
+		if role.Name == object.GetName() && role.Namespace == object.GetNamespace() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: accountAccess.Name, Namespace: accountAccess.Namespace}})
+		}
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *AWSFederatedAccountAccessReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.awsClientBuilder = &awsclient.Builder{}
+	r.httpClient = &http.Client{Timeout: federationClientTimeout}
 	maxReconciles, err := controllerutils.GetControllerMaxReconciles(controllerName)
 	if err != nil {
 		log.Error(err, "missing max reconciles for controller", "controller", controllerName)
@@ -868,7 +983,8 @@ func (r *AWSFederatedAccountAccessReconciler) SetupWithManager(mgr ctrl.Manager)
 
 	rwm := controllerutils.NewReconcilerWithMetrics(r, controllerName)
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&awsv1alpha1.AWSFederatedAccountAccess{}).
+		For(&awsv1alpha1.AWSFederatedAccountAccess{}, builder.WithPredicates(controllerutils.SpecOrMetadataChanged)).
+		Watches(&source.Kind{Type: &awsv1alpha1.AWSFederatedRole{}}, handler.EnqueueRequestsFromMapFunc(r.accountAccessesForRole), builder.WithPredicates(controllerutils.SpecOrMetadataChanged)).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxReconciles,
 		}).Complete(rwm)
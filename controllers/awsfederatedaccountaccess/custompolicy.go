@@ -0,0 +1,97 @@
+package awsfederatedaccountaccess
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// customPolicyParts returns the names and documents of the custom policies created for the role of an
+// account access. Custom policies too large for a single customer managed policy are split in parts.
+func customPolicyParts(afr awsv1alpha1.AWSFederatedRole, uid string) ([]string, []string, error) {
+	documents, err := controllerutils.SplitIAMPolicy(afr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := make([]string, 0, len(documents))
+	for i := range documents {
+		names = append(names, controllerutils.CustomPolicyPartName(afr.Spec.AWSCustomPolicy.Name, i)+"-"+uid)
+	}
+	return names, documents, nil
+}
+
+// customPolicyArns returns the ARNs of the parts of the custom policy of the role in the account
+func customPolicyArns(accountID string, afr awsv1alpha1.AWSFederatedRole, uid string) ([]string, error) {
+	names, _, err := customPolicyParts(afr, uid)
+	if err != nil {
+		return nil, err
+	}
+	return createPolicyArns(accountID, names, false), nil
+}
+
+// currentCustomPolicyArns returns the ARNs of the custom policy parts created for the account access. Account
+// accesses created before custom policies were split don't track them and have a single part.
+func currentCustomPolicyArns(currentFAA *awsv1alpha1.AWSFederatedAccountAccess, afr awsv1alpha1.AWSFederatedRole, accountID string, uid string) []string {
+	if len(currentFAA.Status.CustomPolicyArns) > 0 {
+		return currentFAA.Status.CustomPolicyArns
+	}
+	return createPolicyArns(accountID, []string{afr.Spec.AWSCustomPolicy.Name + "-" + uid}, false)
+}
+
+// detachRolePolicies detaches the policies from the role, ignoring the ones that aren't attached
+func detachRolePolicies(awsClient awsclient.Client, roleName string, policyArns []string) error {
+	for _, policyArn := range policyArns {
+		_, err := awsClient.DetachRolePolicy(&iam.DetachRolePolicyInput{
+			PolicyArn: aws.String(policyArn),
+			RoleName:  aws.String(roleName),
+		})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteCustomPolicy deletes a custom policy along with its non-default versions
+func deleteCustomPolicy(awsClient awsclient.Client, policyArn *string) error {
+	policyVersions, err := awsClient.ListPolicyVersions(&iam.ListPolicyVersionsInput{PolicyArn: policyArn})
+	if err != nil {
+		return err
+	}
+
+	for _, policyVersion := range policyVersions.Versions {
+		if !*policyVersion.IsDefaultVersion {
+			if _, err = awsClient.DeletePolicyVersion(&iam.DeletePolicyVersionInput{VersionId: policyVersion.VersionId, PolicyArn: policyArn}); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = awsClient.DeletePolicy(&iam.DeletePolicyInput{PolicyArn: policyArn})
+	return err
+}
+
+// customPolicyArnSet returns the ARNs of all the custom policy parts of the account access, the ones it
+// tracks and the ones its AWSFederatedRole currently needs
+func customPolicyArnSet(currentFAA *awsv1alpha1.AWSFederatedAccountAccess, afr *awsv1alpha1.AWSFederatedRole, accountID string, uid string) map[string]bool {
+	arns := map[string]bool{}
+	for _, arn := range currentFAA.Status.CustomPolicyArns {
+		arns[arn] = true
+	}
+	if afr == nil {
+		return arns
+	}
+	if expected, err := customPolicyArns(accountID, *afr, uid); err == nil {
+		for _, arn := range expected {
+			arns[arn] = true
+		}
+	}
+	return arns
+}
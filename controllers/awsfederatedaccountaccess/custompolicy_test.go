@@ -0,0 +1,82 @@
+package awsfederatedaccountaccess
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newLargeFederatedRole returns a role whose custom policy doesn't fit in a single customer managed policy
+func newLargeFederatedRole() awsv1alpha1.AWSFederatedRole {
+	statements := []awsv1alpha1.StatementEntry{}
+	for i := 0; i < 30; i++ {
+		statement := awsv1alpha1.StatementEntry{Effect: "Allow", Resource: []string{"*"}}
+		for j := 0; j < 20; j++ {
+			statement.Action = append(statement.Action, fmt.Sprintf("service%d:Action%d", i, j))
+		}
+		statements = append(statements, statement)
+	}
+	return awsv1alpha1.AWSFederatedRole{
+		Spec: awsv1alpha1.AWSFederatedRoleSpec{
+			AWSCustomPolicy: awsv1alpha1.AWSCustomPolicy{Name: "large", Description: "large policy", Statements: statements},
+		},
+	}
+}
+
+func TestCustomPolicyArns(t *testing.T) {
+	arns, err := customPolicyArns("123456789012", newLargeFederatedRole(), "abc123")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"arn:aws:iam::123456789012:policy/large-abc123",
+		"arn:aws:iam::123456789012:policy/large-2-abc123",
+		"arn:aws:iam::123456789012:policy/large-3-abc123",
+	}, arns)
+}
+
+func TestCurrentCustomPolicyArns(t *testing.T) {
+	afr := newLargeFederatedRole()
+
+	// Account accesses created before custom policies were split have a single part
+	legacyFAA := &awsv1alpha1.AWSFederatedAccountAccess{}
+	assert.Equal(t, []string{"arn:aws:iam::123456789012:policy/large-abc123"}, currentCustomPolicyArns(legacyFAA, afr, "123456789012", "abc123"))
+
+	trackedFAA := &awsv1alpha1.AWSFederatedAccountAccess{
+		Status: awsv1alpha1.AWSFederatedAccountAccessStatus{CustomPolicyArns: []string{"arn:aws:iam::123456789012:policy/large-abc123", "arn:aws:iam::123456789012:policy/large-2-abc123"}},
+	}
+	assert.Equal(t, trackedFAA.Status.CustomPolicyArns, currentCustomPolicyArns(trackedFAA, afr, "123456789012", "abc123"))
+}
+
+func TestCreateOrUpdateIAMPolicyParts(t *testing.T) {
+	mocks := setupDefaultMocks(t)
+	mockAWSClient := mock.NewMockClient(mocks.mockCtrl)
+	defer mocks.mockCtrl.Finish()
+
+	afr := newLargeFederatedRole()
+	afaa := awsv1alpha1.AWSFederatedAccountAccess{
+		ObjectMeta: v1.ObjectMeta{Labels: map[string]string{awsv1alpha1.UIDLabel: "abc123"}},
+	}
+
+	mockAWSClient.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{Account: aws.String("123456789012")}, nil)
+	for _, policyName := range []string{"large-abc123", "large-2-abc123", "large-3-abc123"} {
+		mockAWSClient.EXPECT().CreatePolicy(gomock.AssignableToTypeOf(&iam.CreatePolicyInput{})).DoAndReturn(
+			func(name string) func(*iam.CreatePolicyInput) (*iam.CreatePolicyOutput, error) {
+				return func(input *iam.CreatePolicyInput) (*iam.CreatePolicyOutput, error) {
+					assert.Equal(t, name, aws.StringValue(input.PolicyName))
+					return &iam.CreatePolicyOutput{Policy: &iam.Policy{}}, nil
+				}
+			}(policyName),
+		)
+	}
+
+	r := AWSFederatedAccountAccessReconciler{}
+	err := r.createOrUpdateIAMPolicy(mockAWSClient, afr, afaa)
+	assert.NoError(t, err)
+}
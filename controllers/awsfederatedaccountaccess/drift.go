@@ -0,0 +1,233 @@
+package awsfederatedaccountaccess
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultRoleResyncInterval is how often the roles of Ready account accesses are checked for drift unless the
+// configmap says otherwise. An interval of 0 only checks them when the CRs change.
+const defaultRoleResyncInterval = time.Hour
+
+// The parts of a federated role that can drift from its AWSFederatedRole, as counted by the repair metric
+const (
+	driftRole             = "role"
+	driftTrustPolicy      = "trust_policy"
+	driftAttachedPolicies = "attached_policies"
+	driftInlinePolicies   = "inline_policies"
+	driftCustomPolicy     = "custom_policy"
+)
+
+// getRoleResyncInterval reads the role drift check interval from the configmap, falling back to the default
+// when it's missing or invalid
+func getRoleResyncInterval(reqLogger logr.Logger, kubeClient client.Client) time.Duration {
+	configMap, err := controllerutils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		reqLogger.Error(err, "Unable to get the federated role resync interval from the configmap, using the default")
+		return defaultRoleResyncInterval
+	}
+
+	value, ok := configMap.Data[awsv1alpha1.FederatedRoleResyncIntervalConfigMapKey]
+	if !ok || value == "" {
+		return defaultRoleResyncInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		reqLogger.Error(err, "Invalid federated role resync interval in configmap, using the default", "value", value)
+		return defaultRoleResyncInterval
+	}
+	return interval
+}
+
+// repairRoleDrift compares the role of the account access with its AWSFederatedRole and repairs the
+// differences: a deleted role is recreated, the trust policy is reset, missing policies are attached,
+// unexpected ones detached and inline policies, which the operator never adds, are deleted
+func (r *AWSFederatedAccountAccessReconciler) repairRoleDrift(reqLogger logr.Logger, awsClient awsclient.Client, requestedRole *awsv1alpha1.AWSFederatedRole, currentFAA *awsv1alpha1.AWSFederatedAccountAccess) error {
+	uid, ok := currentFAA.Labels[awsv1alpha1.UIDLabel]
+	if !ok {
+		return errors.New("FederatedAccountAccess has no uid label")
+	}
+	roleName := fmt.Sprintf("%s-%s", requestedRole.Name, uid)
+	accountID := currentFAA.Labels[awsv1alpha1.AccountIDLabel]
+
+	expectedTrustPolicy, err := buildAssumeRolePolicyDocument(*currentFAA)
+	if err != nil {
+		return err
+	}
+
+	roleOutput, err := awsClient.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
+			return err
+		}
+		reqLogger.Info(fmt.Sprintf("Role %s was deleted, recreating it", roleName))
+		_, err = r.createIAMRole(awsClient, *requestedRole, *currentFAA)
+		if err != nil {
+			return err
+		}
+		localmetrics.Collector.AddFederatedRoleRepair(driftRole)
+	} else {
+		liveTrustPolicy, err := url.QueryUnescape(aws.StringValue(roleOutput.Role.AssumeRolePolicyDocument))
+		if err != nil {
+			return err
+		}
+		if !policyDocumentsEqual(liveTrustPolicy, expectedTrustPolicy) {
+			reqLogger.Info(fmt.Sprintf("Resetting the drifted trust policy of role %s", roleName))
+			_, err = awsClient.UpdateAssumeRolePolicy(&iam.UpdateAssumeRolePolicyInput{
+				RoleName:       aws.String(roleName),
+				PolicyDocument: aws.String(expectedTrustPolicy),
+			})
+			if err != nil {
+				return err
+			}
+			localmetrics.Collector.AddFederatedRoleRepair(driftTrustPolicy)
+		}
+	}
+
+	expectedPolicyArns := createPolicyArns(accountID, requestedRole.Spec.AWSManagedPolicies, true)
+	customArns, err := customPolicyArns(accountID, *requestedRole, uid)
+	if err != nil {
+		return err
+	}
+	expectedPolicyArns = append(expectedPolicyArns, customArns...)
+	repaired, err := r.repairAttachedPolicies(reqLogger, awsClient, roleName, expectedPolicyArns)
+	if err != nil {
+		return err
+	}
+	if repaired {
+		localmetrics.Collector.AddFederatedRoleRepair(driftAttachedPolicies)
+	}
+
+	repaired, err = deleteInlinePolicies(reqLogger, awsClient, roleName)
+	if err != nil {
+		return err
+	}
+	if repaired {
+		localmetrics.Collector.AddFederatedRoleRepair(driftInlinePolicies)
+	}
+	return nil
+}
+
+// repairAttachedPolicies attaches the expected policies missing from the role and detaches the others.
+// Returns true if anything changed.
+func (r *AWSFederatedAccountAccessReconciler) repairAttachedPolicies(reqLogger logr.Logger, awsClient awsclient.Client, roleName string, expectedPolicyArns []string) (bool, error) {
+	attached := map[string]bool{}
+	var marker *string
+	for {
+		output, err := awsClient.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{RoleName: aws.String(roleName), Marker: marker})
+		if err != nil {
+			return false, err
+		}
+		for _, policy := range output.AttachedPolicies {
+			attached[aws.StringValue(policy.PolicyArn)] = true
+		}
+		if !aws.BoolValue(output.IsTruncated) {
+			break
+		}
+		marker = output.Marker
+	}
+
+	expected := map[string]bool{}
+	var missing []string
+	for _, policyArn := range expectedPolicyArns {
+		expected[policyArn] = true
+		if !attached[policyArn] {
+			missing = append(missing, policyArn)
+		}
+	}
+	if len(missing) > 0 {
+		reqLogger.Info(fmt.Sprintf("Attaching missing policies %v to role %s", missing, roleName))
+		if err := r.attachIAMPolices(awsClient, roleName, missing); err != nil {
+			return false, err
+		}
+	}
+
+	repaired := len(missing) > 0
+	for policyArn := range attached {
+		if expected[policyArn] {
+			continue
+		}
+		reqLogger.Info(fmt.Sprintf("Detaching unexpected policy %s from role %s", policyArn, roleName))
+		_, err := awsClient.DetachRolePolicy(&iam.DetachRolePolicyInput{RoleName: aws.String(roleName), PolicyArn: aws.String(policyArn)})
+		if err != nil {
+			return false, err
+		}
+		repaired = true
+	}
+	return repaired, nil
+}
+
+// deleteInlinePolicies deletes the inline policies of the role. Returns true if there were any.
+func deleteInlinePolicies(reqLogger logr.Logger, awsClient awsclient.Client, roleName string) (bool, error) {
+	var policyNames []*string
+	var marker *string
+	for {
+		output, err := awsClient.ListRolePolicies(&iam.ListRolePoliciesInput{RoleName: aws.String(roleName), Marker: marker})
+		if err != nil {
+			return false, err
+		}
+		policyNames = append(policyNames, output.PolicyNames...)
+		if !aws.BoolValue(output.IsTruncated) {
+			break
+		}
+		marker = output.Marker
+	}
+
+	for _, policyName := range policyNames {
+		reqLogger.Info(fmt.Sprintf("Deleting unexpected inline policy %s of role %s", aws.StringValue(policyName), roleName))
+		_, err := awsClient.DeleteRolePolicy(&iam.DeleteRolePolicyInput{RoleName: aws.String(roleName), PolicyName: policyName})
+		if err != nil {
+			return false, err
+		}
+	}
+	return len(policyNames) > 0, nil
+}
+
+// policyDocumentsEqual compares two IAM policy documents. AWS returns single-element lists as a plain value,
+// e.g. "Action": "sts:AssumeRole", so those are considered equal to the list.
+func policyDocumentsEqual(a, b string) bool {
+	var docA, docB interface{}
+	if err := json.Unmarshal([]byte(a), &docA); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(b), &docB); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(normalizePolicyValue(docA), normalizePolicyValue(docB))
+}
+
+// normalizePolicyValue replaces the single-element lists of a decoded policy document with their element
+func normalizePolicyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		if len(v) == 1 {
+			return normalizePolicyValue(v[0])
+		}
+		normalized := make([]interface{}, len(v))
+		for i := range v {
+			normalized[i] = normalizePolicyValue(v[i])
+		}
+		return normalized
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized[key] = normalizePolicyValue(item)
+		}
+		return normalized
+	}
+	return value
+}
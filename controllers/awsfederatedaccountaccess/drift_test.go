@@ -0,0 +1,153 @@
+package awsfederatedaccountaccess
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestPolicyDocumentsEqual(t *testing.T) {
+	expected := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["sts:AssumeRole"],"Principal":{"AWS":["arn:aws:iam::123456789012:user/sre"]}}]}`
+	tests := []struct {
+		name     string
+		live     string
+		expected bool
+	}{
+		{
+			name:     "Document as returned by AWS",
+			live:     `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:user/sre"},"Action":"sts:AssumeRole"}]}`,
+			expected: true,
+		},
+		{
+			name:     "Principal added by hand",
+			live:     `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:user/sre","arn:aws:iam::210987654321:root"]},"Action":"sts:AssumeRole"}]}`,
+			expected: false,
+		},
+		{
+			name:     "Invalid document",
+			live:     `{`,
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, policyDocumentsEqual(tt.live, expected))
+		})
+	}
+}
+
+func TestRepairRoleDrift(t *testing.T) {
+	localmetrics.Collector = localmetrics.NewMetricsCollector(nil)
+
+	federatedRole := &awsv1alpha1.AWSFederatedRole{
+		ObjectMeta: v1.ObjectMeta{Name: "read-only"},
+		Spec: awsv1alpha1.AWSFederatedRoleSpec{
+			AWSCustomPolicy:    awsv1alpha1.AWSCustomPolicy{Name: "custom"},
+			AWSManagedPolicies: []string{"ReadOnlyAccess"},
+		},
+	}
+	accountAccess := &awsv1alpha1.AWSFederatedAccountAccess{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   "access",
+			Labels: map[string]string{awsv1alpha1.UIDLabel: "abc123", awsv1alpha1.AccountIDLabel: "111111111111"},
+		},
+		Spec: awsv1alpha1.AWSFederatedAccountAccessSpec{ExternalCustomerAWSIAMARN: "arn:aws:iam::123456789012:user/sre"},
+	}
+	trustPolicy, err := buildAssumeRolePolicyDocument(*accountAccess)
+	assert.NoError(t, err)
+
+	roleName := aws.String("read-only-abc123")
+	managedArn := "arn:aws:iam::aws:policy/ReadOnlyAccess"
+	customArn := "arn:aws:iam::111111111111:policy/custom-abc123"
+
+	tests := []struct {
+		name   string
+		setupf func(*mock.MockClient)
+	}{
+		{
+			name: "Role in sync",
+			setupf: func(m *mock.MockClient) {
+				m.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{Role: &iam.Role{AssumeRolePolicyDocument: aws.String(url.QueryEscape(trustPolicy))}}, nil)
+				m.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{
+					AttachedPolicies: []*iam.AttachedPolicy{{PolicyArn: aws.String(managedArn)}, {PolicyArn: aws.String(customArn)}},
+				}, nil)
+				m.EXPECT().ListRolePolicies(gomock.Any()).Return(&iam.ListRolePoliciesOutput{}, nil)
+			},
+		},
+		{
+			name: "Drifted trust policy, attached and inline policies",
+			setupf: func(m *mock.MockClient) {
+				m.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{Role: &iam.Role{AssumeRolePolicyDocument: aws.String(`{"Version":"2012-10-17","Statement":[]}`)}}, nil)
+				m.EXPECT().UpdateAssumeRolePolicy(&iam.UpdateAssumeRolePolicyInput{RoleName: roleName, PolicyDocument: aws.String(trustPolicy)}).Return(&iam.UpdateAssumeRolePolicyOutput{}, nil)
+				m.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{
+					AttachedPolicies: []*iam.AttachedPolicy{{PolicyArn: aws.String(customArn)}, {PolicyArn: aws.String("arn:aws:iam::aws:policy/AdministratorAccess")}},
+				}, nil)
+				m.EXPECT().AttachRolePolicy(&iam.AttachRolePolicyInput{RoleName: roleName, PolicyArn: aws.String(managedArn)}).Return(&iam.AttachRolePolicyOutput{}, nil)
+				m.EXPECT().DetachRolePolicy(&iam.DetachRolePolicyInput{RoleName: roleName, PolicyArn: aws.String("arn:aws:iam::aws:policy/AdministratorAccess")}).Return(&iam.DetachRolePolicyOutput{}, nil)
+				m.EXPECT().ListRolePolicies(gomock.Any()).Return(&iam.ListRolePoliciesOutput{PolicyNames: aws.StringSlice([]string{"added-by-hand"})}, nil)
+				m.EXPECT().DeleteRolePolicy(&iam.DeleteRolePolicyInput{RoleName: roleName, PolicyName: aws.String("added-by-hand")}).Return(&iam.DeleteRolePolicyOutput{}, nil)
+			},
+		},
+		{
+			name: "Deleted role",
+			setupf: func(m *mock.MockClient) {
+				m.EXPECT().GetRole(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "role not found", nil))
+				m.EXPECT().CreateRole(gomock.Any()).Return(&iam.CreateRoleOutput{Role: &iam.Role{RoleName: roleName}}, nil)
+				m.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{}, nil)
+				m.EXPECT().AttachRolePolicy(gomock.Any()).Return(&iam.AttachRolePolicyOutput{}, nil).Times(2)
+				m.EXPECT().ListRolePolicies(gomock.Any()).Return(&iam.ListRolePoliciesOutput{}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := setupDefaultMocks(t)
+			defer mocks.mockCtrl.Finish()
+			mockAWSClient := mock.NewMockClient(mocks.mockCtrl)
+			tt.setupf(mockAWSClient)
+
+			r := &AWSFederatedAccountAccessReconciler{}
+			err := r.repairRoleDrift(testutils.NewTestLogger().Logger(), mockAWSClient, federatedRole, accountAccess)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAccountAccessesForRole(t *testing.T) {
+	assert.NoError(t, apis.AddToScheme(scheme.Scheme))
+
+	accountAccess := func(name string, role string, roleNamespace string) *awsv1alpha1.AWSFederatedAccountAccess {
+		return &awsv1alpha1.AWSFederatedAccountAccess{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "aws-account-operator"},
+			Spec: awsv1alpha1.AWSFederatedAccountAccessSpec{
+				AWSFederatedRole: awsv1alpha1.AWSFederatedRoleRef{Name: role, Namespace: roleNamespace},
+			},
+		}
+	}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		accountAccess("sre-read-only", "read-only", "aws-account-operator"),
+		accountAccess("sre-admin", "admin", "aws-account-operator"),
+		accountAccess("other-read-only", "read-only", "other-namespace"),
+	).Build()
+	r := &AWSFederatedAccountAccessReconciler{Client: kubeClient}
+
+	role := &awsv1alpha1.AWSFederatedRole{ObjectMeta: v1.ObjectMeta{Name: "read-only", Namespace: "aws-account-operator"}}
+	assert.Equal(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "sre-read-only", Namespace: "aws-account-operator"}},
+	}, r.accountAccessesForRole(role))
+}
@@ -0,0 +1,123 @@
+package awsfederatedaccountaccess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// expiredTrustPolicy replaces the trust policy of the role of an expired account access so nobody can assume it
+const expiredTrustPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Principal":{"AWS":"*"},"Action":"sts:AssumeRole"}]}`
+
+// revokeSessionsPolicyName is the inline policy denying the sessions of the role issued before it expired.
+// It's the name the AWS console uses when revoking the active sessions of a role.
+const revokeSessionsPolicyName = "AWSRevokeOlderSessions"
+
+// buildRevokeSessionsPolicyDocument returns a policy denying everything to the sessions issued before the given time
+func buildRevokeSessionsPolicyDocument(issuedBefore time.Time) (string, error) {
+	policyDoc := map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{{
+			"Effect":   "Deny",
+			"Action":   []string{"*"},
+			"Resource": []string{"*"},
+			"Condition": map[string]interface{}{
+				"DateLessThan": map[string]string{"aws:TokenIssueTime": issuedBefore.UTC().Format(time.RFC3339)},
+			},
+		}},
+	}
+	jsonPolicyDoc, err := json.Marshal(policyDoc)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonPolicyDoc), nil
+}
+
+// revokeRoleAccess removes the trust of the role of the account access and revokes its active sessions.
+// A role that doesn't exist has nothing to revoke.
+func revokeRoleAccess(reqLogger logr.Logger, awsClient awsclient.Client, roleName string, now time.Time) error {
+	reqLogger.Info(fmt.Sprintf("Removing the trust policy of role %s", roleName))
+	_, err := awsClient.UpdateAssumeRolePolicy(&iam.UpdateAssumeRolePolicyInput{
+		RoleName:       aws.String(roleName),
+		PolicyDocument: aws.String(expiredTrustPolicy),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+			reqLogger.Info(fmt.Sprintf("Role %s doesn't exist, nothing to revoke", roleName))
+			return nil
+		}
+		return err
+	}
+
+	revokeSessionsPolicy, err := buildRevokeSessionsPolicyDocument(now)
+	if err != nil {
+		return err
+	}
+	_, err = awsClient.PutRolePolicy(&iam.PutRolePolicyInput{
+		RoleName:       aws.String(roleName),
+		PolicyName:     aws.String(revokeSessionsPolicyName),
+		PolicyDocument: aws.String(revokeSessionsPolicy),
+	})
+	return err
+}
+
+// expireAccountAccess revokes the access granted by an expired account access and marks it Expired. Its role
+// and policies are kept until the CR is deleted, so what was granted can still be inspected.
+func (r *AWSFederatedAccountAccessReconciler) expireAccountAccess(reqLogger logr.Logger, awsClient awsclient.Client, currentFAA *awsv1alpha1.AWSFederatedAccountAccess) (reconcile.Result, error) {
+	if uid, ok := currentFAA.Labels[awsv1alpha1.UIDLabel]; ok {
+		roleName := currentFAA.Spec.AWSFederatedRole.Name + "-" + uid
+		if err := revokeRoleAccess(reqLogger, awsClient, roleName, time.Now()); err != nil {
+			reqLogger.Error(err, fmt.Sprintf("Failed to revoke the access of expired account access %s/%s", currentFAA.Namespace, currentFAA.Name))
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Console sessions opened before the expiration are revoked with the role, but the sign-in URL goes too
+	if err := r.deleteSigninURL(currentFAA); err != nil {
+		reqLogger.Error(err, fmt.Sprintf("Failed to delete the console sign-in URL of expired account access %s/%s", currentFAA.Namespace, currentFAA.Name))
+		return reconcile.Result{}, err
+	}
+
+	expirationTime, _ := currentFAA.ExpirationTime()
+	SetStatuswithCondition(currentFAA, fmt.Sprintf("Account access expired at %s", expirationTime.UTC().Format(time.RFC3339)), awsv1alpha1.AWSFederatedAccountExpired, awsv1alpha1.AWSFederatedAccountStateExpired)
+	reqLogger.Info(fmt.Sprintf("Account access %s/%s expired", currentFAA.Namespace, currentFAA.Name))
+	err := r.Client.Status().Update(context.TODO(), currentFAA)
+	if err != nil {
+		reqLogger.Error(err, fmt.Sprintf("Status update for %s failed", currentFAA.Name))
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// untilExpiration returns how long until the account access expires, and false if it doesn't expire
+func untilExpiration(currentFAA *awsv1alpha1.AWSFederatedAccountAccess) (time.Duration, bool) {
+	expirationTime, ok := currentFAA.ExpirationTime()
+	if !ok {
+		return 0, false
+	}
+	return time.Until(expirationTime), true
+}
+
+// requeueBeforeExpiration makes sure the account access is reconciled again when it expires
+func requeueBeforeExpiration(result reconcile.Result, currentFAA *awsv1alpha1.AWSFederatedAccountAccess) reconcile.Result {
+	remaining, ok := untilExpiration(currentFAA)
+	if !ok {
+		return result
+	}
+	if remaining < time.Second {
+		remaining = time.Second
+	}
+	if result.RequeueAfter == 0 || remaining < result.RequeueAfter {
+		result.RequeueAfter = remaining
+	}
+	return result
+}
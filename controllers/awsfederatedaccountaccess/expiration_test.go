@@ -0,0 +1,106 @@
+package awsfederatedaccountaccess
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestRevokeRoleAccess(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	roleName := aws.String("read-only-abc123")
+
+	tests := []struct {
+		name   string
+		setupf func(*mock.MockClient)
+	}{
+		{
+			name: "Role trust removed and sessions revoked",
+			setupf: func(m *mock.MockClient) {
+				m.EXPECT().UpdateAssumeRolePolicy(&iam.UpdateAssumeRolePolicyInput{RoleName: roleName, PolicyDocument: aws.String(expiredTrustPolicy)}).Return(&iam.UpdateAssumeRolePolicyOutput{}, nil)
+				m.EXPECT().PutRolePolicy(&iam.PutRolePolicyInput{
+					RoleName:       roleName,
+					PolicyName:     aws.String(revokeSessionsPolicyName),
+					PolicyDocument: aws.String(`{"Statement":[{"Action":["*"],"Condition":{"DateLessThan":{"aws:TokenIssueTime":"2024-01-02T03:04:05Z"}},"Effect":"Deny","Resource":["*"]}],"Version":"2012-10-17"}`),
+				}).Return(&iam.PutRolePolicyOutput{}, nil)
+			},
+		},
+		{
+			name: "Role already deleted",
+			setupf: func(m *mock.MockClient) {
+				m.EXPECT().UpdateAssumeRolePolicy(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "role not found", nil))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := setupDefaultMocks(t)
+			defer mocks.mockCtrl.Finish()
+			mockAWSClient := mock.NewMockClient(mocks.mockCtrl)
+			tt.setupf(mockAWSClient)
+
+			err := revokeRoleAccess(testutils.NewTestLogger().Logger(), mockAWSClient, *roleName, now)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRequeueBeforeExpiration(t *testing.T) {
+	newAccountAccess := func(expiration *time.Duration) *awsv1alpha1.AWSFederatedAccountAccess {
+		accountAccess := &awsv1alpha1.AWSFederatedAccountAccess{
+			ObjectMeta: v1.ObjectMeta{CreationTimestamp: v1.NewTime(time.Now())},
+		}
+		if expiration != nil {
+			accountAccess.Spec.Expiration = &v1.Duration{Duration: *expiration}
+		}
+		return accountAccess
+	}
+	eightHours := 8 * time.Hour
+
+	tests := []struct {
+		name           string
+		expiration     *time.Duration
+		requeueAfter   time.Duration
+		expectedAtMost time.Duration
+	}{
+		{
+			name:           "Access doesn't expire",
+			requeueAfter:   time.Hour,
+			expectedAtMost: time.Hour,
+		},
+		{
+			name:           "Resync before the expiration",
+			expiration:     &eightHours,
+			requeueAfter:   time.Hour,
+			expectedAtMost: time.Hour,
+		},
+		{
+			name:           "Expiration before the resync",
+			expiration:     &eightHours,
+			requeueAfter:   24 * time.Hour,
+			expectedAtMost: eightHours,
+		},
+		{
+			name:           "Resync disabled",
+			expiration:     &eightHours,
+			expectedAtMost: eightHours,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := requeueBeforeExpiration(reconcile.Result{RequeueAfter: tt.requeueAfter}, newAccountAccess(tt.expiration))
+			assert.LessOrEqual(t, result.RequeueAfter, tt.expectedAtMost)
+			assert.Greater(t, result.RequeueAfter, tt.expectedAtMost-time.Minute)
+		})
+	}
+}
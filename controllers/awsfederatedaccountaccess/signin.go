@@ -0,0 +1,223 @@
+package awsfederatedaccountaccess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
+	controllerutils "github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// The bounds GetFederationToken puts on the duration of the console sessions
+	defaultSigninSessionDuration = time.Hour
+	minSigninSessionDuration     = 15 * time.Minute
+	maxSigninSessionDuration     = 36 * time.Hour
+
+	// signinURLRefreshMargin is how long before the console session expires the sign-in URL is regenerated
+	signinURLRefreshMargin = 5 * time.Minute
+
+	signinURLSecretSuffix = "-console-url"
+	secretConsoleURL      = "aws_console_login_url"
+
+	// maxFederatedUserNameLength is the length limit of the name of a federated user
+	maxFederatedUserNameLength = 32
+
+	federationClientTimeout = 10 * time.Second
+)
+
+// federationEndpoint returns the AWS sign-in federation endpoint of the partition
+var federationEndpoint = stsclient.FederationEndpoint
+
+// signinURLSecretName returns the name of the secret holding the sign-in URL of an account access
+func signinURLSecretName(faaName string) string {
+	return faaName + signinURLSecretSuffix
+}
+
+// signinSessionDuration returns how long the console sessions of the account access last. They never
+// outlive the expiration of the access, as far as the minimum duration allows.
+func signinSessionDuration(currentFAA *awsv1alpha1.AWSFederatedAccountAccess) time.Duration {
+	duration := defaultSigninSessionDuration
+	if currentFAA.Spec.SigninSessionDuration != nil {
+		duration = currentFAA.Spec.SigninSessionDuration.Duration
+	}
+	if remaining, ok := untilExpiration(currentFAA); ok && remaining < duration {
+		duration = remaining
+	}
+	if duration < minSigninSessionDuration {
+		duration = minSigninSessionDuration
+	}
+	if duration > maxSigninSessionDuration {
+		duration = maxSigninSessionDuration
+	}
+	return duration
+}
+
+// federatedUserName returns the name of the federated user of the console sessions of a role, which
+// AWS shows in the console and in CloudTrail
+func federatedUserName(roleName string) string {
+	if len(roleName) > maxFederatedUserNameLength {
+		return roleName[len(roleName)-maxFederatedUserNameLength:]
+	}
+	return roleName
+}
+
+// signinURLExpiration returns when the console session of the sign-in URL in the secret expires, and
+// false if it's unknown
+func signinURLExpiration(secret *corev1.Secret) (time.Time, bool) {
+	expiration, err := time.Parse(time.RFC3339, secret.GetAnnotations()[awsv1alpha1.ConsoleSessionExpiresAtAnnotation])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return expiration, true
+}
+
+// ensureSigninURL publishes a console sign-in URL scoped to the policies of the role of the account access
+// in its secret, regenerating it when its console session is about to expire or when the account access is
+// annotated with RegenerateConsoleURLAnnotation. Returns when the URL has to be regenerated next.
+func (r *AWSFederatedAccountAccessReconciler) ensureSigninURL(reqLogger logr.Logger, awsClient awsclient.Client, requestedRole *awsv1alpha1.AWSFederatedRole, currentFAA *awsv1alpha1.AWSFederatedAccountAccess) (time.Time, error) {
+	uid, ok := currentFAA.Labels[awsv1alpha1.UIDLabel]
+	if !ok {
+		return time.Time{}, errors.New("FederatedAccountAccess has no uid label")
+	}
+	accountID := currentFAA.Labels[awsv1alpha1.AccountIDLabel]
+
+	secret := &corev1.Secret{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: signinURLSecretName(currentFAA.Name), Namespace: currentFAA.Namespace}, secret)
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return time.Time{}, err
+		}
+		secret = nil
+	}
+
+	_, requested := currentFAA.GetAnnotations()[awsv1alpha1.RegenerateConsoleURLAnnotation]
+	if secret != nil && !requested {
+		if expiration, ok := signinURLExpiration(secret); ok && time.Until(expiration) > signinURLRefreshMargin {
+			currentFAA.Status.SigninURLSecret = secret.Name
+			return expiration.Add(-signinURLRefreshMargin), nil
+		}
+	}
+
+	// The federated user gets the intersection of the permissions of the IAM user of the credential secret
+	// and of the policies of the role
+	policyArns := createPolicyArns(accountID, requestedRole.Spec.AWSManagedPolicies, true)
+	customArns, err := customPolicyArns(accountID, *requestedRole, uid)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(requestedRole.Spec.AWSCustomPolicy.Statements) > 0 {
+		policyArns = append(policyArns, customArns...)
+	}
+	policyDescriptors := []*sts.PolicyDescriptorType{}
+	for _, policyArn := range policyArns {
+		policyDescriptors = append(policyDescriptors, &sts.PolicyDescriptorType{Arn: aws.String(policyArn)})
+	}
+
+	tokenOutput, err := awsClient.GetFederationToken(&sts.GetFederationTokenInput{
+		Name:            aws.String(federatedUserName(currentFAA.Spec.AWSFederatedRole.Name + "-" + uid)),
+		DurationSeconds: aws.Int64(int64(signinSessionDuration(currentFAA).Seconds())),
+		PolicyArns:      policyDescriptors,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if tokenOutput.Credentials == nil {
+		return time.Time{}, fmt.Errorf("GetFederationToken returned no credentials")
+	}
+
+	issued := time.Now()
+	signinToken, err := stsclient.GetSigninToken(r.httpClient, federationEndpoint(), tokenOutput.Credentials)
+	if err != nil {
+		return time.Time{}, err
+	}
+	loginURL := stsclient.LoginURL(federationEndpoint(), signinToken)
+
+	sessionExpiration := aws.TimeValue(tokenOutput.Credentials.Expiration)
+	annotations := map[string]string{
+		awsv1alpha1.ConsoleURLExpiresAtAnnotation:     issued.Add(stsclient.SigninTokenValidity).UTC().Format(time.RFC3339),
+		awsv1alpha1.ConsoleSessionExpiresAtAnnotation: sessionExpiration.UTC().Format(time.RFC3339),
+	}
+	reqLogger.Info("Regenerated console sign-in URL", "sessionExpiresAt", annotations[awsv1alpha1.ConsoleSessionExpiresAtAnnotation])
+
+	if secret == nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: signinURLSecretName(currentFAA.Name), Namespace: currentFAA.Namespace, Annotations: annotations},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{secretConsoleURL: []byte(loginURL)},
+		}
+		err = controllerutils.SetSecretOwner(secret, currentFAA, r.Scheme)
+		if err != nil {
+			return time.Time{}, err
+		}
+		err = r.Client.Create(context.TODO(), secret)
+	} else {
+		err = controllerutils.UpdateWithRetry(r.Client, secret, func() error {
+			secret.Data = map[string][]byte{secretConsoleURL: []byte(loginURL)}
+			current := secret.GetAnnotations()
+			if current == nil {
+				current = map[string]string{}
+			}
+			for k, v := range annotations {
+				current[k] = v
+			}
+			secret.SetAnnotations(current)
+			return controllerutils.SetSecretOwner(secret, currentFAA, r.Scheme)
+		})
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if requested {
+		err = controllerutils.UpdateWithRetry(r.Client, currentFAA, func() error {
+			annotations := currentFAA.GetAnnotations()
+			delete(annotations, awsv1alpha1.RegenerateConsoleURLAnnotation)
+			currentFAA.SetAnnotations(annotations)
+			return nil
+		})
+		if err != nil {
+			reqLogger.Error(err, "Failed to remove the console URL regeneration annotation")
+		}
+	}
+
+	currentFAA.Status.SigninURLSecret = secret.Name
+	return sessionExpiration.Add(-signinURLRefreshMargin), nil
+}
+
+// deleteSigninURL deletes the sign-in URL of the account access. Console sessions already opened with it
+// last until they expire.
+func (r *AWSFederatedAccountAccessReconciler) deleteSigninURL(currentFAA *awsv1alpha1.AWSFederatedAccountAccess) error {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: signinURLSecretName(currentFAA.Name), Namespace: currentFAA.Namespace}}
+	err := r.Client.Delete(context.TODO(), secret)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return err
+	}
+	currentFAA.Status.SigninURLSecret = ""
+	return nil
+}
+
+// requeueBeforeSigninRefresh makes sure the account access is reconciled again when its sign-in URL has to
+// be regenerated
+func requeueBeforeSigninRefresh(result reconcile.Result, refreshAt time.Time) reconcile.Result {
+	remaining := time.Until(refreshAt)
+	if remaining < time.Second {
+		remaining = time.Second
+	}
+	if result.RequeueAfter == 0 || remaining < result.RequeueAfter {
+		result.RequeueAfter = remaining
+	}
+	return result
+}
@@ -0,0 +1,181 @@
+package awsfederatedaccountaccess
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSigninSessionDuration(t *testing.T) {
+	newAccountAccess := func(sessionDuration *time.Duration, expiration *time.Duration) *awsv1alpha1.AWSFederatedAccountAccess {
+		accountAccess := &awsv1alpha1.AWSFederatedAccountAccess{
+			ObjectMeta: v1.ObjectMeta{CreationTimestamp: v1.NewTime(time.Now())},
+		}
+		if sessionDuration != nil {
+			accountAccess.Spec.SigninSessionDuration = &v1.Duration{Duration: *sessionDuration}
+		}
+		if expiration != nil {
+			accountAccess.Spec.Expiration = &v1.Duration{Duration: *expiration}
+		}
+		return accountAccess
+	}
+	twoHours := 2 * time.Hour
+	thirtyMinutes := 30 * time.Minute
+	fiveMinutes := 5 * time.Minute
+	week := 7 * 24 * time.Hour
+
+	tests := []struct {
+		name            string
+		sessionDuration *time.Duration
+		expiration      *time.Duration
+		expectedAtMost  time.Duration
+	}{
+		{name: "Default duration", expectedAtMost: time.Hour},
+		{name: "Configured duration", sessionDuration: &twoHours, expectedAtMost: twoHours},
+		{name: "Duration below the minimum", sessionDuration: &fiveMinutes, expectedAtMost: minSigninSessionDuration},
+		{name: "Duration above the maximum", sessionDuration: &week, expectedAtMost: maxSigninSessionDuration},
+		{name: "Access expires before the session", sessionDuration: &twoHours, expiration: &thirtyMinutes, expectedAtMost: thirtyMinutes},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			duration := signinSessionDuration(newAccountAccess(tt.sessionDuration, tt.expiration))
+			assert.LessOrEqual(t, duration, tt.expectedAtMost)
+			assert.Greater(t, duration, tt.expectedAtMost-time.Minute)
+		})
+	}
+}
+
+func TestFederatedUserName(t *testing.T) {
+	assert.Equal(t, "read-only-abc123", federatedUserName("read-only-abc123"))
+	assert.Equal(t, "very-long-federated-role-abc123", federatedUserName("a-very-very-long-federated-role-abc123")[1:])
+	assert.Len(t, federatedUserName("a-very-very-long-federated-role-abc123"), maxFederatedUserNameLength)
+}
+
+func TestEnsureSigninURL(t *testing.T) {
+	assert.NoError(t, apis.AddToScheme(scheme.Scheme))
+
+	federation := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "getSigninToken", r.URL.Query().Get("Action"))
+		_ = json.NewEncoder(w).Encode(map[string]string{"SigninToken": "signin-token"})
+	}))
+	defer federation.Close()
+	previousEndpoint := federationEndpoint
+	federationEndpoint = func() string { return federation.URL + "/federation" }
+	defer func() { federationEndpoint = previousEndpoint }()
+
+	role := &awsv1alpha1.AWSFederatedRole{
+		ObjectMeta: v1.ObjectMeta{Name: "read-only", Namespace: "aws-account-operator"},
+		Spec: awsv1alpha1.AWSFederatedRoleSpec{
+			AWSManagedPolicies: []string{"ReadOnlyAccess"},
+			AWSCustomPolicy: awsv1alpha1.AWSCustomPolicy{
+				Name:       "custom",
+				Statements: []awsv1alpha1.StatementEntry{{Effect: "Allow", Action: []string{"ec2:DescribeInstances"}, Resource: []string{"*"}}},
+			},
+		},
+	}
+	newAccountAccess := func(annotations map[string]string) *awsv1alpha1.AWSFederatedAccountAccess {
+		return &awsv1alpha1.AWSFederatedAccountAccess{
+			ObjectMeta: v1.ObjectMeta{
+				Name:        "sre-access",
+				Namespace:   "aws-account-operator",
+				Annotations: annotations,
+				Labels:      map[string]string{awsv1alpha1.UIDLabel: "abc123", awsv1alpha1.AccountIDLabel: "123456789012"},
+			},
+			Spec: awsv1alpha1.AWSFederatedAccountAccessSpec{
+				AWSFederatedRole: awsv1alpha1.AWSFederatedRoleRef{Name: "read-only", Namespace: "aws-account-operator"},
+			},
+		}
+	}
+	validSecret := &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "sre-access-console-url",
+			Namespace:   "aws-account-operator",
+			Annotations: map[string]string{awsv1alpha1.ConsoleSessionExpiresAtAnnotation: time.Now().Add(time.Hour).UTC().Format(time.RFC3339)},
+		},
+		Data: map[string][]byte{secretConsoleURL: []byte("https://signin.aws.amazon.com/federation?Action=login")},
+	}
+
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		secret        *corev1.Secret
+		expectedToken bool
+	}{
+		{name: "Secret is created", expectedToken: true},
+		{name: "Valid URL is kept", secret: validSecret.DeepCopy()},
+		{
+			name:          "Regeneration is requested",
+			annotations:   map[string]string{awsv1alpha1.RegenerateConsoleURLAnnotation: "true"},
+			secret:        validSecret.DeepCopy(),
+			expectedToken: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := setupDefaultMocks(t)
+			defer mocks.mockCtrl.Finish()
+			mockAWSClient := mock.NewMockClient(mocks.mockCtrl)
+
+			accountAccess := newAccountAccess(tt.annotations)
+			builder := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(accountAccess)
+			if tt.secret != nil {
+				builder = builder.WithObjects(tt.secret)
+			}
+			r := AWSFederatedAccountAccessReconciler{Client: builder.Build(), Scheme: scheme.Scheme, httpClient: federation.Client()}
+
+			if tt.expectedToken {
+				mockAWSClient.EXPECT().GetFederationToken(gomock.Any()).DoAndReturn(func(input *sts.GetFederationTokenInput) (*sts.GetFederationTokenOutput, error) {
+					assert.Equal(t, "read-only-abc123", aws.StringValue(input.Name))
+					assert.Equal(t, int64(time.Hour.Seconds()), aws.Int64Value(input.DurationSeconds))
+					assert.Equal(t, []*sts.PolicyDescriptorType{
+						{Arn: aws.String("arn:aws:iam::aws:policy/ReadOnlyAccess")},
+						{Arn: aws.String("arn:aws:iam::123456789012:policy/custom-abc123")},
+					}, input.PolicyArns)
+					return &sts.GetFederationTokenOutput{Credentials: &sts.Credentials{
+						AccessKeyId:     aws.String("access-key"),
+						SecretAccessKey: aws.String("secret-key"),
+						SessionToken:    aws.String("session-token"),
+						Expiration:      aws.Time(time.Now().Add(time.Hour)),
+					}}, nil
+				})
+			}
+
+			refreshAt, err := r.ensureSigninURL(testutils.NewTestLogger().Logger(), mockAWSClient, role, accountAccess)
+			assert.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(time.Hour-signinURLRefreshMargin), refreshAt, time.Minute)
+
+			secret := &corev1.Secret{}
+			assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "sre-access-console-url", Namespace: "aws-account-operator"}, secret))
+			if tt.expectedToken {
+				assert.Equal(t, "sre-access-console-url", accountAccess.Status.SigninURLSecret)
+				assert.True(t, strings.Contains(string(secret.Data[secretConsoleURL]), "SigninToken=signin-token"))
+				assert.Len(t, secret.OwnerReferences, 1)
+			} else {
+				assert.Equal(t, validSecret.Data, secret.Data)
+				assert.Equal(t, "sre-access-console-url", accountAccess.Status.SigninURLSecret)
+			}
+
+			updated := &awsv1alpha1.AWSFederatedAccountAccess{}
+			assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "sre-access", Namespace: "aws-account-operator"}, updated))
+			assert.NotContains(t, updated.Annotations, awsv1alpha1.RegenerateConsoleURLAnnotation)
+		})
+	}
+}
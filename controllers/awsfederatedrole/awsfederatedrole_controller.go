@@ -4,15 +4,18 @@ import (
 	"context"
 	goerr "errors"
 	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"strings"
 	"time"
 
 	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
@@ -29,7 +32,9 @@ var (
 	log           = logf.Log.WithName("controller_awsfederatedrole")
 	awsSecretName = "aws-account-operator-credentials" //  #nosec G101 -- This is a false positive
 
-	errInvalidManagedPolicy = goerr.New("InvalidManagedPolicy")
+	errInvalidManagedPolicy   = goerr.New("InvalidManagedPolicy")
+	errTooManyPolicies        = goerr.New("TooManyPolicies")
+	errPolicyValidationFailed = goerr.New("PolicyValidationFailed")
 )
 
 // AWSFederatedRoleReconciler reconciles a AWSFederatedRole object
@@ -109,15 +114,6 @@ func (r *AWSFederatedRoleReconciler) Reconcile(_ context.Context, request ctrl.R
 		return reconcile.Result{}, err
 	}
 
-	// Validates Custom IAM Policy
-	log.Info("Validating Custom Policies")
-	// Build custom policy in AWS-valid JSON and converts to string
-	jsonPolicy, err := utils.MarshalIAMPolicy(*instance)
-	if err != nil {
-		reqLogger.Error(err, "failed marshalling IAM Policy", "instanceRoleName", instance.Spec.RoleDisplayName)
-		return reconcile.Result{}, err
-	}
-
 	// If AWSCustomPolicy and AWSManagedPolicies don't exist, update condition and exit
 	if len(instance.Spec.AWSManagedPolicies) == 0 && instance.Spec.AWSCustomPolicy.Name == "" {
 		instance.Status.Conditions = utils.SetAWSFederatedRoleCondition(
@@ -127,7 +123,7 @@ func (r *AWSFederatedRoleReconciler) Reconcile(_ context.Context, request ctrl.R
 			"NoAWSCustomPolicyOrAWSManagedPolicies",
 			"AWSCustomPolicy and/or AWSManagedPolicies do not exist",
 			utils.UpdateConditionNever)
-		err = r.Client.Status().Update(context.TODO(), instance)
+		err = utils.UpdateStatus(r.Client, instance)
 		if err != nil {
 			log.Error(err, "Error updating conditions")
 			return reconcile.Result{}, err
@@ -138,43 +134,70 @@ func (r *AWSFederatedRoleReconciler) Reconcile(_ context.Context, request ctrl.R
 		return reconcile.Result{}, nil
 	}
 
-	// Attempts to create the policy to ensure it's a valid policy
-	createOutput, err := awsClient.CreatePolicy(&iam.CreatePolicyInput{
-		Description:    &instance.Spec.AWSCustomPolicy.Description,
-		PolicyName:     &instance.Spec.AWSCustomPolicy.Name,
-		PolicyDocument: &jsonPolicy,
-	})
+	// Validates Custom IAM Policy
+	log.Info("Validating Custom Policies")
+	// Build custom policy in AWS-valid JSON and converts to string. Custom policies exceeding the size of a
+	// customer managed policy are split in parts, each created as a separate policy.
+	jsonPolicies, err := utils.SplitIAMPolicy(*instance)
 	if err != nil {
-		if aerr, ok := err.(awserr.Error); ok {
-			if aerr.Code() == "MalformedPolicyDocument" {
-				log.Error(err, "Malformed Policy Document")
-				instance.Status.State = awsv1alpha1.AWSFederatedRoleStateInvalid
-				instance.Status.Conditions = utils.SetAWSFederatedRoleCondition(
-					instance.Status.Conditions,
-					awsv1alpha1.AWSFederatedRoleInvalid,
-					"True",
-					"InvalidCustomerPolicy",
-					"Custom Policy is malformed",
-					utils.UpdateConditionNever)
-				err = r.Client.Status().Update(context.TODO(), instance)
-				if err != nil {
-					log.Error(err, "Error updating conditions")
-					return reconcile.Result{}, err
-				}
-				return reconcile.Result{}, nil
-			}
-			utils.LogAwsError(log, "", nil, err)
-		} else {
-			log.Error(err, "Non-AWS Error while creating Policy")
+		if goerr.Is(err, utils.ErrPolicyStatementTooLarge) {
+			log.Error(err, "Custom policy statement too large")
+			return r.setInvalid(instance, "PolicyStatementTooLarge", fmt.Sprintf("A statement of the custom policy exceeds %d characters", utils.MaxManagedPolicySize))
 		}
+		reqLogger.Error(err, "failed marshalling IAM Policy", "instanceRoleName", instance.Spec.RoleDisplayName)
 		return reconcile.Result{}, err
 	}
+	if len(jsonPolicies) > 1 {
+		reqLogger.Info(fmt.Sprintf("Custom policy %s is split in %d parts", instance.Spec.AWSCustomPolicy.Name, len(jsonPolicies)))
+	}
 
-	// Cleanup the created policy since it's only for validation
-	_, err = awsClient.DeletePolicy(&iam.DeletePolicyInput{PolicyArn: createOutput.Policy.Arn})
-	if err != nil {
-		log.Error(err, "Error deleting custom policy")
-		return reconcile.Result{}, err
+	// Every part is attached to the role along with the managed policies
+	if len(jsonPolicies)+len(instance.Spec.AWSManagedPolicies) > utils.MaxAttachedRolePolicies {
+		log.Error(errTooManyPolicies, fmt.Sprintf("%d custom policy parts and %d managed policies exceed the %d policies attachable to a role", len(jsonPolicies), len(instance.Spec.AWSManagedPolicies), utils.MaxAttachedRolePolicies))
+		return r.setInvalid(instance, "TooManyPolicies", fmt.Sprintf("More than %d custom policy parts and managed policies", utils.MaxAttachedRolePolicies))
+	}
+
+	// Validate the custom policy with IAM Access Analyzer before anything is created. Errors make the role
+	// invalid, warnings are reported in a condition.
+	if len(instance.Spec.AWSCustomPolicy.Statements) > 0 {
+		policyErrors, policyWarnings, err := validatePolicyDocuments(awsClient, jsonPolicies)
+		if err != nil {
+			utils.LogAwsError(log, "Error validating custom policy with Access Analyzer", err, err)
+			return reconcile.Result{}, err
+		}
+		if len(policyErrors) > 0 {
+			log.Error(errPolicyValidationFailed, fmt.Sprintf("Access Analyzer reported errors for custom policy %s: %s", instance.Spec.AWSCustomPolicy.Name, strings.Join(policyErrors, "; ")))
+			return r.setInvalid(instance, "PolicyValidationFailed", summarizeFindings(policyErrors))
+		}
+		instance.Status.Conditions = setPolicyWarningsCondition(instance.Status.Conditions, policyWarnings)
+	}
+
+	for i, jsonPolicy := range jsonPolicies {
+		// Attempts to create the policy to ensure it's a valid policy
+		createOutput, err := awsClient.CreatePolicy(&iam.CreatePolicyInput{
+			Description:    &instance.Spec.AWSCustomPolicy.Description,
+			PolicyName:     aws.String(utils.CustomPolicyPartName(instance.Spec.AWSCustomPolicy.Name, i)),
+			PolicyDocument: aws.String(jsonPolicy),
+		})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok {
+				if aerr.Code() == "MalformedPolicyDocument" {
+					log.Error(err, "Malformed Policy Document")
+					return r.setInvalid(instance, "InvalidCustomerPolicy", "Custom Policy is malformed")
+				}
+				utils.LogAwsError(log, "", nil, err)
+			} else {
+				log.Error(err, "Non-AWS Error while creating Policy")
+			}
+			return reconcile.Result{}, err
+		}
+
+		// Cleanup the created policy since it's only for validation
+		_, err = awsClient.DeletePolicy(&iam.DeletePolicyInput{PolicyArn: createOutput.Policy.Arn})
+		if err != nil {
+			log.Error(err, "Error deleting custom policy")
+			return reconcile.Result{}, err
+		}
 	}
 	log.Info("Valided Custom Policies")
 
@@ -203,7 +226,7 @@ func (r *AWSFederatedRoleReconciler) Reconcile(_ context.Context, request ctrl.R
 				"InvalidManagedPolicy",
 				"Managed policy does not exist",
 				utils.UpdateConditionNever)
-			err = r.Client.Status().Update(context.TODO(), instance)
+			err = utils.UpdateStatus(r.Client, instance)
 			if err != nil {
 				log.Error(err, "Error updating conditions")
 				return reconcile.Result{}, err
@@ -223,7 +246,7 @@ func (r *AWSFederatedRoleReconciler) Reconcile(_ context.Context, request ctrl.R
 		"AllPoliciesValid",
 		"All managed and custom policies are validated",
 		utils.UpdateConditionNever)
-	err = r.Client.Status().Update(context.TODO(), instance)
+	err = utils.UpdateStatus(r.Client, instance)
 	if err != nil {
 		log.Error(err, "Error updating conditions")
 		return reconcile.Result{}, err
@@ -236,6 +259,24 @@ func (r *AWSFederatedRoleReconciler) Reconcile(_ context.Context, request ctrl.R
 	return reconcile.Result{}, nil
 }
 
+// setInvalid marks the role Invalid for the given reason. Invalid roles aren't requeued until they change.
+func (r *AWSFederatedRoleReconciler) setInvalid(instance *awsv1alpha1.AWSFederatedRole, reason string, message string) (reconcile.Result, error) {
+	instance.Status.State = awsv1alpha1.AWSFederatedRoleStateInvalid
+	instance.Status.Conditions = utils.SetAWSFederatedRoleCondition(
+		instance.Status.Conditions,
+		awsv1alpha1.AWSFederatedRoleInvalid,
+		"True",
+		reason,
+		message,
+		utils.UpdateConditionNever)
+	err := utils.UpdateStatus(r.Client, instance)
+	if err != nil {
+		log.Error(err, "Error updating conditions")
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
 func annotateAccountAccesses(kubeClient client.Client, roleName string) error {
 	accountAccesses := &awsv1alpha1.AWSFederatedAccountAccessList{}
 	err := kubeClient.List(context.TODO(), accountAccesses, client.MatchingLabels{awsv1alpha1.FederatedRoleNameLabel: roleName})
@@ -320,7 +361,7 @@ func (r *AWSFederatedRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	rwm := utils.NewReconcilerWithMetrics(r, controllerName)
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&awsv1alpha1.AWSFederatedRole{}).
+		For(&awsv1alpha1.AWSFederatedRole{}, builder.WithPredicates(utils.SpecOrMetadataChanged)).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxReconciles,
 		}).Complete(rwm)
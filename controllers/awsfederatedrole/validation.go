@@ -0,0 +1,83 @@
+package awsfederatedrole
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/accessanalyzer"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxReportedFindings caps the findings listed in a condition message
+const maxReportedFindings = 5
+
+// validatePolicyDocuments runs the custom policy documents through IAM Access Analyzer. Findings of type
+// ERROR are returned separately from the warnings and suggestions, as they make the role invalid.
+func validatePolicyDocuments(awsClient awsclient.Client, documents []string) ([]string, []string, error) {
+	policyErrors := []string{}
+	policyWarnings := []string{}
+	for i, document := range documents {
+		var nextToken *string
+		for {
+			output, err := awsClient.ValidatePolicy(&accessanalyzer.ValidatePolicyInput{
+				PolicyDocument: aws.String(document),
+				PolicyType:     aws.String(accessanalyzer.PolicyTypeIdentityPolicy),
+				NextToken:      nextToken,
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+
+			for _, finding := range output.Findings {
+				message := fmt.Sprintf("%s: %s", aws.StringValue(finding.IssueCode), aws.StringValue(finding.FindingDetails))
+				if len(documents) > 1 {
+					message = fmt.Sprintf("part %d %s", i+1, message)
+				}
+				if aws.StringValue(finding.FindingType) == accessanalyzer.ValidatePolicyFindingTypeError {
+					policyErrors = append(policyErrors, message)
+				} else {
+					policyWarnings = append(policyWarnings, message)
+				}
+			}
+
+			if output.NextToken == nil {
+				break
+			}
+			nextToken = output.NextToken
+		}
+	}
+	return policyErrors, policyWarnings, nil
+}
+
+// summarizeFindings joins the first findings into a condition message
+func summarizeFindings(findings []string) string {
+	if len(findings) <= maxReportedFindings {
+		return strings.Join(findings, "; ")
+	}
+	return fmt.Sprintf("%s; and %d more", strings.Join(findings[:maxReportedFindings], "; "), len(findings)-maxReportedFindings)
+}
+
+// setPolicyWarningsCondition reports the Access Analyzer warnings of the custom policy, clearing the
+// condition once they are fixed
+func setPolicyWarningsCondition(conditions []awsv1alpha1.AWSFederatedRoleCondition, policyWarnings []string) []awsv1alpha1.AWSFederatedRoleCondition {
+	if len(policyWarnings) == 0 {
+		return utils.SetAWSFederatedRoleCondition(
+			conditions,
+			awsv1alpha1.AWSFederatedRolePolicyWarnings,
+			corev1.ConditionFalse,
+			"NoPolicyWarnings",
+			"Access Analyzer reported no warnings",
+			utils.UpdateConditionIfReasonOrMessageChange)
+	}
+	return utils.SetAWSFederatedRoleCondition(
+		conditions,
+		awsv1alpha1.AWSFederatedRolePolicyWarnings,
+		corev1.ConditionTrue,
+		"PolicyWarnings",
+		summarizeFindings(policyWarnings),
+		utils.UpdateConditionIfReasonOrMessageChange)
+}
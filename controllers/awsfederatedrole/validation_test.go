@@ -0,0 +1,87 @@
+package awsfederatedrole
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/accessanalyzer"
+	"github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func newFinding(findingType string, issueCode string) *accessanalyzer.ValidatePolicyFinding {
+	return &accessanalyzer.ValidatePolicyFinding{
+		FindingType:    aws.String(findingType),
+		IssueCode:      aws.String(issueCode),
+		FindingDetails: aws.String("details"),
+	}
+}
+
+func TestValidatePolicyDocuments(t *testing.T) {
+	tests := []struct {
+		name             string
+		documents        []string
+		findings         [][]*accessanalyzer.ValidatePolicyFinding
+		expectedErrors   []string
+		expectedWarnings []string
+	}{
+		{
+			name:             "Valid policy",
+			documents:        []string{"{}"},
+			findings:         [][]*accessanalyzer.ValidatePolicyFinding{{}},
+			expectedErrors:   []string{},
+			expectedWarnings: []string{},
+		},
+		{
+			name:      "Errors and warnings",
+			documents: []string{"{}"},
+			findings: [][]*accessanalyzer.ValidatePolicyFinding{{
+				newFinding(accessanalyzer.ValidatePolicyFindingTypeError, "INVALID_ACTION"),
+				newFinding(accessanalyzer.ValidatePolicyFindingTypeSecurityWarning, "PASS_ROLE_WITH_STAR_IN_RESOURCE"),
+				newFinding(accessanalyzer.ValidatePolicyFindingTypeSuggestion, "EMPTY_ARRAY_RESOURCE"),
+			}},
+			expectedErrors:   []string{"INVALID_ACTION: details"},
+			expectedWarnings: []string{"PASS_ROLE_WITH_STAR_IN_RESOURCE: details", "EMPTY_ARRAY_RESOURCE: details"},
+		},
+		{
+			name:      "Findings of split policy parts",
+			documents: []string{"{}", "{}"},
+			findings: [][]*accessanalyzer.ValidatePolicyFinding{
+				{},
+				{newFinding(accessanalyzer.ValidatePolicyFindingTypeWarning, "REDUNDANT_ACTION")},
+			},
+			expectedErrors:   []string{},
+			expectedWarnings: []string{"part 2 REDUNDANT_ACTION: details"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAWSClient := mock.NewMockClient(gomock.NewController(t))
+			for _, findings := range tt.findings {
+				mockAWSClient.EXPECT().ValidatePolicy(gomock.Any()).Return(&accessanalyzer.ValidatePolicyOutput{Findings: findings}, nil)
+			}
+
+			policyErrors, policyWarnings, err := validatePolicyDocuments(mockAWSClient, tt.documents)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedErrors, policyErrors)
+			assert.Equal(t, tt.expectedWarnings, policyWarnings)
+		})
+	}
+}
+
+func TestSetPolicyWarningsCondition(t *testing.T) {
+	conditions := setPolicyWarningsCondition(nil, []string{"REDUNDANT_ACTION: details"})
+	condition := utils.FindAWSFederatedRoleCondition(conditions, v1alpha1.AWSFederatedRolePolicyWarnings)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, "REDUNDANT_ACTION: details", condition.Message)
+
+	// Fixing the warnings clears the condition
+	conditions = setPolicyWarningsCondition(conditions, nil)
+	condition = utils.FindAWSFederatedRoleCondition(conditions, v1alpha1.AWSFederatedRolePolicyWarnings)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+}
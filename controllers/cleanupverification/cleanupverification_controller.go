@@ -0,0 +1,316 @@
+package cleanupverification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+const (
+	controllerName = "cleanupverification"
+
+	// seedingRequeueDelay is how often the seeding checks whether the EBS volume and snapshot are available
+	seedingRequeueDelay = 15 * time.Second
+	// cleanupRequeueDelay is how often the verification checks whether its AccountCleanup finished
+	cleanupRequeueDelay = 30 * time.Second
+)
+
+var log = logf.Log.WithName("controller_cleanupverification")
+
+// CleanupVerificationReconciler verifies the account cleanup end to end, for the CI of the cleanup changes: it
+// seeds the account of a CleanupVerification with representative resources, runs an AccountCleanup against it and
+// checks the seeded resources are gone. It's only run when the operator is started with
+// --enable-cleanup-verification.
+type CleanupVerificationReconciler struct {
+	client.Client
+	Scheme           *runtime.Scheme
+	awsClientBuilder awsclient.IBuilder
+	recorder         record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=cleanupverifications,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=cleanupverifications/status,verbs=get;update;patch
+
+// NewCleanupVerificationReconciler initializes CleanupVerificationReconciler
+func NewCleanupVerificationReconciler(client client.Client, scheme *runtime.Scheme, awsClientBuilder awsclient.IBuilder) *CleanupVerificationReconciler {
+	return &CleanupVerificationReconciler{
+		Client:           client,
+		Scheme:           scheme,
+		awsClientBuilder: awsClientBuilder,
+	}
+}
+
+// Reconcile moves a CleanupVerification through its states: the account is reserved and seeded, then cleaned up,
+// and the verification passes or fails depending on what the cleanup left behind
+func (r *CleanupVerificationReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.WithValues("Controller", controllerName, "Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	verification := &awsv1alpha1.CleanupVerification{}
+	err := r.Client.Get(ctx, request.NamespacedName, verification)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if verification.IsFinished() {
+		return reconcile.Result{}, nil
+	}
+	reqLogger = reqLogger.WithValues("account", verification.Spec.AccountLink)
+
+	account := &awsv1alpha1.Account{}
+	err = r.Client.Get(ctx, client.ObjectKey{Name: verification.Spec.AccountLink, Namespace: awsv1alpha1.AccountCrNamespace}, account)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return reconcile.Result{}, r.fail(reqLogger, verification, fmt.Sprintf("Account %s not found", verification.Spec.AccountLink))
+		}
+		return reconcile.Result{}, err
+	}
+
+	switch verification.Status.State {
+	case "":
+		return r.reserveAccount(reqLogger, verification, account)
+	case awsv1alpha1.CleanupVerificationSeeding:
+		if utils.IsAWSMutationPaused(r.Client) {
+			reqLogger.Info("AWS changes are paused, postponing the seeding")
+			return reconcile.Result{RequeueAfter: utils.MaintenanceModeRequeueDelay}, nil
+		}
+		return r.seed(ctx, reqLogger, verification, account)
+	case awsv1alpha1.CleanupVerificationCleaningUp:
+		return r.verify(ctx, reqLogger, verification, account)
+	}
+	return reconcile.Result{}, nil
+}
+
+// reserveAccount links the account to the verification as if it were claimed by it, so it isn't handed out to a
+// claim while it's seeded, and the AccountCleanup releases it once it's cleaned up
+func (r *CleanupVerificationReconciler) reserveAccount(reqLogger logr.Logger, verification *awsv1alpha1.CleanupVerification, account *awsv1alpha1.Account) (reconcile.Result, error) {
+	switch {
+	case account.IsBYOC() || account.Spec.ManualSTSMode:
+		return reconcile.Result{}, r.fail(reqLogger, verification, "CCS and STS accounts aren't cleaned up for reuse")
+	case account.Status.State != string(awsv1alpha1.AccountReady):
+		return reconcile.Result{}, r.fail(reqLogger, verification, fmt.Sprintf("Account %s isn't Ready", account.Name))
+	case account.Spec.ClaimLink != "" || account.Status.Claimed:
+		return reconcile.Result{}, r.fail(reqLogger, verification, fmt.Sprintf("Account %s is claimed", account.Name))
+	}
+
+	err := utils.UpdateWithRetry(r.Client, account, func() error {
+		// The account may have been claimed since it was read
+		if account.Spec.ClaimLink != "" || account.Status.Claimed {
+			return awsv1alpha1.ErrAccountClaimed
+		}
+		account.Spec.ClaimLink = verification.Name
+		account.Spec.ClaimLinkNamespace = verification.Namespace
+		account.Spec.ClaimLinkUID = string(verification.UID)
+		return nil
+	})
+	if errors.Is(err, awsv1alpha1.ErrAccountClaimed) {
+		return reconcile.Result{}, r.fail(reqLogger, verification, fmt.Sprintf("Account %s is claimed", account.Name))
+	}
+	if err != nil {
+		reqLogger.Error(err, "Failed to reserve the account")
+		return reconcile.Result{}, err
+	}
+
+	reqLogger.Info("Reserved the account, seeding it")
+	err = utils.UpdateStatusWithRetry(r.Client, verification, func() error {
+		now := metav1.Now()
+		verification.Status.State = awsv1alpha1.CleanupVerificationSeeding
+		verification.Status.StartTime = &now
+		return nil
+	})
+	return reconcile.Result{Requeue: true}, err
+}
+
+// seed creates the resources that aren't seeded yet. Once they all are, the AccountCleanup is created.
+func (r *CleanupVerificationReconciler) seed(ctx context.Context, reqLogger logr.Logger, verification *awsv1alpha1.CleanupVerification, account *awsv1alpha1.Account) (reconcile.Result, error) {
+	if account.Spec.ClaimLinkUID != string(verification.UID) {
+		return reconcile.Result{}, r.fail(reqLogger, verification, fmt.Sprintf("Account %s was claimed during the seeding", account.Name))
+	}
+
+	awsClient, err := r.getAccountClient(ctx, reqLogger, verification, account)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	seeder := newSeeder(reqLogger, awsClient, verification, getRegion(verification))
+	resource, done, err := seeder.seedNext()
+	if err != nil {
+		reqLogger.Error(err, "Failed to seed the account")
+		return reconcile.Result{}, err
+	}
+	if resource != nil {
+		reqLogger.Info("Seeded resource", "kind", resource.Kind, "id", resource.ID)
+		err = utils.UpdateStatusWithRetry(r.Client, verification, func() error {
+			verification.Status.SeededResources = append(verification.Status.SeededResources, *resource)
+			return nil
+		})
+		return reconcile.Result{Requeue: true}, err
+	}
+	if !done {
+		return reconcile.Result{RequeueAfter: seedingRequeueDelay}, nil
+	}
+
+	cleanup, err := r.createAccountCleanup(reqLogger, verification, account)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	err = utils.UpdateStatusWithRetry(r.Client, verification, func() error {
+		verification.Status.State = awsv1alpha1.CleanupVerificationCleaningUp
+		verification.Status.AccountCleanup = cleanup.Name
+		return nil
+	})
+	return reconcile.Result{RequeueAfter: cleanupRequeueDelay}, err
+}
+
+// createAccountCleanup creates the AccountCleanup of the seeded account, as the finalizer of the claim would. It's
+// owned by the Account, as the verification may live in another namespace.
+func (r *CleanupVerificationReconciler) createAccountCleanup(reqLogger logr.Logger, verification *awsv1alpha1.CleanupVerification, account *awsv1alpha1.Account) (*awsv1alpha1.AccountCleanup, error) {
+	cleanup := &awsv1alpha1.AccountCleanup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      account.Name + "-" + string(verification.UID),
+			Namespace: awsv1alpha1.AccountCrNamespace,
+		},
+		Spec: awsv1alpha1.AccountCleanupSpec{
+			AccountLink:    account.Name,
+			AwsAccountID:   account.Spec.AwsAccountID,
+			ClaimName:      verification.Name,
+			ClaimNamespace: verification.Namespace,
+			ClaimUID:       string(verification.UID),
+			Region:         getRegion(verification),
+			LegalEntity:    account.Spec.LegalEntity,
+		},
+	}
+	err := controllerutil.SetOwnerReference(account, cleanup, r.Scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Client.Create(context.TODO(), cleanup)
+	if err != nil && !k8serr.IsAlreadyExists(err) {
+		reqLogger.Error(err, "Failed to create the account cleanup")
+		return nil, err
+	}
+	reqLogger.Info("Seeding completed, cleaning up the account", "accountCleanup", cleanup.Name)
+	return cleanup, nil
+}
+
+// verify waits for the AccountCleanup to finish, then checks which seeded resources are left in the account
+func (r *CleanupVerificationReconciler) verify(ctx context.Context, reqLogger logr.Logger, verification *awsv1alpha1.CleanupVerification, account *awsv1alpha1.Account) (reconcile.Result, error) {
+	cleanup := &awsv1alpha1.AccountCleanup{}
+	err := r.Client.Get(ctx, client.ObjectKey{Name: verification.Status.AccountCleanup, Namespace: awsv1alpha1.AccountCrNamespace}, cleanup)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return reconcile.Result{}, r.fail(reqLogger, verification, fmt.Sprintf("AccountCleanup %s not found", verification.Status.AccountCleanup))
+		}
+		return reconcile.Result{}, err
+	}
+	if !cleanup.IsFinished() {
+		return reconcile.Result{RequeueAfter: cleanupRequeueDelay}, nil
+	}
+
+	awsClient, err := r.getAccountClient(ctx, reqLogger, verification, account)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	remaining, err := remainingResources(awsClient, verification.Status.SeededResources)
+	if err != nil {
+		reqLogger.Error(err, "Failed to look up the seeded resources")
+		return reconcile.Result{}, err
+	}
+	err = utils.UpdateStatusWithRetry(r.Client, verification, func() error {
+		verification.Status.RemainingResources = remaining
+		return nil
+	})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	switch {
+	case cleanup.Status.State == awsv1alpha1.AccountCleanupFailed:
+		return reconcile.Result{}, r.fail(reqLogger, verification, fmt.Sprintf("AccountCleanup %s failed: %s", cleanup.Name, cleanup.Status.LastError))
+	case len(remaining) > 0:
+		return reconcile.Result{}, r.fail(reqLogger, verification, fmt.Sprintf("The cleanup left %d of the %d seeded resources in the account", len(remaining), len(verification.Status.SeededResources)))
+	}
+
+	reqLogger.Info("Cleanup verification passed")
+	utils.RecordEvent(r.recorder, verification, corev1.EventTypeNormal, utils.EventReasonVerificationPassed, "The cleanup of account %s removed the %d seeded resources", account.Name, len(verification.Status.SeededResources))
+	return reconcile.Result{}, r.finish(verification, awsv1alpha1.CleanupVerificationPassed, "")
+}
+
+// fail records that the verification failed
+func (r *CleanupVerificationReconciler) fail(reqLogger logr.Logger, verification *awsv1alpha1.CleanupVerification, message string) error {
+	reqLogger.Info("Cleanup verification failed", "reason", message)
+	utils.RecordEvent(r.recorder, verification, corev1.EventTypeWarning, utils.EventReasonVerificationFailed, "%s", message)
+	return r.finish(verification, awsv1alpha1.CleanupVerificationFailed, message)
+}
+
+// finish records that the verification passed or failed
+func (r *CleanupVerificationReconciler) finish(verification *awsv1alpha1.CleanupVerification, state awsv1alpha1.CleanupVerificationState, message string) error {
+	return utils.UpdateStatusWithRetry(r.Client, verification, func() error {
+		now := metav1.Now()
+		verification.Status.State = state
+		verification.Status.Message = message
+		verification.Status.CompletionTime = &now
+		return nil
+	})
+}
+
+// getAccountClient returns a client of the AWS account of the verification, in its region
+func (r *CleanupVerificationReconciler) getAccountClient(ctx context.Context, reqLogger logr.Logger, verification *awsv1alpha1.CleanupVerification, account *awsv1alpha1.Account) (awsclient.Client, error) {
+	awsClientBuilder := awsclient.WithContext(ctx, r.awsClientBuilder)
+	awsSetupClient, err := awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		reqLogger.Error(err, "failed building operator AWS client")
+		return nil, err
+	}
+
+	awsClient, _, err := stsclient.HandleRoleAssumption(reqLogger, awsClientBuilder, account, r.Client, awsSetupClient, getRegion(verification), account.GetAssumeRole(), "")
+	if err != nil {
+		reqLogger.Error(err, "Unable to create aws client")
+		return nil, err
+	}
+	return awsClient, nil
+}
+
+// getRegion returns the region of the verification
+func getRegion(verification *awsv1alpha1.CleanupVerification) string {
+	if verification.Spec.Region != "" {
+		return verification.Spec.Region
+	}
+	return config.GetDefaultRegion()
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CleanupVerificationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.awsClientBuilder = &awsclient.Builder{}
+	r.recorder = mgr.GetEventRecorderFor(controllerName)
+
+	rwm := utils.NewReconcilerWithMetrics(r, controllerName)
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&awsv1alpha1.CleanupVerification{}).
+		Complete(rwm)
+}
@@ -0,0 +1,197 @@
+package cleanupverification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+)
+
+const (
+	accountName      = "osd-creds-mgmt-aaabbb"
+	verificationName = "verify-cleanup"
+	verificationNS   = "ci"
+)
+
+func newTestReconciler(t *testing.T, objs ...runtime.Object) (*CleanupVerificationReconciler, *mock.MockClient) {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatalf("failed adding apis to scheme: %v", err)
+	}
+	awsClientBuilder := &mock.Builder{MockController: gomock.NewController(t)}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
+	return NewCleanupVerificationReconciler(kubeClient, scheme.Scheme, awsClientBuilder), mock.GetMockClient(awsClientBuilder)
+}
+
+func newReadyAccount() *awsv1alpha1.Account {
+	return &awsv1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      accountName,
+			Namespace: awsv1alpha1.AccountCrNamespace,
+		},
+		Spec: awsv1alpha1.AccountSpec{
+			AwsAccountID: "123456789012",
+		},
+		Status: awsv1alpha1.AccountStatus{
+			State: string(awsv1alpha1.AccountReady),
+		},
+	}
+}
+
+func newVerification() *awsv1alpha1.CleanupVerification {
+	return &awsv1alpha1.CleanupVerification{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      verificationName,
+			Namespace: verificationNS,
+			UID:       "4d1b5b34-verification",
+		},
+		Spec: awsv1alpha1.CleanupVerificationSpec{
+			AccountLink: accountName,
+			Region:      "us-east-1",
+		},
+	}
+}
+
+func reconcileVerification(t *testing.T, r *CleanupVerificationReconciler) *awsv1alpha1.CleanupVerification {
+	_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: verificationName, Namespace: verificationNS}})
+	assert.NoError(t, err)
+
+	verification := &awsv1alpha1.CleanupVerification{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: verificationName, Namespace: verificationNS}, verification))
+	return verification
+}
+
+func TestReconcileReservesAccount(t *testing.T) {
+	r, _ := newTestReconciler(t, newReadyAccount(), newVerification())
+
+	verification := reconcileVerification(t, r)
+	assert.Equal(t, awsv1alpha1.CleanupVerificationSeeding, verification.Status.State)
+	assert.NotNil(t, verification.Status.StartTime)
+
+	account := &awsv1alpha1.Account{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: accountName, Namespace: awsv1alpha1.AccountCrNamespace}, account))
+	assert.Equal(t, verificationName, account.Spec.ClaimLink)
+	assert.Equal(t, verificationNS, account.Spec.ClaimLinkNamespace)
+	assert.Equal(t, string(verification.UID), account.Spec.ClaimLinkUID)
+}
+
+func TestReconcileFailsOnUnusableAccount(t *testing.T) {
+	tests := []struct {
+		name    string
+		account func(*awsv1alpha1.Account)
+	}{
+		{name: "claimed", account: func(a *awsv1alpha1.Account) { a.Spec.ClaimLink = "claim" }},
+		{name: "not ready", account: func(a *awsv1alpha1.Account) { a.Status.State = string(awsv1alpha1.AccountCreating) }},
+		{name: "ccs", account: func(a *awsv1alpha1.Account) { a.Spec.BYOC = true }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			account := newReadyAccount()
+			test.account(account)
+			r, _ := newTestReconciler(t, account, newVerification())
+
+			verification := reconcileVerification(t, r)
+			assert.Equal(t, awsv1alpha1.CleanupVerificationFailed, verification.Status.State)
+			assert.NotEmpty(t, verification.Status.Message)
+			assert.NotNil(t, verification.Status.CompletionTime)
+		})
+	}
+}
+
+func TestReserveAccountFailsWhenClaimedSinceRead(t *testing.T) {
+	r, _ := newTestReconciler(t, newReadyAccount(), newVerification())
+	stale := &awsv1alpha1.Account{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: accountName, Namespace: awsv1alpha1.AccountCrNamespace}, stale))
+	claimed := stale.DeepCopy()
+	claimed.Spec.ClaimLink = "claim"
+	assert.NoError(t, r.Client.Update(context.TODO(), claimed))
+	verification := &awsv1alpha1.CleanupVerification{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: verificationName, Namespace: verificationNS}, verification))
+
+	_, err := r.reserveAccount(testutils.NewTestLogger().Logger(), verification, stale)
+	assert.NoError(t, err)
+	assert.Equal(t, awsv1alpha1.CleanupVerificationFailed, verification.Status.State)
+
+	account := &awsv1alpha1.Account{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: accountName, Namespace: awsv1alpha1.AccountCrNamespace}, account))
+	assert.Equal(t, "claim", account.Spec.ClaimLink)
+}
+
+func TestSeedNextSeedsVersionedBucketFirst(t *testing.T) {
+	_, mockAWSClient := newTestReconciler(t)
+	verification := newVerification()
+	bucketName := seededNamePrefix + "-" + string(verification.UID)
+
+	mockAWSClient.EXPECT().CreateBucket(&s3.CreateBucketInput{Bucket: aws.String(bucketName)}).Return(&s3.CreateBucketOutput{}, nil)
+	mockAWSClient.EXPECT().PutBucketVersioning(gomock.Any()).Return(&s3.PutBucketVersioningOutput{}, nil)
+	// Two versions of the same object
+	mockAWSClient.EXPECT().PutObject(gomock.Any()).Return(&s3.PutObjectOutput{}, nil).Times(2)
+
+	resource, done, err := newSeeder(testutils.NewTestLogger().Logger(), mockAWSClient, verification, "us-east-1").seedNext()
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, &awsv1alpha1.SeededResource{Kind: kindS3Bucket, ID: bucketName}, resource)
+}
+
+func TestSeedNextWaitsForVolume(t *testing.T) {
+	_, mockAWSClient := newTestReconciler(t)
+	verification := newVerification()
+	verification.Status.SeededResources = []awsv1alpha1.SeededResource{
+		{Kind: kindS3Bucket, ID: "bucket"},
+		{Kind: kindHostedZone, ID: "/hostedzone/Z1"},
+		{Kind: kindIAMUser, ID: "user"},
+		{Kind: kindEBSVolume, ID: "vol-1"},
+	}
+
+	mockAWSClient.EXPECT().DescribeVolumes(gomock.Any()).Return(&ec2.DescribeVolumesOutput{
+		Volumes: []*ec2.Volume{{VolumeId: aws.String("vol-1"), State: aws.String(ec2.VolumeStateCreating)}},
+	}, nil)
+
+	resource, done, err := newSeeder(testutils.NewTestLogger().Logger(), mockAWSClient, verification, "us-east-1").seedNext()
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Nil(t, resource)
+}
+
+func TestRemainingResources(t *testing.T) {
+	_, mockAWSClient := newTestReconciler(t)
+	seeded := []awsv1alpha1.SeededResource{
+		{Kind: kindS3Bucket, ID: "bucket"},
+		{Kind: kindHostedZone, ID: "/hostedzone/Z1"},
+		{Kind: kindIAMUser, ID: "user"},
+		{Kind: kindEBSVolume, ID: "vol-1"},
+		{Kind: kindEBSSnapshot, ID: "snap-1"},
+	}
+
+	mockAWSClient.EXPECT().HeadBucket(gomock.Any()).Return(nil, awserr.New("NotFound", "", nil))
+	mockAWSClient.EXPECT().GetHostedZone(gomock.Any()).Return(nil, awserr.New(route53.ErrCodeNoSuchHostedZone, "", nil))
+	mockAWSClient.EXPECT().GetUser(gomock.Any()).Return(&iam.GetUserOutput{User: &iam.User{UserName: aws.String("user")}}, nil)
+	mockAWSClient.EXPECT().DescribeVolumes(gomock.Any()).Return(nil, awserr.New("InvalidVolume.NotFound", "", nil))
+	mockAWSClient.EXPECT().DescribeSnapshots(gomock.Any()).Return(&ec2.DescribeSnapshotsOutput{
+		Snapshots: []*ec2.Snapshot{{SnapshotId: aws.String("snap-1")}},
+	}, nil)
+
+	remaining, err := remainingResources(mockAWSClient, seeded)
+	assert.NoError(t, err)
+	assert.Equal(t, []awsv1alpha1.SeededResource{
+		{Kind: kindIAMUser, ID: "user"},
+		{Kind: kindEBSSnapshot, ID: "snap-1"},
+	}, remaining)
+}
@@ -0,0 +1,296 @@
+package cleanupverification
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/go-logr/logr"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+)
+
+const (
+	kindS3Bucket    = "S3Bucket"
+	kindHostedZone  = "HostedZone"
+	kindIAMUser     = "IAMUser"
+	kindEBSVolume   = "EBSVolume"
+	kindEBSSnapshot = "EBSSnapshot"
+
+	// seededNamePrefix names the seeded resources, so they're told apart from anything else in the account
+	seededNamePrefix = "aao-cleanup-verification"
+	// seededZoneDomain is the domain of the seeded hosted zones, they're never delegated to
+	seededZoneDomain = "aao-cleanup-verification.com"
+	// seededObjectKey is the key of the object written twice to the seeded bucket, so it has a noncurrent version
+	seededObjectKey = "seeded-object"
+)
+
+// seededKinds are the kinds of resources seeded, in order. The snapshot is taken of the seeded volume.
+var seededKinds = []string{kindS3Bucket, kindHostedZone, kindIAMUser, kindEBSVolume, kindEBSSnapshot}
+
+// seeder creates the resources of a verification in its account, one at a time
+type seeder struct {
+	reqLogger    logr.Logger
+	awsClient    awsclient.Client
+	verification *awsv1alpha1.CleanupVerification
+	region       string
+}
+
+func newSeeder(reqLogger logr.Logger, awsClient awsclient.Client, verification *awsv1alpha1.CleanupVerification, region string) *seeder {
+	return &seeder{reqLogger: reqLogger, awsClient: awsClient, verification: verification, region: region}
+}
+
+// seedNext creates the next resource that isn't seeded yet and returns it, to be recorded before the next one is
+// created. It returns nil when the next resource has to wait for the previous ones to be available, and done once
+// everything is seeded and available.
+func (s *seeder) seedNext() (*awsv1alpha1.SeededResource, bool, error) {
+	for _, kind := range seededKinds {
+		if s.seeded(kind) != "" {
+			continue
+		}
+		var id string
+		var err error
+		switch kind {
+		case kindS3Bucket:
+			id, err = s.seedBucket()
+		case kindHostedZone:
+			id, err = s.seedHostedZone()
+		case kindIAMUser:
+			id, err = s.seedIAMUser()
+		case kindEBSVolume:
+			id, err = s.seedVolume()
+		case kindEBSSnapshot:
+			id, err = s.seedSnapshot()
+		}
+		if err != nil || id == "" {
+			return nil, false, err
+		}
+		return &awsv1alpha1.SeededResource{Kind: kind, ID: id}, false, nil
+	}
+
+	// The cleanup could fail on a snapshot still being taken
+	snapshots, err := s.awsClient.DescribeSnapshots(&ec2.DescribeSnapshotsInput{SnapshotIds: aws.StringSlice([]string{s.seeded(kindEBSSnapshot)})})
+	if err != nil {
+		return nil, false, err
+	}
+	for _, snapshot := range snapshots.Snapshots {
+		if aws.StringValue(snapshot.State) != ec2.SnapshotStateCompleted {
+			s.reqLogger.Info("Waiting for the seeded snapshot to complete", "snapshotID", aws.StringValue(snapshot.SnapshotId))
+			return nil, false, nil
+		}
+	}
+	return nil, true, nil
+}
+
+// seeded returns the ID of the seeded resource of the kind, empty if it isn't seeded yet
+func (s *seeder) seeded(kind string) string {
+	for _, resource := range s.verification.Status.SeededResources {
+		if resource.Kind == kind {
+			return resource.ID
+		}
+	}
+	return ""
+}
+
+// seedName returns the name of a seeded resource, unique to the verification
+func (s *seeder) seedName() string {
+	return seededNamePrefix + "-" + string(s.verification.UID)
+}
+
+// seedBucket creates a versioned bucket whose object has a current and a noncurrent version
+func (s *seeder) seedBucket() (string, error) {
+	bucketName := s.seedName()
+	input := &s3.CreateBucketInput{Bucket: aws.String(bucketName)}
+	// us-east-1 is the default location and can't be given as a constraint
+	if s.region != "us-east-1" {
+		input.CreateBucketConfiguration = &s3.CreateBucketConfiguration{LocationConstraint: aws.String(s.region)}
+	}
+	_, err := s.awsClient.CreateBucket(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != s3.ErrCodeBucketAlreadyOwnedByYou {
+			return "", fmt.Errorf("failed to create bucket %s: %w", bucketName, err)
+		}
+	}
+
+	_, err = s.awsClient.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket:                  aws.String(bucketName),
+		VersioningConfiguration: &s3.VersioningConfiguration{Status: aws.String(s3.BucketVersioningStatusEnabled)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to enable the versioning of bucket %s: %w", bucketName, err)
+	}
+
+	for _, content := range []string{"first version", "second version"} {
+		_, err = s.awsClient.PutObject(&s3.PutObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(seededObjectKey),
+			Body:   strings.NewReader(content),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to write object %s to bucket %s: %w", seededObjectKey, bucketName, err)
+		}
+	}
+	return bucketName, nil
+}
+
+// seedHostedZone creates a hosted zone with a record, which has to be deleted before the zone
+func (s *seeder) seedHostedZone() (string, error) {
+	zoneName := string(s.verification.UID) + "." + seededZoneDomain
+	zone, err := s.awsClient.CreateHostedZone(&route53.CreateHostedZoneInput{
+		Name:            aws.String(zoneName),
+		CallerReference: aws.String(s.seedName()),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create hosted zone %s: %w", zoneName, err)
+	}
+
+	_, err = s.awsClient.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: zone.HostedZone.Id,
+		ChangeBatch: &route53.ChangeBatch{
+			Changes: []*route53.Change{{
+				Action: aws.String(route53.ChangeActionUpsert),
+				ResourceRecordSet: &route53.ResourceRecordSet{
+					Name:            aws.String("seeded." + zoneName),
+					Type:            aws.String(route53.RRTypeTxt),
+					TTL:             aws.Int64(300),
+					ResourceRecords: []*route53.ResourceRecord{{Value: aws.String(`"` + s.verification.Name + `"`)}},
+				},
+			}},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create a record in hosted zone %s: %w", zoneName, err)
+	}
+	return aws.StringValue(zone.HostedZone.Id), nil
+}
+
+// seedIAMUser creates an IAM user tagged with the verification as its claim, like the users created for a claim
+func (s *seeder) seedIAMUser() (string, error) {
+	userName := s.seedName()
+	_, err := s.awsClient.CreateUser(&iam.CreateUserInput{
+		UserName: aws.String(userName),
+		Tags: []*iam.Tag{{
+			Key:   aws.String(awsv1alpha1.ClaimUIDTagKey),
+			Value: aws.String(string(s.verification.UID)),
+		}},
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeEntityAlreadyExistsException {
+			return "", fmt.Errorf("failed to create IAM user %s: %w", userName, err)
+		}
+	}
+	return userName, nil
+}
+
+// seedVolume creates an EBS volume in the first availability zone of the region
+func (s *seeder) seedVolume() (string, error) {
+	zones, err := s.awsClient.DescribeAvailabilityZones(&ec2.DescribeAvailabilityZonesInput{})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe the availability zones: %w", err)
+	}
+	if len(zones.AvailabilityZones) == 0 {
+		return "", fmt.Errorf("no availability zone in region %s", s.region)
+	}
+
+	volume, err := s.awsClient.CreateVolume(&ec2.CreateVolumeInput{
+		AvailabilityZone: zones.AvailabilityZones[0].ZoneName,
+		Size:             aws.Int64(1),
+		VolumeType:       aws.String(ec2.VolumeTypeGp3),
+		TagSpecifications: []*ec2.TagSpecification{{
+			ResourceType: aws.String(ec2.ResourceTypeVolume),
+			Tags:         []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String(s.seedName())}},
+		}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create EBS volume: %w", err)
+	}
+	return aws.StringValue(volume.VolumeId), nil
+}
+
+// seedSnapshot takes a snapshot of the seeded volume once it's available, it returns an empty ID until then
+func (s *seeder) seedSnapshot() (string, error) {
+	volumeID := s.seeded(kindEBSVolume)
+	volumes, err := s.awsClient.DescribeVolumes(&ec2.DescribeVolumesInput{VolumeIds: aws.StringSlice([]string{volumeID})})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe EBS volume %s: %w", volumeID, err)
+	}
+	if len(volumes.Volumes) == 0 || aws.StringValue(volumes.Volumes[0].State) != ec2.VolumeStateAvailable {
+		s.reqLogger.Info("Waiting for the seeded volume to be available", "volumeID", volumeID)
+		return "", nil
+	}
+
+	snapshot, err := s.awsClient.CreateSnapshot(&ec2.CreateSnapshotInput{
+		VolumeId:    aws.String(volumeID),
+		Description: aws.String(s.seedName()),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to snapshot EBS volume %s: %w", volumeID, err)
+	}
+	return aws.StringValue(snapshot.SnapshotId), nil
+}
+
+// remainingResources returns the seeded resources still in the account
+func remainingResources(awsClient awsclient.Client, seeded []awsv1alpha1.SeededResource) ([]awsv1alpha1.SeededResource, error) {
+	remaining := []awsv1alpha1.SeededResource{}
+	for _, resource := range seeded {
+		exists, err := resourceExists(awsClient, resource)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			remaining = append(remaining, resource)
+		}
+	}
+	return remaining, nil
+}
+
+// resourceExists looks a seeded resource up, it doesn't exist if AWS reports it not found
+func resourceExists(awsClient awsclient.Client, resource awsv1alpha1.SeededResource) (bool, error) {
+	var err error
+	var notFoundCodes []string
+	switch resource.Kind {
+	case kindS3Bucket:
+		_, err = awsClient.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String(resource.ID)})
+		// HeadBucket has no body, its errors only carry the HTTP status
+		notFoundCodes = []string{s3.ErrCodeNoSuchBucket, "NotFound"}
+	case kindHostedZone:
+		_, err = awsClient.GetHostedZone(&route53.GetHostedZoneInput{Id: aws.String(resource.ID)})
+		notFoundCodes = []string{route53.ErrCodeNoSuchHostedZone}
+	case kindIAMUser:
+		_, err = awsClient.GetUser(&iam.GetUserInput{UserName: aws.String(resource.ID)})
+		notFoundCodes = []string{iam.ErrCodeNoSuchEntityException}
+	case kindEBSVolume:
+		var volumes *ec2.DescribeVolumesOutput
+		volumes, err = awsClient.DescribeVolumes(&ec2.DescribeVolumesInput{VolumeIds: aws.StringSlice([]string{resource.ID})})
+		notFoundCodes = []string{"InvalidVolume.NotFound"}
+		if err == nil {
+			return len(volumes.Volumes) > 0, nil
+		}
+	case kindEBSSnapshot:
+		var snapshots *ec2.DescribeSnapshotsOutput
+		snapshots, err = awsClient.DescribeSnapshots(&ec2.DescribeSnapshotsInput{SnapshotIds: aws.StringSlice([]string{resource.ID})})
+		notFoundCodes = []string{"InvalidSnapshot.NotFound"}
+		if err == nil {
+			return len(snapshots.Snapshots) > 0, nil
+		}
+	default:
+		return false, fmt.Errorf("unknown seeded resource kind %s", resource.Kind)
+	}
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			for _, code := range notFoundCodes {
+				if aerr.Code() == code {
+					return false, nil
+				}
+			}
+		}
+		return false, fmt.Errorf("failed to look up %s %s: %w", resource.Kind, resource.ID, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,354 @@
+package iamuserrequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	stsclient "github.com/openshift/aws-account-operator/pkg/awsclient/sts"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+const controllerName = "iamuserrequest"
+
+var log = logf.Log.WithName("controller_iamuserrequest")
+
+// errInvalidRequest is wrapped by the errors of requests that can't be fulfilled until their spec is fixed
+var errInvalidRequest = errors.New("invalid IAMUserRequest")
+
+// IAMUserRequestReconciler creates the IAM principals requested in the accounts of AccountClaims, and stores their
+// credentials in secrets
+type IAMUserRequestReconciler struct {
+	client.Client
+	Scheme           *runtime.Scheme
+	awsClientBuilder awsclient.IBuilder
+}
+
+//+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=iamuserrequests,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=iamuserrequests/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=aws.managed.openshift.io,resources=iamuserrequests/finalizers,verbs=update
+
+// NewIAMUserRequestReconciler initializes IAMUserRequestReconciler
+func NewIAMUserRequestReconciler(client client.Client, scheme *runtime.Scheme, awsClientBuilder awsclient.IBuilder) *IAMUserRequestReconciler {
+	return &IAMUserRequestReconciler{
+		Client:           client,
+		Scheme:           scheme,
+		awsClientBuilder: awsClientBuilder,
+	}
+}
+
+// Reconcile creates the IAM principal of an IAMUserRequest in the account of its AccountClaim, keeps its policies
+// in line with the request and tears it down when the request is deleted
+func (r *IAMUserRequestReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.WithValues("Controller", controllerName, "Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	iamUserRequest := &awsv1alpha1.IAMUserRequest{}
+	err := r.Client.Get(ctx, request.NamespacedName, iamUserRequest)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	reqLogger = reqLogger.WithValues("iamName", iamUserRequest.GetIAMName(), "principalType", iamUserRequest.Spec.PrincipalType)
+
+	// Neither creating nor tearing down the principal can be done while AWS changes are paused
+	if utils.IsAWSMutationPaused(r.Client) {
+		reqLogger.Info("AWS changes are paused, postponing the IAMUserRequest")
+		return reconcile.Result{RequeueAfter: utils.MaintenanceModeRequeueDelay}, nil
+	}
+
+	if iamUserRequest.DeletionTimestamp != nil {
+		if !controllerutil.ContainsFinalizer(iamUserRequest, utils.Finalizer) {
+			return reconcile.Result{}, nil
+		}
+		err = r.finalizeIAMUserRequest(ctx, reqLogger, iamUserRequest)
+		if err != nil {
+			reqLogger.Error(err, "Failed to tear down the IAM principal")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, r.removeFinalizer(reqLogger, iamUserRequest)
+	}
+
+	if !controllerutil.ContainsFinalizer(iamUserRequest, utils.Finalizer) {
+		utils.AddFinalizer(iamUserRequest, utils.Finalizer)
+		return reconcile.Result{}, r.Client.Update(ctx, iamUserRequest)
+	}
+
+	err = validateRequest(iamUserRequest)
+	if err != nil {
+		reqLogger.Info("Invalid IAMUserRequest", "error", err.Error())
+		return reconcile.Result{}, r.setStatus(iamUserRequest, awsv1alpha1.IAMUserRequestFailed, err.Error())
+	}
+
+	accountClaim := &awsv1alpha1.AccountClaim{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: iamUserRequest.Spec.AccountClaim, Namespace: iamUserRequest.Namespace}, accountClaim)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return reconcile.Result{}, r.setStatus(iamUserRequest, awsv1alpha1.IAMUserRequestPending, "the AccountClaim doesn't exist")
+		}
+		return reconcile.Result{}, err
+	}
+	// The principal is only created once the claim has its account, the claim's watch requeues the request
+	if accountClaim.Status.State != awsv1alpha1.ClaimStatusReady || accountClaim.Spec.AccountLink == "" {
+		return reconcile.Result{}, r.setStatus(iamUserRequest, awsv1alpha1.IAMUserRequestPending, "waiting for the AccountClaim to be Ready")
+	}
+	// A principal can't be moved to another account, requests outliving their account are deleted with their claim
+	if iamUserRequest.Status.AccountLink != "" && iamUserRequest.Status.AccountLink != accountClaim.Spec.AccountLink {
+		return reconcile.Result{}, r.setStatus(iamUserRequest, awsv1alpha1.IAMUserRequestFailed, fmt.Sprintf("the AccountClaim now links to account %s, the principal was created in account %s", accountClaim.Spec.AccountLink, iamUserRequest.Status.AccountLink))
+	}
+
+	// The request is garbage collected with its claim, after its finalizer tore the principal down
+	if !metav1.IsControlledBy(iamUserRequest, accountClaim) {
+		err = controllerutil.SetControllerReference(accountClaim, iamUserRequest, r.Scheme)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, r.Client.Update(ctx, iamUserRequest)
+	}
+
+	linkedAccount := &awsv1alpha1.Account{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: accountClaim.Spec.AccountLink, Namespace: awsv1alpha1.AccountCrNamespace}, linkedAccount)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	reqLogger = reqLogger.WithValues("account", linkedAccount.Name, "awsAccountID", linkedAccount.Spec.AwsAccountID)
+
+	// Record the account before creating anything in it, so the principal is torn down even if the rest fails
+	if iamUserRequest.Status.AccountLink == "" {
+		err = utils.UpdateStatusWithRetry(r.Client, iamUserRequest, func() error {
+			iamUserRequest.Status.AccountLink = linkedAccount.Name
+			iamUserRequest.Status.AwsAccountID = linkedAccount.Spec.AwsAccountID
+			return nil
+		})
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	awsClient, err := r.getAccountClient(ctx, reqLogger, linkedAccount)
+	if err != nil {
+		reqLogger.Error(err, "Failed building the AWS client of the account")
+		return reconcile.Result{}, err
+	}
+
+	principalARN, err := ensurePrincipal(reqLogger, awsClient, iamUserRequest, linkedAccount)
+	if err == nil {
+		err = r.ensureSecret(ctx, reqLogger, awsClient, iamUserRequest, principalARN)
+	}
+	if err != nil {
+		reqLogger.Error(err, "Failed to create the IAM principal")
+		if statusErr := r.setStatus(iamUserRequest, awsv1alpha1.IAMUserRequestFailed, err.Error()); statusErr != nil {
+			reqLogger.Error(statusErr, "Failed to update IAMUserRequest status")
+		}
+		if errors.Is(err, errInvalidRequest) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if iamUserRequest.Status.State != awsv1alpha1.IAMUserRequestReady {
+		reqLogger.Info("IAM principal ready", "principalARN", principalARN)
+	}
+	return reconcile.Result{}, utils.UpdateStatusWithRetry(r.Client, iamUserRequest, func() error {
+		iamUserRequest.Status.State = awsv1alpha1.IAMUserRequestReady
+		iamUserRequest.Status.PrincipalARN = principalARN
+		iamUserRequest.Status.SecretRef = &awsv1alpha1.SecretRef{Name: iamUserRequest.GetSecretName(), Namespace: iamUserRequest.Namespace}
+		iamUserRequest.Status.Message = ""
+		return nil
+	})
+}
+
+// validateRequest returns an error wrapping errInvalidRequest if the principal of the request can't be created
+func validateRequest(iamUserRequest *awsv1alpha1.IAMUserRequest) error {
+	switch iamUserRequest.Spec.PrincipalType {
+	case awsv1alpha1.IAMPrincipalUser:
+		if len(iamUserRequest.Spec.TrustedARNs) > 0 {
+			return fmt.Errorf("%w: trustedARNs only apply to roles", errInvalidRequest)
+		}
+	case awsv1alpha1.IAMPrincipalRole:
+		if len(iamUserRequest.Spec.TrustedARNs) == 0 {
+			return fmt.Errorf("%w: a role requires trustedARNs", errInvalidRequest)
+		}
+	default:
+		return fmt.Errorf("%w: unknown principalType %s", errInvalidRequest, iamUserRequest.Spec.PrincipalType)
+	}
+	if iamUserRequest.Spec.InlinePolicy != nil && iamUserRequest.Spec.InlinePolicy.Name == "" {
+		return fmt.Errorf("%w: the inlinePolicy requires a name", errInvalidRequest)
+	}
+	return nil
+}
+
+// getAccountClient returns an AWS client in the account, assuming its access role
+func (r *IAMUserRequestReconciler) getAccountClient(ctx context.Context, reqLogger logr.Logger, linkedAccount *awsv1alpha1.Account) (awsclient.Client, error) {
+	awsClientBuilder := awsclient.WithContext(ctx, r.awsClientBuilder)
+	awsSetupClient, err := awsClientBuilder.GetClient(controllerName, r.Client, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	awsClient, _, err := stsclient.HandleRoleAssumption(reqLogger, awsClientBuilder, linkedAccount, r.Client, awsSetupClient, config.GetDefaultRegion(), linkedAccount.GetAssumeRole(), "")
+	return awsClient, err
+}
+
+// ensureSecret creates the secret of the credentials of the principal if it doesn't exist, the access key of a
+// user or the ARN of a role. It's owned by the request, so it's deleted with it.
+func (r *IAMUserRequestReconciler) ensureSecret(ctx context.Context, reqLogger logr.Logger, awsClient awsclient.Client, iamUserRequest *awsv1alpha1.IAMUserRequest, principalARN string) error {
+	secret := &corev1.Secret{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: iamUserRequest.GetSecretName(), Namespace: iamUserRequest.Namespace}, secret)
+	if err == nil {
+		if !metav1.IsControlledBy(secret, iamUserRequest) {
+			return fmt.Errorf("%w: secret %s already exists and isn't owned by the request", errInvalidRequest, secret.Name)
+		}
+		// The access key of a user is only known when it's created, the secret holds the current one
+		if iamUserRequest.Spec.PrincipalType == awsv1alpha1.IAMPrincipalUser || string(secret.Data[secretRoleARN]) == principalARN {
+			return nil
+		}
+		secret.Data = map[string][]byte{secretRoleARN: []byte(principalARN)}
+		return r.Client.Update(ctx, secret)
+	}
+	if !k8serr.IsNotFound(err) {
+		return err
+	}
+
+	data := map[string][]byte{secretRoleARN: []byte(principalARN)}
+	if iamUserRequest.Spec.PrincipalType == awsv1alpha1.IAMPrincipalUser {
+		data, err = createUserCredentials(awsClient, iamUserRequest.GetIAMName())
+		if err != nil {
+			return err
+		}
+	}
+
+	secret = &corev1.Secret{
+		Type: "Opaque",
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      iamUserRequest.GetSecretName(),
+			Namespace: iamUserRequest.Namespace,
+		},
+		Data: data,
+	}
+	err = utils.SetSecretOwner(secret, iamUserRequest, r.Scheme)
+	if err != nil {
+		return err
+	}
+	err = r.Client.Create(ctx, secret)
+	if err != nil {
+		return err
+	}
+	reqLogger.Info("Created IAM principal credentials secret", "secret", secret.Name)
+	return nil
+}
+
+// setStatus sets the state of the request and explains it
+func (r *IAMUserRequestReconciler) setStatus(iamUserRequest *awsv1alpha1.IAMUserRequest, state awsv1alpha1.IAMUserRequestState, message string) error {
+	if iamUserRequest.Status.State == state && iamUserRequest.Status.Message == message {
+		return nil
+	}
+	return utils.UpdateStatusWithRetry(r.Client, iamUserRequest, func() error {
+		iamUserRequest.Status.State = state
+		iamUserRequest.Status.Message = message
+		return nil
+	})
+}
+
+// finalizeIAMUserRequest tears the principal down from the account it was created in. Nothing is left to tear down
+// when the Account or its AWS account is gone.
+func (r *IAMUserRequestReconciler) finalizeIAMUserRequest(ctx context.Context, reqLogger logr.Logger, iamUserRequest *awsv1alpha1.IAMUserRequest) error {
+	if iamUserRequest.Status.AccountLink == "" {
+		return nil
+	}
+
+	linkedAccount := &awsv1alpha1.Account{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: iamUserRequest.Status.AccountLink, Namespace: awsv1alpha1.AccountCrNamespace}, linkedAccount)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !linkedAccount.HasAwsAccountID() || linkedAccount.IsPendingDeletion() {
+		return nil
+	}
+
+	awsClient, err := r.getAccountClient(ctx, reqLogger, linkedAccount)
+	if err != nil {
+		return err
+	}
+	err = deletePrincipal(awsClient, iamUserRequest)
+	if err != nil {
+		return err
+	}
+	reqLogger.Info("Tore down the IAM principal", "account", linkedAccount.Name)
+	return nil
+}
+
+func (r *IAMUserRequestReconciler) removeFinalizer(reqLogger logr.Logger, iamUserRequest *awsv1alpha1.IAMUserRequest) error {
+	reqLogger.Info("Removing Finalizer for the IAMUserRequest")
+	controllerutil.RemoveFinalizer(iamUserRequest, utils.Finalizer)
+
+	err := r.Client.Update(context.TODO(), iamUserRequest)
+	if err != nil {
+		reqLogger.Error(err, "Failed to remove IAMUserRequest finalizer")
+		return err
+	}
+	return nil
+}
+
+// iamUserRequestsForClaim returns the requests of the principals in the account of a claim, so they're created once
+// the claim is Ready
+func (r *IAMUserRequestReconciler) iamUserRequestsForClaim(object client.Object) []reconcile.Request {
+	iamUserRequests := &awsv1alpha1.IAMUserRequestList{}
+	if err := r.Client.List(context.TODO(), iamUserRequests, client.InNamespace(object.GetNamespace())); err != nil {
+		log.Error(err, "Failed to list the IAMUserRequests of AccountClaim", "accountClaim", object.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, iamUserRequest := range iamUserRequests.Items {
+		if iamUserRequest.Spec.AccountClaim == object.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: iamUserRequest.Name, Namespace: iamUserRequest.Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IAMUserRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.awsClientBuilder = &awsclient.Builder{}
+	maxReconciles, err := utils.GetControllerMaxReconciles(controllerName)
+	if err != nil {
+		log.Error(err, "missing max reconciles for controller", "controller", controllerName)
+	}
+
+	rwm := utils.NewReconcilerWithMetrics(r, controllerName)
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&awsv1alpha1.IAMUserRequest{}, builder.WithPredicates(utils.SpecOrMetadataChanged)).
+		Owns(&corev1.Secret{}).
+		Watches(&source.Kind{Type: &awsv1alpha1.AccountClaim{}}, handler.EnqueueRequestsFromMapFunc(r.iamUserRequestsForClaim)).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: maxReconciles,
+		}).Complete(rwm)
+}
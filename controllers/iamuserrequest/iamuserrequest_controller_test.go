@@ -0,0 +1,247 @@
+package iamuserrequest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+const (
+	accountName      = "osd-creds-mgmt-aaabbb"
+	claimName        = "claim"
+	claimNamespace   = "claim-namespace"
+	requestName      = "addon"
+	managedPolicyARN = "arn:aws:iam::aws:policy/ReadOnlyAccess"
+	userARN          = "arn:aws:iam::123456789012:user/addon"
+)
+
+var requestKey = types.NamespacedName{Name: requestName, Namespace: claimNamespace}
+
+func newTestReconciler(t *testing.T, objs ...runtime.Object) (*IAMUserRequestReconciler, *mock.MockClient) {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatalf("failed adding apis to scheme: %v", err)
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      awsv1alpha1.DefaultConfigMap,
+			Namespace: awsv1alpha1.AccountCrNamespace,
+		},
+	}
+	objs = append(objs, configMap)
+	awsClientBuilder := &mock.Builder{MockController: gomock.NewController(t)}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build()
+	return NewIAMUserRequestReconciler(kubeClient, scheme.Scheme, awsClientBuilder), mock.GetMockClient(awsClientBuilder)
+}
+
+func newAccount() *awsv1alpha1.Account {
+	return &awsv1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      accountName,
+			Namespace: awsv1alpha1.AccountCrNamespace,
+		},
+		Spec: awsv1alpha1.AccountSpec{
+			AwsAccountID:       "123456789012",
+			ClaimLink:          claimName,
+			ClaimLinkNamespace: claimNamespace,
+		},
+		Status: awsv1alpha1.AccountStatus{
+			State:   string(awsv1alpha1.AccountReady),
+			Claimed: true,
+		},
+	}
+}
+
+func newAccountClaim(state awsv1alpha1.ClaimStatus) *awsv1alpha1.AccountClaim {
+	return &awsv1alpha1.AccountClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claimName,
+			Namespace: claimNamespace,
+			UID:       "claim-uid",
+		},
+		Spec: awsv1alpha1.AccountClaimSpec{
+			AccountLink: accountName,
+		},
+		Status: awsv1alpha1.AccountClaimStatus{
+			State: state,
+		},
+	}
+}
+
+func newIAMUserRequest() *awsv1alpha1.IAMUserRequest {
+	return &awsv1alpha1.IAMUserRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      requestName,
+			Namespace: claimNamespace,
+		},
+		Spec: awsv1alpha1.IAMUserRequestSpec{
+			AccountClaim:      claimName,
+			PrincipalType:     awsv1alpha1.IAMPrincipalUser,
+			ManagedPolicyARNs: []string{managedPolicyARN},
+			InlinePolicy: &awsv1alpha1.AWSCustomPolicy{
+				Name:        "addon-s3",
+				Description: "S3 access of the add-on",
+				Statements: []awsv1alpha1.StatementEntry{{
+					Effect:   "Allow",
+					Action:   []string{"s3:GetObject"},
+					Resource: []string{"arn:aws:s3:::addon/*"},
+				}},
+			},
+		},
+	}
+}
+
+func expectAssumeRole(mockAWSClient *mock.MockClient) {
+	mockAWSClient.EXPECT().AssumeRole(gomock.Any()).Return(&sts.AssumeRoleOutput{
+		AssumedRoleUser: &sts.AssumedRoleUser{
+			Arn:           aws.String("aws:::OrganizationAccountAccessRole/awsAccountOperator"),
+			AssumedRoleId: aws.String("OrganizationAccountAccessRole/awsAccountOperator"),
+		},
+		Credentials: &sts.Credentials{
+			AccessKeyId:     aws.String("ACCESS_KEY"),
+			SecretAccessKey: aws.String("SECRET_KEY"),
+			SessionToken:    aws.String("SESSION_TOKEN"),
+		},
+	}, nil)
+}
+
+// reconcileUntilDone reconciles the request until the finalizer and owner reference are set and the principal is
+// handled
+func reconcileUntilDone(t *testing.T, r *IAMUserRequestReconciler) {
+	for i := 0; i < 3; i++ {
+		_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: requestKey})
+		assert.NoError(t, err)
+	}
+}
+
+func TestReconcileCreatesUserAndSecret(t *testing.T) {
+	r, mockAWSClient := newTestReconciler(t, newAccount(), newAccountClaim(awsv1alpha1.ClaimStatusReady), newIAMUserRequest())
+
+	expectAssumeRole(mockAWSClient)
+	mockAWSClient.EXPECT().GetUser(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil))
+	mockAWSClient.EXPECT().CreateUser(gomock.Any()).Return(&iam.CreateUserOutput{User: &iam.User{Arn: aws.String(userARN)}}, nil)
+	mockAWSClient.EXPECT().ListAttachedUserPolicies(gomock.Any()).Return(&iam.ListAttachedUserPoliciesOutput{}, nil)
+	mockAWSClient.EXPECT().AttachUserPolicy(&iam.AttachUserPolicyInput{UserName: aws.String(requestName), PolicyArn: aws.String(managedPolicyARN)}).Return(&iam.AttachUserPolicyOutput{}, nil)
+	mockAWSClient.EXPECT().ListUserPolicies(gomock.Any()).Return(&iam.ListUserPoliciesOutput{}, nil)
+	mockAWSClient.EXPECT().PutUserPolicy(gomock.Any()).Return(&iam.PutUserPolicyOutput{}, nil)
+	mockAWSClient.EXPECT().ListAccessKeys(gomock.Any()).Return(&iam.ListAccessKeysOutput{}, nil)
+	mockAWSClient.EXPECT().CreateAccessKey(gomock.Any()).Return(&iam.CreateAccessKeyOutput{
+		AccessKey: &iam.AccessKey{
+			UserName:        aws.String(requestName),
+			AccessKeyId:     aws.String("AKIA"),
+			SecretAccessKey: aws.String("secret"),
+		},
+	}, nil)
+
+	reconcileUntilDone(t, r)
+
+	iamUserRequest := &awsv1alpha1.IAMUserRequest{}
+	assert.NoError(t, r.Client.Get(context.TODO(), requestKey, iamUserRequest))
+	assert.Equal(t, awsv1alpha1.IAMUserRequestReady, iamUserRequest.Status.State)
+	assert.Equal(t, userARN, iamUserRequest.Status.PrincipalARN)
+	assert.Equal(t, accountName, iamUserRequest.Status.AccountLink)
+	assert.Equal(t, &awsv1alpha1.SecretRef{Name: "addon-iam-credentials", Namespace: claimNamespace}, iamUserRequest.Status.SecretRef)
+	assert.Contains(t, iamUserRequest.Finalizers, utils.Finalizer)
+	assert.Len(t, iamUserRequest.OwnerReferences, 1)
+
+	secret := &corev1.Secret{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "addon-iam-credentials", Namespace: claimNamespace}, secret))
+	assert.Equal(t, "AKIA", string(secret.Data[secretAccessKeyID]))
+	assert.Equal(t, "secret", string(secret.Data[secretSecretAccessKey]))
+	assert.True(t, metav1.IsControlledBy(secret, iamUserRequest))
+}
+
+func TestReconcileWaitsForClaim(t *testing.T) {
+	r, _ := newTestReconciler(t, newAccount(), newAccountClaim(awsv1alpha1.ClaimStatusPending), newIAMUserRequest())
+
+	// No AWS call is expected
+	reconcileUntilDone(t, r)
+
+	iamUserRequest := &awsv1alpha1.IAMUserRequest{}
+	assert.NoError(t, r.Client.Get(context.TODO(), requestKey, iamUserRequest))
+	assert.Equal(t, awsv1alpha1.IAMUserRequestPending, iamUserRequest.Status.State)
+	assert.Empty(t, iamUserRequest.Status.AccountLink)
+}
+
+func TestReconcileDeletionTearsDownUser(t *testing.T) {
+	now := metav1.Now()
+	iamUserRequest := newIAMUserRequest()
+	iamUserRequest.DeletionTimestamp = &now
+	iamUserRequest.Finalizers = []string{utils.Finalizer}
+	iamUserRequest.Status.AccountLink = accountName
+	r, mockAWSClient := newTestReconciler(t, newAccount(), iamUserRequest)
+
+	expectAssumeRole(mockAWSClient)
+	mockAWSClient.EXPECT().ListAttachedUserPolicies(gomock.Any()).Return(&iam.ListAttachedUserPoliciesOutput{
+		AttachedPolicies: []*iam.AttachedPolicy{{PolicyArn: aws.String(managedPolicyARN)}},
+	}, nil)
+	mockAWSClient.EXPECT().DetachUserPolicy(gomock.Any()).Return(&iam.DetachUserPolicyOutput{}, nil)
+	mockAWSClient.EXPECT().ListUserPolicies(gomock.Any()).Return(&iam.ListUserPoliciesOutput{PolicyNames: aws.StringSlice([]string{"addon-s3"})}, nil)
+	mockAWSClient.EXPECT().DeleteUserPolicy(gomock.Any()).Return(&iam.DeleteUserPolicyOutput{}, nil)
+	mockAWSClient.EXPECT().ListAccessKeys(gomock.Any()).Return(&iam.ListAccessKeysOutput{
+		AccessKeyMetadata: []*iam.AccessKeyMetadata{{AccessKeyId: aws.String("AKIA")}},
+	}, nil)
+	mockAWSClient.EXPECT().DeleteAccessKey(gomock.Any()).Return(&iam.DeleteAccessKeyOutput{}, nil)
+	mockAWSClient.EXPECT().DeleteUser(gomock.Any()).Return(&iam.DeleteUserOutput{}, nil)
+
+	_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: requestKey})
+	assert.NoError(t, err)
+
+	deleted := &awsv1alpha1.IAMUserRequest{}
+	err = r.Client.Get(context.TODO(), requestKey, deleted)
+	if err == nil {
+		assert.NotContains(t, deleted.Finalizers, utils.Finalizer)
+	} else {
+		assert.True(t, k8serr.IsNotFound(err))
+	}
+}
+
+func TestDeletePrincipalAlreadyDeleted(t *testing.T) {
+	_, mockAWSClient := newTestReconciler(t)
+	iamUserRequest := newIAMUserRequest()
+	iamUserRequest.Spec.PrincipalType = awsv1alpha1.IAMPrincipalRole
+
+	mockAWSClient.EXPECT().ListAttachedRolePolicies(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil))
+
+	assert.NoError(t, deletePrincipal(mockAWSClient, iamUserRequest))
+}
+
+func TestValidateRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		principalType awsv1alpha1.IAMPrincipalType
+		trustedARNs   []string
+		valid         bool
+	}{
+		{name: "user", principalType: awsv1alpha1.IAMPrincipalUser, valid: true},
+		{name: "user with trusted ARNs", principalType: awsv1alpha1.IAMPrincipalUser, trustedARNs: []string{"arn:aws:iam::111111111111:root"}},
+		{name: "role", principalType: awsv1alpha1.IAMPrincipalRole, trustedARNs: []string{"arn:aws:iam::111111111111:root"}, valid: true},
+		{name: "role without trusted ARNs", principalType: awsv1alpha1.IAMPrincipalRole},
+		{name: "unknown type", principalType: "Group"},
+	}
+	for _, test := range tests {
+		iamUserRequest := newIAMUserRequest()
+		iamUserRequest.Spec.PrincipalType = test.principalType
+		iamUserRequest.Spec.TrustedARNs = test.trustedARNs
+		err := validateRequest(iamUserRequest)
+		assert.Equal(t, test.valid, err == nil, test.name)
+	}
+}
@@ -0,0 +1,344 @@
+package iamuserrequest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-logr/logr"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// Keys of the credentials secret, the ones of users match the secrets of the Account IAM users
+const (
+	secretUserName        = "aws_user_name"
+	secretAccessKeyID     = "aws_access_key_id"
+	secretSecretAccessKey = "aws_secret_access_key"
+	secretRoleARN         = "role_arn"
+)
+
+// policyAPI attaches and puts policies on a user or a role
+type policyAPI struct {
+	listAttached func() ([]string, error)
+	attach       func(policyARN string) error
+	detach       func(policyARN string) error
+	listInline   func() ([]string, error)
+	putInline    func(name string, document string) error
+	deleteInline func(name string) error
+}
+
+// ensurePrincipal creates the user or role of the request if it doesn't exist, syncs its policies with the request
+// and returns its ARN
+func ensurePrincipal(reqLogger logr.Logger, awsClient awsclient.Client, iamUserRequest *awsv1alpha1.IAMUserRequest, linkedAccount *awsv1alpha1.Account) (string, error) {
+	tags := awsclient.AWSTags.BuildTags(linkedAccount, nil, nil).GetIAMTags()
+	iamName := iamUserRequest.GetIAMName()
+
+	var principalARN string
+	var policies policyAPI
+	var err error
+	if iamUserRequest.Spec.PrincipalType == awsv1alpha1.IAMPrincipalRole {
+		principalARN, err = ensureRole(reqLogger, awsClient, iamName, iamUserRequest.Spec.TrustedARNs, tags)
+		policies = rolePolicyAPI(awsClient, iamName)
+	} else {
+		principalARN, err = ensureUser(reqLogger, awsClient, iamName, tags)
+		policies = userPolicyAPI(awsClient, iamName)
+	}
+	if err != nil {
+		return "", invalidRequestOr(err)
+	}
+
+	err = syncPolicies(policies, iamUserRequest.Spec.ManagedPolicyARNs, iamUserRequest.Spec.InlinePolicy)
+	if err != nil {
+		return "", invalidRequestOr(err)
+	}
+	return principalARN, nil
+}
+
+// ensureUser creates the IAM user if it doesn't exist and returns its ARN
+func ensureUser(reqLogger logr.Logger, awsClient awsclient.Client, userName string, tags []*iam.Tag) (string, error) {
+	getUserOutput, err := awsClient.GetUser(&iam.GetUserInput{UserName: aws.String(userName)})
+	if err == nil {
+		return aws.StringValue(getUserOutput.User.Arn), nil
+	}
+	if !isNoSuchEntity(err) {
+		return "", err
+	}
+
+	createUserOutput, err := awsClient.CreateUser(&iam.CreateUserInput{
+		UserName: aws.String(userName),
+		Tags:     tags,
+	})
+	if err != nil {
+		return "", err
+	}
+	reqLogger.Info("Created IAM user")
+	return aws.StringValue(createUserOutput.User.Arn), nil
+}
+
+// ensureRole creates the IAM role if it doesn't exist, trusting the given ARNs, and returns its ARN
+func ensureRole(reqLogger logr.Logger, awsClient awsclient.Client, roleName string, trustedARNs []string, tags []*iam.Tag) (string, error) {
+	trustPolicy, err := buildTrustPolicy(trustedARNs)
+	if err != nil {
+		return "", err
+	}
+
+	getRoleOutput, err := awsClient.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)})
+	if err == nil {
+		// The trusted ARNs may have changed since the role was created
+		_, err = awsClient.UpdateAssumeRolePolicy(&iam.UpdateAssumeRolePolicyInput{
+			RoleName:       aws.String(roleName),
+			PolicyDocument: aws.String(trustPolicy),
+		})
+		if err != nil {
+			return "", err
+		}
+		return aws.StringValue(getRoleOutput.Role.Arn), nil
+	}
+	if !isNoSuchEntity(err) {
+		return "", err
+	}
+
+	createRoleOutput, err := awsClient.CreateRole(&iam.CreateRoleInput{
+		RoleName:                 aws.String(roleName),
+		Description:              aws.String("Requested by an IAMUserRequest"),
+		AssumeRolePolicyDocument: aws.String(trustPolicy),
+		Tags:                     tags,
+	})
+	if err != nil {
+		return "", err
+	}
+	reqLogger.Info("Created IAM role")
+	return aws.StringValue(createRoleOutput.Role.Arn), nil
+}
+
+// buildTrustPolicy returns the assume role policy document of a role trusting the given ARNs
+func buildTrustPolicy(trustedARNs []string) (string, error) {
+	trustPolicy := utils.AwsPolicy{
+		Version: "2012-10-17",
+		Statement: []utils.AwsStatement{{
+			Effect:    "Allow",
+			Action:    []string{"sts:AssumeRole"},
+			Principal: &awsv1alpha1.Principal{AWS: trustedARNs},
+		}},
+	}
+
+	jsonTrustPolicy, err := json.Marshal(&trustPolicy)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonTrustPolicy), nil
+}
+
+// syncPolicies attaches the managed policies and puts the inline policy of the request on the principal, and removes
+// the other ones
+func syncPolicies(policies policyAPI, managedPolicyARNs []string, inlinePolicy *awsv1alpha1.AWSCustomPolicy) error {
+	attached, err := policies.listAttached()
+	if err != nil {
+		return err
+	}
+	for _, policyARN := range managedPolicyARNs {
+		if !utils.Contains(attached, policyARN) {
+			if err = policies.attach(policyARN); err != nil {
+				return fmt.Errorf("unable to attach policy %s: %w", policyARN, err)
+			}
+		}
+	}
+	for _, policyARN := range attached {
+		if !utils.Contains(managedPolicyARNs, policyARN) {
+			if err = policies.detach(policyARN); err != nil {
+				return fmt.Errorf("unable to detach policy %s: %w", policyARN, err)
+			}
+		}
+	}
+
+	inline, err := policies.listInline()
+	if err != nil {
+		return err
+	}
+	for _, name := range inline {
+		if inlinePolicy == nil || name != inlinePolicy.Name {
+			if err = policies.deleteInline(name); err != nil {
+				return fmt.Errorf("unable to delete inline policy %s: %w", name, err)
+			}
+		}
+	}
+	if inlinePolicy == nil {
+		return nil
+	}
+	document, err := utils.MarshalCustomPolicy(*inlinePolicy)
+	if err != nil {
+		return err
+	}
+	if err = policies.putInline(inlinePolicy.Name, document); err != nil {
+		return fmt.Errorf("unable to put inline policy %s: %w", inlinePolicy.Name, err)
+	}
+	return nil
+}
+
+// createUserCredentials replaces the access keys of the user with a new one and returns the data of its secret
+func createUserCredentials(awsClient awsclient.Client, userName string) (map[string][]byte, error) {
+	// A user has at most two access keys, the ones of a deleted secret are lost anyway
+	err := deleteAccessKeys(awsClient, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	createAccessKeyOutput, err := awsClient.CreateAccessKey(&iam.CreateAccessKeyInput{UserName: aws.String(userName)})
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		secretUserName:        []byte(aws.StringValue(createAccessKeyOutput.AccessKey.UserName)),
+		secretAccessKeyID:     []byte(aws.StringValue(createAccessKeyOutput.AccessKey.AccessKeyId)),
+		secretSecretAccessKey: []byte(aws.StringValue(createAccessKeyOutput.AccessKey.SecretAccessKey)),
+	}, nil
+}
+
+// deleteAccessKeys deletes the access keys of the user
+func deleteAccessKeys(awsClient awsclient.Client, userName string) error {
+	accessKeys, err := awsClient.ListAccessKeys(&iam.ListAccessKeysInput{UserName: aws.String(userName)})
+	if err != nil {
+		return err
+	}
+	for _, accessKey := range accessKeys.AccessKeyMetadata {
+		_, err = awsClient.DeleteAccessKey(&iam.DeleteAccessKeyInput{
+			UserName:    aws.String(userName),
+			AccessKeyId: accessKey.AccessKeyId,
+		})
+		if err != nil && !isNoSuchEntity(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// deletePrincipal removes the policies of the user or role of the request and deletes it. A principal that doesn't
+// exist is already torn down.
+func deletePrincipal(awsClient awsclient.Client, iamUserRequest *awsv1alpha1.IAMUserRequest) error {
+	iamName := iamUserRequest.GetIAMName()
+
+	var err error
+	if iamUserRequest.Spec.PrincipalType == awsv1alpha1.IAMPrincipalRole {
+		err = syncPolicies(rolePolicyAPI(awsClient, iamName), nil, nil)
+		if err == nil {
+			_, err = awsClient.DeleteRole(&iam.DeleteRoleInput{RoleName: aws.String(iamName)})
+		}
+	} else {
+		err = syncPolicies(userPolicyAPI(awsClient, iamName), nil, nil)
+		if err == nil {
+			err = deleteAccessKeys(awsClient, iamName)
+		}
+		if err == nil {
+			_, err = awsClient.DeleteUser(&iam.DeleteUserInput{UserName: aws.String(iamName)})
+		}
+	}
+	if err != nil && !isNoSuchEntity(err) {
+		return err
+	}
+	return nil
+}
+
+// userPolicyAPI returns the policyAPI of an IAM user
+func userPolicyAPI(awsClient awsclient.Client, userName string) policyAPI {
+	return policyAPI{
+		listAttached: func() ([]string, error) {
+			output, err := awsClient.ListAttachedUserPolicies(&iam.ListAttachedUserPoliciesInput{UserName: aws.String(userName)})
+			if err != nil {
+				return nil, err
+			}
+			var policyARNs []string
+			for _, policy := range output.AttachedPolicies {
+				policyARNs = append(policyARNs, aws.StringValue(policy.PolicyArn))
+			}
+			return policyARNs, nil
+		},
+		attach: func(policyARN string) error {
+			_, err := awsClient.AttachUserPolicy(&iam.AttachUserPolicyInput{UserName: aws.String(userName), PolicyArn: aws.String(policyARN)})
+			return err
+		},
+		detach: func(policyARN string) error {
+			_, err := awsClient.DetachUserPolicy(&iam.DetachUserPolicyInput{UserName: aws.String(userName), PolicyArn: aws.String(policyARN)})
+			return err
+		},
+		listInline: func() ([]string, error) {
+			output, err := awsClient.ListUserPolicies(&iam.ListUserPoliciesInput{UserName: aws.String(userName)})
+			if err != nil {
+				return nil, err
+			}
+			return aws.StringValueSlice(output.PolicyNames), nil
+		},
+		putInline: func(name string, document string) error {
+			_, err := awsClient.PutUserPolicy(&iam.PutUserPolicyInput{UserName: aws.String(userName), PolicyName: aws.String(name), PolicyDocument: aws.String(document)})
+			return err
+		},
+		deleteInline: func(name string) error {
+			_, err := awsClient.DeleteUserPolicy(&iam.DeleteUserPolicyInput{UserName: aws.String(userName), PolicyName: aws.String(name)})
+			return err
+		},
+	}
+}
+
+// rolePolicyAPI returns the policyAPI of an IAM role
+func rolePolicyAPI(awsClient awsclient.Client, roleName string) policyAPI {
+	return policyAPI{
+		listAttached: func() ([]string, error) {
+			output, err := awsClient.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{RoleName: aws.String(roleName)})
+			if err != nil {
+				return nil, err
+			}
+			var policyARNs []string
+			for _, policy := range output.AttachedPolicies {
+				policyARNs = append(policyARNs, aws.StringValue(policy.PolicyArn))
+			}
+			return policyARNs, nil
+		},
+		attach: func(policyARN string) error {
+			_, err := awsClient.AttachRolePolicy(&iam.AttachRolePolicyInput{RoleName: aws.String(roleName), PolicyArn: aws.String(policyARN)})
+			return err
+		},
+		detach: func(policyARN string) error {
+			_, err := awsClient.DetachRolePolicy(&iam.DetachRolePolicyInput{RoleName: aws.String(roleName), PolicyArn: aws.String(policyARN)})
+			return err
+		},
+		listInline: func() ([]string, error) {
+			output, err := awsClient.ListRolePolicies(&iam.ListRolePoliciesInput{RoleName: aws.String(roleName)})
+			if err != nil {
+				return nil, err
+			}
+			return aws.StringValueSlice(output.PolicyNames), nil
+		},
+		putInline: func(name string, document string) error {
+			_, err := awsClient.PutRolePolicy(&iam.PutRolePolicyInput{RoleName: aws.String(roleName), PolicyName: aws.String(name), PolicyDocument: aws.String(document)})
+			return err
+		},
+		deleteInline: func(name string) error {
+			_, err := awsClient.DeleteRolePolicy(&iam.DeleteRolePolicyInput{RoleName: aws.String(roleName), PolicyName: aws.String(name)})
+			return err
+		},
+	}
+}
+
+// isNoSuchEntity returns true if the error is an IAM NoSuchEntity error
+func isNoSuchEntity(err error) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == iam.ErrCodeNoSuchEntityException
+}
+
+// invalidRequestOr wraps the errors AWS returns for invalid names and policies in errInvalidRequest, they aren't
+// retried until the request is fixed
+func invalidRequestOr(err error) error {
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		switch aerr.Code() {
+		case iam.ErrCodeMalformedPolicyDocumentException, iam.ErrCodeInvalidInputException:
+			return fmt.Errorf("%w: %v", errInvalidRequest, err)
+		}
+	}
+	return err
+}
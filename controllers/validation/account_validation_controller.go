@@ -18,6 +18,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/organizations"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -82,7 +83,7 @@ func NewAccountValidationReconciler(client client.Client, scheme *runtime.Scheme
 }
 
 func (r *AccountValidationReconciler) statusUpdate(account *awsv1alpha1.Account) error {
-	err := r.Client.Status().Update(context.TODO(), account)
+	err := utils.UpdateStatus(r.Client, account)
 	return err
 }
 
@@ -679,7 +680,7 @@ func (r *AccountValidationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	rwm := utils.NewReconcilerWithMetrics(r, controllerName)
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&awsv1alpha1.Account{}).
+		For(&awsv1alpha1.Account{}, builder.WithPredicates(utils.InShardFilter, utils.IgnoreReconcileOutcomeUpdates)).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxReconciles,
 		}).Complete(rwm)
@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -86,8 +87,9 @@ func (r *AccountPoolValidationReconciler) Reconcile(ctx context.Context, request
 	return utils.DoNotRequeue()
 }
 
-func (r *AccountPoolValidationReconciler) accountSpecUpdate(reqLogger logr.Logger, account *awsv1alpha1.Account) error {
-	err := r.Client.Update(context.TODO(), account)
+// accountSpecUpdate applies mutate to the account and updates it, retrying on conflicts
+func (r *AccountPoolValidationReconciler) accountSpecUpdate(reqLogger logr.Logger, account *awsv1alpha1.Account, mutate func() error) error {
+	err := utils.UpdateWithRetry(r.Client, account, mutate)
 	if err != nil {
 		reqLogger.Error(err, fmt.Sprintf("Account spec update for %s failed", account.Name))
 	}
@@ -95,7 +97,7 @@ func (r *AccountPoolValidationReconciler) accountSpecUpdate(reqLogger logr.Logge
 }
 
 func (r *AccountPoolValidationReconciler) accountStatusUpdate(reqLogger logr.Logger, account *awsv1alpha1.Account) error {
-	err := r.Client.Status().Update(context.TODO(), account)
+	err := utils.UpdateStatus(r.Client, account)
 	if err != nil {
 		reqLogger.Error(err, fmt.Sprintf("Account status update for %s failed", account.Name))
 	}
@@ -134,10 +136,11 @@ func (r *AccountPoolValidationReconciler) checkAccountServiceQuota(reqLogger log
 	for _, account := range accountList {
 		accountCopy := account
 		if !reflect.DeepEqual(accountCopy.Spec.RegionalServiceQuotas, parsedRegionalServiceQuotas) && isEnabled {
-			accountCopy.Spec.RegionalServiceQuotas = parsedRegionalServiceQuotas
-
 			reqLogger.Info(fmt.Sprintf("Attempting to update the account Spec for: %v", accountCopy.Name))
-			err = r.accountSpecUpdate(reqLogger, &accountCopy)
+			err = r.accountSpecUpdate(reqLogger, &accountCopy, func() error {
+				accountCopy.Spec.RegionalServiceQuotas = parsedRegionalServiceQuotas
+				return nil
+			})
 			if err != nil {
 				logs.Error(err, "failed to update account spec", "account", accountCopy.Name)
 				return reconcile.Result{}, err
@@ -190,7 +193,7 @@ func (r *AccountPoolValidationReconciler) SetupWithManager(mgr ctrl.Manager) err
 
 	rwm := utils.NewReconcilerWithMetrics(r, validationControllerName)
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&awsv1alpha1.AccountPool{}).
+		For(&awsv1alpha1.AccountPool{}, builder.WithPredicates(utils.SpecOrMetadataChanged)).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxReconciles,
 		}).Complete(rwm)
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"runtime"
+	"strings"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -30,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
 	routev1 "github.com/openshift/api/route/v1"
+	userv1 "github.com/openshift/api/user/v1"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
@@ -38,18 +40,56 @@ import (
 	"github.com/openshift/aws-account-operator/controllers/account"
 	"github.com/openshift/aws-account-operator/controllers/accountclaim"
 	"github.com/openshift/aws-account-operator/controllers/accountpool"
+	"github.com/openshift/aws-account-operator/controllers/awsaccountquota"
+	"github.com/openshift/aws-account-operator/controllers/awsfederatedaccessgroup"
 	"github.com/openshift/aws-account-operator/controllers/awsfederatedaccountaccess"
 	"github.com/openshift/aws-account-operator/controllers/awsfederatedrole"
+	"github.com/openshift/aws-account-operator/controllers/cleanupverification"
+	"github.com/openshift/aws-account-operator/controllers/iamuserrequest"
 	"github.com/openshift/aws-account-operator/controllers/validation"
+	"github.com/openshift/aws-account-operator/pkg/audit"
 	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/cloudevents"
+	"github.com/openshift/aws-account-operator/pkg/fleetstatus"
+	"github.com/openshift/aws-account-operator/pkg/health"
 	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/notifier"
 	"github.com/openshift/aws-account-operator/pkg/totalaccountwatcher"
+	"github.com/openshift/aws-account-operator/pkg/tracing"
 	"github.com/openshift/aws-account-operator/pkg/utils"
 	"github.com/openshift/aws-account-operator/version"
 	"github.com/openshift/operator-custom-metrics/pkg/metrics"
 	//+kubebuilder:scaffold:imports
 )
 
+// logFormatEnvVar overrides the log encoder, "json" or "console"
+const logFormatEnvVar = "LOG_FORMAT"
+
+// cacheSyncCheckTimeout is how long the health checks wait for the informers to be synced
+const cacheSyncCheckTimeout = 5 * time.Second
+
+// Operator ConfigMap keys configuring where the audit trail is shipped in CloudWatch Logs
+const (
+	auditLogGroupKey      = "audit.cloudwatch-log-group"
+	auditLogStreamKey     = "audit.cloudwatch-log-stream"
+	defaultAuditLogStream = "aws-account-operator"
+)
+
+// Operator ConfigMap keys configuring the HTTP sink the account lifecycle CloudEvents are sent to
+const (
+	cloudEventsSinkURLKey = "cloudevents.sink-url"
+	cloudEventsSourceKey  = "cloudevents.source"
+)
+
+// Operator ConfigMap keys configuring the sinks of the notifications of critical account events
+const (
+	notificationsWebhookURLKey   = "notifications.webhook-url"
+	notificationsSlackURLKey     = "notifications.slack-webhook-url"
+	notificationsSlackChannelKey = "notifications.slack-channel"
+	notificationsPagerDutyKey    = "notifications.pagerduty-routing-key"
+	notificationsKindsKey        = "notifications.kinds"
+)
+
 // Change below variables to serve metrics on different host or port.
 var (
 	customMetricsPort string = "8080"
@@ -65,6 +105,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(awsv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(routev1.Install(scheme))
+	utilruntime.Must(userv1.Install(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -78,12 +119,29 @@ func printVersion() {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var enableCleanupVerification bool
 	var probeAddr string
+	var maxConcurrentReconciles string
+	var gracefulShutdownTimeout time.Duration
+	var shardID, shardCount int
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8081", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":9081", "The address the probe endpoint binds to.")
+	flag.StringVar(&maxConcurrentReconciles, "max-concurrent-reconciles", "",
+		"Comma separated controller=value pairs, e.g. account=10,accountclaim=4, overriding the "+
+			"MaxConcurrentReconciles.<controller> keys of the operator configmap.")
+	flag.DurationVar(&gracefulShutdownTimeout, "graceful-shutdown-timeout", utils.DefaultGracefulShutdownTimeout,
+		"How long the in-flight reconciles, e.g. account cleanups, are given to finish when the operator is stopped.")
+	flag.IntVar(&shardCount, "shard-count", 1,
+		"The number of shards the Accounts and AccountClaims are split between, each run by its own operator replicas.")
+	flag.IntVar(&shardID, "shard-id", 0,
+		"The shard of the Accounts and AccountClaims this replica reconciles, from 0 to --shard-count - 1. "+
+			"Shard 0 also runs the other controllers and the fleet-wide routines.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&enableCleanupVerification, "enable-cleanup-verification", false,
+		"Run the CleanupVerification controller, which seeds accounts and checks the account cleanup removes everything. "+
+			"Only for the CI of disposable accounts, as the seeded accounts are wiped.")
 
 	opts := zap.Options{
 		Development: false,
@@ -95,8 +153,33 @@ func main() {
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	logOpts := []zap.Opts{zap.UseFlagOptions(&opts)}
+	switch strings.ToLower(os.Getenv(logFormatEnvVar)) {
+	case "json":
+		logOpts = append(logOpts, zap.JSONEncoder())
+	case "console":
+		logOpts = append(logOpts, zap.ConsoleEncoder())
+	}
+	ctrl.SetLogger(zap.New(logOpts...))
 	printVersion()
+	if utils.IsObserveOnly() {
+		setupLog.Info(fmt.Sprintf("%s is set, mutating AWS calls are only planned", utils.ObserveOnlyEnvVar))
+	}
+	if utils.IsLocalStack() {
+		// Support cases can't be filed in LocalStack, only the development modes skip them
+		if utils.DetectDevMode == utils.DevModeProduction {
+			setupLog.Error(fmt.Errorf("%s requires FORCE_DEV_MODE", utils.LocalStackEndpointEnvVar), "Invalid LocalStack mode")
+			os.Exit(1)
+		}
+		setupLog.Info(fmt.Sprintf("%s is set, AWS calls go to LocalStack and Organizations is simulated", utils.LocalStackEndpointEnvVar), "endpoint", utils.LocalStackEndpoint())
+	}
+	if err := utils.ConfigureSharding(shardID, shardCount); err != nil {
+		setupLog.Error(err, "Invalid sharding flags")
+		os.Exit(1)
+	}
+	if utils.IsSharded() {
+		setupLog.Info("Reconciling a shard of the Accounts and AccountClaims", "shard", shardID, "shards", shardCount)
+	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
@@ -104,7 +187,9 @@ func main() {
 		Port:                   9443,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "c0d5a6d1.managed.openshift.io",
+		LeaderElectionID:       utils.ShardLockName("c0d5a6d1.managed.openshift.io"),
+		// The reconciles see their context canceled on SIGTERM, the manager waits for them before exiting
+		GracefulShutdownTimeout: &gracefulShutdownTimeout,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -114,7 +199,7 @@ func main() {
 	// Become the leader before proceeding
 	// This doesn't work locally, so only perform it when running on-cluster
 	if utils.DetectDevMode != utils.DevModeLocal {
-		err = leader.Become(context.TODO(), "aws-account-operator-lock")
+		err = leader.Become(context.TODO(), utils.ShardLockName("aws-account-operator-lock"))
 		if err != nil {
 			setupLog.Error(err, "Unable to become leader")
 			os.Exit(1)
@@ -146,6 +231,10 @@ func main() {
 			setupLog.Error(err, "")
 		}
 	}
+	if err = utils.OverrideControllerMaxReconciles(maxConcurrentReconciles); err != nil {
+		setupLog.Error(err, "Invalid --max-concurrent-reconciles")
+		os.Exit(1)
+	}
 
 	if err = (&accountclaim.AccountClaimReconciler{
 		Client: mgr.GetClient(),
@@ -154,25 +243,11 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "AccountClaim")
 		os.Exit(1)
 	}
-	if err = (&awsfederatedrole.AWSFederatedRoleReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "AWSFederatedRole")
-		os.Exit(1)
-	}
-	if err = (&awsfederatedaccountaccess.AWSFederatedAccountAccessReconciler{
+	if err = (&accountclaim.AccountCleanupReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "AWSFederatedAccountAccess")
-		os.Exit(1)
-	}
-	if err = (&accountpool.AccountPoolReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "AccountPool")
+		setupLog.Error(err, "unable to create controller", "controller", "AccountCleanup")
 		os.Exit(1)
 	}
 	if err = (&account.AccountReconciler{
@@ -189,13 +264,67 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "AccountValidation")
 		os.Exit(1)
 	}
-	if err = (&validation.AccountPoolValidationReconciler{
+	if err = (&awsaccountquota.AWSAccountQuotaReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "AccountPoolValidation")
+		setupLog.Error(err, "unable to create controller", "controller", "AWSAccountQuota")
 		os.Exit(1)
 	}
+	// The controllers of the CRs that aren't sharded only run in the primary shard
+	if utils.IsPrimaryShard() {
+		if err = (&awsfederatedrole.AWSFederatedRoleReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AWSFederatedRole")
+			os.Exit(1)
+		}
+		if err = (&awsfederatedaccountaccess.AWSFederatedAccountAccessReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AWSFederatedAccountAccess")
+			os.Exit(1)
+		}
+		if err = (&awsfederatedaccessgroup.AWSFederatedAccessGroupReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AWSFederatedAccessGroup")
+			os.Exit(1)
+		}
+		if err = (&accountpool.AccountPoolReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AccountPool")
+			os.Exit(1)
+		}
+		if err = (&validation.AccountPoolValidationReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AccountPoolValidation")
+			os.Exit(1)
+		}
+		if err = (&iamuserrequest.IAMUserRequestReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "IAMUserRequest")
+			os.Exit(1)
+		}
+		if enableCleanupVerification {
+			if err = (&cleanupverification.CleanupVerificationReconciler{
+				Client: mgr.GetClient(),
+				Scheme: mgr.GetScheme(),
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "CleanupVerification")
+				os.Exit(1)
+			}
+		}
+	}
 
 	//+kubebuilder:scaffold:builder
 
@@ -203,10 +332,24 @@ func main() {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
+	// A wedged operator whose informers stopped syncing is restarted
+	if err := mgr.AddHealthzCheck("informers", health.CacheSynced(mgr.GetCache(), cacheSyncCheckTimeout)); err != nil {
+		setupLog.Error(err, "unable to set up informers health check")
+		os.Exit(1)
+	}
 	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("informers", health.CacheSynced(mgr.GetCache(), cacheSyncCheckTimeout)); err != nil {
+		setupLog.Error(err, "unable to set up informers ready check")
+		os.Exit(1)
+	}
+	// The operator isn't ready until it can reach AWS with the payer account credentials
+	if err := mgr.AddReadyzCheck("aws", health.NewAWSConnectivity(kubeClient, &awsclient.Builder{}).Check); err != nil {
+		setupLog.Error(err, "unable to set up AWS ready check")
+		os.Exit(1)
+	}
 
 	// initialize metrics collector
 	localmetrics.Collector = localmetrics.NewMetricsCollector(mgr.GetCache())
@@ -243,6 +386,9 @@ func main() {
 		}
 	}
 
+	// Export reconcile and AWS call traces if an OTLP endpoint is configured
+	tracing.InitFromEnv(ctrl.Log.WithName("tracing"))
+
 	// Define stopCh which we'll use to notify the accountWatcher (any any other routine)
 	// to stop work. This channel can also be used to signal routines to complete any cleanup
 	// work
@@ -251,14 +397,85 @@ func main() {
 	// Initialize our ConfigMap with default values if necessary.
 	initOperatorConfigMapVars(kubeClient)
 
-	// Initialize the TotalAccountWatcher
-	go totalaccountwatcher.TotalAccountWatcher.Start(setupLog, stopCh, kubeClient, totalWatcherInterval)
+	// Initialize the audit trail of destructive AWS operations
+	audit.Trail = audit.NewAuditTrail(kubeClient, awsv1alpha1.AccountCrNamespace)
+	initAuditCloudWatch(kubeClient)
+	go audit.Trail.Start(setupLog, stopCh)
+
+	// Publish the account lifecycle CloudEvents if a sink is configured
+	initCloudEvents(kubeClient, stopCh)
+
+	// Notify the critical account events if a webhook or Slack is configured
+	initNotifier(kubeClient, stopCh)
+
+	// Periodically audit the IAM principals of Ready accounts for drift
+	iamDriftWatcher := account.NewIAMDriftWatcher(kubeClient, &awsclient.Builder{}, mgr.GetEventRecorderFor("iam-drift-watcher"))
+	go iamDriftWatcher.Start(setupLog, stopCh)
+
+	// Periodically verify that the organization trail covers the Ready accounts and delivers their logs. The trail
+	// itself is only remediated from the primary shard.
+	cloudTrailWatcher := account.NewCloudTrailWatcher(kubeClient, &awsclient.Builder{}, mgr.GetEventRecorderFor("cloudtrail-watcher"))
+	go cloudTrailWatcher.Start(setupLog, stopCh)
+
+	// Rotate access keys older than the configured maximum age
+	credentialRotator := account.NewCredentialRotator(kubeClient, &awsclient.Builder{}, mgr.GetEventRecorderFor("credential-rotator"))
+	go credentialRotator.Start(setupLog, stopCh)
+
+	// Publish short-lived SRE CLI credentials and renew them before they expire
+	sreCLICredentialsRefresher := account.NewSRECLICredentialsRefresher(kubeClient, &awsclient.Builder{})
+	go sreCLICredentialsRefresher.Start(setupLog, stopCh)
+
+	// Publish SRE console URLs and regenerate them on expiry or on request
+	sreConsoleURLRefresher := account.NewSREConsoleURLRefresher(kubeClient, &awsclient.Builder{})
+	go sreConsoleURLRefresher.Start(setupLog, stopCh)
+
+	// Remove the billable resources of accounts idle in the pool for longer than their pool's hibernateAfter
+	accountHibernator := account.NewAccountHibernator(kubeClient, &awsclient.Builder{}, mgr.GetEventRecorderFor("account-hibernator"))
+	go accountHibernator.Start(setupLog, stopCh)
+
+	// The routines above only act on the accounts of this shard, the ones below on the whole fleet
+	if utils.IsPrimaryShard() {
+		// Initialize the TotalAccountWatcher
+		go totalaccountwatcher.TotalAccountWatcher.Start(setupLog, stopCh, kubeClient, totalWatcherInterval)
+
+		// Delete operator secrets whose Account or AccountClaim no longer exists
+		secretCollector := accountclaim.NewSecretCollector(kubeClient)
+		go secretCollector.Start(setupLog, stopCh)
+
+		// Flag Account CRs whose AWS account is gone, and report or adopt AWS accounts without an Account CR
+		orphanedAccountWatcher := account.NewOrphanedAccountWatcher(kubeClient, &awsclient.Builder{}, mgr.GetEventRecorderFor("orphaned-account-watcher"))
+		go orphanedAccountWatcher.Start(setupLog, stopCh)
+
+		// Report the cost of the unclaimed accounts and flag the ones incurring costs
+		idleCostWatcher := account.NewIdleCostWatcher(kubeClient, &awsclient.Builder{}, mgr.GetEventRecorderFor("idle-cost-watcher"))
+		go idleCostWatcher.Start(setupLog, stopCh)
+
+		// Flag accounts AWS Health reports as suspended, under abuse review or affected by an operational issue
+		accountHealthWatcher := account.NewAccountHealthWatcher(kubeClient, &awsclient.Builder{}, mgr.GetEventRecorderFor("account-health-watcher"))
+		go accountHealthWatcher.Start(setupLog, stopCh)
+
+		// Flag accounts from the AWS notifications routed to the configured SQS queue
+		awsNotificationListener := account.NewAWSNotificationListener(kubeClient, &awsclient.Builder{}, mgr.GetEventRecorderFor("aws-notification-listener"))
+		go awsNotificationListener.Start(setupLog, stopCh)
+
+		// Delete retired CCS Account CRs once their retention expires
+		retiredAccountCollector := account.NewRetiredAccountCollector(kubeClient)
+		go retiredAccountCollector.Start(setupLog, stopCh)
+
+		// Summarize the fleet into the AccountOperatorStatus CR
+		fleetStatusUpdater := fleetstatus.NewUpdater(kubeClient)
+		go fleetStatusUpdater.Start(setupLog, stopCh)
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(stopCh); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	tracing.Shutdown(shutdownCtx)
 }
 
 func initOperatorConfigMapVars(kubeClient client.Client) {
@@ -314,3 +531,85 @@ func initOperatorConfigMapVars(kubeClient client.Client) {
 		return
 	}
 }
+
+// initCloudEvents starts publishing the account lifecycle CloudEvents if a sink URL is configured in the
+// operator ConfigMap
+func initCloudEvents(kubeClient client.Client, stopCh context.Context) {
+	cm, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		setupLog.Error(err, "There was an error getting the default configmap.")
+		return
+	}
+	sinkURL := cm.Data[cloudEventsSinkURLKey]
+	if sinkURL == "" {
+		return
+	}
+	cloudevents.Sink = cloudevents.NewPublisher(sinkURL, cm.Data[cloudEventsSourceKey])
+	go cloudevents.Sink.Start(setupLog, stopCh)
+}
+
+// initNotifier starts sending the notifications of critical account events if a webhook, a Slack incoming
+// webhook or a PagerDuty integration is configured in the operator ConfigMap
+func initNotifier(kubeClient client.Client, stopCh context.Context) {
+	cm, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		setupLog.Error(err, "There was an error getting the default configmap.")
+		return
+	}
+	var sinks []notifier.Sink
+	if url := cm.Data[notificationsWebhookURLKey]; url != "" {
+		sinks = append(sinks, notifier.NewWebhookSink(url))
+	}
+	if url := cm.Data[notificationsSlackURLKey]; url != "" {
+		sinks = append(sinks, notifier.NewSlackSink(url, cm.Data[notificationsSlackChannelKey]))
+	}
+	if routingKey := cm.Data[notificationsPagerDutyKey]; routingKey != "" {
+		sinks = append(sinks, notifier.NewPagerDutySink(routingKey))
+	}
+	if len(sinks) == 0 {
+		return
+	}
+	kinds, err := notifier.ParseKinds(cm.Data[notificationsKindsKey])
+	if err != nil {
+		setupLog.Error(err, "Invalid notification kinds in the configmap, sending all notifications")
+		kinds = nil
+	}
+	notifier.Default = notifier.New(sinks, kinds)
+	go notifier.Default.Start(setupLog, stopCh)
+}
+
+// initAuditCloudWatch ships the audit trail to CloudWatch Logs in the payer account if a log group
+// is configured in the operator ConfigMap
+func initAuditCloudWatch(kubeClient client.Client) {
+	cm, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		setupLog.Error(err, "There was an error getting the default configmap.")
+		return
+	}
+	logGroup := cm.Data[auditLogGroupKey]
+	if logGroup == "" {
+		return
+	}
+	logStream := cm.Data[auditLogStreamKey]
+	if logStream == "" {
+		logStream = defaultAuditLogStream
+	}
+
+	builder := &awsclient.Builder{}
+	awsClient, err := builder.GetClient("", kubeClient, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  aaoconfig.GetDefaultRegion(),
+	})
+	if err != nil {
+		setupLog.Error(err, "Failed creating AWS client")
+		return
+	}
+
+	err = audit.Trail.ShipToCloudWatch(awsClient, logGroup, logStream)
+	if err != nil {
+		setupLog.Error(err, "Failed to set up shipping the audit trail to CloudWatch Logs", "logGroup", logGroup)
+		return
+	}
+	setupLog.Info("Shipping the audit trail to CloudWatch Logs", "logGroup", logGroup, "logStream", logStream)
+}
@@ -0,0 +1,378 @@
+// Package audit records the destructive AWS operations performed by the operator, e.g. deleting
+// resources while cleaning up an account for reuse or rotating IAM credentials, so they can be
+// reviewed for compliance. Entries are kept in a ConfigMap in the operator namespace and can
+// also be shipped to a CloudWatch Logs group in the payer account.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ConfigMapName is the ConfigMap holding the most recent audit entries
+	ConfigMapName = "aws-account-operator-audit-log"
+	// ConfigMapKey holds the entries as JSON lines, oldest first
+	ConfigMapKey = "audit.log"
+
+	// ResultSucceeded and ResultFailed are the results of an audited operation. ResultPlanned is
+	// the result of the operations not made in observe-only mode.
+	ResultSucceeded = "Succeeded"
+	ResultFailed    = "Failed"
+	ResultPlanned   = "Planned"
+
+	// maxConfigMapBytes keeps the entries in the ConfigMap well below the 1MiB object size limit
+	maxConfigMapBytes = 512 * 1024
+	// maxConfigMapEntryBytes is the size of the largest entry kept in the ConfigMap, larger entries are only shipped
+	// to CloudWatch
+	maxConfigMapEntryBytes = 16 * 1024
+	// maxPendingEntries bounds memory use if the entries can't be written for a while
+	maxPendingEntries = 10000
+	flushInterval     = 30 * time.Second
+
+	// The limits of a PutLogEvents batch: its number of events, its size, counting each event's message and 26 bytes
+	// of overhead, and the time it spans
+	maxLogEventsPerBatch   = 10000
+	maxLogEventsBatchBytes = 1048576
+	logEventOverheadBytes  = 26
+	maxLogEventsBatchSpan  = 24 * time.Hour
+)
+
+// destructivePrefixes are the AWS API operation prefixes that delete or revoke something
+var destructivePrefixes = []string{"Delete", "Detach", "Remove", "Terminate", "Revoke", "Deregister", "Disable"}
+
+// unauditedOperations match a destructive prefix without deleting anything of an account. DeleteMessage
+// acknowledges an AWS notification consumed from SQS.
+var unauditedOperations = map[string]bool{
+	"DeleteMessage": true,
+}
+
+// credentialOperations are the AWS API operations that rotate credentials
+var credentialOperations = map[string]bool{
+	"CreateAccessKey":    true,
+	"UpdateAccessKey":    true,
+	"CreateLoginProfile": true,
+	"UpdateLoginProfile": true,
+}
+
+// Entry is a single audited operation
+type Entry struct {
+	Time         time.Time `json:"time"`
+	Controller   string    `json:"controller,omitempty"`
+	Operation    string    `json:"operation"`
+	Region       string    `json:"region,omitempty"`
+	Target       string    `json:"target,omitempty"`
+	Account      string    `json:"account,omitempty"`
+	AccountClaim string    `json:"accountClaim,omitempty"`
+	AWSAccountID string    `json:"awsAccountID,omitempty"`
+	RequestID    string    `json:"requestID,omitempty"`
+	Result       string    `json:"result"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// Fields identify what the operator was working on when it performed an operation
+type Fields struct {
+	Account      string
+	AccountClaim string
+	AWSAccountID string
+}
+
+type fieldsContextKey struct{}
+
+// WithFields returns a context carrying the given fields, merged with any fields already in ctx.
+// Clients built with that context add them to every entry they record.
+func WithFields(ctx context.Context, fields Fields) context.Context {
+	existing := FieldsFromContext(ctx)
+	if fields.Account == "" {
+		fields.Account = existing.Account
+	}
+	if fields.AccountClaim == "" {
+		fields.AccountClaim = existing.AccountClaim
+	}
+	if fields.AWSAccountID == "" {
+		fields.AWSAccountID = existing.AWSAccountID
+	}
+	return context.WithValue(ctx, fieldsContextKey{}, fields)
+}
+
+// FieldsFromContext returns the fields held by ctx
+func FieldsFromContext(ctx context.Context) Fields {
+	if ctx == nil {
+		return Fields{}
+	}
+	fields, _ := ctx.Value(fieldsContextKey{}).(Fields)
+	return fields
+}
+
+// IsAudited returns true for AWS API operations that delete something or rotate credentials
+func IsAudited(operation string) bool {
+	if credentialOperations[operation] {
+		return true
+	}
+	if unauditedOperations[operation] {
+		return false
+	}
+	for _, prefix := range destructivePrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// LogEventsPutter is the part of awsclient.Client used to ship entries to CloudWatch Logs
+type LogEventsPutter interface {
+	CreateLogStream(*cloudwatchlogs.CreateLogStreamInput) (*cloudwatchlogs.CreateLogStreamOutput, error)
+	PutLogEvents(*cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error)
+}
+
+// Trail is the global audit trail. It is nil, and recording is a no-op, until it is initialized.
+var Trail *AuditTrail
+
+// AuditTrail buffers entries and periodically writes them out
+type AuditTrail struct {
+	client    client.Client
+	namespace string
+
+	cloudWatch LogEventsPutter
+	logGroup   string
+	logStream  string
+
+	mutex   sync.Mutex
+	pending []Entry
+}
+
+// NewAuditTrail returns an AuditTrail keeping its entries in a ConfigMap in the given namespace
+func NewAuditTrail(kubeClient client.Client, namespace string) *AuditTrail {
+	return &AuditTrail{
+		client:    kubeClient,
+		namespace: namespace,
+	}
+}
+
+// ShipToCloudWatch also sends every entry to the given CloudWatch Logs group and stream. The log
+// group must already exist, the stream is created if needed.
+func (t *AuditTrail) ShipToCloudWatch(cloudWatch LogEventsPutter, logGroup, logStream string) error {
+	_, err := cloudWatch.CreateLogStream(&cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(logGroup),
+		LogStreamName: aws.String(logStream),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != cloudwatchlogs.ErrCodeResourceAlreadyExistsException {
+			return err
+		}
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.cloudWatch = cloudWatch
+	t.logGroup = logGroup
+	t.logStream = logStream
+	return nil
+}
+
+// Record queues an entry, filling in the fields held by ctx. It does nothing if the audit trail
+// has not been initialized.
+func Record(ctx context.Context, entry Entry) {
+	if Trail == nil {
+		return
+	}
+	fields := FieldsFromContext(ctx)
+	if entry.Account == "" {
+		entry.Account = fields.Account
+	}
+	if entry.AccountClaim == "" {
+		entry.AccountClaim = fields.AccountClaim
+	}
+	if entry.AWSAccountID == "" {
+		entry.AWSAccountID = fields.AWSAccountID
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	Trail.record(entry)
+}
+
+func (t *AuditTrail) record(entry Entry) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.pending = append(t.pending, entry)
+	if len(t.pending) > maxPendingEntries {
+		t.pending = t.pending[len(t.pending)-maxPendingEntries:]
+	}
+}
+
+// Start writes out the recorded entries every flushInterval until stopCh is done
+func (t *AuditTrail) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the audit trail")
+	for {
+		select {
+		case <-time.After(flushInterval):
+			err := t.Flush()
+			if err != nil {
+				log.Error(err, "Failed to write audit entries")
+			}
+		case <-stopCh.Done():
+			log.Info("Stopping the audit trail")
+			err := t.Flush()
+			if err != nil {
+				log.Error(err, "Failed to write audit entries")
+			}
+			return
+		}
+	}
+}
+
+// Flush writes out the recorded entries. Entries that could not be written to the ConfigMap are
+// kept and retried on the next flush, unless the API server refused the write, shipping them to
+// CloudWatch is best effort.
+func (t *AuditTrail) Flush() error {
+	t.mutex.Lock()
+	entries := t.pending
+	t.pending = nil
+	t.mutex.Unlock()
+	if len(entries) == 0 {
+		return nil
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+
+	err := t.writeConfigMap(entries)
+	if err != nil {
+		// A write refused by the API server would be refused on every flush, the entries are dropped then
+		if k8serr.IsRequestEntityTooLargeError(err) || k8serr.IsInvalid(err) {
+			return errors.Join(err, t.putLogEvents(entries))
+		}
+		t.mutex.Lock()
+		t.pending = append(entries, t.pending...)
+		t.mutex.Unlock()
+		return err
+	}
+	return t.putLogEvents(entries)
+}
+
+func (t *AuditTrail) writeConfigMap(entries []Entry) error {
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, string(line))
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm := &corev1.ConfigMap{}
+		err := t.client.Get(context.TODO(), types.NamespacedName{Namespace: t.namespace, Name: ConfigMapName}, cm)
+		if k8serr.IsNotFound(err) {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: t.namespace, Name: ConfigMapName},
+				Data:       map[string]string{ConfigMapKey: strings.Join(lastLines(lines), "\n")},
+			}
+			return t.client.Create(context.TODO(), cm)
+		}
+		if err != nil {
+			return err
+		}
+
+		var existing []string
+		if data := cm.Data[ConfigMapKey]; data != "" {
+			existing = strings.Split(data, "\n")
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[ConfigMapKey] = strings.Join(lastLines(append(existing, lines...)), "\n")
+		return t.client.Update(context.TODO(), cm)
+	})
+}
+
+// lastLines returns the most recent lines that fit in maxConfigMapBytes, leaving out the lines larger than
+// maxConfigMapEntryBytes
+func lastLines(lines []string) []string {
+	first, size := len(lines), 0
+	for i := len(lines) - 1; i >= 0; i-- {
+		if len(lines[i]) > maxConfigMapEntryBytes {
+			continue
+		}
+		// Each line but the last is followed by a newline
+		if size+len(lines[i])+1 > maxConfigMapBytes {
+			break
+		}
+		size += len(lines[i]) + 1
+		first = i
+	}
+
+	kept := make([]string, 0, len(lines)-first)
+	for _, line := range lines[first:] {
+		if len(line) <= maxConfigMapEntryBytes {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+func (t *AuditTrail) putLogEvents(entries []Entry) error {
+	t.mutex.Lock()
+	cloudWatch, logGroup, logStream := t.cloudWatch, t.logGroup, t.logStream
+	t.mutex.Unlock()
+	if cloudWatch == nil {
+		return nil
+	}
+
+	events := make([]*cloudwatchlogs.InputLogEvent, 0, len(entries))
+	for _, entry := range entries {
+		message, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		events = append(events, &cloudwatchlogs.InputLogEvent{
+			Message:   aws.String(string(message)),
+			Timestamp: aws.Int64(entry.Time.UnixMilli()),
+		})
+	}
+	for _, batch := range logEventBatches(events) {
+		_, err := cloudWatch.PutLogEvents(&cloudwatchlogs.PutLogEventsInput{
+			LogGroupName:  aws.String(logGroup),
+			LogStreamName: aws.String(logStream),
+			LogEvents:     batch,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logEventBatches splits chronologically ordered events into the fewest batches PutLogEvents accepts
+func logEventBatches(events []*cloudwatchlogs.InputLogEvent) [][]*cloudwatchlogs.InputLogEvent {
+	var batches [][]*cloudwatchlogs.InputLogEvent
+	start, size := 0, 0
+	for i, event := range events {
+		eventSize := len(aws.StringValue(event.Message)) + logEventOverheadBytes
+		span := time.Duration(aws.Int64Value(event.Timestamp)-aws.Int64Value(events[start].Timestamp)) * time.Millisecond
+		if i > start && (i-start == maxLogEventsPerBatch || size+eventSize > maxLogEventsBatchBytes || span >= maxLogEventsBatchSpan) {
+			batches = append(batches, events[start:i])
+			start, size = i, 0
+		}
+		size += eventSize
+	}
+	if start < len(events) {
+		batches = append(batches, events[start:])
+	}
+	return batches
+}
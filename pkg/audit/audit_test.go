@@ -0,0 +1,162 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIsAudited(t *testing.T) {
+	tests := map[string]bool{
+		"DeleteBucket":       true,
+		"DeleteObjects":      true,
+		"DetachRolePolicy":   true,
+		"TerminateInstances": true,
+		"CreateAccessKey":    true,
+		"ListBuckets":        false,
+		"CreateUser":         false,
+		"DescribeSnapshots":  false,
+		"DeleteMessage":      false,
+	}
+	for operation, expected := range tests {
+		assert.Equal(t, expected, IsAudited(operation), operation)
+	}
+}
+
+func TestWithFields(t *testing.T) {
+	ctx := WithFields(context.Background(), Fields{AccountClaim: "ns/claim", Account: "osd-creds-mgmt-abcdef"})
+	ctx = WithFields(ctx, Fields{AWSAccountID: "123456789012"})
+	assert.Equal(t, Fields{AccountClaim: "ns/claim", Account: "osd-creds-mgmt-abcdef", AWSAccountID: "123456789012"}, FieldsFromContext(ctx))
+}
+
+func TestFlush(t *testing.T) {
+	kubeClient := fakekubeclient.NewClientBuilder().Build()
+	Trail = NewAuditTrail(kubeClient, "aws-account-operator")
+	defer func() { Trail = nil }()
+
+	ctx := WithFields(context.Background(), Fields{Account: "osd-creds-mgmt-abcdef", AWSAccountID: "123456789012"})
+	Record(ctx, Entry{Operation: "s3:DeleteBucket", Target: "Bucket=foo", Result: ResultSucceeded})
+	Record(ctx, Entry{Operation: "ec2:DeleteVolume", Target: "VolumeId=vol-1", Result: ResultFailed, Error: "VolumeInUse"})
+	assert.NoError(t, Trail.Flush())
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, kubeClient.Get(context.TODO(), types.NamespacedName{Namespace: "aws-account-operator", Name: ConfigMapName}, cm))
+	lines := strings.Split(cm.Data[ConfigMapKey], "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"operation":"s3:DeleteBucket"`)
+	assert.Contains(t, lines[0], `"awsAccountID":"123456789012"`)
+	assert.Contains(t, lines[1], `"error":"VolumeInUse"`)
+
+	// The ConfigMap only keeps the most recent entries that fit in it
+	target := strings.Repeat("x", 1000)
+	for i := 0; i < 2*maxConfigMapBytes/len(target); i++ {
+		Record(ctx, Entry{Operation: "s3:DeleteBucket", Target: fmt.Sprintf("Bucket=%d%s", i, target), Result: ResultSucceeded})
+	}
+	assert.NoError(t, Trail.Flush())
+	assert.NoError(t, kubeClient.Get(context.TODO(), types.NamespacedName{Namespace: "aws-account-operator", Name: ConfigMapName}, cm))
+	assert.LessOrEqual(t, len(cm.Data[ConfigMapKey]), maxConfigMapBytes)
+	lines = strings.Split(cm.Data[ConfigMapKey], "\n")
+	assert.Contains(t, lines[len(lines)-1], fmt.Sprintf(`"target":"Bucket=%d%s"`, 2*maxConfigMapBytes/len(target)-1, target))
+
+	// Entries too large for the ConfigMap are left out
+	Record(ctx, Entry{Operation: "s3:DeleteBucket", Target: strings.Repeat("x", maxConfigMapEntryBytes), Result: ResultSucceeded})
+	assert.NoError(t, Trail.Flush())
+	assert.NoError(t, kubeClient.Get(context.TODO(), types.NamespacedName{Namespace: "aws-account-operator", Name: ConfigMapName}, cm))
+	assert.Equal(t, lines, strings.Split(cm.Data[ConfigMapKey], "\n"))
+}
+
+// tooLargeClient refuses to create objects, as the API server does with objects over its size limit
+type tooLargeClient struct {
+	client.Client
+}
+
+func (c tooLargeClient) Create(context.Context, client.Object, ...client.CreateOption) error {
+	return k8serr.NewRequestEntityTooLargeError("limit is 3145728")
+}
+
+func TestFlushRefused(t *testing.T) {
+	cloudWatch := &fakeCloudWatch{}
+	trail := NewAuditTrail(tooLargeClient{fakekubeclient.NewClientBuilder().Build()}, "aws-account-operator")
+	assert.NoError(t, trail.ShipToCloudWatch(cloudWatch, "audit", "aws-account-operator"))
+
+	trail.record(Entry{Time: time.Now(), Operation: "iam:DeleteUser", Result: ResultSucceeded})
+	assert.Error(t, trail.Flush())
+
+	// The write would be refused again, the entries are dropped rather than retried
+	assert.Empty(t, trail.pending)
+	assert.Len(t, cloudWatch.events, 1)
+}
+
+type fakeCloudWatch struct {
+	events []*cloudwatchlogs.InputLogEvent
+	err    error
+}
+
+func (f *fakeCloudWatch) CreateLogStream(*cloudwatchlogs.CreateLogStreamInput) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	return &cloudwatchlogs.CreateLogStreamOutput{}, nil
+}
+
+func (f *fakeCloudWatch) PutLogEvents(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	f.events = append(f.events, input.LogEvents...)
+	return &cloudwatchlogs.PutLogEventsOutput{}, f.err
+}
+
+func TestFlushToCloudWatch(t *testing.T) {
+	cloudWatch := &fakeCloudWatch{err: errors.New("throttled")}
+	trail := NewAuditTrail(fakekubeclient.NewClientBuilder().Build(), "aws-account-operator")
+	assert.NoError(t, trail.ShipToCloudWatch(cloudWatch, "audit", "aws-account-operator"))
+
+	now := time.Now()
+	trail.record(Entry{Time: now, Operation: "iam:DeleteUser", Result: ResultSucceeded})
+	trail.record(Entry{Time: now.Add(-time.Second), Operation: "iam:DeleteAccessKey", Result: ResultSucceeded})
+	assert.Error(t, trail.Flush())
+
+	// CloudWatch requires the events in chronological order
+	assert.Len(t, cloudWatch.events, 2)
+	assert.Contains(t, *cloudWatch.events[0].Message, "iam:DeleteAccessKey")
+	// Entries are in the ConfigMap already, so they aren't retried
+	assert.Empty(t, trail.pending)
+}
+
+func TestLogEventBatches(t *testing.T) {
+	now := time.Now().UnixMilli()
+	event := func(message string, timestamp int64) *cloudwatchlogs.InputLogEvent {
+		return &cloudwatchlogs.InputLogEvent{Message: aws.String(message), Timestamp: aws.Int64(timestamp)}
+	}
+
+	// At most 10,000 events per batch
+	events := make([]*cloudwatchlogs.InputLogEvent, 0, maxLogEventsPerBatch+1)
+	for i := 0; i <= maxLogEventsPerBatch; i++ {
+		events = append(events, event("{}", now))
+	}
+	batches := logEventBatches(events)
+	assert.Len(t, batches, 2)
+	assert.Len(t, batches[0], maxLogEventsPerBatch)
+	assert.Len(t, batches[1], 1)
+
+	// At most 1 MiB per batch, with the overhead of each event
+	large := strings.Repeat("x", maxLogEventsBatchBytes/2-logEventOverheadBytes)
+	batches = logEventBatches([]*cloudwatchlogs.InputLogEvent{event(large, now), event(large, now), event("{}", now)})
+	assert.Len(t, batches, 2)
+	assert.Len(t, batches[0], 2)
+
+	// Spanning less than 24 hours
+	dayLater := now + (24 * time.Hour).Milliseconds()
+	batches = logEventBatches([]*cloudwatchlogs.InputLogEvent{event("{}", now), event("{}", now+1), event("{}", dayLater)})
+	assert.Len(t, batches, 2)
+	assert.Len(t, batches[1], 1)
+
+	assert.Empty(t, logEventBatches(nil))
+}
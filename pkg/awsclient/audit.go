@@ -0,0 +1,120 @@
+package awsclient
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/openshift/aws-account-operator/pkg/audit"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// auditAPICall returns a handler recording destructive AWS calls in the audit trail, along with
+// the fields of the Account or AccountClaim held by ctx
+func auditAPICall(ctx context.Context, controllerName string) func(r *request.Request) {
+	return func(r *request.Request) {
+		target := auditTarget(r.Params)
+		if !audit.IsAudited(r.Operation.Name) {
+			deleted := deletedRecordSets(r.Params)
+			if deleted == "" {
+				return
+			}
+			target += ",RecordSets=" + deleted
+		}
+		entry := audit.Entry{
+			Controller: controllerName,
+			Operation:  r.ClientInfo.ServiceName + ":" + r.Operation.Name,
+			Region:     aws.StringValue(r.Config.Region),
+			Target:     target,
+			RequestID:  r.RequestID,
+			Result:     audit.ResultSucceeded,
+		}
+		if utils.IsObserveOnlyError(r.Error) {
+			entry.Result = audit.ResultPlanned
+		} else if r.Error != nil {
+			entry.Result = audit.ResultFailed
+			entry.Error = r.Error.Error()
+		}
+		audit.Record(ctx, entry)
+	}
+}
+
+// auditTarget describes what an operation acted on using the identifying fields of its input,
+// e.g. "Bucket=foo,UserName=bar". Other fields are left out so secrets such as passwords are
+// never recorded.
+func auditTarget(params interface{}) string {
+	value := reflect.ValueOf(params)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return ""
+	}
+
+	var targets []string
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() || !isIdentifyingField(field.Name) {
+			continue
+		}
+		fieldValue := value.Field(i)
+		switch v := fieldValue.Interface().(type) {
+		case *string:
+			if v != nil {
+				targets = append(targets, fmt.Sprintf("%s=%s", field.Name, *v))
+			}
+		case []*string:
+			if len(v) > 0 {
+				targets = append(targets, fmt.Sprintf("%s=%s", field.Name, strings.Join(aws.StringValueSlice(v), ";")))
+			}
+		}
+	}
+	sort.Strings(targets)
+	return strings.Join(targets, ",")
+}
+
+// maxAuditedRecordSets is the number of deleted record sets named in the target of a change batch, a batch can
+// delete up to 1000
+const maxAuditedRecordSets = 10
+
+// deletedRecordSets describes the record sets a Route53 change batch deletes, e.g. "a.example.com./A;a.example.com./TXT",
+// empty if it deletes none. Past maxAuditedRecordSets, only the number of the other record sets is given, e.g.
+// "a.example.com./A;...;j.example.com./A;+990 more". Change batches also create and update record sets, they're only
+// audited when they delete some.
+func deletedRecordSets(params interface{}) string {
+	input, ok := params.(*route53.ChangeResourceRecordSetsInput)
+	if !ok || input == nil || input.ChangeBatch == nil {
+		return ""
+	}
+	var deleted []string
+	for _, change := range input.ChangeBatch.Changes {
+		if aws.StringValue(change.Action) != route53.ChangeActionDelete || change.ResourceRecordSet == nil {
+			continue
+		}
+		deleted = append(deleted, aws.StringValue(change.ResourceRecordSet.Name)+"/"+aws.StringValue(change.ResourceRecordSet.Type))
+	}
+	if len(deleted) > maxAuditedRecordSets {
+		deleted = append(deleted[:maxAuditedRecordSets], fmt.Sprintf("+%d more", len(deleted)-maxAuditedRecordSets))
+	}
+	return strings.Join(deleted, ";")
+}
+
+func isIdentifyingField(name string) bool {
+	if name == "Bucket" || name == "Key" {
+		return true
+	}
+	for _, suffix := range []string{"Id", "Ids", "Name", "Arn"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
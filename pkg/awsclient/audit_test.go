@@ -0,0 +1,72 @@
+package awsclient
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Audit target", func() {
+	It("Should describe the identifying fields of the input", func() {
+		Expect(auditTarget(&s3.DeleteBucketInput{Bucket: aws.String("foo")})).To(Equal("Bucket=foo"))
+		Expect(auditTarget(&iam.DeleteAccessKeyInput{AccessKeyId: aws.String("AKIA"), UserName: aws.String("osdManagedAdmin")})).To(Equal("AccessKeyId=AKIA,UserName=osdManagedAdmin"))
+		Expect(auditTarget(&ec2.DeleteVpcEndpointServiceConfigurationsInput{ServiceIds: aws.StringSlice([]string{"a", "b"})})).To(Equal("ServiceIds=a;b"))
+	})
+
+	It("Should describe the hosted zone deleted", func() {
+		Expect(auditTarget(&route53.DeleteHostedZoneInput{Id: aws.String("Z1")})).To(Equal("Id=Z1"))
+	})
+
+	It("Should describe the record sets deleted", func() {
+		change := func(action string, name string, recordType string) *route53.Change {
+			return &route53.Change{
+				Action:            aws.String(action),
+				ResourceRecordSet: &route53.ResourceRecordSet{Name: aws.String(name), Type: aws.String(recordType)},
+			}
+		}
+		input := &route53.ChangeResourceRecordSetsInput{
+			HostedZoneId: aws.String("Z1"),
+			ChangeBatch: &route53.ChangeBatch{Changes: []*route53.Change{
+				change(route53.ChangeActionDelete, "a.example.com.", "A"),
+				change(route53.ChangeActionUpsert, "b.example.com.", "A"),
+				change(route53.ChangeActionDelete, "a.example.com.", "TXT"),
+			}},
+		}
+		Expect(auditTarget(input)).To(Equal("HostedZoneId=Z1"))
+		Expect(deletedRecordSets(input)).To(Equal("a.example.com./A;a.example.com./TXT"))
+
+		input.ChangeBatch.Changes = input.ChangeBatch.Changes[1:2]
+		Expect(deletedRecordSets(input)).To(BeEmpty())
+		Expect(deletedRecordSets(&s3.DeleteBucketInput{Bucket: aws.String("foo")})).To(BeEmpty())
+	})
+
+	It("Should only name the first record sets of a large change batch", func() {
+		input := &route53.ChangeResourceRecordSetsInput{HostedZoneId: aws.String("Z1"), ChangeBatch: &route53.ChangeBatch{}}
+		for i := 0; i < 1000; i++ {
+			input.ChangeBatch.Changes = append(input.ChangeBatch.Changes, &route53.Change{
+				Action:            aws.String(route53.ChangeActionDelete),
+				ResourceRecordSet: &route53.ResourceRecordSet{Name: aws.String(fmt.Sprintf("%d.example.com.", i)), Type: aws.String("A")},
+			})
+		}
+		deleted := strings.Split(deletedRecordSets(input), ";")
+		Expect(deleted).To(HaveLen(maxAuditedRecordSets + 1))
+		Expect(deleted[0]).To(Equal("0.example.com./A"))
+		Expect(deleted[maxAuditedRecordSets]).To(Equal("+990 more"))
+	})
+
+	It("Should never include secrets", func() {
+		Expect(auditTarget(&iam.UpdateLoginProfileInput{UserName: aws.String("osdManagedAdmin"), Password: aws.String("hunter2")})).To(Equal("UserName=osdManagedAdmin"))
+	})
+
+	It("Should handle nil input", func() {
+		var input *s3.DeleteBucketInput
+		Expect(auditTarget(input)).To(Equal(""))
+	})
+})
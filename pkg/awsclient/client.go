@@ -16,23 +16,40 @@ package awsclient
 import (
 	"context"
 	"fmt"
+	"github.com/aws/aws-sdk-go/service/accessanalyzer"
+	"github.com/aws/aws-sdk-go/service/accessanalyzer/accessanalyzeriface"
 	"github.com/aws/aws-sdk-go/service/account"
 	"github.com/aws/aws-sdk-go/service/account/accountiface"
 	"net/http"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/service/budgets"
+	"github.com/aws/aws-sdk-go/service/budgets/budgetsiface"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/cloudtrail/cloudtrailiface"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	"github.com/aws/aws-sdk-go/service/costexplorer/costexploreriface"
+	"github.com/aws/aws-sdk-go/service/guardduty"
+	"github.com/aws/aws-sdk-go/service/guardduty/guarddutyiface"
+	"github.com/aws/aws-sdk-go/service/health"
+	"github.com/aws/aws-sdk-go/service/health/healthiface"
 	"github.com/aws/aws-sdk-go/service/route53/route53iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aws/aws-sdk-go/service/servicequotas"
 	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
+	"github.com/aws/aws-sdk-go/service/ssoadmin"
+	"github.com/aws/aws-sdk-go/service/ssoadmin/ssoadminiface"
 	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/utils"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/iam"
@@ -42,10 +59,15 @@ import (
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3control"
+	"github.com/aws/aws-sdk-go/service/s3control/s3controliface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/aws/aws-sdk-go/service/support"
 	"github.com/aws/aws-sdk-go/service/support/supportiface"
+	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	kubeclientpkg "sigs.k8s.io/controller-runtime/pkg/client"
@@ -70,8 +92,14 @@ type Client interface {
 	TerminateInstances(*ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error)
 	DescribeVolumes(*ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error)
 	DeleteVolume(*ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error)
+	DetachVolume(*ec2.DetachVolumeInput) (*ec2.VolumeAttachment, error)
 	DescribeSnapshots(*ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error)
 	DeleteSnapshot(*ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error)
+	DescribeLockedSnapshots(*ec2.DescribeLockedSnapshotsInput) (*ec2.DescribeLockedSnapshotsOutput, error)
+	ResetSnapshotAttribute(*ec2.ResetSnapshotAttributeInput) (*ec2.ResetSnapshotAttributeOutput, error)
+	CreateVolume(*ec2.CreateVolumeInput) (*ec2.Volume, error)
+	CreateSnapshot(*ec2.CreateSnapshotInput) (*ec2.Snapshot, error)
+	DescribeAvailabilityZones(*ec2.DescribeAvailabilityZonesInput) (*ec2.DescribeAvailabilityZonesOutput, error)
 	DescribeImages(*ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error)
 	DescribeInstances(*ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error)
 	DescribeInstanceTypes(*ec2.DescribeInstanceTypesInput) (*ec2.DescribeInstanceTypesOutput, error)
@@ -84,6 +112,21 @@ type Client interface {
 	DescribeSubnets(*ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error)
 	CreateSubnet(*ec2.CreateSubnetInput) (*ec2.CreateSubnetOutput, error)
 	DeleteSubnet(*ec2.DeleteSubnetInput) (*ec2.DeleteSubnetOutput, error)
+	EnableEbsEncryptionByDefault(*ec2.EnableEbsEncryptionByDefaultInput) (*ec2.EnableEbsEncryptionByDefaultOutput, error)
+	DescribeInternetGateways(*ec2.DescribeInternetGatewaysInput) (*ec2.DescribeInternetGatewaysOutput, error)
+	DetachInternetGateway(*ec2.DetachInternetGatewayInput) (*ec2.DetachInternetGatewayOutput, error)
+	DeleteInternetGateway(*ec2.DeleteInternetGatewayInput) (*ec2.DeleteInternetGatewayOutput, error)
+	DescribeAddresses(*ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error)
+	ReleaseAddress(*ec2.ReleaseAddressInput) (*ec2.ReleaseAddressOutput, error)
+	DescribeNatGateways(*ec2.DescribeNatGatewaysInput) (*ec2.DescribeNatGatewaysOutput, error)
+	DeleteNatGateway(*ec2.DeleteNatGatewayInput) (*ec2.DeleteNatGatewayOutput, error)
+	DescribeNetworkInterfaces(*ec2.DescribeNetworkInterfacesInput) (*ec2.DescribeNetworkInterfacesOutput, error)
+	DetachNetworkInterface(*ec2.DetachNetworkInterfaceInput) (*ec2.DetachNetworkInterfaceOutput, error)
+	DeleteNetworkInterface(*ec2.DeleteNetworkInterfaceInput) (*ec2.DeleteNetworkInterfaceOutput, error)
+	DescribeSecurityGroups(*ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error)
+	RevokeSecurityGroupIngress(*ec2.RevokeSecurityGroupIngressInput) (*ec2.RevokeSecurityGroupIngressOutput, error)
+	RevokeSecurityGroupEgress(*ec2.RevokeSecurityGroupEgressInput) (*ec2.RevokeSecurityGroupEgressOutput, error)
+	DeleteSecurityGroup(*ec2.DeleteSecurityGroupInput) (*ec2.DeleteSecurityGroupOutput, error)
 
 	//IAM
 	CreateAccessKey(*iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error)
@@ -95,6 +138,7 @@ type Client interface {
 	ListUsers(*iam.ListUsersInput) (*iam.ListUsersOutput, error)
 	ListUsersPages(*iam.ListUsersInput, func(*iam.ListUsersOutput, bool) bool) error
 	ListUserTags(*iam.ListUserTagsInput) (*iam.ListUserTagsOutput, error)
+	TagUser(*iam.TagUserInput) (*iam.TagUserOutput, error)
 	ListAccessKeys(*iam.ListAccessKeysInput) (*iam.ListAccessKeysOutput, error)
 	ListUserPolicies(*iam.ListUserPoliciesInput) (*iam.ListUserPoliciesOutput, error)
 	PutUserPolicy(*iam.PutUserPolicyInput) (*iam.PutUserPolicyOutput, error)
@@ -118,9 +162,17 @@ type Client interface {
 	DeleteRole(*iam.DeleteRoleInput) (*iam.DeleteRoleOutput, error)
 	ListRoles(input *iam.ListRolesInput) (*iam.ListRolesOutput, error)
 	PutRolePolicy(input *iam.PutRolePolicyInput) (*iam.PutRolePolicyOutput, error)
+	UpdateRole(*iam.UpdateRoleInput) (*iam.UpdateRoleOutput, error)
+	UpdateAssumeRolePolicy(*iam.UpdateAssumeRolePolicyInput) (*iam.UpdateAssumeRolePolicyOutput, error)
+	TagRole(*iam.TagRoleInput) (*iam.TagRoleOutput, error)
+	PutUserPermissionsBoundary(*iam.PutUserPermissionsBoundaryInput) (*iam.PutUserPermissionsBoundaryOutput, error)
+	PutRolePermissionsBoundary(*iam.PutRolePermissionsBoundaryInput) (*iam.PutRolePermissionsBoundaryOutput, error)
+	UpdateAccountPasswordPolicy(*iam.UpdateAccountPasswordPolicyInput) (*iam.UpdateAccountPasswordPolicyOutput, error)
+	GetAccountSummary(*iam.GetAccountSummaryInput) (*iam.GetAccountSummaryOutput, error)
 
 	//Organizations
 	ListAccounts(*organizations.ListAccountsInput) (*organizations.ListAccountsOutput, error)
+	ListAccountsForParent(*organizations.ListAccountsForParentInput) (*organizations.ListAccountsForParentOutput, error)
 	CreateAccount(*organizations.CreateAccountInput) (*organizations.CreateAccountOutput, error)
 	DescribeCreateAccountStatus(*organizations.DescribeCreateAccountStatusInput) (*organizations.DescribeCreateAccountStatusOutput, error)
 	MoveAccount(*organizations.MoveAccountInput) (*organizations.MoveAccountOutput, error)
@@ -131,6 +183,11 @@ type Client interface {
 	UntagResource(input *organizations.UntagResourceInput) (*organizations.UntagResourceOutput, error)
 	ListParents(*organizations.ListParentsInput) (*organizations.ListParentsOutput, error)
 	ListTagsForResource(input *organizations.ListTagsForResourceInput) (*organizations.ListTagsForResourceOutput, error)
+	AttachPolicy(*organizations.AttachPolicyInput) (*organizations.AttachPolicyOutput, error)
+	DetachPolicy(*organizations.DetachPolicyInput) (*organizations.DetachPolicyOutput, error)
+	ListPoliciesForTarget(*organizations.ListPoliciesForTargetInput) (*organizations.ListPoliciesForTargetOutput, error)
+	DescribeAccount(*organizations.DescribeAccountInput) (*organizations.DescribeAccountOutput, error)
+	DescribeOrganization(*organizations.DescribeOrganizationInput) (*organizations.DescribeOrganizationOutput, error)
 
 	//sts
 	AssumeRole(*sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error)
@@ -140,36 +197,103 @@ type Client interface {
 	//Support
 	CreateCase(*support.CreateCaseInput) (*support.CreateCaseOutput, error)
 	DescribeCases(*support.DescribeCasesInput) (*support.DescribeCasesOutput, error)
+	DescribeTrustedAdvisorCheckResult(*support.DescribeTrustedAdvisorCheckResultInput) (*support.DescribeTrustedAdvisorCheckResultOutput, error)
+	RefreshTrustedAdvisorCheck(*support.RefreshTrustedAdvisorCheckInput) (*support.RefreshTrustedAdvisorCheckOutput, error)
 
 	// S3
 	ListBuckets(*s3.ListBucketsInput) (*s3.ListBucketsOutput, error)
 	DeleteBucket(*s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error)
+	GetBucketPolicy(*s3.GetBucketPolicyInput) (*s3.GetBucketPolicyOutput, error)
+	DeleteBucketPolicy(*s3.DeleteBucketPolicyInput) (*s3.DeleteBucketPolicyOutput, error)
+	GetObjectLockConfiguration(*s3.GetObjectLockConfigurationInput) (*s3.GetObjectLockConfigurationOutput, error)
 	BatchDeleteBucketObjects(bucketName *string) error
 	ListObjectsV2(*s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error)
+	ListObjectVersions(*s3.ListObjectVersionsInput) (*s3.ListObjectVersionsOutput, error)
+	BatchDeleteBucketObjectVersions(bucketName *string) error
+	CreateBucket(*s3.CreateBucketInput) (*s3.CreateBucketOutput, error)
+	HeadBucket(*s3.HeadBucketInput) (*s3.HeadBucketOutput, error)
+	PutBucketVersioning(*s3.PutBucketVersioningInput) (*s3.PutBucketVersioningOutput, error)
+	PutObject(*s3.PutObjectInput) (*s3.PutObjectOutput, error)
+	PutPublicAccessBlock(*s3control.PutPublicAccessBlockInput) (*s3control.PutPublicAccessBlockOutput, error)
 
 	// Route53
 	ListHostedZones(*route53.ListHostedZonesInput) (*route53.ListHostedZonesOutput, error)
 	DeleteHostedZone(*route53.DeleteHostedZoneInput) (*route53.DeleteHostedZoneOutput, error)
 	ListResourceRecordSets(*route53.ListResourceRecordSetsInput) (*route53.ListResourceRecordSetsOutput, error)
 	ChangeResourceRecordSets(*route53.ChangeResourceRecordSetsInput) (*route53.ChangeResourceRecordSetsOutput, error)
+	CreateHostedZone(*route53.CreateHostedZoneInput) (*route53.CreateHostedZoneOutput, error)
+	GetHostedZone(*route53.GetHostedZoneInput) (*route53.GetHostedZoneOutput, error)
 
 	// Service Quota
 	GetServiceQuota(*servicequotas.GetServiceQuotaInput) (*servicequotas.GetServiceQuotaOutput, error)
 	RequestServiceQuotaIncrease(*servicequotas.RequestServiceQuotaIncreaseInput) (*servicequotas.RequestServiceQuotaIncreaseOutput, error)
 	ListRequestedServiceQuotaChangeHistory(*servicequotas.ListRequestedServiceQuotaChangeHistoryInput) (*servicequotas.ListRequestedServiceQuotaChangeHistoryOutput, error)
 	ListRequestedServiceQuotaChangeHistoryByQuota(*servicequotas.ListRequestedServiceQuotaChangeHistoryByQuotaInput) (*servicequotas.ListRequestedServiceQuotaChangeHistoryByQuotaOutput, error)
+
+	// Health
+	DescribeEventsForOrganization(*health.DescribeEventsForOrganizationInput) (*health.DescribeEventsForOrganizationOutput, error)
+	DescribeAffectedAccountsForOrganization(*health.DescribeAffectedAccountsForOrganizationInput) (*health.DescribeAffectedAccountsForOrganizationOutput, error)
+
+	// CloudWatch Logs
+	CreateLogStream(*cloudwatchlogs.CreateLogStreamInput) (*cloudwatchlogs.CreateLogStreamOutput, error)
+	PutLogEvents(*cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error)
+
+	// SQS
+	ReceiveMessage(*sqs.ReceiveMessageInput) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(*sqs.DeleteMessageInput) (*sqs.DeleteMessageOutput, error)
+
+	// Access Analyzer
+	ValidatePolicy(*accessanalyzer.ValidatePolicyInput) (*accessanalyzer.ValidatePolicyOutput, error)
+
+	// IAM Identity Center
+	CreateAccountAssignment(*ssoadmin.CreateAccountAssignmentInput) (*ssoadmin.CreateAccountAssignmentOutput, error)
+	DeleteAccountAssignment(*ssoadmin.DeleteAccountAssignmentInput) (*ssoadmin.DeleteAccountAssignmentOutput, error)
+	DescribeAccountAssignmentCreationStatus(*ssoadmin.DescribeAccountAssignmentCreationStatusInput) (*ssoadmin.DescribeAccountAssignmentCreationStatusOutput, error)
+	ListAccountAssignments(*ssoadmin.ListAccountAssignmentsInput) (*ssoadmin.ListAccountAssignmentsOutput, error)
+
+	// Budgets
+	CreateBudget(*budgets.CreateBudgetInput) (*budgets.CreateBudgetOutput, error)
+	DescribeBudget(*budgets.DescribeBudgetInput) (*budgets.DescribeBudgetOutput, error)
+	DeleteBudget(*budgets.DeleteBudgetInput) (*budgets.DeleteBudgetOutput, error)
+
+	// Cost Explorer
+	GetCostAndUsage(*costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error)
+	ListCostAllocationTags(*costexplorer.ListCostAllocationTagsInput) (*costexplorer.ListCostAllocationTagsOutput, error)
+	UpdateCostAllocationTagsStatus(*costexplorer.UpdateCostAllocationTagsStatusInput) (*costexplorer.UpdateCostAllocationTagsStatusOutput, error)
+
+	// CloudTrail
+	DescribeTrails(*cloudtrail.DescribeTrailsInput) (*cloudtrail.DescribeTrailsOutput, error)
+	GetTrailStatus(*cloudtrail.GetTrailStatusInput) (*cloudtrail.GetTrailStatusOutput, error)
+	StartLogging(*cloudtrail.StartLoggingInput) (*cloudtrail.StartLoggingOutput, error)
+
+	// GuardDuty
+	ListDetectors(*guardduty.ListDetectorsInput) (*guardduty.ListDetectorsOutput, error)
+	CreateMembers(*guardduty.CreateMembersInput) (*guardduty.CreateMembersOutput, error)
+	StopMonitoringMembers(*guardduty.StopMonitoringMembersInput) (*guardduty.StopMonitoringMembersOutput, error)
+	DisassociateMembers(*guardduty.DisassociateMembersInput) (*guardduty.DisassociateMembersOutput, error)
+	DeleteMembers(*guardduty.DeleteMembersInput) (*guardduty.DeleteMembersOutput, error)
 }
 
 type awsClient struct {
-	acctClient          accountiface.AccountAPI
-	ec2Client           ec2iface.EC2API
-	iamClient           iamiface.IAMAPI
-	orgClient           organizationsiface.OrganizationsAPI
-	stsClient           stsiface.STSAPI
-	supportClient       supportiface.SupportAPI
-	s3Client            s3iface.S3API
-	route53client       route53iface.Route53API
-	serviceQuotasClient servicequotasiface.ServiceQuotasAPI
+	acctClient           accountiface.AccountAPI
+	ec2Client            ec2iface.EC2API
+	iamClient            iamiface.IAMAPI
+	orgClient            organizationsiface.OrganizationsAPI
+	stsClient            stsiface.STSAPI
+	supportClient        supportiface.SupportAPI
+	s3Client             s3iface.S3API
+	s3ControlClient      s3controliface.S3ControlAPI
+	route53client        route53iface.Route53API
+	serviceQuotasClient  servicequotasiface.ServiceQuotasAPI
+	healthClient         healthiface.HealthAPI
+	cloudWatchLogsClient cloudwatchlogsiface.CloudWatchLogsAPI
+	sqsClient            sqsiface.SQSAPI
+	accessAnalyzerClient accessanalyzeriface.AccessAnalyzerAPI
+	ssoAdminClient       ssoadminiface.SSOAdminAPI
+	budgetsClient        budgetsiface.BudgetsAPI
+	costExplorerClient   costexploreriface.CostExplorerAPI
+	cloudTrailClient     cloudtrailiface.CloudTrailAPI
+	guardDutyClient      guarddutyiface.GuardDutyAPI
 }
 
 // NewAwsClientInput input for new aws client
@@ -180,6 +304,12 @@ type NewAwsClientInput struct {
 	AwsRegion               string
 	SecretName              string
 	NameSpace               string
+	// Context holds the span of the reconcile the client is built for, if any, so each AWS call
+	// is traced as a child of it
+	Context context.Context
+	// AwsAccountID is the member account the credentials give access to, if any. The calls made
+	// to it by all the clients of the operator share a rate limit.
+	AwsAccountID string
 }
 
 func (c *awsClient) EnableRegion(input *account.EnableRegionInput) (*account.EnableRegionOutput, error) {
@@ -194,6 +324,10 @@ func (c *awsClient) RunInstances(input *ec2.RunInstancesInput) (*ec2.Reservation
 	return c.ec2Client.RunInstances(input)
 }
 
+func (c *awsClient) DescribeAvailabilityZones(input *ec2.DescribeAvailabilityZonesInput) (*ec2.DescribeAvailabilityZonesOutput, error) {
+	return c.ec2Client.DescribeAvailabilityZones(input)
+}
+
 func (c *awsClient) DescribeImages(input *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error) {
 	return c.ec2Client.DescribeImages(input)
 }
@@ -214,6 +348,10 @@ func (c *awsClient) DeleteVolume(input *ec2.DeleteVolumeInput) (*ec2.DeleteVolum
 	return c.ec2Client.DeleteVolume(input)
 }
 
+func (c *awsClient) DetachVolume(input *ec2.DetachVolumeInput) (*ec2.VolumeAttachment, error) {
+	return c.ec2Client.DetachVolume(input)
+}
+
 func (c *awsClient) DescribeVpcEndpointServiceConfigurations(input *ec2.DescribeVpcEndpointServiceConfigurationsInput) (*ec2.DescribeVpcEndpointServiceConfigurationsOutput, error) {
 	return c.ec2Client.DescribeVpcEndpointServiceConfigurations(input)
 }
@@ -226,10 +364,26 @@ func (c *awsClient) DescribeSnapshots(input *ec2.DescribeSnapshotsInput) (*ec2.D
 	return c.ec2Client.DescribeSnapshots(input)
 }
 
+func (c *awsClient) CreateVolume(input *ec2.CreateVolumeInput) (*ec2.Volume, error) {
+	return c.ec2Client.CreateVolume(input)
+}
+
+func (c *awsClient) CreateSnapshot(input *ec2.CreateSnapshotInput) (*ec2.Snapshot, error) {
+	return c.ec2Client.CreateSnapshot(input)
+}
+
 func (c *awsClient) DeleteSnapshot(input *ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error) {
 	return c.ec2Client.DeleteSnapshot(input)
 }
 
+func (c *awsClient) DescribeLockedSnapshots(input *ec2.DescribeLockedSnapshotsInput) (*ec2.DescribeLockedSnapshotsOutput, error) {
+	return c.ec2Client.DescribeLockedSnapshots(input)
+}
+
+func (c *awsClient) ResetSnapshotAttribute(input *ec2.ResetSnapshotAttributeInput) (*ec2.ResetSnapshotAttributeOutput, error) {
+	return c.ec2Client.ResetSnapshotAttribute(input)
+}
+
 func (c *awsClient) DescribeInstances(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
 	return c.ec2Client.DescribeInstances(input)
 }
@@ -266,6 +420,66 @@ func (c *awsClient) DeleteSubnet(input *ec2.DeleteSubnetInput) (*ec2.DeleteSubne
 	return c.ec2Client.DeleteSubnet(input)
 }
 
+func (c *awsClient) EnableEbsEncryptionByDefault(input *ec2.EnableEbsEncryptionByDefaultInput) (*ec2.EnableEbsEncryptionByDefaultOutput, error) {
+	return c.ec2Client.EnableEbsEncryptionByDefault(input)
+}
+
+func (c *awsClient) DescribeInternetGateways(input *ec2.DescribeInternetGatewaysInput) (*ec2.DescribeInternetGatewaysOutput, error) {
+	return c.ec2Client.DescribeInternetGateways(input)
+}
+
+func (c *awsClient) DetachInternetGateway(input *ec2.DetachInternetGatewayInput) (*ec2.DetachInternetGatewayOutput, error) {
+	return c.ec2Client.DetachInternetGateway(input)
+}
+
+func (c *awsClient) DeleteInternetGateway(input *ec2.DeleteInternetGatewayInput) (*ec2.DeleteInternetGatewayOutput, error) {
+	return c.ec2Client.DeleteInternetGateway(input)
+}
+
+func (c *awsClient) DescribeAddresses(input *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error) {
+	return c.ec2Client.DescribeAddresses(input)
+}
+
+func (c *awsClient) ReleaseAddress(input *ec2.ReleaseAddressInput) (*ec2.ReleaseAddressOutput, error) {
+	return c.ec2Client.ReleaseAddress(input)
+}
+
+func (c *awsClient) DescribeNatGateways(input *ec2.DescribeNatGatewaysInput) (*ec2.DescribeNatGatewaysOutput, error) {
+	return c.ec2Client.DescribeNatGateways(input)
+}
+
+func (c *awsClient) DeleteNatGateway(input *ec2.DeleteNatGatewayInput) (*ec2.DeleteNatGatewayOutput, error) {
+	return c.ec2Client.DeleteNatGateway(input)
+}
+
+func (c *awsClient) DescribeNetworkInterfaces(input *ec2.DescribeNetworkInterfacesInput) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	return c.ec2Client.DescribeNetworkInterfaces(input)
+}
+
+func (c *awsClient) DetachNetworkInterface(input *ec2.DetachNetworkInterfaceInput) (*ec2.DetachNetworkInterfaceOutput, error) {
+	return c.ec2Client.DetachNetworkInterface(input)
+}
+
+func (c *awsClient) DeleteNetworkInterface(input *ec2.DeleteNetworkInterfaceInput) (*ec2.DeleteNetworkInterfaceOutput, error) {
+	return c.ec2Client.DeleteNetworkInterface(input)
+}
+
+func (c *awsClient) DescribeSecurityGroups(input *ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error) {
+	return c.ec2Client.DescribeSecurityGroups(input)
+}
+
+func (c *awsClient) RevokeSecurityGroupIngress(input *ec2.RevokeSecurityGroupIngressInput) (*ec2.RevokeSecurityGroupIngressOutput, error) {
+	return c.ec2Client.RevokeSecurityGroupIngress(input)
+}
+
+func (c *awsClient) RevokeSecurityGroupEgress(input *ec2.RevokeSecurityGroupEgressInput) (*ec2.RevokeSecurityGroupEgressOutput, error) {
+	return c.ec2Client.RevokeSecurityGroupEgress(input)
+}
+
+func (c *awsClient) DeleteSecurityGroup(input *ec2.DeleteSecurityGroupInput) (*ec2.DeleteSecurityGroupOutput, error) {
+	return c.ec2Client.DeleteSecurityGroup(input)
+}
+
 func (c *awsClient) CreateAccessKey(input *iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error) {
 	return c.iamClient.CreateAccessKey(input)
 }
@@ -301,6 +515,10 @@ func (c *awsClient) ListUserTags(input *iam.ListUserTagsInput) (*iam.ListUserTag
 	return c.iamClient.ListUserTags(input)
 }
 
+func (c *awsClient) TagUser(input *iam.TagUserInput) (*iam.TagUserOutput, error) {
+	return c.iamClient.TagUser(input)
+}
+
 func (c *awsClient) ListAccessKeys(input *iam.ListAccessKeysInput) (*iam.ListAccessKeysOutput, error) {
 	return c.iamClient.ListAccessKeys(input)
 }
@@ -389,6 +607,34 @@ func (c *awsClient) DeleteRole(input *iam.DeleteRoleInput) (*iam.DeleteRoleOutpu
 	return c.iamClient.DeleteRole(input)
 }
 
+func (c *awsClient) UpdateRole(input *iam.UpdateRoleInput) (*iam.UpdateRoleOutput, error) {
+	return c.iamClient.UpdateRole(input)
+}
+
+func (c *awsClient) UpdateAssumeRolePolicy(input *iam.UpdateAssumeRolePolicyInput) (*iam.UpdateAssumeRolePolicyOutput, error) {
+	return c.iamClient.UpdateAssumeRolePolicy(input)
+}
+
+func (c *awsClient) TagRole(input *iam.TagRoleInput) (*iam.TagRoleOutput, error) {
+	return c.iamClient.TagRole(input)
+}
+
+func (c *awsClient) PutUserPermissionsBoundary(input *iam.PutUserPermissionsBoundaryInput) (*iam.PutUserPermissionsBoundaryOutput, error) {
+	return c.iamClient.PutUserPermissionsBoundary(input)
+}
+
+func (c *awsClient) PutRolePermissionsBoundary(input *iam.PutRolePermissionsBoundaryInput) (*iam.PutRolePermissionsBoundaryOutput, error) {
+	return c.iamClient.PutRolePermissionsBoundary(input)
+}
+
+func (c *awsClient) UpdateAccountPasswordPolicy(input *iam.UpdateAccountPasswordPolicyInput) (*iam.UpdateAccountPasswordPolicyOutput, error) {
+	return c.iamClient.UpdateAccountPasswordPolicy(input)
+}
+
+func (c *awsClient) GetAccountSummary(input *iam.GetAccountSummaryInput) (*iam.GetAccountSummaryOutput, error) {
+	return c.iamClient.GetAccountSummary(input)
+}
+
 func (c *awsClient) ListRoles(input *iam.ListRolesInput) (*iam.ListRolesOutput, error) {
 	return c.iamClient.ListRoles(input)
 }
@@ -397,6 +643,10 @@ func (c *awsClient) ListAccounts(input *organizations.ListAccountsInput) (*organ
 	return c.orgClient.ListAccounts(input)
 }
 
+func (c *awsClient) ListAccountsForParent(input *organizations.ListAccountsForParentInput) (*organizations.ListAccountsForParentOutput, error) {
+	return c.orgClient.ListAccountsForParent(input)
+}
+
 func (c *awsClient) CreateAccount(input *organizations.CreateAccountInput) (*organizations.CreateAccountOutput, error) {
 	return c.orgClient.CreateAccount(input)
 }
@@ -437,6 +687,26 @@ func (c *awsClient) ListTagsForResource(input *organizations.ListTagsForResource
 	return c.orgClient.ListTagsForResource(input)
 }
 
+func (c *awsClient) AttachPolicy(input *organizations.AttachPolicyInput) (*organizations.AttachPolicyOutput, error) {
+	return c.orgClient.AttachPolicy(input)
+}
+
+func (c *awsClient) DetachPolicy(input *organizations.DetachPolicyInput) (*organizations.DetachPolicyOutput, error) {
+	return c.orgClient.DetachPolicy(input)
+}
+
+func (c *awsClient) ListPoliciesForTarget(input *organizations.ListPoliciesForTargetInput) (*organizations.ListPoliciesForTargetOutput, error) {
+	return c.orgClient.ListPoliciesForTarget(input)
+}
+
+func (c *awsClient) DescribeAccount(input *organizations.DescribeAccountInput) (*organizations.DescribeAccountOutput, error) {
+	return c.orgClient.DescribeAccount(input)
+}
+
+func (c *awsClient) DescribeOrganization(input *organizations.DescribeOrganizationInput) (*organizations.DescribeOrganizationOutput, error) {
+	return c.orgClient.DescribeOrganization(input)
+}
+
 func (c *awsClient) AssumeRole(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
 	return c.stsClient.AssumeRole(input)
 }
@@ -449,6 +719,22 @@ func (c *awsClient) DescribeCases(input *support.DescribeCasesInput) (*support.D
 	return c.supportClient.DescribeCases(input)
 }
 
+func (c *awsClient) DescribeTrustedAdvisorCheckResult(input *support.DescribeTrustedAdvisorCheckResultInput) (*support.DescribeTrustedAdvisorCheckResultOutput, error) {
+	return c.supportClient.DescribeTrustedAdvisorCheckResult(input)
+}
+
+func (c *awsClient) RefreshTrustedAdvisorCheck(input *support.RefreshTrustedAdvisorCheckInput) (*support.RefreshTrustedAdvisorCheckOutput, error) {
+	return c.supportClient.RefreshTrustedAdvisorCheck(input)
+}
+
+func (c *awsClient) DescribeEventsForOrganization(input *health.DescribeEventsForOrganizationInput) (*health.DescribeEventsForOrganizationOutput, error) {
+	return c.healthClient.DescribeEventsForOrganization(input)
+}
+
+func (c *awsClient) DescribeAffectedAccountsForOrganization(input *health.DescribeAffectedAccountsForOrganizationInput) (*health.DescribeAffectedAccountsForOrganizationOutput, error) {
+	return c.healthClient.DescribeAffectedAccountsForOrganization(input)
+}
+
 func (c *awsClient) GetCallerIdentity(input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
 	return c.stsClient.GetCallerIdentity(input)
 }
@@ -469,10 +755,46 @@ func (c *awsClient) DeleteBucket(input *s3.DeleteBucketInput) (*s3.DeleteBucketO
 	return c.s3Client.DeleteBucket(input)
 }
 
+func (c *awsClient) GetBucketPolicy(input *s3.GetBucketPolicyInput) (*s3.GetBucketPolicyOutput, error) {
+	return c.s3Client.GetBucketPolicy(input)
+}
+
+func (c *awsClient) DeleteBucketPolicy(input *s3.DeleteBucketPolicyInput) (*s3.DeleteBucketPolicyOutput, error) {
+	return c.s3Client.DeleteBucketPolicy(input)
+}
+
+func (c *awsClient) GetObjectLockConfiguration(input *s3.GetObjectLockConfigurationInput) (*s3.GetObjectLockConfigurationOutput, error) {
+	return c.s3Client.GetObjectLockConfiguration(input)
+}
+
 func (c *awsClient) ListObjectsV2(input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
 	return c.s3Client.ListObjectsV2(input)
 }
 
+func (c *awsClient) ListObjectVersions(input *s3.ListObjectVersionsInput) (*s3.ListObjectVersionsOutput, error) {
+	return c.s3Client.ListObjectVersions(input)
+}
+
+func (c *awsClient) CreateBucket(input *s3.CreateBucketInput) (*s3.CreateBucketOutput, error) {
+	return c.s3Client.CreateBucket(input)
+}
+
+func (c *awsClient) HeadBucket(input *s3.HeadBucketInput) (*s3.HeadBucketOutput, error) {
+	return c.s3Client.HeadBucket(input)
+}
+
+func (c *awsClient) PutBucketVersioning(input *s3.PutBucketVersioningInput) (*s3.PutBucketVersioningOutput, error) {
+	return c.s3Client.PutBucketVersioning(input)
+}
+
+func (c *awsClient) PutObject(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+	return c.s3Client.PutObject(input)
+}
+
+func (c *awsClient) PutPublicAccessBlock(input *s3control.PutPublicAccessBlockInput) (*s3control.PutPublicAccessBlockOutput, error) {
+	return c.s3ControlClient.PutPublicAccessBlock(input)
+}
+
 func (c *awsClient) BatchDeleteBucketObjects(bucketName *string) error {
 	// Setup BatchDeleteItrerator to iterate through a list of objects
 	iter := s3manager.NewDeleteListIterator(c.s3Client, &s3.ListObjectsInput{
@@ -483,6 +805,35 @@ func (c *awsClient) BatchDeleteBucketObjects(bucketName *string) error {
 	return s3manager.NewBatchDeleteWithClient(c.s3Client).Delete(aws.BackgroundContext(), iter)
 }
 
+// BatchDeleteBucketObjectVersions deletes every version and delete marker of the objects of a bucket. A versioned
+// bucket can only be deleted once they're all gone, deleting its current objects only adds delete markers.
+func (c *awsClient) BatchDeleteBucketObjectVersions(bucketName *string) error {
+	objects := []s3manager.BatchDeleteObject{}
+	err := c.s3Client.ListObjectVersionsPages(&s3.ListObjectVersionsInput{Bucket: bucketName},
+		func(page *s3.ListObjectVersionsOutput, lastPage bool) bool {
+			for _, version := range page.Versions {
+				objects = append(objects, s3manager.BatchDeleteObject{Object: &s3.DeleteObjectInput{
+					Bucket:    bucketName,
+					Key:       version.Key,
+					VersionId: version.VersionId,
+				}})
+			}
+			for _, marker := range page.DeleteMarkers {
+				objects = append(objects, s3manager.BatchDeleteObject{Object: &s3.DeleteObjectInput{
+					Bucket:    bucketName,
+					Key:       marker.Key,
+					VersionId: marker.VersionId,
+				}})
+			}
+			return true
+		})
+	if err != nil {
+		return err
+	}
+
+	return s3manager.NewBatchDeleteWithClient(c.s3Client).Delete(aws.BackgroundContext(), &s3manager.DeleteObjectsIterator{Objects: objects})
+}
+
 func (c *awsClient) ListHostedZones(input *route53.ListHostedZonesInput) (*route53.ListHostedZonesOutput, error) {
 	return c.route53client.ListHostedZones(input)
 }
@@ -499,6 +850,14 @@ func (c *awsClient) ChangeResourceRecordSets(input *route53.ChangeResourceRecord
 	return c.route53client.ChangeResourceRecordSets(input)
 }
 
+func (c *awsClient) CreateHostedZone(input *route53.CreateHostedZoneInput) (*route53.CreateHostedZoneOutput, error) {
+	return c.route53client.CreateHostedZone(input)
+}
+
+func (c *awsClient) GetHostedZone(input *route53.GetHostedZoneInput) (*route53.GetHostedZoneOutput, error) {
+	return c.route53client.GetHostedZone(input)
+}
+
 func (c *awsClient) GetServiceQuota(input *servicequotas.GetServiceQuotaInput) (*servicequotas.GetServiceQuotaOutput, error) {
 	return c.serviceQuotasClient.GetServiceQuota(input)
 }
@@ -515,12 +874,104 @@ func (c *awsClient) ListRequestedServiceQuotaChangeHistoryByQuota(input *service
 	return c.serviceQuotasClient.ListRequestedServiceQuotaChangeHistoryByQuota(input)
 }
 
+func (c *awsClient) CreateLogStream(input *cloudwatchlogs.CreateLogStreamInput) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	return c.cloudWatchLogsClient.CreateLogStream(input)
+}
+
+func (c *awsClient) PutLogEvents(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	return c.cloudWatchLogsClient.PutLogEvents(input)
+}
+
+func (c *awsClient) ReceiveMessage(input *sqs.ReceiveMessageInput) (*sqs.ReceiveMessageOutput, error) {
+	return c.sqsClient.ReceiveMessage(input)
+}
+
+func (c *awsClient) DeleteMessage(input *sqs.DeleteMessageInput) (*sqs.DeleteMessageOutput, error) {
+	return c.sqsClient.DeleteMessage(input)
+}
+
+func (c *awsClient) ValidatePolicy(input *accessanalyzer.ValidatePolicyInput) (*accessanalyzer.ValidatePolicyOutput, error) {
+	return c.accessAnalyzerClient.ValidatePolicy(input)
+}
+
+func (c *awsClient) CreateAccountAssignment(input *ssoadmin.CreateAccountAssignmentInput) (*ssoadmin.CreateAccountAssignmentOutput, error) {
+	return c.ssoAdminClient.CreateAccountAssignment(input)
+}
+
+func (c *awsClient) DeleteAccountAssignment(input *ssoadmin.DeleteAccountAssignmentInput) (*ssoadmin.DeleteAccountAssignmentOutput, error) {
+	return c.ssoAdminClient.DeleteAccountAssignment(input)
+}
+
+func (c *awsClient) DescribeAccountAssignmentCreationStatus(input *ssoadmin.DescribeAccountAssignmentCreationStatusInput) (*ssoadmin.DescribeAccountAssignmentCreationStatusOutput, error) {
+	return c.ssoAdminClient.DescribeAccountAssignmentCreationStatus(input)
+}
+
+func (c *awsClient) ListAccountAssignments(input *ssoadmin.ListAccountAssignmentsInput) (*ssoadmin.ListAccountAssignmentsOutput, error) {
+	return c.ssoAdminClient.ListAccountAssignments(input)
+}
+
+func (c *awsClient) CreateBudget(input *budgets.CreateBudgetInput) (*budgets.CreateBudgetOutput, error) {
+	return c.budgetsClient.CreateBudget(input)
+}
+
+func (c *awsClient) DescribeBudget(input *budgets.DescribeBudgetInput) (*budgets.DescribeBudgetOutput, error) {
+	return c.budgetsClient.DescribeBudget(input)
+}
+
+func (c *awsClient) DeleteBudget(input *budgets.DeleteBudgetInput) (*budgets.DeleteBudgetOutput, error) {
+	return c.budgetsClient.DeleteBudget(input)
+}
+
+func (c *awsClient) GetCostAndUsage(input *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error) {
+	return c.costExplorerClient.GetCostAndUsage(input)
+}
+
+func (c *awsClient) ListCostAllocationTags(input *costexplorer.ListCostAllocationTagsInput) (*costexplorer.ListCostAllocationTagsOutput, error) {
+	return c.costExplorerClient.ListCostAllocationTags(input)
+}
+
+func (c *awsClient) UpdateCostAllocationTagsStatus(input *costexplorer.UpdateCostAllocationTagsStatusInput) (*costexplorer.UpdateCostAllocationTagsStatusOutput, error) {
+	return c.costExplorerClient.UpdateCostAllocationTagsStatus(input)
+}
+
+func (c *awsClient) DescribeTrails(input *cloudtrail.DescribeTrailsInput) (*cloudtrail.DescribeTrailsOutput, error) {
+	return c.cloudTrailClient.DescribeTrails(input)
+}
+
+func (c *awsClient) GetTrailStatus(input *cloudtrail.GetTrailStatusInput) (*cloudtrail.GetTrailStatusOutput, error) {
+	return c.cloudTrailClient.GetTrailStatus(input)
+}
+
+func (c *awsClient) StartLogging(input *cloudtrail.StartLoggingInput) (*cloudtrail.StartLoggingOutput, error) {
+	return c.cloudTrailClient.StartLogging(input)
+}
+
+func (c *awsClient) ListDetectors(input *guardduty.ListDetectorsInput) (*guardduty.ListDetectorsOutput, error) {
+	return c.guardDutyClient.ListDetectors(input)
+}
+
+func (c *awsClient) CreateMembers(input *guardduty.CreateMembersInput) (*guardduty.CreateMembersOutput, error) {
+	return c.guardDutyClient.CreateMembers(input)
+}
+
+func (c *awsClient) StopMonitoringMembers(input *guardduty.StopMonitoringMembersInput) (*guardduty.StopMonitoringMembersOutput, error) {
+	return c.guardDutyClient.StopMonitoringMembers(input)
+}
+
+func (c *awsClient) DisassociateMembers(input *guardduty.DisassociateMembersInput) (*guardduty.DisassociateMembersOutput, error) {
+	return c.guardDutyClient.DisassociateMembers(input)
+}
+
+func (c *awsClient) DeleteMembers(input *guardduty.DeleteMembersInput) (*guardduty.DeleteMembersOutput, error) {
+	return c.guardDutyClient.DeleteMembers(input)
+}
+
 var awsApiTimeout time.Duration = 30 * time.Second
 var awsApiMaxRetries int = 10
 
 // NewClient creates our client wrapper object for the actual AWS clients we use.
 // If controllerName is nonempty, metrics are collected timing and counting each AWS request.
-func newClient(controllerName, awsAccessID, awsAccessSecret, token, region string) (Client, error) {
+func newClient(ctx context.Context, controllerName, awsAccessID, awsAccessSecret, token, region, permissionsBoundary string, executor Executor, limiter *rate.Limiter) (Client, error) {
 	// dereferencing http.DefaultClient so we copy the underlying struct instead of copying the pointer.
 	timeOutHttpClient := *http.DefaultClient
 	timeOutHttpClient.Timeout = awsApiTimeout
@@ -536,6 +987,11 @@ func newClient(controllerName, awsAccessID, awsAccessSecret, token, region strin
 			MinThrottleDelay: 2 * time.Second,
 		},
 	}
+	// In LocalStack mode every service is called at the LocalStack endpoint, which only serves path-style S3 URLs
+	if utils.IsLocalStack() {
+		awsConfig.Endpoint = aws.String(utils.LocalStackEndpoint())
+		awsConfig.S3ForcePathStyle = aws.Bool(true)
+	}
 
 	s, err := session.NewSession(awsConfig)
 	if err != nil {
@@ -544,9 +1000,13 @@ func newClient(controllerName, awsAccessID, awsAccessSecret, token, region strin
 
 	// Use a regional endpoint for ec2 calls in order to reach opt-in regions when necessary
 	resolver := func(service, region string, optFns ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		url := fmt.Sprintf("https://ec2.%s.amazonaws.com", region)
+		if utils.IsLocalStack() {
+			url = utils.LocalStackEndpoint()
+		}
 		return endpoints.ResolvedEndpoint{
 			PartitionID:   "aws",
-			URL:           fmt.Sprintf("https://ec2.%s.amazonaws.com", region),
+			URL:           url,
 			SigningRegion: region,
 		}, nil
 	}
@@ -580,16 +1040,57 @@ func newClient(controllerName, awsAccessID, awsAccessSecret, token, region strin
 		})
 	}
 
+	// Trace each AWS call as a child span of the reconcile in ctx, when tracing is enabled
+	s.Handlers.Complete.PushBack(traceAPICall(ctx))
+	ec2Sess.Handlers.Complete.PushBack(traceAPICall(ctx))
+
+	// Record deletions and credential rotations in the audit trail
+	s.Handlers.Complete.PushBack(auditAPICall(ctx, controllerName))
+	ec2Sess.Handlers.Complete.PushBack(auditAPICall(ctx, controllerName))
+
+	// Apply the configured permissions boundary to every IAM user and role the operator creates
+	if permissionsBoundary != "" {
+		s.Handlers.Validate.PushFront(permissionsBoundaryHandler(permissionsBoundary))
+	}
+
+	// Share the rate limit of the member account with the other clients making calls to it
+	if limiter != nil {
+		s.Handlers.Send.PushFront(rateLimitHandler(limiter))
+		ec2Sess.Handlers.Send.PushFront(rateLimitHandler(limiter))
+	}
+
+	// Hand the calls that could change something to the executor of the operator mode, e.g. maintenance mode
+	if executor != nil {
+		s.Handlers.Validate.PushBack(executeHandler(ctx, executor))
+		ec2Sess.Handlers.Validate.PushBack(executeHandler(ctx, executor))
+	}
+
+	// LocalStack doesn't emulate Organizations, the accounts are created in an organization simulated in memory
+	var orgClient organizationsiface.OrganizationsAPI = organizations.New(s)
+	if utils.IsLocalStack() {
+		orgClient = newSimulatedOrganizationsClient(orgClient)
+	}
+
 	return &awsClient{
-		acctClient:          account.New(s),
-		iamClient:           iam.New(s),
-		ec2Client:           ec2.New(ec2Sess),
-		orgClient:           organizations.New(s),
-		route53client:       route53.New(s),
-		s3Client:            s3.New(s),
-		stsClient:           sts.New(s),
-		supportClient:       support.New(s),
-		serviceQuotasClient: servicequotas.New(s),
+		acctClient:           account.New(s),
+		iamClient:            iam.New(s),
+		ec2Client:            ec2.New(ec2Sess),
+		orgClient:            orgClient,
+		route53client:        route53.New(s),
+		s3Client:             s3.New(s),
+		s3ControlClient:      s3control.New(s),
+		stsClient:            sts.New(s),
+		supportClient:        support.New(s),
+		serviceQuotasClient:  servicequotas.New(s),
+		healthClient:         health.New(s),
+		cloudWatchLogsClient: cloudwatchlogs.New(s),
+		sqsClient:            sqs.New(s),
+		accessAnalyzerClient: accessanalyzer.New(s),
+		ssoAdminClient:       ssoadmin.New(s),
+		budgetsClient:        budgets.New(s),
+		costExplorerClient:   costexplorer.New(s),
+		cloudTrailClient:     cloudtrail.New(s),
+		guardDutyClient:      guardduty.New(s),
 	}, nil
 }
 
@@ -614,6 +1115,13 @@ func (rp *Builder) GetClient(controllerName string, kubeClient kubeclientpkg.Cli
 		return nil, fmt.Errorf("getAWSClient:NoRegion: %v", input.AwsRegion)
 	}
 
+	permissionsBoundary, err := utils.GetPermissionsBoundaryARN(kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	executor := executorFor(kubeClient)
+	limiter := accountRateLimiter(kubeClient, input.AwsAccountID)
+
 	if input.SecretName != "" && input.NameSpace != "" {
 		secret := &corev1.Secret{}
 		err := kubeClient.Get(context.TODO(),
@@ -636,7 +1144,7 @@ func (rp *Builder) GetClient(controllerName string, kubeClient kubeclientpkg.Cli
 				input.SecretName, awsCredsSecretAccessKey)
 		}
 
-		awsClient, err := newClient(controllerName, string(accessKeyID), string(secretAccessKey), input.AwsToken, input.AwsRegion)
+		awsClient, err := newClient(input.Context, controllerName, string(accessKeyID), string(secretAccessKey), input.AwsToken, input.AwsRegion, permissionsBoundary, executor, limiter)
 		if err != nil {
 			return nil, err
 		}
@@ -647,7 +1155,7 @@ func (rp *Builder) GetClient(controllerName string, kubeClient kubeclientpkg.Cli
 		return nil, fmt.Errorf("getAWSClient: NoAwsCredentials or Secret %v", input)
 	}
 
-	awsClient, err := newClient(controllerName, input.AwsCredsSecretIDKey, input.AwsCredsSecretAccessKey, input.AwsToken, input.AwsRegion)
+	awsClient, err := newClient(input.Context, controllerName, input.AwsCredsSecretIDKey, input.AwsCredsSecretAccessKey, input.AwsToken, input.AwsRegion, permissionsBoundary, executor, limiter)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package awsclient
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"time"
@@ -28,7 +29,7 @@ var _ = Describe("AWS Resource Tag Builder", func() {
 				},
 			}
 
-			client, err := newClient("", "sss", "TESTSTETST", "eu-central-1", "eu-central-1")
+			client, err := newClient(context.TODO(), "", "sss", "TESTSTETST", "eu-central-1", "eu-central-1", "", nil, nil)
 			done := make(chan error)
 			// call describeRegions asyncronously
 			go func() {
@@ -0,0 +1,128 @@
+package awsclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	kubeclientpkg "sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var executorLog = logf.Log.WithName("aws-executor")
+
+// Executor decides what happens to the mutating AWS calls made through a client, before they are sent. Clients
+// without an Executor send every call.
+type Executor interface {
+	// Execute is called with each mutating call. Setting r.Error prevents the call from being sent.
+	Execute(ctx context.Context, r *request.Request)
+}
+
+// executorFor returns the Executor of the clients built while the operator is in the current mode, or nil when
+// the mutating calls are sent
+func executorFor(kubeClient kubeclientpkg.Client) Executor {
+	if utils.IsObserveOnly() {
+		return observeOnlyExecutor{}
+	}
+	if utils.IsMaintenanceMode(kubeClient) {
+		return maintenanceModeExecutor{}
+	}
+	return nil
+}
+
+// executeHandler returns a handler passing the mutating AWS calls made through the client to the executor
+func executeHandler(ctx context.Context, executor Executor) func(r *request.Request) {
+	return func(r *request.Request) {
+		if !isMutatingOperation(r.Operation.Name) {
+			return
+		}
+		executor.Execute(ctx, r)
+	}
+}
+
+// observeOnlyExecutor records the mutating AWS calls in the Plan of the context instead of sending them
+type observeOnlyExecutor struct{}
+
+// Execute implements Executor
+func (observeOnlyExecutor) Execute(ctx context.Context, r *request.Request) {
+	action := PlannedAction{
+		Operation: r.ClientInfo.ServiceName + ":" + r.Operation.Name,
+		Region:    aws.StringValue(r.Config.Region),
+		Target:    auditTarget(r.Params),
+	}
+	executorLog.Info("Observe-only mode, not making AWS call", "operation", action.Operation, "region", action.Region, "target", action.Target)
+	if plan := PlanFromContext(ctx); plan != nil {
+		plan.add(action)
+	}
+	r.Error = awserr.New(utils.ErrCodeObserveOnly, fmt.Sprintf("%s is only planned in observe-only mode", action), nil)
+}
+
+// PlannedAction is a mutating AWS call the operator would have made if it wasn't in observe-only mode
+type PlannedAction struct {
+	Operation string
+	Region    string
+	Target    string
+}
+
+// String describes the action, e.g. "iam:DeleteUser UserName=osdManagedAdmin in us-east-1"
+func (a PlannedAction) String() string {
+	description := []string{a.Operation}
+	if a.Target != "" {
+		description = append(description, a.Target)
+	}
+	if a.Region != "" {
+		description = append(description, "in "+a.Region)
+	}
+	return strings.Join(description, " ")
+}
+
+// Plan collects the actions planned while reconciling a CR in observe-only mode
+type Plan struct {
+	mutex   sync.Mutex
+	actions []PlannedAction
+}
+
+func (p *Plan) add(action PlannedAction) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.actions = append(p.actions, action)
+}
+
+// Actions returns the planned actions, in the order they were planned
+func (p *Plan) Actions() []PlannedAction {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]PlannedAction(nil), p.actions...)
+}
+
+type planContextKey struct{}
+
+// WithPlan returns a context collecting the actions planned in observe-only mode by the clients built with it
+func WithPlan(ctx context.Context) (context.Context, *Plan) {
+	plan := &Plan{}
+	return context.WithValue(ctx, planContextKey{}, plan), plan
+}
+
+// PlanFromContext returns the Plan of ctx, or nil if it doesn't collect planned actions
+func PlanFromContext(ctx context.Context) *Plan {
+	if ctx == nil {
+		return nil
+	}
+	plan, _ := ctx.Value(planContextKey{}).(*Plan)
+	return plan
+}
+
+// RecordPlannedActions emits a PlannedAction Event on the object for each action of the plan
+func RecordPlannedActions(recorder record.EventRecorder, object runtime.Object, plan *Plan) {
+	for _, action := range plan.Actions() {
+		utils.RecordEvent(recorder, object, corev1.EventTypeNormal, utils.EventReasonPlannedAction, "Observe-only mode, would have made %s", action)
+	}
+}
@@ -0,0 +1,58 @@
+package awsclient
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/iam"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+var _ = Describe("Observe-only mode", func() {
+	var (
+		ctx     context.Context
+		plan    *Plan
+		handler func(r *request.Request)
+	)
+
+	BeforeEach(func() {
+		ctx, plan = WithPlan(context.TODO())
+		handler = executeHandler(ctx, observeOnlyExecutor{})
+	})
+
+	It("Should plan the mutating operations instead of making them", func() {
+		r := &request.Request{
+			ClientInfo: metadata.ClientInfo{ServiceName: "iam"},
+			Operation:  &request.Operation{Name: "DeleteUser"},
+			Config:     aws.Config{Region: aws.String("us-east-1")},
+			Params:     &iam.DeleteUserInput{UserName: aws.String("osdManagedAdmin")},
+		}
+		handler(r)
+		Expect(utils.IsObserveOnlyError(r.Error)).To(BeTrue())
+		Expect(plan.Actions()).To(Equal([]PlannedAction{{Operation: "iam:DeleteUser", Region: "us-east-1", Target: "UserName=osdManagedAdmin"}}))
+		Expect(plan.Actions()[0].String()).To(Equal("iam:DeleteUser UserName=osdManagedAdmin in us-east-1"))
+	})
+
+	It("Should make the read-only operations", func() {
+		r := &request.Request{
+			ClientInfo: metadata.ClientInfo{ServiceName: "iam"},
+			Operation:  &request.Operation{Name: "ListUsers"},
+		}
+		handler(r)
+		Expect(r.Error).ToNot(HaveOccurred())
+		Expect(plan.Actions()).To(BeEmpty())
+	})
+
+	It("Should not need a plan", func() {
+		r := &request.Request{
+			ClientInfo: metadata.ClientInfo{ServiceName: "s3"},
+			Operation:  &request.Operation{Name: "DeleteBucket"},
+		}
+		executeHandler(context.TODO(), observeOnlyExecutor{})(r)
+		Expect(utils.IsObserveOnlyError(r.Error)).To(BeTrue())
+	})
+})
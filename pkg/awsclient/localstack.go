@@ -0,0 +1,309 @@
+package awsclient
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+)
+
+// LocalStackRootID is the ID of the root of the organization simulated in LocalStack mode, to set as the "root" of
+// the operator configmap
+const LocalStackRootID = "r-local"
+
+// localStackOrganization is the organization shared by all the clients built in LocalStack mode. LocalStack doesn't
+// emulate Organizations, so the accounts created by the operator only exist in it, and the calls to the member
+// accounts go to the LocalStack account with the same ID.
+var localStackOrganization = newSimulatedOrganization()
+
+// simulatedOrganization is an in-memory AWS Organization, with its accounts, OUs and their tags
+type simulatedOrganization struct {
+	mutex          sync.Mutex
+	accounts       map[string]*organizations.Account
+	createStatuses map[string]*organizations.CreateAccountStatus
+	ous            map[string]*organizations.OrganizationalUnit
+	// parents maps the ID of each account and OU to the ID of its parent, the root or an OU
+	parents map[string]string
+	tags    map[string]map[string]string
+	counter int
+}
+
+func newSimulatedOrganization() *simulatedOrganization {
+	return &simulatedOrganization{
+		accounts:       map[string]*organizations.Account{},
+		createStatuses: map[string]*organizations.CreateAccountStatus{},
+		ous:            map[string]*organizations.OrganizationalUnit{},
+		parents:        map[string]string{},
+		tags:           map[string]map[string]string{},
+	}
+}
+
+// nextID returns a new sequence number, for the IDs of the accounts, OUs and account creation requests
+func (o *simulatedOrganization) nextID() int {
+	o.counter++
+	return o.counter
+}
+
+// isParent checks whether id is the root or an OU
+func (o *simulatedOrganization) isParent(id string) bool {
+	_, ok := o.ous[id]
+	return id == LocalStackRootID || ok
+}
+
+// simulatedOrganizationsClient serves the Organizations calls made by the operator from the simulated organization.
+// The calls it doesn't simulate go to LocalStack.
+type simulatedOrganizationsClient struct {
+	organizationsiface.OrganizationsAPI
+	org *simulatedOrganization
+}
+
+// newSimulatedOrganizationsClient returns a client of the organization shared by the clients built in LocalStack mode
+func newSimulatedOrganizationsClient(localStackClient organizationsiface.OrganizationsAPI) *simulatedOrganizationsClient {
+	return &simulatedOrganizationsClient{OrganizationsAPI: localStackClient, org: localStackOrganization}
+}
+
+// CreateAccount creates the account immediately, its creation request has SUCCEEDED when it returns
+func (c *simulatedOrganizationsClient) CreateAccount(input *organizations.CreateAccountInput) (*organizations.CreateAccountOutput, error) {
+	c.org.mutex.Lock()
+	defer c.org.mutex.Unlock()
+
+	for _, account := range c.org.accounts {
+		if aws.StringValue(account.Email) == aws.StringValue(input.Email) {
+			return nil, awserr.New(organizations.ErrCodeConstraintViolationException, fmt.Sprintf("an account already uses the email %s", aws.StringValue(input.Email)), nil)
+		}
+	}
+
+	now := time.Now()
+	id := fmt.Sprintf("%012d", 100000000000+c.org.nextID())
+	c.org.accounts[id] = &organizations.Account{
+		Id:              aws.String(id),
+		Arn:             aws.String(fmt.Sprintf("arn:aws:organizations::000000000000:account/o-local/%s", id)),
+		Name:            input.AccountName,
+		Email:           input.Email,
+		Status:          aws.String(organizations.AccountStatusActive),
+		JoinedMethod:    aws.String(organizations.AccountJoinedMethodCreated),
+		JoinedTimestamp: aws.Time(now),
+	}
+	c.org.parents[id] = LocalStackRootID
+	c.org.tags[id] = map[string]string{}
+	for _, tag := range input.Tags {
+		c.org.tags[id][aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+
+	status := &organizations.CreateAccountStatus{
+		Id:                 aws.String(fmt.Sprintf("car-local%d", c.org.nextID())),
+		AccountId:          aws.String(id),
+		AccountName:        input.AccountName,
+		State:              aws.String(organizations.CreateAccountStateSucceeded),
+		RequestedTimestamp: aws.Time(now),
+		CompletedTimestamp: aws.Time(now),
+	}
+	c.org.createStatuses[aws.StringValue(status.Id)] = status
+	return &organizations.CreateAccountOutput{CreateAccountStatus: status}, nil
+}
+
+// DescribeCreateAccountStatus implements organizationsiface.OrganizationsAPI
+func (c *simulatedOrganizationsClient) DescribeCreateAccountStatus(input *organizations.DescribeCreateAccountStatusInput) (*organizations.DescribeCreateAccountStatusOutput, error) {
+	c.org.mutex.Lock()
+	defer c.org.mutex.Unlock()
+
+	status, ok := c.org.createStatuses[aws.StringValue(input.CreateAccountRequestId)]
+	if !ok {
+		return nil, awserr.New(organizations.ErrCodeCreateAccountStatusNotFoundException, "no account creation request with this ID", nil)
+	}
+	return &organizations.DescribeCreateAccountStatusOutput{CreateAccountStatus: status}, nil
+}
+
+// ListAccounts returns all the accounts in a single page
+func (c *simulatedOrganizationsClient) ListAccounts(input *organizations.ListAccountsInput) (*organizations.ListAccountsOutput, error) {
+	c.org.mutex.Lock()
+	defer c.org.mutex.Unlock()
+
+	return &organizations.ListAccountsOutput{Accounts: c.org.accountsUnder("")}, nil
+}
+
+// ListAccountsForParent returns all the accounts of the parent in a single page
+func (c *simulatedOrganizationsClient) ListAccountsForParent(input *organizations.ListAccountsForParentInput) (*organizations.ListAccountsForParentOutput, error) {
+	c.org.mutex.Lock()
+	defer c.org.mutex.Unlock()
+
+	if !c.org.isParent(aws.StringValue(input.ParentId)) {
+		return nil, awserr.New(organizations.ErrCodeParentNotFoundException, "no root or OU with this ID", nil)
+	}
+	return &organizations.ListAccountsForParentOutput{Accounts: c.org.accountsUnder(aws.StringValue(input.ParentId))}, nil
+}
+
+// accountsUnder returns the accounts of the parent, or all the accounts if parentID is empty, sorted by ID
+func (o *simulatedOrganization) accountsUnder(parentID string) []*organizations.Account {
+	var accounts []*organizations.Account
+	for id, account := range o.accounts {
+		if parentID == "" || o.parents[id] == parentID {
+			accounts = append(accounts, account)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return aws.StringValue(accounts[i].Id) < aws.StringValue(accounts[j].Id) })
+	return accounts
+}
+
+// MoveAccount implements organizationsiface.OrganizationsAPI
+func (c *simulatedOrganizationsClient) MoveAccount(input *organizations.MoveAccountInput) (*organizations.MoveAccountOutput, error) {
+	c.org.mutex.Lock()
+	defer c.org.mutex.Unlock()
+
+	accountID := aws.StringValue(input.AccountId)
+	if _, ok := c.org.accounts[accountID]; !ok {
+		return nil, awserr.New(organizations.ErrCodeAccountNotFoundException, "no account with this ID", nil)
+	}
+	if c.org.parents[accountID] != aws.StringValue(input.SourceParentId) {
+		return nil, awserr.New(organizations.ErrCodeSourceParentNotFoundException, "the source parent isn't the parent of the account", nil)
+	}
+	if !c.org.isParent(aws.StringValue(input.DestinationParentId)) {
+		return nil, awserr.New(organizations.ErrCodeDestinationParentNotFoundException, "no root or OU with this ID", nil)
+	}
+	c.org.parents[accountID] = aws.StringValue(input.DestinationParentId)
+	return &organizations.MoveAccountOutput{}, nil
+}
+
+// CreateOrganizationalUnit implements organizationsiface.OrganizationsAPI
+func (c *simulatedOrganizationsClient) CreateOrganizationalUnit(input *organizations.CreateOrganizationalUnitInput) (*organizations.CreateOrganizationalUnitOutput, error) {
+	c.org.mutex.Lock()
+	defer c.org.mutex.Unlock()
+
+	parentID := aws.StringValue(input.ParentId)
+	if !c.org.isParent(parentID) {
+		return nil, awserr.New(organizations.ErrCodeParentNotFoundException, "no root or OU with this ID", nil)
+	}
+	for _, ou := range c.org.ousUnder(parentID) {
+		if aws.StringValue(ou.Name) == aws.StringValue(input.Name) {
+			return nil, awserr.New(organizations.ErrCodeDuplicateOrganizationalUnitException, "an OU with this name already exists in the parent", nil)
+		}
+	}
+
+	id := fmt.Sprintf("ou-local-%08d", c.org.nextID())
+	ou := &organizations.OrganizationalUnit{
+		Id:   aws.String(id),
+		Arn:  aws.String(fmt.Sprintf("arn:aws:organizations::000000000000:ou/o-local/%s", id)),
+		Name: input.Name,
+	}
+	c.org.ous[id] = ou
+	c.org.parents[id] = parentID
+	c.org.tags[id] = map[string]string{}
+	for _, tag := range input.Tags {
+		c.org.tags[id][aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return &organizations.CreateOrganizationalUnitOutput{OrganizationalUnit: ou}, nil
+}
+
+// ListOrganizationalUnitsForParent returns all the OUs of the parent in a single page
+func (c *simulatedOrganizationsClient) ListOrganizationalUnitsForParent(input *organizations.ListOrganizationalUnitsForParentInput) (*organizations.ListOrganizationalUnitsForParentOutput, error) {
+	c.org.mutex.Lock()
+	defer c.org.mutex.Unlock()
+
+	if !c.org.isParent(aws.StringValue(input.ParentId)) {
+		return nil, awserr.New(organizations.ErrCodeParentNotFoundException, "no root or OU with this ID", nil)
+	}
+	return &organizations.ListOrganizationalUnitsForParentOutput{OrganizationalUnits: c.org.ousUnder(aws.StringValue(input.ParentId))}, nil
+}
+
+// ousUnder returns the OUs of the parent, sorted by ID
+func (o *simulatedOrganization) ousUnder(parentID string) []*organizations.OrganizationalUnit {
+	var ous []*organizations.OrganizationalUnit
+	for id, ou := range o.ous {
+		if o.parents[id] == parentID {
+			ous = append(ous, ou)
+		}
+	}
+	sort.Slice(ous, func(i, j int) bool { return aws.StringValue(ous[i].Id) < aws.StringValue(ous[j].Id) })
+	return ous
+}
+
+// ListChildren returns all the children of the given type of the parent in a single page
+func (c *simulatedOrganizationsClient) ListChildren(input *organizations.ListChildrenInput) (*organizations.ListChildrenOutput, error) {
+	c.org.mutex.Lock()
+	defer c.org.mutex.Unlock()
+
+	parentID := aws.StringValue(input.ParentId)
+	if !c.org.isParent(parentID) {
+		return nil, awserr.New(organizations.ErrCodeParentNotFoundException, "no root or OU with this ID", nil)
+	}
+	output := &organizations.ListChildrenOutput{}
+	switch aws.StringValue(input.ChildType) {
+	case organizations.ChildTypeAccount:
+		for _, account := range c.org.accountsUnder(parentID) {
+			output.Children = append(output.Children, &organizations.Child{Id: account.Id, Type: aws.String(organizations.ChildTypeAccount)})
+		}
+	case organizations.ChildTypeOrganizationalUnit:
+		for _, ou := range c.org.ousUnder(parentID) {
+			output.Children = append(output.Children, &organizations.Child{Id: ou.Id, Type: aws.String(organizations.ChildTypeOrganizationalUnit)})
+		}
+	}
+	return output, nil
+}
+
+// ListParents implements organizationsiface.OrganizationsAPI
+func (c *simulatedOrganizationsClient) ListParents(input *organizations.ListParentsInput) (*organizations.ListParentsOutput, error) {
+	c.org.mutex.Lock()
+	defer c.org.mutex.Unlock()
+
+	parentID, ok := c.org.parents[aws.StringValue(input.ChildId)]
+	if !ok {
+		return nil, awserr.New(organizations.ErrCodeChildNotFoundException, "no account or OU with this ID", nil)
+	}
+	parentType := organizations.ParentTypeOrganizationalUnit
+	if parentID == LocalStackRootID {
+		parentType = organizations.ParentTypeRoot
+	}
+	return &organizations.ListParentsOutput{Parents: []*organizations.Parent{{Id: aws.String(parentID), Type: aws.String(parentType)}}}, nil
+}
+
+// TagResource implements organizationsiface.OrganizationsAPI
+func (c *simulatedOrganizationsClient) TagResource(input *organizations.TagResourceInput) (*organizations.TagResourceOutput, error) {
+	c.org.mutex.Lock()
+	defer c.org.mutex.Unlock()
+
+	tags, ok := c.org.tags[aws.StringValue(input.ResourceId)]
+	if !ok {
+		return nil, awserr.New(organizations.ErrCodeTargetNotFoundException, "no account or OU with this ID", nil)
+	}
+	for _, tag := range input.Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return &organizations.TagResourceOutput{}, nil
+}
+
+// UntagResource implements organizationsiface.OrganizationsAPI
+func (c *simulatedOrganizationsClient) UntagResource(input *organizations.UntagResourceInput) (*organizations.UntagResourceOutput, error) {
+	c.org.mutex.Lock()
+	defer c.org.mutex.Unlock()
+
+	tags, ok := c.org.tags[aws.StringValue(input.ResourceId)]
+	if !ok {
+		return nil, awserr.New(organizations.ErrCodeTargetNotFoundException, "no account or OU with this ID", nil)
+	}
+	for _, key := range input.TagKeys {
+		delete(tags, aws.StringValue(key))
+	}
+	return &organizations.UntagResourceOutput{}, nil
+}
+
+// ListTagsForResource returns all the tags of the resource in a single page
+func (c *simulatedOrganizationsClient) ListTagsForResource(input *organizations.ListTagsForResourceInput) (*organizations.ListTagsForResourceOutput, error) {
+	c.org.mutex.Lock()
+	defer c.org.mutex.Unlock()
+
+	tags, ok := c.org.tags[aws.StringValue(input.ResourceId)]
+	if !ok {
+		return nil, awserr.New(organizations.ErrCodeTargetNotFoundException, "no account or OU with this ID", nil)
+	}
+	output := &organizations.ListTagsForResourceOutput{}
+	for key, value := range tags {
+		output.Tags = append(output.Tags, &organizations.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	sort.Slice(output.Tags, func(i, j int) bool { return aws.StringValue(output.Tags[i].Key) < aws.StringValue(output.Tags[j].Key) })
+	return output, nil
+}
@@ -0,0 +1,88 @@
+package awsclient
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Simulated organization", func() {
+	var client *simulatedOrganizationsClient
+
+	BeforeEach(func() {
+		client = &simulatedOrganizationsClient{org: newSimulatedOrganization()}
+	})
+
+	createAccount := func(email string) string {
+		output, err := client.CreateAccount(&organizations.CreateAccountInput{AccountName: aws.String(email), Email: aws.String(email)})
+		Expect(err).ToNot(HaveOccurred())
+		status, err := client.DescribeCreateAccountStatus(&organizations.DescribeCreateAccountStatusInput{CreateAccountRequestId: output.CreateAccountStatus.Id})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aws.StringValue(status.CreateAccountStatus.State)).To(Equal(organizations.CreateAccountStateSucceeded))
+		return aws.StringValue(status.CreateAccountStatus.AccountId)
+	}
+
+	errorCode := func(err error) string {
+		aerr, ok := err.(awserr.Error)
+		Expect(ok).To(BeTrue())
+		return aerr.Code()
+	}
+
+	It("Should create accounts in the root", func() {
+		first := createAccount("first@example.com")
+		second := createAccount("second@example.com")
+		Expect(first).To(HaveLen(12))
+		Expect(first).ToNot(Equal(second))
+
+		accounts, err := client.ListAccounts(&organizations.ListAccountsInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(accounts.Accounts).To(HaveLen(2))
+		parents, err := client.ListParents(&organizations.ListParentsInput{ChildId: aws.String(first)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aws.StringValue(parents.Parents[0].Id)).To(Equal(LocalStackRootID))
+
+		_, err = client.CreateAccount(&organizations.CreateAccountInput{AccountName: aws.String("again"), Email: aws.String("first@example.com")})
+		Expect(errorCode(err)).To(Equal(organizations.ErrCodeConstraintViolationException))
+	})
+
+	It("Should move accounts to OUs", func() {
+		accountID := createAccount("claimed@example.com")
+		ou, err := client.CreateOrganizationalUnit(&organizations.CreateOrganizationalUnitInput{ParentId: aws.String(LocalStackRootID), Name: aws.String("claimed")})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.CreateOrganizationalUnit(&organizations.CreateOrganizationalUnitInput{ParentId: aws.String(LocalStackRootID), Name: aws.String("claimed")})
+		Expect(errorCode(err)).To(Equal(organizations.ErrCodeDuplicateOrganizationalUnitException))
+
+		_, err = client.MoveAccount(&organizations.MoveAccountInput{AccountId: aws.String(accountID), SourceParentId: ou.OrganizationalUnit.Id, DestinationParentId: aws.String(LocalStackRootID)})
+		Expect(errorCode(err)).To(Equal(organizations.ErrCodeSourceParentNotFoundException))
+		_, err = client.MoveAccount(&organizations.MoveAccountInput{AccountId: aws.String(accountID), SourceParentId: aws.String(LocalStackRootID), DestinationParentId: ou.OrganizationalUnit.Id})
+		Expect(err).ToNot(HaveOccurred())
+
+		children, err := client.ListChildren(&organizations.ListChildrenInput{ParentId: ou.OrganizationalUnit.Id, ChildType: aws.String(organizations.ChildTypeAccount)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(children.Children).To(HaveLen(1))
+		Expect(aws.StringValue(children.Children[0].Id)).To(Equal(accountID))
+		inRoot, err := client.ListAccountsForParent(&organizations.ListAccountsForParentInput{ParentId: aws.String(LocalStackRootID)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inRoot.Accounts).To(BeEmpty())
+	})
+
+	It("Should tag accounts", func() {
+		accountID := createAccount("tagged@example.com")
+		_, err := client.TagResource(&organizations.TagResourceInput{ResourceId: aws.String(accountID), Tags: []*organizations.Tag{
+			{Key: aws.String("owner"), Value: aws.String("shard-1")},
+			{Key: aws.String("claimed"), Value: aws.String("true")},
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.UntagResource(&organizations.UntagResourceInput{ResourceId: aws.String(accountID), TagKeys: []*string{aws.String("claimed")}})
+		Expect(err).ToNot(HaveOccurred())
+
+		tags, err := client.ListTagsForResource(&organizations.ListTagsForResourceInput{ResourceId: aws.String(accountID)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tags.Tags).To(Equal([]*organizations.Tag{{Key: aws.String("owner"), Value: aws.String("shard-1")}}))
+
+		_, err = client.ListTagsForResource(&organizations.ListTagsForResourceInput{ResourceId: aws.String("999999999999")})
+		Expect(errorCode(err)).To(Equal(organizations.ErrCodeTargetNotFoundException))
+	})
+})
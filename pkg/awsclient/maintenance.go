@@ -0,0 +1,46 @@
+package awsclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+// readOnlyOperationPrefixes prefix the names of the AWS operations that don't change anything
+var readOnlyOperationPrefixes = []string{"Describe", "Get", "Head", "List", "Lookup", "Search", "Simulate", "Validate"}
+
+// maintenanceModeAllowedOperations are the other AWS operations still made in maintenance mode. Assuming roles is
+// needed to read the member accounts, the audit trail keeps being written to CloudWatch Logs, and the AWS
+// notifications keep being consumed from SQS.
+var maintenanceModeAllowedOperations = map[string]bool{
+	"AssumeRole":      true,
+	"CreateLogStream": true,
+	"PutLogEvents":    true,
+	"ReceiveMessage":  true,
+	"DeleteMessage":   true,
+}
+
+// isMutatingOperation checks whether the AWS operation may change something
+func isMutatingOperation(operation string) bool {
+	if maintenanceModeAllowedOperations[operation] {
+		return false
+	}
+	for _, prefix := range readOnlyOperationPrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// maintenanceModeExecutor rejects the mutating AWS operations while the operator is in maintenance mode
+type maintenanceModeExecutor struct{}
+
+// Execute implements Executor
+func (maintenanceModeExecutor) Execute(_ context.Context, r *request.Request) {
+	r.Error = awserr.New(utils.ErrCodeMaintenanceMode, fmt.Sprintf("%s:%s is paused by maintenance mode", r.ClientInfo.ServiceName, r.Operation.Name), nil)
+}
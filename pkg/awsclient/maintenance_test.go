@@ -0,0 +1,39 @@
+package awsclient
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+var _ = Describe("Maintenance mode", func() {
+	handler := executeHandler(context.TODO(), maintenanceModeExecutor{})
+
+	newRequest := func(operation string) *request.Request {
+		return &request.Request{
+			ClientInfo: metadata.ClientInfo{ServiceName: "iam"},
+			Operation:  &request.Operation{Name: operation},
+		}
+	}
+
+	It("Should reject the mutating operations", func() {
+		for _, operation := range []string{"CreateAccount", "DeleteBucket", "CreateAccessKey", "TerminateInstances", "PutUserPolicy"} {
+			r := newRequest(operation)
+			handler(r)
+			Expect(r.Error).To(HaveOccurred(), operation)
+			Expect(utils.IsMaintenanceModeError(r.Error)).To(BeTrue(), operation)
+		}
+	})
+
+	It("Should let the read-only operations through", func() {
+		for _, operation := range []string{"DescribeAccount", "GetUser", "ListAccessKeys", "HeadBucket", "AssumeRole", "PutLogEvents"} {
+			r := newRequest(operation)
+			handler(r)
+			Expect(r.Error).ToNot(HaveOccurred(), operation)
+		}
+	})
+})
@@ -12,13 +12,23 @@ package mock
 import (
 	reflect "reflect"
 
+	accessanalyzer "github.com/aws/aws-sdk-go/service/accessanalyzer"
 	account "github.com/aws/aws-sdk-go/service/account"
+	budgets "github.com/aws/aws-sdk-go/service/budgets"
+	cloudtrail "github.com/aws/aws-sdk-go/service/cloudtrail"
+	cloudwatchlogs "github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	costexplorer "github.com/aws/aws-sdk-go/service/costexplorer"
 	ec2 "github.com/aws/aws-sdk-go/service/ec2"
+	guardduty "github.com/aws/aws-sdk-go/service/guardduty"
+	health "github.com/aws/aws-sdk-go/service/health"
 	iam "github.com/aws/aws-sdk-go/service/iam"
 	organizations "github.com/aws/aws-sdk-go/service/organizations"
 	route53 "github.com/aws/aws-sdk-go/service/route53"
 	s3 "github.com/aws/aws-sdk-go/service/s3"
+	s3control "github.com/aws/aws-sdk-go/service/s3control"
 	servicequotas "github.com/aws/aws-sdk-go/service/servicequotas"
+	sqs "github.com/aws/aws-sdk-go/service/sqs"
+	ssoadmin "github.com/aws/aws-sdk-go/service/ssoadmin"
 	sts "github.com/aws/aws-sdk-go/service/sts"
 	support "github.com/aws/aws-sdk-go/service/support"
 	awsclient "github.com/openshift/aws-account-operator/pkg/awsclient"
@@ -64,6 +74,21 @@ func (mr *MockClientMockRecorder) AssumeRole(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssumeRole", reflect.TypeOf((*MockClient)(nil).AssumeRole), arg0)
 }
 
+// AttachPolicy mocks base method.
+func (m *MockClient) AttachPolicy(arg0 *organizations.AttachPolicyInput) (*organizations.AttachPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachPolicy", arg0)
+	ret0, _ := ret[0].(*organizations.AttachPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachPolicy indicates an expected call of AttachPolicy.
+func (mr *MockClientMockRecorder) AttachPolicy(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachPolicy", reflect.TypeOf((*MockClient)(nil).AttachPolicy), arg0)
+}
+
 // AttachRolePolicy mocks base method.
 func (m *MockClient) AttachRolePolicy(arg0 *iam.AttachRolePolicyInput) (*iam.AttachRolePolicyOutput, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +119,20 @@ func (mr *MockClientMockRecorder) AttachUserPolicy(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachUserPolicy", reflect.TypeOf((*MockClient)(nil).AttachUserPolicy), arg0)
 }
 
+// BatchDeleteBucketObjectVersions mocks base method.
+func (m *MockClient) BatchDeleteBucketObjectVersions(bucketName *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchDeleteBucketObjectVersions", bucketName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchDeleteBucketObjectVersions indicates an expected call of BatchDeleteBucketObjectVersions.
+func (mr *MockClientMockRecorder) BatchDeleteBucketObjectVersions(bucketName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDeleteBucketObjectVersions", reflect.TypeOf((*MockClient)(nil).BatchDeleteBucketObjectVersions), bucketName)
+}
+
 // BatchDeleteBucketObjects mocks base method.
 func (m *MockClient) BatchDeleteBucketObjects(bucketName *string) error {
 	m.ctrl.T.Helper()
@@ -153,6 +192,51 @@ func (mr *MockClientMockRecorder) CreateAccount(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockClient)(nil).CreateAccount), arg0)
 }
 
+// CreateAccountAssignment mocks base method.
+func (m *MockClient) CreateAccountAssignment(arg0 *ssoadmin.CreateAccountAssignmentInput) (*ssoadmin.CreateAccountAssignmentOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountAssignment", arg0)
+	ret0, _ := ret[0].(*ssoadmin.CreateAccountAssignmentOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountAssignment indicates an expected call of CreateAccountAssignment.
+func (mr *MockClientMockRecorder) CreateAccountAssignment(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountAssignment", reflect.TypeOf((*MockClient)(nil).CreateAccountAssignment), arg0)
+}
+
+// CreateBucket mocks base method.
+func (m *MockClient) CreateBucket(arg0 *s3.CreateBucketInput) (*s3.CreateBucketOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBucket", arg0)
+	ret0, _ := ret[0].(*s3.CreateBucketOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBucket indicates an expected call of CreateBucket.
+func (mr *MockClientMockRecorder) CreateBucket(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBucket", reflect.TypeOf((*MockClient)(nil).CreateBucket), arg0)
+}
+
+// CreateBudget mocks base method.
+func (m *MockClient) CreateBudget(arg0 *budgets.CreateBudgetInput) (*budgets.CreateBudgetOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBudget", arg0)
+	ret0, _ := ret[0].(*budgets.CreateBudgetOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBudget indicates an expected call of CreateBudget.
+func (mr *MockClientMockRecorder) CreateBudget(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBudget", reflect.TypeOf((*MockClient)(nil).CreateBudget), arg0)
+}
+
 // CreateCase mocks base method.
 func (m *MockClient) CreateCase(arg0 *support.CreateCaseInput) (*support.CreateCaseOutput, error) {
 	m.ctrl.T.Helper()
@@ -168,6 +252,51 @@ func (mr *MockClientMockRecorder) CreateCase(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCase", reflect.TypeOf((*MockClient)(nil).CreateCase), arg0)
 }
 
+// CreateHostedZone mocks base method.
+func (m *MockClient) CreateHostedZone(arg0 *route53.CreateHostedZoneInput) (*route53.CreateHostedZoneOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHostedZone", arg0)
+	ret0, _ := ret[0].(*route53.CreateHostedZoneOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHostedZone indicates an expected call of CreateHostedZone.
+func (mr *MockClientMockRecorder) CreateHostedZone(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHostedZone", reflect.TypeOf((*MockClient)(nil).CreateHostedZone), arg0)
+}
+
+// CreateLogStream mocks base method.
+func (m *MockClient) CreateLogStream(arg0 *cloudwatchlogs.CreateLogStreamInput) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLogStream", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.CreateLogStreamOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLogStream indicates an expected call of CreateLogStream.
+func (mr *MockClientMockRecorder) CreateLogStream(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLogStream", reflect.TypeOf((*MockClient)(nil).CreateLogStream), arg0)
+}
+
+// CreateMembers mocks base method.
+func (m *MockClient) CreateMembers(arg0 *guardduty.CreateMembersInput) (*guardduty.CreateMembersOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMembers", arg0)
+	ret0, _ := ret[0].(*guardduty.CreateMembersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMembers indicates an expected call of CreateMembers.
+func (mr *MockClientMockRecorder) CreateMembers(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMembers", reflect.TypeOf((*MockClient)(nil).CreateMembers), arg0)
+}
+
 // CreateOrganizationalUnit mocks base method.
 func (m *MockClient) CreateOrganizationalUnit(arg0 *organizations.CreateOrganizationalUnitInput) (*organizations.CreateOrganizationalUnitOutput, error) {
 	m.ctrl.T.Helper()
@@ -213,6 +342,21 @@ func (mr *MockClientMockRecorder) CreateRole(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockClient)(nil).CreateRole), arg0)
 }
 
+// CreateSnapshot mocks base method.
+func (m *MockClient) CreateSnapshot(arg0 *ec2.CreateSnapshotInput) (*ec2.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSnapshot", arg0)
+	ret0, _ := ret[0].(*ec2.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSnapshot indicates an expected call of CreateSnapshot.
+func (mr *MockClientMockRecorder) CreateSnapshot(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshot", reflect.TypeOf((*MockClient)(nil).CreateSnapshot), arg0)
+}
+
 // CreateSubnet mocks base method.
 func (m *MockClient) CreateSubnet(arg0 *ec2.CreateSubnetInput) (*ec2.CreateSubnetOutput, error) {
 	m.ctrl.T.Helper()
@@ -243,6 +387,21 @@ func (mr *MockClientMockRecorder) CreateUser(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockClient)(nil).CreateUser), arg0)
 }
 
+// CreateVolume mocks base method.
+func (m *MockClient) CreateVolume(arg0 *ec2.CreateVolumeInput) (*ec2.Volume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVolume", arg0)
+	ret0, _ := ret[0].(*ec2.Volume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVolume indicates an expected call of CreateVolume.
+func (mr *MockClientMockRecorder) CreateVolume(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolume", reflect.TypeOf((*MockClient)(nil).CreateVolume), arg0)
+}
+
 // CreateVpc mocks base method.
 func (m *MockClient) CreateVpc(arg0 *ec2.CreateVpcInput) (*ec2.CreateVpcOutput, error) {
 	m.ctrl.T.Helper()
@@ -273,6 +432,21 @@ func (mr *MockClientMockRecorder) DeleteAccessKey(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessKey", reflect.TypeOf((*MockClient)(nil).DeleteAccessKey), arg0)
 }
 
+// DeleteAccountAssignment mocks base method.
+func (m *MockClient) DeleteAccountAssignment(arg0 *ssoadmin.DeleteAccountAssignmentInput) (*ssoadmin.DeleteAccountAssignmentOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountAssignment", arg0)
+	ret0, _ := ret[0].(*ssoadmin.DeleteAccountAssignmentOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccountAssignment indicates an expected call of DeleteAccountAssignment.
+func (mr *MockClientMockRecorder) DeleteAccountAssignment(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountAssignment", reflect.TypeOf((*MockClient)(nil).DeleteAccountAssignment), arg0)
+}
+
 // DeleteBucket mocks base method.
 func (m *MockClient) DeleteBucket(arg0 *s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error) {
 	m.ctrl.T.Helper()
@@ -288,6 +462,36 @@ func (mr *MockClientMockRecorder) DeleteBucket(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBucket", reflect.TypeOf((*MockClient)(nil).DeleteBucket), arg0)
 }
 
+// DeleteBucketPolicy mocks base method.
+func (m *MockClient) DeleteBucketPolicy(arg0 *s3.DeleteBucketPolicyInput) (*s3.DeleteBucketPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBucketPolicy", arg0)
+	ret0, _ := ret[0].(*s3.DeleteBucketPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBucketPolicy indicates an expected call of DeleteBucketPolicy.
+func (mr *MockClientMockRecorder) DeleteBucketPolicy(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBucketPolicy", reflect.TypeOf((*MockClient)(nil).DeleteBucketPolicy), arg0)
+}
+
+// DeleteBudget mocks base method.
+func (m *MockClient) DeleteBudget(arg0 *budgets.DeleteBudgetInput) (*budgets.DeleteBudgetOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBudget", arg0)
+	ret0, _ := ret[0].(*budgets.DeleteBudgetOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBudget indicates an expected call of DeleteBudget.
+func (mr *MockClientMockRecorder) DeleteBudget(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBudget", reflect.TypeOf((*MockClient)(nil).DeleteBudget), arg0)
+}
+
 // DeleteHostedZone mocks base method.
 func (m *MockClient) DeleteHostedZone(arg0 *route53.DeleteHostedZoneInput) (*route53.DeleteHostedZoneOutput, error) {
 	m.ctrl.T.Helper()
@@ -303,6 +507,81 @@ func (mr *MockClientMockRecorder) DeleteHostedZone(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHostedZone", reflect.TypeOf((*MockClient)(nil).DeleteHostedZone), arg0)
 }
 
+// DeleteInternetGateway mocks base method.
+func (m *MockClient) DeleteInternetGateway(arg0 *ec2.DeleteInternetGatewayInput) (*ec2.DeleteInternetGatewayOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInternetGateway", arg0)
+	ret0, _ := ret[0].(*ec2.DeleteInternetGatewayOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteInternetGateway indicates an expected call of DeleteInternetGateway.
+func (mr *MockClientMockRecorder) DeleteInternetGateway(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInternetGateway", reflect.TypeOf((*MockClient)(nil).DeleteInternetGateway), arg0)
+}
+
+// DeleteMembers mocks base method.
+func (m *MockClient) DeleteMembers(arg0 *guardduty.DeleteMembersInput) (*guardduty.DeleteMembersOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMembers", arg0)
+	ret0, _ := ret[0].(*guardduty.DeleteMembersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMembers indicates an expected call of DeleteMembers.
+func (mr *MockClientMockRecorder) DeleteMembers(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMembers", reflect.TypeOf((*MockClient)(nil).DeleteMembers), arg0)
+}
+
+// DeleteMessage mocks base method.
+func (m *MockClient) DeleteMessage(arg0 *sqs.DeleteMessageInput) (*sqs.DeleteMessageOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessage", arg0)
+	ret0, _ := ret[0].(*sqs.DeleteMessageOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMessage indicates an expected call of DeleteMessage.
+func (mr *MockClientMockRecorder) DeleteMessage(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockClient)(nil).DeleteMessage), arg0)
+}
+
+// DeleteNatGateway mocks base method.
+func (m *MockClient) DeleteNatGateway(arg0 *ec2.DeleteNatGatewayInput) (*ec2.DeleteNatGatewayOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNatGateway", arg0)
+	ret0, _ := ret[0].(*ec2.DeleteNatGatewayOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteNatGateway indicates an expected call of DeleteNatGateway.
+func (mr *MockClientMockRecorder) DeleteNatGateway(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNatGateway", reflect.TypeOf((*MockClient)(nil).DeleteNatGateway), arg0)
+}
+
+// DeleteNetworkInterface mocks base method.
+func (m *MockClient) DeleteNetworkInterface(arg0 *ec2.DeleteNetworkInterfaceInput) (*ec2.DeleteNetworkInterfaceOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNetworkInterface", arg0)
+	ret0, _ := ret[0].(*ec2.DeleteNetworkInterfaceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteNetworkInterface indicates an expected call of DeleteNetworkInterface.
+func (mr *MockClientMockRecorder) DeleteNetworkInterface(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNetworkInterface", reflect.TypeOf((*MockClient)(nil).DeleteNetworkInterface), arg0)
+}
+
 // DeletePolicy mocks base method.
 func (m *MockClient) DeletePolicy(input *iam.DeletePolicyInput) (*iam.DeletePolicyOutput, error) {
 	m.ctrl.T.Helper()
@@ -363,6 +642,21 @@ func (mr *MockClientMockRecorder) DeleteRolePolicy(input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRolePolicy", reflect.TypeOf((*MockClient)(nil).DeleteRolePolicy), input)
 }
 
+// DeleteSecurityGroup mocks base method.
+func (m *MockClient) DeleteSecurityGroup(arg0 *ec2.DeleteSecurityGroupInput) (*ec2.DeleteSecurityGroupOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSecurityGroup", arg0)
+	ret0, _ := ret[0].(*ec2.DeleteSecurityGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSecurityGroup indicates an expected call of DeleteSecurityGroup.
+func (mr *MockClientMockRecorder) DeleteSecurityGroup(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecurityGroup", reflect.TypeOf((*MockClient)(nil).DeleteSecurityGroup), arg0)
+}
+
 // DeleteSnapshot mocks base method.
 func (m *MockClient) DeleteSnapshot(arg0 *ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error) {
 	m.ctrl.T.Helper()
@@ -468,6 +762,96 @@ func (mr *MockClientMockRecorder) DeleteVpcEndpointServiceConfigurations(arg0 an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVpcEndpointServiceConfigurations", reflect.TypeOf((*MockClient)(nil).DeleteVpcEndpointServiceConfigurations), arg0)
 }
 
+// DescribeAccount mocks base method.
+func (m *MockClient) DescribeAccount(arg0 *organizations.DescribeAccountInput) (*organizations.DescribeAccountOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeAccount", arg0)
+	ret0, _ := ret[0].(*organizations.DescribeAccountOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAccount indicates an expected call of DescribeAccount.
+func (mr *MockClientMockRecorder) DescribeAccount(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAccount", reflect.TypeOf((*MockClient)(nil).DescribeAccount), arg0)
+}
+
+// DescribeAccountAssignmentCreationStatus mocks base method.
+func (m *MockClient) DescribeAccountAssignmentCreationStatus(arg0 *ssoadmin.DescribeAccountAssignmentCreationStatusInput) (*ssoadmin.DescribeAccountAssignmentCreationStatusOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeAccountAssignmentCreationStatus", arg0)
+	ret0, _ := ret[0].(*ssoadmin.DescribeAccountAssignmentCreationStatusOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAccountAssignmentCreationStatus indicates an expected call of DescribeAccountAssignmentCreationStatus.
+func (mr *MockClientMockRecorder) DescribeAccountAssignmentCreationStatus(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAccountAssignmentCreationStatus", reflect.TypeOf((*MockClient)(nil).DescribeAccountAssignmentCreationStatus), arg0)
+}
+
+// DescribeAddresses mocks base method.
+func (m *MockClient) DescribeAddresses(arg0 *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeAddresses", arg0)
+	ret0, _ := ret[0].(*ec2.DescribeAddressesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAddresses indicates an expected call of DescribeAddresses.
+func (mr *MockClientMockRecorder) DescribeAddresses(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAddresses", reflect.TypeOf((*MockClient)(nil).DescribeAddresses), arg0)
+}
+
+// DescribeAffectedAccountsForOrganization mocks base method.
+func (m *MockClient) DescribeAffectedAccountsForOrganization(arg0 *health.DescribeAffectedAccountsForOrganizationInput) (*health.DescribeAffectedAccountsForOrganizationOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeAffectedAccountsForOrganization", arg0)
+	ret0, _ := ret[0].(*health.DescribeAffectedAccountsForOrganizationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAffectedAccountsForOrganization indicates an expected call of DescribeAffectedAccountsForOrganization.
+func (mr *MockClientMockRecorder) DescribeAffectedAccountsForOrganization(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAffectedAccountsForOrganization", reflect.TypeOf((*MockClient)(nil).DescribeAffectedAccountsForOrganization), arg0)
+}
+
+// DescribeAvailabilityZones mocks base method.
+func (m *MockClient) DescribeAvailabilityZones(arg0 *ec2.DescribeAvailabilityZonesInput) (*ec2.DescribeAvailabilityZonesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeAvailabilityZones", arg0)
+	ret0, _ := ret[0].(*ec2.DescribeAvailabilityZonesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAvailabilityZones indicates an expected call of DescribeAvailabilityZones.
+func (mr *MockClientMockRecorder) DescribeAvailabilityZones(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAvailabilityZones", reflect.TypeOf((*MockClient)(nil).DescribeAvailabilityZones), arg0)
+}
+
+// DescribeBudget mocks base method.
+func (m *MockClient) DescribeBudget(arg0 *budgets.DescribeBudgetInput) (*budgets.DescribeBudgetOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeBudget", arg0)
+	ret0, _ := ret[0].(*budgets.DescribeBudgetOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeBudget indicates an expected call of DescribeBudget.
+func (mr *MockClientMockRecorder) DescribeBudget(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeBudget", reflect.TypeOf((*MockClient)(nil).DescribeBudget), arg0)
+}
+
 // DescribeCases mocks base method.
 func (m *MockClient) DescribeCases(arg0 *support.DescribeCasesInput) (*support.DescribeCasesOutput, error) {
 	m.ctrl.T.Helper()
@@ -498,6 +882,21 @@ func (mr *MockClientMockRecorder) DescribeCreateAccountStatus(arg0 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCreateAccountStatus", reflect.TypeOf((*MockClient)(nil).DescribeCreateAccountStatus), arg0)
 }
 
+// DescribeEventsForOrganization mocks base method.
+func (m *MockClient) DescribeEventsForOrganization(arg0 *health.DescribeEventsForOrganizationInput) (*health.DescribeEventsForOrganizationOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeEventsForOrganization", arg0)
+	ret0, _ := ret[0].(*health.DescribeEventsForOrganizationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeEventsForOrganization indicates an expected call of DescribeEventsForOrganization.
+func (mr *MockClientMockRecorder) DescribeEventsForOrganization(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeEventsForOrganization", reflect.TypeOf((*MockClient)(nil).DescribeEventsForOrganization), arg0)
+}
+
 // DescribeImages mocks base method.
 func (m *MockClient) DescribeImages(arg0 *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error) {
 	m.ctrl.T.Helper()
@@ -558,6 +957,81 @@ func (mr *MockClientMockRecorder) DescribeInstances(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeInstances", reflect.TypeOf((*MockClient)(nil).DescribeInstances), arg0)
 }
 
+// DescribeInternetGateways mocks base method.
+func (m *MockClient) DescribeInternetGateways(arg0 *ec2.DescribeInternetGatewaysInput) (*ec2.DescribeInternetGatewaysOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeInternetGateways", arg0)
+	ret0, _ := ret[0].(*ec2.DescribeInternetGatewaysOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeInternetGateways indicates an expected call of DescribeInternetGateways.
+func (mr *MockClientMockRecorder) DescribeInternetGateways(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeInternetGateways", reflect.TypeOf((*MockClient)(nil).DescribeInternetGateways), arg0)
+}
+
+// DescribeLockedSnapshots mocks base method.
+func (m *MockClient) DescribeLockedSnapshots(arg0 *ec2.DescribeLockedSnapshotsInput) (*ec2.DescribeLockedSnapshotsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeLockedSnapshots", arg0)
+	ret0, _ := ret[0].(*ec2.DescribeLockedSnapshotsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeLockedSnapshots indicates an expected call of DescribeLockedSnapshots.
+func (mr *MockClientMockRecorder) DescribeLockedSnapshots(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLockedSnapshots", reflect.TypeOf((*MockClient)(nil).DescribeLockedSnapshots), arg0)
+}
+
+// DescribeNatGateways mocks base method.
+func (m *MockClient) DescribeNatGateways(arg0 *ec2.DescribeNatGatewaysInput) (*ec2.DescribeNatGatewaysOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeNatGateways", arg0)
+	ret0, _ := ret[0].(*ec2.DescribeNatGatewaysOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeNatGateways indicates an expected call of DescribeNatGateways.
+func (mr *MockClientMockRecorder) DescribeNatGateways(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeNatGateways", reflect.TypeOf((*MockClient)(nil).DescribeNatGateways), arg0)
+}
+
+// DescribeNetworkInterfaces mocks base method.
+func (m *MockClient) DescribeNetworkInterfaces(arg0 *ec2.DescribeNetworkInterfacesInput) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeNetworkInterfaces", arg0)
+	ret0, _ := ret[0].(*ec2.DescribeNetworkInterfacesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeNetworkInterfaces indicates an expected call of DescribeNetworkInterfaces.
+func (mr *MockClientMockRecorder) DescribeNetworkInterfaces(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeNetworkInterfaces", reflect.TypeOf((*MockClient)(nil).DescribeNetworkInterfaces), arg0)
+}
+
+// DescribeOrganization mocks base method.
+func (m *MockClient) DescribeOrganization(arg0 *organizations.DescribeOrganizationInput) (*organizations.DescribeOrganizationOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeOrganization", arg0)
+	ret0, _ := ret[0].(*organizations.DescribeOrganizationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeOrganization indicates an expected call of DescribeOrganization.
+func (mr *MockClientMockRecorder) DescribeOrganization(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeOrganization", reflect.TypeOf((*MockClient)(nil).DescribeOrganization), arg0)
+}
+
 // DescribeRegions mocks base method.
 func (m *MockClient) DescribeRegions(input *ec2.DescribeRegionsInput) (*ec2.DescribeRegionsOutput, error) {
 	m.ctrl.T.Helper()
@@ -573,6 +1047,21 @@ func (mr *MockClientMockRecorder) DescribeRegions(input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeRegions", reflect.TypeOf((*MockClient)(nil).DescribeRegions), input)
 }
 
+// DescribeSecurityGroups mocks base method.
+func (m *MockClient) DescribeSecurityGroups(arg0 *ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeSecurityGroups", arg0)
+	ret0, _ := ret[0].(*ec2.DescribeSecurityGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeSecurityGroups indicates an expected call of DescribeSecurityGroups.
+func (mr *MockClientMockRecorder) DescribeSecurityGroups(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSecurityGroups", reflect.TypeOf((*MockClient)(nil).DescribeSecurityGroups), arg0)
+}
+
 // DescribeSnapshots mocks base method.
 func (m *MockClient) DescribeSnapshots(arg0 *ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error) {
 	m.ctrl.T.Helper()
@@ -603,6 +1092,36 @@ func (mr *MockClientMockRecorder) DescribeSubnets(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSubnets", reflect.TypeOf((*MockClient)(nil).DescribeSubnets), arg0)
 }
 
+// DescribeTrails mocks base method.
+func (m *MockClient) DescribeTrails(arg0 *cloudtrail.DescribeTrailsInput) (*cloudtrail.DescribeTrailsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeTrails", arg0)
+	ret0, _ := ret[0].(*cloudtrail.DescribeTrailsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeTrails indicates an expected call of DescribeTrails.
+func (mr *MockClientMockRecorder) DescribeTrails(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeTrails", reflect.TypeOf((*MockClient)(nil).DescribeTrails), arg0)
+}
+
+// DescribeTrustedAdvisorCheckResult mocks base method.
+func (m *MockClient) DescribeTrustedAdvisorCheckResult(arg0 *support.DescribeTrustedAdvisorCheckResultInput) (*support.DescribeTrustedAdvisorCheckResultOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeTrustedAdvisorCheckResult", arg0)
+	ret0, _ := ret[0].(*support.DescribeTrustedAdvisorCheckResultOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeTrustedAdvisorCheckResult indicates an expected call of DescribeTrustedAdvisorCheckResult.
+func (mr *MockClientMockRecorder) DescribeTrustedAdvisorCheckResult(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeTrustedAdvisorCheckResult", reflect.TypeOf((*MockClient)(nil).DescribeTrustedAdvisorCheckResult), arg0)
+}
+
 // DescribeVolumes mocks base method.
 func (m *MockClient) DescribeVolumes(arg0 *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
 	m.ctrl.T.Helper()
@@ -648,6 +1167,51 @@ func (mr *MockClientMockRecorder) DescribeVpcs(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeVpcs", reflect.TypeOf((*MockClient)(nil).DescribeVpcs), arg0)
 }
 
+// DetachInternetGateway mocks base method.
+func (m *MockClient) DetachInternetGateway(arg0 *ec2.DetachInternetGatewayInput) (*ec2.DetachInternetGatewayOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachInternetGateway", arg0)
+	ret0, _ := ret[0].(*ec2.DetachInternetGatewayOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachInternetGateway indicates an expected call of DetachInternetGateway.
+func (mr *MockClientMockRecorder) DetachInternetGateway(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachInternetGateway", reflect.TypeOf((*MockClient)(nil).DetachInternetGateway), arg0)
+}
+
+// DetachNetworkInterface mocks base method.
+func (m *MockClient) DetachNetworkInterface(arg0 *ec2.DetachNetworkInterfaceInput) (*ec2.DetachNetworkInterfaceOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachNetworkInterface", arg0)
+	ret0, _ := ret[0].(*ec2.DetachNetworkInterfaceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachNetworkInterface indicates an expected call of DetachNetworkInterface.
+func (mr *MockClientMockRecorder) DetachNetworkInterface(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachNetworkInterface", reflect.TypeOf((*MockClient)(nil).DetachNetworkInterface), arg0)
+}
+
+// DetachPolicy mocks base method.
+func (m *MockClient) DetachPolicy(arg0 *organizations.DetachPolicyInput) (*organizations.DetachPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachPolicy", arg0)
+	ret0, _ := ret[0].(*organizations.DetachPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachPolicy indicates an expected call of DetachPolicy.
+func (mr *MockClientMockRecorder) DetachPolicy(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachPolicy", reflect.TypeOf((*MockClient)(nil).DetachPolicy), arg0)
+}
+
 // DetachRolePolicy mocks base method.
 func (m *MockClient) DetachRolePolicy(arg0 *iam.DetachRolePolicyInput) (*iam.DetachRolePolicyOutput, error) {
 	m.ctrl.T.Helper()
@@ -678,6 +1242,51 @@ func (mr *MockClientMockRecorder) DetachUserPolicy(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachUserPolicy", reflect.TypeOf((*MockClient)(nil).DetachUserPolicy), arg0)
 }
 
+// DetachVolume mocks base method.
+func (m *MockClient) DetachVolume(arg0 *ec2.DetachVolumeInput) (*ec2.VolumeAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachVolume", arg0)
+	ret0, _ := ret[0].(*ec2.VolumeAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachVolume indicates an expected call of DetachVolume.
+func (mr *MockClientMockRecorder) DetachVolume(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachVolume", reflect.TypeOf((*MockClient)(nil).DetachVolume), arg0)
+}
+
+// DisassociateMembers mocks base method.
+func (m *MockClient) DisassociateMembers(arg0 *guardduty.DisassociateMembersInput) (*guardduty.DisassociateMembersOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisassociateMembers", arg0)
+	ret0, _ := ret[0].(*guardduty.DisassociateMembersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisassociateMembers indicates an expected call of DisassociateMembers.
+func (mr *MockClientMockRecorder) DisassociateMembers(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisassociateMembers", reflect.TypeOf((*MockClient)(nil).DisassociateMembers), arg0)
+}
+
+// EnableEbsEncryptionByDefault mocks base method.
+func (m *MockClient) EnableEbsEncryptionByDefault(arg0 *ec2.EnableEbsEncryptionByDefaultInput) (*ec2.EnableEbsEncryptionByDefaultOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableEbsEncryptionByDefault", arg0)
+	ret0, _ := ret[0].(*ec2.EnableEbsEncryptionByDefaultOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableEbsEncryptionByDefault indicates an expected call of EnableEbsEncryptionByDefault.
+func (mr *MockClientMockRecorder) EnableEbsEncryptionByDefault(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableEbsEncryptionByDefault", reflect.TypeOf((*MockClient)(nil).EnableEbsEncryptionByDefault), arg0)
+}
+
 // EnableRegion mocks base method.
 func (m *MockClient) EnableRegion(arg0 *account.EnableRegionInput) (*account.EnableRegionOutput, error) {
 	m.ctrl.T.Helper()
@@ -693,6 +1302,36 @@ func (mr *MockClientMockRecorder) EnableRegion(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableRegion", reflect.TypeOf((*MockClient)(nil).EnableRegion), arg0)
 }
 
+// GetAccountSummary mocks base method.
+func (m *MockClient) GetAccountSummary(arg0 *iam.GetAccountSummaryInput) (*iam.GetAccountSummaryOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountSummary", arg0)
+	ret0, _ := ret[0].(*iam.GetAccountSummaryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountSummary indicates an expected call of GetAccountSummary.
+func (mr *MockClientMockRecorder) GetAccountSummary(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountSummary", reflect.TypeOf((*MockClient)(nil).GetAccountSummary), arg0)
+}
+
+// GetBucketPolicy mocks base method.
+func (m *MockClient) GetBucketPolicy(arg0 *s3.GetBucketPolicyInput) (*s3.GetBucketPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBucketPolicy", arg0)
+	ret0, _ := ret[0].(*s3.GetBucketPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBucketPolicy indicates an expected call of GetBucketPolicy.
+func (mr *MockClientMockRecorder) GetBucketPolicy(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketPolicy", reflect.TypeOf((*MockClient)(nil).GetBucketPolicy), arg0)
+}
+
 // GetCallerIdentity mocks base method.
 func (m *MockClient) GetCallerIdentity(arg0 *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
 	m.ctrl.T.Helper()
@@ -708,6 +1347,21 @@ func (mr *MockClientMockRecorder) GetCallerIdentity(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCallerIdentity", reflect.TypeOf((*MockClient)(nil).GetCallerIdentity), arg0)
 }
 
+// GetCostAndUsage mocks base method.
+func (m *MockClient) GetCostAndUsage(arg0 *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCostAndUsage", arg0)
+	ret0, _ := ret[0].(*costexplorer.GetCostAndUsageOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCostAndUsage indicates an expected call of GetCostAndUsage.
+func (mr *MockClientMockRecorder) GetCostAndUsage(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCostAndUsage", reflect.TypeOf((*MockClient)(nil).GetCostAndUsage), arg0)
+}
+
 // GetFederationToken mocks base method.
 func (m *MockClient) GetFederationToken(arg0 *sts.GetFederationTokenInput) (*sts.GetFederationTokenOutput, error) {
 	m.ctrl.T.Helper()
@@ -723,6 +1377,36 @@ func (mr *MockClientMockRecorder) GetFederationToken(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFederationToken", reflect.TypeOf((*MockClient)(nil).GetFederationToken), arg0)
 }
 
+// GetHostedZone mocks base method.
+func (m *MockClient) GetHostedZone(arg0 *route53.GetHostedZoneInput) (*route53.GetHostedZoneOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHostedZone", arg0)
+	ret0, _ := ret[0].(*route53.GetHostedZoneOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHostedZone indicates an expected call of GetHostedZone.
+func (mr *MockClientMockRecorder) GetHostedZone(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHostedZone", reflect.TypeOf((*MockClient)(nil).GetHostedZone), arg0)
+}
+
+// GetObjectLockConfiguration mocks base method.
+func (m *MockClient) GetObjectLockConfiguration(arg0 *s3.GetObjectLockConfigurationInput) (*s3.GetObjectLockConfigurationOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObjectLockConfiguration", arg0)
+	ret0, _ := ret[0].(*s3.GetObjectLockConfigurationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObjectLockConfiguration indicates an expected call of GetObjectLockConfiguration.
+func (mr *MockClientMockRecorder) GetObjectLockConfiguration(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectLockConfiguration", reflect.TypeOf((*MockClient)(nil).GetObjectLockConfiguration), arg0)
+}
+
 // GetPolicy mocks base method.
 func (m *MockClient) GetPolicy(input *iam.GetPolicyInput) (*iam.GetPolicyOutput, error) {
 	m.ctrl.T.Helper()
@@ -798,6 +1482,21 @@ func (mr *MockClientMockRecorder) GetServiceQuota(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceQuota", reflect.TypeOf((*MockClient)(nil).GetServiceQuota), arg0)
 }
 
+// GetTrailStatus mocks base method.
+func (m *MockClient) GetTrailStatus(arg0 *cloudtrail.GetTrailStatusInput) (*cloudtrail.GetTrailStatusOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrailStatus", arg0)
+	ret0, _ := ret[0].(*cloudtrail.GetTrailStatusOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrailStatus indicates an expected call of GetTrailStatus.
+func (mr *MockClientMockRecorder) GetTrailStatus(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrailStatus", reflect.TypeOf((*MockClient)(nil).GetTrailStatus), arg0)
+}
+
 // GetUser mocks base method.
 func (m *MockClient) GetUser(arg0 *iam.GetUserInput) (*iam.GetUserOutput, error) {
 	m.ctrl.T.Helper()
@@ -813,6 +1512,21 @@ func (mr *MockClientMockRecorder) GetUser(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockClient)(nil).GetUser), arg0)
 }
 
+// HeadBucket mocks base method.
+func (m *MockClient) HeadBucket(arg0 *s3.HeadBucketInput) (*s3.HeadBucketOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeadBucket", arg0)
+	ret0, _ := ret[0].(*s3.HeadBucketOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeadBucket indicates an expected call of HeadBucket.
+func (mr *MockClientMockRecorder) HeadBucket(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeadBucket", reflect.TypeOf((*MockClient)(nil).HeadBucket), arg0)
+}
+
 // ListAccessKeys mocks base method.
 func (m *MockClient) ListAccessKeys(arg0 *iam.ListAccessKeysInput) (*iam.ListAccessKeysOutput, error) {
 	m.ctrl.T.Helper()
@@ -828,6 +1542,21 @@ func (mr *MockClientMockRecorder) ListAccessKeys(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccessKeys", reflect.TypeOf((*MockClient)(nil).ListAccessKeys), arg0)
 }
 
+// ListAccountAssignments mocks base method.
+func (m *MockClient) ListAccountAssignments(arg0 *ssoadmin.ListAccountAssignmentsInput) (*ssoadmin.ListAccountAssignmentsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountAssignments", arg0)
+	ret0, _ := ret[0].(*ssoadmin.ListAccountAssignmentsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountAssignments indicates an expected call of ListAccountAssignments.
+func (mr *MockClientMockRecorder) ListAccountAssignments(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountAssignments", reflect.TypeOf((*MockClient)(nil).ListAccountAssignments), arg0)
+}
+
 // ListAccounts mocks base method.
 func (m *MockClient) ListAccounts(arg0 *organizations.ListAccountsInput) (*organizations.ListAccountsOutput, error) {
 	m.ctrl.T.Helper()
@@ -843,6 +1572,21 @@ func (mr *MockClientMockRecorder) ListAccounts(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockClient)(nil).ListAccounts), arg0)
 }
 
+// ListAccountsForParent mocks base method.
+func (m *MockClient) ListAccountsForParent(arg0 *organizations.ListAccountsForParentInput) (*organizations.ListAccountsForParentOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountsForParent", arg0)
+	ret0, _ := ret[0].(*organizations.ListAccountsForParentOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountsForParent indicates an expected call of ListAccountsForParent.
+func (mr *MockClientMockRecorder) ListAccountsForParent(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountsForParent", reflect.TypeOf((*MockClient)(nil).ListAccountsForParent), arg0)
+}
+
 // ListAttachedRolePolicies mocks base method.
 func (m *MockClient) ListAttachedRolePolicies(arg0 *iam.ListAttachedRolePoliciesInput) (*iam.ListAttachedRolePoliciesOutput, error) {
 	m.ctrl.T.Helper()
@@ -903,6 +1647,36 @@ func (mr *MockClientMockRecorder) ListChildren(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChildren", reflect.TypeOf((*MockClient)(nil).ListChildren), arg0)
 }
 
+// ListCostAllocationTags mocks base method.
+func (m *MockClient) ListCostAllocationTags(arg0 *costexplorer.ListCostAllocationTagsInput) (*costexplorer.ListCostAllocationTagsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCostAllocationTags", arg0)
+	ret0, _ := ret[0].(*costexplorer.ListCostAllocationTagsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCostAllocationTags indicates an expected call of ListCostAllocationTags.
+func (mr *MockClientMockRecorder) ListCostAllocationTags(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCostAllocationTags", reflect.TypeOf((*MockClient)(nil).ListCostAllocationTags), arg0)
+}
+
+// ListDetectors mocks base method.
+func (m *MockClient) ListDetectors(arg0 *guardduty.ListDetectorsInput) (*guardduty.ListDetectorsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDetectors", arg0)
+	ret0, _ := ret[0].(*guardduty.ListDetectorsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDetectors indicates an expected call of ListDetectors.
+func (mr *MockClientMockRecorder) ListDetectors(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDetectors", reflect.TypeOf((*MockClient)(nil).ListDetectors), arg0)
+}
+
 // ListHostedZones mocks base method.
 func (m *MockClient) ListHostedZones(arg0 *route53.ListHostedZonesInput) (*route53.ListHostedZonesOutput, error) {
 	m.ctrl.T.Helper()
@@ -918,6 +1692,21 @@ func (mr *MockClientMockRecorder) ListHostedZones(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHostedZones", reflect.TypeOf((*MockClient)(nil).ListHostedZones), arg0)
 }
 
+// ListObjectVersions mocks base method.
+func (m *MockClient) ListObjectVersions(arg0 *s3.ListObjectVersionsInput) (*s3.ListObjectVersionsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListObjectVersions", arg0)
+	ret0, _ := ret[0].(*s3.ListObjectVersionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListObjectVersions indicates an expected call of ListObjectVersions.
+func (mr *MockClientMockRecorder) ListObjectVersions(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectVersions", reflect.TypeOf((*MockClient)(nil).ListObjectVersions), arg0)
+}
+
 // ListObjectsV2 mocks base method.
 func (m *MockClient) ListObjectsV2(arg0 *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
 	m.ctrl.T.Helper()
@@ -978,6 +1767,21 @@ func (mr *MockClientMockRecorder) ListPolicies(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPolicies", reflect.TypeOf((*MockClient)(nil).ListPolicies), arg0)
 }
 
+// ListPoliciesForTarget mocks base method.
+func (m *MockClient) ListPoliciesForTarget(arg0 *organizations.ListPoliciesForTargetInput) (*organizations.ListPoliciesForTargetOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPoliciesForTarget", arg0)
+	ret0, _ := ret[0].(*organizations.ListPoliciesForTargetOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPoliciesForTarget indicates an expected call of ListPoliciesForTarget.
+func (mr *MockClientMockRecorder) ListPoliciesForTarget(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPoliciesForTarget", reflect.TypeOf((*MockClient)(nil).ListPoliciesForTarget), arg0)
+}
+
 // ListPolicyVersions mocks base method.
 func (m *MockClient) ListPolicyVersions(input *iam.ListPolicyVersionsInput) (*iam.ListPolicyVersionsOutput, error) {
 	m.ctrl.T.Helper()
@@ -1157,6 +1961,81 @@ func (mr *MockClientMockRecorder) MoveAccount(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveAccount", reflect.TypeOf((*MockClient)(nil).MoveAccount), arg0)
 }
 
+// PutBucketVersioning mocks base method.
+func (m *MockClient) PutBucketVersioning(arg0 *s3.PutBucketVersioningInput) (*s3.PutBucketVersioningOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutBucketVersioning", arg0)
+	ret0, _ := ret[0].(*s3.PutBucketVersioningOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutBucketVersioning indicates an expected call of PutBucketVersioning.
+func (mr *MockClientMockRecorder) PutBucketVersioning(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutBucketVersioning", reflect.TypeOf((*MockClient)(nil).PutBucketVersioning), arg0)
+}
+
+// PutLogEvents mocks base method.
+func (m *MockClient) PutLogEvents(arg0 *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutLogEvents", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutLogEventsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutLogEvents indicates an expected call of PutLogEvents.
+func (mr *MockClientMockRecorder) PutLogEvents(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutLogEvents", reflect.TypeOf((*MockClient)(nil).PutLogEvents), arg0)
+}
+
+// PutObject mocks base method.
+func (m *MockClient) PutObject(arg0 *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutObject", arg0)
+	ret0, _ := ret[0].(*s3.PutObjectOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutObject indicates an expected call of PutObject.
+func (mr *MockClientMockRecorder) PutObject(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutObject", reflect.TypeOf((*MockClient)(nil).PutObject), arg0)
+}
+
+// PutPublicAccessBlock mocks base method.
+func (m *MockClient) PutPublicAccessBlock(arg0 *s3control.PutPublicAccessBlockInput) (*s3control.PutPublicAccessBlockOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutPublicAccessBlock", arg0)
+	ret0, _ := ret[0].(*s3control.PutPublicAccessBlockOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutPublicAccessBlock indicates an expected call of PutPublicAccessBlock.
+func (mr *MockClientMockRecorder) PutPublicAccessBlock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutPublicAccessBlock", reflect.TypeOf((*MockClient)(nil).PutPublicAccessBlock), arg0)
+}
+
+// PutRolePermissionsBoundary mocks base method.
+func (m *MockClient) PutRolePermissionsBoundary(arg0 *iam.PutRolePermissionsBoundaryInput) (*iam.PutRolePermissionsBoundaryOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutRolePermissionsBoundary", arg0)
+	ret0, _ := ret[0].(*iam.PutRolePermissionsBoundaryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutRolePermissionsBoundary indicates an expected call of PutRolePermissionsBoundary.
+func (mr *MockClientMockRecorder) PutRolePermissionsBoundary(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutRolePermissionsBoundary", reflect.TypeOf((*MockClient)(nil).PutRolePermissionsBoundary), arg0)
+}
+
 // PutRolePolicy mocks base method.
 func (m *MockClient) PutRolePolicy(input *iam.PutRolePolicyInput) (*iam.PutRolePolicyOutput, error) {
 	m.ctrl.T.Helper()
@@ -1172,6 +2051,21 @@ func (mr *MockClientMockRecorder) PutRolePolicy(input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutRolePolicy", reflect.TypeOf((*MockClient)(nil).PutRolePolicy), input)
 }
 
+// PutUserPermissionsBoundary mocks base method.
+func (m *MockClient) PutUserPermissionsBoundary(arg0 *iam.PutUserPermissionsBoundaryInput) (*iam.PutUserPermissionsBoundaryOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutUserPermissionsBoundary", arg0)
+	ret0, _ := ret[0].(*iam.PutUserPermissionsBoundaryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutUserPermissionsBoundary indicates an expected call of PutUserPermissionsBoundary.
+func (mr *MockClientMockRecorder) PutUserPermissionsBoundary(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutUserPermissionsBoundary", reflect.TypeOf((*MockClient)(nil).PutUserPermissionsBoundary), arg0)
+}
+
 // PutUserPolicy mocks base method.
 func (m *MockClient) PutUserPolicy(arg0 *iam.PutUserPolicyInput) (*iam.PutUserPolicyOutput, error) {
 	m.ctrl.T.Helper()
@@ -1187,6 +2081,51 @@ func (mr *MockClientMockRecorder) PutUserPolicy(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutUserPolicy", reflect.TypeOf((*MockClient)(nil).PutUserPolicy), arg0)
 }
 
+// ReceiveMessage mocks base method.
+func (m *MockClient) ReceiveMessage(arg0 *sqs.ReceiveMessageInput) (*sqs.ReceiveMessageOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReceiveMessage", arg0)
+	ret0, _ := ret[0].(*sqs.ReceiveMessageOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReceiveMessage indicates an expected call of ReceiveMessage.
+func (mr *MockClientMockRecorder) ReceiveMessage(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveMessage", reflect.TypeOf((*MockClient)(nil).ReceiveMessage), arg0)
+}
+
+// RefreshTrustedAdvisorCheck mocks base method.
+func (m *MockClient) RefreshTrustedAdvisorCheck(arg0 *support.RefreshTrustedAdvisorCheckInput) (*support.RefreshTrustedAdvisorCheckOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTrustedAdvisorCheck", arg0)
+	ret0, _ := ret[0].(*support.RefreshTrustedAdvisorCheckOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshTrustedAdvisorCheck indicates an expected call of RefreshTrustedAdvisorCheck.
+func (mr *MockClientMockRecorder) RefreshTrustedAdvisorCheck(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTrustedAdvisorCheck", reflect.TypeOf((*MockClient)(nil).RefreshTrustedAdvisorCheck), arg0)
+}
+
+// ReleaseAddress mocks base method.
+func (m *MockClient) ReleaseAddress(arg0 *ec2.ReleaseAddressInput) (*ec2.ReleaseAddressOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseAddress", arg0)
+	ret0, _ := ret[0].(*ec2.ReleaseAddressOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseAddress indicates an expected call of ReleaseAddress.
+func (mr *MockClientMockRecorder) ReleaseAddress(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseAddress", reflect.TypeOf((*MockClient)(nil).ReleaseAddress), arg0)
+}
+
 // RequestServiceQuotaIncrease mocks base method.
 func (m *MockClient) RequestServiceQuotaIncrease(arg0 *servicequotas.RequestServiceQuotaIncreaseInput) (*servicequotas.RequestServiceQuotaIncreaseOutput, error) {
 	m.ctrl.T.Helper()
@@ -1202,6 +2141,51 @@ func (mr *MockClientMockRecorder) RequestServiceQuotaIncrease(arg0 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestServiceQuotaIncrease", reflect.TypeOf((*MockClient)(nil).RequestServiceQuotaIncrease), arg0)
 }
 
+// ResetSnapshotAttribute mocks base method.
+func (m *MockClient) ResetSnapshotAttribute(arg0 *ec2.ResetSnapshotAttributeInput) (*ec2.ResetSnapshotAttributeOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetSnapshotAttribute", arg0)
+	ret0, _ := ret[0].(*ec2.ResetSnapshotAttributeOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetSnapshotAttribute indicates an expected call of ResetSnapshotAttribute.
+func (mr *MockClientMockRecorder) ResetSnapshotAttribute(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetSnapshotAttribute", reflect.TypeOf((*MockClient)(nil).ResetSnapshotAttribute), arg0)
+}
+
+// RevokeSecurityGroupEgress mocks base method.
+func (m *MockClient) RevokeSecurityGroupEgress(arg0 *ec2.RevokeSecurityGroupEgressInput) (*ec2.RevokeSecurityGroupEgressOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSecurityGroupEgress", arg0)
+	ret0, _ := ret[0].(*ec2.RevokeSecurityGroupEgressOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSecurityGroupEgress indicates an expected call of RevokeSecurityGroupEgress.
+func (mr *MockClientMockRecorder) RevokeSecurityGroupEgress(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSecurityGroupEgress", reflect.TypeOf((*MockClient)(nil).RevokeSecurityGroupEgress), arg0)
+}
+
+// RevokeSecurityGroupIngress mocks base method.
+func (m *MockClient) RevokeSecurityGroupIngress(arg0 *ec2.RevokeSecurityGroupIngressInput) (*ec2.RevokeSecurityGroupIngressOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSecurityGroupIngress", arg0)
+	ret0, _ := ret[0].(*ec2.RevokeSecurityGroupIngressOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSecurityGroupIngress indicates an expected call of RevokeSecurityGroupIngress.
+func (mr *MockClientMockRecorder) RevokeSecurityGroupIngress(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSecurityGroupIngress", reflect.TypeOf((*MockClient)(nil).RevokeSecurityGroupIngress), arg0)
+}
+
 // RunInstances mocks base method.
 func (m *MockClient) RunInstances(arg0 *ec2.RunInstancesInput) (*ec2.Reservation, error) {
 	m.ctrl.T.Helper()
@@ -1217,6 +2201,36 @@ func (mr *MockClientMockRecorder) RunInstances(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunInstances", reflect.TypeOf((*MockClient)(nil).RunInstances), arg0)
 }
 
+// StartLogging mocks base method.
+func (m *MockClient) StartLogging(arg0 *cloudtrail.StartLoggingInput) (*cloudtrail.StartLoggingOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartLogging", arg0)
+	ret0, _ := ret[0].(*cloudtrail.StartLoggingOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartLogging indicates an expected call of StartLogging.
+func (mr *MockClientMockRecorder) StartLogging(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartLogging", reflect.TypeOf((*MockClient)(nil).StartLogging), arg0)
+}
+
+// StopMonitoringMembers mocks base method.
+func (m *MockClient) StopMonitoringMembers(arg0 *guardduty.StopMonitoringMembersInput) (*guardduty.StopMonitoringMembersOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopMonitoringMembers", arg0)
+	ret0, _ := ret[0].(*guardduty.StopMonitoringMembersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopMonitoringMembers indicates an expected call of StopMonitoringMembers.
+func (mr *MockClientMockRecorder) StopMonitoringMembers(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopMonitoringMembers", reflect.TypeOf((*MockClient)(nil).StopMonitoringMembers), arg0)
+}
+
 // TagResource mocks base method.
 func (m *MockClient) TagResource(arg0 *organizations.TagResourceInput) (*organizations.TagResourceOutput, error) {
 	m.ctrl.T.Helper()
@@ -1232,6 +2246,36 @@ func (mr *MockClientMockRecorder) TagResource(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResource", reflect.TypeOf((*MockClient)(nil).TagResource), arg0)
 }
 
+// TagRole mocks base method.
+func (m *MockClient) TagRole(arg0 *iam.TagRoleInput) (*iam.TagRoleOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagRole", arg0)
+	ret0, _ := ret[0].(*iam.TagRoleOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagRole indicates an expected call of TagRole.
+func (mr *MockClientMockRecorder) TagRole(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagRole", reflect.TypeOf((*MockClient)(nil).TagRole), arg0)
+}
+
+// TagUser mocks base method.
+func (m *MockClient) TagUser(arg0 *iam.TagUserInput) (*iam.TagUserOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagUser", arg0)
+	ret0, _ := ret[0].(*iam.TagUserOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagUser indicates an expected call of TagUser.
+func (mr *MockClientMockRecorder) TagUser(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagUser", reflect.TypeOf((*MockClient)(nil).TagUser), arg0)
+}
+
 // TerminateInstances mocks base method.
 func (m *MockClient) TerminateInstances(arg0 *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
 	m.ctrl.T.Helper()
@@ -1262,6 +2306,81 @@ func (mr *MockClientMockRecorder) UntagResource(input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagResource", reflect.TypeOf((*MockClient)(nil).UntagResource), input)
 }
 
+// UpdateAccountPasswordPolicy mocks base method.
+func (m *MockClient) UpdateAccountPasswordPolicy(arg0 *iam.UpdateAccountPasswordPolicyInput) (*iam.UpdateAccountPasswordPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountPasswordPolicy", arg0)
+	ret0, _ := ret[0].(*iam.UpdateAccountPasswordPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountPasswordPolicy indicates an expected call of UpdateAccountPasswordPolicy.
+func (mr *MockClientMockRecorder) UpdateAccountPasswordPolicy(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountPasswordPolicy", reflect.TypeOf((*MockClient)(nil).UpdateAccountPasswordPolicy), arg0)
+}
+
+// UpdateAssumeRolePolicy mocks base method.
+func (m *MockClient) UpdateAssumeRolePolicy(arg0 *iam.UpdateAssumeRolePolicyInput) (*iam.UpdateAssumeRolePolicyOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAssumeRolePolicy", arg0)
+	ret0, _ := ret[0].(*iam.UpdateAssumeRolePolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAssumeRolePolicy indicates an expected call of UpdateAssumeRolePolicy.
+func (mr *MockClientMockRecorder) UpdateAssumeRolePolicy(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAssumeRolePolicy", reflect.TypeOf((*MockClient)(nil).UpdateAssumeRolePolicy), arg0)
+}
+
+// UpdateCostAllocationTagsStatus mocks base method.
+func (m *MockClient) UpdateCostAllocationTagsStatus(arg0 *costexplorer.UpdateCostAllocationTagsStatusInput) (*costexplorer.UpdateCostAllocationTagsStatusOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCostAllocationTagsStatus", arg0)
+	ret0, _ := ret[0].(*costexplorer.UpdateCostAllocationTagsStatusOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCostAllocationTagsStatus indicates an expected call of UpdateCostAllocationTagsStatus.
+func (mr *MockClientMockRecorder) UpdateCostAllocationTagsStatus(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCostAllocationTagsStatus", reflect.TypeOf((*MockClient)(nil).UpdateCostAllocationTagsStatus), arg0)
+}
+
+// UpdateRole mocks base method.
+func (m *MockClient) UpdateRole(arg0 *iam.UpdateRoleInput) (*iam.UpdateRoleOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", arg0)
+	ret0, _ := ret[0].(*iam.UpdateRoleOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockClientMockRecorder) UpdateRole(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockClient)(nil).UpdateRole), arg0)
+}
+
+// ValidatePolicy mocks base method.
+func (m *MockClient) ValidatePolicy(arg0 *accessanalyzer.ValidatePolicyInput) (*accessanalyzer.ValidatePolicyOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidatePolicy", arg0)
+	ret0, _ := ret[0].(*accessanalyzer.ValidatePolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidatePolicy indicates an expected call of ValidatePolicy.
+func (mr *MockClientMockRecorder) ValidatePolicy(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatePolicy", reflect.TypeOf((*MockClient)(nil).ValidatePolicy), arg0)
+}
+
 // MockIBuilder is a mock of IBuilder interface.
 type MockIBuilder struct {
 	ctrl     *gomock.Controller
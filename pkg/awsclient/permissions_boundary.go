@@ -0,0 +1,62 @@
+package awsclient
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-logr/logr"
+)
+
+// permissionsBoundaryHandler returns a handler setting the given permissions boundary on every IAM user and role
+// created through the client, unless the caller already chose one
+func permissionsBoundaryHandler(permissionsBoundary string) func(r *request.Request) {
+	return func(r *request.Request) {
+		switch input := r.Params.(type) {
+		case *iam.CreateUserInput:
+			if input.PermissionsBoundary == nil {
+				input.PermissionsBoundary = aws.String(permissionsBoundary)
+			}
+		case *iam.CreateRoleInput:
+			if input.PermissionsBoundary == nil {
+				input.PermissionsBoundary = aws.String(permissionsBoundary)
+			}
+		}
+	}
+}
+
+// EnsureUserPermissionsBoundary sets the permissions boundary on an existing IAM user if it has a different one or
+// none at all. It does nothing if no permissions boundary is configured.
+func EnsureUserPermissionsBoundary(reqLogger logr.Logger, client Client, user *iam.User, permissionsBoundary string) error {
+	if permissionsBoundary == "" || currentPermissionsBoundary(user.PermissionsBoundary) == permissionsBoundary {
+		return nil
+	}
+	reqLogger.Info(fmt.Sprintf("Setting permissions boundary %s on IAM user %s", permissionsBoundary, aws.StringValue(user.UserName)))
+	_, err := client.PutUserPermissionsBoundary(&iam.PutUserPermissionsBoundaryInput{
+		UserName:            user.UserName,
+		PermissionsBoundary: aws.String(permissionsBoundary),
+	})
+	return err
+}
+
+// EnsureRolePermissionsBoundary sets the permissions boundary on an existing IAM role if it has a different one or
+// none at all. It does nothing if no permissions boundary is configured.
+func EnsureRolePermissionsBoundary(reqLogger logr.Logger, client Client, role *iam.Role, permissionsBoundary string) error {
+	if permissionsBoundary == "" || currentPermissionsBoundary(role.PermissionsBoundary) == permissionsBoundary {
+		return nil
+	}
+	reqLogger.Info(fmt.Sprintf("Setting permissions boundary %s on IAM role %s", permissionsBoundary, aws.StringValue(role.RoleName)))
+	_, err := client.PutRolePermissionsBoundary(&iam.PutRolePermissionsBoundaryInput{
+		RoleName:            role.RoleName,
+		PermissionsBoundary: aws.String(permissionsBoundary),
+	})
+	return err
+}
+
+func currentPermissionsBoundary(boundary *iam.AttachedPermissionsBoundary) string {
+	if boundary == nil {
+		return ""
+	}
+	return aws.StringValue(boundary.PermissionsBoundaryArn)
+}
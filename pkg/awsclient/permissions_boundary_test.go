@@ -0,0 +1,41 @@
+package awsclient
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/iam"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Permissions boundary", func() {
+	boundary := "arn:aws:iam::aws:policy/PowerUserAccess"
+	handler := permissionsBoundaryHandler(boundary)
+
+	It("Should set the boundary on new users and roles", func() {
+		createUser := &iam.CreateUserInput{UserName: aws.String("osdManagedAdmin")}
+		handler(&request.Request{Params: createUser})
+		Expect(aws.StringValue(createUser.PermissionsBoundary)).To(Equal(boundary))
+
+		createRole := &iam.CreateRoleInput{RoleName: aws.String("ManagedOpenShift-Support")}
+		handler(&request.Request{Params: createRole})
+		Expect(aws.StringValue(createRole.PermissionsBoundary)).To(Equal(boundary))
+	})
+
+	It("Should keep a boundary chosen by the caller", func() {
+		createRole := &iam.CreateRoleInput{PermissionsBoundary: aws.String("arn:aws:iam::aws:policy/ReadOnlyAccess")}
+		handler(&request.Request{Params: createRole})
+		Expect(aws.StringValue(createRole.PermissionsBoundary)).To(Equal("arn:aws:iam::aws:policy/ReadOnlyAccess"))
+	})
+
+	It("Should leave other operations alone", func() {
+		attachPolicy := &iam.AttachUserPolicyInput{UserName: aws.String("osdManagedAdmin")}
+		handler(&request.Request{Params: attachPolicy})
+		Expect(attachPolicy).To(Equal(&iam.AttachUserPolicyInput{UserName: aws.String("osdManagedAdmin")}))
+	})
+
+	It("Should report the current boundary", func() {
+		Expect(currentPermissionsBoundary(nil)).To(Equal(""))
+		Expect(currentPermissionsBoundary(&iam.AttachedPermissionsBoundary{PermissionsBoundaryArn: aws.String(boundary)})).To(Equal(boundary))
+	})
+})
@@ -0,0 +1,78 @@
+package awsclient
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	"golang.org/x/time/rate"
+	kubeclientpkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultAccountRateLimit is how many calls per second are made to a member account by default. It stays well
+	// below the throttling thresholds of the IAM, EC2 and S3 control plane APIs.
+	defaultAccountRateLimit = 10
+	// defaultAccountRateBurst is how many calls can be made to a member account at once by default
+	defaultAccountRateBurst = 20
+)
+
+// accountRateLimiters holds a token bucket per AWS account, shared by all the clients making calls to the account
+// whatever controller built them
+var accountRateLimiters = &rateLimiterSet{limiters: map[string]*rate.Limiter{}}
+
+type rateLimiterSet struct {
+	mutex    sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// get returns the limiter of the account, updated to the given limit and burst if they changed
+func (s *rateLimiterSet) get(awsAccountID string, limit rate.Limit, burst int) *rate.Limiter {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	limiter, ok := s.limiters[awsAccountID]
+	if !ok {
+		limiter = rate.NewLimiter(limit, burst)
+		s.limiters[awsAccountID] = limiter
+		return limiter
+	}
+	if limiter.Limit() != limit {
+		limiter.SetLimit(limit)
+	}
+	if limiter.Burst() != burst {
+		limiter.SetBurst(burst)
+	}
+	return limiter
+}
+
+// accountRateLimiter returns the limiter of the AWS account, with the rate and burst configured in the operator
+// configmap. It returns nil when the account isn't known, and calls aren't rate limited then.
+func accountRateLimiter(kubeClient kubeclientpkg.Client, awsAccountID string) *rate.Limiter {
+	if awsAccountID == "" {
+		return nil
+	}
+	limit, burst := rate.Limit(defaultAccountRateLimit), defaultAccountRateBurst
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err == nil {
+		if value, err := strconv.ParseFloat(configMap.Data[awsv1alpha1.AWSAccountRateLimitConfigMapKey], 64); err == nil && value > 0 {
+			limit = rate.Limit(value)
+		}
+		if value, err := strconv.Atoi(configMap.Data[awsv1alpha1.AWSAccountRateBurstConfigMapKey]); err == nil && value > 0 {
+			burst = value
+		}
+	}
+	return accountRateLimiters.get(awsAccountID, limit, burst)
+}
+
+// rateLimitHandler returns a handler waiting for a token of the limiter before each attempt of a call is sent,
+// retries included
+func rateLimitHandler(limiter *rate.Limiter) func(r *request.Request) {
+	return func(r *request.Request) {
+		if err := limiter.Wait(r.Context()); err != nil {
+			r.Error = awserr.New(request.CanceledErrorCode, "waiting for the rate limit of the account was canceled", err)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package awsclient
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/time/rate"
+)
+
+var _ = Describe("Account rate limiters", func() {
+	var limiters *rateLimiterSet
+
+	BeforeEach(func() {
+		limiters = &rateLimiterSet{limiters: map[string]*rate.Limiter{}}
+	})
+
+	It("Should share the limiter of an account", func() {
+		limiter := limiters.get("123456789012", 10, 20)
+		Expect(limiters.get("123456789012", 10, 20)).To(BeIdenticalTo(limiter))
+		Expect(limiters.get("210987654321", 10, 20)).ToNot(BeIdenticalTo(limiter))
+	})
+
+	It("Should update the limiter when the configuration changes", func() {
+		limiter := limiters.get("123456789012", 10, 20)
+		limiters.get("123456789012", 5, 8)
+		Expect(limiter.Limit()).To(Equal(rate.Limit(5)))
+		Expect(limiter.Burst()).To(Equal(8))
+	})
+
+	It("Should not limit the calls to unknown accounts", func() {
+		Expect(accountRateLimiter(nil, "")).To(BeNil())
+	})
+})
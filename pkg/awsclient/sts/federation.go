@@ -0,0 +1,86 @@
+package sts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/openshift/aws-account-operator/config"
+)
+
+const (
+	// SigninTokenValidity is how long AWS accepts a sign-in token after it was issued
+	SigninTokenValidity = 15 * time.Minute
+
+	// FederationIssuer is the issuer of the console sign-in URLs generated by the operator
+	FederationIssuer = "aws-account-operator"
+
+	consoleDestinationSuffix = "/console/home"
+)
+
+// FederationEndpoint returns the AWS sign-in federation endpoint of the partition
+func FederationEndpoint() string {
+	if config.IsFedramp() {
+		return "https://signin.amazonaws-us-gov.com/federation"
+	}
+	return "https://signin.aws.amazon.com/federation"
+}
+
+// ConsoleDestination returns the home page of the AWS console of the partition
+func ConsoleDestination() string {
+	if config.IsFedramp() {
+		return "https://console.amazonaws-us-gov.com" + consoleDestinationSuffix
+	}
+	return "https://console.aws.amazon.com" + consoleDestinationSuffix
+}
+
+// GetSigninToken exchanges session credentials for a sign-in token at the federation endpoint, see
+// https://docs.aws.amazon.com/IAM/latest/UserGuide/id_roles_providers_enable-console-custom-url.html
+func GetSigninToken(httpClient *http.Client, endpoint string, creds *sts.Credentials) (string, error) {
+	session, err := json.Marshal(map[string]string{
+		"sessionId":    aws.StringValue(creds.AccessKeyId),
+		"sessionKey":   aws.StringValue(creds.SecretAccessKey),
+		"sessionToken": aws.StringValue(creds.SessionToken),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := httpClient.Get(fmt.Sprintf("%s?%s", endpoint, url.Values{
+		"Action":  {"getSigninToken"},
+		"Session": {string(session)},
+	}.Encode()))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("federation endpoint returned status %s", resp.Status)
+	}
+
+	response := struct {
+		SigninToken string `json:"SigninToken"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return "", err
+	}
+	if response.SigninToken == "" {
+		return "", fmt.Errorf("federation endpoint returned no sign-in token")
+	}
+	return response.SigninToken, nil
+}
+
+// LoginURL returns the console sign-in URL of a sign-in token
+func LoginURL(endpoint string, signinToken string) string {
+	return fmt.Sprintf("%s?%s", endpoint, url.Values{
+		"Action":      {"login"},
+		"Issuer":      {FederationIssuer},
+		"Destination": {ConsoleDestination()},
+		"SigninToken": {signinToken},
+	}.Encode())
+}
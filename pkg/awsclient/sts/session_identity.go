@@ -0,0 +1,108 @@
+package sts
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+)
+
+const (
+	// Session tag keys of the SRE access sessions, visible in the CloudTrail events of the member account
+	SessionTagRequestedBy  = "RequestedBy"
+	SessionTagAccountClaim = "AccountClaim"
+	SessionTagTicket       = "Ticket"
+
+	maxSourceIdentityLength = 64
+	maxSessionTagValueLen   = 256
+)
+
+var (
+	invalidSourceIdentityChars = regexp.MustCompile(`[^\w+=,.@-]`)
+	invalidSessionTagChars     = regexp.MustCompile(`[^\p{L}\p{Z}\p{N}_.:/=+\-@]`)
+)
+
+// SessionIdentity attributes the actions performed with assumed role credentials to whoever the
+// session was created for. The source identity and session tags are recorded in CloudTrail. The
+// trust policy of the assumed role has to allow sts:SetSourceIdentity and sts:TagSession.
+type SessionIdentity struct {
+	SourceIdentity string
+	Tags           map[string]string
+}
+
+// SREAccessSessionIdentity returns the identity of an SRE access session to an account: the user in
+// the requested-by annotation of the Account, the claim the account is bound to and the ticket in
+// the ticket annotation
+func SREAccessSessionIdentity(account *awsv1alpha1.Account) SessionIdentity {
+	requestedBy := account.GetAnnotations()[awsv1alpha1.RequestedByAnnotation]
+	identity := SessionIdentity{
+		SourceIdentity: requestedBy,
+		Tags: map[string]string{
+			SessionTagRequestedBy: requestedBy,
+			SessionTagTicket:      account.GetAnnotations()[awsv1alpha1.TicketAnnotation],
+		},
+	}
+	if account.Spec.ClaimLink != "" {
+		identity.Tags[SessionTagAccountClaim] = account.Spec.ClaimLinkNamespace + "/" + account.Spec.ClaimLink
+	}
+	return identity
+}
+
+// Apply sets the source identity and session tags on the AssumeRole input. Nothing is set when no
+// requester is recorded, so the operator's own sessions work with trust policies that don't allow
+// sts:SetSourceIdentity and sts:TagSession. Characters AWS doesn't accept are replaced and empty
+// tags are left out.
+func (i SessionIdentity) Apply(input *sts.AssumeRoleInput) {
+	input.SourceIdentity = nil
+	input.Tags = nil
+
+	sourceIdentity := invalidSourceIdentityChars.ReplaceAllString(i.SourceIdentity, "-")
+	if len(sourceIdentity) > maxSourceIdentityLength {
+		sourceIdentity = sourceIdentity[:maxSourceIdentityLength]
+	}
+	// AWS requires at least 2 characters
+	if len(sourceIdentity) < 2 {
+		return
+	}
+	input.SourceIdentity = aws.String(sourceIdentity)
+
+	keys := make([]string, 0, len(i.Tags))
+	for key := range i.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := []rune(invalidSessionTagChars.ReplaceAllString(i.Tags[key], "-"))
+		if len(value) == 0 {
+			continue
+		}
+		if len(value) > maxSessionTagValueLen {
+			value = value[:maxSessionTagValueLen]
+		}
+		input.Tags = append(input.Tags, &sts.Tag{Key: aws.String(key), Value: aws.String(string(value))})
+	}
+}
+
+// GetSREAccessCredentials returns STS credentials of the role for SRE access, with the session
+// attributed to the given identity
+func GetSREAccessCredentials(
+	reqLogger logr.Logger,
+	client awsclient.Client,
+	roleArn string,
+	roleSessionName string,
+	durationSeconds int64,
+	identity SessionIdentity) (*sts.AssumeRoleOutput, error) {
+	reqLogger.Info("Creating SRE access STS credentials", "roleArn", roleArn, "sourceIdentity", identity.SourceIdentity)
+	assumeRoleInput := &sts.AssumeRoleInput{
+		DurationSeconds: aws.Int64(durationSeconds),
+		RoleArn:         aws.String(roleArn),
+		RoleSessionName: aws.String(roleSessionName),
+	}
+	identity.Apply(assumeRoleInput)
+	return assumeRole(reqLogger, client, assumeRoleInput)
+}
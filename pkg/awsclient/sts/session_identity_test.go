@@ -0,0 +1,81 @@
+package sts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSREAccessSessionIdentity(t *testing.T) {
+	tests := []struct {
+		name                   string
+		account                *awsv1alpha1.Account
+		expectedSourceIdentity string
+		expectedTags           []*sts.Tag
+	}{
+		{
+			name:    "Nobody requested the session",
+			account: &awsv1alpha1.Account{},
+		},
+		{
+			name: "Claimed account without requester",
+			account: &awsv1alpha1.Account{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					awsv1alpha1.TicketAnnotation: "OHSS-1234",
+				}},
+				Spec: awsv1alpha1.AccountSpec{ClaimLink: "claim", ClaimLinkNamespace: "uhc-production-abc"},
+			},
+		},
+		{
+			name: "Claimed account with requester and ticket",
+			account: &awsv1alpha1.Account{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					awsv1alpha1.RequestedByAnnotation: "jdoe@example.com",
+					awsv1alpha1.TicketAnnotation:      "OHSS-1234",
+				}},
+				Spec: awsv1alpha1.AccountSpec{ClaimLink: "claim", ClaimLinkNamespace: "uhc-production-abc"},
+			},
+			expectedSourceIdentity: "jdoe@example.com",
+			expectedTags: []*sts.Tag{
+				{Key: aws.String("AccountClaim"), Value: aws.String("uhc-production-abc/claim")},
+				{Key: aws.String("RequestedBy"), Value: aws.String("jdoe@example.com")},
+				{Key: aws.String("Ticket"), Value: aws.String("OHSS-1234")},
+			},
+		},
+		{
+			name: "Characters AWS doesn't accept are replaced",
+			account: &awsv1alpha1.Account{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					awsv1alpha1.RequestedByAnnotation: "John Doe (SRE)",
+				}},
+			},
+			expectedSourceIdentity: "John-Doe--SRE-",
+			expectedTags: []*sts.Tag{
+				{Key: aws.String("RequestedBy"), Value: aws.String("John Doe -SRE-")},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := &sts.AssumeRoleInput{}
+			SREAccessSessionIdentity(test.account).Apply(input)
+			assert.Equal(t, test.expectedSourceIdentity, aws.StringValue(input.SourceIdentity))
+			assert.Equal(t, test.expectedTags, input.Tags)
+		})
+	}
+}
+
+func TestSessionIdentityApplyTruncates(t *testing.T) {
+	input := &sts.AssumeRoleInput{}
+	SessionIdentity{
+		SourceIdentity: strings.Repeat("a", 100),
+		Tags:           map[string]string{SessionTagTicket: strings.Repeat("b", 300)},
+	}.Apply(input)
+	assert.Len(t, aws.StringValue(input.SourceIdentity), 64)
+	assert.Len(t, aws.StringValue(input.Tags[0].Value), 256)
+}
@@ -47,12 +47,17 @@ func GetSTSCredentials(
 	if externalID != "" {
 		assumeRoleInput.ExternalId = &externalID
 	}
+	return assumeRole(reqLogger, client, &assumeRoleInput)
+}
 
+// assumeRole retries AssumeRole until it succeeds or times out, new roles can take a while to be assumable
+func assumeRole(reqLogger logr.Logger, client awsclient.Client, assumeRoleInput *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+	roleArn := *assumeRoleInput.RoleArn
 	assumeRoleOutput := &sts.AssumeRoleOutput{}
 	var err error
 	for i := 0; i < 100; i++ {
 		time.Sleep(defaultSleepDelay)
-		assumeRoleOutput, err = client.AssumeRole(&assumeRoleInput)
+		assumeRoleOutput, err = client.AssumeRole(assumeRoleInput)
 		if err == nil {
 			break
 		}
@@ -146,6 +151,7 @@ func HandleRoleAssumption(
 		AwsCredsSecretAccessKey: *creds.Credentials.SecretAccessKey,
 		AwsToken:                *creds.Credentials.SessionToken,
 		AwsRegion:               awsRegion,
+		AwsAccountID:            currentAcctInstance.Spec.AwsAccountID,
 	})
 	if err != nil {
 		logger.Error(err, "Failed to assume role")
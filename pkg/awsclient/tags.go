@@ -76,6 +76,27 @@ func (t *AWSAccountOperatorTags) BuildTags(account *awsv1alpha1.Account, managed
 		Value: account.Spec.ClaimLinkNamespace,
 	})
 
+	// Add the claim metadata once it's known, the claim UID identifies the resources
+	// created for a claim even if its namespace is renamed
+	if account.Spec.ClusterID != "" {
+		tags = append(tags, AWSTag{
+			Key:   awsv1alpha1.ClusterIDTagKey,
+			Value: account.Spec.ClusterID,
+		})
+	}
+	if account.Spec.LegalEntity.ID != "" {
+		tags = append(tags, AWSTag{
+			Key:   awsv1alpha1.LegalEntityIDTagKey,
+			Value: account.Spec.LegalEntity.ID,
+		})
+	}
+	if account.Spec.ClaimLinkUID != "" {
+		tags = append(tags, AWSTag{
+			Key:   awsv1alpha1.ClaimUIDTagKey,
+			Value: account.Spec.ClaimLinkUID,
+		})
+	}
+
 	// Adds all of the "managed tags" passed in (typically through the configmap)
 	tags = append(tags, managedTags...)
 
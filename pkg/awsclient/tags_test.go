@@ -124,6 +124,34 @@ var _ = Describe("AWS Resource Tag Builder", func() {
 			})
 		})
 	})
+
+	When("Building AWS Tags for a claimed account", func() {
+		var (
+			account = awsv1alpha1.Account{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tagsTest",
+					Namespace: "tagsTestNamespace",
+				},
+				Spec: awsv1alpha1.AccountSpec{
+					ClaimLink:          "tagsTestClaimLink",
+					ClaimLinkNamespace: "tagsTestClaimLinkNamespace",
+					ClaimLinkUID:       "tagsTestClaimUID",
+					ClusterID:          "tagsTestClusterID",
+					LegalEntity: awsv1alpha1.LegalEntity{
+						ID: "tagsTestLegalEntityID",
+					},
+				},
+			}
+			tags = AWSTags.BuildTags(&account, nil, nil).GetIAMTags()
+		)
+
+		It("Should add the claim metadata tags", func() {
+			Expect(tags).To(HaveLen(7))
+			Expect(tags).To(ContainElement(iamTag(awsv1alpha1.ClusterIDTagKey, account.Spec.ClusterID)))
+			Expect(tags).To(ContainElement(iamTag(awsv1alpha1.LegalEntityIDTagKey, account.Spec.LegalEntity.ID)))
+			Expect(tags).To(ContainElement(iamTag(awsv1alpha1.ClaimUIDTagKey, account.Spec.ClaimLinkUID)))
+		})
+	})
 })
 
 func iamTag(key string, value string) *iam.Tag {
@@ -0,0 +1,50 @@
+package awsclient
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/openshift/aws-account-operator/pkg/tracing"
+	kubeclientpkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// traceAPICall returns a handler recording each AWS call as a client span under the span in ctx.
+// Like the metrics handler it runs at the end of the Complete phase, so the span covers retries.
+func traceAPICall(ctx context.Context) func(r *request.Request) {
+	return func(r *request.Request) {
+		attributes := []tracing.Attribute{
+			tracing.String("rpc.system", "aws-api"),
+			tracing.String("rpc.service", r.ClientInfo.ServiceName),
+			tracing.String("rpc.method", r.Operation.Name),
+			tracing.String("aws.region", aws.StringValue(r.Config.Region)),
+			tracing.String("aws.request_id", r.RequestID),
+			tracing.String("aws.retry_count", strconv.Itoa(r.RetryCount)),
+		}
+		if r.HTTPResponse != nil {
+			attributes = append(attributes, tracing.String("http.status_code", strconv.Itoa(r.HTTPResponse.StatusCode)))
+		}
+		tracing.RecordClientSpan(ctx, r.ClientInfo.ServiceName+"."+r.Operation.Name, r.Time, r.Error, attributes...)
+	}
+}
+
+// WithContext returns an IBuilder that sets ctx on the NewAwsClientInput of every client it builds,
+// unless one is already set, so calls made by those clients are traced under the span in ctx.
+// This lets a reconcile hand its context to helpers which only take an IBuilder.
+func WithContext(ctx context.Context, builder IBuilder) IBuilder {
+	return &contextBuilder{ctx: ctx, builder: builder}
+}
+
+type contextBuilder struct {
+	ctx     context.Context
+	builder IBuilder
+}
+
+// GetClient implements IBuilder
+func (b *contextBuilder) GetClient(controllerName string, kubeClient kubeclientpkg.Client, input NewAwsClientInput) (Client, error) {
+	if input.Context == nil {
+		input.Context = b.ctx
+	}
+	return b.builder.GetClient(controllerName, kubeClient, input)
+}
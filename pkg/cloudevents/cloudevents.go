@@ -0,0 +1,184 @@
+// Package cloudevents publishes CloudEvents for the lifecycle of the accounts, e.g. an account being created,
+// claimed or quarantined, to an HTTP sink, so external systems can react to them without polling the CRs.
+// Events are sent in the structured content mode of the CloudEvents HTTP binding.
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+const (
+	// SpecVersion is the version of the CloudEvents specification the events follow
+	SpecVersion = "1.0"
+	// DefaultSource is the source of the events when none is configured
+	DefaultSource = "aws-account-operator"
+
+	// Types of the published events
+	TypeAccountCreated     = "com.redhat.openshift.aws-account-operator.account.created"
+	TypeAccountClaimed     = "com.redhat.openshift.aws-account-operator.account.claimed"
+	TypeAccountReused      = "com.redhat.openshift.aws-account-operator.account.reused"
+	TypeAccountQuarantined = "com.redhat.openshift.aws-account-operator.account.quarantined"
+	TypeAccountClosed      = "com.redhat.openshift.aws-account-operator.account.closed"
+
+	contentType = "application/cloudevents+json"
+
+	// maxPendingEvents bounds memory use if the sink is unreachable, newer events are dropped past it
+	maxPendingEvents = 1000
+	maxAttempts      = 3
+	sendTimeout      = 10 * time.Second
+)
+
+// retryDelay is the delay before the first retry of a failed event, it grows with every attempt
+var retryDelay = 2 * time.Second
+
+// Event is a CloudEvent about an account
+type Event struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Subject         string      `json:"subject,omitempty"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            AccountData `json:"data"`
+}
+
+// AccountData is the data of an event, describing the account it is about
+type AccountData struct {
+	Account        string `json:"account"`
+	Namespace      string `json:"namespace"`
+	AWSAccountID   string `json:"awsAccountID,omitempty"`
+	AccountPool    string `json:"accountPool,omitempty"`
+	ClaimName      string `json:"claimName,omitempty"`
+	ClaimNamespace string `json:"claimNamespace,omitempty"`
+	State          string `json:"state,omitempty"`
+	BYOC           bool   `json:"byoc,omitempty"`
+	Message        string `json:"message,omitempty"`
+}
+
+// Sink is the global publisher. It is nil, and publishing is a no-op, until it is initialized.
+var Sink *Publisher
+
+// Publisher queues events and sends them to an HTTP sink
+type Publisher struct {
+	url        string
+	source     string
+	httpClient *http.Client
+	events     chan Event
+}
+
+// NewPublisher returns a Publisher sending events to the given URL, with the given source
+func NewPublisher(url, source string) *Publisher {
+	if source == "" {
+		source = DefaultSource
+	}
+	return &Publisher{
+		url:        url,
+		source:     source,
+		httpClient: &http.Client{Timeout: sendTimeout},
+		events:     make(chan Event, maxPendingEvents),
+	}
+}
+
+// Publish queues an event of the given type about the account. It does nothing if the publisher has not been
+// initialized, and never blocks: the event is dropped if too many events are waiting to be sent.
+func Publish(log logr.Logger, eventType string, account *awsv1alpha1.Account, message string) {
+	if Sink == nil {
+		return
+	}
+	event := Sink.newEvent(eventType, account, message)
+	select {
+	case Sink.events <- event:
+	default:
+		log.Info("Too many CloudEvents waiting to be sent, dropping the event", "type", eventType, "account", account.Name)
+	}
+}
+
+func (p *Publisher) newEvent(eventType string, account *awsv1alpha1.Account, message string) Event {
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              string(uuid.NewUUID()),
+		Source:          p.source,
+		Type:            eventType,
+		Subject:         account.Name,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data: AccountData{
+			Account:        account.Name,
+			Namespace:      account.Namespace,
+			AWSAccountID:   account.Spec.AwsAccountID,
+			AccountPool:    account.Spec.AccountPool,
+			ClaimName:      account.Spec.ClaimLink,
+			ClaimNamespace: account.Spec.ClaimLinkNamespace,
+			State:          account.Status.State,
+			BYOC:           account.Spec.BYOC,
+			Message:        message,
+		},
+	}
+}
+
+// Start sends the queued events to the sink until stopCh is done. An event is retried a few times before being
+// dropped, so an unreachable sink doesn't hold up the events after it for long.
+func (p *Publisher) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the CloudEvents publisher", "sink", p.url)
+	for {
+		select {
+		case event := <-p.events:
+			err := p.sendWithRetries(stopCh, event)
+			if err != nil {
+				log.Error(err, "Failed to send CloudEvent, dropping it", "type", event.Type, "account", event.Subject)
+			}
+		case <-stopCh.Done():
+			log.Info("Stopping the CloudEvents publisher")
+			return
+		}
+	}
+}
+
+func (p *Publisher) sendWithRetries(ctx context.Context, event Event) error {
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err = p.send(ctx, event)
+		if err == nil || attempt == maxAttempts {
+			break
+		}
+		select {
+		case <-time.After(retryDelay * time.Duration(attempt)):
+		case <-ctx.Done():
+			return err
+		}
+	}
+	return err
+}
+
+// send posts the event to the sink, failing unless the sink answers with a 2xx status
+func (p *Publisher) send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("sink answered with status %s", resp.Status)
+	}
+	return nil
+}
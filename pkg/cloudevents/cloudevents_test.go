@@ -0,0 +1,111 @@
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// sink records the events it receives, failing the first requests when asked to
+type sink struct {
+	mutex    sync.Mutex
+	failures int
+	events   []Event
+	headers  []http.Header
+}
+
+func (s *sink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	event := Event{}
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.events = append(s.events, event)
+	s.headers = append(s.headers, r.Header)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *sink) received() []Event {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Event{}, s.events...)
+}
+
+var account = &awsv1alpha1.Account{
+	ObjectMeta: metav1.ObjectMeta{Name: "osd-creds-mgmt-aaabbb", Namespace: awsv1alpha1.AccountCrNamespace},
+	Spec: awsv1alpha1.AccountSpec{
+		AwsAccountID:       "123456789012",
+		ClaimLink:          "claim",
+		ClaimLinkNamespace: "cluster-ns",
+	},
+	Status: awsv1alpha1.AccountStatus{State: "Ready"},
+}
+
+func TestPublishWithoutSink(t *testing.T) {
+	Sink = nil
+	// Publishing without a sink does nothing
+	Publish(testutils.NewTestLogger().Logger(), TypeAccountClaimed, account, "")
+}
+
+func TestPublish(t *testing.T) {
+	received := &sink{failures: 1}
+	server := httptest.NewServer(received)
+	defer server.Close()
+	retryDelay = time.Millisecond
+
+	Sink = NewPublisher(server.URL, "")
+	defer func() { Sink = nil }()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go Sink.Start(testutils.NewTestLogger().Logger(), ctx)
+
+	Publish(testutils.NewTestLogger().Logger(), TypeAccountClaimed, account, "Account claim bound")
+
+	// The first request fails and is retried
+	assert.Eventually(t, func() bool { return len(received.received()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	event := received.received()[0]
+	assert.Equal(t, SpecVersion, event.SpecVersion)
+	assert.Equal(t, DefaultSource, event.Source)
+	assert.Equal(t, TypeAccountClaimed, event.Type)
+	assert.Equal(t, "osd-creds-mgmt-aaabbb", event.Subject)
+	assert.NotEmpty(t, event.ID)
+	assert.Equal(t, AccountData{
+		Account:        "osd-creds-mgmt-aaabbb",
+		Namespace:      awsv1alpha1.AccountCrNamespace,
+		AWSAccountID:   "123456789012",
+		ClaimName:      "claim",
+		ClaimNamespace: "cluster-ns",
+		State:          "Ready",
+		Message:        "Account claim bound",
+	}, event.Data)
+	assert.Equal(t, contentType, received.headers[0].Get("Content-Type"))
+}
+
+func TestSendFailure(t *testing.T) {
+	received := &sink{failures: maxAttempts}
+	server := httptest.NewServer(received)
+	defer server.Close()
+	retryDelay = time.Millisecond
+
+	publisher := NewPublisher(server.URL, "custom-source")
+	err := publisher.sendWithRetries(context.Background(), publisher.newEvent(TypeAccountClosed, account, ""))
+	assert.Error(t, err)
+	assert.Empty(t, received.received())
+}
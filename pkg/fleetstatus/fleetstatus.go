@@ -0,0 +1,143 @@
+package fleetstatus
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// summaryInterval is how often the fleet summary is recomputed
+const summaryInterval = time.Minute
+
+// unknownState is the state accounts without a state are counted in
+const unknownState = "Pending"
+
+// Updater periodically summarizes the Accounts and AccountClaims into the singleton AccountOperatorStatus CR
+type Updater struct {
+	client client.Client
+}
+
+// NewUpdater returns a new Updater
+func NewUpdater(client client.Client) *Updater {
+	return &Updater{client: client}
+}
+
+// Start updates the fleet summary every summaryInterval, until the operator is stopped
+func (u *Updater) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the fleet status updater")
+	for {
+		err := u.UpdateFleetStatus(time.Now())
+		if err != nil {
+			log.Error(err, "Failed to update the fleet status")
+		}
+
+		select {
+		case <-time.After(summaryInterval):
+		case <-stopCh.Done():
+			log.Info("Stopping the fleet status updater")
+			return
+		}
+	}
+}
+
+// UpdateFleetStatus summarizes the Accounts and AccountClaims into the status of the AccountOperatorStatus CR,
+// creating it if it doesn't exist
+func (u *Updater) UpdateFleetStatus(now time.Time) error {
+	accounts := &awsv1alpha1.AccountList{}
+	err := u.client.List(context.TODO(), accounts, client.InNamespace(awsv1alpha1.AccountCrNamespace))
+	if err != nil {
+		return err
+	}
+	accountClaims := &awsv1alpha1.AccountClaimList{}
+	err = u.client.List(context.TODO(), accountClaims)
+	if err != nil {
+		return err
+	}
+
+	fleetStatus := &awsv1alpha1.AccountOperatorStatus{}
+	key := types.NamespacedName{Name: awsv1alpha1.AccountOperatorStatusName, Namespace: awsv1alpha1.AccountCrNamespace}
+	err = u.client.Get(context.TODO(), key, fleetStatus)
+	if k8serr.IsNotFound(err) {
+		fleetStatus = &awsv1alpha1.AccountOperatorStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		}
+		err = u.client.Create(context.TODO(), fleetStatus)
+	}
+	if err != nil {
+		return err
+	}
+
+	fleetStatus.Status = Summarize(accounts.Items, accountClaims.Items, now)
+	return u.client.Status().Update(context.TODO(), fleetStatus)
+}
+
+// Summarize returns the summary of the fleet made of the accounts and account claims at the given time
+func Summarize(accounts []awsv1alpha1.Account, accountClaims []awsv1alpha1.AccountClaim, now time.Time) awsv1alpha1.AccountOperatorStatusStatus {
+	lastUpdated := metav1.NewTime(now)
+	summary := awsv1alpha1.AccountOperatorStatusStatus{
+		TotalAccounts:   len(accounts),
+		AccountsByState: map[string]int{},
+		LastUpdated:     &lastUpdated,
+	}
+
+	for i := range accounts {
+		account := &accounts[i]
+
+		state := account.Status.State
+		if state == "" {
+			state = unknownState
+		}
+		summary.AccountsByState[state]++
+
+		if account.IsFailed() {
+			summary.QuarantinedAccounts = append(summary.QuarantinedAccounts, account.Name)
+		}
+
+		if stuck := stuckAccount(account); stuck != nil {
+			if summary.OldestStuckAccount == nil || stuck.Since.Before(&summary.OldestStuckAccount.Since) {
+				summary.OldestStuckAccount = stuck
+			}
+		}
+
+		// CCS accounts are brought by the customer, they aren't created
+		phase := account.Status.Phase
+		if account.IsBYOC() || phase == nil || phase.Phase != awsv1alpha1.AccountPhaseReady {
+			continue
+		}
+		if summary.LastSuccessfulCreation == nil || summary.LastSuccessfulCreation.Before(&phase.Since) {
+			since := phase.Since
+			summary.LastSuccessfulCreation = &since
+			summary.LastCreatedAccount = account.Name
+		}
+	}
+	sort.Strings(summary.QuarantinedAccounts)
+
+	for _, accountClaim := range accountClaims {
+		if accountClaim.Status.State == "" || accountClaim.Status.State == awsv1alpha1.ClaimStatusPending {
+			summary.PendingClaims++
+		}
+	}
+
+	return summary
+}
+
+// stuckAccount returns the account if its Stuck condition is set, nil otherwise
+func stuckAccount(account *awsv1alpha1.Account) *awsv1alpha1.StuckAccount {
+	condition := account.GetCondition(awsv1alpha1.AccountStuck)
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		return nil
+	}
+	since := condition.LastTransitionTime
+	if account.Status.StateTransition.IsFor(account.Status.State) {
+		since = account.Status.StateTransition.LastTransitionTime
+	}
+	return &awsv1alpha1.StuckAccount{Name: account.Name, State: account.Status.State, Since: since}
+}
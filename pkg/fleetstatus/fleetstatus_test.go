@@ -0,0 +1,110 @@
+package fleetstatus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func account(name string, state string) awsv1alpha1.Account {
+	return awsv1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: awsv1alpha1.AccountCrNamespace},
+		Status:     awsv1alpha1.AccountStatus{State: state},
+	}
+}
+
+func stuck(a awsv1alpha1.Account, since time.Time) awsv1alpha1.Account {
+	a.Status.StateTransition = &awsv1alpha1.StateTransition{State: a.Status.State, LastTransitionTime: metav1.NewTime(since)}
+	a.Status.Conditions = []awsv1alpha1.AccountCondition{{Type: awsv1alpha1.AccountStuck, Status: corev1.ConditionTrue}}
+	return a
+}
+
+func created(a awsv1alpha1.Account, at time.Time) awsv1alpha1.Account {
+	a.Status.Phase = &awsv1alpha1.PhaseStatus{Phase: awsv1alpha1.AccountPhaseReady, Since: metav1.NewTime(at)}
+	return a
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Now()
+
+	ccs := created(account("ccs", "Ready"), now.Add(-time.Minute))
+	ccs.Spec.BYOC = true
+	accounts := []awsv1alpha1.Account{
+		created(account("old", "Ready"), now.Add(-48*time.Hour)),
+		created(account("recent", "Ready"), now.Add(-time.Hour)),
+		ccs,
+		account("failed", string(awsv1alpha1.AccountFailed)),
+		account("unhandled", string(awsv1alpha1.AccountUnhandledError)),
+		stuck(account("creating", string(awsv1alpha1.AccountCreating)), now.Add(-3*time.Hour)),
+		stuck(account("verifying", string(awsv1alpha1.AccountPendingVerification)), now.Add(-30*time.Hour)),
+		account("new", ""),
+	}
+	accountClaims := []awsv1alpha1.AccountClaim{
+		{Status: awsv1alpha1.AccountClaimStatus{State: awsv1alpha1.ClaimStatusReady}},
+		{Status: awsv1alpha1.AccountClaimStatus{State: awsv1alpha1.ClaimStatusPending}},
+		{},
+	}
+
+	summary := Summarize(accounts, accountClaims, now)
+
+	assert.Equal(t, 8, summary.TotalAccounts)
+	assert.Equal(t, map[string]int{
+		"Ready":               3,
+		"Failed":              1,
+		"UnhandledError":      1,
+		"Creating":            1,
+		"PendingVerification": 1,
+		"Pending":             1,
+	}, summary.AccountsByState)
+	assert.Equal(t, 2, summary.PendingClaims)
+	assert.Equal(t, []string{"failed", "unhandled"}, summary.QuarantinedAccounts)
+	if assert.NotNil(t, summary.OldestStuckAccount) {
+		assert.Equal(t, "verifying", summary.OldestStuckAccount.Name)
+		assert.Equal(t, string(awsv1alpha1.AccountPendingVerification), summary.OldestStuckAccount.State)
+	}
+	assert.Equal(t, "recent", summary.LastCreatedAccount)
+	if assert.NotNil(t, summary.LastSuccessfulCreation) {
+		assert.True(t, summary.LastSuccessfulCreation.Time.Equal(now.Add(-time.Hour)))
+	}
+}
+
+func TestSummarizeEmptyFleet(t *testing.T) {
+	summary := Summarize(nil, nil, time.Now())
+
+	assert.Equal(t, 0, summary.TotalAccounts)
+	assert.Empty(t, summary.AccountsByState)
+	assert.Nil(t, summary.OldestStuckAccount)
+	assert.Nil(t, summary.LastSuccessfulCreation)
+	assert.NotNil(t, summary.LastUpdated)
+}
+
+func TestUpdateFleetStatus(t *testing.T) {
+	assert.NoError(t, apis.AddToScheme(scheme.Scheme))
+	ready := account("ready", "Ready")
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&ready).Build()
+	updater := NewUpdater(kubeClient)
+
+	// The CR is created by the first update
+	assert.NoError(t, updater.UpdateFleetStatus(time.Now()))
+	fleetStatus := &awsv1alpha1.AccountOperatorStatus{}
+	key := types.NamespacedName{Name: awsv1alpha1.AccountOperatorStatusName, Namespace: awsv1alpha1.AccountCrNamespace}
+	assert.NoError(t, kubeClient.Get(context.TODO(), key, fleetStatus))
+	assert.Equal(t, 1, fleetStatus.Status.TotalAccounts)
+
+	failed := account("failed", string(awsv1alpha1.AccountFailed))
+	assert.NoError(t, kubeClient.Create(context.TODO(), &failed))
+	assert.NoError(t, updater.UpdateFleetStatus(time.Now()))
+	assert.NoError(t, kubeClient.Get(context.TODO(), key, fleetStatus))
+	assert.Equal(t, 2, fleetStatus.Status.TotalAccounts)
+	assert.Equal(t, []string{"failed"}, fleetStatus.Status.QuarantinedAccounts)
+}
@@ -0,0 +1,91 @@
+// Package health provides the liveness and readiness checks of the operator
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/sts"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+const (
+	// awsCheckInterval is how long the outcome of the AWS connectivity check is reused. The probes run every few
+	// seconds, the payer account isn't called that often.
+	awsCheckInterval = time.Minute
+	// checkerName is the controller name the AWS calls of the checks are recorded under
+	checkerName = "health"
+)
+
+// CacheSynced returns a check failing when the informers of the cache don't sync within timeout, e.g. because the
+// operator lost its connection to the API server
+func CacheSynced(informers cache.Informers, timeout time.Duration) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		if !informers.WaitForCacheSync(ctx) {
+			return errors.New("the informers aren't synced")
+		}
+		return nil
+	}
+}
+
+// AWSConnectivity checks the operator can reach STS and AWS Organizations with the credentials of the payer account
+type AWSConnectivity struct {
+	kubeClient       client.Client
+	awsClientBuilder awsclient.IBuilder
+
+	// mutex guards the outcome of the last check
+	mutex     sync.Mutex
+	lastCheck time.Time
+	lastErr   error
+}
+
+// NewAWSConnectivity returns a new AWSConnectivity
+func NewAWSConnectivity(kubeClient client.Client, awsClientBuilder awsclient.IBuilder) *AWSConnectivity {
+	return &AWSConnectivity{kubeClient: kubeClient, awsClientBuilder: awsClientBuilder}
+}
+
+// Check is the healthz.Checker of the AWS connectivity, it calls AWS at most once every awsCheckInterval
+func (c *AWSConnectivity) Check(_ *http.Request) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.lastCheck.IsZero() && time.Since(c.lastCheck) < awsCheckInterval {
+		return c.lastErr
+	}
+	c.lastErr = c.check()
+	c.lastCheck = time.Now()
+	return c.lastErr
+}
+
+func (c *AWSConnectivity) check() error {
+	awsClient, err := c.awsClientBuilder.GetClient(checkerName, c.kubeClient, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed building the operator AWS client: %w", err)
+	}
+	_, err = awsClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return fmt.Errorf("failed reaching STS: %w", err)
+	}
+	_, err = awsClient.ListAccounts(&organizations.ListAccountsInput{MaxResults: aws.Int64(1)})
+	if err != nil {
+		return fmt.Errorf("failed reaching AWS Organizations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package health
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/openshift/aws-account-operator/pkg/awsclient/mock"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAWSConnectivity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	builder := mock.NewMockIBuilder(ctrl)
+	awsClient := mock.NewMockClient(ctrl)
+	builder.EXPECT().GetClient(checkerName, gomock.Any(), gomock.Any()).Return(awsClient, nil).Times(1)
+	awsClient.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{}, nil).Times(1)
+	awsClient.EXPECT().ListAccounts(gomock.Any()).Return(nil, errors.New("AccessDenied")).Times(1)
+
+	check := NewAWSConnectivity(nil, builder)
+	if err := check.Check(nil); err == nil {
+		t.Error("expected the check to fail when AWS Organizations can't be reached")
+	}
+	// The outcome is reused, AWS isn't called again
+	if err := check.Check(nil); err == nil {
+		t.Error("expected the failure to be reused")
+	}
+}
+
+func TestAWSConnectivitySucceeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	builder := mock.NewMockIBuilder(ctrl)
+	awsClient := mock.NewMockClient(ctrl)
+	builder.EXPECT().GetClient(checkerName, gomock.Any(), gomock.Any()).Return(awsClient, nil)
+	awsClient.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{}, nil)
+	awsClient.EXPECT().ListAccounts(gomock.Any()).Return(&organizations.ListAccountsOutput{}, nil)
+
+	if err := NewAWSConnectivity(nil, builder).Check(nil); err != nil {
+		t.Errorf("expected the check to succeed, got %v", err)
+	}
+}
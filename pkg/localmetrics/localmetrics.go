@@ -16,14 +16,17 @@ package localmetrics
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	neturl "net/url"
+	"strconv"
 	"strings"
 
 	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -43,6 +46,9 @@ var (
 type MetricsCollector struct {
 	store                           cache.Cache
 	awsAccounts                     prometheus.Gauge
+	orphanedAWSAccounts             prometheus.Gauge
+	accountsNotInOrganization       prometheus.Gauge
+	idleAccountCost                 *prometheus.GaugeVec
 	accounts                        *prometheus.GaugeVec
 	ccsAccounts                     *prometheus.GaugeVec
 	accountClaims                   *prometheus.GaugeVec
@@ -51,14 +57,28 @@ type MetricsCollector struct {
 	awsLimitDelta                   *prometheus.GaugeVec
 	availableOSDAccounts            *prometheus.GaugeVec
 	accountsProgressing             *prometheus.GaugeVec
+	accountPoolAccounts             *prometheus.GaugeVec
+	pendingAccountClaims            *prometheus.GaugeVec
+	stuckCRs                        *prometheus.GaugeVec
+	timeInState                     *prometheus.GaugeVec
+	rootAccessKeyAccounts           *prometheus.GaugeVec
+	accountPhases                   *prometheus.GaugeVec
+	nearlyExhaustedAccounts         prometheus.Gauge
+	maxQuotaUsage                   *prometheus.GaugeVec
 	accountReadyDuration            prometheus.Histogram
 	ccsAccountReadyDuration         prometheus.Histogram
 	accountClaimReadyDuration       prometheus.Histogram
 	ccsAccountClaimReadyDuration    prometheus.Histogram
 	accountClaimPendingDuration     prometheus.Histogram
 	ccsAccountClaimPendingDuration  prometheus.Histogram
+	accountClaimFulfillmentDuration *prometheus.HistogramVec
+	accountClaimTimeouts            prometheus.Counter
 	accountReuseCleanupDuration     prometheus.Histogram
 	accountReuseCleanupFailureCount prometheus.Counter
+	accountReuseCleanupStepDuration *prometheus.HistogramVec
+	accountReuseCleanupStepFailures *prometheus.CounterVec
+	accountReuseCount               *prometheus.CounterVec
+	federatedRoleRepairs            *prometheus.CounterVec
 	reconcileDuration               *prometheus.HistogramVec
 	apiCallDuration                 *prometheus.HistogramVec
 }
@@ -72,6 +92,21 @@ func NewMetricsCollector(store cache.Cache) *MetricsCollector {
 			Help:        "Report how many accounts have been created in AWS org",
 			ConstLabels: prometheus.Labels{"name": operatorName},
 		}),
+		orphanedAWSAccounts: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "aws_account_operator_orphaned_aws_accounts",
+			Help:        "Report how many AWS accounts of the pool OU have no account cr",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}),
+		accountsNotInOrganization: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "aws_account_operator_accounts_not_in_organization",
+			Help:        "Report how many account crs refer to an AWS account that isn't an active member of the AWS org",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}),
+		idleAccountCost: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "aws_account_operator_idle_account_cost_usd",
+			Help:        "The cost Cost Explorer reports for a ready unclaimed account over the days it was idle in the last week",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}, []string{"account_name", "aws_account_id"}),
 		accounts: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name:        "aws_account_operator_account_crs",
 			Help:        "Report how many account crs in the cluster",
@@ -120,6 +155,55 @@ func NewMetricsCollector(store cache.Cache) *MetricsCollector {
 			ConstLabels: prometheus.Labels{"name": operatorName},
 		}, []string{"namespace", "pool_name"}),
 
+		accountPoolAccounts: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "aws_account_operator_account_pool_accounts",
+			Help:        "Report how many account crs each account pool has, broken down by state",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}, []string{"pool_name", "claimed", "state"}),
+
+		// pool_name is empty for claims against the default account pool
+		pendingAccountClaims: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "aws_account_operator_account_claims_pending",
+			Help:        "Report how many account claim crs are waiting for an account",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}, []string{"pool_name", "ccs"}),
+
+		stuckCRs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "aws_account_operator_stuck_crs",
+			Help:        "Report how many crs have been in their current state for longer than the configured threshold",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}, []string{"kind", "state"}),
+
+		timeInState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "aws_account_operator_max_time_in_state_seconds",
+			Help:        "The longest time any cr has spent in its current state",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}, []string{"kind", "state"}),
+
+		rootAccessKeyAccounts: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "aws_account_operator_accounts_with_root_access_keys",
+			Help:        "Report how many accounts have a root user with access keys, a security risk",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}, []string{"claimed"}),
+
+		accountPhases: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "aws_account_operator_accounts_by_phase",
+			Help:        "Report how many accounts are in each account creation phase, and whether the phase is being retried after a failure",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}, []string{"phase", "retrying"}),
+
+		nearlyExhaustedAccounts: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "aws_account_operator_nearly_exhausted_accounts",
+			Help:        "Report how many accounts have a service quota whose usage reached the exhaustion threshold",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}),
+
+		maxQuotaUsage: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "aws_account_operator_max_quota_usage_percent",
+			Help:        "The highest usage of a tracked service quota in any account, as a percentage of the quota",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}, []string{"quota", "region"}),
+
 		accountReadyDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
 			Name:        "aws_account_operator_account_ready_duration_seconds",
 			Help:        "The duration for account cr to get ready",
@@ -158,6 +242,17 @@ func NewMetricsCollector(store cache.Cache) *MetricsCollector {
 			ConstLabels: prometheus.Labels{"name": operatorName},
 			Buckets:     []float64{60, 120, 240, 300, 600},
 		}),
+		accountClaimFulfillmentDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "aws_account_operator_account_claim_fulfillment_duration_seconds",
+			Help:        "The duration from account claim cr creation until it is Ready",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+			Buckets:     []float64{5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+		}, []string{"ccs"}),
+		accountClaimTimeouts: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "aws_account_operator_account_claim_timeouts_total",
+			Help:        "Number of account claims not fulfilled within their deadline",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}),
 		accountReuseCleanupDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
 			Name:        "aws_account_operator_account_reuse_cleanup_duration_seconds",
 			Help:        "The duration for account reuse cleanup",
@@ -170,6 +265,27 @@ func NewMetricsCollector(store cache.Cache) *MetricsCollector {
 			Help:        "Number of account reuse cleanup failures",
 			ConstLabels: prometheus.Labels{"name": operatorName},
 		}),
+		accountReuseCleanupStepDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "aws_account_operator_account_reuse_cleanup_step_duration_seconds",
+			Help:        "The duration of each step of the account reuse cleanup",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+			Buckets:     []float64{1, 3, 5, 10, 15, 20, 30},
+		}, []string{"step"}),
+		accountReuseCleanupStepFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "aws_account_operator_account_reuse_cleanup_step_failures_total",
+			Help:        "Number of account reuse cleanup step failures, broken down by step, error code and category",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}, []string{"step", "error", "error_source", "error_category"}),
+		accountReuseCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "aws_account_operator_account_reuse_total",
+			Help:        "Number of accounts put back into the pool for reuse, broken down by result",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}, []string{"result"}),
+		federatedRoleRepairs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "aws_account_operator_federated_role_repairs_total",
+			Help:        "Number of repairs of federated roles that drifted from their AWSFederatedRole, broken down by what was repaired",
+			ConstLabels: prometheus.Labels{"name": operatorName},
+		}, []string{"drift"}),
 		reconcileDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Name:        "aws_account_operator_reconcile_duration_seconds",
 			Help:        "Distribution of the number of seconds a Reconcile takes, broken down by controller",
@@ -192,6 +308,9 @@ func NewMetricsCollector(store cache.Cache) *MetricsCollector {
 // Describe implements the prometheus.Collector interface.
 func (c *MetricsCollector) Describe(ch chan<- *prometheus.Desc) {
 	c.awsAccounts.Describe(ch)
+	c.orphanedAWSAccounts.Describe(ch)
+	c.accountsNotInOrganization.Describe(ch)
+	c.idleAccountCost.Describe(ch)
 	c.accounts.Describe(ch)
 	c.ccsAccounts.Describe(ch)
 	c.accountClaims.Describe(ch)
@@ -199,6 +318,14 @@ func (c *MetricsCollector) Describe(ch chan<- *prometheus.Desc) {
 	c.awsLimitDelta.Describe(ch)
 	c.availableOSDAccounts.Describe(ch)
 	c.accountsProgressing.Describe(ch)
+	c.accountPoolAccounts.Describe(ch)
+	c.pendingAccountClaims.Describe(ch)
+	c.stuckCRs.Describe(ch)
+	c.timeInState.Describe(ch)
+	c.rootAccessKeyAccounts.Describe(ch)
+	c.accountPhases.Describe(ch)
+	c.nearlyExhaustedAccounts.Describe(ch)
+	c.maxQuotaUsage.Describe(ch)
 	c.accountPoolSize.Describe(ch)
 	c.accountPoolSize.Describe(ch)
 	c.accountReuseAvailable.Describe(ch)
@@ -208,8 +335,14 @@ func (c *MetricsCollector) Describe(ch chan<- *prometheus.Desc) {
 	c.ccsAccountClaimReadyDuration.Describe(ch)
 	c.accountClaimPendingDuration.Describe(ch)
 	c.ccsAccountClaimPendingDuration.Describe(ch)
+	c.accountClaimFulfillmentDuration.Describe(ch)
+	c.accountClaimTimeouts.Describe(ch)
 	c.accountReuseCleanupDuration.Describe(ch)
 	c.accountReuseCleanupFailureCount.Describe(ch)
+	c.accountReuseCleanupStepDuration.Describe(ch)
+	c.accountReuseCleanupStepFailures.Describe(ch)
+	c.accountReuseCount.Describe(ch)
+	c.federatedRoleRepairs.Describe(ch)
 	c.reconcileDuration.Describe(ch)
 	c.apiCallDuration.Describe(ch)
 }
@@ -218,6 +351,9 @@ func (c *MetricsCollector) Describe(ch chan<- *prometheus.Desc) {
 func (c *MetricsCollector) Collect(ch chan<- prometheus.Metric) {
 	c.collect()
 	c.awsAccounts.Collect(ch)
+	c.orphanedAWSAccounts.Collect(ch)
+	c.accountsNotInOrganization.Collect(ch)
+	c.idleAccountCost.Collect(ch)
 	c.accounts.Collect(ch)
 	c.ccsAccounts.Collect(ch)
 	c.accountClaims.Collect(ch)
@@ -225,6 +361,14 @@ func (c *MetricsCollector) Collect(ch chan<- prometheus.Metric) {
 	c.awsLimitDelta.Collect(ch)
 	c.availableOSDAccounts.Collect(ch)
 	c.accountsProgressing.Collect(ch)
+	c.accountPoolAccounts.Collect(ch)
+	c.pendingAccountClaims.Collect(ch)
+	c.stuckCRs.Collect(ch)
+	c.timeInState.Collect(ch)
+	c.rootAccessKeyAccounts.Collect(ch)
+	c.accountPhases.Collect(ch)
+	c.nearlyExhaustedAccounts.Collect(ch)
+	c.maxQuotaUsage.Collect(ch)
 	c.accountReuseAvailable.Collect(ch)
 	c.accountReadyDuration.Collect(ch)
 	c.ccsAccountReadyDuration.Collect(ch)
@@ -232,8 +376,14 @@ func (c *MetricsCollector) Collect(ch chan<- prometheus.Metric) {
 	c.ccsAccountClaimReadyDuration.Collect(ch)
 	c.accountClaimPendingDuration.Collect(ch)
 	c.ccsAccountClaimPendingDuration.Collect(ch)
+	c.accountClaimFulfillmentDuration.Collect(ch)
+	c.accountClaimTimeouts.Collect(ch)
 	c.accountReuseCleanupDuration.Collect(ch)
 	c.accountReuseCleanupFailureCount.Collect(ch)
+	c.accountReuseCleanupStepDuration.Collect(ch)
+	c.accountReuseCleanupStepFailures.Collect(ch)
+	c.accountReuseCount.Collect(ch)
+	c.federatedRoleRepairs.Collect(ch)
 	c.reconcileDuration.Collect(ch)
 	c.apiCallDuration.Collect(ch)
 }
@@ -248,6 +398,14 @@ func (c *MetricsCollector) collect() {
 	c.awsLimitDelta.Reset()
 	c.availableOSDAccounts.Reset()
 	c.accountsProgressing.Reset()
+	c.accountPoolAccounts.Reset()
+	c.pendingAccountClaims.Reset()
+	c.stuckCRs.Reset()
+	c.timeInState.Reset()
+	c.rootAccessKeyAccounts.Reset()
+	c.accountPhases.Reset()
+	c.nearlyExhaustedAccounts.Set(0)
+	c.maxQuotaUsage.Reset()
 	c.accountReuseAvailable.Reset()
 
 	ctx := context.TODO()
@@ -255,6 +413,7 @@ func (c *MetricsCollector) collect() {
 		accounts      awsv1alpha1.AccountList
 		accountClaims awsv1alpha1.AccountClaimList
 		accountPool   awsv1alpha1.AccountPoolList
+		accountQuotas awsv1alpha1.AWSAccountQuotaList
 		claimed       string
 		reused        string
 	)
@@ -274,6 +433,7 @@ func (c *MetricsCollector) collect() {
 		return
 	}
 
+	maxTimeInState := map[stateKey]float64{}
 	for _, account := range accounts.Items {
 		if account.Status.Claimed {
 			claimed = "true"
@@ -297,18 +457,99 @@ func (c *MetricsCollector) collect() {
 		} else {
 			c.accounts.WithLabelValues(claimed, reused, account.Status.State).Inc()
 		}
+
+		if poolName, ok := accountPoolName(account); ok {
+			c.accountPoolAccounts.WithLabelValues(poolName, claimed, account.Status.State).Inc()
+		}
+
+		if rootAccessKeys := account.GetCondition(awsv1alpha1.AccountRootAccessKeysPresent); rootAccessKeys != nil && rootAccessKeys.Status == corev1.ConditionTrue {
+			c.rootAccessKeyAccounts.WithLabelValues(claimed).Inc()
+		}
+
+		if phase := account.Status.Phase; phase != nil {
+			c.accountPhases.WithLabelValues(string(phase.Phase), strconv.FormatBool(phase.Attempts > 0)).Inc()
+		}
+
+		stuck := account.GetCondition(awsv1alpha1.AccountStuck)
+		c.observeTimeInState(maxTimeInState, "Account", account.Status.StateTransition, stuck != nil && stuck.Status == corev1.ConditionTrue)
 	}
 
 	for _, accountClaim := range accountClaims.Items {
 		c.accountClaims.WithLabelValues(string(accountClaim.Status.State)).Inc()
+
+		if accountClaim.Status.State == "" || accountClaim.Status.State == awsv1alpha1.ClaimStatusPending {
+			ccs := strconv.FormatBool(accountClaim.Spec.BYOCAWSAccountID != "")
+			c.pendingAccountClaims.WithLabelValues(accountClaim.Spec.AccountPool, ccs).Inc()
+		}
+
+		stuck := false
+		for _, condition := range accountClaim.Status.Conditions {
+			if condition.Type == awsv1alpha1.AccountClaimStuck && condition.Status == corev1.ConditionTrue {
+				stuck = true
+			}
+		}
+		c.observeTimeInState(maxTimeInState, "AccountClaim", accountClaim.Status.StateTransition, stuck)
+	}
+
+	for key, seconds := range maxTimeInState {
+		c.timeInState.WithLabelValues(key.kind, key.state).Set(seconds)
 	}
 
 	for _, pool := range accountPool.Items {
-		c.accountPoolSize.WithLabelValues(pool.Namespace, pool.Name).Set(float64(pool.Spec.PoolSize))
+		// The status holds the pool size targeted by the active AccountPoolPolicy windows
+		c.accountPoolSize.WithLabelValues(pool.Namespace, pool.Name).Set(float64(pool.Status.PoolSize))
 		c.awsLimitDelta.WithLabelValues(pool.Namespace, pool.Name).Set(float64(pool.Status.AWSLimitDelta))
 		c.availableOSDAccounts.WithLabelValues(pool.Namespace, pool.Name).Set(float64(pool.Status.AvailableAccounts))
 		c.accountsProgressing.WithLabelValues(pool.Namespace, pool.Name).Set(float64(pool.Status.AccountsProgressing))
 	}
+
+	if err := c.store.List(ctx, &accountQuotas, []client.ListOption{
+		client.InNamespace(awsv1alpha1.AccountCrNamespace)}...); err != nil {
+		log.Error(err, "failed to list account quotas")
+		return
+	}
+
+	maxQuotaUsage := map[quotaKey]int{}
+	for _, accountQuota := range accountQuotas.Items {
+		if accountQuota.Status.NearlyExhausted {
+			c.nearlyExhaustedAccounts.Inc()
+		}
+		for _, quota := range accountQuota.Status.Quotas {
+			key := quotaKey{name: quota.Name, region: quota.Region}
+			if usage, ok := maxQuotaUsage[key]; !ok || quota.UsagePercent > usage {
+				maxQuotaUsage[key] = quota.UsagePercent
+			}
+		}
+	}
+	for key, usage := range maxQuotaUsage {
+		c.maxQuotaUsage.WithLabelValues(key.name, key.region).Set(float64(usage))
+	}
+}
+
+// quotaKey identifies a service quota in the quota usage metrics
+type quotaKey struct {
+	name   string
+	region string
+}
+
+// stateKey identifies the state of a kind of cr in the time in state metrics
+type stateKey struct {
+	kind  string
+	state string
+}
+
+// observeTimeInState keeps the longest time in state per kind and state, and counts stuck crs
+func (c *MetricsCollector) observeTimeInState(maxTimeInState map[stateKey]float64, kind string, transition *awsv1alpha1.StateTransition, stuck bool) {
+	if transition == nil {
+		return
+	}
+	if stuck {
+		c.stuckCRs.WithLabelValues(kind, transition.State).Inc()
+	}
+	key := stateKey{kind: kind, state: transition.State}
+	if seconds := transition.TimeInState().Seconds(); seconds > maxTimeInState[key] {
+		maxTimeInState[key] = seconds
+	}
 }
 
 // SetTotalAWSAccounts sets the metric watching the total number of AWS accounts known by the operator
@@ -316,6 +557,22 @@ func (c *MetricsCollector) SetTotalAWSAccounts(total int) {
 	c.awsAccounts.Set(float64(total))
 }
 
+// SetInventoryDrift sets the metrics watching the drift between the AWS accounts of the organization and the account crs
+func (c *MetricsCollector) SetInventoryDrift(orphanedAWSAccounts, accountsNotInOrganization int) {
+	c.orphanedAWSAccounts.Set(float64(orphanedAWSAccounts))
+	c.accountsNotInOrganization.Set(float64(accountsNotInOrganization))
+}
+
+// ResetIdleAccountCosts drops the cost of the accounts of the previous idle cost check
+func (c *MetricsCollector) ResetIdleAccountCosts() {
+	c.idleAccountCost.Reset()
+}
+
+// SetIdleAccountCost sets the cost of a ready unclaimed account over the days it was idle in the last week
+func (c *MetricsCollector) SetIdleAccountCost(accountName, awsAccountID string, cost float64) {
+	c.idleAccountCost.WithLabelValues(accountName, awsAccountID).Set(cost)
+}
+
 // SetAccountReadyDuration sets the metric describing the time it takes for an account to go into the Ready state
 func (c *MetricsCollector) SetAccountReadyDuration(ccs bool, duration float64) {
 	if ccs {
@@ -343,6 +600,16 @@ func (c *MetricsCollector) SetAccountClaimPendingDuration(ccs bool, duration flo
 	}
 }
 
+// SetAccountClaimFulfillmentDuration sets the metric describing the time it takes from the creation of an accountClaim until it is Ready
+func (c *MetricsCollector) SetAccountClaimFulfillmentDuration(ccs bool, duration float64) {
+	c.accountClaimFulfillmentDuration.WithLabelValues(strconv.FormatBool(ccs)).Observe(duration)
+}
+
+// AddAccountClaimTimeout counts an account claim not fulfilled within its deadline
+func (c *MetricsCollector) AddAccountClaimTimeout() {
+	c.accountClaimTimeouts.Inc()
+}
+
 // SetAccountReusedCleanupDuration sets the metric describing the time it takes for an account to complete the reuse process
 func (c *MetricsCollector) SetAccountReusedCleanupDuration(duration float64) {
 	c.accountReuseCleanupDuration.Observe(duration)
@@ -353,9 +620,51 @@ func (c *MetricsCollector) AddAccountReuseCleanupFailure() {
 	c.accountReuseCleanupFailureCount.Inc()
 }
 
+// SetAccountReuseCleanupStepDuration describes the time a single step of the reuse cleanup took,
+// and counts the step as failed if it returned an error
+func (c *MetricsCollector) SetAccountReuseCleanupStepDuration(step string, duration float64, err error) {
+	c.accountReuseCleanupStepDuration.WithLabelValues(step).Observe(duration)
+	if err == nil {
+		return
+	}
+	e := &ReportedError{}
+	e.Parse(err)
+	c.accountReuseCleanupStepFailures.WithLabelValues(step, e.Code, e.Source, string(e.Category)).Inc()
+}
+
+// AddAccountReuse counts an account that succeeded or failed to be put back into the pool for reuse
+func (c *MetricsCollector) AddAccountReuse(succeeded bool) {
+	result := "success"
+	if !succeeded {
+		result = "failure"
+	}
+	c.accountReuseCount.WithLabelValues(result).Inc()
+}
+
+// AddFederatedRoleRepair counts a repair of a federated role that drifted from its AWSFederatedRole, e.g.
+// of its trust policy or attached policies
+func (c *MetricsCollector) AddFederatedRoleRepair(drift string) {
+	c.federatedRoleRepairs.WithLabelValues(drift).Inc()
+}
+
+// accountPoolName returns the name of the account pool the account belongs to, if any
+func accountPoolName(account awsv1alpha1.Account) (string, bool) {
+	if account.Spec.AccountPool != "" {
+		return account.Spec.AccountPool, true
+	}
+	for _, ref := range account.GetOwnerReferences() {
+		if ref.Kind == "AccountPool" {
+			return ref.Name, true
+		}
+	}
+	return "", false
+}
+
 type ReportedError struct {
 	Source string
 	Code   string
+	// Category tells the throttled, denied, blocked by a dependency and not found AWS errors apart
+	Category awsv1alpha1.AWSErrorCategory
 }
 
 func (e *ReportedError) Parse(err error) {
@@ -363,8 +672,11 @@ func (e *ReportedError) Parse(err error) {
 		return
 	}
 
-	// attempt to see if it's an AWS Error
-	if aerr, ok := err.(awserr.Error); ok {
+	e.Category = awsv1alpha1.GetAWSErrorCategory(err)
+
+	// attempt to see if it's an AWS Error, possibly wrapped
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
 		e.Code = aerr.Code()
 		e.Source = "aws"
 		return
@@ -6,7 +6,11 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
 )
 
 func TestPathParse(t *testing.T) {
@@ -130,3 +134,65 @@ func TestReconcileErrorParse(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountReuseCleanupStepMetrics(t *testing.T) {
+	c := NewMetricsCollector(nil)
+
+	c.SetAccountReuseCleanupStepDuration("s3", 1, nil)
+	c.SetAccountReuseCleanupStepDuration("s3", 2, awserr.New("AccessDenied", "This is a message", nil))
+	c.SetAccountReuseCleanupStepDuration("route53", 1, fmt.Errorf("Test"))
+	c.SetAccountReuseCleanupStepDuration("ebs_volumes", 1, fmt.Errorf("ebs_volumes: %w", awserr.New("VolumeInUse", "This is a message", nil)))
+
+	assert.Equal(t, 3, testutil.CollectAndCount(c.accountReuseCleanupStepDuration))
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.accountReuseCleanupStepFailures.WithLabelValues("s3", "AccessDenied", "aws", "AccessDenied")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.accountReuseCleanupStepFailures.WithLabelValues("route53", "{OTHER}", "{OTHER}", "Other")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.accountReuseCleanupStepFailures.WithLabelValues("ebs_volumes", "VolumeInUse", "aws", "DependencyViolation")))
+}
+
+func TestAddAccountReuse(t *testing.T) {
+	c := NewMetricsCollector(nil)
+
+	c.AddAccountReuse(true)
+	c.AddAccountReuse(true)
+	c.AddAccountReuse(false)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(c.accountReuseCount.WithLabelValues("success")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.accountReuseCount.WithLabelValues("failure")))
+}
+
+func TestAccountPoolName(t *testing.T) {
+	tests := []struct {
+		name         string
+		account      awsv1alpha1.Account
+		expectedName string
+		expectedOK   bool
+	}{
+		{
+			name:         "pool from spec",
+			account:      awsv1alpha1.Account{Spec: awsv1alpha1.AccountSpec{AccountPool: "hs-zero-size-accountpool"}},
+			expectedName: "hs-zero-size-accountpool",
+			expectedOK:   true,
+		},
+		{
+			name: "pool from owner reference",
+			account: awsv1alpha1.Account{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{
+				{Kind: "AccountPool", Name: "default-accountpool"},
+			}}},
+			expectedName: "default-accountpool",
+			expectedOK:   true,
+		},
+		{
+			name:       "not in a pool",
+			account:    awsv1alpha1.Account{},
+			expectedOK: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			poolName, ok := accountPoolName(test.account)
+			assert.Equal(t, test.expectedName, poolName)
+			assert.Equal(t, test.expectedOK, ok)
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Package notifier sends notifications about the critical conditions of the fleet, e.g. an account failing to be
+// created or a pool running out of accounts, to webhooks, Slack and PagerDuty, so they reach the on-call SREs instead
+// of only showing up in the logs and the dashboards.
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+)
+
+// Kind is the kind of condition a notification is about
+type Kind string
+
+const (
+	// KindAccountFailed is sent when an account fails to be created or set up and is set to Failed
+	KindAccountFailed Kind = "account-failed"
+	// KindCleanupQuarantined is sent when an account is kept out of the pool after its cleanup, because resources
+	// were excluded from the cleanup or the cleanup was given up
+	KindCleanupQuarantined Kind = "cleanup-quarantined"
+	// KindPoolExhausted is sent when an AccountPool has no unclaimed account left
+	KindPoolExhausted Kind = "pool-exhausted"
+	// KindClaimDeadlineExceeded is sent when an AccountClaim isn't fulfilled by its deadline
+	KindClaimDeadlineExceeded Kind = "claim-deadline-exceeded"
+
+	// maxPendingNotifications bounds memory use if the sinks are unreachable, newer notifications are dropped past it
+	maxPendingNotifications = 100
+	maxAttempts             = 3
+	sendTimeout             = 10 * time.Second
+)
+
+// Kinds are the kinds of notifications, all sent unless configured otherwise
+var Kinds = []Kind{KindAccountFailed, KindCleanupQuarantined, KindPoolExhausted, KindClaimDeadlineExceeded}
+
+// retryDelay is the delay before the first retry of a failed notification, it grows with every attempt
+var retryDelay = 2 * time.Second
+
+// Notification is a critical condition of an account, a claim or a pool
+type Notification struct {
+	Kind         Kind      `json:"kind"`
+	Summary      string    `json:"summary"`
+	Message      string    `json:"message,omitempty"`
+	Time         time.Time `json:"time"`
+	Account      string    `json:"account,omitempty"`
+	AWSAccountID string    `json:"awsAccountID,omitempty"`
+	AccountPool  string    `json:"accountPool,omitempty"`
+	Claim        string    `json:"claim,omitempty"`
+}
+
+// ForAccount returns a notification of the given kind about the account
+func ForAccount(kind Kind, account *awsv1alpha1.Account, summary, message string) Notification {
+	notification := Notification{
+		Kind:         kind,
+		Summary:      summary,
+		Message:      message,
+		Time:         time.Now().UTC(),
+		Account:      account.Name,
+		AWSAccountID: account.Spec.AwsAccountID,
+		AccountPool:  account.Spec.AccountPool,
+	}
+	if account.Spec.ClaimLink != "" {
+		notification.Claim = account.Spec.ClaimLinkNamespace + "/" + account.Spec.ClaimLink
+	}
+	return notification
+}
+
+// ForAccountPool returns a notification of the given kind about the pool
+func ForAccountPool(kind Kind, pool *awsv1alpha1.AccountPool, summary, message string) Notification {
+	return Notification{
+		Kind:        kind,
+		Summary:     summary,
+		Message:     message,
+		Time:        time.Now().UTC(),
+		AccountPool: pool.Name,
+	}
+}
+
+// ForAccountClaim returns a notification of the given kind about the claim
+func ForAccountClaim(kind Kind, accountClaim *awsv1alpha1.AccountClaim, summary, message string) Notification {
+	return Notification{
+		Kind:        kind,
+		Summary:     summary,
+		Message:     message,
+		Time:        time.Now().UTC(),
+		AccountPool: accountClaim.Spec.AccountPool,
+		Claim:       accountClaim.Namespace + "/" + accountClaim.Name,
+	}
+}
+
+// Sink is a destination of the notifications
+type Sink interface {
+	// Name identifies the sink in the logs
+	Name() string
+	// Send delivers the notification, failing if it wasn't accepted
+	Send(ctx context.Context, notification Notification) error
+}
+
+// Default is the global notifier. It is nil, and notifying is a no-op, until it is initialized.
+var Default *Notifier
+
+// Notifier queues notifications and sends them to its sinks
+type Notifier struct {
+	sinks         []Sink
+	kinds         map[Kind]bool
+	notifications chan Notification
+}
+
+// New returns a Notifier sending the notifications of the given kinds, or of all kinds if none is given, to the sinks
+func New(sinks []Sink, kinds []Kind) *Notifier {
+	if len(kinds) == 0 {
+		kinds = Kinds
+	}
+	enabled := map[Kind]bool{}
+	for _, kind := range kinds {
+		enabled[kind] = true
+	}
+	return &Notifier{
+		sinks:         sinks,
+		kinds:         enabled,
+		notifications: make(chan Notification, maxPendingNotifications),
+	}
+}
+
+// ParseKinds parses a comma-separated list of notification kinds, failing on unknown kinds
+func ParseKinds(value string) ([]Kind, error) {
+	var kinds []Kind
+	for _, field := range strings.Split(value, ",") {
+		kind := Kind(strings.TrimSpace(field))
+		if kind == "" {
+			continue
+		}
+		known := false
+		for _, k := range Kinds {
+			if k == kind {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown notification kind %q", kind)
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
+}
+
+// Notify queues a notification. It does nothing if the notifier has not been initialized or the kind of the
+// notification isn't enabled, and never blocks: the notification is dropped if too many are waiting to be sent.
+func Notify(log logr.Logger, notification Notification) {
+	if Default == nil || !Default.kinds[notification.Kind] {
+		return
+	}
+	select {
+	case Default.notifications <- notification:
+	default:
+		log.Info("Too many notifications waiting to be sent, dropping the notification", "kind", notification.Kind, "summary", notification.Summary)
+	}
+}
+
+// Start sends the queued notifications to the sinks until stopCh is done. A notification is retried a few times
+// per sink before being dropped for that sink.
+func (n *Notifier) Start(log logr.Logger, stopCh context.Context) {
+	log.Info("Starting the notifier", "sinks", len(n.sinks))
+	for {
+		select {
+		case notification := <-n.notifications:
+			for _, sink := range n.sinks {
+				err := sendWithRetries(stopCh, sink, notification)
+				if err != nil {
+					log.Error(err, "Failed to send notification, dropping it", "sink", sink.Name(), "kind", notification.Kind)
+				}
+			}
+		case <-stopCh.Done():
+			log.Info("Stopping the notifier")
+			return
+		}
+	}
+}
+
+func sendWithRetries(ctx context.Context, sink Sink, notification Notification) error {
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err = sink.Send(ctx, notification)
+		if err == nil || attempt == maxAttempts {
+			break
+		}
+		select {
+		case <-time.After(retryDelay * time.Duration(attempt)):
+		case <-ctx.Done():
+			return err
+		}
+	}
+	return err
+}
+
+// post sends a JSON body to a URL, failing unless the server answers with a 2xx status
+func post(ctx context.Context, httpClient *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("server answered with status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// server records the JSON bodies it receives, failing the first requests when asked to
+type server struct {
+	mutex    sync.Mutex
+	failures int
+	bodies   []map[string]interface{}
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	body := map[string]interface{}{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.bodies = append(s.bodies, body)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *server) received() []map[string]interface{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]map[string]interface{}{}, s.bodies...)
+}
+
+var account = &awsv1alpha1.Account{
+	ObjectMeta: metav1.ObjectMeta{Name: "osd-creds-mgmt-aaabbb", Namespace: awsv1alpha1.AccountCrNamespace},
+	Spec: awsv1alpha1.AccountSpec{
+		AwsAccountID: "123456789012",
+		AccountPool:  "default",
+	},
+}
+
+func TestNotifyWithoutNotifier(t *testing.T) {
+	Default = nil
+	// Notifying without a notifier does nothing
+	Notify(testutils.NewTestLogger().Logger(), ForAccount(KindAccountFailed, account, "Account failed", ""))
+}
+
+func TestNotify(t *testing.T) {
+	webhook := &server{failures: 1}
+	webhookServer := httptest.NewServer(webhook)
+	defer webhookServer.Close()
+	slack := &server{}
+	slackServer := httptest.NewServer(slack)
+	defer slackServer.Close()
+	retryDelay = time.Millisecond
+
+	Default = New([]Sink{NewWebhookSink(webhookServer.URL), NewSlackSink(slackServer.URL, "#aao-alerts")}, []Kind{KindAccountFailed})
+	defer func() { Default = nil }()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go Default.Start(testutils.NewTestLogger().Logger(), ctx)
+
+	// Pool exhaustion isn't enabled, it's not sent
+	Notify(testutils.NewTestLogger().Logger(), ForAccountPool(KindPoolExhausted, &awsv1alpha1.AccountPool{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, "Pool exhausted", ""))
+	Notify(testutils.NewTestLogger().Logger(), ForAccount(KindAccountFailed, account, "Account osd-creds-mgmt-aaabbb failed", "CreationTimeout"))
+
+	// The first webhook request fails and is retried
+	assert.Eventually(t, func() bool { return len(webhook.received()) == 1 && len(slack.received()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	notification := webhook.received()[0]
+	assert.Equal(t, string(KindAccountFailed), notification["kind"])
+	assert.Equal(t, "Account osd-creds-mgmt-aaabbb failed", notification["summary"])
+	assert.Equal(t, "123456789012", notification["awsAccountID"])
+	assert.Equal(t, "default", notification["accountPool"])
+
+	message := slack.received()[0]
+	assert.Equal(t, "#aao-alerts", message["channel"])
+	assert.Contains(t, message["text"], "*Account osd-creds-mgmt-aaabbb failed*")
+	assert.Contains(t, message["text"], "AWS account: `123456789012`")
+}
+
+func TestParseKinds(t *testing.T) {
+	kinds, err := ParseKinds(" account-failed, pool-exhausted,")
+	assert.NoError(t, err)
+	assert.Equal(t, []Kind{KindAccountFailed, KindPoolExhausted}, kinds)
+
+	_, err = ParseKinds("account-failed,unknown")
+	assert.Error(t, err)
+}
+
+func TestPagerDutySink(t *testing.T) {
+	pagerDuty := &server{}
+	pagerDutyServer := httptest.NewServer(pagerDuty)
+	defer pagerDutyServer.Close()
+
+	sink := NewPagerDutySink("routing-key")
+	sink.url = pagerDutyServer.URL
+
+	// Pool exhaustion doesn't page
+	assert.NoError(t, sink.Send(context.Background(), ForAccountPool(KindPoolExhausted, &awsv1alpha1.AccountPool{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, "Pool exhausted", "")))
+	assert.Empty(t, pagerDuty.received())
+
+	assert.NoError(t, sink.Send(context.Background(), ForAccount(KindAccountFailed, account, "Account osd-creds-mgmt-aaabbb set to Failed", "")))
+	claim := &awsv1alpha1.AccountClaim{ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "cluster-ns"}}
+	assert.NoError(t, sink.Send(context.Background(), ForAccountClaim(KindClaimDeadlineExceeded, claim, "AccountClaim cluster-ns/claim not fulfilled", "")))
+
+	events := pagerDuty.received()
+	assert.Len(t, events, 2)
+	assert.Equal(t, "routing-key", events[0]["routing_key"])
+	assert.Equal(t, "trigger", events[0]["event_action"])
+	assert.Equal(t, "aws-account-operator/account/osd-creds-mgmt-aaabbb", events[0]["dedup_key"])
+	assert.Equal(t, "Account osd-creds-mgmt-aaabbb set to Failed", events[0]["payload"].(map[string]interface{})["summary"])
+	assert.Equal(t, "aws-account-operator/accountclaim/cluster-ns/claim", events[1]["dedup_key"])
+}
@@ -0,0 +1,156 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WebhookSink posts the notifications as JSON to a generic webhook
+type WebhookSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookSink returns a sink posting the notifications to the URL
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{url: url, httpClient: &http.Client{Timeout: sendTimeout}}
+}
+
+// Name implements Sink
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+// Send implements Sink
+func (s *WebhookSink) Send(ctx context.Context, notification Notification) error {
+	return post(ctx, s.httpClient, s.url, notification)
+}
+
+// SlackSink posts the notifications to a Slack incoming webhook
+type SlackSink struct {
+	url        string
+	channel    string
+	httpClient *http.Client
+}
+
+// slackMessage is the payload of a Slack incoming webhook
+type slackMessage struct {
+	Channel string `json:"channel,omitempty"`
+	Text    string `json:"text"`
+}
+
+// NewSlackSink returns a sink posting the notifications to the Slack incoming webhook URL. The channel overrides
+// the channel of the webhook when it's set.
+func NewSlackSink(url, channel string) *SlackSink {
+	return &SlackSink{url: url, channel: channel, httpClient: &http.Client{Timeout: sendTimeout}}
+}
+
+// Name implements Sink
+func (s *SlackSink) Name() string {
+	return "slack"
+}
+
+// Send implements Sink
+func (s *SlackSink) Send(ctx context.Context, notification Notification) error {
+	return post(ctx, s.httpClient, s.url, slackMessage{Channel: s.channel, Text: slackText(notification)})
+}
+
+// slackText formats a notification as a Slack message
+func slackText(notification Notification) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, ":rotating_light: *%s*", notification.Summary)
+	if notification.Message != "" {
+		fmt.Fprintf(&text, "\n%s", notification.Message)
+	}
+	fields := []struct{ name, value string }{
+		{"Account", notification.Account},
+		{"AWS account", notification.AWSAccountID},
+		{"Pool", notification.AccountPool},
+		{"Claim", notification.Claim},
+	}
+	for _, field := range fields {
+		if field.value != "" {
+			fmt.Fprintf(&text, "\n• %s: `%s`", field.name, field.value)
+		}
+	}
+	return text.String()
+}
+
+// pagerDutyEventsURL is the endpoint of the PagerDuty Events API v2
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// pagerDutyKinds are the kinds of notifications the PagerDuty sink pages for, the unrecoverable states that need
+// an SRE to act on them
+var pagerDutyKinds = map[Kind]bool{KindAccountFailed: true, KindCleanupQuarantined: true, KindClaimDeadlineExceeded: true}
+
+// PagerDutySink triggers PagerDuty incidents for the unrecoverable states of the accounts and the claims. Its other
+// notifications are ignored.
+type PagerDutySink struct {
+	url        string
+	routingKey string
+	httpClient *http.Client
+}
+
+// pagerDutyEvent is an event of the PagerDuty Events API v2
+type pagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	DedupKey    string           `json:"dedup_key"`
+	Payload     pagerDutyPayload `json:"payload"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string       `json:"summary"`
+	Source        string       `json:"source"`
+	Severity      string       `json:"severity"`
+	Timestamp     string       `json:"timestamp"`
+	Component     string       `json:"component,omitempty"`
+	Class         string       `json:"class"`
+	CustomDetails Notification `json:"custom_details"`
+}
+
+// NewPagerDutySink returns a sink triggering incidents on the service of the integration with the routing key
+func NewPagerDutySink(routingKey string) *PagerDutySink {
+	return &PagerDutySink{url: pagerDutyEventsURL, routingKey: routingKey, httpClient: &http.Client{Timeout: sendTimeout}}
+}
+
+// Name implements Sink
+func (s *PagerDutySink) Name() string {
+	return "pagerduty"
+}
+
+// Send implements Sink. The incidents are deduplicated per CR, so an account failing again while its incident is
+// open doesn't page again.
+func (s *PagerDutySink) Send(ctx context.Context, notification Notification) error {
+	if !pagerDutyKinds[notification.Kind] {
+		return nil
+	}
+	return post(ctx, s.httpClient, s.url, pagerDutyEvent{
+		RoutingKey:  s.routingKey,
+		EventAction: "trigger",
+		DedupKey:    dedupKey(notification),
+		Payload: pagerDutyPayload{
+			Summary:       notification.Summary,
+			Source:        "aws-account-operator",
+			Severity:      "critical",
+			Timestamp:     notification.Time.Format(time.RFC3339),
+			Component:     notification.Account,
+			Class:         string(notification.Kind),
+			CustomDetails: notification,
+		},
+	})
+}
+
+// dedupKey identifies the CR a notification is about
+func dedupKey(notification Notification) string {
+	switch {
+	case notification.Account != "":
+		return "aws-account-operator/account/" + notification.Account
+	case notification.Claim != "":
+		return "aws-account-operator/accountclaim/" + notification.Claim
+	}
+	return "aws-account-operator/accountpool/" + notification.AccountPool
+}
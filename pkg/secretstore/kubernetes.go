@@ -0,0 +1,61 @@
+package secretstore
+
+import (
+	"context"
+
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type kubernetesStore struct {
+	kubeClient client.Client
+}
+
+// NewKubernetesStore returns a Store keeping the credentials in Kubernetes Secrets
+func NewKubernetesStore(kubeClient client.Client) Store {
+	return &kubernetesStore{kubeClient: kubeClient}
+}
+
+func (s *kubernetesStore) Backend() string {
+	return BackendKubernetes
+}
+
+func (s *kubernetesStore) Get(ctx context.Context, key types.NamespacedName) (map[string][]byte, error) {
+	secret := &corev1.Secret{}
+	err := s.kubeClient.Get(ctx, key, secret)
+	if err != nil {
+		return nil, err
+	}
+	return secret.Data, nil
+}
+
+func (s *kubernetesStore) Put(ctx context.Context, secret *corev1.Secret) error {
+	err := s.kubeClient.Create(ctx, secret)
+	if !k8serr.IsAlreadyExists(err) {
+		return err
+	}
+
+	existing := &corev1.Secret{}
+	err = s.kubeClient.Get(ctx, client.ObjectKeyFromObject(secret), existing)
+	if err != nil {
+		return err
+	}
+	return utils.UpdateWithRetry(s.kubeClient, existing, func() error {
+		existing.Data = secret.Data
+		return nil
+	})
+}
+
+func (s *kubernetesStore) Delete(ctx context.Context, key types.NamespacedName) error {
+	secret := &corev1.Secret{}
+	secret.Name = key.Name
+	secret.Namespace = key.Namespace
+	err := s.kubeClient.Delete(ctx, secret)
+	if k8serr.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+// Package secretstore stores the AWS credentials handed to AccountClaims. By default they are
+// Kubernetes Secrets, the operator configmap can select HashiCorp Vault instead for consumers
+// that must not keep cloud keys in etcd.
+package secretstore
+
+import (
+	"context"
+	"fmt"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// BackendKubernetes stores the credentials in Kubernetes Secrets
+	BackendKubernetes = "kubernetes"
+	// BackendVault stores the credentials in a Vault KV version 2 secrets engine
+	BackendVault = "vault"
+)
+
+// Store reads and writes credentials. Secrets that don't exist are reported with a Kubernetes
+// NotFound error whatever the backend, so callers can check them with k8serr.IsNotFound.
+type Store interface {
+	// Backend returns the name of the backend the store writes to
+	Backend() string
+	// Get returns the data of the secret
+	Get(ctx context.Context, key types.NamespacedName) (map[string][]byte, error)
+	// Put creates the secret, or replaces its data if it already exists
+	Put(ctx context.Context, secret *corev1.Secret) error
+	// Delete deletes the secret, secrets that don't exist are ignored
+	Delete(ctx context.Context, key types.NamespacedName) error
+}
+
+// New returns the Store selected in the operator configmap. Without a configmap the credentials are
+// kept in Kubernetes Secrets.
+func New(kubeClient client.Client) (Store, error) {
+	configMap, err := utils.GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return NewKubernetesStore(kubeClient), nil
+		}
+		return nil, err
+	}
+
+	switch backend := configMap.Data[awsv1alpha1.SecretBackendConfigMapKey]; backend {
+	case "", BackendKubernetes:
+		return NewKubernetesStore(kubeClient), nil
+	case BackendVault:
+		config, err := vaultConfigFromConfigMap(configMap)
+		if err != nil {
+			return nil, err
+		}
+		return getVaultStore(config)
+	default:
+		return nil, fmt.Errorf("%w: unknown %s %q", awsv1alpha1.ErrInvalidConfigMap, awsv1alpha1.SecretBackendConfigMapKey, backend)
+	}
+}
+
+func notFound(key types.NamespacedName) error {
+	return k8serr.NewNotFound(corev1.Resource("secrets"), key.String())
+}
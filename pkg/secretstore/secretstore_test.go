@@ -0,0 +1,228 @@
+package secretstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var claimSecret = types.NamespacedName{Name: "aws", Namespace: "claim-namespace"}
+
+func newSecret(data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: claimSecret.Name, Namespace: claimSecret.Namespace},
+		Data:       data,
+	}
+}
+
+func operatorConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+		Data:       data,
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name            string
+		configMap       *corev1.ConfigMap
+		expectedBackend string
+		expectErr       bool
+	}{
+		{
+			name:            "No configmap",
+			expectedBackend: BackendKubernetes,
+		},
+		{
+			name:            "No backend configured",
+			configMap:       operatorConfigMap(nil),
+			expectedBackend: BackendKubernetes,
+		},
+		{
+			name: "Vault",
+			configMap: operatorConfigMap(map[string]string{
+				awsv1alpha1.SecretBackendConfigMapKey: BackendVault,
+				awsv1alpha1.VaultAddressConfigMapKey:  "https://vault.example.com",
+				awsv1alpha1.VaultRoleConfigMapKey:     "aws-account-operator",
+			}),
+			expectedBackend: BackendVault,
+		},
+		{
+			name: "Vault without address",
+			configMap: operatorConfigMap(map[string]string{
+				awsv1alpha1.SecretBackendConfigMapKey: BackendVault,
+				awsv1alpha1.VaultRoleConfigMapKey:     "aws-account-operator",
+			}),
+			expectErr: true,
+		},
+		{
+			name:      "Unknown backend",
+			configMap: operatorConfigMap(map[string]string{awsv1alpha1.SecretBackendConfigMapKey: "etcd"}),
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			builder := fakekubeclient.NewClientBuilder().WithScheme(scheme.Scheme)
+			if test.configMap != nil {
+				builder = builder.WithObjects(test.configMap)
+			}
+
+			store, err := New(builder.Build())
+			if test.expectErr {
+				assert.ErrorIs(t, err, awsv1alpha1.ErrInvalidConfigMap)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedBackend, store.Backend())
+		})
+	}
+}
+
+func TestKubernetesStore(t *testing.T) {
+	ctx := context.TODO()
+	store := NewKubernetesStore(fakekubeclient.NewClientBuilder().WithScheme(scheme.Scheme).Build())
+
+	_, err := store.Get(ctx, claimSecret)
+	assert.True(t, k8serr.IsNotFound(err))
+
+	require.NoError(t, store.Put(ctx, newSecret(map[string][]byte{"aws_access_key_id": []byte("AKIAOLD")})))
+	require.NoError(t, store.Put(ctx, newSecret(map[string][]byte{"aws_access_key_id": []byte("AKIANEW")})))
+	data, err := store.Get(ctx, claimSecret)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("AKIANEW"), data["aws_access_key_id"])
+
+	require.NoError(t, store.Delete(ctx, claimSecret))
+	require.NoError(t, store.Delete(ctx, claimSecret))
+	_, err = store.Get(ctx, claimSecret)
+	assert.True(t, k8serr.IsNotFound(err))
+}
+
+// fakeVault implements the Kubernetes auth login and the KV version 2 endpoints the store uses
+type fakeVault struct {
+	mutex   sync.Mutex
+	secrets map[string]map[string]string
+	logins  int
+	// revoked has the next request refused as if the token had been revoked
+	revoked bool
+	// status is returned instead of serving the KV endpoints when set
+	status int
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if r.URL.Path == "/v1/auth/kubernetes/login" {
+		login := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&login)
+		if login["role"] != "aws-account-operator" || login["jwt"] != "service-account-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		v.logins++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"auth": map[string]interface{}{"client_token": "vault-token", "lease_duration": 3600}})
+		return
+	}
+	if r.Header.Get("X-Vault-Token") != "vault-token" || v.revoked {
+		v.revoked = false
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if v.status != 0 {
+		w.WriteHeader(v.status)
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v1/secret/data/"):
+		path := strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")
+		switch r.Method {
+		case http.MethodGet:
+			data, ok := v.secrets[path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"data": data}})
+		case http.MethodPost, http.MethodPut:
+			body := struct {
+				Data map[string]string `json:"data"`
+			}{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			v.secrets[path] = body.Data
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"version": 1}})
+		}
+	case strings.HasPrefix(r.URL.Path, "/v1/secret/metadata/") && r.Method == http.MethodDelete:
+		delete(v.secrets, strings.TrimPrefix(r.URL.Path, "/v1/secret/metadata/"))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestVaultStore(t *testing.T) {
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("service-account-token\n"), 0600))
+	previousTokenPath := serviceAccountTokenPath
+	serviceAccountTokenPath = tokenPath
+	defer func() { serviceAccountTokenPath = previousTokenPath }()
+
+	vault := &fakeVault{secrets: map[string]map[string]string{}}
+	server := httptest.NewServer(vault)
+	defer server.Close()
+
+	kubeClient := fakekubeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(operatorConfigMap(map[string]string{
+		awsv1alpha1.SecretBackendConfigMapKey: BackendVault,
+		awsv1alpha1.VaultAddressConfigMapKey:  server.URL + "/",
+		awsv1alpha1.VaultRoleConfigMapKey:     "aws-account-operator",
+	})).Build()
+	store, err := New(kubeClient)
+	require.NoError(t, err)
+	ctx := context.TODO()
+
+	_, err = store.Get(ctx, claimSecret)
+	assert.True(t, k8serr.IsNotFound(err))
+
+	require.NoError(t, store.Put(ctx, newSecret(map[string][]byte{"aws_access_key_id": []byte("AKIANEW"), "aws_secret_access_key": []byte("secret")})))
+	assert.Equal(t, map[string]string{"aws_access_key_id": "AKIANEW", "aws_secret_access_key": "secret"}, vault.secrets["aws-account-operator/claim-namespace/aws"])
+
+	data, err := store.Get(ctx, claimSecret)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("AKIANEW"), data["aws_access_key_id"])
+
+	require.NoError(t, store.Delete(ctx, claimSecret))
+	assert.Empty(t, vault.secrets)
+
+	// The token is reused
+	assert.Equal(t, 1, vault.logins)
+
+	// A revoked token is replaced
+	require.NoError(t, store.Put(ctx, newSecret(map[string][]byte{"aws_access_key_id": []byte("AKIANEW")})))
+	vault.revoked = true
+	_, err = store.Get(ctx, claimSecret)
+	require.NoError(t, err)
+	assert.Equal(t, 2, vault.logins)
+
+	// Only a secret Vault doesn't have is reported as not found
+	vault.status = http.StatusBadRequest
+	_, err = store.Get(ctx, claimSecret)
+	assert.Error(t, err)
+	assert.False(t, k8serr.IsNotFound(err))
+}
@@ -0,0 +1,214 @@
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	defaultVaultAuthMount  = "kubernetes"
+	defaultVaultKVMount    = "secret"
+	defaultVaultPathPrefix = "aws-account-operator"
+
+	vaultClientTimeout = 10 * time.Second
+	// vaultTokenRenewMargin is how long before its lease ends a token is replaced by a new login
+	vaultTokenRenewMargin = time.Minute
+)
+
+// serviceAccountTokenPath is where the operator's service account token is mounted, it is used to
+// log in to Vault
+var serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token" // #nosec G101 -- This is a false positive
+
+type vaultConfig struct {
+	address    string
+	role       string
+	authMount  string
+	kvMount    string
+	pathPrefix string
+}
+
+func vaultConfigFromConfigMap(configMap *corev1.ConfigMap) (vaultConfig, error) {
+	config := vaultConfig{
+		address:    strings.TrimSuffix(configMap.Data[awsv1alpha1.VaultAddressConfigMapKey], "/"),
+		role:       configMap.Data[awsv1alpha1.VaultRoleConfigMapKey],
+		authMount:  valueOrDefault(configMap.Data[awsv1alpha1.VaultAuthMountConfigMapKey], defaultVaultAuthMount),
+		kvMount:    valueOrDefault(configMap.Data[awsv1alpha1.VaultKVMountConfigMapKey], defaultVaultKVMount),
+		pathPrefix: valueOrDefault(configMap.Data[awsv1alpha1.VaultPathPrefixConfigMapKey], defaultVaultPathPrefix),
+	}
+	if config.address == "" || config.role == "" {
+		return vaultConfig{}, fmt.Errorf("%w: %s and %s are required when %s is %s", awsv1alpha1.ErrInvalidConfigMap,
+			awsv1alpha1.VaultAddressConfigMapKey, awsv1alpha1.VaultRoleConfigMapKey, awsv1alpha1.SecretBackendConfigMapKey, BackendVault)
+	}
+	return config, nil
+}
+
+func valueOrDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return strings.Trim(value, "/")
+}
+
+var (
+	vaultStoresMutex sync.Mutex
+	// vaultStores are reused as long as the configuration doesn't change, so the Vault token is too
+	vaultStores = map[vaultConfig]*vaultStore{}
+)
+
+func getVaultStore(config vaultConfig) (*vaultStore, error) {
+	vaultStoresMutex.Lock()
+	defer vaultStoresMutex.Unlock()
+	if store, ok := vaultStores[config]; ok {
+		return store, nil
+	}
+
+	clientConfig := vault.DefaultConfig()
+	if clientConfig.Error != nil {
+		return nil, clientConfig.Error
+	}
+	clientConfig.Address = config.address
+	clientConfig.Timeout = vaultClientTimeout
+	client, err := vault.NewClient(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	// The token is only set by the Kubernetes auth login, not taken from the environment
+	client.ClearToken()
+
+	store := &vaultStore{config: config, client: client}
+	vaultStores[config] = store
+	return store, nil
+}
+
+// vaultStore keeps the credentials in a Vault KV version 2 secrets engine, at
+// <kv mount>/data/<path prefix>/<namespace>/<name>. The operator logs in with the Kubernetes auth method.
+type vaultStore struct {
+	config vaultConfig
+	client *vault.Client
+
+	loginMutex  sync.Mutex
+	tokenExpiry time.Time
+}
+
+func (s *vaultStore) Backend() string {
+	return BackendVault
+}
+
+func (s *vaultStore) Get(ctx context.Context, key types.NamespacedName) (map[string][]byte, error) {
+	var secret *vault.KVSecret
+	err := s.withLogin(ctx, func() error {
+		var err error
+		secret, err = s.client.KVv2(s.config.kvMount).Get(ctx, s.secretPath(key))
+		return err
+	})
+	if errors.Is(err, vault.ErrSecretNotFound) {
+		return nil, notFound(key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Deleted versions are still returned, without data
+	if secret.Data == nil {
+		return nil, notFound(key)
+	}
+
+	data := map[string][]byte{}
+	for k, v := range secret.Data {
+		value, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("value of %s in vault secret %s is not a string", k, s.secretPath(key))
+		}
+		data[k] = []byte(value)
+	}
+	return data, nil
+}
+
+func (s *vaultStore) Put(ctx context.Context, secret *corev1.Secret) error {
+	data := map[string]interface{}{}
+	for k, v := range secret.Data {
+		data[k] = string(v)
+	}
+	for k, v := range secret.StringData {
+		data[k] = v
+	}
+	return s.withLogin(ctx, func() error {
+		_, err := s.client.KVv2(s.config.kvMount).Put(ctx, s.secretPath(types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}), data)
+		return err
+	})
+}
+
+func (s *vaultStore) Delete(ctx context.Context, key types.NamespacedName) error {
+	// Deleting the metadata removes every version of the secret
+	return s.withLogin(ctx, func() error {
+		return s.client.KVv2(s.config.kvMount).DeleteMetadata(ctx, s.secretPath(key))
+	})
+}
+
+func (s *vaultStore) secretPath(key types.NamespacedName) string {
+	return fmt.Sprintf("%s/%s/%s", s.config.pathPrefix, key.Namespace, key.Name)
+}
+
+// withLogin calls Vault once logged in. The token may have been revoked, a call that is forbidden is retried once
+// after logging in again.
+func (s *vaultStore) withLogin(ctx context.Context, call func() error) error {
+	if err := s.login(ctx, false); err != nil {
+		return err
+	}
+	err := call()
+	var responseErr *vault.ResponseError
+	if !errors.As(err, &responseErr) || responseErr.StatusCode != http.StatusForbidden {
+		return err
+	}
+	if err := s.login(ctx, true); err != nil {
+		return err
+	}
+	return call()
+}
+
+// login logs in with the Kubernetes auth method unless the client has a token that isn't about to expire
+func (s *vaultStore) login(ctx context.Context, force bool) error {
+	s.loginMutex.Lock()
+	defer s.loginMutex.Unlock()
+	if !force && s.client.Token() != "" && time.Now().Before(s.tokenExpiry) {
+		return nil
+	}
+
+	secret, err := s.client.Auth().Login(ctx, &kubernetesAuth{mount: s.config.authMount, role: s.config.role})
+	if err != nil {
+		return fmt.Errorf("vault login with role %s failed: %w", s.config.role, err)
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return fmt.Errorf("vault login with role %s returned no token", s.config.role)
+	}
+	s.tokenExpiry = time.Now().Add(time.Duration(secret.Auth.LeaseDuration)*time.Second - vaultTokenRenewMargin)
+	return nil
+}
+
+// kubernetesAuth is the Kubernetes auth method, logging in with the operator's service account token
+type kubernetesAuth struct {
+	mount string
+	role  string
+}
+
+// Login implements vault.AuthMethod
+func (a *kubernetesAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	jwt, err := os.ReadFile(serviceAccountTokenPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account token to log in to vault: %w", err)
+	}
+	return client.Logical().WriteWithContext(ctx, fmt.Sprintf("auth/%s/login", a.mount), map[string]interface{}{
+		"role": a.role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+}
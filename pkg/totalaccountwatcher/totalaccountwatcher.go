@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -35,9 +36,11 @@ type AccountWatcherIface interface {
 }
 
 type AccountWatcher struct {
-	watchInterval        time.Duration
-	awsClient            awsclient.Client
-	client               client.Client
+	watchInterval time.Duration
+	awsClient     awsclient.Client
+	client        client.Client
+	// mutex guards the fields below, which are read by the concurrent account reconciles
+	mutex                sync.RWMutex
 	total                int
 	accountsCanBeCreated bool
 	limit                int
@@ -113,28 +116,36 @@ func (s *AccountWatcher) UpdateTotalAccounts(log logr.Logger) error {
 	if err != nil {
 		log.Error(err, "Failed to get account list with error code")
 		// Stop account creation while we can't talk to AWS
-		s.accountsCanBeCreated = false
+		s.setAccountsCanBeCreated(false)
 		return err
 	}
 	localmetrics.Collector.SetTotalAWSAccounts(accountTotal)
 
+	s.mutex.Lock()
 	if accountTotal != s.total {
 		log.Info(fmt.Sprintf("Updating total from %d to %d", s.total, accountTotal))
 		s.total = accountTotal
 	}
+	s.mutex.Unlock()
 
 	// AccountsCanBeCreated is a bool that returns the opposite of accountLimitReached.
 	// If the account limit is reached, we do NOT want to create accounts.  However, if the
 	// account limit has NOT been reached, then account creation can happen.
 	limitReached, err := s.accountLimitReached(log, accountTotal)
 	if err != nil {
-		s.accountsCanBeCreated = false
+		s.setAccountsCanBeCreated(false)
 		return err
 	}
-	s.accountsCanBeCreated = (!limitReached)
+	s.setAccountsCanBeCreated(!limitReached)
 	return nil
 }
 
+func (s *AccountWatcher) setAccountsCanBeCreated(accountsCanBeCreated bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.accountsCanBeCreated = accountsCanBeCreated
+}
+
 // TotalAwsAccounts returns the total number of aws accounts in the aws org
 func (s *AccountWatcher) getTotalAwsAccounts() (int, error) {
 	var nextToken *string
@@ -170,16 +181,22 @@ func (s *AccountWatcher) getTotalAwsAccounts() (int, error) {
 
 // AccountsCanBeCreated returns whether we can create accounts or not
 func (s *AccountWatcher) AccountsCanBeCreated() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	return s.accountsCanBeCreated
 }
 
 // GetAccountCount returns the number of accounts that are currently recorded.
 func (s *AccountWatcher) GetAccountCount() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	return s.total
 }
 
 // GetLimit returns the soft limit we have set in the configmap
 func (s *AccountWatcher) GetLimit() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	return s.limit
 }
 
@@ -212,6 +229,8 @@ func (s *AccountWatcher) getAwsAccountLimit() (int, error) {
 	}
 
 	// persist the limit
+	s.mutex.Lock()
 	s.limit = limit
+	s.mutex.Unlock()
 	return limit, nil
 }
@@ -0,0 +1,261 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// Standard OpenTelemetry SDK environment variables
+	otlpEndpointEnvVar       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	otlpTracesEndpointEnvVar = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	serviceNameEnvVar        = "OTEL_SERVICE_NAME"
+
+	defaultServiceName  = "aws-account-operator"
+	instrumentationName = "github.com/openshift/aws-account-operator/pkg/tracing"
+
+	// Spans are buffered and sent in batches. Spans are dropped rather than slowing down reconciles
+	// when the collector can't keep up.
+	exportQueueSize     = 2048
+	exportBatchSize     = 512
+	exportInterval      = 5 * time.Second
+	exportClientTimeout = 10 * time.Second
+)
+
+// defaultExporter is set by InitFromEnv and cleared by Shutdown, while reconciles read it to start and export spans
+var defaultExporter atomic.Pointer[exporter]
+
+type exporter struct {
+	endpoint    string
+	serviceName string
+	httpClient  *http.Client
+	log         logr.Logger
+
+	queue chan *Span
+	done  chan struct{}
+
+	// mutex guards closed, so no span is queued once the queue is closed
+	mutex  sync.RWMutex
+	closed bool
+}
+
+// InitFromEnv enables tracing if OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT is
+// set, exporting spans to it using OTLP/HTTP with JSON encoding. It must be called before any
+// controller starts. Returns true if tracing was enabled.
+func InitFromEnv(log logr.Logger) bool {
+	endpoint := os.Getenv(otlpTracesEndpointEnvVar)
+	if endpoint == "" {
+		base := os.Getenv(otlpEndpointEnvVar)
+		if base == "" {
+			return false
+		}
+		endpoint = strings.TrimSuffix(base, "/") + "/v1/traces"
+	}
+
+	serviceName := os.Getenv(serviceNameEnvVar)
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	e := newExporter(endpoint, serviceName, log)
+	go e.run()
+	defaultExporter.Store(e)
+	log.Info("Tracing enabled", "endpoint", endpoint, "serviceName", serviceName)
+	return true
+}
+
+// Shutdown exports any queued spans and disables tracing
+func Shutdown(ctx context.Context) {
+	e := defaultExporter.Swap(nil)
+	if e == nil {
+		return
+	}
+	e.mutex.Lock()
+	e.closed = true
+	close(e.queue)
+	e.mutex.Unlock()
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+	}
+}
+
+func newExporter(endpoint, serviceName string, log logr.Logger) *exporter {
+	return &exporter{
+		endpoint:    endpoint,
+		serviceName: serviceName,
+		httpClient:  &http.Client{Timeout: exportClientTimeout},
+		log:         log,
+		queue:       make(chan *Span, exportQueueSize),
+		done:        make(chan struct{}),
+	}
+}
+
+func (e *exporter) export(span *Span) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	// The queue is closed on shutdown, spans ended afterwards are dropped
+	if e.closed {
+		return
+	}
+	select {
+	case e.queue <- span:
+	default:
+	}
+}
+
+func (e *exporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, exportBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.send(batch); err != nil {
+			e.log.Error(err, "Failed to export spans", "spans", len(batch))
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case span, ok := <-e.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, span)
+			if len(batch) >= exportBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (e *exporter) send(spans []*Span) error {
+	body, err := json.Marshal(e.encode(spans))
+	if err != nil {
+		return err
+	}
+	resp, err := e.httpClient.Post(e.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("collector returned status %s", resp.Status)
+	}
+	return nil
+}
+
+// The types below are the subset of the OTLP JSON encoding the exporter needs, see
+// https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              spanKind        `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+const otlpStatusCodeError = 2
+
+func (e *exporter) encode(spans []*Span) otlpRequest {
+	encoded := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		s := otlpSpan{
+			TraceID:           hex.EncodeToString(span.traceID[:]),
+			SpanID:            hex.EncodeToString(span.spanID[:]),
+			Name:              span.name,
+			Kind:              span.kind,
+			StartTimeUnixNano: strconv.FormatInt(span.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.end.UnixNano(), 10),
+			Attributes:        encodeAttributes(span.Attributes()),
+		}
+		if span.parentID != [8]byte{} {
+			s.ParentSpanID = hex.EncodeToString(span.parentID[:])
+		}
+		if span.err != nil {
+			s.Status = otlpStatus{Code: otlpStatusCodeError, Message: span.err.Error()}
+		}
+		encoded = append(encoded, s)
+	}
+
+	return otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: encodeAttributes([]Attribute{String("service.name", e.serviceName)}),
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: instrumentationName},
+				Spans: encoded,
+			}},
+		}},
+	}
+}
+
+func encodeAttributes(attributes []Attribute) []otlpAttribute {
+	encoded := make([]otlpAttribute, 0, len(attributes))
+	for _, attribute := range attributes {
+		encoded = append(encoded, otlpAttribute{Key: attribute.Key, Value: otlpValue{StringValue: attribute.Value}})
+	}
+	return encoded
+}
@@ -0,0 +1,157 @@
+// Package tracing records spans for reconciles and the AWS API calls they make, and exports them to an
+// OpenTelemetry collector over OTLP/HTTP. Tracing is disabled unless an OTLP endpoint is configured, in
+// which case every function in this package is a no-op and spans are nil.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Attribute is a key/value pair attached to a span
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// String returns an Attribute with the given key and value
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+type spanKind int
+
+const (
+	// Values of the OTLP SpanKind enum
+	spanKindInternal spanKind = 1
+	spanKindClient   spanKind = 3
+)
+
+// Span is a timed operation within a trace. A nil *Span is valid and does nothing, which is what
+// Start returns while tracing is disabled.
+type Span struct {
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	kind     spanKind
+	start    time.Time
+	end      time.Time
+
+	mutex      sync.Mutex
+	attributes []Attribute
+	err        error
+	ended      bool
+}
+
+type spanContextKey struct{}
+
+// Start creates a span as a child of the span in ctx, if any, and returns a context holding the new
+// span. Child spans inherit the attributes of their parent, so AWS calls made while reconciling an
+// Account or AccountClaim carry its name.
+func Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, *Span) {
+	if defaultExporter.Load() == nil {
+		return ctx, nil
+	}
+	span := newSpan(SpanFromContext(ctx), name, spanKindInternal, time.Now(), attributes)
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// RecordClientSpan records an already finished call to an external API as a child of the span in ctx
+func RecordClientSpan(ctx context.Context, name string, start time.Time, err error, attributes ...Attribute) {
+	if defaultExporter.Load() == nil {
+		return
+	}
+	newSpan(SpanFromContext(ctx), name, spanKindClient, start, attributes).End(err)
+}
+
+// SpanFromContext returns the span held by ctx, or nil if there is none
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+func newSpan(parent *Span, name string, kind spanKind, start time.Time, attributes []Attribute) *Span {
+	span := &Span{
+		name:  name,
+		kind:  kind,
+		start: start,
+	}
+	if parent != nil {
+		span.traceID = parent.traceID
+		span.parentID = parent.spanID
+		span.attributes = parent.Attributes()
+	} else {
+		_, _ = rand.Read(span.traceID[:])
+	}
+	_, _ = rand.Read(span.spanID[:])
+	span.SetAttributes(attributes...)
+	return span
+}
+
+// SetAttributes adds attributes to the span, replacing any existing attributes with the same key
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, attribute := range attributes {
+		replaced := false
+		for i := range s.attributes {
+			if s.attributes[i].Key == attribute.Key {
+				s.attributes[i].Value = attribute.Value
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.attributes = append(s.attributes, attribute)
+		}
+	}
+}
+
+// Attributes returns a copy of the span's attributes
+func (s *Span) Attributes() []Attribute {
+	if s == nil {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Attribute(nil), s.attributes...)
+}
+
+// TraceID returns the hex encoded ID of the trace the span belongs to
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return hex.EncodeToString(s.traceID[:])
+}
+
+// End finishes the span, marking it as failed if err is not nil, and queues it for export.
+// Calling End more than once has no effect.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	if s.ended {
+		s.mutex.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.err = err
+	s.mutex.Unlock()
+
+	if e := defaultExporter.Load(); e != nil {
+		e.export(s)
+	}
+}
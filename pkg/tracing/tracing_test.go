@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestStartWhenDisabled(t *testing.T) {
+	ctx, span := Start(context.Background(), "Reconcile")
+	if span != nil {
+		t.Fatalf("expected no span while tracing is disabled")
+	}
+	if SpanFromContext(ctx) != nil {
+		t.Fatalf("expected no span in context while tracing is disabled")
+	}
+	// A nil span must be safe to use
+	span.SetAttributes(String("account", "osd-creds-mgmt-abcdef"))
+	span.End(nil)
+}
+
+func TestExportSpans(t *testing.T) {
+	requests := make(chan otlpRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var request otlpRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		requests <- request
+	}))
+	defer server.Close()
+
+	t.Setenv(otlpEndpointEnvVar, server.URL)
+	if !InitFromEnv(logr.Discard()) {
+		t.Fatalf("expected tracing to be enabled")
+	}
+
+	ctx, parent := Start(context.Background(), "Reconcile AccountClaim", String("accountclaim", "test-claim"))
+	parent.SetAttributes(String("account", "osd-creds-mgmt-abcdef"))
+	RecordClientSpan(ctx, "ec2.DescribeSnapshots", time.Now(), errors.New("throttled"))
+	parent.End(nil)
+	Shutdown(context.Background())
+
+	var request otlpRequest
+	select {
+	case request = <-requests:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for spans to be exported")
+	}
+
+	spans := request.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	child, root := spans[0], spans[1]
+	if root.ParentSpanID != "" {
+		t.Errorf("expected root span to have no parent, got %s", root.ParentSpanID)
+	}
+	if child.TraceID != root.TraceID || child.ParentSpanID != root.SpanID {
+		t.Errorf("expected child span to belong to the root span")
+	}
+	if child.Kind != spanKindClient || child.Status.Code != otlpStatusCodeError || child.Status.Message != "throttled" {
+		t.Errorf("unexpected child span %+v", child)
+	}
+	attributes := map[string]string{}
+	for _, attribute := range child.Attributes {
+		attributes[attribute.Key] = attribute.Value.StringValue
+	}
+	if attributes["accountclaim"] != "test-claim" || attributes["account"] != "osd-creds-mgmt-abcdef" {
+		t.Errorf("expected child span to inherit the parent's attributes, got %v", attributes)
+	}
+}
+
+func TestEndDuringShutdown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	t.Setenv(otlpEndpointEnvVar, server.URL)
+	if !InitFromEnv(logr.Discard()) {
+		t.Fatalf("expected tracing to be enabled")
+	}
+
+	// Spans ended by reconciles still running when the operator shuts down are dropped
+	spans := make([]*Span, 0, 100)
+	for i := 0; i < cap(spans); i++ {
+		_, span := Start(context.Background(), "Reconcile Account")
+		spans = append(spans, span)
+	}
+	ended := make(chan struct{})
+	go func() {
+		defer close(ended)
+		for _, span := range spans {
+			span.End(nil)
+		}
+	}()
+	Shutdown(context.Background())
+	<-ended
+
+	if _, span := Start(context.Background(), "Reconcile Account"); span != nil {
+		t.Errorf("expected no span once tracing is shut down")
+	}
+}
@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// requeueBackoffBase is the delay before the first retry of a failed reconcile
+	requeueBackoffBase = 5 * time.Second
+	// requeueBackoffMax caps the delay between retries so a CR is never forgotten for too long
+	requeueBackoffMax = 15 * time.Minute
+	// requeueBackoffJitter is the fraction of the delay that is randomized, so CRs that failed
+	// together (e.g. on a throttled AWS API) don't all retry at the same instant
+	requeueBackoffJitter = 0.2
+)
+
+// ReconcileBackoff returns how long to wait before reconciling again after the given number of
+// consecutive failures. The delay doubles with each failure up to requeueBackoffMax, with jitter.
+func ReconcileBackoff(failures int) time.Duration {
+	if failures < 1 {
+		failures = 1
+	}
+	backoff := float64(requeueBackoffBase) * math.Pow(2, float64(failures-1))
+	if backoff > float64(requeueBackoffMax) {
+		backoff = float64(requeueBackoffMax)
+	}
+	// #nosec G404 -- jitter does not need a cryptographically secure source
+	jitter := backoff * requeueBackoffJitter * (rand.Float64()*2 - 1)
+	return time.Duration(backoff + jitter)
+}
+
+// RequeueWithBackoff requeues after the backoff delay for the given number of consecutive failures
+func RequeueWithBackoff(failures int) (reconcile.Result, error) {
+	return reconcile.Result{RequeueAfter: ReconcileBackoff(failures)}, nil
+}
+
+// ReconcileOutcomeRecorder is implemented by CRs that keep their observed generation and a
+// consecutive reconcile failure counter in their status
+type ReconcileOutcomeRecorder interface {
+	client.Object
+	// RecordReconcileOutcome updates the status for a failed or successful reconcile and returns
+	// true if anything changed
+	RecordReconcileOutcome(failed bool) bool
+	// GetReconcileFailures returns the number of consecutive failed reconciles
+	GetReconcileFailures() int
+}
+
+// UpdateReconcileOutcome re-reads the CR and records the outcome of the reconcile that just
+// finished in its status, along with whether the CR is stuck in its current state. A failed
+// reconcile is logged and requeued with RequeueWithBackoff for the consecutive failures of the CR,
+// rather than returning its error: the controller's rate limiter would retry it within
+// milliseconds. The error is only passed through when the CR can't be read. A reconcile
+// failed because of maintenance or observe-only mode is requeued after
+// MaintenanceModeRequeueDelay without counting a failure. A reconcile interrupted by an operator
+// shutdown doesn't count as a failure either. A successful result is shortened, if
+// needed, so the CR is reconciled again when it would become stuck.
+func UpdateReconcileOutcome(kubeClient client.Client, reqLogger logr.Logger, key types.NamespacedName, obj ReconcileOutcomeRecorder, result reconcile.Result, reconcileErr error) (reconcile.Result, error) {
+	// Operations paused by maintenance mode or only planned in observe-only mode aren't failures,
+	// they are retried once the mode is lifted
+	if IsMaintenanceModeError(reconcileErr) || IsObserveOnlyError(reconcileErr) {
+		reqLogger.Info("Reconcile paused", "reason", reconcileErr.Error())
+		result, reconcileErr = reconcile.Result{RequeueAfter: MaintenanceModeRequeueDelay}, nil
+	}
+	// Work stopped by an operator shutdown resumes when the CR is reconciled after the restart
+	if IsShuttingDownError(reconcileErr) {
+		reqLogger.Info("Reconcile interrupted by the operator shutdown")
+		result, reconcileErr = reconcile.Result{Requeue: true}, nil
+	}
+
+	err := kubeClient.Get(context.TODO(), key, obj)
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			reqLogger.Error(err, "Failed to get CR to record reconcile outcome")
+		}
+		return result, reconcileErr
+	}
+
+	configMap, err := GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		reqLogger.Error(err, "Failed retrieving configmap, using default stuck thresholds")
+		configMap = nil
+	}
+
+	changed := obj.RecordReconcileOutcome(reconcileErr != nil)
+	stuckChanged, untilStuck := updateStuckCondition(obj, configMap)
+	if changed || stuckChanged {
+		err = kubeClient.Status().Update(context.TODO(), obj)
+		if err != nil {
+			reqLogger.Error(err, "Failed to record reconcile outcome in status")
+		}
+	}
+
+	if reconcileErr != nil {
+		reqLogger.Error(reconcileErr, "Reconcile failed, retrying with backoff", "failures", obj.GetReconcileFailures())
+		return RequeueWithBackoff(obj.GetReconcileFailures())
+	}
+	if untilStuck > 0 && !result.Requeue && (result.RequeueAfter == 0 || result.RequeueAfter > untilStuck) {
+		result.RequeueAfter = untilStuck
+	}
+	return result, nil
+}
+
+// IgnoreReconcileOutcomeUpdates filters out update events that only change the reconcile outcome
+// recorded in status. Without it, recording a failure would trigger another reconcile straight
+// away and bypass the backoff.
+var IgnoreReconcileOutcomeUpdates = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !onlyReconcileOutcomeChanged(e.ObjectOld, e.ObjectNew)
+	},
+}
+
+func onlyReconcileOutcomeChanged(oldObj, newObj client.Object) bool {
+	if oldObj == nil || newObj == nil {
+		return false
+	}
+	oldFields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(oldObj)
+	if err != nil {
+		return false
+	}
+	newFields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(newObj)
+	if err != nil {
+		return false
+	}
+	for _, fields := range []map[string]interface{}{oldFields, newFields} {
+		if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "resourceVersion")
+			delete(metadata, "managedFields")
+		}
+		if status, ok := fields["status"].(map[string]interface{}); ok {
+			delete(status, "observedGeneration")
+			delete(status, "reconcileFailures")
+			delete(status, "stateTransition")
+		}
+	}
+	return reflect.DeepEqual(oldFields, newFields)
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+)
+
+func TestReconcileBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		want     time.Duration
+	}{
+		{name: "no failures uses the base delay", failures: 0, want: requeueBackoffBase},
+		{name: "first failure uses the base delay", failures: 1, want: requeueBackoffBase},
+		{name: "delay doubles with each failure", failures: 3, want: 4 * requeueBackoffBase},
+		{name: "delay is capped", failures: 50, want: requeueBackoffMax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minimum := time.Duration(float64(tt.want) * (1 - requeueBackoffJitter))
+			maximum := time.Duration(float64(tt.want) * (1 + requeueBackoffJitter))
+			got := ReconcileBackoff(tt.failures)
+			if got < minimum || got > maximum {
+				t.Errorf("ReconcileBackoff(%d) = %v, want between %v and %v", tt.failures, got, minimum, maximum)
+			}
+		})
+	}
+}
+
+func TestUpdateReconcileOutcome(t *testing.T) {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	key := types.NamespacedName{Name: "test", Namespace: awsv1alpha1.AccountCrNamespace}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&awsv1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		Status:     awsv1alpha1.AccountStatus{ReconcileFailures: 2},
+	}).Build()
+
+	// A failure is retried with the backoff of the consecutive failures, not by the rate limiter
+	result, err := UpdateReconcileOutcome(kubeClient, logr.Discard(), key, &awsv1alpha1.Account{}, reconcile.Result{Requeue: true}, errors.New("throttled"))
+	if err != nil {
+		t.Fatalf("UpdateReconcileOutcome() error = %v, want nil", err)
+	}
+	minimum := time.Duration(float64(4*requeueBackoffBase) * (1 - requeueBackoffJitter))
+	if result.Requeue || result.RequeueAfter < minimum {
+		t.Errorf("UpdateReconcileOutcome() = %+v, want a requeue after at least %v", result, minimum)
+	}
+
+	// Requeues of a successful reconcile are left alone
+	result, err = UpdateReconcileOutcome(kubeClient, logr.Discard(), key, &awsv1alpha1.Account{}, reconcile.Result{Requeue: true}, nil)
+	if err != nil || !result.Requeue || result.RequeueAfter != 0 {
+		t.Errorf("UpdateReconcileOutcome() = %+v, %v, want an immediate requeue", result, err)
+	}
+}
+
+func TestOnlyReconcileOutcomeChanged(t *testing.T) {
+	oldAccount := &awsv1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", ResourceVersion: "1"},
+		Status:     awsv1alpha1.AccountStatus{State: "Creating"},
+	}
+
+	outcomeOnly := oldAccount.DeepCopy()
+	outcomeOnly.ResourceVersion = "2"
+	outcomeOnly.Status.ReconcileFailures = 1
+	if !onlyReconcileOutcomeChanged(oldAccount, outcomeOnly) {
+		t.Error("expected an update of only the reconcile outcome to be detected")
+	}
+
+	stateChanged := outcomeOnly.DeepCopy()
+	stateChanged.Status.State = "Ready"
+	if onlyReconcileOutcomeChanged(oldAccount, stateChanged) {
+		t.Error("expected a state change not to be filtered")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -18,16 +20,25 @@ import (
 
 var ControllerMaxReconciles map[string]int = map[string]int{}
 
+// controllerMaxReconcilesMutex guards ControllerMaxReconciles
+var controllerMaxReconcilesMutex sync.RWMutex
+
+// maxReconcilesControllers are the controllers whose MaxConcurrentReconciles can be configured
+var maxReconcilesControllers = []string{
+	"account",
+	"accountclaim",
+	"accountcleanup",
+	"accountpool",
+	"accountpoolvalidation",
+	"accountvalidation",
+	"awsaccountquota",
+	"awsfederatedaccessgroup",
+	"awsfederatedaccountaccess",
+	"awsfederatedrole",
+	"iamuserrequest",
+}
+
 func InitControllerMaxReconciles(kubeClient client.Client) []error {
-	controllers := []string{
-		"account",
-		"accountclaim",
-		"accountpool",
-		"accountpoolvalidation",
-		"accountvalidation",
-		"awsfederatedaccountaccess",
-		"awsfederatedrole",
-	}
 	controllerErrors := []error{}
 	cm, err := GetOperatorConfigMap(kubeClient)
 	if err != nil {
@@ -35,29 +46,68 @@ func InitControllerMaxReconciles(kubeClient client.Client) []error {
 		return controllerErrors
 	}
 
-	for _, controller := range controllers {
+	for _, controller := range maxReconcilesControllers {
 		val, err := getControllerMaxReconcilesFromCM(cm, controller)
 		if err != nil {
 			controllerErrors = append(controllerErrors, fmt.Errorf("Error getting Max Reconciles for %s controller", controller))
 			continue
 		}
-		ControllerMaxReconciles[controller] = val
+		setControllerMaxReconciles(controller, val)
 	}
 
 	return controllerErrors
 }
 
+// OverrideControllerMaxReconciles sets the max reconciles of the controllers given in a comma separated list of
+// controller=value pairs, e.g. "account=10,accountclaim=4", such as the --max-concurrent-reconciles flag. They take
+// precedence over the configmap.
+func OverrideControllerMaxReconciles(overrides string) error {
+	if overrides == "" {
+		return nil
+	}
+	for _, override := range strings.Split(overrides, ",") {
+		controller, value, found := strings.Cut(strings.TrimSpace(override), "=")
+		if !found {
+			return fmt.Errorf("invalid max reconciles %q, expected controller=value", override)
+		}
+		if !Contains(maxReconcilesControllers, controller) {
+			return fmt.Errorf("invalid max reconciles %q, unknown controller %s", override, controller)
+		}
+		val, err := strconv.Atoi(value)
+		if err != nil || val < 1 {
+			return fmt.Errorf("invalid max reconciles %q, expected a positive number", override)
+		}
+		setControllerMaxReconciles(controller, val)
+	}
+	return nil
+}
+
+func setControllerMaxReconciles(controllerName string, val int) {
+	controllerMaxReconcilesMutex.Lock()
+	defer controllerMaxReconcilesMutex.Unlock()
+	ControllerMaxReconciles[controllerName] = val
+}
+
 // getControllerMaxReconcilesFromCM gets the max reconciles for a given controller out of the config map
 func getControllerMaxReconcilesFromCM(cm *corev1.ConfigMap, controllerName string) (int, error) {
 	cmKey := fmt.Sprintf("MaxConcurrentReconciles.%s", controllerName)
 	if val, ok := cm.Data[cmKey]; ok {
-		return strconv.Atoi(val)
+		maxReconciles, err := strconv.Atoi(val)
+		if err != nil {
+			return 0, err
+		}
+		if maxReconciles < 1 {
+			return 0, fmt.Errorf("%s must be positive, got %d", cmKey, maxReconciles)
+		}
+		return maxReconciles, nil
 	}
 	return 0, awsv1alpha1.ErrInvalidConfigMap
 }
 
 // GetControllerMaxReconciles gets the default configMap and then gets the amount of concurrent reconciles to run from it
 func GetControllerMaxReconciles(controllerName string) (int, error) {
+	controllerMaxReconcilesMutex.RLock()
+	defer controllerMaxReconcilesMutex.RUnlock()
 	if _, ok := ControllerMaxReconciles[controllerName]; !ok {
 		return 1, fmt.Errorf("Controller %s not present in config data", controllerName)
 	}
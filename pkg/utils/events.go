@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reasons of the Events emitted for significant Account, AccountClaim, AccountCleanup and CleanupVerification
+// lifecycle transitions
+const (
+	EventReasonAccountCreated       = "AccountCreated"
+	EventReasonClaimBound           = "ClaimBound"
+	EventReasonCleanupQueued        = "CleanupQueued"
+	EventReasonCleanupStarted       = "CleanupStarted"
+	EventReasonCleanupStepFailed    = "CleanupStepFailed"
+	EventReasonCleanupInterrupted   = "CleanupInterrupted"
+	EventReasonCleanupFailed        = "CleanupFailed"
+	EventReasonManualActionRequired = "ManualActionRequired"
+	EventReasonReuseCompleted       = "ReuseCompleted"
+	EventReasonCredentialsRotated   = "CredentialsRotated"
+	EventReasonIAMUserMissing       = "IAMUserMissing"
+	EventReasonIAMUserRecreated     = "IAMUserRecreated"
+	EventReasonAccountQuarantined   = "AccountQuarantined"
+	EventReasonAccountParked        = "AccountParked"
+	EventReasonAccountUnparked      = "AccountUnparked"
+	EventReasonIAMDriftDetected     = "IAMDriftDetected"
+	EventReasonIAMDriftResolved     = "IAMDriftResolved"
+	EventReasonRootAccessKeys       = "RootAccessKeysPresent"
+	EventReasonSecretsDeleted       = "SecretsDeleted"
+	EventReasonNotInOrganization    = "NotInOrganization"
+	EventReasonAccountAdopted       = "AccountAdopted"
+	EventReasonAWSHealthIssue       = "AWSHealthIssue"
+	EventReasonAWSHealthResolved    = "AWSHealthResolved"
+	EventReasonBudgetAlarm          = "BudgetAlarm"
+	EventReasonIdleCost             = "IdleCost"
+	EventReasonPreflightFailed      = "PreflightFailed"
+	EventReasonCloudTrailBroken     = "CloudTrailBroken"
+	EventReasonCloudTrailRestored   = "CloudTrailRestored"
+	EventReasonAccountHibernated    = "AccountHibernated"
+	EventReasonPlannedAction        = "PlannedAction"
+	EventReasonClusterDeleted       = "ClusterDeploymentDeleted"
+	EventReasonClaimTimedOut        = "ClaimTimedOut"
+	EventReasonClaimExpired         = "ClaimExpired"
+	EventReasonAccountOnDemand      = "AccountCreatedOnDemand"
+	EventReasonVerificationPassed   = "CleanupVerificationPassed"
+	EventReasonVerificationFailed   = "CleanupVerificationFailed"
+	EventReasonAccountRepooled      = "AccountRepooled"
+	EventReasonRepoolRefused        = "RepoolRefused"
+	EventReasonIAMRebuilt           = "IAMRebuilt"
+	EventReasonIAMRebuildRefused    = "IAMRebuildRefused"
+	EventReasonEntityReassigned     = "LegalEntityReassigned"
+	EventReasonReassignRefused      = "LegalEntityReassignmentRefused"
+	EventReasonAdHocCleanupQueued   = "AdHocCleanupQueued"
+	EventReasonAdHocCleanupRefused  = "AdHocCleanupRefused"
+	EventReasonClaimTransferred     = "ClaimTransferred"
+	EventReasonTransferRefused      = "ClaimTransferRefused"
+)
+
+// RecordEvent emits an Event for the object. Reconcilers built without a recorder, like in the
+// unit tests, don't emit anything.
+func RecordEvent(recorder record.EventRecorder, object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(object, eventtype, reason, messageFmt, args...)
+}
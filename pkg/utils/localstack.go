@@ -0,0 +1,27 @@
+package utils
+
+import "os"
+
+// LocalStackEndpointEnvVar is the environment variable starting the operator in LocalStack mode when set to the URL
+// of a LocalStack endpoint, e.g. http://localhost:4566
+const LocalStackEndpointEnvVar = "LOCALSTACK_ENDPOINT"
+
+// localStackEndpoint is set for the whole life of the operator, like observe-only mode
+var localStackEndpoint = os.Getenv(LocalStackEndpointEnvVar)
+
+// IsLocalStack checks whether the operator runs in LocalStack mode: the AWS clients call the LocalStack endpoint
+// instead of AWS, and the Organizations calls, which LocalStack doesn't emulate, are simulated in memory.
+func IsLocalStack() bool {
+	return localStackEndpoint != ""
+}
+
+// LocalStackEndpoint returns the URL of the LocalStack endpoint, empty unless the operator runs in LocalStack mode
+func LocalStackEndpoint() string {
+	return localStackEndpoint
+}
+
+// SetLocalStackEndpoint overrides the LocalStack endpoint set by LocalStackEndpointEnvVar, an empty endpoint
+// disables LocalStack mode
+func SetLocalStackEndpoint(endpoint string) {
+	localStackEndpoint = endpoint
+}
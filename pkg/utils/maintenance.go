@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrCodeMaintenanceMode is the error code of the AWS operations rejected while the operator is in maintenance mode
+const ErrCodeMaintenanceMode = "OperatorMaintenanceMode"
+
+// MaintenanceModeRequeueDelay is how long the reconciles stopped by maintenance mode wait before being retried
+const MaintenanceModeRequeueDelay = 5 * time.Minute
+
+// ErrMaintenanceMode indicates an operation was skipped because the operator is in maintenance mode
+var ErrMaintenanceMode = awserr.New(ErrCodeMaintenanceMode, "mutating AWS operations are paused by maintenance mode", nil)
+
+// IsMaintenanceMode checks whether maintenance mode is enabled in the operator configmap. It's disabled when the
+// configmap can't be read.
+func IsMaintenanceMode(kubeClient client.Client) bool {
+	configMap, err := GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		return false
+	}
+	enabled, err := strconv.ParseBool(configMap.Data[awsv1alpha1.MaintenanceModeConfigMapKey])
+	return err == nil && enabled
+}
+
+// IsMaintenanceModeError checks whether err was caused by maintenance mode
+func IsMaintenanceModeError(err error) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == ErrCodeMaintenanceMode
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ObserveOnlyEnvVar is the environment variable starting the operator in observe-only mode when set to true
+const ObserveOnlyEnvVar = "OBSERVE_ONLY"
+
+// ErrCodeObserveOnly is the error code of the mutating AWS operations only planned in observe-only mode
+const ErrCodeObserveOnly = "OperatorObserveOnly"
+
+// observeOnly is set for the whole life of the operator, unlike maintenance mode which can be toggled at runtime
+var observeOnly = GetEnvironmentBool(ObserveOnlyEnvVar, false)
+
+// IsObserveOnly checks whether the operator runs in observe-only mode: it makes the read-only AWS calls as usual and
+// records the mutating calls it would have made as planned actions, but doesn't make them.
+func IsObserveOnly() bool {
+	return observeOnly
+}
+
+// SetObserveOnly overrides the observe-only mode set by ObserveOnlyEnvVar
+func SetObserveOnly(enabled bool) {
+	observeOnly = enabled
+}
+
+// IsObserveOnlyError checks whether err was caused by an AWS operation only planned in observe-only mode
+func IsObserveOnlyError(err error) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == ErrCodeObserveOnly
+}
+
+// IsAWSMutationPaused checks whether the operator is in maintenance or observe-only mode, in which case account
+// lifecycle transitions which can't be left half done, e.g. creating or cleaning up accounts, are postponed
+func IsAWSMutationPaused(kubeClient client.Client) bool {
+	return IsObserveOnly() || IsMaintenanceMode(kubeClient)
+}
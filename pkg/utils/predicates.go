@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"reflect"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// SpecOrMetadataChanged filters out the update events that don't change the spec (the generation), the labels,
+// the annotations or the finalizers of an object, nor start its deletion. The controllers that don't act on the
+// status of the objects they watch use it, so their own status updates don't trigger another reconcile.
+var SpecOrMetadataChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.ObjectOld == nil || e.ObjectNew == nil {
+			return true
+		}
+		oldObj, newObj := e.ObjectOld, e.ObjectNew
+		return oldObj.GetGeneration() != newObj.GetGeneration() ||
+			!reflect.DeepEqual(oldObj.GetLabels(), newObj.GetLabels()) ||
+			!reflect.DeepEqual(oldObj.GetAnnotations(), newObj.GetAnnotations()) ||
+			!reflect.DeepEqual(oldObj.GetFinalizers(), newObj.GetFinalizers()) ||
+			!oldObj.GetDeletionTimestamp().Equal(newObj.GetDeletionTimestamp())
+	},
+}
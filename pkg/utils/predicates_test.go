@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestSpecOrMetadataChanged(t *testing.T) {
+	oldPool := &awsv1alpha1.AccountPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", ResourceVersion: "1", Generation: 1},
+		Spec:       awsv1alpha1.AccountPoolSpec{PoolSize: 5},
+	}
+
+	statusOnly := oldPool.DeepCopy()
+	statusOnly.ResourceVersion = "2"
+	statusOnly.Status.UnclaimedAccounts = 3
+	if SpecOrMetadataChanged.Update(event.UpdateEvent{ObjectOld: oldPool, ObjectNew: statusOnly}) {
+		t.Error("expected a status update to be filtered")
+	}
+
+	specChanged := statusOnly.DeepCopy()
+	specChanged.Generation = 2
+	specChanged.Spec.PoolSize = 10
+	if !SpecOrMetadataChanged.Update(event.UpdateEvent{ObjectOld: oldPool, ObjectNew: specChanged}) {
+		t.Error("expected a spec change not to be filtered")
+	}
+
+	labelChanged := statusOnly.DeepCopy()
+	labelChanged.Labels = map[string]string{"label": "value"}
+	if !SpecOrMetadataChanged.Update(event.UpdateEvent{ObjectOld: oldPool, ObjectNew: labelChanged}) {
+		t.Error("expected a label change not to be filtered")
+	}
+
+	finalizerAdded := statusOnly.DeepCopy()
+	finalizerAdded.Finalizers = []string{Finalizer}
+	if !SpecOrMetadataChanged.Update(event.UpdateEvent{ObjectOld: oldPool, ObjectNew: finalizerAdded}) {
+		t.Error("expected a finalizer change not to be filtered")
+	}
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// SetSecretOwner labels and annotates a secret as created by the operator for the owner CR. When
+// they're in the same namespace the owner is also set as the controller of the secret, so the secret
+// is garbage collected with it. Secrets in other namespaces are found by their labels and annotations.
+func SetSecretOwner(secret *corev1.Secret, owner client.Object, scheme *runtime.Scheme) error {
+	gvk, err := apiutil.GVKForObject(owner, scheme)
+	if err != nil {
+		return err
+	}
+
+	AddLabels(secret, map[string]string{
+		awsv1alpha1.ManagedByLabel:       awsv1alpha1.ManagedByOperator,
+		awsv1alpha1.SecretOwnerKindLabel: gvk.Kind,
+	})
+	annotations := secret.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[awsv1alpha1.SecretOwnerNameAnnotation] = owner.GetName()
+	annotations[awsv1alpha1.SecretOwnerNamespaceAnnotation] = owner.GetNamespace()
+	secret.SetAnnotations(annotations)
+
+	if secret.Namespace != owner.GetNamespace() {
+		return nil
+	}
+	return controllerutil.SetControllerReference(owner, secret, scheme)
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+)
+
+// The shard of this operator replica and the number of shards, set by the --shard-id and --shard-count flags.
+// With a single shard, the default, one replica reconciles everything.
+var (
+	shardID    = 0
+	shardCount = 1
+)
+
+// ConfigureSharding sets the shard of this operator replica
+func ConfigureSharding(id, count int) error {
+	if count < 1 {
+		return fmt.Errorf("the shard count must be at least 1, got %d", count)
+	}
+	if id < 0 || id >= count {
+		return fmt.Errorf("the shard ID must be between 0 and %d, got %d", count-1, id)
+	}
+	shardID, shardCount = id, count
+	return nil
+}
+
+// IsSharded checks whether the Accounts and AccountClaims are split between several operator replicas
+func IsSharded() bool {
+	return shardCount > 1
+}
+
+// IsPrimaryShard checks whether this replica runs the work that isn't split between shards: the controllers of
+// the CRs other than Accounts and AccountClaims, and the routines that act on the whole fleet
+func IsPrimaryShard() bool {
+	return shardID == 0
+}
+
+// ShardOf returns the shard owning the Account or AccountClaim with the given name
+func ShardOf(name string) int {
+	hash := fnv.New32a()
+	// Writing to a hash never fails
+	_, _ = hash.Write([]byte(name))
+	return int(hash.Sum32() % uint32(shardCount))
+}
+
+// InShard checks whether the Account or AccountClaim with the given name is reconciled by this replica
+func InShard(name string) bool {
+	return ShardOf(name) == shardID
+}
+
+// ShardLockName returns the name of the leader election lock of this shard. The replicas of the same shard
+// elect a leader among themselves, so each shard has exactly one active replica.
+func ShardLockName(lockName string) string {
+	if !IsSharded() {
+		return lockName
+	}
+	return fmt.Sprintf("%s-shard-%d", lockName, shardID)
+}
+
+// SetShardLabel labels obj with the shard that owns it and returns true if the label changed. The label isn't
+// used to pick the shard, it lets the Accounts and AccountClaims of a shard be listed.
+func SetShardLabel(obj client.Object) bool {
+	if !IsSharded() {
+		return false
+	}
+	shard := strconv.Itoa(ShardOf(obj.GetName()))
+	labels := obj.GetLabels()
+	if labels[awsv1alpha1.ShardLabel] == shard {
+		return false
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[awsv1alpha1.ShardLabel] = shard
+	obj.SetLabels(labels)
+	return true
+}
+
+// InShardFilter filters out the events of the Accounts and AccountClaims reconciled by other replicas
+var InShardFilter = predicate.NewPredicateFuncs(func(obj client.Object) bool {
+	return InShard(obj.GetName())
+})
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSharding(t *testing.T) {
+	defer func() { _ = ConfigureSharding(0, 1) }()
+
+	if err := ConfigureSharding(3, 3); err == nil {
+		t.Error("expected a shard ID out of range to be rejected")
+	}
+
+	// Every name belongs to exactly one shard
+	owners := map[string]int{}
+	for id := 0; id < 3; id++ {
+		if err := ConfigureSharding(id, 3); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 100; i++ {
+			name := fmt.Sprintf("osd-creds-mgmt-%d", i)
+			if InShard(name) {
+				owners[name]++
+			}
+		}
+	}
+	for i := 0; i < 100; i++ {
+		name := fmt.Sprintf("osd-creds-mgmt-%d", i)
+		if owners[name] != 1 {
+			t.Errorf("expected %s to belong to 1 shard, got %d", name, owners[name])
+		}
+	}
+
+	if ShardLockName("lock") != "lock-shard-2" {
+		t.Errorf("expected a lock per shard, got %s", ShardLockName("lock"))
+	}
+
+	account := &awsv1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: "osd-creds-mgmt-1"}}
+	if !SetShardLabel(account) || account.Labels[awsv1alpha1.ShardLabel] != fmt.Sprint(ShardOf(account.Name)) {
+		t.Errorf("expected the account to be labelled with its shard, got %v", account.Labels)
+	}
+	if SetShardLabel(account) {
+		t.Error("expected an account labelled with its shard to be left alone")
+	}
+
+	if err := ConfigureSharding(0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if !InShard("osd-creds-mgmt-1") || ShardLockName("lock") != "lock" {
+		t.Error("expected a single shard to own everything with the default lock")
+	}
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultGracefulShutdownTimeout is how long the operator waits for the in-flight reconciles, e.g. account cleanups,
+// to finish when it's stopped, unless the --graceful-shutdown-timeout flag is set
+const DefaultGracefulShutdownTimeout = 2 * time.Minute
+
+// ErrShuttingDown indicates work wasn't started or completed because the operator is shutting down
+var ErrShuttingDown = errors.New("the operator is shutting down")
+
+// IsShuttingDown checks whether ctx, the context of a reconcile or a background routine, was canceled because the
+// operator is shutting down
+func IsShuttingDown(ctx context.Context) bool {
+	return ctx.Err() != nil
+}
+
+// IsShuttingDownError checks whether err was caused by the operator shutting down
+func IsShuttingDownError(err error) bool {
+	return errors.Is(err, ErrShuttingDown)
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// stuckThresholdKeyPrefix prefixes the operator configmap keys overriding how long a CR may stay in
+	// a state, e.g. "stuck-threshold.account.Creating: 3h". A threshold of 0 disables the check.
+	stuckThresholdKeyPrefix = "stuck-threshold"
+
+	stuckReason    = "TimeInStateExceeded"
+	notStuckReason = "TimeInStateWithinThreshold"
+)
+
+// defaultStuckThresholds are how long an Account or AccountClaim may stay in a state before it is
+// considered stuck. States that aren't listed are never considered stuck unless configured.
+var defaultStuckThresholds = map[string]map[string]time.Duration{
+	"account": {
+		string(awsv1alpha1.AccountCreating):            2 * time.Hour,
+		awsv1alpha1.AccountInitializingRegions:         2 * time.Hour,
+		string(awsv1alpha1.AccountOptingInRegions):     24 * time.Hour,
+		string(awsv1alpha1.AccountPendingVerification): 24 * time.Hour,
+	},
+	"accountclaim": {
+		string(awsv1alpha1.ClaimStatusPending): 2 * time.Hour,
+	},
+}
+
+// GetStuckThreshold returns how long a CR of the given kind may stay in the given state before it is
+// considered stuck, and false if there is no threshold for that state
+func GetStuckThreshold(configMap *corev1.ConfigMap, kind string, state string) (time.Duration, bool) {
+	threshold, ok := defaultStuckThresholds[kind][state]
+
+	if configMap != nil {
+		key := fmt.Sprintf("%s.%s.%s", stuckThresholdKeyPrefix, kind, state)
+		if value, found := configMap.Data[key]; found {
+			configured, err := time.ParseDuration(value)
+			if err != nil {
+				log.Error(err, "Invalid stuck threshold in configmap, using the default", "key", key)
+			} else {
+				threshold, ok = configured, true
+			}
+		}
+	}
+
+	return threshold, ok && threshold > 0
+}
+
+// updateStuckCondition sets or clears the Stuck condition depending on how long the CR has been in its
+// current state. Returns true if the condition changed, and how long until the CR would become stuck.
+func updateStuckCondition(obj ReconcileOutcomeRecorder, configMap *corev1.ConfigMap) (bool, time.Duration) {
+	switch cr := obj.(type) {
+	case *awsv1alpha1.Account:
+		threshold, ok := GetStuckThreshold(configMap, "account", cr.Status.State)
+		timeInState := cr.Status.StateTransition.TimeInState()
+		stuck := ok && timeInState > threshold
+
+		existing := cr.GetCondition(awsv1alpha1.AccountStuck)
+		if stuck != (existing != nil && existing.Status == corev1.ConditionTrue) {
+			status, reason, message := stuckConditionValues(stuck, "Account", cr.Status.State, timeInState, threshold)
+			cr.Status.Conditions = SetAccountCondition(cr.Status.Conditions, awsv1alpha1.AccountStuck, status, reason, message, UpdateConditionIfReasonOrMessageChange, cr.Spec.BYOC)
+			return true, 0
+		}
+		return false, timeUntilStuck(ok, stuck, timeInState, threshold)
+	case *awsv1alpha1.AccountClaim:
+		state := string(cr.Status.State)
+		threshold, ok := GetStuckThreshold(configMap, "accountclaim", state)
+		timeInState := cr.Status.StateTransition.TimeInState()
+		stuck := ok && timeInState > threshold
+
+		existing := FindAccountClaimCondition(cr.Status.Conditions, awsv1alpha1.AccountClaimStuck)
+		if stuck != (existing != nil && existing.Status == corev1.ConditionTrue) {
+			status, reason, message := stuckConditionValues(stuck, "AccountClaim", state, timeInState, threshold)
+			cr.Status.Conditions = SetAccountClaimCondition(cr.Status.Conditions, awsv1alpha1.AccountClaimStuck, status, reason, message, UpdateConditionIfReasonOrMessageChange, cr.Spec.BYOC)
+			return true, 0
+		}
+		return false, timeUntilStuck(ok, stuck, timeInState, threshold)
+	}
+	return false, 0
+}
+
+func stuckConditionValues(stuck bool, kind string, state string, timeInState time.Duration, threshold time.Duration) (corev1.ConditionStatus, string, string) {
+	if !stuck {
+		return corev1.ConditionFalse, notStuckReason, fmt.Sprintf("%s is no longer stuck", kind)
+	}
+	message := fmt.Sprintf("%s has been in state %s for %s, longer than the %s threshold", kind, state, timeInState.Round(time.Minute), threshold)
+	return corev1.ConditionTrue, stuckReason, message
+}
+
+func timeUntilStuck(hasThreshold bool, stuck bool, timeInState time.Duration, threshold time.Duration) time.Duration {
+	if !hasThreshold || stuck {
+		return 0
+	}
+	return threshold - timeInState
+}
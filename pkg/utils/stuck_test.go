@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+)
+
+func TestGetStuckThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      map[string]string
+		kind      string
+		state     string
+		expected  time.Duration
+		expectsOK bool
+	}{
+		{
+			name:      "default threshold",
+			kind:      "account",
+			state:     "Creating",
+			expected:  2 * time.Hour,
+			expectsOK: true,
+		},
+		{
+			name:      "no threshold for state",
+			kind:      "account",
+			state:     "Ready",
+			expectsOK: false,
+		},
+		{
+			name:      "configured threshold",
+			data:      map[string]string{"stuck-threshold.accountclaim.Pending": "30m"},
+			kind:      "accountclaim",
+			state:     "Pending",
+			expected:  30 * time.Minute,
+			expectsOK: true,
+		},
+		{
+			name:      "invalid threshold falls back to the default",
+			data:      map[string]string{"stuck-threshold.account.Creating": "soon"},
+			kind:      "account",
+			state:     "Creating",
+			expected:  2 * time.Hour,
+			expectsOK: true,
+		},
+		{
+			name:      "zero disables the check",
+			data:      map[string]string{"stuck-threshold.account.Creating": "0s"},
+			kind:      "account",
+			state:     "Creating",
+			expectsOK: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configMap := &corev1.ConfigMap{Data: test.data}
+			threshold, ok := GetStuckThreshold(configMap, test.kind, test.state)
+			if ok != test.expectsOK {
+				t.Errorf("GetStuckThreshold() ok = %v, want %v", ok, test.expectsOK)
+			}
+			if ok && threshold != test.expected {
+				t.Errorf("GetStuckThreshold() = %v, want %v", threshold, test.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateStuckCondition(t *testing.T) {
+	account := &awsv1alpha1.Account{
+		Status: awsv1alpha1.AccountStatus{
+			State: string(awsv1alpha1.AccountCreating),
+			StateTransition: &awsv1alpha1.StateTransition{
+				State:              string(awsv1alpha1.AccountCreating),
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-3 * time.Hour)),
+			},
+		},
+	}
+
+	changed, _ := updateStuckCondition(account, nil)
+	if !changed {
+		t.Fatal("expected the Stuck condition to be set")
+	}
+	condition := account.GetCondition(awsv1alpha1.AccountStuck)
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		t.Fatalf("expected a true Stuck condition, got %v", condition)
+	}
+
+	changed, _ = updateStuckCondition(account, nil)
+	if changed {
+		t.Error("expected no change while the account is still stuck")
+	}
+
+	account.Status.State = string(awsv1alpha1.AccountReady)
+	account.Status.StateTransition = awsv1alpha1.NewStateTransition(account.Status.State)
+	changed, untilStuck := updateStuckCondition(account, nil)
+	if !changed {
+		t.Fatal("expected the Stuck condition to be cleared")
+	}
+	if untilStuck != 0 {
+		t.Errorf("expected no stuck requeue for a Ready account, got %v", untilStuck)
+	}
+	condition = account.GetCondition(awsv1alpha1.AccountStuck)
+	if condition.Status != corev1.ConditionFalse {
+		t.Errorf("expected a false Stuck condition, got %v", condition.Status)
+	}
+}
+
+func TestUpdateStuckConditionRequeuesUntilStuck(t *testing.T) {
+	accountClaim := &awsv1alpha1.AccountClaim{
+		Status: awsv1alpha1.AccountClaimStatus{
+			State: awsv1alpha1.ClaimStatusPending,
+			StateTransition: &awsv1alpha1.StateTransition{
+				State:              string(awsv1alpha1.ClaimStatusPending),
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+			},
+		},
+	}
+
+	changed, untilStuck := updateStuckCondition(accountClaim, nil)
+	if changed {
+		t.Error("expected no Stuck condition before the threshold")
+	}
+	if untilStuck <= 0 || untilStuck > time.Hour {
+		t.Errorf("expected to requeue within the hour left before the threshold, got %v", untilStuck)
+	}
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"context"
+	"reflect"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UpdateWithRetry applies mutate to obj and updates it. When the update conflicts with a concurrent
+// change, obj is re-read and mutate applied again, so mutate has to set every field it changes rather
+// than rely on changes made to obj before the call.
+func UpdateWithRetry(kubeClient client.Client, obj client.Object, mutate func() error) error {
+	return retryOnConflict(kubeClient, obj, mutate, func() error {
+		return kubeClient.Update(context.TODO(), obj)
+	})
+}
+
+// UpdateStatusWithRetry is UpdateWithRetry for the status subresource
+func UpdateStatusWithRetry(kubeClient client.Client, obj client.Object, mutate func() error) error {
+	return retryOnConflict(kubeClient, obj, mutate, func() error {
+		return kubeClient.Status().Update(context.TODO(), obj)
+	})
+}
+
+// UpdateAccountConditionsWithRetry writes the status of the account with the conditions of the given types as they
+// are set on it. When the update conflicts, the account is re-read and only these conditions are set again, so the
+// concurrent changes to the rest of its status are kept.
+func UpdateAccountConditionsWithRetry(kubeClient client.Client, account *awsv1alpha1.Account, conditionTypes ...awsv1alpha1.AccountConditionType) error {
+	var conditions []awsv1alpha1.AccountCondition
+	for _, conditionType := range conditionTypes {
+		if condition := account.GetCondition(conditionType); condition != nil {
+			conditions = append(conditions, *condition)
+		}
+	}
+	return UpdateStatusWithRetry(kubeClient, account, func() error {
+		for _, condition := range conditions {
+			if existing := account.GetCondition(condition.Type); existing != nil {
+				*existing = condition
+			} else {
+				account.Status.Conditions = append(account.Status.Conditions, condition)
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateStatus writes the status of obj, unless obj is the version of the object in the cache and its status
+// wasn't changed. Skipping these spurious writes spares the API calls, and the conflicts they cause with the
+// other controllers and routines updating the object.
+func UpdateStatus(kubeClient client.Client, obj client.Object) error {
+	cached := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
+	err := kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(obj), cached)
+	if err == nil && cached.GetResourceVersion() == obj.GetResourceVersion() && sameStatus(cached, obj) {
+		return nil
+	}
+	return kubeClient.Status().Update(context.TODO(), obj)
+}
+
+func sameStatus(oldObj, newObj client.Object) bool {
+	oldFields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(oldObj)
+	if err != nil {
+		return false
+	}
+	newFields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(newObj)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldFields["status"], newFields["status"])
+}
+
+func retryOnConflict(kubeClient client.Client, obj client.Object, mutate func() error, update func() error) error {
+	key := client.ObjectKeyFromObject(obj)
+	attempt := 0
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// The first attempt uses the object the caller has, it's only re-read after a conflict
+		if attempt > 0 {
+			err := kubeClient.Get(context.TODO(), key, obj)
+			if err != nil {
+				return err
+			}
+		}
+		attempt++
+
+		err := mutate()
+		if err != nil {
+			return err
+		}
+		return update()
+	})
+}
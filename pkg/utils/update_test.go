@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Conflict-safe updates", func() {
+	var (
+		kubeClient client.Client
+		secret     *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		kubeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "namespace"},
+			Data:       map[string][]byte{"aws_access_key_id": []byte("AKIAOLD")},
+		}).Build()
+
+		secret = &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKey{Name: "secret", Namespace: "namespace"}, secret)).To(Succeed())
+	})
+
+	It("Updates the object", func() {
+		Expect(UpdateWithRetry(kubeClient, secret, func() error {
+			secret.Data["aws_access_key_id"] = []byte("AKIANEW")
+			return nil
+		})).To(Succeed())
+
+		updated := &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(secret), updated)).To(Succeed())
+		Expect(updated.Data["aws_access_key_id"]).To(Equal([]byte("AKIANEW")))
+	})
+
+	It("Re-reads the object and applies the change again after a conflict", func() {
+		// Someone else updates the secret after it was read
+		concurrent := secret.DeepCopy()
+		concurrent.Labels = map[string]string{"concurrent": "true"}
+		Expect(kubeClient.Update(context.TODO(), concurrent)).To(Succeed())
+
+		attempts := 0
+		Expect(UpdateWithRetry(kubeClient, secret, func() error {
+			attempts++
+			secret.Data["aws_access_key_id"] = []byte("AKIANEW")
+			return nil
+		})).To(Succeed())
+		Expect(attempts).To(Equal(2))
+
+		updated := &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(secret), updated)).To(Succeed())
+		Expect(updated.Data["aws_access_key_id"]).To(Equal([]byte("AKIANEW")))
+		Expect(updated.Labels).To(HaveKeyWithValue("concurrent", "true"))
+	})
+})
+
+var _ = Describe("Conflict-safe condition updates", func() {
+	It("Sets the conditions again on the account re-read after a conflict", func() {
+		Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&awsv1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "account", Namespace: "namespace"},
+		}).Build()
+		account := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKey{Name: "account", Namespace: "namespace"}, account)).To(Succeed())
+
+		// Someone else updates the status after the account was read
+		concurrent := account.DeepCopy()
+		concurrent.Status.State = string(awsv1alpha1.AccountReady)
+		Expect(kubeClient.Status().Update(context.TODO(), concurrent)).To(Succeed())
+
+		account.Status.Conditions = SetAccountCondition(account.Status.Conditions, awsv1alpha1.AccountDegraded, corev1.ConditionTrue, "Drift", "drifted", UpdateConditionIfReasonOrMessageChange, false)
+		Expect(UpdateAccountConditionsWithRetry(kubeClient, account, awsv1alpha1.AccountDegraded)).To(Succeed())
+
+		updated := &awsv1alpha1.Account{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(account), updated)).To(Succeed())
+		Expect(updated.Status.State).To(Equal(string(awsv1alpha1.AccountReady)))
+		Expect(updated.GetCondition(awsv1alpha1.AccountDegraded).Message).To(Equal("drifted"))
+	})
+})
+
+var _ = Describe("Status writer", func() {
+	var (
+		kubeClient client.Client
+		namespace  *corev1.Namespace
+	)
+
+	BeforeEach(func() {
+		kubeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "namespace"},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		}).Build()
+
+		namespace = &corev1.Namespace{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKey{Name: "namespace"}, namespace)).To(Succeed())
+	})
+
+	It("Skips the update of an unchanged status", func() {
+		resourceVersion := namespace.ResourceVersion
+		Expect(UpdateStatus(kubeClient, namespace)).To(Succeed())
+		Expect(namespace.ResourceVersion).To(Equal(resourceVersion))
+	})
+
+	It("Updates a changed status", func() {
+		namespace.Status.Phase = corev1.NamespaceTerminating
+		Expect(UpdateStatus(kubeClient, namespace)).To(Succeed())
+
+		updated := &corev1.Namespace{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(namespace), updated)).To(Succeed())
+		Expect(updated.Status.Phase).To(Equal(corev1.NamespaceTerminating))
+	})
+})
@@ -19,6 +19,7 @@ import (
 	"github.com/go-logr/logr"
 	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
@@ -112,6 +113,15 @@ type AwsPolicy struct {
 	Statement []AwsStatement
 }
 
+// MaxManagedPolicySize is the size limit of the document of a customer managed policy, whitespace excluded
+const MaxManagedPolicySize = 6144
+
+// MaxAttachedRolePolicies is the default quota of managed policies attached to an IAM role
+const MaxAttachedRolePolicies = 10
+
+// ErrPolicyStatementTooLarge is returned when a single statement doesn't fit in a customer managed policy
+var ErrPolicyStatementTooLarge = errors.New("PolicyStatementTooLarge")
+
 // GetServiceQuotasFromAccountPool retrieves and processes the account pool's service quotas from ConfigMap
 func GetServiceQuotasFromAccountPool(reqLogger logr.Logger, accountPoolName string, client client.Client) (awsv1alpha1.RegionalServiceQuotas, error) {
 	reqLogger.Info("Loading Service Quotas")
@@ -144,9 +154,14 @@ func GetServiceQuotasFromAccountPool(reqLogger logr.Logger, accountPoolName stri
 
 	var parsedRegionalServiceQuotas = make(awsv1alpha1.RegionalServiceQuotas)
 
+	requiredServiceQuotas, err := getRequiredServiceQuotas(cm)
+	if err != nil {
+		reqLogger.Error(err, "Failed to parse required service quotas", "key", awsv1alpha1.RequiredServiceQuotasConfigMapKey)
+		return nil, err
+	}
+
 	if poolData, ok := data[accountPoolName]; !ok {
-		reqLogger.Info("Accountpool not found in configmap. Not setting servicequotas.")
-		return parsedRegionalServiceQuotas, nil
+		reqLogger.Info("Accountpool not found in configmap. Only setting the required servicequotas.")
 	} else {
 		// for each service quota in a given region, we'll need to parse and save to use in the account spec.
 		for regionName, serviceQuotas := range poolData.RegionedServicequotas {
@@ -161,17 +176,258 @@ func GetServiceQuotasFromAccountPool(reqLogger logr.Logger, accountPoolName stri
 		}
 	}
 
+	// The required service quotas are requested in all regions, unless the pool asks for another value
+	if len(requiredServiceQuotas) > 0 {
+		if _, ok := parsedRegionalServiceQuotas["default"]; !ok {
+			parsedRegionalServiceQuotas["default"] = make(awsv1alpha1.AccountServiceQuota)
+		}
+		for quotaCode, quota := range requiredServiceQuotas {
+			if _, ok := parsedRegionalServiceQuotas["default"][quotaCode]; !ok {
+				parsedRegionalServiceQuotas["default"][quotaCode] = quota
+			}
+		}
+	}
+
 	return parsedRegionalServiceQuotas, nil
 }
 
+// getRequiredServiceQuotas parses the service quotas every new account requests from the configmap, e.g.
+//
+//	required-service-quotas: |
+//	  L-1216C47A: '750'
+//	  L-0263D0A3: '6'
+func getRequiredServiceQuotas(cm *corev1.ConfigMap) (awsv1alpha1.AccountServiceQuota, error) {
+	requiredString, found := cm.Data[awsv1alpha1.RequiredServiceQuotasConfigMapKey]
+	if !found || strings.TrimSpace(requiredString) == "" {
+		return nil, nil
+	}
+
+	data := make(map[string]string)
+	err := yaml.Unmarshal([]byte(requiredString), &data)
+	if err != nil {
+		return nil, err
+	}
+
+	requiredServiceQuotas := make(awsv1alpha1.AccountServiceQuota)
+	for quotaCode, quotaValue := range data {
+		qv, err := strconv.Atoi(quotaValue)
+		if err != nil || qv <= 0 {
+			return nil, fmt.Errorf("invalid value %q for required service quota %s", quotaValue, quotaCode)
+		}
+		requiredServiceQuotas[awsv1alpha1.SupportedServiceQuotas(quotaCode)] = &awsv1alpha1.ServiceQuotaStatus{
+			Value: qv,
+		}
+	}
+	return requiredServiceQuotas, nil
+}
+
+// IAMUserPolicies are the policies attached to the osdManagedAdmin IAM user of the accounts in an AccountPool
+type IAMUserPolicies struct {
+	ManagedPolicyARNs []string          `yaml:"managedPolicyArns,omitempty"`
+	InlinePolicies    map[string]string `yaml:"inlinePolicies,omitempty"`
+}
+
+// GetIAMUserPoliciesFromAccountPool retrieves the account pool's IAM user policies from ConfigMap. An empty
+// accountPoolName selects the default pool. It returns nil if the pool doesn't configure any IAM user policies.
+func GetIAMUserPoliciesFromAccountPool(reqLogger logr.Logger, accountPoolName string, client client.Client) (*IAMUserPolicies, error) {
+	cm, err := GetOperatorConfigMap(client)
+	if err != nil {
+		reqLogger.Error(err, "failed retrieving configmap")
+		return nil, err
+	}
+
+	accountpoolString, found := cm.Data["accountpool"]
+	if !found {
+		return nil, nil
+	}
+
+	type AccountPoolConfig struct {
+		IsDefault       bool             `yaml:"default,omitempty"`
+		IAMUserPolicies *IAMUserPolicies `yaml:"iamUserPolicies,omitempty"`
+	}
+
+	data := make(map[string]AccountPoolConfig)
+	err = yaml.Unmarshal([]byte(accountpoolString), &data)
+	if err != nil {
+		reqLogger.Error(err, "Failed to unmarshal yaml")
+		return nil, err
+	}
+
+	if accountPoolName == "" {
+		for _, poolData := range data {
+			if poolData.IsDefault {
+				return poolData.IAMUserPolicies, nil
+			}
+		}
+		return nil, nil
+	}
+
+	return data[accountPoolName].IAMUserPolicies, nil
+}
+
+// Region initialization modes of an AccountPool
+const (
+	// RegionInitModeInstance initializes the regions of new accounts by launching and terminating an instance
+	// in each of them
+	RegionInitModeInstance = "instance"
+	// RegionInitModeAPICheck verifies the regions of new accounts through the EC2 API, without launching
+	// instances
+	RegionInitModeAPICheck = "api-check"
+)
+
+// GetRegionInitModeFromAccountPool retrieves how the regions of the account pool's new accounts are
+// initialized from ConfigMap. An empty accountPoolName selects the default pool. It returns
+// RegionInitModeInstance unless the pool configures another valid mode.
+func GetRegionInitModeFromAccountPool(reqLogger logr.Logger, accountPoolName string, client client.Client) string {
+	cm, err := GetOperatorConfigMap(client)
+	if err != nil {
+		reqLogger.Error(err, "failed retrieving configmap, using the instance region init mode")
+		return RegionInitModeInstance
+	}
+
+	accountpoolString, found := cm.Data["accountpool"]
+	if !found {
+		return RegionInitModeInstance
+	}
+
+	type AccountPoolConfig struct {
+		IsDefault      bool   `yaml:"default,omitempty"`
+		RegionInitMode string `yaml:"regionInitMode,omitempty"`
+	}
+
+	data := make(map[string]AccountPoolConfig)
+	err = yaml.Unmarshal([]byte(accountpoolString), &data)
+	if err != nil {
+		reqLogger.Error(err, "Failed to unmarshal yaml, using the instance region init mode")
+		return RegionInitModeInstance
+	}
+
+	var mode string
+	if accountPoolName == "" {
+		for _, poolData := range data {
+			if poolData.IsDefault {
+				mode = poolData.RegionInitMode
+			}
+		}
+	} else {
+		mode = data[accountPoolName].RegionInitMode
+	}
+
+	switch mode {
+	case RegionInitModeAPICheck:
+		return RegionInitModeAPICheck
+	case "", RegionInitModeInstance:
+		return RegionInitModeInstance
+	}
+	reqLogger.Info("Unknown region init mode for accountpool, using the instance region init mode", "accountPool", accountPoolName, "regionInitMode", mode)
+	return RegionInitModeInstance
+}
+
+// GetHibernateAfterFromAccountPool returns how long accounts of the accountpool stay Ready and unclaimed
+// before being hibernated, from ConfigMap. An empty accountPoolName selects the default pool. It returns
+// 0, which disables hibernation, unless the pool configures a valid positive duration.
+func GetHibernateAfterFromAccountPool(reqLogger logr.Logger, accountPoolName string, client client.Client) time.Duration {
+	cm, err := GetOperatorConfigMap(client)
+	if err != nil {
+		reqLogger.Error(err, "failed retrieving configmap, not hibernating accounts")
+		return 0
+	}
+
+	accountpoolString, found := cm.Data["accountpool"]
+	if !found {
+		return 0
+	}
+
+	type AccountPoolConfig struct {
+		IsDefault      bool   `yaml:"default,omitempty"`
+		HibernateAfter string `yaml:"hibernateAfter,omitempty"`
+	}
+
+	data := make(map[string]AccountPoolConfig)
+	err = yaml.Unmarshal([]byte(accountpoolString), &data)
+	if err != nil {
+		reqLogger.Error(err, "Failed to unmarshal yaml, not hibernating accounts")
+		return 0
+	}
+
+	var value string
+	if accountPoolName == "" {
+		for _, poolData := range data {
+			if poolData.IsDefault {
+				value = poolData.HibernateAfter
+			}
+		}
+	} else {
+		value = data[accountPoolName].HibernateAfter
+	}
+	if value == "" {
+		return 0
+	}
+
+	hibernateAfter, err := time.ParseDuration(value)
+	if err != nil || hibernateAfter < 0 {
+		reqLogger.Error(err, "Invalid hibernateAfter for accountpool, not hibernating accounts", "accountPool", accountPoolName, "hibernateAfter", value)
+		return 0
+	}
+	return hibernateAfter
+}
+
+// GetKeepDefaultVPCFromAccountPool returns whether the default VPCs of the accountpool's accounts are kept,
+// from ConfigMap. An empty accountPoolName selects the default pool. It returns false, which deletes them,
+// unless the pool opts out with keepDefaultVpc. The VPCs are kept when the configmap can't be read.
+func GetKeepDefaultVPCFromAccountPool(reqLogger logr.Logger, accountPoolName string, client client.Client) bool {
+	cm, err := GetOperatorConfigMap(client)
+	if err != nil {
+		reqLogger.Error(err, "failed retrieving configmap, keeping the default VPCs")
+		return true
+	}
+
+	accountpoolString, found := cm.Data["accountpool"]
+	if !found {
+		return false
+	}
+
+	type AccountPoolConfig struct {
+		IsDefault      bool `yaml:"default,omitempty"`
+		KeepDefaultVPC bool `yaml:"keepDefaultVpc,omitempty"`
+	}
+
+	data := make(map[string]AccountPoolConfig)
+	err = yaml.Unmarshal([]byte(accountpoolString), &data)
+	if err != nil {
+		reqLogger.Error(err, "Failed to unmarshal yaml, keeping the default VPCs")
+		return true
+	}
+
+	if accountPoolName == "" {
+		for _, poolData := range data {
+			if poolData.IsDefault {
+				return poolData.KeepDefaultVPC
+			}
+		}
+		return false
+	}
+	return data[accountPoolName].KeepDefaultVPC
+}
+
 // MarshalIAMPolicy converts a role CR into a JSON policy that is acceptable to AWS
 func MarshalIAMPolicy(role awsv1alpha1.AWSFederatedRole) (string, error) {
+	return MarshalCustomPolicy(role.Spec.AWSCustomPolicy)
+}
+
+// MarshalCustomPolicy marshals the statements of a custom policy into an AWS policy document
+func MarshalCustomPolicy(policy awsv1alpha1.AWSCustomPolicy) (string, error) {
 	statements := []AwsStatement{}
 
-	for _, statement := range role.Spec.AWSCustomPolicy.Statements {
+	for _, statement := range policy.Statements {
 		statements = append(statements, AwsStatement(statement))
 	}
 
+	return marshalPolicyStatements(statements)
+}
+
+// marshalPolicyStatements marshals the statements into an AWS policy document
+func marshalPolicyStatements(statements []AwsStatement) (string, error) {
 	// Create a aws policydoc formated struct
 	policyDoc := AwsPolicy{
 		Version:   "2012-10-17",
@@ -187,6 +443,59 @@ func MarshalIAMPolicy(role awsv1alpha1.AWSFederatedRole) (string, error) {
 	return string(jsonPolicyDoc), nil
 }
 
+// SplitIAMPolicy marshals the custom policy of the role into as few policy documents as possible, none of them
+// larger than MaxManagedPolicySize. A custom policy that fits in a single customer managed policy is returned
+// as marshalled by MarshalIAMPolicy.
+func SplitIAMPolicy(role awsv1alpha1.AWSFederatedRole) ([]string, error) {
+	jsonPolicyDoc, err := MarshalIAMPolicy(role)
+	if err != nil {
+		return nil, err
+	}
+	if len(jsonPolicyDoc) <= MaxManagedPolicySize {
+		return []string{jsonPolicyDoc}, nil
+	}
+
+	documents := []string{}
+	part := []AwsStatement{}
+	partDoc := ""
+	for _, statement := range role.Spec.AWSCustomPolicy.Statements {
+		candidateDoc, err := marshalPolicyStatements(append(part, AwsStatement(statement)))
+		if err != nil {
+			return nil, err
+		}
+		if len(candidateDoc) <= MaxManagedPolicySize {
+			part = append(part, AwsStatement(statement))
+			partDoc = candidateDoc
+			continue
+		}
+
+		if len(part) == 0 {
+			return nil, fmt.Errorf("%w: a statement of policy %s exceeds %d characters", ErrPolicyStatementTooLarge, role.Spec.AWSCustomPolicy.Name, MaxManagedPolicySize)
+		}
+		documents = append(documents, partDoc)
+
+		// Start the next part with the statement that didn't fit
+		part = []AwsStatement{AwsStatement(statement)}
+		partDoc, err = marshalPolicyStatements(part)
+		if err != nil {
+			return nil, err
+		}
+		if len(partDoc) > MaxManagedPolicySize {
+			return nil, fmt.Errorf("%w: a statement of policy %s exceeds %d characters", ErrPolicyStatementTooLarge, role.Spec.AWSCustomPolicy.Name, MaxManagedPolicySize)
+		}
+	}
+	return append(documents, partDoc), nil
+}
+
+// CustomPolicyPartName returns the name of a part of a split custom policy. The first part keeps the name
+// of the custom policy so policies that fit in a single part are named as before.
+func CustomPolicyPartName(policyName string, index int) string {
+	if index == 0 {
+		return policyName
+	}
+	return fmt.Sprintf("%s-%d", policyName, index+1)
+}
+
 // AddFinalizer adds a finalizer to an object
 func AddFinalizer(object metav1.Object, finalizer string) {
 	finalizers := sets.NewString(object.GetFinalizers()...)
@@ -282,6 +591,19 @@ func GetOperatorConfigMap(kubeClient client.Client) (*corev1.ConfigMap, error) {
 	return configMap, err
 }
 
+// GetPermissionsBoundaryARN returns the permissions boundary policy ARN configured in the operator configmap,
+// or an empty string if there is none
+func GetPermissionsBoundaryARN(kubeClient client.Client) (string, error) {
+	configMap, err := GetOperatorConfigMap(kubeClient)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return configMap.Data[awsv1alpha1.PermissionsBoundaryConfigMapKey], nil
+}
+
 func GetEnvironmentBool(key string, fallback bool) bool {
 	value := os.Getenv(key)
 	cast, err := strconv.ParseBool(value)
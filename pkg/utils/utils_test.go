@@ -2,9 +2,11 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
@@ -198,6 +200,75 @@ func TestAddingConditionsToStatements(t *testing.T) {
 	}
 }
 
+func TestSplitIAMPolicy(t *testing.T) {
+	newStatement := func(i int, actions int) awsv1alpha1.StatementEntry {
+		statement := awsv1alpha1.StatementEntry{Effect: "Allow", Resource: []string{"*"}}
+		for j := 0; j < actions; j++ {
+			statement.Action = append(statement.Action, fmt.Sprintf("service%d:Action%d", i, j))
+		}
+		return statement
+	}
+	newStatements := func(count int, actions int) []awsv1alpha1.StatementEntry {
+		statements := []awsv1alpha1.StatementEntry{}
+		for i := 0; i < count; i++ {
+			statements = append(statements, newStatement(i, actions))
+		}
+		return statements
+	}
+
+	tables := []struct {
+		name        string
+		statements  []awsv1alpha1.StatementEntry
+		parts       int
+		expectedErr error
+	}{
+		{"small policy", newStatements(2, 1), 1, nil},
+		{"large policy", newStatements(30, 20), 3, nil},
+		{"statement too large", newStatements(1, 500), 0, ErrPolicyStatementTooLarge},
+	}
+
+	for _, table := range tables {
+		role := createRoleMock(table.statements)
+		documents, err := SplitIAMPolicy(role)
+		if !errors.Is(err, table.expectedErr) {
+			t.Errorf("%s: unexpected error %v, expected %v", table.name, err, table.expectedErr)
+		}
+		if len(documents) != table.parts {
+			t.Errorf("%s: expected %d parts, got %d", table.name, table.parts, len(documents))
+		}
+
+		statements := 0
+		for _, document := range documents {
+			if len(document) > MaxManagedPolicySize {
+				t.Errorf("%s: part of %d characters exceeds the limit", table.name, len(document))
+			}
+			var policy AwsPolicy
+			if err := json.Unmarshal([]byte(document), &policy); err != nil {
+				t.Errorf("%s: there was an error unmarshalling the IAM Policy. %s", table.name, err)
+			}
+			statements += len(policy.Statement)
+		}
+		if err == nil && statements != len(table.statements) {
+			t.Errorf("%s: expected %d statements across the parts, got %d", table.name, len(table.statements), statements)
+		}
+	}
+
+	smallPolicy, _ := MarshalIAMPolicy(createRoleMock(newStatements(2, 1)))
+	documents, _ := SplitIAMPolicy(createRoleMock(newStatements(2, 1)))
+	if documents[0] != smallPolicy {
+		t.Errorf("Unexpected Result.  Expected %s got %s", smallPolicy, documents[0])
+	}
+}
+
+func TestCustomPolicyPartName(t *testing.T) {
+	if name := CustomPolicyPartName("MyPolicy", 0); name != "MyPolicy" {
+		t.Errorf("Unexpected Result.  Expected MyPolicy got %s", name)
+	}
+	if name := CustomPolicyPartName("MyPolicy", 1); name != "MyPolicy-2" {
+		t.Errorf("Unexpected Result.  Expected MyPolicy-2 got %s", name)
+	}
+}
+
 func TestContains(t *testing.T) {
 	tables := []struct {
 		list   []string
@@ -276,6 +347,17 @@ func TestGetControllerMaxReconcilesFromCM(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "Tests value not positive",
+			expectedErr: fmt.Errorf("MaxConcurrentReconciles.test-controller must be positive, got 0"),
+			expectedVal: 0,
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: validObjectMeta,
+				Data: map[string]string{
+					"MaxConcurrentReconciles.test-controller": "0",
+				},
+			},
+		},
 		{
 			name:        "Tests valid value returned",
 			expectedErr: nil,
@@ -311,6 +393,24 @@ func TestGetControllerMaxReconcilesFromCM(t *testing.T) {
 	}
 }
 
+func TestOverrideControllerMaxReconciles(t *testing.T) {
+	err := OverrideControllerMaxReconciles("account=10, accountclaim=4")
+	if err != nil {
+		t.Errorf("Expected no error but got %s", err.Error())
+	}
+	for controller, expected := range map[string]int{"account": 10, "accountclaim": 4} {
+		if val, _ := GetControllerMaxReconciles(controller); val != expected {
+			t.Errorf("Expected %d max reconciles for %s but got %d", expected, controller, val)
+		}
+	}
+
+	for _, overrides := range []string{"account", "account=0", "account=ten", "unknown=2"} {
+		if OverrideControllerMaxReconciles(overrides) == nil {
+			t.Errorf("Expected an error for %q", overrides)
+		}
+	}
+}
+
 func TestJoinLabelMaps(t *testing.T) {
 	tests := []struct {
 		name string
@@ -455,6 +555,141 @@ fm-accountpool:
 			Expect(quotas).ToNot(BeEmpty())
 			Expect(quotas).To(HaveKey("default"))
 		})
+		It("Should add the required servicequotas to the default region of every pool", func() {
+			configMap.Data["accountpool"] = `hives02ue1:
+  default: true
+fm-accountpool:
+  servicequotas:
+    default:
+      L-1216C47A: '2500'
+`
+			configMap.Data[awsv1alpha1.RequiredServiceQuotasConfigMapKey] = `L-1216C47A: '750'
+L-0263D0A3: '6'
+`
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{configMap}...).Build()
+			quotas, err := GetServiceQuotasFromAccountPool(nullLogger, "fm-accountpool", client)
+			Expect(err).To(BeNil())
+			Expect(quotas["default"][awsv1alpha1.RunningStandardInstances].Value).To(Equal(2500))
+			Expect(quotas["default"][awsv1alpha1.EC2VPCElasticIPsQuotaCode].Value).To(Equal(6))
+
+			quotas, err = GetServiceQuotasFromAccountPool(nullLogger, "hives02ue1", client)
+			Expect(err).To(BeNil())
+			Expect(quotas["default"][awsv1alpha1.RunningStandardInstances].Value).To(Equal(750))
+			Expect(quotas["default"][awsv1alpha1.EC2VPCElasticIPsQuotaCode].Value).To(Equal(6))
+		})
+		It("Should return an Error when a required servicequota isn't a number", func() {
+			configMap.Data["accountpool"] = `hives02ue1:
+  default: true
+`
+			configMap.Data[awsv1alpha1.RequiredServiceQuotasConfigMapKey] = `L-1216C47A: lots`
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{configMap}...).Build()
+			_, err := GetServiceQuotasFromAccountPool(nullLogger, "hives02ue1", client)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("GetIAMUserPoliciesFromAccountPool", func() {
+		BeforeEach(func() {
+			configMap.Data["accountpool"] = `hives02ue1:
+  default: true
+  iamUserPolicies:
+    managedPolicyArns:
+    - arn:aws:iam::aws:policy/AdministratorAccess
+fm-accountpool:
+  iamUserPolicies:
+    managedPolicyArns:
+    - arn:aws:iam::aws:policy/PowerUserAccess
+    inlinePolicies:
+      osd-iam: '{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["iam:*"],"Resource":"*"}]}'
+no-policies-pool: {}
+`
+		})
+		It("Should return the IAM user policies of the pool", func() {
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{configMap}...).Build()
+			policies, err := GetIAMUserPoliciesFromAccountPool(nullLogger, "fm-accountpool", client)
+			Expect(err).To(BeNil())
+			Expect(policies.ManagedPolicyARNs).To(Equal([]string{"arn:aws:iam::aws:policy/PowerUserAccess"}))
+			Expect(policies.InlinePolicies).To(HaveKey("osd-iam"))
+		})
+		It("Should return the IAM user policies of the default pool for accounts without a pool", func() {
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{configMap}...).Build()
+			policies, err := GetIAMUserPoliciesFromAccountPool(nullLogger, "", client)
+			Expect(err).To(BeNil())
+			Expect(policies.ManagedPolicyARNs).To(Equal([]string{"arn:aws:iam::aws:policy/AdministratorAccess"}))
+		})
+		It("Should return nil when the pool doesn't configure IAM user policies", func() {
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{configMap}...).Build()
+			policies, err := GetIAMUserPoliciesFromAccountPool(nullLogger, "no-policies-pool", client)
+			Expect(err).To(BeNil())
+			Expect(policies).To(BeNil())
+		})
+		It("Should return nil when there is no accountpool key in the configmap", func() {
+			delete(configMap.Data, "accountpool")
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{configMap}...).Build()
+			policies, err := GetIAMUserPoliciesFromAccountPool(nullLogger, "fm-accountpool", client)
+			Expect(err).To(BeNil())
+			Expect(policies).To(BeNil())
+		})
+	})
+
+	Context("GetRegionInitModeFromAccountPool", func() {
+		BeforeEach(func() {
+			configMap.Data["accountpool"] = `hives02ue1:
+  default: true
+fast-pool:
+  regionInitMode: api-check
+typo-pool:
+  regionInitMode: apicheck
+`
+		})
+		It("Should return the region init mode of the pool", func() {
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{configMap}...).Build()
+			Expect(GetRegionInitModeFromAccountPool(nullLogger, "fast-pool", client)).To(Equal(RegionInitModeAPICheck))
+		})
+		It("Should default to the instance mode", func() {
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{configMap}...).Build()
+			Expect(GetRegionInitModeFromAccountPool(nullLogger, "", client)).To(Equal(RegionInitModeInstance))
+			Expect(GetRegionInitModeFromAccountPool(nullLogger, "typo-pool", client)).To(Equal(RegionInitModeInstance))
+		})
+	})
+
+	Context("GetHibernateAfterFromAccountPool", func() {
+		BeforeEach(func() {
+			configMap.Data["accountpool"] = `hives02ue1:
+  default: true
+  hibernateAfter: 72h
+idle-pool:
+  hibernateAfter: 24h
+typo-pool:
+  hibernateAfter: 1day
+`
+		})
+		It("Should return the hibernation delay of the pool", func() {
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{configMap}...).Build()
+			Expect(GetHibernateAfterFromAccountPool(nullLogger, "idle-pool", client)).To(Equal(24 * time.Hour))
+			Expect(GetHibernateAfterFromAccountPool(nullLogger, "", client)).To(Equal(72 * time.Hour))
+		})
+		It("Should not hibernate accounts of pools without a valid delay", func() {
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{configMap}...).Build()
+			Expect(GetHibernateAfterFromAccountPool(nullLogger, "typo-pool", client)).To(BeZero())
+			Expect(GetHibernateAfterFromAccountPool(nullLogger, "unknown-pool", client)).To(BeZero())
+		})
+	})
+
+	Context("IsMaintenanceMode", func() {
+		It("Should be enabled by the maintenance-mode key", func() {
+			configMap.Data[awsv1alpha1.MaintenanceModeConfigMapKey] = "true"
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{configMap}...).Build()
+			Expect(IsMaintenanceMode(client)).To(BeTrue())
+		})
+		It("Should be disabled by default", func() {
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects([]runtime.Object{configMap}...).Build()
+			Expect(IsMaintenanceMode(client)).To(BeFalse())
+		})
+		It("Should be disabled when the configmap is missing", func() {
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			Expect(IsMaintenanceMode(client)).To(BeFalse())
+		})
 	})
 
 })
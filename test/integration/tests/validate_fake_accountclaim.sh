@@ -18,6 +18,12 @@ if [[ $(jq -r '.spec.accountLink' <<< "$ac") != "" ]]; then
   exit 1
 fi
 
+# validate accountclaim went through the claim lifecycle
+if [[ $(jq -r '.status.state' <<< "$ac") != "Ready" ]]; then
+  echo "Fake accountclaim is not Ready."
+  exit 1
+fi
+
 # get secrets
 if [[ 1 -ne $(oc get secrets -n "$FAKE_NAMESPACE_NAME" aws -o name | wc -l) ]]; then
   echo "Secret ${FAKE_NAMESPACE_NAME}/secret/aws does not exist."
@@ -0,0 +1,122 @@
+//go:build localstack
+
+package localstack
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/config"
+	"github.com/openshift/aws-account-operator/controllers/account"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/testutils"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+const (
+	timeout  = 2 * time.Minute
+	interval = time.Second
+)
+
+// payerClient returns a client of the payer account, i.e. of the simulated organization
+func payerClient() awsclient.Client {
+	awsClient, err := (&awsclient.Builder{}).GetClient("", kubeClient, awsclient.NewAwsClientInput{
+		SecretName: utils.AwsSecretName,
+		NameSpace:  awsv1alpha1.AccountCrNamespace,
+		AwsRegion:  config.GetDefaultRegion(),
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return awsClient
+}
+
+// createReadyAccount creates an account in the simulated organization, and a Ready Account CR with its IAM secret
+func createReadyAccount(name string) *awsv1alpha1.Account {
+	status, err := account.CreateAccount(testutils.NewTestLogger().Logger(), payerClient(), name, name+"@example.com")
+	Expect(err).NotTo(HaveOccurred())
+
+	Expect(kubeClient.Create(context.TODO(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-secret", Namespace: awsv1alpha1.AccountCrNamespace},
+		Data: map[string][]byte{
+			"aws_access_key_id":     []byte("test"),
+			"aws_secret_access_key": []byte("test"),
+		},
+	})).To(Succeed())
+	acct := &awsv1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: awsv1alpha1.AccountCrNamespace, Finalizers: []string{awsv1alpha1.AccountFinalizer}},
+		Spec: awsv1alpha1.AccountSpec{
+			AwsAccountID:  aws.StringValue(status.CreateAccountStatus.AccountId),
+			IAMUserSecret: name + "-secret",
+			AccountPool:   "default",
+		},
+	}
+	Expect(kubeClient.Create(context.TODO(), acct)).To(Succeed())
+	acct.Status.State = string(awsv1alpha1.AccountReady)
+	acct.Status.Conditions = []awsv1alpha1.AccountCondition{{
+		Type:               awsv1alpha1.AccountReady,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+	}}
+	Expect(kubeClient.Status().Update(context.TODO(), acct)).To(Succeed())
+	return acct
+}
+
+var _ = Describe("Account creation", func() {
+	It("Creates the account in the simulated organization", func() {
+		acct := account.GenerateAccountCR(awsv1alpha1.AccountCrNamespace)
+		acct.Spec.AccountPool = "default"
+		Expect(kubeClient.Create(context.TODO(), acct)).To(Succeed())
+
+		Eventually(func() string {
+			Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(acct), acct)).To(Succeed())
+			return acct.Spec.AwsAccountID
+		}, timeout, interval).ShouldNot(BeEmpty())
+
+		accounts, err := payerClient().ListAccounts(&organizations.ListAccountsInput{})
+		Expect(err).NotTo(HaveOccurred())
+		var ids []string
+		for _, a := range accounts.Accounts {
+			ids = append(ids, aws.StringValue(a.Id))
+		}
+		Expect(ids).To(ContainElement(acct.Spec.AwsAccountID))
+	})
+})
+
+var _ = Describe("Claim, reuse and cleanup", func() {
+	It("Binds a Ready account to a claim and puts it back in the pool once the claim is deleted", func() {
+		acct := createReadyAccount("osd-creds-mgmt-localstack")
+		Expect(kubeClient.Create(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "claim-namespace"}})).To(Succeed())
+		claim := &awsv1alpha1.AccountClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "claim-namespace"},
+			Spec: awsv1alpha1.AccountClaimSpec{
+				LegalEntity:         awsv1alpha1.LegalEntity{Name: "localstack", ID: "localstack"},
+				AwsCredentialSecret: awsv1alpha1.SecretRef{Name: "aws", Namespace: "claim-namespace"},
+				Aws:                 awsv1alpha1.Aws{Regions: []awsv1alpha1.AwsRegions{{Name: config.GetDefaultRegion()}}},
+			},
+		}
+		Expect(kubeClient.Create(context.TODO(), claim)).To(Succeed())
+
+		Eventually(func() awsv1alpha1.ClaimStatus {
+			Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(claim), claim)).To(Succeed())
+			return claim.Status.State
+		}, timeout, interval).Should(Equal(awsv1alpha1.ClaimStatusReady))
+		Expect(claim.Spec.AccountLink).To(Equal(acct.Name))
+		secret := &corev1.Secret{}
+		Expect(kubeClient.Get(context.TODO(), client.ObjectKey{Name: "aws", Namespace: "claim-namespace"}, secret)).To(Succeed())
+
+		// The claim is deleted, the account is cleaned up in LocalStack and reused
+		Expect(kubeClient.Delete(context.TODO(), claim)).To(Succeed())
+		Eventually(func() bool {
+			Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(acct), acct)).To(Succeed())
+			return acct.Status.Reused && acct.Spec.ClaimLink == "" && acct.Status.State == string(awsv1alpha1.AccountReady)
+		}, timeout, interval).Should(BeTrue())
+	})
+})
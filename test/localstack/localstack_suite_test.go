@@ -0,0 +1,115 @@
+//go:build localstack
+
+// Package localstack runs the controllers of the operator against LocalStack and an envtest API server, to test the
+// claim, reuse and cleanup flows end to end without touching AWS. Run it with `make test-localstack`.
+package localstack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	apis "github.com/openshift/aws-account-operator/api"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/openshift/aws-account-operator/controllers/account"
+	"github.com/openshift/aws-account-operator/controllers/accountclaim"
+	"github.com/openshift/aws-account-operator/pkg/awsclient"
+	"github.com/openshift/aws-account-operator/pkg/localmetrics"
+	"github.com/openshift/aws-account-operator/pkg/totalaccountwatcher"
+	"github.com/openshift/aws-account-operator/pkg/utils"
+)
+
+const defaultLocalStackEndpoint = "http://localhost:4566"
+
+var (
+	testEnv    *envtest.Environment
+	kubeClient client.Client
+	cancel     context.CancelFunc
+)
+
+func TestLocalStack(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "LocalStack Suite")
+}
+
+var _ = BeforeSuite(func() {
+	ctrl.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
+
+	endpoint := os.Getenv(utils.LocalStackEndpointEnvVar)
+	if endpoint == "" {
+		endpoint = defaultLocalStackEndpoint
+	}
+	utils.SetLocalStackEndpoint(endpoint)
+	utils.DetectDevMode = utils.DevModeLocal
+	localmetrics.Collector = localmetrics.NewMetricsCollector(nil)
+
+	testEnv = &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "deploy", "crds")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := testEnv.Start()
+	Expect(err).NotTo(HaveOccurred())
+
+	Expect(apis.AddToScheme(scheme.Scheme)).To(Succeed())
+	kubeClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	Expect(err).NotTo(HaveOccurred())
+	createOperatorResources()
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{Scheme: scheme.Scheme, MetricsBindAddress: "0"})
+	Expect(err).NotTo(HaveOccurred())
+	Expect((&account.AccountReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}).SetupWithManager(mgr)).To(Succeed())
+	Expect((&accountclaim.AccountClaimReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}).SetupWithManager(mgr)).To(Succeed())
+	Expect((&accountclaim.AccountCleanupReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}).SetupWithManager(mgr)).To(Succeed())
+
+	var ctx context.Context
+	ctx, cancel = context.WithCancel(context.Background())
+	go totalaccountwatcher.TotalAccountWatcher.Start(ctrl.Log, ctx, kubeClient, time.Second)
+	go func() {
+		defer GinkgoRecover()
+		Expect(mgr.Start(ctx)).To(Succeed())
+	}()
+})
+
+var _ = AfterSuite(func() {
+	if cancel != nil {
+		cancel()
+	}
+	if testEnv != nil {
+		Expect(testEnv.Stop()).To(Succeed())
+	}
+})
+
+// createOperatorResources creates the namespace, configmap and credentials the operator expects to find
+func createOperatorResources() {
+	Expect(kubeClient.Create(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.AccountCrNamespace}})).To(Succeed())
+	Expect(kubeClient.Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: awsv1alpha1.DefaultConfigMap, Namespace: awsv1alpha1.AccountCrNamespace},
+		Data: map[string]string{
+			"account-limit": "100",
+			"root":          awsclient.LocalStackRootID,
+			"base":          awsclient.LocalStackRootID,
+			"ami-owner":     "000000000000",
+			"accountpool":   "default:\n  default: true",
+		},
+	})).To(Succeed())
+	// LocalStack accepts any credentials
+	Expect(kubeClient.Create(context.TODO(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: utils.AwsSecretName, Namespace: awsv1alpha1.AccountCrNamespace},
+		Data: map[string][]byte{
+			"aws_access_key_id":     []byte("test"),
+			"aws_secret_access_key": []byte("test"),
+		},
+	})).To(Succeed())
+}